@@ -7,18 +7,30 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	trellislogs "github.com/wingedpig/trellis/internal/logs"
 )
 
 // Filter filters log entries based on the provided options.
 type Filter struct {
 	opts        FilterOptions
 	grepRegex   *regexp.Regexp
+	query       *trellislogs.Filter
 }
 
 // NewFilter creates a new Filter with the given options.
 func NewFilter(opts FilterOptions) (*Filter, error) {
 	f := &Filter{opts: opts}
 
+	// Parse filter expression if provided
+	if opts.Query != "" {
+		query, err := trellislogs.ParseFilter(opts.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		f.query = query
+	}
+
 	// Compile grep pattern if provided
 	if opts.GrepPattern != "" {
 		re, err := regexp.Compile(opts.GrepPattern)
@@ -56,9 +68,31 @@ func (f *Filter) Match(entry *LogEntry) bool {
 		return false
 	}
 
+	// Filter expression
+	if f.query != nil && !f.query.Match(toFilterEntry(entry)) {
+		return false
+	}
+
 	return true
 }
 
+// toFilterEntry converts an API log entry into the form the shared filter
+// expression matcher understands.
+func toFilterEntry(entry *LogEntry) trellislogs.LogEntry {
+	var level trellislogs.LogLevel
+	if entry.Level != "" {
+		level = trellislogs.NormalizeLevel(entry.Level)
+	}
+	return trellislogs.LogEntry{
+		Timestamp: entry.Timestamp,
+		Level:     level,
+		Message:   entry.Message,
+		Fields:    entry.Fields,
+		Raw:       entry.Raw,
+		Source:    entry.Source,
+	}
+}
+
 // matchLevel checks if the entry matches level filter criteria.
 func (f *Filter) matchLevel(entry *LogEntry) bool {
 	entryLevel := GetLevelFromEntry(entry)
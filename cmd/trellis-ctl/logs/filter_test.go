@@ -4,6 +4,7 @@
 package logs
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -568,3 +569,37 @@ func TestFilterEntriesContextOverlap(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterQueryExpression(t *testing.T) {
+	entries := []LogEntry{
+		{Level: "ERROR", Message: "db down", Fields: map[string]interface{}{"path": "/api"}},
+		{Level: "INFO", Message: "ok", Fields: map[string]interface{}{"path": "/health", "status": float64(503)}},
+		{Level: "INFO", Message: "slow", Fields: map[string]interface{}{"path": "/api", "status": float64(502), "user_id": "u1"}},
+		{Level: "WARN", Message: "retry"},
+	}
+
+	filtered, err := FilterEntries(entries, FilterOptions{
+		MinLevel: LevelUnset,
+		Query:    `(level:error OR status:>=500) AND -path:~"/health"`,
+	})
+	if err != nil {
+		t.Fatalf("FilterEntries error: %v", err)
+	}
+	if len(filtered) != 2 || filtered[0].Message != "db down" || filtered[1].Message != "slow" {
+		t.Errorf("FilterEntries returned %+v, want [db down, slow]", filtered)
+	}
+
+	filtered, err = FilterEntries(entries, FilterOptions{MinLevel: LevelUnset, Query: "has:user_id"})
+	if err != nil {
+		t.Fatalf("FilterEntries error: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Message != "slow" {
+		t.Errorf("has:user_id returned %+v, want [slow]", filtered)
+	}
+
+	if _, err := NewFilter(FilterOptions{Query: "(level:error"}); err == nil {
+		t.Error("NewFilter with unbalanced parenthesis should fail")
+	} else if !strings.Contains(err.Error(), "column 1") {
+		t.Errorf("error %q should report the column", err)
+	}
+}
@@ -9,10 +9,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
+
+	"github.com/wingedpig/trellis/pkg/client"
 )
 
 // Formatter formats log entries for output.
@@ -294,6 +298,95 @@ func FormatStats(w io.Writer, stats *LogStats, serviceName string) {
 	}
 }
 
+// histogramBarWidth is the width of the longest bar in FormatAggregate's
+// histogram.
+const histogramBarWidth = 40
+
+// FormatAggregate writes a server-side log aggregation: the value summary,
+// the group table, and the histogram as horizontal bars.
+func FormatAggregate(w io.Writer, stats *client.LogStats, name string) {
+	fmt.Fprintf(w, "Log aggregation for '%s': %d entries", name, stats.Total)
+	if stats.Total > 0 {
+		fmt.Fprintf(w, " (%s - %s)", stats.Oldest.Local().Format("2006-01-02 15:04:05"), stats.Newest.Local().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintln(w)
+
+	if stats.Values != nil {
+		fmt.Fprintf(w, "\n  %s: %s\n", stats.Field, formatValueStats(stats.Values))
+	}
+
+	if stats.GroupBy != "" {
+		fmt.Fprintln(w)
+		keyWidth := len(stats.GroupBy)
+		for _, g := range stats.Groups {
+			if len(g.Key) > keyWidth {
+				keyWidth = len(g.Key)
+			}
+		}
+		if keyWidth > 60 {
+			keyWidth = 60
+		}
+		fmt.Fprintf(w, "  %-*s %8s", keyWidth, strings.ToUpper(stats.GroupBy), "COUNT")
+		if stats.Field != "" {
+			fmt.Fprintf(w, " %10s %10s %10s %10s", "AVG", "P50", "P95", "P99")
+		}
+		fmt.Fprintln(w)
+		for _, g := range stats.Groups {
+			key := g.Key
+			if len(key) > keyWidth {
+				key = key[:keyWidth-3] + "..."
+			}
+			fmt.Fprintf(w, "  %-*s %8d", keyWidth, key, g.Count)
+			if g.Values != nil && g.Values.Count > 0 {
+				fmt.Fprintf(w, " %10s %10s %10s %10s", formatNumber(g.Values.Avg), formatNumber(g.Values.P50), formatNumber(g.Values.P95), formatNumber(g.Values.P99))
+			}
+			fmt.Fprintln(w)
+		}
+		if stats.OtherGroups > 0 {
+			fmt.Fprintf(w, "  (%d more groups)\n", stats.OtherGroups)
+		}
+		if stats.Ungrouped > 0 {
+			fmt.Fprintf(w, "  (%d entries without %s)\n", stats.Ungrouped, stats.GroupBy)
+		}
+	}
+
+	if len(stats.Histogram) > 0 {
+		fmt.Fprintf(w, "\n  Histogram (%s buckets):\n", stats.Bucket)
+		maxCount := 0
+		for _, b := range stats.Histogram {
+			if b.Count > maxCount {
+				maxCount = b.Count
+			}
+		}
+		for _, b := range stats.Histogram {
+			bar := 0
+			if maxCount > 0 {
+				bar = (b.Count*histogramBarWidth + maxCount - 1) / maxCount
+			}
+			fmt.Fprintf(w, "    %s %-*s %d", b.Start.Local().Format("01-02 15:04:05"), histogramBarWidth, strings.Repeat("#", bar), b.Count)
+			if errCount := b.Levels["error"] + b.Levels["fatal"]; errCount > 0 {
+				fmt.Fprintf(w, " (%d error)", errCount)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// formatValueStats renders a numeric summary on one line.
+func formatValueStats(v *client.LogValueStats) string {
+	if v.Count == 0 {
+		return "no numeric values"
+	}
+	return fmt.Sprintf("count %d  avg %s  min %s  max %s  p50 %s  p95 %s  p99 %s",
+		v.Count, formatNumber(v.Avg), formatNumber(v.Min), formatNumber(v.Max),
+		formatNumber(v.P50), formatNumber(v.P95), formatNumber(v.P99))
+}
+
+// formatNumber rounds a summary value to two decimals without trailing zeros.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
 		return fmt.Sprintf("%.0fs", d.Seconds())
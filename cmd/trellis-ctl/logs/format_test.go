@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/wingedpig/trellis/pkg/client"
 )
 
 func TestFormatterPlain(t *testing.T) {
@@ -365,3 +367,49 @@ func TestEmptyLevelDefaultsToInfo(t *testing.T) {
 		t.Errorf("expected empty level to default to INFO, got: %s", output)
 	}
 }
+
+func TestFormatAggregate(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	stats := &client.LogStats{
+		Total:   30,
+		Oldest:  base,
+		Newest:  base.Add(2 * time.Minute),
+		GroupBy: "path",
+		Field:   "duration",
+		Groups: []client.LogStatsGroup{
+			{Key: "/api/users", Count: 20, Values: &client.LogValueStats{Count: 20, Avg: 12.3456, P50: 10, P95: 40, P99: 55}},
+			{Key: "/api/orders", Count: 8, Values: &client.LogValueStats{Count: 8, Avg: 30, P50: 25, P95: 90, P99: 90}},
+		},
+		OtherGroups: 1,
+		Ungrouped:   2,
+		Values:      &client.LogValueStats{Count: 28, Avg: 17.5, Min: 1, Max: 90, P50: 12, P95: 60, P99: 90},
+		Bucket:      "1m0s",
+		Histogram: []client.LogHistogramBucket{
+			{Start: base, Count: 20, Levels: map[string]int{"info": 18, "error": 2}},
+			{Start: base.Add(time.Minute)},
+			{Start: base.Add(2 * time.Minute), Count: 10},
+		},
+	}
+
+	var buf bytes.Buffer
+	FormatAggregate(&buf, stats, "api")
+	output := buf.String()
+
+	expectations := []string{
+		"Log aggregation for 'api': 30 entries",
+		"duration: count 28  avg 17.5  min 1  max 90  p50 12  p95 60  p99 90",
+		"PATH",
+		"/api/users",
+		"12.35",
+		"(1 more groups)",
+		"(2 entries without path)",
+		"Histogram (1m0s buckets):",
+		strings.Repeat("#", histogramBarWidth) + " 20 (2 error)",
+		strings.Repeat("#", histogramBarWidth/2) + " ",
+	}
+	for _, exp := range expectations {
+		if !strings.Contains(output, exp) {
+			t.Errorf("output missing %q\nGot:\n%s", exp, output)
+		}
+	}
+}
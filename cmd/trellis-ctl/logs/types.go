@@ -61,6 +61,7 @@ type FilterOptions struct {
 	MinLevel     LogLevel          // Minimum level (for "level+" syntax), -1 if not set
 	GrepPattern  string            // Regex pattern to match in message
 	FieldFilters map[string]string // Field name -> value filters
+	Query        string            // Filter expression (same syntax as the log viewer filter box)
 	Before       int               // Number of lines to show before each match (-B)
 	After        int               // Number of lines to show after each match (-A)
 }
@@ -7,12 +7,16 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -21,6 +25,7 @@ import (
 
 	"github.com/wingedpig/trellis/cmd/trellis-ctl/logs"
 	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/otlp"
 	"github.com/wingedpig/trellis/pkg/client"
 )
 
@@ -135,6 +140,14 @@ func main() {
 		err = cmdNotify(args)
 	case "crash":
 		err = cmdCrash(args)
+	case "otlp":
+		err = cmdOTLP(args)
+	case "proxy":
+		err = cmdProxy(args)
+	case "config":
+		err = cmdConfig(args)
+	case "secret", "secrets":
+		err = cmdSecret(args)
 	case "version", "-v", "--version":
 		fmt.Printf("trellis-ctl %s\n", version)
 	case "help", "-h", "--help":
@@ -199,6 +212,8 @@ Commands:
   start <service>          Start a service
   stop <service>           Stop a service
   restart <service>        Restart a service
+    -w <worktree>          Address the services another worktree runs alongside
+                           the active one's (status, start, stop, restart, logs)
 
   logs <service> [options] Show logs for a service
     -n N                   Number of lines (default: 100)
@@ -211,6 +226,7 @@ Commands:
     -A N                   Show N lines after each grep match
     -C N                   Show N lines before and after each grep match
     -field <key=value>     Filter by field value (can repeat)
+    -filter <expr>         Filter expression, e.g. '(level:error OR status:>=500) -path:~"/health"'
     -json                  Output as JSON array
     -jsonl                 Output as JSON Lines
     -csv                   Output as CSV
@@ -222,21 +238,55 @@ Commands:
     -clear                 Clear log buffer
     -open                  Open in browser
     -url                   Print browser URL
+    -w <worktree>          A service of another worktree (see worktree start)
+
+  logs stats <service> [options]  Aggregate parsed log fields on the server
+    -viewer <name>         Aggregate a log viewer instead of a service
+    -group-by <field>      Count entries per field value (e.g., path, level)
+    -field <field>         Summarize a numeric field: avg, min, max, p50, p95, p99
+    -top N                 Number of groups to show (default: 10)
+    -bucket <dur|auto>     Add a histogram with this bucket width (e.g., 1m)
+    -filter <expr>         Only aggregate entries matching a filter expression
+    -since <duration>      Start time (e.g., 1h, 30m, 6:30am)
+    -until <duration>      End time (default: now)
+    -history               Scan rotated history files (requires -since)
+    -grep <pattern>        Pre-filter history lines by regex (with -history)
 
   workflow list            List all workflows
   workflow run <id>        Run a workflow (waits for completion)
   workflow status <id>     Get workflow status
   workflow cancel <id>     Cancel a running workflow (run ID or workflow ID)
+  workflow history <id> [options]  List a workflow's past runs
+    <run-id>               Show a past run with its output
+    -tests                 Pass/fail timeline of tests that failed
+    -flaky                 Tests that passed and failed on the same commit
+    -n N                   Number of runs (default: 20, 0 for all)
+    -w <worktree>          Only runs for a worktree
+  workflow artifacts <id> <run-id>  List the files kept from a run
+    <path>                 Download a file to the current directory
+    -o <file>              Download to <file> instead (- for stdout)
 
   worktree list            List all worktrees
   worktree activate <name> Activate a worktree
-
-  events [-n N]            Show recent events (default: 50)
+  worktree start <name>    Run a worktree's services alongside the active one's
+                           (requires worktree.concurrent)
+  worktree stop <name>     Stop the services a worktree runs alongside
+  worktree ports [name]    Show the ports allocated by {{.Worktree.Port "name"}}
+
+  events [options]         Show recent events
+    -n N                   Number of events (default: 50)
+    -type <pattern>        Filter by event type (repeatable, supports wildcards)
+    -worktree <name>       Filter by worktree
+    -since <duration>      Start time (e.g., 1h, 2d, 6:30am)
+    -until <duration>      End time
+    -grep <text>           Full-text search over type, worktree, and payload
+    -before <event-id>     Page back: events older than this event
 
   trace <id> <group> [options]  Run a distributed trace search
     -since <duration>      Start time (e.g., 1h, 30m, 6:30am)
     -until <duration>      End time (default: now)
     -name <name>           Report name (default: auto-generated)
+    -filter <expr>         Only keep entries matching a log filter expression
     -no-expand-by-id       Disable ID expansion (two-pass search)
 
   trace-report <name>      Get a saved trace report
@@ -253,6 +303,48 @@ Commands:
   crash delete <id>        Delete a crash by ID
   crash clear              Clear all crashes
 
+  otlp send [options]      Send a sample trace (spans and logs) to an OTLP receiver
+    -endpoint <url>        OTLP/HTTP endpoint (default: http://localhost:4318)
+    -service <name>        service.name resource attribute (default: trellis-ctl)
+
+  proxy requests [options] List requests captured by proxy listeners (newest first)
+    -listener <addr>       Only this listener (e.g., :443)
+    -method <method>       Only this HTTP method
+    -status <code>         Status code (404) or class (5xx)
+    -path <text>           Path and query contain text
+    -upstream <text>       Upstream host contains text
+    -slow <duration>       Only requests at least this slow (e.g., 500ms)
+    -limit <n>             Maximum requests (default: 50, 0 for all)
+  proxy show <id>          Show a captured request with headers and bodies
+  proxy replay <id> [options]  Replay a captured request against the current upstream
+    -X <method>            Replace the method
+    -url <path>            Replace the path and query
+    -H 'Name: value'       Set a header ('Name:' removes it; repeatable)
+    -d <body|@file>        Replace the body
+    -worktree <name>       Send to this worktree's upstreams (worktree-routing listeners)
+  proxy clear              Clear captured requests
+  proxy faults             List fault injection settings per route
+  proxy faults clear       Turn fault injection off on every route
+  proxy fault <listener> <route> [options]  Inject faults into a route (index from 'proxy faults')
+    -latency <duration>    Added latency (e.g., 200ms)
+    -latency-max <duration>  Pick latency uniformly up to this
+    -error-rate <pct>      Answer this percentage of requests with an error
+    -error-status <code>   Status for injected errors (default: 503)
+    -abort-rate <pct>      Close this percentage of connections without a response
+    -bandwidth <bytes>     Throttle responses to bytes per second
+    -ws-drop-rate <pct>    Drop this percentage of WebSocket connections
+    -ws-drop-after <duration>  When to drop them (default: 10s)
+    -off                   Turn faults off, keeping the settings
+
+  config show              List the files the running config was assembled from
+  config show -effective [prefix]  Show every setting and the file that set it
+                           (prefix limits output, e.g. services[api] or server)
+
+  secret list              List secrets by name and source (values are never shown)
+  secret set <name> [value|-]  Store a secret in the local encrypted store
+                           (reads the value from stdin if omitted or -)
+  secret rm <name>         Remove a secret from the local store
+
   version                  Show version
   help                     Show this help`)
 }
@@ -263,13 +355,61 @@ func printJSON(v interface{}) {
 	fmt.Println(string(out))
 }
 
+// splitWorktreeFlag removes -w/-worktree <name> from args, returning the
+// worktree whose services a command addresses ("" for the active one).
+func splitWorktreeFlag(args []string) (string, []string, error) {
+	var worktree string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-w" || arg == "-worktree":
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("%s requires a worktree name", arg)
+			}
+			i++
+			worktree = args[i]
+		case strings.HasPrefix(arg, "-w="):
+			worktree = strings.TrimPrefix(arg, "-w=")
+		case strings.HasPrefix(arg, "-worktree="):
+			worktree = strings.TrimPrefix(arg, "-worktree=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return worktree, rest, nil
+}
+
+// serviceClient returns the client for the services of worktree, or of the
+// active worktree if it's empty.
+func serviceClient(worktree string) *client.ServiceClient {
+	if worktree == "" {
+		return apiClient.Services
+	}
+	return apiClient.Services.Worktree(worktree)
+}
+
+// servicePageURL returns the browser URL of a service's logs.
+func servicePageURL(name, worktree string) string {
+	u := fmt.Sprintf("%s/terminal/service/%s", apiURL, name)
+	if worktree != "" {
+		u += "?worktree=" + url.QueryEscape(worktree)
+	}
+	return u
+}
+
 func cmdStatus(args []string) error {
 	ctx := context.Background()
+	worktree, args, err := splitWorktreeFlag(args)
+	if err != nil {
+		return err
+	}
+	services := serviceClient(worktree)
 
 	if len(args) > 0 {
 		// Specific service
 		name := args[0]
-		svc, err := apiClient.Services.Get(ctx, name)
+		svc, err := services.Get(ctx, name)
 		if err != nil {
 			return err
 		}
@@ -295,20 +435,20 @@ func cmdStatus(args []string) error {
 	}
 
 	// All services
-	services, err := apiClient.Services.List(ctx)
+	list, err := services.List(ctx)
 	if err != nil {
 		return err
 	}
 
 	if jsonOutput {
-		printJSON(services)
+		printJSON(list)
 		return nil
 	}
 
 	// Print as formatted table
 	fmt.Printf("%-20s %-10s %-8s %-10s %s\n", "SERVICE", "STATE", "PID", "RESTARTS", "ERROR")
 	fmt.Println(strings.Repeat("-", 70))
-	for _, svc := range services {
+	for _, svc := range list {
 		pid := "-"
 		if svc.Status.PID > 0 {
 			pid = strconv.Itoa(svc.Status.PID)
@@ -333,6 +473,7 @@ func cmdStatus(args []string) error {
 type logsConfig struct {
 	service      string
 	viewer       string
+	worktree     string // -w: a service of another worktree
 	lines        int
 	follow       bool
 	since        string
@@ -340,6 +481,7 @@ type logsConfig struct {
 	level        string
 	grep         string
 	field        []string
+	filter       string
 	outputFormat string
 	template     string
 	list         bool
@@ -383,6 +525,9 @@ func parseLogsArgs(args []string) (*logsConfig, error) {
 		case arg == "-field" && i+1 < len(args):
 			i++
 			cfg.field = append(cfg.field, args[i])
+		case arg == "-filter" && i+1 < len(args):
+			i++
+			cfg.filter = args[i]
 		case arg == "-format" && i+1 < len(args):
 			i++
 			cfg.template = args[i]
@@ -397,6 +542,9 @@ func parseLogsArgs(args []string) (*logsConfig, error) {
 		case arg == "-viewer" && i+1 < len(args):
 			i++
 			cfg.viewer = args[i]
+		case (arg == "-w" || arg == "-worktree") && i+1 < len(args):
+			i++
+			cfg.worktree = args[i]
 		case arg == "-list":
 			cfg.list = true
 		case arg == "-clear":
@@ -444,6 +592,10 @@ func parseLogsArgs(args []string) (*logsConfig, error) {
 }
 
 func cmdLogs(args []string) error {
+	if len(args) > 0 && args[0] == "stats" {
+		return cmdLogsStats(args[1:])
+	}
+
 	cfg, err := parseLogsArgs(args)
 	if err != nil {
 		return err
@@ -463,14 +615,18 @@ func cmdLogs(args []string) error {
 	isViewer := cfg.viewer != ""
 	if isViewer {
 		name = cfg.viewer
+		if cfg.worktree != "" {
+			return fmt.Errorf("-w is not supported for log viewers")
+		}
 	}
+	services := serviceClient(cfg.worktree)
 
 	// Handle --url
 	if cfg.showURL {
 		if isViewer {
 			fmt.Printf("%s/terminal/logviewer/%s\n", apiURL, name)
 		} else {
-			fmt.Printf("%s/terminal/service/%s\n", apiURL, name)
+			fmt.Println(servicePageURL(name, cfg.worktree))
 		}
 		return nil
 	}
@@ -481,7 +637,7 @@ func cmdLogs(args []string) error {
 		if isViewer {
 			url = fmt.Sprintf("%s/terminal/logviewer/%s", apiURL, name)
 		} else {
-			url = fmt.Sprintf("%s/terminal/service/%s", apiURL, name)
+			url = servicePageURL(name, cfg.worktree)
 		}
 		return openBrowser(url)
 	}
@@ -492,7 +648,7 @@ func cmdLogs(args []string) error {
 			return fmt.Errorf("--clear is not supported for log viewers")
 		}
 		ctx := context.Background()
-		err := apiClient.Services.ClearLogs(ctx, name)
+		err := services.ClearLogs(ctx, name)
 		if err != nil {
 			return err
 		}
@@ -547,6 +703,10 @@ func cmdLogs(args []string) error {
 		}
 	}
 
+	if cfg.filter != "" {
+		filterOpts.Query = cfg.filter
+	}
+
 	// Build output options
 	outputOpts := logs.OutputOptions{}
 	if cfg.template != "" {
@@ -567,7 +727,7 @@ func cmdLogs(args []string) error {
 		if outputOpts.Format == logs.FormatJSON || jsonOutput {
 			outputOpts.Format = logs.FormatJSONL
 		}
-		return cmdLogsFollow(name, isViewer, filterOpts, outputOpts)
+		return cmdLogsFollow(name, cfg.worktree, isViewer, filterOpts, outputOpts)
 	}
 
 	// Fetch logs
@@ -576,7 +736,7 @@ func cmdLogs(args []string) error {
 		// For log viewers, pass time range, grep pattern, and context to server
 		entries, err = fetchLogViewerEntries(name, cfg.lines, filterOpts.Since, filterOpts.Until, cfg.grep, cfg.before, cfg.after)
 	} else {
-		entries, err = fetchServiceLogs(name, cfg.lines)
+		entries, err = fetchServiceLogs(services, name, cfg.lines)
 	}
 	if err != nil {
 		return err
@@ -622,6 +782,95 @@ func cmdLogs(args []string) error {
 	return formatter.FormatEntries(filtered)
 }
 
+// cmdLogsStats runs a server-side aggregation over a log viewer, or over
+// a service's svc:<name> viewer.
+func cmdLogsStats(args []string) error {
+	var name string
+	opts := &client.LogStatsOptions{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-viewer" && i+1 < len(args):
+			i++
+			name = args[i]
+		case arg == "-group-by" && i+1 < len(args):
+			i++
+			opts.GroupBy = args[i]
+		case arg == "-field" && i+1 < len(args):
+			i++
+			opts.Field = args[i]
+		case arg == "-top" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid value for -top: %s", args[i])
+			}
+			opts.Top = n
+		case arg == "-bucket" && i+1 < len(args):
+			i++
+			opts.Bucket = args[i]
+		case arg == "-filter" && i+1 < len(args):
+			i++
+			opts.Filter = args[i]
+		case arg == "-grep" && i+1 < len(args):
+			i++
+			opts.Grep = args[i]
+		case arg == "-since" && i+1 < len(args):
+			i++
+			since, err := logs.ParseDuration(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid -since value: %w", err)
+			}
+			opts.Since = since
+		case arg == "-until" && i+1 < len(args):
+			i++
+			until, err := logs.ParseDuration(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid -until value: %w", err)
+			}
+			opts.Until = until
+		case arg == "-history":
+			opts.History = true
+		case !strings.HasPrefix(arg, "-"):
+			if name == "" {
+				name = "svc:" + arg
+			}
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	if name == "" {
+		return fmt.Errorf("usage: trellis-ctl logs stats <service> [options] or trellis-ctl logs stats -viewer <name> [options]")
+	}
+	if opts.History {
+		if opts.Since.IsZero() {
+			return fmt.Errorf("-history requires -since")
+		}
+		if opts.Until.IsZero() {
+			opts.Until = time.Now()
+		}
+	}
+	if opts.Grep != "" && !opts.History {
+		return fmt.Errorf("-grep is only supported with -history; use -filter for the live buffer")
+	}
+
+	ctx := context.Background()
+	stats, err := apiClient.Logs.Stats(ctx, name, opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(stats)
+		return nil
+	}
+
+	logs.FormatAggregate(os.Stdout, stats, strings.TrimPrefix(name, "svc:"))
+	return nil
+}
+
 func cmdLogsListViewers() error {
 	ctx := context.Background()
 	viewers, err := apiClient.Logs.List(ctx)
@@ -647,7 +896,7 @@ func cmdLogsListViewers() error {
 	return nil
 }
 
-func cmdLogsFollow(name string, isViewer bool, filterOpts logs.FilterOptions, outputOpts logs.OutputOptions) error {
+func cmdLogsFollow(name, worktree string, isViewer bool, filterOpts logs.FilterOptions, outputOpts logs.OutputOptions) error {
 	// Follow mode uses Server-Sent Events for real-time streaming
 	filter, err := logs.NewFilter(filterOpts)
 	if err != nil {
@@ -665,12 +914,15 @@ func cmdLogsFollow(name string, isViewer bool, filterOpts logs.FilterOptions, ou
 		endpoint = fmt.Sprintf("/api/v1/logs/%s/stream/sse", name)
 	} else {
 		endpoint = fmt.Sprintf("/api/v1/services/%s/logs/stream", name)
+		if worktree != "" {
+			endpoint += "?worktree=" + url.QueryEscape(worktree)
+		}
 	}
 
-	return streamSSE(endpoint, name, isViewer, filter, formatter)
+	return streamSSE(endpoint, name, serviceClient(worktree), isViewer, filter, formatter)
 }
 
-func streamSSE(endpoint, name string, isViewer bool, filter *logs.Filter, formatter *logs.Formatter) error {
+func streamSSE(endpoint, name string, services *client.ServiceClient, isViewer bool, filter *logs.Filter, formatter *logs.Formatter) error {
 	url := apiURL + endpoint
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -697,7 +949,7 @@ func streamSSE(endpoint, name string, isViewer bool, filter *logs.Filter, format
 	var parserCfg logs.ParserConfig
 	if !isViewer {
 		ctx := context.Background()
-		parserCfg = getServiceParserConfig(ctx, name)
+		parserCfg = getServiceParserConfig(ctx, services, name)
 	}
 
 	// Read SSE events
@@ -760,14 +1012,14 @@ func streamSSE(endpoint, name string, isViewer bool, filter *logs.Filter, format
 	}
 }
 
-func fetchServiceLogs(name string, lines int) ([]logs.LogEntry, error) {
+func fetchServiceLogs(services *client.ServiceClient, name string, lines int) ([]logs.LogEntry, error) {
 	ctx := context.Background()
 
 	// First, fetch service info to get parser config
-	parserCfg := getServiceParserConfig(ctx, name)
+	parserCfg := getServiceParserConfig(ctx, services, name)
 
 	// Fetch log lines
-	data, err := apiClient.Services.Logs(ctx, name, lines)
+	data, err := services.Logs(ctx, name, lines)
 	if err != nil {
 		return nil, err
 	}
@@ -799,8 +1051,8 @@ func fetchServiceLogs(name string, lines int) ([]logs.LogEntry, error) {
 }
 
 // getServiceParserConfig fetches the parser configuration for a service.
-func getServiceParserConfig(ctx context.Context, name string) logs.ParserConfig {
-	svc, err := apiClient.Services.Get(ctx, name)
+func getServiceParserConfig(ctx context.Context, services *client.ServiceClient, name string) logs.ParserConfig {
+	svc, err := services.Get(ctx, name)
 	if err != nil {
 		return logs.ParserConfig{} // No parsing if we can't get config
 	}
@@ -936,13 +1188,17 @@ func openBrowser(url string) error {
 }
 
 func cmdStart(args []string) error {
+	worktree, args, err := splitWorktreeFlag(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl start <service>")
+		return fmt.Errorf("usage: trellis-ctl start [-w <worktree>] <service>")
 	}
 
 	ctx := context.Background()
 	name := args[0]
-	svc, err := apiClient.Services.Start(ctx, name)
+	svc, err := serviceClient(worktree).Start(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -957,13 +1213,17 @@ func cmdStart(args []string) error {
 }
 
 func cmdStop(args []string) error {
+	worktree, args, err := splitWorktreeFlag(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl stop <service>")
+		return fmt.Errorf("usage: trellis-ctl stop [-w <worktree>] <service>")
 	}
 
 	ctx := context.Background()
 	name := args[0]
-	svc, err := apiClient.Services.Stop(ctx, name)
+	svc, err := serviceClient(worktree).Stop(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -978,13 +1238,17 @@ func cmdStop(args []string) error {
 }
 
 func cmdRestart(args []string) error {
+	worktree, args, err := splitWorktreeFlag(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl restart <service>")
+		return fmt.Errorf("usage: trellis-ctl restart [-w <worktree>] <service>")
 	}
 
 	ctx := context.Background()
 	name := args[0]
-	svc, err := apiClient.Services.Restart(ctx, name)
+	svc, err := serviceClient(worktree).Restart(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -1000,7 +1264,7 @@ func cmdRestart(args []string) error {
 
 func cmdWorkflow(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl workflow <list|describe|run|status|cancel> [args]")
+		return fmt.Errorf("usage: trellis-ctl workflow <list|describe|run|status|cancel|history|artifacts> [args]")
 	}
 
 	subcmd := args[0]
@@ -1017,6 +1281,10 @@ func cmdWorkflow(args []string) error {
 		return cmdWorkflowStatus(subargs)
 	case "cancel":
 		return cmdWorkflowCancel(subargs)
+	case "history":
+		return cmdWorkflowHistory(subargs)
+	case "artifacts":
+		return cmdWorkflowArtifacts(subargs)
 	default:
 		return fmt.Errorf("unknown workflow subcommand: %s", subcmd)
 	}
@@ -1122,6 +1390,44 @@ func cmdWorkflowDescribe(args []string) error {
 		}
 	}
 
+	if len(wf.Steps) > 0 {
+		fmt.Println("\nSteps:")
+		for _, step := range wf.Steps {
+			run := strings.Join(step.Command, " ")
+			if step.Workflow != "" {
+				run = "workflow " + step.Workflow
+			}
+			fmt.Printf("  %-20s %s\n", step.ID, run)
+			if len(step.Needs) > 0 {
+				fmt.Printf("  %-20s needs: %s\n", "", strings.Join(step.Needs, ", "))
+			}
+			if step.If != "" {
+				fmt.Printf("  %-20s if: %s\n", "", step.If)
+			}
+		}
+	}
+
+	if tr := wf.Triggers; tr != nil {
+		fmt.Println("\nTriggers:")
+		if len(tr.Files) > 0 {
+			fmt.Printf("  Files:    %s\n", strings.Join(tr.Files, ", "))
+		}
+		if len(tr.Ignore) > 0 {
+			fmt.Printf("  Ignore:   %s\n", strings.Join(tr.Ignore, ", "))
+		}
+		if len(tr.Events) > 0 {
+			fmt.Printf("  Events:   %s\n", strings.Join(tr.Events, ", "))
+		}
+		if tr.Schedule != "" {
+			fmt.Printf("  Schedule: %s\n", tr.Schedule)
+		}
+		policy := tr.Policy
+		if policy == "" {
+			policy = "queue"
+		}
+		fmt.Printf("  Policy:   %s\n", policy)
+	}
+
 	return nil
 }
 
@@ -1204,28 +1510,18 @@ func cmdWorkflowRun(args []string) error {
 	}
 
 	// Print structured summary if the workflow has an output parser
-	if s := status.Summary; s != nil {
-		var parts []string
-		if s.TestsPassed+s.TestsFailed+s.TestsSkipped > 0 {
-			parts = append(parts, fmt.Sprintf("tests: %d passed, %d failed, %d skipped", s.TestsPassed, s.TestsFailed, s.TestsSkipped))
-		}
-		if s.Errors > 0 {
-			parts = append(parts, fmt.Sprintf("%d errors", s.Errors))
-		}
-		if s.Warnings > 0 {
-			parts = append(parts, fmt.Sprintf("%d warnings", s.Warnings))
-		}
-		if len(parts) > 0 {
-			fmt.Printf("\nSummary: %s\n", strings.Join(parts, "; "))
-		}
-		for _, t := range s.FailedTests {
-			fmt.Printf("  FAIL %s\n", t)
-		}
+	printWorkflowSummary(status.Summary)
+	printWorkflowSteps(status.Steps, "")
+	printWorkflowCoverage(status.Coverage, false)
+	if len(status.Artifacts) > 0 {
+		fmt.Printf("\nArtifacts: %d files (trellis-ctl workflow artifacts %s %s)\n", len(status.Artifacts), id, status.ID)
 	}
 
 	// Print result
 	duration := status.Duration.Round(time.Millisecond).String()
-	if status.Success {
+	if status.CachedFrom != "" {
+		fmt.Printf("\n✓ Workflow restored from run %s\n", status.CachedFrom)
+	} else if status.Success {
 		fmt.Printf("\n✓ Workflow completed successfully (%s)\n", duration)
 	} else {
 		fmt.Printf("\n✗ Workflow failed (%s)\n", duration)
@@ -1238,6 +1534,79 @@ func cmdWorkflowRun(args []string) error {
 	return nil
 }
 
+// printWorkflowSteps prints the state of each step of a run composed of
+// steps, indenting the steps of reused workflows under their step.
+func printWorkflowSteps(steps []client.StepStatus, indent string) {
+	if len(steps) == 0 {
+		return
+	}
+	if indent == "" {
+		fmt.Println("\nSteps:")
+	}
+	for _, st := range steps {
+		mark := " "
+		switch st.State {
+		case client.WorkflowStateSuccess:
+			mark = "✓"
+		case client.WorkflowStateFailed, client.WorkflowStateCanceled:
+			mark = "✗"
+		case client.WorkflowStateSkipped:
+			mark = "-"
+		}
+		line := fmt.Sprintf("%s  %s %-20s %-8s", indent, mark, st.ID, st.State)
+		if !st.StartedAt.IsZero() {
+			line += " " + st.Duration.Round(time.Millisecond).String()
+		}
+		if st.CachedFrom != "" {
+			line += "  restored from " + st.CachedFrom
+		}
+		if st.Error != "" {
+			line += "  " + st.Error
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+		printWorkflowSteps(st.Steps, indent+"  ")
+	}
+}
+
+// printWorkflowSummary prints the summary of a run's parsed output, if it
+// has one.
+func printWorkflowSummary(s *client.WorkflowSummary) {
+	if s == nil {
+		return
+	}
+	var parts []string
+	if s.TestsPassed+s.TestsFailed+s.TestsSkipped > 0 {
+		parts = append(parts, fmt.Sprintf("tests: %d passed, %d failed, %d skipped", s.TestsPassed, s.TestsFailed, s.TestsSkipped))
+	}
+	if s.Errors > 0 {
+		parts = append(parts, fmt.Sprintf("%d errors", s.Errors))
+	}
+	if s.Warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d warnings", s.Warnings))
+	}
+	if len(parts) > 0 {
+		fmt.Printf("\nSummary: %s\n", strings.Join(parts, "; "))
+	}
+	for _, t := range s.FailedTests {
+		fmt.Printf("  FAIL %s\n", t)
+	}
+}
+
+// printWorkflowCoverage prints the coverage of a run's coverage files, if
+// it kept any, with the coverage of each file if perFile is set.
+func printWorkflowCoverage(c *client.Coverage, perFile bool) {
+	if c == nil {
+		return
+	}
+	fmt.Printf("\nCoverage: %.1f%% (%d/%d)\n", c.Percent(), c.Covered, c.Total)
+	if !perFile {
+		return
+	}
+	for _, f := range c.Files {
+		fmt.Printf("  %6.1f%%  %-60s %d/%d\n", f.Percent(), f.File, f.Covered, f.Total)
+	}
+}
+
 func cmdWorkflowStatus(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("usage: trellis-ctl workflow status <id>")
@@ -1276,123 +1645,546 @@ func cmdWorkflowCancel(args []string) error {
 	return nil
 }
 
-func cmdWorktree(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl worktree <list|activate> [args]")
+func cmdWorkflowHistory(args []string) error {
+	const usage = "usage: trellis-ctl workflow history <id> [<run-id> | -tests | -flaky] [-n <count>] [-w <worktree>]"
+	var id, runID string
+	var tests, flaky bool
+	opts := &client.HistoryOptions{Limit: 20}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-tests":
+			tests = true
+		case arg == "-flaky":
+			flaky = true
+		case arg == "-n" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid -n %q", args[i])
+			}
+			opts.Limit = n
+		case (arg == "-w" || arg == "-worktree") && i+1 < len(args):
+			i++
+			opts.Worktree = args[i]
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s\n%s", arg, usage)
+		case id == "":
+			id = arg
+		case runID == "":
+			runID = arg
+		default:
+			return fmt.Errorf(usage)
+		}
+	}
+	if id == "" {
+		return fmt.Errorf(usage)
 	}
 
-	subcmd := args[0]
-	subargs := args[1:]
-
-	switch subcmd {
-	case "list":
-		return cmdWorktreeList()
-	case "activate":
-		return cmdWorktreeActivate(subargs)
-	default:
-		return fmt.Errorf("unknown worktree subcommand: %s", subcmd)
+	switch {
+	case runID != "":
+		return cmdWorkflowHistoryRun(id, runID)
+	case tests:
+		return cmdWorkflowHistoryTests(id, opts.Limit)
+	case flaky:
+		return cmdWorkflowHistoryFlaky(id)
 	}
-}
 
-func cmdWorktreeList() error {
 	ctx := context.Background()
-	worktrees, err := apiClient.Worktrees.List(ctx)
+	runs, err := apiClient.Workflows.History(ctx, id, opts)
 	if err != nil {
 		return err
 	}
 
 	if jsonOutput {
-		printJSON(worktrees)
+		printJSON(runs)
 		return nil
 	}
 
-	fmt.Printf("%-20s %-20s %-8s %-20s %s\n", "NAME", "BRANCH", "ACTIVE", "STATUS", "PATH")
-	fmt.Println(strings.Repeat("-", 100))
-	for _, wt := range worktrees {
-		active := ""
-		if wt.Active {
-			active = "*"
-		}
-		status := formatWorktreeStatus(wt)
-		fmt.Printf("%-20s %-20s %-8s %-20s %s\n", wt.Name(), wt.Branch, active, status, wt.Path)
+	if len(runs) == 0 {
+		fmt.Printf("No runs of %s recorded\n", id)
+		return nil
 	}
 
+	fmt.Printf("%-32s %-15s %-9s %-9s %-10s %-15s %-9s %s\n", "RUN", "STARTED", "STATE", "DURATION", "COMMIT", "WORKTREE", "TRIGGER", "TESTS")
+	fmt.Println(strings.Repeat("-", 120))
+	for _, run := range runs {
+		testCounts := "-"
+		if s := run.Summary; s != nil && s.TestsPassed+s.TestsFailed > 0 {
+			testCounts = fmt.Sprintf("%d passed, %d failed", s.TestsPassed, s.TestsFailed)
+		}
+		worktree := run.Worktree
+		if worktree == "" {
+			worktree = "-"
+		}
+		trigger := "-"
+		if run.Trigger != nil {
+			trigger = run.Trigger.Type
+		}
+		fmt.Printf("%-32s %-15s %-9s %-9s %-10s %-15s %-9s %s\n",
+			run.ID,
+			run.StartedAt.Local().Format("Jan 02 15:04:05"),
+			run.State,
+			run.Duration.Round(100*time.Millisecond),
+			shortCommit(run.Commit, run.Dirty),
+			worktree,
+			trigger,
+			testCounts,
+		)
+	}
 	return nil
 }
 
-// formatWorktreeStatus builds a compact status string for a worktree.
-func formatWorktreeStatus(wt client.Worktree) string {
-	var parts []string
-	if wt.Dirty {
-		parts = append(parts, "dirty")
+// shortCommit abbreviates a run's commit, marking it with * if the run had
+// uncommitted changes.
+func shortCommit(commit string, dirty bool) string {
+	if commit == "" {
+		return "-"
 	}
-	if wt.Ahead > 0 {
-		parts = append(parts, fmt.Sprintf("↑%d", wt.Ahead))
+	if len(commit) > 8 {
+		commit = commit[:8]
 	}
-	if wt.Behind > 0 {
-		parts = append(parts, fmt.Sprintf("↓%d", wt.Behind))
+	if dirty {
+		commit += "*"
 	}
-	if wt.Detached {
-		parts = append(parts, "detached")
+	return commit
+}
+
+func cmdWorkflowHistoryRun(id, runID string) error {
+	ctx := context.Background()
+	run, err := apiClient.Workflows.HistoryRun(ctx, id, runID)
+	if err != nil {
+		return err
 	}
-	if len(parts) == 0 {
-		return "-"
+
+	if jsonOutput {
+		printJSON(run)
+		return nil
 	}
-	return strings.Join(parts, ", ")
+
+	fmt.Printf("Run: %s\n", run.ID)
+	fmt.Printf("Started: %s\n", run.StartedAt.Local().Format(time.RFC1123))
+	fmt.Printf("State: %s (%s)\n", run.State, run.Duration.Round(time.Millisecond))
+	if run.Commit != "" {
+		fmt.Printf("Commit: %s\n", shortCommit(run.Commit, run.Dirty))
+	}
+	if run.Worktree != "" {
+		fmt.Printf("Worktree: %s\n", run.Worktree)
+	}
+	if run.Trigger != nil {
+		fmt.Printf("Trigger: %s (%s)\n", run.Trigger.Type, run.Trigger.Detail)
+	}
+	if run.CachedFrom != "" {
+		fmt.Printf("Restored from: %s\n", run.CachedFrom)
+	}
+	if len(run.Inputs) > 0 {
+		names := make([]string, 0, len(run.Inputs))
+		for name := range run.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("Input %s: %v\n", name, run.Inputs[name])
+		}
+	}
+	if run.Error != "" {
+		fmt.Printf("Error: %s\n", run.Error)
+	}
+	if run.Output != "" {
+		fmt.Printf("\n%s", run.Output)
+	}
+	printWorkflowSummary(run.Summary)
+	printWorkflowSteps(run.Steps, "")
+	printWorkflowCoverage(run.Coverage, true)
+	if len(run.Artifacts) > 0 {
+		fmt.Println("\nArtifacts:")
+		printWorkflowArtifacts(run.Artifacts)
+	}
+	return nil
 }
 
-func cmdWorktreeActivate(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("usage: trellis-ctl worktree activate <name>")
+func cmdWorkflowArtifacts(args []string) error {
+	const usage = "usage: trellis-ctl workflow artifacts <id> <run-id> [<path> [-o <file>]]"
+	var id, runID, path, out string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-o" && i+1 < len(args):
+			i++
+			out = args[i]
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s\n%s", arg, usage)
+		case id == "":
+			id = arg
+		case runID == "":
+			runID = arg
+		case path == "":
+			path = arg
+		default:
+			return fmt.Errorf(usage)
+		}
+	}
+	if runID == "" {
+		return fmt.Errorf(usage)
 	}
 
 	ctx := context.Background()
-	name := args[0]
-	if !jsonOutput {
-		fmt.Printf("Activating worktree: %s\n", name)
+	if path == "" {
+		run, err := apiClient.Workflows.HistoryRun(ctx, id, runID)
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			printJSON(run.Artifacts)
+			return nil
+		}
+		if len(run.Artifacts) == 0 {
+			fmt.Printf("Run %s kept no artifacts\n", runID)
+			return nil
+		}
+		printWorkflowArtifacts(run.Artifacts)
+		return nil
 	}
 
-	result, err := apiClient.Worktrees.Activate(ctx, name)
+	body, err := apiClient.Workflows.Artifact(ctx, id, runID, path)
 	if err != nil {
 		return err
 	}
+	defer body.Close()
 
-	if jsonOutput {
-		printJSON(result)
-		return nil
+	// Write to stdout with -o -, and to the file's name otherwise
+	if out == "-" {
+		_, err := io.Copy(os.Stdout, body)
+		return err
+	}
+	if out == "" {
+		out = filepath.Base(filepath.FromSlash(path))
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Saved %s (%d bytes)\n", out, n)
 	}
-
-	fmt.Printf("Activated %s (%s) in %s\n", result.Worktree.Name(), result.Worktree.Branch, result.Duration)
 	return nil
 }
 
-func cmdEvents(args []string) error {
-	limit := 50
-
-	// Parse -n flag
-	for i := 0; i < len(args); i++ {
-		if args[i] == "-n" && i+1 < len(args) {
-			n, err := strconv.Atoi(args[i+1])
-			if err == nil && n > 0 {
-				limit = n
-			}
-			i++
+// printWorkflowArtifacts prints a table of the files kept from a run.
+func printWorkflowArtifacts(artifacts []client.Artifact) {
+	fmt.Printf("%-60s %-12s %s\n", "PATH", "SIZE", "STEP")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, a := range artifacts {
+		step := a.Step
+		if step == "" {
+			step = "-"
 		}
+		fmt.Printf("%-60s %-12d %s\n", a.Path, a.Size, step)
 	}
+}
 
+func cmdWorkflowHistoryTests(id string, limit int) error {
 	ctx := context.Background()
-	events, err := apiClient.Events.List(ctx, &client.ListOptions{Limit: limit})
+	timeline, err := apiClient.Workflows.Tests(ctx, id, limit)
 	if err != nil {
 		return err
 	}
 
 	if jsonOutput {
-		printJSON(events)
+		printJSON(timeline)
 		return nil
 	}
 
-	fmt.Printf("%-25s %-25s %-15s %s\n", "TIME", "TYPE", "WORKTREE", "DETAILS")
-	fmt.Println(strings.Repeat("-", 100))
+	if len(timeline.Tests) == 0 {
+		fmt.Printf("No test failures in %d runs of %s\n", len(timeline.Runs), id)
+		return nil
+	}
+
+	fmt.Printf("Last %d runs of %s, oldest first (✓ passed, ✗ failed, ? unknown)\n\n", len(timeline.Runs), id)
+	fmt.Printf("%-50s %-6s %-6s %s\n", "TEST", "FAILS", "FLIPS", "HISTORY")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, test := range timeline.Tests {
+		var history strings.Builder
+		for _, result := range test.Results {
+			switch result {
+			case "pass":
+				history.WriteString("✓")
+			case "fail":
+				history.WriteString("✗")
+			default:
+				history.WriteString("?")
+			}
+		}
+		fmt.Printf("%-50s %-6d %-6d %s\n", test.Test, test.Failures, test.Flips, history.String())
+	}
+	return nil
+}
+
+func cmdWorkflowHistoryFlaky(id string) error {
+	ctx := context.Background()
+	tests, err := apiClient.Workflows.Flaky(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(tests)
+		return nil
+	}
+
+	if len(tests) == 0 {
+		fmt.Printf("No flaky tests in the runs of %s\n", id)
+		return nil
+	}
+
+	fmt.Printf("%-50s %-6s %-7s %-20s %s\n", "TEST", "FAILS", "PASSES", "COMMITS", "LAST FAILURE")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, test := range tests {
+		commits := make([]string, len(test.Commits))
+		for i, commit := range test.Commits {
+			commits[i] = shortCommit(commit, false)
+		}
+		fmt.Printf("%-50s %-6d %-7d %-20s %s\n", test.Test, test.Failures, test.Passes, strings.Join(commits, ","), test.LastFailure)
+	}
+	return nil
+}
+
+func cmdWorktree(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl worktree <list|activate|start|stop|ports> [args]")
+	}
+
+	subcmd := args[0]
+	subargs := args[1:]
+
+	switch subcmd {
+	case "list":
+		return cmdWorktreeList()
+	case "activate":
+		return cmdWorktreeActivate(subargs)
+	case "start", "stop":
+		return cmdWorktreeServices(subcmd, subargs)
+	case "ports":
+		return cmdWorktreePorts(subargs)
+	default:
+		return fmt.Errorf("unknown worktree subcommand: %s", subcmd)
+	}
+}
+
+func cmdWorktreeList() error {
+	ctx := context.Background()
+	worktrees, err := apiClient.Worktrees.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(worktrees)
+		return nil
+	}
+
+	fmt.Printf("%-20s %-20s %-8s %-20s %s\n", "NAME", "BRANCH", "ACTIVE", "STATUS", "PATH")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, wt := range worktrees {
+		active := ""
+		if wt.Active {
+			active = "*"
+		}
+		status := formatWorktreeStatus(wt)
+		fmt.Printf("%-20s %-20s %-8s %-20s %s\n", wt.Name(), wt.Branch, active, status, wt.Path)
+	}
+
+	return nil
+}
+
+// formatWorktreeStatus builds a compact status string for a worktree.
+func formatWorktreeStatus(wt client.Worktree) string {
+	var parts []string
+	if wt.Dirty {
+		parts = append(parts, "dirty")
+	}
+	if wt.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", wt.Ahead))
+	}
+	if wt.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", wt.Behind))
+	}
+	if wt.Detached {
+		parts = append(parts, "detached")
+	}
+	if wt.Services {
+		parts = append(parts, "services")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func cmdWorktreeActivate(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl worktree activate <name>")
+	}
+
+	ctx := context.Background()
+	name := args[0]
+	if !jsonOutput {
+		fmt.Printf("Activating worktree: %s\n", name)
+	}
+
+	result, err := apiClient.Worktrees.Activate(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(result)
+		return nil
+	}
+
+	fmt.Printf("Activated %s (%s) in %s\n", result.Worktree.Name(), result.Worktree.Branch, result.Duration)
+	return nil
+}
+
+// cmdWorktreeServices starts or stops the services a worktree runs
+// alongside the active worktree's.
+func cmdWorktreeServices(action string, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl worktree %s <name>", action)
+	}
+
+	ctx := context.Background()
+	name := args[0]
+	var wt *client.Worktree
+	var err error
+	if action == "start" {
+		wt, err = apiClient.Worktrees.StartServices(ctx, name)
+	} else {
+		wt, err = apiClient.Worktrees.StopServices(ctx, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(wt)
+		return nil
+	}
+
+	if action == "start" {
+		fmt.Printf("Started services of %s (trellis-ctl status -w %s)\n", wt.Name(), wt.Name())
+	} else {
+		fmt.Printf("Stopped services of %s\n", wt.Name())
+	}
+	return nil
+}
+
+// cmdWorktreePorts shows the ports allocated to one worktree or to all.
+func cmdWorktreePorts(args []string) error {
+	ctx := context.Background()
+	var worktrees []client.Worktree
+	if len(args) > 0 {
+		wt, err := apiClient.Worktrees.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		worktrees = []client.Worktree{*wt}
+	} else {
+		var err error
+		if worktrees, err = apiClient.Worktrees.List(ctx); err != nil {
+			return err
+		}
+	}
+
+	if jsonOutput {
+		ports := make(map[string]map[string]int, len(worktrees))
+		for _, wt := range worktrees {
+			if len(wt.Ports) > 0 {
+				ports[wt.Name()] = wt.Ports
+			}
+		}
+		printJSON(ports)
+		return nil
+	}
+
+	fmt.Printf("%-20s %-20s %s\n", "WORKTREE", "NAME", "PORT")
+	fmt.Println(strings.Repeat("-", 50))
+	for _, wt := range worktrees {
+		names := make([]string, 0, len(wt.Ports))
+		for name := range wt.Ports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-20s %-20s %d\n", wt.Name(), name, wt.Ports[name])
+		}
+	}
+	return nil
+}
+
+func cmdEvents(args []string) error {
+	opts := &client.ListOptions{Limit: 50}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-n" && i+1 < len(args):
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err == nil && n > 0 {
+				opts.Limit = n
+			}
+		case arg == "-type" && i+1 < len(args):
+			i++
+			opts.Types = append(opts.Types, args[i])
+		case arg == "-worktree" && i+1 < len(args):
+			i++
+			opts.Worktree = args[i]
+		case arg == "-since" && i+1 < len(args):
+			i++
+			since, err := logs.ParseDuration(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid -since value: %w", err)
+			}
+			opts.Since = since
+		case arg == "-until" && i+1 < len(args):
+			i++
+			until, err := logs.ParseDuration(args[i])
+			if err != nil {
+				return fmt.Errorf("invalid -until value: %w", err)
+			}
+			opts.Until = until
+		case arg == "-grep" && i+1 < len(args):
+			i++
+			opts.Search = args[i]
+		case arg == "-before" && i+1 < len(args):
+			i++
+			opts.Before = args[i]
+		default:
+			return fmt.Errorf("unknown flag: %s", arg)
+		}
+	}
+
+	ctx := context.Background()
+	events, err := apiClient.Events.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(events)
+		return nil
+	}
+
+	fmt.Printf("%-25s %-25s %-15s %s\n", "TIME", "TYPE", "WORKTREE", "DETAILS")
+	fmt.Println(strings.Repeat("-", 100))
 	for _, evt := range events {
 		details := ""
 		if len(evt.Payload) > 0 {
@@ -1461,6 +2253,7 @@ type traceConfig struct {
 	since      string
 	until      string
 	name       string
+	filter     string
 	expandByID bool
 }
 
@@ -1482,6 +2275,9 @@ func parseTraceArgs(args []string) (*traceConfig, error) {
 		case arg == "-name" && i+1 < len(args):
 			i++
 			cfg.name = args[i]
+		case arg == "-filter" && i+1 < len(args):
+			i++
+			cfg.filter = args[i]
 		case arg == "-expand-by-id":
 			cfg.expandByID = true
 		case arg == "-no-expand-by-id":
@@ -1546,6 +2342,7 @@ func cmdTrace(args []string) error {
 		End:        until,
 		Name:       cfg.name,
 		ExpandByID: cfg.expandByID,
+		Filter:     cfg.filter,
 	}
 
 	if !jsonOutput {
@@ -1946,3 +2743,711 @@ func printCrashDetail(crash *client.Crash) {
 		}
 	}
 }
+
+func cmdOTLP(args []string) error {
+	if len(args) < 1 || args[0] != "send" {
+		return fmt.Errorf("usage: trellis-ctl otlp send [-endpoint <url>] [-service <name>]")
+	}
+
+	endpoint := "http://localhost:4318"
+	service := "trellis-ctl"
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "-endpoint" && i+1 < len(args):
+			i++
+			endpoint = args[i]
+		case args[i] == "-service" && i+1 < len(args):
+			i++
+			service = args[i]
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+
+	traceID := randomHex(16)
+	rootID, dbID, payID := randomHex(8), randomHex(8), randomHex(8)
+	resource := map[string]any{"service.name": service}
+	start := time.Now().Add(-120 * time.Millisecond)
+
+	spans := []otlp.Span{
+		{
+			TraceID: traceID, SpanID: rootID, Name: "GET /checkout", Kind: otlp.SpanKindServer,
+			Start: start, End: start.Add(110 * time.Millisecond),
+			Attributes: map[string]any{"http.method": "GET", "http.route": "/checkout", "http.status_code": int64(502)},
+			StatusCode: otlp.StatusCodeError, Resource: resource,
+		},
+		{
+			TraceID: traceID, SpanID: dbID, ParentSpanID: rootID, Name: "SELECT orders", Kind: otlp.SpanKindClient,
+			Start: start.Add(5 * time.Millisecond), End: start.Add(25 * time.Millisecond),
+			Attributes: map[string]any{"db.system": "postgresql"}, Resource: resource,
+		},
+		{
+			TraceID: traceID, SpanID: payID, ParentSpanID: rootID, Name: "POST /charge", Kind: otlp.SpanKindClient,
+			Start: start.Add(30 * time.Millisecond), End: start.Add(105 * time.Millisecond),
+			Events: []otlp.SpanEvent{{
+				Time: start.Add(104 * time.Millisecond), Name: "exception",
+				Attributes: map[string]any{"exception.type": "TimeoutError", "exception.message": "payment gateway timed out"},
+			}},
+			StatusCode: otlp.StatusCodeError, StatusMessage: "payment gateway timed out", Resource: resource,
+		},
+	}
+	records := []otlp.LogRecord{
+		{
+			Time: start.Add(time.Millisecond), SeverityNumber: 9, SeverityText: "INFO", Body: "checkout started",
+			TraceID: traceID, SpanID: rootID, Resource: resource,
+		},
+		{
+			Time: start.Add(105 * time.Millisecond), SeverityNumber: 17, SeverityText: "ERROR", Body: "charge failed",
+			Attributes: map[string]any{"attempt": int64(1)}, TraceID: traceID, SpanID: payID, Resource: resource,
+		},
+	}
+
+	ctx := context.Background()
+	exporter := otlp.NewExporter(endpoint)
+	if err := exporter.ExportSpans(ctx, spans); err != nil {
+		return fmt.Errorf("exporting spans: %w", err)
+	}
+	if err := exporter.ExportLogs(ctx, records); err != nil {
+		return fmt.Errorf("exporting logs: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(map[string]any{"trace_id": traceID, "spans": len(spans), "logs": len(records)})
+		return nil
+	}
+	fmt.Printf("Sent %d spans and %d logs for trace %s\n", len(spans), len(records), traceID)
+	return nil
+}
+
+// randomHex returns n random bytes hex-encoded, for OTLP trace and span IDs.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func cmdProxy(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl proxy <requests|show|replay|clear|faults|fault>")
+	}
+
+	subcmd := args[0]
+	subargs := args[1:]
+
+	switch subcmd {
+	case "requests":
+		return cmdProxyRequests(subargs)
+	case "show":
+		return cmdProxyShow(subargs)
+	case "replay":
+		return cmdProxyReplay(subargs)
+	case "clear":
+		return cmdProxyClear()
+	case "faults":
+		return cmdProxyFaults(subargs)
+	case "fault":
+		return cmdProxyFault(subargs)
+	default:
+		return fmt.Errorf("unknown proxy subcommand: %s", subcmd)
+	}
+}
+
+func cmdProxyRequests(args []string) error {
+	opts := &client.ProxyRequestsOptions{Limit: 50}
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "-listener":
+			opts.Listener = value
+		case "-method":
+			opts.Method = value
+		case "-status":
+			opts.Status = value
+		case "-path":
+			opts.Path = value
+		case "-upstream":
+			opts.Upstream = value
+		case "-slow":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid -slow duration %q: %w", value, err)
+			}
+			opts.MinDuration = d
+		case "-limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid -limit %q", value)
+			}
+			opts.Limit = n
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+		i++
+	}
+
+	ctx := context.Background()
+	requests, err := apiClient.Proxy.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(requests)
+		return nil
+	}
+
+	if len(requests) == 0 {
+		fmt.Println("No captured requests")
+		return nil
+	}
+
+	fmt.Printf("%-6s %-9s %-7s %-6s %-10s %-22s %s\n", "ID", "TIME", "METHOD", "STATUS", "DURATION", "UPSTREAM", "URL")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, r := range requests {
+		url := r.URL
+		if r.ReplayOf != 0 {
+			url += fmt.Sprintf(" (replay of %d)", r.ReplayOf)
+		}
+		if r.Fault != "" {
+			url += " [fault: " + r.Fault + "]"
+		}
+		if r.Mock != "" {
+			url += " [mock: " + r.Mock + "]"
+		}
+		upstream := r.Upstream
+		if r.Worktree != "" {
+			upstream += " @" + r.Worktree
+		}
+		fmt.Printf("%-6d %-9s %-7s %-6d %-10s %-22s %s\n",
+			r.ID,
+			r.Time.Local().Format("15:04:05"),
+			r.Method,
+			r.Status,
+			fmt.Sprintf("%.1fms", r.DurationMS),
+			upstream,
+			url,
+		)
+	}
+
+	return nil
+}
+
+// parseProxyID parses a captured request ID argument.
+func parseProxyID(args []string, usage string) (uint64, error) {
+	if len(args) < 1 {
+		return 0, fmt.Errorf("usage: %s", usage)
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request id: %s", args[0])
+	}
+	return id, nil
+}
+
+func cmdProxyShow(args []string) error {
+	id, err := parseProxyID(args, "trellis-ctl proxy show <id>")
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	req, err := apiClient.Proxy.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(req)
+		return nil
+	}
+
+	printProxyRequest(req)
+	return nil
+}
+
+func cmdProxyReplay(args []string) error {
+	usage := "trellis-ctl proxy replay <id> [-X method] [-url path] [-H 'Name: value'] [-d body|@file] [-worktree name]"
+	id, err := parseProxyID(args, usage)
+	if err != nil {
+		return err
+	}
+
+	opts := &client.ReplayOptions{}
+	for i := 1; i < len(args); i++ {
+		if i+1 >= len(args) {
+			return fmt.Errorf("usage: %s", usage)
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "-X", "-method":
+			opts.Method = value
+		case "-url":
+			opts.URL = value
+		case "-H", "-header":
+			name, val, ok := strings.Cut(value, ":")
+			if !ok {
+				return fmt.Errorf("invalid header %q (want 'Name: value', or 'Name:' to remove it)", value)
+			}
+			if opts.Headers == nil {
+				opts.Headers = make(map[string]string)
+			}
+			opts.Headers[strings.TrimSpace(name)] = strings.TrimSpace(val)
+		case "-d", "-data":
+			body := value
+			if strings.HasPrefix(value, "@") {
+				data, err := os.ReadFile(value[1:])
+				if err != nil {
+					return fmt.Errorf("reading body: %w", err)
+				}
+				body = string(data)
+			}
+			opts.Body = &body
+		case "-worktree":
+			opts.Worktree = value
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+		i++
+	}
+
+	ctx := context.Background()
+	req, err := apiClient.Proxy.Replay(ctx, id, opts)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(req)
+		return nil
+	}
+
+	printProxyRequest(req)
+	return nil
+}
+
+func cmdProxyClear() error {
+	ctx := context.Background()
+	if err := apiClient.Proxy.Clear(ctx); err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Println("Cleared captured requests")
+	}
+
+	return nil
+}
+
+func cmdProxyFaults(args []string) error {
+	ctx := context.Background()
+	var faults []client.ProxyRouteFaults
+	var err error
+	switch {
+	case len(args) == 0:
+		faults, err = apiClient.Proxy.Faults(ctx)
+	case args[0] == "clear":
+		faults, err = apiClient.Proxy.ClearFaults(ctx)
+	default:
+		return fmt.Errorf("usage: trellis-ctl proxy faults [clear]")
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(faults)
+		return nil
+	}
+
+	printProxyFaults(faults)
+	return nil
+}
+
+func cmdProxyFault(args []string) error {
+	usage := "trellis-ctl proxy fault <listener> <route> [-latency d] [-latency-max d] [-error-rate pct] [-error-status code] [-abort-rate pct] [-bandwidth bytes] [-ws-drop-rate pct] [-ws-drop-after d] [-off]"
+	if len(args) < 2 {
+		return fmt.Errorf("usage: %s", usage)
+	}
+	listener := args[0]
+	route, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid route index: %s", args[1])
+	}
+
+	// Start from the route's current settings so flags only change what
+	// they name
+	ctx := context.Background()
+	current, err := apiClient.Proxy.Faults(ctx)
+	if err != nil {
+		return err
+	}
+	var faults client.ProxyFaults
+	for _, rf := range current {
+		if rf.Listener == listener && rf.Route == route {
+			faults = rf.Faults
+		}
+	}
+	faults.Enabled = true
+
+	parseRate := func(flag, value string) (float64, error) {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q", flag, value)
+		}
+		return v, nil
+	}
+	for i := 2; i < len(args); i++ {
+		if args[i] == "-off" {
+			faults.Enabled = false
+			continue
+		}
+		if i+1 >= len(args) {
+			return fmt.Errorf("usage: %s", usage)
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "-latency":
+			faults.Latency = value
+		case "-latency-max":
+			faults.LatencyMax = value
+		case "-error-rate":
+			if faults.ErrorRate, err = parseRate(args[i], value); err != nil {
+				return err
+			}
+		case "-error-status":
+			if faults.ErrorStatus, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("invalid -error-status %q", value)
+			}
+		case "-abort-rate":
+			if faults.AbortRate, err = parseRate(args[i], value); err != nil {
+				return err
+			}
+		case "-bandwidth":
+			if faults.Bandwidth, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("invalid -bandwidth %q", value)
+			}
+		case "-ws-drop-rate":
+			if faults.WebSocketDropRate, err = parseRate(args[i], value); err != nil {
+				return err
+			}
+		case "-ws-drop-after":
+			faults.WebSocketDropAfter = value
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+		i++
+	}
+
+	updated, err := apiClient.Proxy.SetFaults(ctx, listener, route, faults)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(updated)
+		return nil
+	}
+
+	printProxyFaults(updated)
+	return nil
+}
+
+func printProxyFaults(faults []client.ProxyRouteFaults) {
+	if len(faults) == 0 {
+		fmt.Println("No proxy routes")
+		return
+	}
+
+	fmt.Printf("%-10s %-5s %-4s %-40s %s\n", "LISTENER", "ROUTE", "ON", "MATCH", "FAULTS")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, rf := range faults {
+		on := "-"
+		if rf.Faults.Enabled {
+			on = "yes"
+		}
+		fmt.Printf("%-10s %-5d %-4s %-40s %s\n", rf.Listener, rf.Route, on, rf.Name, describeProxyFaults(rf.Faults))
+	}
+}
+
+// describeProxyFaults summarizes a route's fault settings, whether or not
+// they are enabled.
+func describeProxyFaults(f client.ProxyFaults) string {
+	var parts []string
+	if f.Latency != "" || f.LatencyMax != "" {
+		latency := f.Latency
+		if f.LatencyMax != "" {
+			latency += "-" + f.LatencyMax
+		}
+		parts = append(parts, "latency="+latency)
+	}
+	if f.ErrorRate > 0 {
+		status := f.ErrorStatus
+		if status == 0 {
+			status = 503
+		}
+		parts = append(parts, fmt.Sprintf("error=%g%%(%d)", f.ErrorRate, status))
+	}
+	if f.AbortRate > 0 {
+		parts = append(parts, fmt.Sprintf("abort=%g%%", f.AbortRate))
+	}
+	if f.Bandwidth > 0 {
+		parts = append(parts, fmt.Sprintf("bandwidth=%dB/s", f.Bandwidth))
+	}
+	if f.WebSocketDropRate > 0 {
+		drop := fmt.Sprintf("ws-drop=%g%%", f.WebSocketDropRate)
+		if f.WebSocketDropAfter != "" {
+			drop += " after " + f.WebSocketDropAfter
+		}
+		parts = append(parts, drop)
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+func printProxyRequest(req *client.ProxyRequest) {
+	fmt.Printf("Request %d: %s %s%s\n", req.ID, req.Method, req.Host, req.URL)
+	fmt.Printf("  Time: %s\n", req.Time.Local().Format("2006-01-02 15:04:05.000"))
+	fmt.Printf("  Listener: %s\n", req.Listener)
+	route := req.Route
+	if route == "" {
+		route = "(none)"
+	}
+	fmt.Printf("  Route: %s -> %s\n", route, req.Upstream)
+	if req.Worktree != "" {
+		fmt.Printf("  Worktree: %s\n", req.Worktree)
+	}
+	fmt.Printf("  Status: %d (%.1fms)\n", req.Status, req.DurationMS)
+	if req.ReplayOf != 0 {
+		fmt.Printf("  Replay of: %d\n", req.ReplayOf)
+	}
+	if req.Error != "" {
+		fmt.Printf("  Error: %s\n", req.Error)
+	}
+	if req.Fault != "" {
+		fmt.Printf("  Injected fault: %s\n", req.Fault)
+	}
+	if req.Mock != "" {
+		fmt.Printf("  Mock: %s\n", req.Mock)
+	}
+
+	fmt.Println()
+	fmt.Println("Request:")
+	printProxyHeaders(req.RequestHeaders)
+	printProxyBody(req.RequestBody)
+
+	fmt.Println()
+	fmt.Println("Response:")
+	printProxyHeaders(req.ResponseHeaders)
+	printProxyBody(req.ResponseBody)
+}
+
+func printProxyHeaders(headers map[string][]string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Printf("  %s: %s\n", name, value)
+		}
+	}
+}
+
+func printProxyBody(body client.ProxyBody) {
+	if body.Size == 0 {
+		return
+	}
+	var notes []string
+	if body.Encoding != "" {
+		notes = append(notes, "decoded from "+body.Encoding)
+	}
+	if body.Base64 {
+		notes = append(notes, "binary, shown as base64")
+	}
+	if body.Truncated {
+		notes = append(notes, "truncated")
+	}
+	fmt.Printf("\n  (%d bytes", body.Size)
+	for _, note := range notes {
+		fmt.Printf(", %s", note)
+	}
+	fmt.Println(")")
+	fmt.Println(body.Data)
+}
+
+func cmdConfig(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl config show [-effective] [prefix]")
+	}
+
+	subcmd := args[0]
+	subargs := args[1:]
+
+	switch subcmd {
+	case "show":
+		return cmdConfigShow(subargs)
+	default:
+		return fmt.Errorf("unknown config subcommand: %s", subcmd)
+	}
+}
+
+func cmdConfigShow(args []string) error {
+	effective := false
+	prefix := ""
+	for _, arg := range args {
+		switch {
+		case arg == "-effective" || arg == "--effective":
+			effective = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown flag: %s", arg)
+		case prefix == "":
+			prefix = arg
+		default:
+			return fmt.Errorf("usage: trellis-ctl config show [-effective] [prefix]")
+		}
+	}
+	if prefix != "" && !effective {
+		return fmt.Errorf("a prefix requires -effective")
+	}
+
+	ctx := context.Background()
+	cfg, err := apiClient.Config.Show(ctx, effective)
+	if err != nil {
+		return err
+	}
+	if prefix != "" {
+		values := cfg.Values[:0]
+		for _, v := range cfg.Values {
+			if v.Path == prefix || strings.HasPrefix(v.Path, prefix+".") || strings.HasPrefix(v.Path, prefix+"[") {
+				values = append(values, v)
+			}
+		}
+		cfg.Values = values
+	}
+
+	if jsonOutput {
+		printJSON(cfg)
+		return nil
+	}
+
+	if !effective {
+		fmt.Printf("%-10s %s\n", "LAYER", "FILE")
+		for _, layer := range cfg.Layers {
+			fmt.Printf("%-10s %s\n", layer.Kind, layer.Path)
+		}
+		return nil
+	}
+
+	for _, v := range cfg.Values {
+		value, err := json.Marshal(v.Value)
+		if err != nil {
+			value = []byte(fmt.Sprint(v.Value))
+		}
+		origin := v.Origin.Kind
+		if v.Origin.Path != "" {
+			origin += ": " + v.Origin.Path
+		}
+		fmt.Printf("%s = %s  (%s)\n", v.Path, value, origin)
+	}
+	return nil
+}
+
+func cmdSecret(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: trellis-ctl secret <list|set|rm> [args]")
+	}
+
+	subcmd := args[0]
+	subargs := args[1:]
+
+	switch subcmd {
+	case "list", "ls":
+		return cmdSecretList(subargs)
+	case "set":
+		return cmdSecretSet(subargs)
+	case "rm", "delete":
+		return cmdSecretRm(subargs)
+	default:
+		return fmt.Errorf("unknown secret subcommand: %s", subcmd)
+	}
+}
+
+func cmdSecretList(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: trellis-ctl secret list")
+	}
+	list, err := apiClient.Secrets.List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(list)
+		return nil
+	}
+	if len(list) == 0 {
+		fmt.Println("No secrets")
+		return nil
+	}
+	fmt.Printf("%-30s %s\n", "NAME", "SOURCE")
+	for _, s := range list {
+		fmt.Printf("%-30s %s\n", s.Name, s.Source)
+	}
+	return nil
+}
+
+func cmdSecretSet(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: trellis-ctl secret set <name> [value|-]")
+	}
+	name := args[0]
+
+	// Reading from stdin keeps the value out of shell history
+	var value string
+	if len(args) == 2 && args[1] != "-" {
+		value = args[1]
+	} else {
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("read value: %w", err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	}
+	if value == "" {
+		return fmt.Errorf("secret value is empty")
+	}
+
+	if err := apiClient.Secrets.Set(context.Background(), name, value); err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Printf("Set secret: %s\n", name)
+	}
+
+	return nil
+}
+
+func cmdSecretRm(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: trellis-ctl secret rm <name>")
+	}
+	if err := apiClient.Secrets.Delete(context.Background(), args[0]); err != nil {
+		return err
+	}
+
+	if !jsonOutput {
+		fmt.Printf("Removed secret: %s\n", args[0])
+	}
+
+	return nil
+}
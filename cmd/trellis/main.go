@@ -458,7 +458,7 @@ func generateConfig(projectName string, port int, services []serviceConfig, buil
     //   // ]
     //
     //   // timeout: "10m"             // Maximum run time
-    //   // output_parser: "go"        // Parse output: "go", "go_test_json", "generic", "html", "none"
+    //   // output_parser: "go"        // Parse output: "go", "go_test_json", "generic", "junit", "tap", "eslint", "tsc", "pytest", "cargo", "html", "none"
     //   // restart_services: true     // Restart watched services after completion
     // }
     //
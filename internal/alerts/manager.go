@@ -0,0 +1,511 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/logs"
+	"github.com/wingedpig/trellis/internal/service"
+)
+
+const (
+	defaultWindow     = time.Minute
+	defaultCooldown   = 5 * time.Minute
+	defaultMaxHistory = 500
+	maxTrackedHits    = 10000 // Cap on match timestamps kept per rule
+	evalInterval      = time.Second
+	resubscribeDelay  = 2 * time.Second
+	restartDelay      = 30 * time.Second // Wait after a viewer fails to start
+	viewerCheckPeriod = 5 * time.Second
+	maxSampleLength   = 500
+)
+
+// Manager evaluates alert rules against live log streams. Each rule counts
+// matching entries in a sliding window; when the count reaches the threshold
+// the rule fires, and when it drops back below the threshold the rule
+// resolves. Both transitions publish an EventLogAlert event and are kept in
+// a bounded history.
+type Manager struct {
+	logManager *logs.Manager
+	services   service.Manager
+	bus        events.EventBus
+	rules      []*ruleState
+	now        func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.Mutex
+	history    []Alert // Most recent last
+	maxHistory int
+	nextID     uint64
+}
+
+// ruleState tracks the evaluation state of one rule. Guarded by Manager.mu.
+type ruleState struct {
+	rule       Rule
+	hits       []time.Time // Arrival times of matches, oldest first
+	firing     bool
+	firedAt    time.Time
+	lastFired  time.Time
+	lastMatch  time.Time
+	sample     string
+	held       bool // Breached but held back by the cooldown
+	suppressed int  // Number of times the cooldown held back a firing
+}
+
+// CompileRules converts configured rules, applying defaults and parsing
+// filters.
+func CompileRules(cfgs []config.AlertRuleConfig) ([]Rule, error) {
+	rules := make([]Rule, 0, len(cfgs))
+	for _, c := range cfgs {
+		filter, err := logs.ParseFilter(c.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("alert %s: invalid filter: %w", c.Name, err)
+		}
+		if filter.IsEmpty() {
+			return nil, fmt.Errorf("alert %s: filter is required", c.Name)
+		}
+		rule := Rule{
+			Name:      c.Name,
+			Viewer:    c.Viewer,
+			Service:   c.Service,
+			Query:     c.Filter,
+			Filter:    filter,
+			Threshold: c.Threshold,
+			Window:    config.ParseDuration(c.Window, defaultWindow),
+			Cooldown:  config.ParseDuration(c.Cooldown, defaultCooldown),
+			Severity:  c.Severity,
+			Message:   c.Message,
+		}
+		if rule.Threshold <= 0 {
+			rule.Threshold = 1
+		}
+		if rule.Window <= 0 {
+			rule.Window = defaultWindow
+		}
+		if rule.Severity == "" {
+			rule.Severity = SeverityWarning
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// NewManager creates an alert manager for the given rules. Either source
+// manager may be nil if no rule watches that kind of source. Call Start to
+// begin evaluating.
+func NewManager(rules []Rule, logManager *logs.Manager, services service.Manager, bus events.EventBus) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		logManager: logManager,
+		services:   services,
+		bus:        bus,
+		now:        time.Now,
+		ctx:        ctx,
+		cancel:     cancel,
+		maxHistory: defaultMaxHistory,
+	}
+	for _, rule := range rules {
+		m.rules = append(m.rules, &ruleState{rule: rule})
+	}
+	return m
+}
+
+// Start subscribes to each watched viewer and service and begins periodic
+// evaluation. Rules sharing a source share one subscription.
+func (m *Manager) Start() error {
+	viewers := make(map[string][]*ruleState)
+	services := make(map[string][]*ruleState)
+	for _, rs := range m.rules {
+		if rs.rule.Service != "" {
+			services[rs.rule.Service] = append(services[rs.rule.Service], rs)
+		} else {
+			viewers[rs.rule.Viewer] = append(viewers[rs.rule.Viewer], rs)
+		}
+	}
+	if len(viewers) > 0 && m.logManager == nil {
+		return fmt.Errorf("alert rules watch log viewers but no log manager is configured")
+	}
+	if len(services) > 0 && m.services == nil {
+		return fmt.Errorf("alert rules watch services but no service manager is configured")
+	}
+
+	for name, rules := range viewers {
+		m.wg.Add(1)
+		go m.watchViewer(name, rules)
+	}
+	for name, rules := range services {
+		m.wg.Add(1)
+		go m.watchService(name, rules)
+	}
+	m.wg.Add(1)
+	go m.evalLoop()
+	return nil
+}
+
+// Close stops evaluation and releases all subscriptions.
+func (m *Manager) Close() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// Rules returns the current status of every rule, in configuration order.
+func (m *Manager) Rules() []RuleStatus {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]RuleStatus, 0, len(m.rules))
+	for _, rs := range m.rules {
+		rs.prune(now)
+		state := StateOK
+		if rs.firing {
+			state = events.AlertStateFiring
+		}
+		result = append(result, RuleStatus{
+			Name:       rs.rule.Name,
+			Source:     rs.rule.Source(),
+			Filter:     rs.rule.Query,
+			Threshold:  rs.rule.Threshold,
+			Window:     rs.rule.Window.String(),
+			Cooldown:   rs.rule.Cooldown.String(),
+			Severity:   rs.rule.Severity,
+			Message:    rs.rule.Message,
+			State:      state,
+			Count:      len(rs.hits),
+			FiredAt:    rs.lastFired,
+			LastMatch:  rs.lastMatch,
+			Suppressed: rs.suppressed,
+		})
+	}
+	return result
+}
+
+// History returns the most recent alert transitions, newest first.
+// A limit of 0 returns all retained entries.
+func (m *Manager) History(limit int) []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.history)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	result := make([]Alert, 0, n)
+	for i := len(m.history) - 1; i >= 0 && len(result) < n; i-- {
+		result = append(result, m.history[i])
+	}
+	return result
+}
+
+// watchViewer feeds entries from a log viewer to its rules, keeping the
+// viewer running while subscribed. The viewer is looked up again
+// periodically because worktree switches replace service viewers with new
+// instances and config reloads may stop it.
+func (m *Manager) watchViewer(name string, rules []*ruleState) {
+	defer m.wg.Done()
+
+	ch := make(chan logs.LogEntry, 1000)
+	check := time.NewTicker(viewerCheckPeriod)
+	defer check.Stop()
+
+	var viewer *logs.Viewer
+	defer func() {
+		if viewer != nil {
+			viewer.Unsubscribe(ch)
+		}
+	}()
+
+	for {
+		if viewer == nil {
+			// Subscribe before starting so the first lines aren't missed
+			v, ok := m.logManager.Get(name)
+			if !ok {
+				if !m.sleep(resubscribeDelay) {
+					return
+				}
+				continue
+			}
+			v.Subscribe(ch)
+			if err := m.logManager.EnsureStarted(name); err != nil {
+				v.Unsubscribe(ch)
+				if !m.sleep(restartDelay) {
+					return
+				}
+				continue
+			}
+			viewer = v
+		}
+
+		select {
+		case <-m.ctx.Done():
+			return
+		case entry := <-ch:
+			m.handleEntry(rules, entry)
+		case <-check.C:
+			if current, ok := m.logManager.Get(name); !ok || current != viewer || !viewer.IsRunning() {
+				viewer.Unsubscribe(ch)
+				viewer = nil
+			}
+		}
+	}
+}
+
+// watchService feeds lines from a service's log buffer to its rules. The
+// subscription channel is closed when the service process is replaced, so
+// it resubscribes until the manager is closed.
+func (m *Manager) watchService(name string, rules []*ruleState) {
+	defer m.wg.Done()
+
+	for {
+		ch, err := m.services.SubscribeLogs(name)
+		if err != nil {
+			if !m.sleep(resubscribeDelay) {
+				return
+			}
+			continue
+		}
+
+	read:
+		for {
+			select {
+			case <-m.ctx.Done():
+				m.services.UnsubscribeLogs(name, ch)
+				return
+			case line, ok := <-ch:
+				if !ok {
+					break read
+				}
+				entry := logs.LogEntry{Timestamp: m.now(), Raw: line.Line, Message: line.Line}
+				if line.Entry != nil {
+					entry = *line.Entry
+				}
+				m.handleEntry(rules, entry)
+			}
+		}
+
+		if !m.sleep(resubscribeDelay) {
+			return
+		}
+	}
+}
+
+// sleep waits for d, returning false if the manager was closed meanwhile.
+func (m *Manager) sleep(d time.Duration) bool {
+	select {
+	case <-m.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// evalLoop periodically resolves rules whose matches have aged out of the
+// window and fires rules whose cooldown has expired.
+func (m *Manager) evalLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(evalInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.evaluate()
+		}
+	}
+}
+
+// handleEntry records a matching entry against each rule and fires any rule
+// that crosses its threshold.
+func (m *Manager) handleEntry(rules []*ruleState, entry logs.LogEntry) {
+	now := m.now()
+	var alerts []Alert
+
+	m.mu.Lock()
+	for _, rs := range rules {
+		// Backlog replayed when a source starts shouldn't fire alerts
+		if !entry.Timestamp.IsZero() && entry.Timestamp.Before(now.Add(-rs.rule.Window)) {
+			continue
+		}
+		if !rs.rule.Filter.Match(entry) {
+			continue
+		}
+		rs.hits = append(rs.hits, now)
+		if len(rs.hits) > maxTrackedHits {
+			rs.hits = rs.hits[len(rs.hits)-maxTrackedHits:]
+		}
+		rs.lastMatch = now
+		rs.sample = sampleLine(entry)
+		rs.prune(now)
+		if alert, ok := m.transition(rs, now); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	m.mu.Unlock()
+
+	m.publish(alerts)
+}
+
+// evaluate re-checks every rule against the current time.
+func (m *Manager) evaluate() {
+	now := m.now()
+	var alerts []Alert
+
+	m.mu.Lock()
+	for _, rs := range m.rules {
+		rs.prune(now)
+		if alert, ok := m.transition(rs, now); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	m.mu.Unlock()
+
+	m.publish(alerts)
+}
+
+// transition advances a rule's state machine and records the resulting
+// alert, if any. A rule fires when its windowed count reaches the threshold
+// and the cooldown since its last firing has passed; it resolves once the
+// count drops below the threshold. Caller must hold m.mu.
+func (m *Manager) transition(rs *ruleState, now time.Time) (Alert, bool) {
+	breached := len(rs.hits) >= rs.rule.Threshold
+
+	if !breached {
+		rs.held = false
+	}
+
+	switch {
+	case !rs.firing && breached:
+		if !rs.lastFired.IsZero() && now.Sub(rs.lastFired) < rs.rule.Cooldown {
+			if !rs.held {
+				rs.held = true
+				rs.suppressed++
+			}
+			return Alert{}, false
+		}
+		rs.held = false
+		rs.firing = true
+		rs.firedAt = now
+		rs.lastFired = now
+		return m.record(rs, events.AlertStateFiring, now), true
+
+	case rs.firing && !breached:
+		rs.firing = false
+		return m.record(rs, events.AlertStateResolved, now), true
+	}
+	return Alert{}, false
+}
+
+// record appends an alert for the rule to the history. Caller must hold m.mu.
+func (m *Manager) record(rs *ruleState, state string, now time.Time) Alert {
+	m.nextID++
+	alert := Alert{
+		ID:        fmt.Sprintf("%d-%d", now.UnixMilli(), m.nextID),
+		Rule:      rs.rule.Name,
+		State:     state,
+		Severity:  rs.rule.Severity,
+		Source:    rs.rule.Source(),
+		Count:     len(rs.hits),
+		Threshold: rs.rule.Threshold,
+		Window:    rs.rule.Window.String(),
+		Message:   rs.message(),
+		Sample:    rs.sample,
+		Timestamp: now,
+	}
+	if state == events.AlertStateResolved {
+		alert.FiredAt = rs.firedAt
+	}
+
+	m.history = append(m.history, alert)
+	if len(m.history) > m.maxHistory {
+		m.history = m.history[len(m.history)-m.maxHistory:]
+	}
+	return alert
+}
+
+// publish emits an event for each alert.
+func (m *Manager) publish(alerts []Alert) {
+	for _, alert := range alerts {
+		if alert.State == events.AlertStateFiring {
+			log.Printf("Alert %s firing: %s", alert.Rule, alert.Message)
+		} else {
+			log.Printf("Alert %s resolved", alert.Rule)
+		}
+		if m.bus == nil {
+			continue
+		}
+		payload := map[string]interface{}{
+			"alert_id":  alert.ID,
+			"rule":      alert.Rule,
+			"state":     alert.State,
+			"severity":  alert.Severity,
+			"source":    alert.Source,
+			"count":     alert.Count,
+			"threshold": alert.Threshold,
+			"window":    alert.Window,
+			"message":   alert.Message,
+			"sample":    alert.Sample,
+		}
+		if !alert.FiredAt.IsZero() {
+			payload["fired_at"] = alert.FiredAt
+		}
+		if err := m.bus.Publish(context.Background(), events.Event{
+			Type:    events.EventLogAlert,
+			Payload: payload,
+		}); err != nil {
+			log.Printf("Failed to publish %s event: %v", events.EventLogAlert, err)
+		}
+	}
+}
+
+// prune drops matches that have aged out of the window.
+func (rs *ruleState) prune(now time.Time) {
+	cutoff := now.Add(-rs.rule.Window)
+	i := 0
+	for i < len(rs.hits) && !rs.hits[i].After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		rs.hits = append(rs.hits[:0], rs.hits[i:]...)
+	}
+}
+
+// message returns the rule's configured message or a generated summary.
+func (rs *ruleState) message() string {
+	if rs.rule.Message != "" {
+		return rs.rule.Message
+	}
+	if rs.rule.Threshold == 1 {
+		return fmt.Sprintf("%s matched %q", rs.rule.Source(), rs.rule.Query)
+	}
+	return fmt.Sprintf("%s: %d entries matching %q in %s", rs.rule.Source(), len(rs.hits), rs.rule.Query, rs.rule.Window)
+}
+
+// sampleLine returns a truncated copy of an entry's original line.
+func sampleLine(entry logs.LogEntry) string {
+	line := entry.Raw
+	if line == "" {
+		line = entry.Message
+	}
+	if len(line) > maxSampleLength {
+		cut := maxSampleLength
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		line = line[:cut] + "…"
+	}
+	return line
+}
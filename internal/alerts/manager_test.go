@@ -0,0 +1,188 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/logs"
+)
+
+// fakeClock is a manually advanced clock for driving rule evaluation.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestManager(t *testing.T, cfgs ...config.AlertRuleConfig) (*Manager, *fakeClock, *events.MemoryEventBus) {
+	t.Helper()
+	rules, err := CompileRules(cfgs)
+	require.NoError(t, err)
+	bus := events.NewMemoryEventBus(events.MemoryBusConfig{})
+	t.Cleanup(func() { bus.Close() })
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewManager(rules, nil, nil, bus)
+	m.now = clock.Now
+	return m, clock, bus
+}
+
+func alertEvents(t *testing.T, bus *events.MemoryEventBus) []events.Event {
+	t.Helper()
+	evts, err := bus.History(events.EventFilter{Types: []string{events.EventLogAlert}})
+	require.NoError(t, err)
+	return evts
+}
+
+func TestCompileRules(t *testing.T) {
+	rules, err := CompileRules([]config.AlertRuleConfig{{Name: "a", Viewer: "api", Filter: "level:error"}})
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, 1, rules[0].Threshold)
+	assert.Equal(t, time.Minute, rules[0].Window)
+	assert.Equal(t, 5*time.Minute, rules[0].Cooldown)
+	assert.Equal(t, SeverityWarning, rules[0].Severity)
+	assert.Equal(t, "api", rules[0].Source())
+
+	_, err = CompileRules([]config.AlertRuleConfig{{Name: "bad", Viewer: "api", Filter: "status:in(1,2"}})
+	assert.ErrorContains(t, err, "alert bad")
+
+	_, err = CompileRules([]config.AlertRuleConfig{{Name: "empty", Service: "api", Filter: " "}})
+	assert.ErrorContains(t, err, "filter is required")
+}
+
+func TestThresholdFiresAndResolves(t *testing.T) {
+	m, clock, bus := newTestManager(t, config.AlertRuleConfig{
+		Name: "errors", Viewer: "api", Filter: "level:error", Threshold: 3, Window: "1m", Cooldown: "0s",
+	})
+	rules := m.rules
+
+	// Non-matching entries don't count
+	m.handleEntry(rules, logs.LogEntry{Level: logs.LevelInfo, Raw: "ok"})
+	m.handleEntry(rules, logs.LogEntry{Level: logs.LevelError, Raw: "boom 1"})
+	clock.Advance(10 * time.Second)
+	m.handleEntry(rules, logs.LogEntry{Level: logs.LevelError, Raw: "boom 2"})
+	assert.Empty(t, alertEvents(t, bus))
+
+	clock.Advance(10 * time.Second)
+	m.handleEntry(rules, logs.LogEntry{Level: logs.LevelError, Raw: "boom 3"})
+	evts := alertEvents(t, bus)
+	require.Len(t, evts, 1)
+	assert.Equal(t, events.AlertStateFiring, evts[0].Payload["state"])
+	assert.Equal(t, "errors", evts[0].Payload["rule"])
+	assert.Equal(t, 3, evts[0].Payload["count"])
+	assert.Equal(t, "boom 3", evts[0].Payload["sample"])
+
+	status := m.Rules()
+	assert.Equal(t, events.AlertStateFiring, status[0].State)
+	assert.Equal(t, 3, status[0].Count)
+
+	// Still breached: no duplicate firing
+	m.handleEntry(rules, logs.LogEntry{Level: logs.LevelError, Raw: "boom 4"})
+	m.evaluate()
+	assert.Len(t, alertEvents(t, bus), 1)
+
+	// The first match ages out and the count drops to 3, still firing; once
+	// the window passes with no new matches the rule resolves.
+	clock.Advance(45 * time.Second)
+	m.evaluate()
+	assert.Len(t, alertEvents(t, bus), 1)
+	clock.Advance(time.Minute)
+	m.evaluate()
+	evts = alertEvents(t, bus)
+	require.Len(t, evts, 2)
+	assert.Equal(t, events.AlertStateResolved, evts[1].Payload["state"])
+	assert.Contains(t, evts[1].Payload, "fired_at")
+
+	history := m.History(0)
+	require.Len(t, history, 2)
+	assert.Equal(t, events.AlertStateResolved, history[0].State)
+	assert.Equal(t, events.AlertStateFiring, history[1].State)
+	assert.Equal(t, StateOK, m.Rules()[0].State)
+}
+
+func TestCooldownHoldsBackRefiring(t *testing.T) {
+	m, clock, bus := newTestManager(t, config.AlertRuleConfig{
+		Name: "deadlock", Service: "db", Filter: `"deadlock detected"`, Window: "10s", Cooldown: "5m",
+	})
+	rules := m.rules
+	match := logs.LogEntry{Message: "ERROR: deadlock detected", Raw: "ERROR: deadlock detected"}
+
+	m.handleEntry(rules, match)
+	require.Len(t, alertEvents(t, bus), 1)
+
+	clock.Advance(time.Minute)
+	m.evaluate()
+	require.Len(t, alertEvents(t, bus), 2) // resolved
+
+	// Matches inside the cooldown don't fire again
+	m.handleEntry(rules, match)
+	m.handleEntry(rules, match)
+	assert.Len(t, alertEvents(t, bus), 2)
+	assert.Equal(t, 1, m.Rules()[0].Suppressed)
+
+	// Once the cooldown expires a still-breached rule fires
+	clock.Advance(4*time.Minute + 5*time.Second)
+	m.handleEntry(rules, match)
+	evts := alertEvents(t, bus)
+	require.Len(t, evts, 3)
+	assert.Equal(t, events.AlertStateFiring, evts[2].Payload["state"])
+	assert.Equal(t, "service:db", evts[2].Payload["source"])
+}
+
+func TestStaleEntriesIgnored(t *testing.T) {
+	m, clock, bus := newTestManager(t, config.AlertRuleConfig{Name: "errors", Viewer: "api", Filter: "level:error"})
+
+	// Backlog replayed on startup is older than the window
+	m.handleEntry(m.rules, logs.LogEntry{Timestamp: clock.Now().Add(-time.Hour), Level: logs.LevelError})
+	assert.Empty(t, alertEvents(t, bus))
+
+	m.handleEntry(m.rules, logs.LogEntry{Timestamp: clock.Now(), Level: logs.LevelError})
+	assert.Len(t, alertEvents(t, bus), 1)
+}
+
+func TestWatchViewer(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(logPath, nil, 0o644))
+
+	logManager := logs.NewManager(nil, config.LogViewerSettings{})
+	require.NoError(t, logManager.Initialize([]config.LogViewerConfig{{
+		Name:   "app",
+		Source: config.LogSourceConfig{Type: "file", Path: logPath},
+		Parser: config.LogParserConfig{Type: "json", Timestamp: "time", Level: "level", Message: "msg"},
+	}}))
+	require.NoError(t, logManager.Start(t.Context()))
+	defer logManager.Stop()
+
+	rules, err := CompileRules([]config.AlertRuleConfig{{Name: "errors", Viewer: "app", Filter: "level:error"}})
+	require.NoError(t, err)
+	bus := events.NewMemoryEventBus(events.MemoryBusConfig{})
+	defer bus.Close()
+	m := NewManager(rules, logManager, nil, bus)
+	require.NoError(t, m.Start())
+	defer m.Close()
+
+	viewer, _ := logManager.Get("app")
+	require.Eventually(t, viewer.IsRunning, 5*time.Second, 20*time.Millisecond)
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"` + time.Now().Format(time.RFC3339) + `","level":"error","msg":"boom"}` + "\n")
+	require.NoError(t, err)
+	f.Close()
+
+	require.Eventually(t, func() bool {
+		return len(m.History(0)) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, "app", m.History(0)[0].Source)
+}
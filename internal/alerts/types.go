@@ -0,0 +1,77 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alerts evaluates log-based alert rules and publishes log.alert
+// events when they fire or resolve.
+package alerts
+
+import (
+	"time"
+
+	"github.com/wingedpig/trellis/internal/logs"
+)
+
+// Severity levels for alert rules.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Rule is a compiled alert rule. Exactly one of Viewer or Service is set.
+type Rule struct {
+	Name      string
+	Viewer    string        // Log viewer to watch
+	Service   string        // Service whose log buffer to watch
+	Query     string        // Filter expression as configured
+	Filter    *logs.Filter  // Compiled filter
+	Threshold int           // Matches within Window needed to fire
+	Window    time.Duration // Sliding window for counting matches
+	Cooldown  time.Duration // Minimum time between firings
+	Severity  string
+	Message   string
+}
+
+// Source returns a display name for what the rule watches.
+func (r Rule) Source() string {
+	if r.Service != "" {
+		return "service:" + r.Service
+	}
+	return r.Viewer
+}
+
+// RuleStatus is the current state of a rule.
+type RuleStatus struct {
+	Name       string    `json:"name"`
+	Source     string    `json:"source"`
+	Filter     string    `json:"filter"`
+	Threshold  int       `json:"threshold"`
+	Window     string    `json:"window"`
+	Cooldown   string    `json:"cooldown"`
+	Severity   string    `json:"severity"`
+	Message    string    `json:"message,omitempty"`
+	State      string    `json:"state"`                // "ok" or "firing"
+	Count      int       `json:"count"`                // Matches currently inside the window
+	FiredAt    time.Time `json:"fired_at,omitzero"`    // When the current or most recent firing started
+	LastMatch  time.Time `json:"last_match,omitzero"`  // When the most recent matching entry arrived
+	Suppressed int       `json:"suppressed,omitempty"` // Firings held back by the cooldown
+}
+
+// Alert records one firing or resolution of a rule.
+type Alert struct {
+	ID        string    `json:"id"`
+	Rule      string    `json:"rule"`
+	State     string    `json:"state"` // "firing" or "resolved"
+	Severity  string    `json:"severity"`
+	Source    string    `json:"source"`
+	Count     int       `json:"count"`
+	Threshold int       `json:"threshold"`
+	Window    string    `json:"window"`
+	Message   string    `json:"message"`
+	Sample    string    `json:"sample,omitempty"` // Most recent matching line
+	Timestamp time.Time `json:"timestamp"`
+	FiredAt   time.Time `json:"fired_at,omitzero"` // For resolutions, when the alert fired
+}
+
+// StateOK is the state of a rule that is not firing.
+const StateOK = "ok"
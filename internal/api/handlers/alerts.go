@@ -0,0 +1,52 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/wingedpig/trellis/internal/alerts"
+)
+
+// AlertHandler handles log alert API requests.
+type AlertHandler struct {
+	manager *alerts.Manager
+}
+
+// NewAlertHandler creates a new alert handler. manager may be nil when no
+// alert rules are configured.
+func NewAlertHandler(manager *alerts.Manager) *AlertHandler {
+	return &AlertHandler{manager: manager}
+}
+
+// Rules returns every alert rule with its current state.
+// GET /api/v1/alerts
+func (h *AlertHandler) Rules(w http.ResponseWriter, r *http.Request) {
+	rules := []alerts.RuleStatus{}
+	if h.manager != nil {
+		rules = h.manager.Rules()
+	}
+	WriteJSON(w, http.StatusOK, rules)
+}
+
+// History returns recent alert firings and resolutions, newest first.
+// GET /api/v1/alerts/history
+func (h *AlertHandler) History(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		limit = n
+	}
+
+	history := []alerts.Alert{}
+	if h.manager != nil {
+		history = h.manager.History(limit)
+	}
+	WriteJSON(w, http.StatusOK, history)
+}
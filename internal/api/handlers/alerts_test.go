@@ -0,0 +1,55 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wingedpig/trellis/internal/alerts"
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestAlertHandler(t *testing.T) {
+	rules, err := alerts.CompileRules([]config.AlertRuleConfig{{Name: "errors", Viewer: "api", Filter: "level:error"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewAlertHandler(alerts.NewManager(rules, nil, nil, nil))
+
+	rec := httptest.NewRecorder()
+	h.Rules(rec, httptest.NewRequest("GET", "/api/v1/alerts", nil))
+	var resp struct {
+		Data []alerts.RuleStatus `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Name != "errors" || resp.Data[0].State != alerts.StateOK {
+		t.Errorf("unexpected rules: %+v", resp.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	h.History(rec, httptest.NewRequest("GET", "/api/v1/alerts/history?limit=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative limit: status = %d, want 400", rec.Code)
+	}
+
+	// Without configured rules both endpoints return empty lists
+	empty := NewAlertHandler(nil)
+	rec = httptest.NewRecorder()
+	empty.History(rec, httptest.NewRequest("GET", "/api/v1/alerts/history", nil))
+	if rec.Code != http.StatusOK || !json.Valid(rec.Body.Bytes()) {
+		t.Errorf("nil manager: status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var history struct {
+		Data []alerts.Alert `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &history)
+	if history.Data == nil {
+		t.Errorf("nil manager: history should be an empty list, got %s", rec.Body.String())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -20,6 +22,7 @@ import (
 	"github.com/wingedpig/trellis/internal/codex"
 	"github.com/wingedpig/trellis/internal/genai"
 	"github.com/wingedpig/trellis/internal/trace"
+	"github.com/wingedpig/trellis/internal/workflow"
 	"github.com/wingedpig/trellis/internal/worktree"
 )
 
@@ -50,6 +53,7 @@ type CaseHandler struct {
 	codexMgr    *codex.Manager
 	traceMgr    *trace.Manager
 	worktreeMgr worktree.Manager
+	history     *workflow.History
 }
 
 // NewCaseHandler creates a new case handler.
@@ -63,6 +67,12 @@ func NewCaseHandler(caseMgr *cases.Manager, claudeMgr *claude.Manager, codexMgr
 	}
 }
 
+// SetWorkflowHistory sets the store of workflow runs whose artifacts can be
+// attached to cases.
+func (h *CaseHandler) SetWorkflowHistory(history *workflow.History) {
+	h.history = history
+}
+
 // resolveWorktree looks up worktree info from the URL parameter.
 func (h *CaseHandler) resolveWorktree(r *http.Request) (worktree.WorktreeInfo, bool) {
 	vars := mux.Vars(r)
@@ -342,6 +352,82 @@ func (h *CaseHandler) AttachEvidence(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusCreated, ev)
 }
 
+// AttachArtifact attaches an artifact of a recorded workflow run to a case
+// as evidence.
+func (h *CaseHandler) AttachArtifact(w http.ResponseWriter, r *http.Request) {
+	wt, ok := h.resolveWorktree(r)
+	if !ok {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "worktree not found")
+		return
+	}
+
+	vars := mux.Vars(r)
+	caseID := vars["id"]
+
+	var body struct {
+		RunID string   `json:"run_id"`
+		Path  string   `json:"path"`
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid JSON")
+		return
+	}
+	if body.RunID == "" || body.Path == "" {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "run_id and path are required")
+		return
+	}
+
+	if h.history == nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "workflow history not available")
+		return
+	}
+	run, err := h.history.Run(body.RunID)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, fmt.Sprintf("workflow run not found: %s", body.RunID))
+		return
+	}
+	found := false
+	for _, a := range run.Artifacts {
+		found = found || a.Path == body.Path
+	}
+	file, err := h.history.ArtifactFile(body.RunID, body.Path)
+	if !found || err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, fmt.Sprintf("artifact not found: %s", body.Path))
+		return
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	defer f.Close()
+
+	title := body.Title
+	if title == "" {
+		title = run.Name + ": " + body.Path
+	}
+	ext := path.Ext(body.Path)
+	if ext != "" {
+		ext = ext[1:] // strip leading dot
+	}
+	ev := cases.CaseEvidence{
+		Title:    title,
+		Filename: path.Base(body.Path),
+		Format:   ext,
+		Tags:     body.Tags,
+		AddedAt:  time.Now(),
+	}
+
+	if err := h.caseMgr.AttachEvidence(wt.Path, caseID, ev, f); err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, ev)
+}
+
 // SaveTranscript exports a Claude session transcript and saves it to a case.
 func (h *CaseHandler) SaveTranscript(w http.ResponseWriter, r *http.Request) {
 	wt, ok := h.resolveWorktree(r)
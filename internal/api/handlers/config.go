@@ -0,0 +1,53 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// ConfigHandler handles config API requests.
+type ConfigHandler struct {
+	document func() *config.Document
+}
+
+// NewConfigHandler creates a new config handler. document returns the
+// layered config the server is running with; it may be nil, or return nil,
+// when the server wasn't started from a config file.
+func NewConfigHandler(document func() *config.Document) *ConfigHandler {
+	return &ConfigHandler{document: document}
+}
+
+// ConfigResponse is the response from the config endpoint.
+type ConfigResponse struct {
+	Layers []config.Layer `json:"layers"`           // Files the config was assembled from, lowest precedence first
+	Values []config.Value `json:"values,omitempty"` // Every setting and its origin, when effective values were requested
+}
+
+// Show returns the files the config was assembled from and, with
+// effective=1, every setting of the merged config with the layer that set it.
+// GET /api/v1/config?effective=1
+func (h *ConfigHandler) Show(w http.ResponseWriter, r *http.Request) {
+	var doc *config.Document
+	if h.document != nil {
+		doc = h.document()
+	}
+	if doc == nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "no config file loaded")
+		return
+	}
+
+	resp := ConfigResponse{Layers: doc.Layers()}
+	if r.URL.Query().Get("effective") == "1" {
+		values, err := doc.Effective()
+		if err != nil {
+			WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+			return
+		}
+		resp.Values = values
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,66 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestConfigHandler(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trellis.hjson")
+	if err := os.WriteFile(path, []byte(`{ version: "1.0", server: { port: 4000 } }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.LocalConfigPath(path), []byte(`{ server: { port: 5000 } }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := (&config.Loader{}).LoadDocument(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewConfigHandler(nil).Show(rec, httptest.NewRequest("GET", "/api/v1/config", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("no document: status = %d, want 404", rec.Code)
+	}
+
+	h := NewConfigHandler(func() *config.Document { return doc })
+	var resp struct {
+		Data ConfigResponse `json:"data"`
+	}
+	rec = httptest.NewRecorder()
+	h.Show(rec, httptest.NewRequest("GET", "/api/v1/config", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Data.Layers) != 2 || resp.Data.Layers[1].Kind != config.LayerLocal || resp.Data.Values != nil {
+		t.Errorf("layers = %+v, values = %+v", resp.Data.Layers, resp.Data.Values)
+	}
+
+	rec = httptest.NewRecorder()
+	h.Show(rec, httptest.NewRequest("GET", "/api/v1/config?effective=1", nil))
+	resp.Data = ConfigResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var port *config.Value
+	for i, v := range resp.Data.Values {
+		if v.Path == "server.port" {
+			port = &resp.Data.Values[i]
+		}
+	}
+	if port == nil || port.Value != float64(5000) || port.Origin.Kind != config.LayerLocal {
+		t.Errorf("server.port = %+v", port)
+	}
+}
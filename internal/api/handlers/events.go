@@ -85,12 +85,14 @@ func (h *upgraderHolder) ws() *websocket.Upgrader {
 // EventHandler handles event-related API requests.
 type EventHandler struct {
 	upgraderHolder
-	bus events.EventBus
+	bus      events.EventBus
+	webhooks *events.WebhookDispatcher
 }
 
-// NewEventHandler creates a new event handler.
-func NewEventHandler(bus events.EventBus) *EventHandler {
-	return &EventHandler{bus: bus}
+// NewEventHandler creates a new event handler. webhooks may be nil when no
+// webhooks are configured.
+func NewEventHandler(bus events.EventBus, webhooks *events.WebhookDispatcher) *EventHandler {
+	return &EventHandler{bus: bus, webhooks: webhooks}
 }
 
 // History returns the event history.
@@ -130,6 +132,10 @@ func (h *EventHandler) History(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Full-text search and pagination cursor
+	filter.Search = query.Get("q")
+	filter.Before = query.Get("before")
+
 	eventList, err := h.bus.History(filter)
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
@@ -139,6 +145,22 @@ func (h *EventHandler) History(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, eventList)
 }
 
+// WebhookDeliveries returns the recent webhook delivery log, newest first.
+func (h *EventHandler) WebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries := []events.WebhookDelivery{}
+	if h.webhooks != nil {
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			if n, err := strconv.Atoi(limitStr); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		deliveries = h.webhooks.Deliveries(limit)
+	}
+
+	WriteJSON(w, http.StatusOK, deliveries)
+}
+
 // WebSocket handles the WebSocket connection for real-time events.
 func (h *EventHandler) WebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := h.ws().Upgrade(w, r, nil)
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 	"github.com/wingedpig/trellis/internal/config"
 	"github.com/wingedpig/trellis/internal/events"
 	"github.com/wingedpig/trellis/internal/logs"
+	"github.com/wingedpig/trellis/internal/secrets"
 	"github.com/wingedpig/trellis/internal/service"
 	"github.com/wingedpig/trellis/internal/terminal"
 	"github.com/wingedpig/trellis/internal/workflow"
@@ -128,6 +131,20 @@ func (m *mockServiceManager) ParsedLogs(name string, lines int) ([]*logs.LogEntr
 	return nil, nil
 }
 
+func (m *mockServiceManager) LogsBefore(name string, seq int64, lines int) ([]service.LogLine, error) {
+	if _, ok := m.services[name]; !ok {
+		return nil, &serviceNotFoundError{name: name}
+	}
+	return nil, nil
+}
+
+func (m *mockServiceManager) LogsRange(name string, start, end time.Time, lines int) ([]service.LogLine, error) {
+	if _, ok := m.services[name]; !ok {
+		return nil, &serviceNotFoundError{name: name}
+	}
+	return nil, nil
+}
+
 func (m *mockServiceManager) HasParser(name string) bool {
 	return false
 }
@@ -324,6 +341,15 @@ func (m *mockWorkflowRunner) Close() error {
 func (m *mockWorkflowRunner) UpdateConfig(workflows []workflow.WorkflowConfig, worktree string) {
 }
 
+func (m *mockWorkflowRunner) SetSecrets(sm *secrets.Manager) {
+}
+
+func (m *mockWorkflowRunner) SetHistory(h *workflow.History) {
+}
+
+func (m *mockWorkflowRunner) SetParsers(parsers []workflow.OutputParser) {
+}
+
 type workflowNotFoundError struct {
 	id string
 }
@@ -537,6 +563,65 @@ func TestServiceHandler_Logs_WithLines(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestServiceHandler_Logs_Older(t *testing.T) {
+	handler := NewServiceHandler(newMockServiceManager())
+
+	tests := []struct {
+		name  string
+		query string
+		code  int
+	}{
+		{"api", "before=5", http.StatusOK},
+		{"api", "since=2026-01-01T00:00:00Z&until=2026-01-02T00:00:00Z", http.StatusOK},
+		{"api", "before=abc", http.StatusBadRequest},
+		{"api", "since=yesterday", http.StatusBadRequest},
+		{"nonexistent", "before=5", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/services/"+tt.name+"/logs?"+tt.query, nil)
+		req = mux.SetURLVars(req, map[string]string{"name": tt.name})
+		rec := httptest.NewRecorder()
+
+		handler.Logs(rec, req)
+
+		assert.Equal(t, tt.code, rec.Code, tt.query)
+	}
+}
+
+// newTestServicePool returns a pool whose worktree managers run no
+// services.
+func newTestServicePool() *service.Pool {
+	return service.NewPool(func(name string) (*service.ServiceManager, error) {
+		return service.NewManager(nil, newMockEventBus(), nil), nil
+	})
+}
+
+func TestServiceHandler_Worktree(t *testing.T) {
+	pool := newTestServicePool()
+	handler := NewServiceHandler(newMockServiceManager())
+	handler.SetWorktreeServices(newMockWorktreeManager(), pool)
+
+	list := func(worktree string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/services?worktree="+worktree, nil)
+		rec := httptest.NewRecorder()
+		handler.List(rec, req)
+		return rec
+	}
+
+	// The active worktree's name addresses the main services
+	rec := list("main")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"api"`)
+
+	assert.Equal(t, http.StatusNotFound, list("feature-x").Code)
+	assert.Equal(t, http.StatusNotFound, list("unknown").Code)
+
+	require.NoError(t, pool.Start(context.Background(), "feature"))
+	rec = list("feature-x")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"api"`)
+}
+
 func TestWorktreeHandler_List(t *testing.T) {
 	handler := NewWorktreeHandler(newMockWorktreeManager())
 
@@ -655,6 +740,41 @@ func TestWorktreeHandler_Get_NotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
 
+func TestWorktreeHandler_Services(t *testing.T) {
+	ports, err := worktree.NewPortAllocator("", 20000, 20099)
+	require.NoError(t, err)
+	handler := NewWorktreeHandler(newMockWorktreeManager())
+	handler.SetWorktreeServices(newTestServicePool(), ports)
+
+	call := func(action, name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/worktrees/"+name+"/services/"+action, nil)
+		req = mux.SetURLVars(req, map[string]string{"name": name})
+		rec := httptest.NewRecorder()
+		if action == "start" {
+			handler.StartServices(rec, req)
+		} else {
+			handler.StopServices(rec, req)
+		}
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, call("start", "main").Code)
+	assert.Equal(t, http.StatusNotFound, call("start", "unknown").Code)
+
+	rec := call("start", "feature-x")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp struct {
+		Data struct {
+			Worktree WorktreeResponse `json:"worktree"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.Worktree.Services)
+
+	assert.Equal(t, http.StatusOK, call("stop", "feature-x").Code)
+	assert.Equal(t, http.StatusNotFound, call("stop", "feature-x").Code)
+}
+
 func TestWorktreeHandler_Activate(t *testing.T) {
 	handler := NewWorktreeHandler(newMockWorktreeManager())
 
@@ -833,8 +953,111 @@ func TestWorkflowHandler_Status(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestWorkflowHandler_History(t *testing.T) {
+	handler := NewWorkflowHandler(newMockWorkflowRunner(), nil)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/test/runs", nil), map[string]string{"id": "test"})
+	rec := httptest.NewRecorder()
+	handler.History(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	history, err := workflow.NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	handler.SetHistory(history)
+	start := time.Now().Add(-time.Hour)
+	for i, failed := range [][]string{{"pkg.TestA"}, nil} {
+		require.NoError(t, history.Record(&workflow.WorkflowStatus{
+			ID:         fmt.Sprintf("test-%d", i),
+			WorkflowID: "test",
+			Commit:     "abc",
+			State:      workflow.StateSuccess,
+			StartedAt:  start.Add(time.Duration(i) * time.Minute),
+			Output:     "ok\n",
+			Summary:    &workflow.WorkflowSummary{TestsPassed: 2, TestsFailed: len(failed), FailedTests: failed},
+		}))
+	}
+
+	rec = httptest.NewRecorder()
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/test/runs?limit=1", nil), map[string]string{"id": "test"})
+	handler.History(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var runs struct{ Data []workflow.WorkflowStatus }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &runs))
+	require.Len(t, runs.Data, 1)
+	assert.Equal(t, "test-1", runs.Data[0].ID)
+	assert.Empty(t, runs.Data[0].Output)
+
+	rec = httptest.NewRecorder()
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/test/runs/test-0", nil), map[string]string{"id": "test", "runID": "test-0"})
+	handler.HistoryRun(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"Output":"ok\n"`)
+
+	// Runs are only found under their own workflow
+	rec = httptest.NewRecorder()
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/build/runs/test-0", nil), map[string]string{"id": "build", "runID": "test-0"})
+	handler.HistoryRun(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/test/tests", nil), map[string]string{"id": "test"})
+	handler.Tests(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var timeline struct{ Data workflow.TestTimeline }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &timeline))
+	require.Len(t, timeline.Data.Tests, 1)
+	assert.Equal(t, []workflow.TestResult{workflow.TestFailed, workflow.TestPassed}, timeline.Data.Tests[0].Results)
+
+	rec = httptest.NewRecorder()
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/workflows/test/flaky", nil), map[string]string{"id": "test"})
+	handler.Flaky(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var flaky struct{ Data []workflow.FlakyTest }
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &flaky))
+	require.Len(t, flaky.Data, 1)
+	assert.Equal(t, "pkg.TestA", flaky.Data[0].Test)
+}
+
+func TestWorkflowHandler_Artifact(t *testing.T) {
+	handler := NewWorkflowHandler(newMockWorkflowRunner(), nil)
+	history, err := workflow.NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	handler.SetHistory(history)
+
+	work := t.TempDir()
+	start := time.Now().Add(-time.Minute)
+	require.NoError(t, os.MkdirAll(filepath.Join(work, "dist"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(work, "dist", "app.js"), []byte("app"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(work, "secret.txt"), []byte("secret"), 0644))
+	artifacts, err := history.CollectArtifacts("build-0", work, []string{"dist"}, start, "")
+	require.NoError(t, err)
+	require.NoError(t, history.Record(&workflow.WorkflowStatus{
+		ID:         "build-0",
+		WorkflowID: "build",
+		State:      workflow.StateSuccess,
+		StartedAt:  start,
+		Artifacts:  artifacts,
+	}))
+
+	get := func(id, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/v1/workflows/"+id+"/runs/build-0/artifacts/"+path, nil)
+		handler.Artifact(rec, mux.SetURLVars(req, map[string]string{"id": id, "runID": "build-0", "path": path}))
+		return rec
+	}
+
+	rec := get("build", "dist/app.js")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "app", rec.Body.String())
+	assert.Equal(t, "attachment; filename=app.js", rec.Header().Get("Content-Disposition"))
+
+	assert.Equal(t, http.StatusNotFound, get("test", "dist/app.js").Code)
+	assert.Equal(t, http.StatusNotFound, get("build", "secret.txt").Code)
+	assert.Equal(t, http.StatusNotFound, get("build", "../build-0.json").Code)
+}
+
 func TestEventHandler_History(t *testing.T) {
-	handler := NewEventHandler(newMockEventBus())
+	handler := NewEventHandler(newMockEventBus(), nil)
 
 	req := httptest.NewRequest("GET", "/api/v1/events", nil)
 	rec := httptest.NewRecorder()
@@ -845,7 +1068,7 @@ func TestEventHandler_History(t *testing.T) {
 }
 
 func TestEventHandler_History_WithFilters(t *testing.T) {
-	handler := NewEventHandler(newMockEventBus())
+	handler := NewEventHandler(newMockEventBus(), nil)
 
 	req := httptest.NewRequest("GET", "/api/v1/events?type=service.started&limit=10", nil)
 	rec := httptest.NewRecorder()
@@ -855,6 +1078,18 @@ func TestEventHandler_History_WithFilters(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestEventHandler_WebhookDeliveries_NoWebhooks(t *testing.T) {
+	handler := NewEventHandler(newMockEventBus(), nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/events/webhooks/deliveries", nil)
+	rec := httptest.NewRecorder()
+
+	handler.WebhookDeliveries(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"data":[]`)
+}
+
 func TestTerminalHandler_ListSessions(t *testing.T) {
 	handler := NewTerminalHandler(newMockTerminalManager(), nil)
 
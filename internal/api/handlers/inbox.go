@@ -77,8 +77,8 @@ func (h *InboxHandler) WebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// serveInbox forwards session.state_changed events to the inbox window and
-// fans incoming navigate commands out to all connected main-window clients.
+// serveInbox forwards session.state_changed and log.alert events to the
+// inbox window and fans incoming navigate commands out to all connected main-window clients.
 func (h *InboxHandler) serveInbox(w http.ResponseWriter, r *http.Request) {
 	conn, err := h.ws().Upgrade(w, r, nil)
 	if err != nil {
@@ -122,6 +122,13 @@ func (h *InboxHandler) serveInbox(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer h.bus.Unsubscribe(activitySub)
+	// Firing log alerts are listed above the sessions until they resolve.
+	alertSub, err := h.bus.SubscribeAsync(events.EventLogAlert, forward, 256)
+	if err != nil {
+		_ = writeJSON(inboxServerMsg{Type: "error", Reason: err.Error()})
+		return
+	}
+	defer h.bus.Unsubscribe(alertSub)
 
 	conn.SetPongHandler(func(string) error {
 		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -154,8 +161,11 @@ func (h *InboxHandler) serveInbox(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 			msgType := "state_changed"
-			if ev.Type == events.EventSessionActivity {
+			switch ev.Type {
+			case events.EventSessionActivity:
 				msgType = "activity"
+			case events.EventLogAlert:
+				msgType = "alert"
 			}
 			if err := writeJSON(inboxServerMsg{Type: msgType, Event: payload}); err != nil {
 				return
@@ -338,6 +338,130 @@ func (h *LogHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// defaultStatsTop is the number of groups returned by Stats when the
+// request doesn't specify top.
+const defaultStatsTop = 10
+
+// Stats aggregates log entries: counts and numeric summaries grouped by a
+// field, plus an optional time-bucketed histogram.
+//
+// With source=buffer (the default) the viewer's buffer is aggregated, using
+// optional start/end bounds. With source=history the rotated files between
+// the required start and end are scanned, as in GetHistory.
+func (h *LogHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+
+	query := r.URL.Query()
+	source := query.Get("source")
+	if source == "" {
+		source = "buffer"
+	}
+	if source != "buffer" && source != "history" {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid source: expected buffer or history")
+		return
+	}
+
+	statsQuery := logs.StatsQuery{
+		GroupBy: query.Get("group_by"),
+		Field:   query.Get("field"),
+		Top:     defaultStatsTop,
+	}
+
+	if topStr := query.Get("top"); topStr != "" {
+		top, err := strconv.Atoi(topStr)
+		if err != nil || top < 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid top: expected a non-negative integer")
+			return
+		}
+		statsQuery.Top = top
+	}
+
+	// A negative bucket asks the viewer to pick a width from the time span
+	switch bucketStr := query.Get("bucket"); bucketStr {
+	case "":
+	case "auto":
+		statsQuery.Bucket = -1
+	default:
+		d, err := time.ParseDuration(bucketStr)
+		if err != nil || d <= 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid bucket: expected a duration such as 1m, or auto")
+			return
+		}
+		statsQuery.Bucket = d
+	}
+
+	var start, end time.Time
+	if startStr := query.Get("start"); startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid start time")
+			return
+		}
+		start = t
+	}
+	if endStr := query.Get("end"); endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid end time")
+			return
+		}
+		end = t
+	}
+
+	var filter *logs.Filter
+	if filterStr := query.Get("filter"); filterStr != "" {
+		var err error
+		filter, err = logs.ParseFilter(filterStr)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid filter: "+err.Error())
+			return
+		}
+	}
+
+	if source == "buffer" {
+		viewer, err := h.manager.GetAndStart(name)
+		if err != nil {
+			WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+			return
+		}
+		statsQuery.Start, statsQuery.End = start, end
+		WriteJSON(w, http.StatusOK, viewer.BufferStats(filter, statsQuery))
+		return
+	}
+
+	if start.IsZero() || end.IsZero() {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "start and end are required for source=history")
+		return
+	}
+
+	viewer, ok := h.manager.Get(name)
+	if !ok {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "log viewer not found")
+		return
+	}
+	viewer.Touch()
+
+	timeout := defaultHistoryTimeout
+	if timeoutStr := query.Get("timeout"); timeoutStr != "" {
+		if d, err := time.ParseDuration(timeoutStr); err == nil && d > 0 {
+			if d > maxHistoryTimeout {
+				d = maxHistoryTimeout
+			}
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	result, err := viewer.HistoricalStats(ctx, start, end, filter, query.Get("grep"), statsQuery)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrLogViewerError, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, result)
+}
+
 // acceptsNDJSON returns true if the client signalled a preference for
 // newline-delimited JSON via the Accept header.
 func acceptsNDJSON(r *http.Request) bool {
@@ -0,0 +1,85 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/logs"
+)
+
+func TestLogStatsValidation(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(logPath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := logs.NewManager(nil, config.LogViewerSettings{})
+	if err := manager.Initialize([]config.LogViewerConfig{{
+		Name:   "stats-test",
+		Source: config.LogSourceConfig{Type: "file", Path: logPath},
+		Parser: config.LogParserConfig{Type: "json", Timestamp: "time", Level: "level", Message: "msg"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := manager.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/v1/logs/{name}/stats", NewLogHandler(manager).Stats)
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{"group_by=path&bucket=1m", http.StatusOK},
+		{"bucket=auto", http.StatusOK},
+		{"source=bogus", http.StatusBadRequest},
+		{"bucket=soon", http.StatusBadRequest},
+		{"top=-1", http.StatusBadRequest},
+		{"filter=status:in(1,2", http.StatusBadRequest},
+		{"source=history", http.StatusBadRequest},
+		{"start=yesterday", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/v1/logs/stats-test/stats?"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.query, rec.Code, tt.status, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/logs/missing/stats", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown viewer: status = %d, want 404", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/logs/stats-test/stats?group_by=path&field=duration", nil)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	var resp struct {
+		Data logs.StatsResult `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Data.GroupBy != "path" || resp.Data.Field != "duration" || resp.Data.Total != 0 {
+		t.Errorf("unexpected result: %+v", resp.Data)
+	}
+}
@@ -26,6 +26,7 @@ type NavHandler struct {
 	codexMgr      *codex.Manager
 	caseMgr       *cases.Manager
 	worktreeMgr   worktree.Manager
+	servicePool   *service.Pool
 	links         []LinkConfig
 	shortcuts     []ShortcutConfig
 	notifications NotificationConfig
@@ -49,6 +50,12 @@ func NewNavHandler(terminalMgr terminal.Manager, serviceMgr service.Manager, log
 	}
 }
 
+// SetServicePool sets the pool running the services of worktrees other
+// than the active one, so their services are navigable too.
+func (h *NavHandler) SetServicePool(pool *service.Pool) {
+	h.servicePool = pool
+}
+
 // NavTerminal represents a terminal option in navigation.
 type NavTerminal struct {
 	URL      string `json:"url"`
@@ -58,8 +65,9 @@ type NavTerminal struct {
 
 // NavService represents a service option in navigation.
 type NavService struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Worktree string `json:"worktree,omitempty"` // Worktree running the service alongside the active one
 }
 
 // NavLink represents a link option in navigation.
@@ -193,6 +201,21 @@ func (h *NavHandler) Options(w http.ResponseWriter, r *http.Request) {
 			})
 		}
 	}
+	if h.servicePool != nil {
+		for _, name := range h.servicePool.Worktrees() {
+			mgr, ok := h.servicePool.Get(name)
+			if !ok {
+				continue
+			}
+			for _, svc := range mgr.List() {
+				resp.Services = append(resp.Services, NavService{
+					Name:     svc.Name,
+					Status:   svc.Status.State.String(),
+					Worktree: name,
+				})
+			}
+		}
+	}
 
 	// Get links
 	for _, link := range h.links {
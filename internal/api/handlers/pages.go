@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/alerts"
 	"github.com/wingedpig/trellis/internal/cases"
 	"github.com/wingedpig/trellis/internal/claude"
 	"github.com/wingedpig/trellis/internal/codex"
@@ -18,6 +19,7 @@ import (
 	"github.com/wingedpig/trellis/internal/crashes"
 	"github.com/wingedpig/trellis/internal/events"
 	"github.com/wingedpig/trellis/internal/logs"
+	"github.com/wingedpig/trellis/internal/proxy"
 	"github.com/wingedpig/trellis/internal/service"
 	"github.com/wingedpig/trellis/internal/terminal"
 	"github.com/wingedpig/trellis/internal/trace"
@@ -75,6 +77,9 @@ type PageHandler struct {
 	worktrees     worktree.Manager
 	workflows     workflow.Runner
 	eventBus      events.EventBus
+	webhooks      *events.WebhookDispatcher
+	alerts        *alerts.Manager
+	proxy         *proxy.Manager
 	terminals     terminal.Manager
 	logManager    *logs.Manager
 	traceManager  *trace.Manager
@@ -82,6 +87,8 @@ type PageHandler struct {
 	claudeManager *claude.Manager
 	codexManager  *codex.Manager
 	caseManager   *cases.Manager
+	servicePool   *service.Pool
+	history       *workflow.History
 	shortcuts     []ShortcutConfig
 	notifications NotificationConfig
 	links         []LinkConfig
@@ -89,12 +96,15 @@ type PageHandler struct {
 }
 
 // NewPageHandler creates a new page handler.
-func NewPageHandler(services service.Manager, worktrees worktree.Manager, workflows workflow.Runner, eventBus events.EventBus, terminals terminal.Manager, logManager *logs.Manager, traceManager *trace.Manager, crashManager *crashes.Manager, claudeManager *claude.Manager, codexManager *codex.Manager, caseManager *cases.Manager, shortcuts []ShortcutConfig, notifications NotificationConfig, links []LinkConfig, version string) *PageHandler {
+func NewPageHandler(services service.Manager, worktrees worktree.Manager, workflows workflow.Runner, eventBus events.EventBus, webhooks *events.WebhookDispatcher, alertManager *alerts.Manager, proxyManager *proxy.Manager, terminals terminal.Manager, logManager *logs.Manager, traceManager *trace.Manager, crashManager *crashes.Manager, claudeManager *claude.Manager, codexManager *codex.Manager, caseManager *cases.Manager, shortcuts []ShortcutConfig, notifications NotificationConfig, links []LinkConfig, version string) *PageHandler {
 	return &PageHandler{
 		services:      services,
 		worktrees:     worktrees,
 		workflows:     workflows,
 		eventBus:      eventBus,
+		webhooks:      webhooks,
+		alerts:        alertManager,
+		proxy:         proxyManager,
 		terminals:     terminals,
 		logManager:    logManager,
 		traceManager:  traceManager,
@@ -109,6 +119,24 @@ func NewPageHandler(services service.Manager, worktrees worktree.Manager, workfl
 	}
 }
 
+// SetServicePool sets the pool running the services of worktrees other
+// than the active one, so their services show in the status page and the
+// service picker.
+func (h *PageHandler) SetServicePool(pool *service.Pool) {
+	h.servicePool = pool
+}
+
+// SetWorkflowHistory sets the store of past workflow runs shown on the
+// workflow history page.
+func (h *PageHandler) SetWorkflowHistory(history *workflow.History) {
+	h.history = history
+}
+
+// worktreeServices returns the services of each worktree namespace.
+func (h *PageHandler) worktreeServices() worktreeServices {
+	return worktreeServices{main: h.services, worktrees: h.worktrees, pool: h.servicePool}
+}
+
 // Dashboard renders the dashboard page.
 func (h *PageHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
 	services := h.services.List()
@@ -395,6 +423,32 @@ func (h *PageHandler) buildLogViewerList() []views.LogViewerInfo {
 	return logViewers
 }
 
+// toViewServiceInfo converts a service to its picker entry, with the
+// logging configuration for structured log display.
+func toViewServiceInfo(svc service.ServiceInfo, worktree string) views.ServiceInfo {
+	info := views.ServiceInfo{
+		Name:     svc.Name,
+		Status:   svc.Status.State.String(),
+		Worktree: worktree,
+	}
+	if svc.ParserType != "" {
+		info.ParserType = svc.ParserType
+		info.TimestampField = svc.TimestampField
+		info.LevelField = svc.LevelField
+		info.MessageField = svc.MessageField
+		info.FileField = svc.FileField
+		info.LineField = svc.LineField
+	}
+	if len(svc.Layout) > 0 {
+		info.Layout = convertLayout(svc.Layout)
+	}
+	if len(svc.Columns) > 0 {
+		info.Columns = svc.Columns
+		info.ColumnWidths = svc.ColumnWidths
+	}
+	return info
+}
+
 // renderTerminalPage renders the terminal page with the given parameters.
 func (h *PageHandler) renderTerminalPage(w http.ResponseWriter, r *http.Request, viewType, session, window string, isRemote bool, serviceName, worktreeName string, logViewerName ...string) {
 	// Convert shortcuts to view format
@@ -414,37 +468,39 @@ func (h *PageHandler) renderTerminalPage(w http.ResponseWriter, r *http.Request,
 		Sound:        h.notifications.Sound,
 	}
 
+	// A service view may show a service another worktree runs
+	var serviceWorktree string
+	if viewType == "service" {
+		var err error
+		if _, serviceWorktree, err = h.worktreeServices().lookup(r.URL.Query().Get("worktree")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+
 	// Get services list for the picker
 	var services []views.ServiceInfo
 	if h.services != nil {
 		for _, svc := range h.services.List() {
-			info := views.ServiceInfo{
-				Name:   svc.Name,
-				Status: svc.Status.State.String(),
-			}
-			// Include logging configuration for structured log display
-			if svc.ParserType != "" {
-				info.ParserType = svc.ParserType
-				info.TimestampField = svc.TimestampField
-				info.LevelField = svc.LevelField
-				info.MessageField = svc.MessageField
-				info.FileField = svc.FileField
-				info.LineField = svc.LineField
-			}
-			if len(svc.Layout) > 0 {
-				info.Layout = convertLayout(svc.Layout)
-			}
-			if len(svc.Columns) > 0 {
-				info.Columns = svc.Columns
-				info.ColumnWidths = svc.ColumnWidths
+			services = append(services, toViewServiceInfo(svc, ""))
+		}
+	}
+	if h.servicePool != nil {
+		for _, name := range h.servicePool.Worktrees() {
+			if mgr, ok := h.servicePool.Get(name); ok {
+				for _, svc := range mgr.List() {
+					services = append(services, toViewServiceInfo(svc, name))
+				}
 			}
-			services = append(services, info)
 		}
-		// Sort by name
-		sort.Slice(services, func(i, j int) bool {
-			return services[i].Name < services[j].Name
-		})
 	}
+	// Sort by name, the active worktree's services first
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Worktree != services[j].Worktree {
+			return services[i].Worktree < services[j].Worktree
+		}
+		return services[i].Name < services[j].Name
+	})
 
 	// Extract log viewer name from variadic parameter
 	var lvName string
@@ -487,18 +543,19 @@ func (h *PageHandler) renderTerminalPage(w http.ResponseWriter, r *http.Request,
 		BasePage: views.BasePage{
 			Title: title,
 		},
-		Session:       session,
-		Window:        window,
-		IsRemote:      isRemote,
-		ViewType:      viewType,
-		ServiceName:   serviceName,
-		WorktreeName:  worktreeName,
-		LogViewerName: lvName,
-		Shortcuts:     shortcuts,
-		Notifications: notifications,
-		Services:      services,
-		Links:         links,
-		LogViewers:    logViewers,
+		Session:         session,
+		Window:          window,
+		IsRemote:        isRemote,
+		ViewType:        viewType,
+		ServiceName:     serviceName,
+		ServiceWorktree: serviceWorktree,
+		WorktreeName:    worktreeName,
+		LogViewerName:   lvName,
+		Shortcuts:       shortcuts,
+		Notifications:   notifications,
+		Services:        services,
+		Links:           links,
+		LogViewers:      logViewers,
 	}
 
 	if h.worktrees != nil {
@@ -517,9 +574,14 @@ func (h *PageHandler) Status(w http.ResponseWriter, r *http.Request) {
 		active = h.worktrees.Active()
 	}
 
+	mgr, serviceWorktree, err := h.worktreeServices().lookup(r.URL.Query().Get("worktree"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	var services []service.ServiceInfo
-	if h.services != nil {
-		services = h.services.List()
+	if mgr != nil {
+		services = mgr.List()
 	}
 
 	page := &views.StatusPage{
@@ -527,7 +589,11 @@ func (h *PageHandler) Status(w http.ResponseWriter, r *http.Request) {
 			Title:    "Status",
 			Worktree: active,
 		},
-		Services: services,
+		Services:        services,
+		ServiceWorktree: serviceWorktree,
+	}
+	if h.servicePool != nil {
+		page.ServiceWorktrees = h.servicePool.Worktrees()
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -618,13 +684,62 @@ func (h *PageHandler) Workflows(w http.ResponseWriter, r *http.Request) {
 	page.WriteRender(w)
 }
 
+// WorkflowHistory renders a workflow's past runs, test timeline and flaky
+// tests.
+func (h *PageHandler) WorkflowHistory(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if h.history == nil {
+		http.Error(w, "Workflow history not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	wf, ok := h.workflows.Get(id)
+	if !ok {
+		http.Error(w, "Workflow not found", http.StatusNotFound)
+		return
+	}
+
+	var activeWorktree *worktree.WorktreeInfo
+	if h.worktrees != nil {
+		activeWorktree = h.worktrees.Active()
+	}
+
+	page := &views.WorkflowHistoryPage{
+		BasePage: views.BasePage{
+			Title:    "History: " + wf.Name,
+			Worktree: activeWorktree,
+		},
+		WorkflowID:   wf.ID,
+		WorkflowName: wf.Name,
+		Runs:         h.history.Runs(wf.ID, "", 100),
+		Timeline:     h.history.Tests(wf.ID, 50),
+		Flaky:        h.history.Flaky(wf.ID),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.WriteRender(w)
+}
+
 // Events renders the events page.
 func (h *PageHandler) Events(w http.ResponseWriter, r *http.Request) {
+	const pageSize = 100
+	query := r.URL.Query()
+	filter := events.EventFilter{
+		Search: query.Get("q"),
+		Before: query.Get("before"),
+		Limit:  pageSize,
+	}
+
 	var recentEvents []events.Event
 	if h.eventBus != nil {
-		recentEvents, _ = h.eventBus.History(events.EventFilter{
-			Limit: 100,
-		})
+		recentEvents, _ = h.eventBus.History(filter)
+	}
+
+	// A full page may have older events behind it
+	olderCursor := ""
+	if len(recentEvents) == pageSize {
+		olderCursor = recentEvents[0].ID
 	}
 
 	var activeWorktree *worktree.WorktreeInfo
@@ -632,12 +747,80 @@ func (h *PageHandler) Events(w http.ResponseWriter, r *http.Request) {
 		activeWorktree = h.worktrees.Active()
 	}
 
+	var deliveries []events.WebhookDelivery
+	if h.webhooks != nil {
+		deliveries = h.webhooks.Deliveries(50)
+	}
+
 	page := &views.EventsPage{
 		BasePage: views.BasePage{
 			Title:    "Events",
 			Worktree: activeWorktree,
 		},
-		Events: recentEvents,
+		Events:            recentEvents,
+		Search:            filter.Search,
+		OlderCursor:       olderCursor,
+		WebhooksEnabled:   h.webhooks != nil,
+		WebhookDeliveries: deliveries,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.WriteRender(w)
+}
+
+// Alerts renders the log alert rules and history page.
+func (h *PageHandler) Alerts(w http.ResponseWriter, r *http.Request) {
+	var rules []alerts.RuleStatus
+	var history []alerts.Alert
+	if h.alerts != nil {
+		rules = h.alerts.Rules()
+		history = h.alerts.History(200)
+	}
+
+	var activeWorktree *worktree.WorktreeInfo
+	if h.worktrees != nil {
+		activeWorktree = h.worktrees.Active()
+	}
+
+	page := &views.AlertsPage{
+		BasePage: views.BasePage{
+			Title:    "Alerts",
+			Worktree: activeWorktree,
+		},
+		Rules:   rules,
+		History: history,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	page.WriteRender(w)
+}
+
+// Proxy renders the captured proxy traffic page.
+func (h *PageHandler) Proxy(w http.ResponseWriter, r *http.Request) {
+	var listeners []proxy.ListenerInfo
+	if h.proxy != nil {
+		listeners = h.proxy.Listeners()
+	}
+
+	var activeWorktree *worktree.WorktreeInfo
+	var worktreeNames []string
+	if h.worktrees != nil {
+		activeWorktree = h.worktrees.Active()
+		wts, _ := h.worktrees.List()
+		for i := range wts {
+			if !wts[i].IsBare {
+				worktreeNames = append(worktreeNames, wts[i].Name())
+			}
+		}
+	}
+
+	page := &views.ProxyPage{
+		BasePage: views.BasePage{
+			Title:    "Proxy",
+			Worktree: activeWorktree,
+		},
+		Listeners: listeners,
+		Worktrees: worktreeNames,
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
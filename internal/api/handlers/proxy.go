@@ -0,0 +1,192 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/proxy"
+)
+
+// ProxyHandler handles captured proxy traffic API requests.
+type ProxyHandler struct {
+	manager *proxy.Manager
+}
+
+// NewProxyHandler creates a new proxy handler. manager may be nil when no
+// proxy listeners are configured.
+func NewProxyHandler(manager *proxy.Manager) *ProxyHandler {
+	return &ProxyHandler{manager: manager}
+}
+
+// Requests returns summaries of captured requests, newest first.
+// GET /api/v1/proxy/requests?listener=&method=&status=&path=&upstream=&min_duration=&limit=
+func (h *ProxyHandler) Requests(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := proxy.CaptureFilter{
+		Listener: q.Get("listener"),
+		Method:   q.Get("method"),
+		Status:   q.Get("status"),
+		Path:     q.Get("path"),
+		Upstream: q.Get("upstream"),
+	}
+	if s := q.Get("min_duration"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid min_duration: "+s)
+			return
+		}
+		filter.MinDuration = d
+	}
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = n
+	}
+	if err := filter.Validate(); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, err.Error())
+		return
+	}
+
+	requests := []proxy.CaptureSummary{}
+	if h.manager != nil {
+		var err error
+		if requests, err = h.manager.Requests(filter); err != nil {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, err.Error())
+			return
+		}
+	}
+	WriteJSON(w, http.StatusOK, requests)
+}
+
+// Get returns a captured request with its headers and bodies.
+// GET /api/v1/proxy/requests/{id}
+func (h *ProxyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requestID(w, r)
+	if !ok {
+		return
+	}
+	c, err := h.manager.Request(id)
+	if err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, c)
+}
+
+// Clear drops every captured request.
+// DELETE /api/v1/proxy/requests
+func (h *ProxyHandler) Clear(w http.ResponseWriter, r *http.Request) {
+	if h.manager != nil {
+		h.manager.ClearRequests()
+	}
+	WriteJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+}
+
+// Replay resends a captured request, with optional edits, and returns the
+// newly captured exchange.
+// POST /api/v1/proxy/requests/{id}/replay
+func (h *ProxyHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.requestID(w, r)
+	if !ok {
+		return
+	}
+
+	var opts proxy.ReplayOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil && err != io.EOF {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	c, err := h.manager.Replay(r.Context(), id, opts)
+	if errors.Is(err, proxy.ErrCaptureNotFound) {
+		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, c)
+}
+
+// Faults returns the fault injection settings of every route.
+// GET /api/v1/proxy/faults
+func (h *ProxyHandler) Faults(w http.ResponseWriter, r *http.Request) {
+	faults := []proxy.RouteFaults{}
+	if h.manager != nil {
+		faults = h.manager.Faults()
+	}
+	WriteJSON(w, http.StatusOK, faults)
+}
+
+// SetFaultsRequest replaces one route's fault injection settings.
+type SetFaultsRequest struct {
+	Listener string                  `json:"listener"`
+	Route    int                     `json:"route"`
+	Faults   config.ProxyFaultConfig `json:"faults"`
+}
+
+// SetFaults replaces a route's fault injection settings until the next
+// restart.
+// PUT /api/v1/proxy/faults
+func (h *ProxyHandler) SetFaults(w http.ResponseWriter, r *http.Request) {
+	var req SetFaultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if h.manager == nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, proxy.ErrRouteNotFound.Error())
+		return
+	}
+
+	err := h.manager.SetFaults(req.Listener, req.Route, req.Faults)
+	if errors.Is(err, proxy.ErrRouteNotFound) {
+		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, h.manager.Faults())
+}
+
+// ClearFaults turns fault injection off on every route.
+// DELETE /api/v1/proxy/faults
+func (h *ProxyHandler) ClearFaults(w http.ResponseWriter, r *http.Request) {
+	faults := []proxy.RouteFaults{}
+	if h.manager != nil {
+		h.manager.ClearFaults()
+		faults = h.manager.Faults()
+	}
+	WriteJSON(w, http.StatusOK, faults)
+}
+
+// requestID parses the {id} route variable, writing an error response if it
+// is invalid or nothing is captured.
+func (h *ProxyHandler) requestID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	idStr := mux.Vars(r)["id"]
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid request id: "+idStr)
+		return 0, false
+	}
+	if h.manager == nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, proxy.ErrCaptureNotFound.Error())
+		return 0, false
+	}
+	return id, true
+}
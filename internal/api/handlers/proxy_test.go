@@ -0,0 +1,129 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/proxy"
+)
+
+func TestProxyHandler(t *testing.T) {
+	manager, err := proxy.NewManager([]config.ProxyListenerConfig{{
+		Listen:  ":0",
+		Routes:  []config.ProxyRouteConfig{{Upstream: "localhost:1"}},
+		Capture: config.ProxyCaptureConfig{Enabled: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range []*ProxyHandler{NewProxyHandler(nil), NewProxyHandler(manager)} {
+		rec := httptest.NewRecorder()
+		h.Requests(rec, httptest.NewRequest("GET", "/api/v1/proxy/requests?status=5xx&limit=10", nil))
+		var resp struct {
+			Data []proxy.CaptureSummary `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if rec.Code != http.StatusOK || resp.Data == nil || len(resp.Data) != 0 {
+			t.Errorf("Requests: status = %d, data = %+v", rec.Code, resp.Data)
+		}
+
+		for _, query := range []string{"status=abc", "limit=-1", "min_duration=soon"} {
+			rec = httptest.NewRecorder()
+			h.Requests(rec, httptest.NewRequest("GET", "/api/v1/proxy/requests?"+query, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: status = %d, want 400", query, rec.Code)
+			}
+		}
+
+		rec = httptest.NewRecorder()
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/proxy/requests/x", nil), map[string]string{"id": "x"})
+		h.Get(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("bad id: status = %d, want 400", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		req = mux.SetURLVars(httptest.NewRequest("GET", "/api/v1/proxy/requests/42", nil), map[string]string{"id": "42"})
+		h.Get(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("unknown id: status = %d, want 404", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		req = mux.SetURLVars(httptest.NewRequest("POST", "/api/v1/proxy/requests/42/replay", strings.NewReader(`{"method":"PUT"}`)), map[string]string{"id": "42"})
+		h.Replay(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("replay unknown id: status = %d, want 404", rec.Code)
+		}
+
+		rec = httptest.NewRecorder()
+		h.Clear(rec, httptest.NewRequest("DELETE", "/api/v1/proxy/requests", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Clear: status = %d", rec.Code)
+		}
+	}
+}
+
+func TestProxyHandler_Faults(t *testing.T) {
+	manager, err := proxy.NewManager([]config.ProxyListenerConfig{{
+		Listen: ":0",
+		Routes: []config.ProxyRouteConfig{{Upstream: "localhost:1"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewProxyHandler(manager)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"enable", `{"listener":":0","route":0,"faults":{"enabled":true,"latency":"100ms"}}`, http.StatusOK},
+		{"invalid settings", `{"listener":":0","route":0,"faults":{"enabled":true,"error_rate":101}}`, http.StatusBadRequest},
+		{"unknown route", `{"listener":":0","route":3,"faults":{"enabled":true}}`, http.StatusNotFound},
+		{"bad body", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.SetFaults(rec, httptest.NewRequest("PUT", "/api/v1/proxy/faults", strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.status, rec.Body.String())
+		}
+	}
+
+	var resp struct {
+		Data []proxy.RouteFaults `json:"data"`
+	}
+	rec := httptest.NewRecorder()
+	h.Faults(rec, httptest.NewRequest("GET", "/api/v1/proxy/faults", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Data) != 1 || !resp.Data[0].Faults.Enabled || resp.Data[0].Faults.Latency != "100ms" {
+		t.Errorf("Faults: data = %+v", resp.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ClearFaults(rec, httptest.NewRequest("DELETE", "/api/v1/proxy/faults", nil))
+	if rec.Code != http.StatusOK || manager.Faults()[0].Faults.Enabled {
+		t.Errorf("ClearFaults: status = %d, faults = %+v", rec.Code, manager.Faults())
+	}
+
+	rec = httptest.NewRecorder()
+	NewProxyHandler(nil).SetFaults(rec, httptest.NewRequest("PUT", "/api/v1/proxy/faults", strings.NewReader(`{"listener":":0"}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("nil manager: status = %d, want 404", rec.Code)
+	}
+}
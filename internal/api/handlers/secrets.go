@@ -0,0 +1,98 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/secrets"
+)
+
+// SecretHandler handles secret store API requests. Values can be set but
+// are never returned.
+type SecretHandler struct {
+	manager *secrets.Manager
+}
+
+// NewSecretHandler creates a new secret handler. manager may be nil when
+// the secret store isn't available.
+func NewSecretHandler(manager *secrets.Manager) *SecretHandler {
+	return &SecretHandler{manager: manager}
+}
+
+// SetSecretRequest is the body of a request to set a secret.
+type SetSecretRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// List returns the names and sources of the known secrets.
+// GET /api/v1/secrets
+func (h *SecretHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	infos, err := h.manager.List()
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, infos)
+}
+
+// Set stores a secret in the local store.
+// POST /api/v1/secrets
+func (h *SecretHandler) Set(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	var req SetSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid JSON")
+		return
+	}
+	if err := secrets.ValidateName(req.Name); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, err.Error())
+		return
+	}
+	if req.Value == "" {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "value is required")
+		return
+	}
+	if err := h.manager.Set(req.Name, req.Value); err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, secrets.Info{Name: req.Name, Source: secrets.SourceStore})
+}
+
+// Delete removes a secret from the local store.
+// DELETE /api/v1/secrets/{name}
+func (h *SecretHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if !h.available(w) {
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if err := h.manager.Delete(name); err != nil {
+		if errors.Is(err, secrets.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// available writes an error and returns false if there is no secret store.
+func (h *SecretHandler) available(w http.ResponseWriter) bool {
+	if h.manager == nil {
+		WriteError(w, http.StatusServiceUnavailable, ErrInternalError, "secret store is not available")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/secrets"
+)
+
+func TestSecretHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewSecretHandler(nil).List(rec, httptest.NewRequest("GET", "/api/v1/secrets", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("no manager: status = %d, want 503", rec.Code)
+	}
+
+	h := NewSecretHandler(secrets.NewManager(secrets.NewStore(t.TempDir(), "test"), nil))
+
+	for _, body := range []string{`{"name": "bad name", "value": "x"}`, `{"name": "ok"}`, `nope`} {
+		rec = httptest.NewRecorder()
+		h.Set(rec, httptest.NewRequest("POST", "/api/v1/secrets", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("set %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.Set(rec, httptest.NewRequest("POST", "/api/v1/secrets", strings.NewReader(`{"name": "stripe_key", "value": "sk_test_1"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set: status = %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.List(rec, httptest.NewRequest("GET", "/api/v1/secrets", nil))
+	if strings.Contains(rec.Body.String(), "sk_test_1") {
+		t.Errorf("list returned a secret value: %s", rec.Body)
+	}
+	var resp struct {
+		Data []secrets.Info `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0] != (secrets.Info{Name: "stripe_key", Source: secrets.SourceStore}) {
+		t.Errorf("list = %+v", resp.Data)
+	}
+
+	del := func(name string) int {
+		req := mux.SetURLVars(httptest.NewRequest("DELETE", "/api/v1/secrets/"+name, nil), map[string]string{"name": name})
+		rec := httptest.NewRecorder()
+		h.Delete(rec, req)
+		return rec.Code
+	}
+	if code := del("stripe_key"); code != http.StatusNoContent {
+		t.Errorf("delete: status = %d, want 204", code)
+	}
+	if code := del("stripe_key"); code != http.StatusNotFound {
+		t.Errorf("delete again: status = %d, want 404", code)
+	}
+}
@@ -12,31 +12,92 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/logs"
 	"github.com/wingedpig/trellis/internal/service"
+	"github.com/wingedpig/trellis/internal/worktree"
 )
 
 // ServiceHandler handles service-related API requests.
 type ServiceHandler struct {
-	mgr service.Manager
+	services worktreeServices
 }
 
 // NewServiceHandler creates a new service handler.
 func NewServiceHandler(mgr service.Manager) *ServiceHandler {
-	return &ServiceHandler{mgr: mgr}
+	return &ServiceHandler{services: worktreeServices{main: mgr}}
+}
+
+// SetWorktreeServices lets requests address the services of a worktree
+// running them alongside the active worktree's with ?worktree=name.
+func (h *ServiceHandler) SetWorktreeServices(worktrees worktree.Manager, pool *service.Pool) {
+	h.services.worktrees = worktrees
+	h.services.pool = pool
+}
+
+// worktreeServices finds the services of a worktree: the main manager's
+// for the active worktree, or the worktree's own in the pool.
+type worktreeServices struct {
+	main      service.Manager
+	worktrees worktree.Manager
+	pool      *service.Pool
+}
+
+// lookup returns the manager of the named worktree's services. An empty
+// name is the active worktree. The worktree's canonical name is returned
+// too, "" for the active worktree.
+func (s worktreeServices) lookup(name string) (service.Manager, string, error) {
+	if name == "" {
+		return s.main, "", nil
+	}
+	if s.worktrees == nil {
+		return nil, "", fmt.Errorf("worktree %q not found", name)
+	}
+	wt, ok := s.worktrees.GetByName(name)
+	if !ok {
+		return nil, "", fmt.Errorf("worktree %q not found", name)
+	}
+	if active := s.worktrees.Active(); active != nil && active.Path == wt.Path {
+		return s.main, "", nil
+	}
+	if s.pool != nil {
+		if mgr, ok := s.pool.Get(wt.Name()); ok {
+			return mgr, wt.Name(), nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: %s", service.ErrNoWorktreeServices, wt.Name())
+}
+
+// manager returns the manager a request's ?worktree= parameter addresses,
+// writing a not found error if there isn't one.
+func (h *ServiceHandler) manager(w http.ResponseWriter, r *http.Request) (service.Manager, bool) {
+	mgr, _, err := h.services.lookup(r.URL.Query().Get("worktree"))
+	if err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+		return nil, false
+	}
+	return mgr, true
 }
 
 // List returns all services.
 func (h *ServiceHandler) List(w http.ResponseWriter, r *http.Request) {
-	services := h.mgr.List()
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
+	services := mgr.List()
 	WriteJSON(w, http.StatusOK, services)
 }
 
 // Get returns a single service by name.
 func (h *ServiceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
-	svc, ok := h.mgr.GetService(name)
+	svc, ok := mgr.GetService(name)
 	if !ok {
 		WriteError(w, http.StatusNotFound, ErrNotFound, "service not found")
 		return
@@ -47,69 +108,93 @@ func (h *ServiceHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // Start starts a service.
 func (h *ServiceHandler) Start(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
 	// Use background context - service should outlive the HTTP request
-	if err := h.mgr.Start(context.Background(), name); err != nil {
+	if err := mgr.Start(context.Background(), name); err != nil {
 		WriteError(w, http.StatusBadRequest, ErrServiceError, err.Error())
 		return
 	}
 
-	svc, _ := h.mgr.GetService(name)
+	svc, _ := mgr.GetService(name)
 	WriteJSON(w, http.StatusOK, svc)
 }
 
 // Stop stops a service.
 func (h *ServiceHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
 	// Use background context - stop should complete even if request is cancelled
-	if err := h.mgr.Stop(context.Background(), name); err != nil {
+	if err := mgr.Stop(context.Background(), name); err != nil {
 		WriteError(w, http.StatusBadRequest, ErrServiceError, err.Error())
 		return
 	}
 
-	svc, _ := h.mgr.GetService(name)
+	svc, _ := mgr.GetService(name)
 	WriteJSON(w, http.StatusOK, svc)
 }
 
 // Restart restarts a service.
 func (h *ServiceHandler) Restart(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
 	// Use background context - service should outlive the HTTP request
-	if err := h.mgr.Restart(context.Background(), name, service.RestartManual); err != nil {
+	if err := mgr.Restart(context.Background(), name, service.RestartManual); err != nil {
 		WriteError(w, http.StatusBadRequest, ErrServiceError, err.Error())
 		return
 	}
 
-	svc, _ := h.mgr.GetService(name)
+	svc, _ := mgr.GetService(name)
 	WriteJSON(w, http.StatusOK, svc)
 }
 
 // StartAll starts all enabled services.
 func (h *ServiceHandler) StartAll(w http.ResponseWriter, r *http.Request) {
-	if err := h.mgr.StartAll(context.Background()); err != nil {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
+	if err := mgr.StartAll(context.Background()); err != nil {
 		WriteError(w, http.StatusInternalServerError, ErrServiceError, err.Error())
 		return
 	}
-	WriteJSON(w, http.StatusOK, h.mgr.List())
+	WriteJSON(w, http.StatusOK, mgr.List())
 }
 
 // StopAll stops all running services.
 func (h *ServiceHandler) StopAll(w http.ResponseWriter, r *http.Request) {
-	if err := h.mgr.StopAll(context.Background()); err != nil {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
+	if err := mgr.StopAll(context.Background()); err != nil {
 		WriteError(w, http.StatusInternalServerError, ErrServiceError, err.Error())
 		return
 	}
-	WriteJSON(w, http.StatusOK, h.mgr.List())
+	WriteJSON(w, http.StatusOK, mgr.List())
 }
 
 // Logs returns the logs for a service.
 func (h *ServiceHandler) Logs(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
@@ -121,10 +206,18 @@ func (h *ServiceHandler) Logs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Paging into older lines, or a time range: these may be served from
+	// the service's persisted log store once its buffer runs out.
+	query := r.URL.Query()
+	if query.Get("before") != "" || query.Get("since") != "" || query.Get("until") != "" {
+		h.olderLogs(w, r, mgr, name, lines)
+		return
+	}
+
 	// Check if service has a parser configured
-	if h.mgr.HasParser(name) {
+	if mgr.HasParser(name) {
 		// Return parsed entries
-		entries, err := h.mgr.ParsedLogs(name, lines)
+		entries, err := mgr.ParsedLogs(name, lines)
 		if err != nil {
 			WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
 			return
@@ -138,7 +231,7 @@ func (h *ServiceHandler) Logs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// No parser - return raw lines
-	logs, err := h.mgr.Logs(name, lines)
+	logs, err := mgr.Logs(name, lines)
 	if err != nil {
 		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
 		return
@@ -150,12 +243,81 @@ func (h *ServiceHandler) Logs(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// olderLogs serves a service logs request with a before sequence number or a
+// since/until time range.
+func (h *ServiceHandler) olderLogs(w http.ResponseWriter, r *http.Request, mgr service.Manager, name string, lines int) {
+	query := r.URL.Query()
+
+	var logLines []service.LogLine
+	var err error
+	if beforeStr := query.Get("before"); beforeStr != "" {
+		before, perr := strconv.ParseInt(beforeStr, 10, 64)
+		if perr != nil || before <= 0 {
+			WriteError(w, http.StatusBadRequest, ErrBadRequest, "before must be a positive sequence number")
+			return
+		}
+		logLines, err = mgr.LogsBefore(name, before, lines)
+	} else {
+		var since time.Time
+		until := time.Now()
+		if sinceStr := query.Get("since"); sinceStr != "" {
+			t, perr := time.Parse(time.RFC3339, sinceStr)
+			if perr != nil {
+				WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid 'since' timestamp: expected RFC3339 format")
+				return
+			}
+			since = t
+		}
+		if untilStr := query.Get("until"); untilStr != "" {
+			t, perr := time.Parse(time.RFC3339, untilStr)
+			if perr != nil {
+				WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid 'until' timestamp: expected RFC3339 format")
+				return
+			}
+			until = t
+		}
+		logLines, err = mgr.LogsRange(name, since, until, lines)
+	}
+	if err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{
+		"service": name,
+	}
+	if len(logLines) > 0 {
+		// Pass as before= to page further back
+		resp["first_sequence"] = logLines[0].Sequence
+	}
+	if mgr.HasParser(name) {
+		entries := make([]*logs.LogEntry, 0, len(logLines))
+		for _, l := range logLines {
+			if l.Entry != nil {
+				entries = append(entries, l.Entry)
+			}
+		}
+		resp["entries"] = entries
+	} else {
+		raw := make([]string, 0, len(logLines))
+		for _, l := range logLines {
+			raw = append(raw, l.Line)
+		}
+		resp["lines"] = raw
+	}
+	WriteJSON(w, http.StatusOK, resp)
+}
+
 // ClearLogs clears the logs for a service.
 func (h *ServiceHandler) ClearLogs(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
-	if err := h.mgr.ClearLogs(name); err != nil {
+	if err := mgr.ClearLogs(name); err != nil {
 		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
 		return
 	}
@@ -168,16 +330,20 @@ func (h *ServiceHandler) ClearLogs(w http.ResponseWriter, r *http.Request) {
 
 // StreamLogs streams service logs via Server-Sent Events.
 func (h *ServiceHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
+	mgr, ok := h.manager(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	name := vars["name"]
 
 	// Subscribe to log updates
-	ch, err := h.mgr.SubscribeLogs(name)
+	ch, err := mgr.SubscribeLogs(name)
 	if err != nil {
 		WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
 		return
 	}
-	defer h.mgr.UnsubscribeLogs(name, ch)
+	defer mgr.UnsubscribeLogs(name, ch)
 
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
@@ -220,6 +386,9 @@ func (h *ServiceHandler) StreamLogs(w http.ResponseWriter, r *http.Request) {
 			if line.Entry != nil {
 				resp["entry"] = line.Entry
 			}
+			if line.Continuation {
+				resp["continuation"] = true
+			}
 			data, _ := json.Marshal(resp)
 			fmt.Fprintf(w, "data: %s\n\n", data)
 			flusher.Flush()
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/logs"
 	"github.com/wingedpig/trellis/internal/trace"
 )
 
@@ -33,6 +34,7 @@ type executeRequest struct {
 	End        string `json:"end"`
 	Name       string `json:"name"`
 	ExpandByID *bool  `json:"expand_by_id"` // nil defaults to true
+	Filter     string `json:"filter"`       // Log filter expression (optional)
 }
 
 // Execute runs a distributed trace search.
@@ -54,6 +56,11 @@ func (h *TraceHandler) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, err := logs.ParseFilter(req.Filter); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "invalid filter: "+err.Error())
+		return
+	}
+
 	// Parse time range
 	start, err := time.Parse(time.RFC3339, req.Start)
 	if err != nil {
@@ -87,6 +94,7 @@ func (h *TraceHandler) Execute(w http.ResponseWriter, r *http.Request) {
 		Start:      start,
 		End:        end,
 		ExpandByID: expandByID,
+		Filter:     req.Filter,
 	}
 
 	// Execute the trace
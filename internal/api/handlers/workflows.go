@@ -8,7 +8,11 @@ import (
 	"encoding/json"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"os"
+	"path"
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,6 +27,7 @@ type WorkflowHandler struct {
 	upgraderHolder
 	runner      workflow.Runner
 	worktreeMgr worktree.Manager
+	history     *workflow.History
 }
 
 // NewWorkflowHandler creates a new workflow handler.
@@ -30,6 +35,12 @@ func NewWorkflowHandler(runner workflow.Runner, worktreeMgr worktree.Manager) *W
 	return &WorkflowHandler{runner: runner, worktreeMgr: worktreeMgr}
 }
 
+// SetHistory sets the store of completed runs served by the history
+// endpoints.
+func (h *WorkflowHandler) SetHistory(history *workflow.History) {
+	h.history = history
+}
+
 // List returns all workflows.
 func (h *WorkflowHandler) List(w http.ResponseWriter, r *http.Request) {
 	workflows := h.runner.List()
@@ -165,6 +176,102 @@ func (h *WorkflowHandler) LatestRun(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, status)
 }
 
+// History returns a workflow's recorded runs without their output, newest
+// first, optionally only those for ?worktree= and at most ?limit= of them.
+func (h *WorkflowHandler) History(w http.ResponseWriter, r *http.Request) {
+	if !h.hasHistory(w) {
+		return
+	}
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	WriteJSON(w, http.StatusOK, h.history.Runs(mux.Vars(r)["id"], query.Get("worktree"), limit))
+}
+
+// HistoryRun returns a recorded run with its output.
+func (h *WorkflowHandler) HistoryRun(w http.ResponseWriter, r *http.Request) {
+	if !h.hasHistory(w) {
+		return
+	}
+	vars := mux.Vars(r)
+	status, err := h.history.Run(vars["runID"])
+	if err != nil || status.WorkflowID != vars["id"] {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "workflow run not found")
+		return
+	}
+	WriteJSON(w, http.StatusOK, status)
+}
+
+// Artifact downloads a file kept from a run, which may still be in flight.
+func (h *WorkflowHandler) Artifact(w http.ResponseWriter, r *http.Request) {
+	if !h.hasHistory(w) {
+		return
+	}
+	vars := mux.Vars(r)
+	runID, artifactPath := vars["runID"], vars["path"]
+	status, ok := h.runner.Status(runID)
+	if !ok {
+		var err error
+		if status, err = h.history.Run(runID); err != nil {
+			status = nil
+		}
+	}
+	if status == nil || status.WorkflowID != vars["id"] {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "workflow run not found")
+		return
+	}
+
+	found := false
+	for _, a := range status.Artifacts {
+		found = found || a.Path == artifactPath
+	}
+	file, err := h.history.ArtifactFile(runID, artifactPath)
+	if !found || err != nil {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "artifact not found")
+		return
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		WriteError(w, http.StatusInternalServerError, ErrInternalError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(artifactPath)}))
+	http.ServeContent(w, r, path.Base(artifactPath), info.ModTime(), f)
+}
+
+// Tests returns the pass/fail timeline of the tests that failed in a
+// workflow's last ?limit= runs reporting test results.
+func (h *WorkflowHandler) Tests(w http.ResponseWriter, r *http.Request) {
+	if !h.hasHistory(w) {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	WriteJSON(w, http.StatusOK, h.history.Tests(mux.Vars(r)["id"], limit))
+}
+
+// Flaky returns the tests of a workflow that both passed and failed on
+// the same commit.
+func (h *WorkflowHandler) Flaky(w http.ResponseWriter, r *http.Request) {
+	if !h.hasHistory(w) {
+		return
+	}
+	WriteJSON(w, http.StatusOK, h.history.Flaky(mux.Vars(r)["id"]))
+}
+
+// hasHistory writes an error if there is no run history.
+func (h *WorkflowHandler) hasHistory(w http.ResponseWriter) bool {
+	if h.history == nil {
+		WriteError(w, http.StatusServiceUnavailable, ErrWorkflowError, "workflow history is not available")
+		return false
+	}
+	return true
+}
+
 // Stream handles WebSocket connections for streaming workflow output.
 func (h *WorkflowHandler) Stream(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -4,17 +4,22 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/service"
 	"github.com/wingedpig/trellis/internal/worktree"
 )
 
 // WorktreeHandler handles worktree-related API requests.
 type WorktreeHandler struct {
-	mgr worktree.Manager
+	mgr   worktree.Manager
+	pool  *service.Pool
+	ports *worktree.PortAllocator
 }
 
 // WorktreeResponse wraps WorktreeInfo with an Active field for API responses.
@@ -28,6 +33,10 @@ type WorktreeResponse struct {
 	Ahead    int    `json:"Ahead"`
 	Behind   int    `json:"Behind"`
 	Active   bool   `json:"Active"`
+	// Services is true when the worktree's services are running alongside
+	// the active worktree's
+	Services bool           `json:"Services"`
+	Ports    map[string]int `json:"Ports,omitempty"` // Allocated by {{.Worktree.Port "name"}}
 }
 
 // toWorktreeResponse converts a WorktreeInfo to a WorktreeResponse.
@@ -50,6 +59,28 @@ func NewWorktreeHandler(mgr worktree.Manager) *WorktreeHandler {
 	return &WorktreeHandler{mgr: mgr}
 }
 
+// SetWorktreeServices sets the pool running the services of worktrees
+// other than the active one and the allocator of worktree ports.
+func (h *WorktreeHandler) SetWorktreeServices(pool *service.Pool, ports *worktree.PortAllocator) {
+	h.pool = pool
+	h.ports = ports
+}
+
+// response converts a WorktreeInfo to a WorktreeResponse with its services
+// and ports.
+func (h *WorktreeHandler) response(wt worktree.WorktreeInfo, isActive bool) WorktreeResponse {
+	resp := toWorktreeResponse(wt, isActive)
+	if h.pool != nil {
+		_, resp.Services = h.pool.Get(wt.Name())
+	}
+	if h.ports != nil {
+		if ports := h.ports.Ports(wt.Name()); len(ports) > 0 {
+			resp.Ports = ports
+		}
+	}
+	return resp
+}
+
 // listWorktreeResponses refreshes worktree data and returns responses for all worktrees.
 func (h *WorktreeHandler) listWorktreeResponses() ([]WorktreeResponse, error) {
 	if err := h.mgr.Refresh(); err != nil {
@@ -65,7 +96,7 @@ func (h *WorktreeHandler) listWorktreeResponses() ([]WorktreeResponse, error) {
 	responses := make([]WorktreeResponse, len(worktrees))
 	for i, wt := range worktrees {
 		isActive := active != nil && active.Path == wt.Path
-		responses[i] = toWorktreeResponse(wt, isActive)
+		responses[i] = h.response(wt, isActive)
 	}
 	return responses, nil
 }
@@ -98,10 +129,71 @@ func (h *WorktreeHandler) Get(w http.ResponseWriter, r *http.Request) {
 	isActive := active != nil && active.Path == wt.Path
 
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"worktree": toWorktreeResponse(wt, isActive),
+		"worktree": h.response(wt, isActive),
+	})
+}
+
+// StartServices starts a worktree's services alongside the active
+// worktree's, each with the worktree's config and ports.
+func (h *WorktreeHandler) StartServices(w http.ResponseWriter, r *http.Request) {
+	wt, ok := h.inactiveWorktree(w, r)
+	if !ok {
+		return
+	}
+
+	// Use background context - services should outlive the HTTP request
+	if err := h.pool.Start(context.Background(), wt.Name()); err != nil {
+		WriteError(w, http.StatusBadRequest, ErrServiceError, err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"worktree": h.response(wt, false),
+	})
+}
+
+// StopServices stops the services a worktree runs alongside the active
+// worktree's.
+func (h *WorktreeHandler) StopServices(w http.ResponseWriter, r *http.Request) {
+	wt, ok := h.inactiveWorktree(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.pool.Stop(context.Background(), wt.Name()); err != nil {
+		if errors.Is(err, service.ErrNoWorktreeServices) {
+			WriteError(w, http.StatusNotFound, ErrNotFound, err.Error())
+			return
+		}
+		WriteError(w, http.StatusInternalServerError, ErrServiceError, err.Error())
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"worktree": h.response(wt, false),
 	})
 }
 
+// inactiveWorktree returns the worktree named in the request, writing an
+// error if it doesn't exist or is active: the active worktree's services
+// are the main services.
+func (h *WorktreeHandler) inactiveWorktree(w http.ResponseWriter, r *http.Request) (worktree.WorktreeInfo, bool) {
+	if h.pool == nil {
+		WriteError(w, http.StatusServiceUnavailable, ErrServiceError, "worktree services are not available")
+		return worktree.WorktreeInfo{}, false
+	}
+	wt, ok := h.mgr.GetByName(mux.Vars(r)["name"])
+	if !ok {
+		WriteError(w, http.StatusNotFound, ErrNotFound, "worktree not found")
+		return wt, false
+	}
+	if active := h.mgr.Active(); active != nil && active.Path == wt.Path {
+		WriteError(w, http.StatusBadRequest, ErrBadRequest, "worktree "+wt.Name()+" is active; use the service commands for its services")
+		return wt, false
+	}
+	return wt, true
+}
+
 // Activate activates a worktree.
 func (h *WorktreeHandler) Activate(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -123,7 +215,7 @@ func (h *WorktreeHandler) Activate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	WriteJSON(w, http.StatusOK, map[string]interface{}{
-		"worktree":     toWorktreeResponse(result.Worktree, true),
+		"worktree":     h.response(result.Worktree, true),
 		"hook_results": result.HookResults,
 		"duration":     result.Duration,
 	})
@@ -164,7 +256,7 @@ func (h *WorktreeHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	WriteJSON(w, http.StatusCreated, map[string]interface{}{
-		"worktree": toWorktreeResponse(wt, req.SwitchTo),
+		"worktree": h.response(wt, req.SwitchTo),
 	})
 }
 
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/wingedpig/trellis/internal/alerts"
 	"github.com/wingedpig/trellis/internal/api/handlers"
 	"github.com/wingedpig/trellis/internal/api/middleware"
 	"github.com/wingedpig/trellis/internal/api/version"
@@ -23,11 +24,14 @@ import (
 	"github.com/wingedpig/trellis/internal/checklist"
 	"github.com/wingedpig/trellis/internal/claude"
 	"github.com/wingedpig/trellis/internal/codex"
+	"github.com/wingedpig/trellis/internal/config"
 	"github.com/wingedpig/trellis/internal/crashes"
 	"github.com/wingedpig/trellis/internal/events"
 	"github.com/wingedpig/trellis/internal/inbox"
 	"github.com/wingedpig/trellis/internal/logs"
 	"github.com/wingedpig/trellis/internal/pair"
+	"github.com/wingedpig/trellis/internal/proxy"
+	"github.com/wingedpig/trellis/internal/secrets"
 	"github.com/wingedpig/trellis/internal/service"
 	"github.com/wingedpig/trellis/internal/terminal"
 	"github.com/wingedpig/trellis/internal/trace"
@@ -59,20 +63,28 @@ type ServerConfig struct {
 // Dependencies holds all dependencies for API handlers.
 type Dependencies struct {
 	ServiceManager    service.Manager
+	ServicePool       *service.Pool // Services of worktrees other than the active one
+	WorktreePorts     *worktree.PortAllocator
 	WorktreeManager   worktree.Manager
 	WorkflowRunner    workflow.Runner
+	WorkflowHistory   *workflow.History // Completed workflow runs (nil if it couldn't be opened)
 	TerminalManager   terminal.Manager
 	EventBus          events.EventBus
-	LogManager        *logs.Manager       // Log viewer manager
-	TraceManager      *trace.Manager      // Distributed trace manager
-	CrashManager      *crashes.Manager    // Crash history manager
-	ClaudeManager     *claude.Manager     // Claude Code session manager
-	CodexManager      *codex.Manager      // OpenAI Codex session manager
-	UsageManager      *usage.Manager      // Claude Code token usage/cost reports
-	CaseManager       *cases.Manager      // Case objects manager
-	InboxAggregator   *inbox.Aggregator   // Cross-agent session inbox
-	PairRegistry      *pair.Registry      // Paired review loops
-	ChecklistRegistry *checklist.Registry // Phased-checklist outer loops
+	WebhookDispatcher *events.WebhookDispatcher // Outbound event webhooks (nil if none configured)
+	AlertManager      *alerts.Manager           // Log alert rules (nil if none configured)
+	ProxyManager      *proxy.Manager            // Reverse proxy listeners (nil if none configured)
+	ConfigDocument    func() *config.Document   // Layered config the server is running with
+	SecretManager     *secrets.Manager          // Secret store for service and workflow environments
+	LogManager        *logs.Manager             // Log viewer manager
+	TraceManager      *trace.Manager            // Distributed trace manager
+	CrashManager      *crashes.Manager          // Crash history manager
+	ClaudeManager     *claude.Manager           // Claude Code session manager
+	CodexManager      *codex.Manager            // OpenAI Codex session manager
+	UsageManager      *usage.Manager            // Claude Code token usage/cost reports
+	CaseManager       *cases.Manager            // Case objects manager
+	InboxAggregator   *inbox.Aggregator         // Cross-agent session inbox
+	PairRegistry      *pair.Registry            // Paired review loops
+	ChecklistRegistry *checklist.Registry       // Phased-checklist outer loops
 	VSCodeHandler     *handlers.VSCodeHandler
 	Shortcuts         []handlers.ShortcutConfig   // Keyboard shortcuts for terminal windows
 	Notifications     handlers.NotificationConfig // Browser notification settings
@@ -139,6 +151,10 @@ func registerPageRoutes(r *mux.Router, pageHandler *handlers.PageHandler) {
 	r.HandleFunc("/status", pageHandler.Status).Methods("GET")
 	r.HandleFunc("/worktrees", pageHandler.Worktrees).Methods("GET")
 	r.HandleFunc("/events", pageHandler.Events).Methods("GET")
+	r.HandleFunc("/workflows", pageHandler.Workflows).Methods("GET")
+	r.HandleFunc("/workflows/{id}/history", pageHandler.WorkflowHistory).Methods("GET")
+	r.HandleFunc("/alerts", pageHandler.Alerts).Methods("GET")
+	r.HandleFunc("/proxy", pageHandler.Proxy).Methods("GET")
 	// Trace pages
 	r.HandleFunc("/trace", pageHandler.Trace).Methods("GET")
 	r.HandleFunc("/trace/report/{name:.+}", pageHandler.TraceReport).Methods("GET")
@@ -195,7 +211,9 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 	}
 
 	// UI Page handlers
-	pageHandler := handlers.NewPageHandler(deps.ServiceManager, deps.WorktreeManager, deps.WorkflowRunner, deps.EventBus, deps.TerminalManager, deps.LogManager, deps.TraceManager, deps.CrashManager, deps.ClaudeManager, deps.CodexManager, deps.CaseManager, deps.Shortcuts, deps.Notifications, deps.Links, deps.Version)
+	pageHandler := handlers.NewPageHandler(deps.ServiceManager, deps.WorktreeManager, deps.WorkflowRunner, deps.EventBus, deps.WebhookDispatcher, deps.AlertManager, deps.ProxyManager, deps.TerminalManager, deps.LogManager, deps.TraceManager, deps.CrashManager, deps.ClaudeManager, deps.CodexManager, deps.CaseManager, deps.Shortcuts, deps.Notifications, deps.Links, deps.Version)
+	pageHandler.SetServicePool(deps.ServicePool)
+	pageHandler.SetWorkflowHistory(deps.WorkflowHistory)
 	registerPageRoutes(r, pageHandler)
 
 	// API v1 routes
@@ -203,6 +221,7 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 
 	// Service handlers
 	serviceHandler := handlers.NewServiceHandler(deps.ServiceManager)
+	serviceHandler.SetWorktreeServices(deps.WorktreeManager, deps.ServicePool)
 	api.HandleFunc("/services", serviceHandler.List).Methods("GET")
 	api.HandleFunc("/services/start-all", serviceHandler.StartAll).Methods("POST")
 	api.HandleFunc("/services/stop-all", serviceHandler.StopAll).Methods("POST")
@@ -216,30 +235,65 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 
 	// Worktree handlers
 	worktreeHandler := handlers.NewWorktreeHandler(deps.WorktreeManager)
+	worktreeHandler.SetWorktreeServices(deps.ServicePool, deps.WorktreePorts)
 	api.HandleFunc("/worktrees", worktreeHandler.List).Methods("GET")
 	api.HandleFunc("/worktrees", worktreeHandler.Create).Methods("POST")
 	api.HandleFunc("/worktrees/info", worktreeHandler.Info).Methods("GET")
 	api.HandleFunc("/worktrees/{name}", worktreeHandler.Get).Methods("GET")
 	api.HandleFunc("/worktrees/{name}", worktreeHandler.Remove).Methods("DELETE")
 	api.HandleFunc("/worktrees/{name}/activate", worktreeHandler.Activate).Methods("POST")
+	api.HandleFunc("/worktrees/{name}/services/start", worktreeHandler.StartServices).Methods("POST")
+	api.HandleFunc("/worktrees/{name}/services/stop", worktreeHandler.StopServices).Methods("POST")
 
 	// Workflow handlers
 	workflowHandler := handlers.NewWorkflowHandler(deps.WorkflowRunner, deps.WorktreeManager)
 	workflowHandler.SetUpgrader(ws)
+	workflowHandler.SetHistory(deps.WorkflowHistory)
 	api.HandleFunc("/workflows", workflowHandler.List).Methods("GET")
 	api.HandleFunc("/workflows/runs/latest", workflowHandler.LatestRun).Methods("GET")
 	api.HandleFunc("/workflows/{id}", workflowHandler.Get).Methods("GET")
 	api.HandleFunc("/workflows/{id}/run", workflowHandler.Run).Methods("POST")
 	api.HandleFunc("/workflows/{id}/status", workflowHandler.Status).Methods("GET")
 	api.HandleFunc("/workflows/{id}/cancel", workflowHandler.CancelRun).Methods("POST")
+	api.HandleFunc("/workflows/{id}/runs", workflowHandler.History).Methods("GET")
+	api.HandleFunc("/workflows/{id}/runs/{runID}", workflowHandler.HistoryRun).Methods("GET")
+	api.HandleFunc("/workflows/{id}/runs/{runID}/artifacts/{path:.+}", workflowHandler.Artifact).Methods("GET")
+	api.HandleFunc("/workflows/{id}/tests", workflowHandler.Tests).Methods("GET")
+	api.HandleFunc("/workflows/{id}/flaky", workflowHandler.Flaky).Methods("GET")
 	api.HandleFunc("/workflows/{runID}/stream", workflowHandler.Stream).Methods("GET")
 
 	// Event handlers
-	eventHandler := handlers.NewEventHandler(deps.EventBus)
+	eventHandler := handlers.NewEventHandler(deps.EventBus, deps.WebhookDispatcher)
 	eventHandler.SetUpgrader(ws)
 	api.HandleFunc("/events", eventHandler.History).Methods("GET")
+	api.HandleFunc("/events/webhooks/deliveries", eventHandler.WebhookDeliveries).Methods("GET")
 	api.HandleFunc("/events/ws", eventHandler.WebSocket).Methods("GET")
 
+	// Log alert handlers
+	alertHandler := handlers.NewAlertHandler(deps.AlertManager)
+	api.HandleFunc("/alerts", alertHandler.Rules).Methods("GET")
+	api.HandleFunc("/alerts/history", alertHandler.History).Methods("GET")
+
+	// Proxy traffic capture handlers
+	proxyHandler := handlers.NewProxyHandler(deps.ProxyManager)
+	api.HandleFunc("/proxy/requests", proxyHandler.Requests).Methods("GET")
+	api.HandleFunc("/proxy/requests", proxyHandler.Clear).Methods("DELETE")
+	api.HandleFunc("/proxy/requests/{id}", proxyHandler.Get).Methods("GET")
+	api.HandleFunc("/proxy/requests/{id}/replay", proxyHandler.Replay).Methods("POST")
+	api.HandleFunc("/proxy/faults", proxyHandler.Faults).Methods("GET")
+	api.HandleFunc("/proxy/faults", proxyHandler.SetFaults).Methods("PUT")
+	api.HandleFunc("/proxy/faults", proxyHandler.ClearFaults).Methods("DELETE")
+
+	// Config handler
+	configHandler := handlers.NewConfigHandler(deps.ConfigDocument)
+	api.HandleFunc("/config", configHandler.Show).Methods("GET")
+
+	// Secret handlers
+	secretHandler := handlers.NewSecretHandler(deps.SecretManager)
+	api.HandleFunc("/secrets", secretHandler.List).Methods("GET")
+	api.HandleFunc("/secrets", secretHandler.Set).Methods("POST")
+	api.HandleFunc("/secrets/{name:.+}", secretHandler.Delete).Methods("DELETE")
+
 	// Notify handler (for AI assistants and external tools)
 	notifyHandler := handlers.NewNotifyHandler(deps.EventBus)
 	api.HandleFunc("/notify", notifyHandler.Notify).Methods("POST")
@@ -280,11 +334,13 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 		projectName = deps.WorktreeManager.ProjectName()
 	}
 	navHandler := handlers.NewNavHandler(deps.TerminalManager, deps.ServiceManager, deps.LogManager, deps.ClaudeManager, deps.CodexManager, deps.CaseManager, deps.WorktreeManager, deps.Links, deps.Shortcuts, deps.Notifications, projectName)
+	navHandler.SetServicePool(deps.ServicePool)
 	api.HandleFunc("/nav/options", navHandler.Options).Methods("GET")
 
 	// Case handlers
 	if deps.CaseManager != nil {
 		caseHandler := handlers.NewCaseHandler(deps.CaseManager, deps.ClaudeManager, deps.CodexManager, deps.TraceManager, deps.WorktreeManager)
+		caseHandler.SetWorkflowHistory(deps.WorkflowHistory)
 		api.HandleFunc("/cases/{worktree}", caseHandler.List).Methods("GET")
 		api.HandleFunc("/cases/{worktree}/archived", caseHandler.ListArchived).Methods("GET")
 		api.HandleFunc("/cases/{worktree}/archived/search", caseHandler.SearchArchived).Methods("GET")
@@ -296,6 +352,7 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 		api.HandleFunc("/cases/{worktree}/{id}/archive", caseHandler.Archive).Methods("POST")
 		api.HandleFunc("/cases/{worktree}/{id}/reopen", caseHandler.Reopen).Methods("POST")
 		api.HandleFunc("/cases/{worktree}/{id}/evidence", caseHandler.AttachEvidence).Methods("POST")
+		api.HandleFunc("/cases/{worktree}/{id}/evidence/artifact", caseHandler.AttachArtifact).Methods("POST")
 		api.HandleFunc("/cases/{worktree}/{id}/transcript", caseHandler.SaveTranscript).Methods("POST")
 		api.HandleFunc("/cases/{worktree}/{id}/transcript/{claude_id}", caseHandler.UpdateTranscript).Methods("PUT")
 		api.HandleFunc("/cases/{worktree}/{id}/transcript/{claude_id}/continue", caseHandler.ContinueTranscript).Methods("POST")
@@ -383,6 +440,7 @@ func NewRouterWithTerminalHandler(deps Dependencies, terminalHandler *handlers.T
 		api.HandleFunc("/logs/{name}", logHandler.Get).Methods("GET")
 		api.HandleFunc("/logs/{name}/entries", logHandler.GetEntries).Methods("GET")
 		api.HandleFunc("/logs/{name}/history", logHandler.GetHistory).Methods("GET")
+		api.HandleFunc("/logs/{name}/stats", logHandler.Stats).Methods("GET")
 		api.HandleFunc("/logs/{name}/files", logHandler.ListRotatedFiles).Methods("GET")
 		api.HandleFunc("/logs/{name}/stream", logHandler.Stream).Methods("GET")
 		api.HandleFunc("/logs/{name}/stream/sse", logHandler.StreamSSE).Methods("GET")
@@ -12,11 +12,14 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/wingedpig/trellis/internal/alerts"
 	"github.com/wingedpig/trellis/internal/api"
 	"github.com/wingedpig/trellis/internal/api/handlers"
 	"github.com/wingedpig/trellis/internal/api/middleware"
@@ -31,6 +34,7 @@ import (
 	"github.com/wingedpig/trellis/internal/logs"
 	"github.com/wingedpig/trellis/internal/pair"
 	"github.com/wingedpig/trellis/internal/proxy"
+	"github.com/wingedpig/trellis/internal/secrets"
 	"github.com/wingedpig/trellis/internal/service"
 	"github.com/wingedpig/trellis/internal/skill"
 	"github.com/wingedpig/trellis/internal/terminal"
@@ -47,13 +51,21 @@ type App struct {
 
 	configPath        string         // Path to config file (for determining repo directory)
 	worktreeOverride  string         // Worktree name/branch override from command line
+	hostOverride      string         // Server host override from command line
+	portOverride      int            // Server port override from command line
 	version           string         // Application version string
 	originalConfig    *config.Config // Original unexpanded config (for worktree switching)
 	config            *config.Config // Expanded config for current worktree
 	eventBus          events.EventBus
+	webhookDispatcher *events.WebhookDispatcher
+	alertManager      *alerts.Manager
 	serviceManager    service.Manager
 	worktreeManager   worktree.Manager
+	servicePool       *service.Pool           // Services of worktrees other than the active one
+	worktreePorts     *worktree.PortAllocator // Backs {{.Worktree.Port "name"}}
 	workflowRunner    workflow.Runner
+	workflowHistory   *workflow.History
+	workflowTriggers  *workflow.Triggers
 	terminalManager   terminal.Manager
 	logManager        *logs.Manager
 	traceManager      *trace.Manager
@@ -67,8 +79,19 @@ type App struct {
 	pairRegistry      *pair.Registry
 	checklistRegistry *checklist.Registry
 	proxyManager      *proxy.Manager
+	secretManager     *secrets.Manager
+	proxyAccessLogs   map[string]*logs.PushSource // Access log sources by listen address
+	proxyAccessLogsMu sync.RWMutex
 	apiServer         *api.Server
 
+	// configMu serializes config changes: worktree switches and reloads
+	configMu      sync.Mutex
+	configWatcher *watcher.FileWatcher
+	configDoc     atomic.Pointer[config.Document] // Config files, with the active worktree's overlay
+
+	concurrentWorktrees bool   // worktree.concurrent, fixed at startup
+	servicesWorktree    string // Worktree whose services the main manager runs (guarded by configMu)
+
 	done     chan struct{}
 	stopOnce sync.Once
 }
@@ -88,32 +111,41 @@ func New(opts Options) (*App, error) {
 	app := &App{
 		configPath:       opts.ConfigPath,
 		worktreeOverride: opts.Worktree,
+		hostOverride:     opts.Host,
+		portOverride:     opts.Port,
 		version:          opts.Version,
 		done:             make(chan struct{}),
 	}
 
 	// Load configuration
 	loader := config.NewLoader()
-	cfg, err := loader.LoadWithDefaults(context.Background(), opts.ConfigPath)
+	doc, err := loader.LoadDocument(context.Background(), opts.ConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	cfg, err := doc.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	app.configDoc.Store(doc)
 	app.originalConfig = cfg // Keep unexpanded config for worktree switching
 	app.config = cfg
 
 	// Override host/port if specified
-	if opts.Host != "" {
-		cfg.Server.Host = opts.Host
-	}
-	if opts.Port > 0 {
-		cfg.Server.Port = opts.Port
-	}
+	app.applyOverrides(cfg)
 
 	// Initialize event bus
-	app.eventBus = events.NewMemoryEventBus(events.MemoryBusConfig{
+	busCfg := events.MemoryBusConfig{
 		HistoryMaxEvents: cfg.Events.History.MaxEvents,
 		HistoryMaxAge:    config.ParseDuration(cfg.Events.History.MaxAge, time.Hour),
-	})
+	}
+	if cfg.Events.History.Persist {
+		busCfg.HistoryDir = cfg.Events.History.Dir
+		if busCfg.HistoryDir == "" {
+			busCfg.HistoryDir = filepath.Join(filepath.Dir(app.configPath), ".trellis", "events")
+		}
+	}
+	app.eventBus = events.NewMemoryEventBus(busCfg)
 
 	return app, nil
 }
@@ -266,6 +298,15 @@ func (app *App) Initialize(ctx context.Context) error {
 		log.Printf("Warning: no worktrees found, template expansion may not work correctly")
 	}
 
+	// Layer the active worktree's config overlay, if it has one. Sections
+	// already in use, such as events and worktree, keep their base values.
+	if active := app.worktreeManager.Active(); active != nil && app.useWorktreeConfig(active.Path) {
+		cfg = app.originalConfig
+	}
+
+	// Worktree ports are needed to expand the config
+	app.initWorktreeServices(cfg)
+
 	// Install the agent skill file into the repo and each worktree so coding
 	// agents discover trellis-ctl, and keep new worktrees covered. Best-effort
 	// and run in the background so it never delays startup.
@@ -298,14 +339,8 @@ func (app *App) Initialize(ctx context.Context) error {
 		},
 	}
 	if active := app.worktreeManager.Active(); active != nil {
-		templateCtx.Worktree.Root = active.Path
-		templateCtx.Worktree.Branch = active.Branch
-		templateCtx.Worktree.Name = active.Name()
-		templateCtx.Worktree.Binaries = cfg.Worktree.Binaries.Path
-		// Also expand binaries path if it has templates
-		if expandedBin, err := expander.Expand(cfg.Worktree.Binaries.Path, templateCtx); err == nil {
-			templateCtx.Worktree.Binaries = expandedBin
-		}
+		templateCtx = app.worktreeTemplateContext(cfg, active)
+		app.servicesWorktree = active.Name()
 		log.Printf("Template context: Root=%s, Name=%s, Branch=%s, Binaries=%s",
 			templateCtx.Worktree.Root, templateCtx.Worktree.Name,
 			templateCtx.Worktree.Branch, templateCtx.Worktree.Binaries)
@@ -447,27 +482,30 @@ func (app *App) Initialize(ctx context.Context) error {
 		StateDir:      terminalStateDir,
 	})
 
+	// Secrets referenced from service and workflow environments come from
+	// the project's local store, then the configured secrets command
+	if secretsDir, err := secrets.DefaultDir(); err != nil {
+		log.Printf("Warning: secret store unavailable: %v", err)
+	} else {
+		project := cfg.Project.Name
+		if project == "" {
+			project = filepath.Base(repoDir)
+		}
+		store := secrets.NewStore(secretsDir, project)
+		app.secretManager = secrets.NewManager(store, cfg.Secrets.Command)
+		log.Printf("Using secret store %s", store.Path())
+	}
+
 	// Initialize service manager (use expanded config)
-	app.serviceManager = service.NewManager(app.config.Services, app.eventBus, nil)
+	// Services with logging.persist keep their log buffers under .trellis/logs
+	logStateDir := filepath.Join(filepath.Dir(app.configPath), ".trellis", "logs")
+	serviceManager := service.NewManager(app.config.Services, app.eventBus, nil)
+	serviceManager.SetLogDir(filepath.Join(logStateDir, "services"))
+	serviceManager.SetSecrets(app.secretManager)
+	app.serviceManager = serviceManager
 
 	// Initialize workflow runner (use expanded config)
-	workflowConfigs := make([]workflow.WorkflowConfig, 0, len(app.config.Workflows))
-	for _, wf := range app.config.Workflows {
-		workflowConfigs = append(workflowConfigs, workflow.WorkflowConfig{
-			ID:              wf.ID,
-			Name:            wf.Name,
-			Description:     wf.Description,
-			Command:         getCommandAsStrings(wf.Command),
-			Commands:        getCommandsAsArray(wf.Commands),
-			Timeout:         config.ParseDuration(wf.Timeout, 0),
-			OutputParser:    wf.OutputParser,
-			Confirm:         wf.Confirm,
-			ConfirmMessage:  wf.ConfirmMessage,
-			RequiresStopped: wf.RequiresStopped,
-			RestartServices: wf.RestartServices,
-			Inputs:          convertWorkflowInputs(wf.Inputs),
-		})
-	}
+	workflowConfigs := convertWorkflows(app.config.Workflows)
 
 	workingDir := ""
 	if active := app.worktreeManager.Active(); active != nil {
@@ -479,13 +517,37 @@ func (app *App) Initialize(ctx context.Context) error {
 		&serviceControllerAdapter{app.serviceManager},
 		workingDir,
 	)
+	app.workflowRunner.SetSecrets(app.secretManager)
+	app.workflowRunner.SetParsers(convertOutputParsers(expandedConfig.OutputParsers))
+
+	// Record completed workflow runs
+	historyDir := expandedConfig.WorkflowHistory.Dir
+	if historyDir == "" {
+		historyDir = filepath.Join(filepath.Dir(app.configPath), ".trellis", "workflows")
+	}
+	historyMaxRuns := expandedConfig.WorkflowHistory.MaxRuns
+	if historyMaxRuns == 0 {
+		historyMaxRuns = 200
+	}
+	history, err := workflow.NewHistory(historyDir, config.ParseDuration(expandedConfig.WorkflowHistory.MaxAge, 30*24*time.Hour), historyMaxRuns)
+	if err != nil {
+		log.Printf("Warning: failed to initialize workflow history: %v", err)
+	} else {
+		app.workflowHistory = history
+		app.workflowRunner.SetHistory(history)
+	}
+
+	// Start workflows on file changes, events and schedules once running
+	app.workflowTriggers = workflow.NewTriggers(app.workflowRunner, app.eventBus)
 
-	// Initialize log manager (if services exist OR log viewers configured)
-	// Use the expanded config from here on: createServiceLogViewers and
-	// injectServicesTraceGroup append svc:* entries to the config they're
-	// given, and the pristine originalConfig must not accumulate them.
-	if len(expandedConfig.LogViewers) > 0 || len(expandedConfig.Services) > 0 {
+	// Initialize log manager (if services, log viewers or proxies are configured)
+	// Use the expanded config from here on: createServiceLogViewers,
+	// createProxyLogViewers and injectServicesTraceGroup append svc:* and
+	// proxy:* entries to the config they're given, and the pristine
+	// originalConfig must not accumulate them.
+	if len(expandedConfig.LogViewers) > 0 || len(expandedConfig.Services) > 0 || len(expandedConfig.Proxy) > 0 {
 		app.logManager = logs.NewManager(app.eventBus, expandedConfig.LogViewerSettings)
+		app.logManager.SetPersistDir(filepath.Join(logStateDir, "viewers"))
 		if len(expandedConfig.LogViewers) > 0 {
 			if err := app.logManager.Initialize(expandedConfig.LogViewers); err != nil {
 				log.Printf("Warning: failed to initialize log viewers: %v", err)
@@ -495,10 +557,16 @@ func (app *App) Initialize(ctx context.Context) error {
 		}
 	}
 
-	// Create service log viewers (svc:* viewers from service log buffers)
-	if app.logManager != nil && len(expandedConfig.Services) > 0 {
-		svcViewerNames := app.createServiceLogViewers(expandedConfig)
-		app.injectServicesTraceGroup(expandedConfig, svcViewerNames)
+	// Create service log viewers (svc:* viewers from service log buffers) and
+	// proxy access log viewers (proxy:* viewers fed by the proxy listeners)
+	if app.logManager != nil {
+		app.proxyAccessLogs = newProxyAccessLogs(expandedConfig.Proxy)
+		var viewerNames []string
+		if len(expandedConfig.Services) > 0 {
+			viewerNames = app.createServiceLogViewers(expandedConfig)
+		}
+		viewerNames = append(viewerNames, app.createProxyLogViewers(expandedConfig)...)
+		app.injectServicesTraceGroup(expandedConfig, viewerNames)
 	}
 
 	// Initialize trace manager (requires log manager)
@@ -541,6 +609,7 @@ func (app *App) Initialize(ctx context.Context) error {
 		log.Printf("Warning: failed to initialize crash manager: %v", err)
 	} else {
 		app.crashManager = crashMgr
+		app.crashManager.SetRedactor(app.secretManager.Redact)
 		if err := app.crashManager.Subscribe(); err != nil {
 			log.Printf("Warning: failed to subscribe crash manager to events: %v", err)
 		} else {
@@ -548,6 +617,26 @@ func (app *App) Initialize(ctx context.Context) error {
 		}
 	}
 
+	// Initialize outbound event webhooks
+	if len(cfg.Events.Webhooks) > 0 {
+		app.webhookDispatcher = events.NewWebhookDispatcher(app.eventBus, convertWebhooks(cfg.Events.Webhooks))
+		if err := app.webhookDispatcher.Start(); err != nil {
+			log.Printf("Warning: failed to start event webhooks: %v", err)
+		} else {
+			log.Printf("Initialized %d event webhooks", len(cfg.Events.Webhooks))
+		}
+	}
+
+	// Initialize log alert rules (evaluation starts with the log viewers)
+	if len(expandedConfig.Alerts) > 0 {
+		rules, err := alerts.CompileRules(expandedConfig.Alerts)
+		if err != nil {
+			log.Printf("Warning: failed to load alert rules: %v", err)
+		} else {
+			app.alertManager = alerts.NewManager(rules, app.logManager, app.serviceManager, app.eventBus)
+		}
+	}
+
 	// Initialize binary watcher (use expanded config for paths)
 	debounce := config.ParseDuration(app.config.Watch.Debounce, 100*time.Millisecond)
 	bw, err := watcher.NewBinaryWatcher(app.eventBus, debounce)
@@ -603,6 +692,9 @@ func (app *App) Initialize(ctx context.Context) error {
 		}
 		log.Printf("Worktree activated event received: name=%s path=%s branch=%s", worktreeName, worktreePath, worktreeBranch)
 
+		app.configMu.Lock()
+		defer app.configMu.Unlock()
+
 		// Update default worktree on event bus for future events
 		app.eventBus.SetDefaultWorktree(worktreeName)
 
@@ -610,10 +702,20 @@ func (app *App) Initialize(ctx context.Context) error {
 		// may be cancelled when the response is sent, which would kill the processes
 		bgCtx := context.Background()
 
-		// Stop all services first
+		// Stop all services first, keeping note of whether the previous
+		// worktree's were up to carry on running them
+		previous := ""
+		if app.servicesRunning() {
+			previous = app.servicesWorktree
+		}
 		if err := app.serviceManager.StopAll(bgCtx); err != nil {
 			log.Printf("Warning: failed to stop services: %v", err)
 		}
+		app.servicesWorktree = worktreeName
+		app.switchWorktreeServices(bgCtx, previous, worktreeName)
+
+		// Swap in the new worktree's config overlay
+		app.useWorktreeConfig(worktreePath)
 
 		// Re-expand config templates with new worktree path using ORIGINAL unexpanded config
 		expander := config.NewTemplateExpander()
@@ -622,6 +724,7 @@ func (app *App) Initialize(ctx context.Context) error {
 				Root:   worktreePath,
 				Branch: worktreeBranch,
 				Name:   worktreeName,
+				Ports:  app.portAllocator(),
 			},
 		}
 		// Expand binaries path from original config
@@ -648,55 +751,13 @@ func (app *App) Initialize(ctx context.Context) error {
 		app.serviceManager.UpdateConfigs(expandedConfig.Services)
 
 		// Update binary watcher paths
-		if app.binaryWatcher != nil {
-			// Build set of services that should be watched
-			shouldWatch := make(map[string]bool)
-			for _, svc := range expandedConfig.Services {
-				if !svc.IsWatching() {
-					continue
-				}
-				var paths []string
-				if binaryPath := svc.GetBinaryPath(); binaryPath != "" {
-					paths = append(paths, binaryPath)
-				}
-				paths = append(paths, svc.WatchFiles...)
-				if len(paths) > 0 {
-					shouldWatch[svc.Name] = true
-					if err := app.binaryWatcher.Watch(svc.Name, paths); err != nil {
-						log.Printf("Warning: failed to update watch for %s: %v", svc.Name, err)
-					}
-				}
-			}
-			// Remove watches for services no longer in config or disabled
-			for _, name := range app.binaryWatcher.Watching() {
-				if !shouldWatch[name] {
-					if err := app.binaryWatcher.Unwatch(name); err != nil {
-						log.Printf("Warning: failed to remove watch for %s: %v", name, err)
-					}
-				}
-			}
-		}
+		app.updateBinaryWatches(expandedConfig)
 
 		// Update workflow runner with new configs and working directory
 		if app.workflowRunner != nil {
-			workflowConfigs := make([]workflow.WorkflowConfig, 0, len(expandedConfig.Workflows))
-			for _, wf := range expandedConfig.Workflows {
-				workflowConfigs = append(workflowConfigs, workflow.WorkflowConfig{
-					ID:              wf.ID,
-					Name:            wf.Name,
-					Description:     wf.Description,
-					Command:         getCommandAsStrings(wf.Command),
-					Commands:        getCommandsAsArray(wf.Commands),
-					Timeout:         config.ParseDuration(wf.Timeout, 0),
-					OutputParser:    wf.OutputParser,
-					Confirm:         wf.Confirm,
-					ConfirmMessage:  wf.ConfirmMessage,
-					RequiresStopped: wf.RequiresStopped,
-					RestartServices: wf.RestartServices,
-					Inputs:          convertWorkflowInputs(wf.Inputs),
-				})
-			}
-			app.workflowRunner.UpdateConfig(workflowConfigs, worktreePath)
+			workflows := convertWorkflows(expandedConfig.Workflows)
+			app.workflowRunner.UpdateConfig(workflows, worktreePath)
+			app.updateWorkflowTriggers(workflows)
 			log.Printf("Updated workflow runner for worktree: %s", worktreePath)
 		}
 
@@ -709,11 +770,14 @@ func (app *App) Initialize(ctx context.Context) error {
 				log.Printf("Warning: failed to update log viewers: %v", err)
 			}
 
-			// Recreate service log viewers with updated parser configs
+			// Recreate service log viewers with updated parser configs;
+			// proxy viewers were preserved and only rejoin the config
+			var viewerNames []string
 			if len(expandedConfig.Services) > 0 {
-				svcViewerNames := app.createServiceLogViewers(expandedConfig)
-				app.injectServicesTraceGroup(expandedConfig, svcViewerNames)
+				viewerNames = app.createServiceLogViewers(expandedConfig)
 			}
+			viewerNames = append(viewerNames, app.createProxyLogViewers(expandedConfig)...)
+			app.injectServicesTraceGroup(expandedConfig, viewerNames)
 		}
 
 		// Update trace manager with new configs
@@ -721,6 +785,15 @@ func (app *App) Initialize(ctx context.Context) error {
 			app.traceManager.UpdateConfigs(expandedConfig)
 		}
 
+		// Restart proxy listeners whose config changed with the overlay or
+		// the worktree's templates; the rest keep serving
+		if app.proxyManager != nil {
+			app.syncProxyAccessLogs(expandedConfig.Proxy)
+			if err := app.proxyManager.Update(bgCtx, expandedConfig.Proxy); err != nil {
+				log.Printf("Warning: failed to update proxy listeners: %v", err)
+			}
+		}
+
 		// Update crash manager with new service ID fields
 		if app.crashManager != nil {
 			serviceIDFields := config.BuildServiceIDFields(expandedConfig.Services, &expandedConfig.LoggingDefaults)
@@ -760,6 +833,14 @@ func (app *App) Initialize(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize proxy: %w", err)
 		}
+		pm.SetWorktreeSource(&proxyWorktreeAdapter{
+			worktrees: app.worktreeManager,
+			binaries:  app.originalConfig.Worktree.Binaries.Path,
+			ports:     app.portAllocator(),
+		})
+		if app.logManager != nil {
+			pm.SetAccessLog(app.logProxyAccess)
+		}
 		app.proxyManager = pm
 		log.Printf("Initialized %d proxy listeners", len(app.config.Proxy))
 	}
@@ -843,13 +924,21 @@ func (app *App) Initialize(ctx context.Context) error {
 		},
 		api.Dependencies{
 			ServiceManager:    app.serviceManager,
+			ServicePool:       app.servicePool,
+			WorktreePorts:     app.worktreePorts,
 			WorktreeManager:   app.worktreeManager,
 			WorkflowRunner:    app.workflowRunner,
 			TerminalManager:   app.terminalManager,
 			LogManager:        app.logManager,
 			TraceManager:      app.traceManager,
 			CrashManager:      app.crashManager,
+			WorkflowHistory:   app.workflowHistory,
 			EventBus:          app.eventBus,
+			WebhookDispatcher: app.webhookDispatcher,
+			AlertManager:      app.alertManager,
+			ProxyManager:      app.proxyManager,
+			ConfigDocument:    app.configDoc.Load,
+			SecretManager:     app.secretManager,
 			ClaudeManager:     app.claudeManager,
 			CodexManager:      app.codexManager,
 			UsageManager:      usage.NewManager(),
@@ -916,6 +1005,15 @@ func (app *App) Start(ctx context.Context) error {
 		}
 	}
 
+	// Start evaluating log alert rules
+	if app.alertManager != nil {
+		if err := app.alertManager.Start(); err != nil {
+			log.Printf("Warning: failed to start alert rules: %v", err)
+		} else {
+			log.Printf("Started %d log alert rules", len(app.config.Alerts))
+		}
+	}
+
 	// Start proxy listeners
 	if app.proxyManager != nil {
 		if err := app.proxyManager.Start(ctx); err != nil {
@@ -923,6 +1021,14 @@ func (app *App) Start(ctx context.Context) error {
 		}
 	}
 
+	// Start triggered workflows
+	app.configMu.Lock()
+	app.updateWorkflowTriggers(convertWorkflows(app.config.Workflows))
+	app.configMu.Unlock()
+
+	// Reload the config file when it's edited
+	app.watchConfig()
+
 	// Start API server in background
 	go func() {
 		log.Printf("Starting API server on %s:%d", app.config.Server.Host, app.config.Server.Port)
@@ -971,6 +1077,11 @@ func (app *App) Shutdown(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// Stop reloading the config before the components it updates go away
+	if app.configWatcher != nil {
+		app.configWatcher.Close()
+	}
+
 	// Stop API server first to stop accepting new requests
 	if app.apiServer != nil {
 		if err := app.apiServer.Shutdown(shutdownCtx); err != nil {
@@ -990,6 +1101,11 @@ func (app *App) Shutdown(ctx context.Context) error {
 		app.binaryWatcher.Close()
 	}
 
+	// Stop triggering workflows before the runner goes away
+	if app.workflowTriggers != nil {
+		app.workflowTriggers.Close()
+	}
+
 	// Stop workflow runner (cancels running workflows and stops cleanup goroutine)
 	if app.workflowRunner != nil {
 		app.workflowRunner.Close()
@@ -1000,6 +1116,11 @@ func (app *App) Shutdown(ctx context.Context) error {
 		app.traceManager.Close()
 	}
 
+	// Stop alert evaluation before its log sources go away
+	if app.alertManager != nil {
+		app.alertManager.Close()
+	}
+
 	// Stop log viewers
 	if app.logManager != nil {
 		app.logManager.Stop()
@@ -1035,12 +1156,22 @@ func (app *App) Shutdown(ctx context.Context) error {
 			log.Printf("Error stopping services: %v", err)
 		}
 	}
+	if app.servicePool != nil {
+		if err := app.servicePool.StopAll(shutdownCtx); err != nil {
+			log.Printf("Error stopping worktree services: %v", err)
+		}
+	}
 
 	// Stop VS Code handler
 	if app.vsCodeHandler != nil {
 		app.vsCodeHandler.Stop()
 	}
 
+	// Stop webhook deliveries before the bus goes away
+	if app.webhookDispatcher != nil {
+		app.webhookDispatcher.Close()
+	}
+
 	// Close event bus
 	if app.eventBus != nil {
 		app.eventBus.Close()
@@ -1103,6 +1234,46 @@ func (a *serviceControllerAdapter) ClearAllLogs(ctx context.Context) error {
 	return nil
 }
 
+// proxyWorktreeAdapter implements proxy.WorktreeSource with the template
+// data each worktree's config would be expanded with.
+type proxyWorktreeAdapter struct {
+	worktrees worktree.Manager
+	binaries  string // Unexpanded worktree.binaries.path
+	ports     config.PortAllocator
+}
+
+func (a *proxyWorktreeAdapter) Worktrees() []config.WorktreeTemplateData {
+	list, _ := a.worktrees.List()
+	out := make([]config.WorktreeTemplateData, 0, len(list))
+	for i := range list {
+		if !list[i].IsBare {
+			out = append(out, a.templateData(&list[i]))
+		}
+	}
+	return out
+}
+
+func (a *proxyWorktreeAdapter) Active() (config.WorktreeTemplateData, bool) {
+	active := a.worktrees.Active()
+	if active == nil {
+		return config.WorktreeTemplateData{}, false
+	}
+	return a.templateData(active), true
+}
+
+func (a *proxyWorktreeAdapter) templateData(wt *worktree.WorktreeInfo) config.WorktreeTemplateData {
+	data := config.WorktreeTemplateData{
+		Root:   wt.Path,
+		Name:   wt.Name(),
+		Branch: wt.Branch,
+		Ports:  a.ports,
+	}
+	if bin, err := config.NewTemplateExpander().Expand(a.binaries, &config.TemplateContext{Worktree: data}); err == nil {
+		data.Binaries = bin
+	}
+	return data
+}
+
 // getCommandAsStrings converts a command interface to a string slice.
 // Supports both string (shell-style) and array commands.
 func getCommandAsStrings(cmd interface{}) []string {
@@ -1166,6 +1337,153 @@ func getCommandsAsArray(cmds interface{}) [][]string {
 	return result
 }
 
+// convertWorkflows converts config.WorkflowConfig to workflow.WorkflowConfig.
+func convertWorkflows(workflows []config.WorkflowConfig) []workflow.WorkflowConfig {
+	out := make([]workflow.WorkflowConfig, 0, len(workflows))
+	for _, wf := range workflows {
+		out = append(out, workflow.WorkflowConfig{
+			ID:              wf.ID,
+			Name:            wf.Name,
+			Description:     wf.Description,
+			Command:         getCommandAsStrings(wf.Command),
+			Commands:        getCommandsAsArray(wf.Commands),
+			Timeout:         config.ParseDuration(wf.Timeout, 0),
+			OutputParser:    wf.OutputParser,
+			Results:         wf.Results,
+			Confirm:         wf.Confirm,
+			ConfirmMessage:  wf.ConfirmMessage,
+			RequiresStopped: wf.RequiresStopped,
+			RestartServices: wf.RestartServices,
+			Inputs:          convertWorkflowInputs(wf.Inputs),
+			Env:             wf.Env,
+			EnvFile:         wf.EnvFile,
+			Steps:           convertWorkflowSteps(wf.Steps),
+			Triggers:        convertWorkflowTriggers(wf.Triggers),
+			Artifacts:       wf.Artifacts,
+			CacheKey:        convertCacheKey(wf.CacheKey),
+		})
+	}
+	return out
+}
+
+// convertWorkflowTriggers converts config.WorkflowTriggerConfig to workflow.WorkflowTriggers.
+func convertWorkflowTriggers(tr *config.WorkflowTriggerConfig) *workflow.WorkflowTriggers {
+	if tr == nil {
+		return nil
+	}
+	return &workflow.WorkflowTriggers{
+		Files:    tr.Files,
+		Ignore:   tr.Ignore,
+		Events:   tr.Events,
+		Schedule: tr.Schedule,
+		Debounce: config.ParseDuration(tr.Debounce, 0),
+		Policy:   workflow.TriggerPolicy(tr.Policy),
+	}
+}
+
+// updateWorkflowTriggers watches the triggers of workflows in the active
+// worktree. Caller must hold app.configMu.
+func (app *App) updateWorkflowTriggers(workflows []workflow.WorkflowConfig) {
+	if app.workflowTriggers == nil {
+		return
+	}
+	workDir, worktreeName := "", ""
+	if active := app.worktreeManager.Active(); active != nil {
+		workDir, worktreeName = active.Path, active.Name()
+	}
+	if err := app.workflowTriggers.Update(workflows, workDir, worktreeName); err != nil {
+		log.Printf("Warning: failed to set up workflow triggers: %v", err)
+	}
+}
+
+// convertWorkflowSteps converts config.WorkflowStepConfig to workflow.WorkflowStep.
+func convertWorkflowSteps(steps []config.WorkflowStepConfig) []workflow.WorkflowStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	result := make([]workflow.WorkflowStep, len(steps))
+	for i, step := range steps {
+		result[i] = workflow.WorkflowStep{
+			ID:              step.ID,
+			Name:            step.Name,
+			Command:         getCommandAsStrings(step.Command),
+			Workflow:        step.Workflow,
+			Inputs:          step.Inputs,
+			Needs:           step.Needs,
+			If:              step.If,
+			ContinueOnError: step.ContinueOnError,
+			Timeout:         config.ParseDuration(step.Timeout, 0),
+			Env:             step.Env,
+			OutputParser:    step.OutputParser,
+			Results:         step.Results,
+			Artifacts:       step.Artifacts,
+			CacheKey:        convertCacheKey(step.CacheKey),
+		}
+	}
+	return result
+}
+
+// convertCacheKey converts config.CacheKeyConfig to workflow.CacheKey.
+func convertCacheKey(ck *config.CacheKeyConfig) *workflow.CacheKey {
+	if ck == nil {
+		return nil
+	}
+	return &workflow.CacheKey{Files: ck.Files, Commit: ck.Commit}
+}
+
+// convertOutputParsers creates the output parsers declared in the config.
+// Parsers that fail to compile are logged and left out.
+func convertOutputParsers(parsers []config.OutputParserConfig) []workflow.OutputParser {
+	var result []workflow.OutputParser
+	for _, p := range parsers {
+		rules := make([]workflow.RegexRule, len(p.Rules))
+		for i, rule := range p.Rules {
+			rules[i] = workflow.RegexRule{Pattern: rule.Pattern, Type: rule.Type}
+		}
+		parser, err := workflow.NewRegexParser(p.Name, rules)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		result = append(result, parser)
+	}
+	return result
+}
+
+// updateBinaryWatches points the binary watcher at the binaries and watch
+// files of cfg's watched services, dropping watches of any other service.
+func (app *App) updateBinaryWatches(cfg *config.Config) {
+	if app.binaryWatcher == nil {
+		return
+	}
+	// Build set of services that should be watched
+	shouldWatch := make(map[string]bool)
+	for _, svc := range cfg.Services {
+		if !svc.IsWatching() {
+			continue
+		}
+		var paths []string
+		if binaryPath := svc.GetBinaryPath(); binaryPath != "" {
+			paths = append(paths, binaryPath)
+		}
+		paths = append(paths, svc.WatchFiles...)
+		if len(paths) > 0 {
+			shouldWatch[svc.Name] = true
+			if err := app.binaryWatcher.Watch(svc.Name, paths); err != nil {
+				log.Printf("Warning: failed to update watch for %s: %v", svc.Name, err)
+			}
+		}
+	}
+	// Remove watches for services no longer in config or disabled
+	for _, name := range app.binaryWatcher.Watching() {
+		if !shouldWatch[name] {
+			if err := app.binaryWatcher.Unwatch(name); err != nil {
+				log.Printf("Warning: failed to remove watch for %s: %v", name, err)
+			}
+		}
+	}
+}
+
 // convertWorkflowInputs converts config.WorkflowInput to workflow.WorkflowInput.
 func convertWorkflowInputs(inputs []config.WorkflowInput) []workflow.WorkflowInput {
 	if len(inputs) == 0 {
@@ -1189,6 +1507,30 @@ func convertWorkflowInputs(inputs []config.WorkflowInput) []workflow.WorkflowInp
 	return result
 }
 
+// convertWebhooks converts config.WebhookConfig to events.Webhook.
+func convertWebhooks(hooks []config.WebhookConfig) []events.Webhook {
+	result := make([]events.Webhook, len(hooks))
+	for i, hook := range hooks {
+		id := hook.ID
+		if id == "" {
+			id = fmt.Sprintf("webhook-%d", i+1)
+		}
+		maxAttempts := 0 // dispatcher default
+		if hook.Retries > 0 {
+			maxAttempts = 1 + hook.Retries
+		}
+		result[i] = events.Webhook{
+			ID:          id,
+			URL:         hook.URL,
+			Events:      hook.Events,
+			Secret:      hook.Secret,
+			Timeout:     config.ParseDuration(hook.Timeout, 10*time.Second),
+			MaxAttempts: maxAttempts,
+		}
+	}
+	return result
+}
+
 // serviceLogAdapter implements logs.ServiceLogProvider by delegating to service.Manager.
 type serviceLogAdapter struct {
 	mgr service.Manager
@@ -1202,10 +1544,26 @@ func (a *serviceLogAdapter) ServiceLogSize(name string) (int, error) {
 	return a.mgr.LogSize(name)
 }
 
+// ServiceLogRange implements logs.ServiceLogRangeProvider, reaching persisted
+// lines that have scrolled out of the service's buffer.
+func (a *serviceLogAdapter) ServiceLogRange(name string, start, end time.Time) ([]string, error) {
+	lines, err := a.mgr.LogsRange(name, start, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(lines))
+	for i, l := range lines {
+		result[i] = l.Line
+	}
+	return result, nil
+}
+
 // createServiceLogViewers creates svc:* log viewers from running services' in-memory
 // log buffers. Only services with a parser configured (after applying LoggingDefaults)
 // are included, since services without a parser can't participate in structured tracing.
-// Returns the list of viewer names created.
+// Viewers that already exist are kept, as for proxy viewers; callers remove them
+// first with RemoveServiceViewers when service configs change.
+// Returns the list of viewer names.
 func (app *App) createServiceLogViewers(cfg *config.Config) []string {
 	provider := &serviceLogAdapter{mgr: app.serviceManager}
 	var viewerNames []string
@@ -1231,42 +1589,166 @@ func (app *App) createServiceLogViewers(cfg *config.Config) []string {
 		}
 
 		// Create source and viewer
-		source := logs.NewServiceSource(svcCfg.Name, provider)
-		viewer, err := logs.NewViewerWithSource(viewerCfg, source)
-		if err != nil {
-			log.Printf("Warning: failed to create service log viewer for %s: %v", svcCfg.Name, err)
-			continue
+		if _, exists := app.logManager.Get(viewerName); !exists {
+			source := logs.NewServiceSource(svcCfg.Name, provider)
+			viewer, err := logs.NewViewerWithSource(viewerCfg, source)
+			if err != nil {
+				log.Printf("Warning: failed to create service log viewer for %s: %v", svcCfg.Name, err)
+				continue
+			}
+			app.logManager.AddViewer(viewer)
 		}
-
-		app.logManager.AddViewer(viewer)
 		viewerNames = append(viewerNames, viewerName)
+		setLogViewerConfig(cfg, viewerCfg)
+	}
 
-		// Add config to cfg.LogViewers so trace manager's logViewerConfig map
-		// picks up the parser.id field for two-pass ID expansion. Replace any
-		// existing entry with the same name (idempotent across repeat calls).
-		replaced := false
-		for i := range cfg.LogViewers {
-			if cfg.LogViewers[i].Name == viewerName {
-				cfg.LogViewers[i] = viewerCfg
-				replaced = true
-				break
-			}
+	if len(viewerNames) > 0 {
+		log.Printf("Created %d service log viewers: %v", len(viewerNames), viewerNames)
+	}
+
+	return viewerNames
+}
+
+// setLogViewerConfig adds the config of a viewer created at runtime to
+// cfg.LogViewers, so the trace manager's logViewerConfig map picks up the
+// parser.id field for two-pass ID expansion. Any existing entry with the same
+// name is replaced (idempotent across repeat calls).
+func setLogViewerConfig(cfg *config.Config, viewerCfg config.LogViewerConfig) {
+	for i := range cfg.LogViewers {
+		if cfg.LogViewers[i].Name == viewerCfg.Name {
+			cfg.LogViewers[i] = viewerCfg
+			return
 		}
-		if !replaced {
-			cfg.LogViewers = append(cfg.LogViewers, viewerCfg)
+	}
+	cfg.LogViewers = append(cfg.LogViewers, viewerCfg)
+}
+
+// proxyAccessLogViewerConfig is the config of every proxy:* viewer. Entries
+// are the JSON written by logProxyAccess; trace_id links them to backend logs.
+var proxyAccessLogViewerConfig = config.LogViewerConfig{
+	Parser: config.LogParserConfig{
+		Type:      "json",
+		Timestamp: "time",
+		Level:     "level",
+		Message:   "message",
+		ID:        "trace_id",
+	},
+	Layout: []config.LayoutColumnConfig{
+		{Field: "time", Timestamp: true},
+		{Field: "level", MinWidth: 5},
+		{Field: "message"},
+		{Field: "upstream", Optional: true},
+		{Field: "trace_id", Optional: true},
+	},
+}
+
+// newProxyAccessLogs creates the access log source of each proxy listener
+// that logs.
+func newProxyAccessLogs(listeners []config.ProxyListenerConfig) map[string]*logs.PushSource {
+	sources := make(map[string]*logs.PushSource)
+	for _, listener := range listeners {
+		if name := listener.AccessLogViewer(); name != "" {
+			sources[listener.Listen] = logs.NewPushSource(name)
 		}
 	}
+	return sources
+}
 
-	if len(viewerNames) > 0 {
-		log.Printf("Created %d service log viewers: %v", len(viewerNames), viewerNames)
+// createProxyLogViewers creates a proxy:* log viewer for each proxy
+// listener's access log. Viewers that already exist are kept (the log
+// manager preserves them across config updates), so only their config is
+// re-added to cfg. Returns the list of viewer names.
+func (app *App) createProxyLogViewers(cfg *config.Config) []string {
+	var viewerNames []string
+	for _, listener := range cfg.Proxy {
+		app.proxyAccessLogsMu.RLock()
+		source, ok := app.proxyAccessLogs[listener.Listen]
+		app.proxyAccessLogsMu.RUnlock()
+		if !ok {
+			continue
+		}
+		viewerCfg := proxyAccessLogViewerConfig
+		viewerCfg.Name = source.Name()
+
+		if _, exists := app.logManager.Get(viewerCfg.Name); !exists {
+			viewer, err := logs.NewViewerWithSource(viewerCfg, source)
+			if err != nil {
+				log.Printf("Warning: failed to create proxy log viewer for %s: %v", listener.Listen, err)
+				continue
+			}
+			app.logManager.AddViewer(viewer)
+		}
+		viewerNames = append(viewerNames, viewerCfg.Name)
+		setLogViewerConfig(cfg, viewerCfg)
 	}
 
+	if len(viewerNames) > 0 {
+		log.Printf("Created %d proxy access log viewers: %v", len(viewerNames), viewerNames)
+	}
 	return viewerNames
 }
 
+// logProxyAccess writes a proxy access log entry to its listener's viewer.
+// Entries are levelled by status: 5xx and aborted requests are errors, 4xx
+// warnings.
+func (app *App) logProxyAccess(e proxy.AccessLogEntry) {
+	app.proxyAccessLogsMu.RLock()
+	source, ok := app.proxyAccessLogs[e.Listener]
+	app.proxyAccessLogsMu.RUnlock()
+	if !ok {
+		return
+	}
+
+	level := "info"
+	switch {
+	case e.Status == 0 || e.Status >= 500:
+		level = "error"
+	case e.Status >= 400:
+		level = "warn"
+	}
+	status := strconv.Itoa(e.Status)
+	if e.Status == 0 {
+		status = "aborted"
+	}
+	durationMS := float64(e.Duration.Microseconds()) / 1000
+
+	fields := map[string]any{
+		"time":        e.Time.UTC().Format(time.RFC3339Nano),
+		"level":       level,
+		"message":     fmt.Sprintf("%s %s %s %.1fms", e.Method, e.URL, status, durationMS),
+		"method":      e.Method,
+		"path":        e.URL,
+		"host":        e.Host,
+		"status":      e.Status,
+		"bytes":       e.Bytes,
+		"duration_ms": durationMS,
+		"route":       e.Route,
+		"route_name":  e.RouteName,
+		"client":      e.Client,
+		"listener":    e.Listener,
+	}
+	optional := map[string]string{
+		"upstream": e.Upstream,
+		"worktree": e.Worktree,
+		"trace_id": e.TraceID,
+		"error":    e.Error,
+		"fault":    e.Fault,
+		"mock":     e.Mock,
+	}
+	for k, v := range optional {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	if e.ReplayOf != 0 {
+		fields["replay_of"] = e.ReplayOf
+	}
+	source.Emit(e.Time, e.TraceID, fields)
+}
+
 // injectServicesTraceGroup ensures a "services" trace group exists that includes
-// all svc:* viewers. If the group already exists in config, the service viewer
-// names are appended; otherwise a new group is created.
+// all svc:* and proxy:* viewers. If the group already exists in config, the
+// viewer names are appended; otherwise a new group is created.
 func (app *App) injectServicesTraceGroup(cfg *config.Config, viewerNames []string) {
 	if len(viewerNames) == 0 {
 		return
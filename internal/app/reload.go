@@ -0,0 +1,341 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/service"
+	"github.com/wingedpig/trellis/internal/watcher"
+)
+
+// configReloadDebounce is how long the config file must be quiet before it
+// is reloaded, so an editor's save is read once, complete.
+const configReloadDebounce = 300 * time.Millisecond
+
+// reloadNoops are sections whose changes need no action.
+var reloadNoops = map[string]bool{
+	"version": true,
+}
+
+// applyOverrides applies the command line host and port to cfg.
+func (app *App) applyOverrides(cfg *config.Config) {
+	if app.hostOverride != "" {
+		cfg.Server.Host = app.hostOverride
+	}
+	if app.portOverride > 0 {
+		cfg.Server.Port = app.portOverride
+	}
+}
+
+// watchConfig reloads the config whenever its file changes.
+func (app *App) watchConfig() {
+	if app.configPath == "" {
+		return
+	}
+	w, err := watcher.NewFileWatcher(app.configPath, configReloadDebounce, func() {
+		app.reloadConfig(context.Background())
+	})
+	if err != nil {
+		log.Printf("Warning: failed to watch config file, hot reload disabled: %v", err)
+		return
+	}
+	app.configWatcher = w
+	app.watchConfigFiles()
+	log.Printf("Watching %s for changes", w.Path())
+}
+
+// watchConfigFiles points the config watcher at the files of the current
+// config document: includes, override files and the worktree overlay.
+func (app *App) watchConfigFiles() {
+	if app.configWatcher == nil {
+		return
+	}
+	if err := app.configWatcher.SetPaths(app.configDoc.Load().WatchFiles()); err != nil {
+		log.Printf("Warning: failed to watch config files: %v", err)
+	}
+}
+
+// useWorktreeConfig layers the config overlay of the worktree at root, if
+// it has one, onto the config files in place of the previous worktree's,
+// and makes the result the original config. It reports whether the original
+// config was replaced; a broken overlay is logged and leaves it as it was.
+func (app *App) useWorktreeConfig(root string) bool {
+	prev := app.configDoc.Load()
+	doc, err := prev.WithWorktree(root)
+	if err != nil {
+		log.Printf("Warning: ignoring config overlay of worktree %s: %v", root, err)
+		return false
+	}
+	app.configDoc.Store(doc)
+	app.watchConfigFiles()
+	if doc.Overlay() == "" && prev.Overlay() == "" {
+		return false
+	}
+
+	cfg, err := doc.Config()
+	if err != nil {
+		log.Printf("Warning: ignoring config overlay of worktree %s: %v", root, err)
+		app.configDoc.Store(prev)
+		app.watchConfigFiles()
+		return false
+	}
+	app.applyOverrides(cfg)
+	app.originalConfig = cfg
+	if overlay := doc.Overlay(); overlay != "" {
+		log.Printf("Using config overlay %s", overlay)
+	}
+	return true
+}
+
+// reloadConfig reloads the config file and applies what changed to the
+// running components. An edit that doesn't load or validate is rejected
+// with a config.reload_failed event and the running config is untouched.
+// Sections that can't be applied in place are reported as needing a restart
+// in the config.reloaded event.
+func (app *App) reloadConfig(ctx context.Context) {
+	app.configMu.Lock()
+	payload, ok := app.reloadConfigLocked(ctx)
+	app.configMu.Unlock()
+
+	if payload == nil {
+		return
+	}
+	eventType := events.EventConfigReloaded
+	if !ok {
+		eventType = events.EventConfigReloadFailed
+	}
+	app.eventBus.Publish(ctx, events.Event{Type: eventType, Payload: payload})
+}
+
+// reloadConfigLocked does the work of reloadConfig and returns the payload
+// of the event to publish, nil if nothing changed. Caller must hold
+// app.configMu.
+func (app *App) reloadConfigLocked(ctx context.Context) (map[string]interface{}, bool) {
+	failed := func(err error) (map[string]interface{}, bool) {
+		log.Printf("Config reload failed, keeping the running config: %v", err)
+		return map[string]interface{}{
+			"path":  app.configPath,
+			"error": err.Error(),
+		}, false
+	}
+
+	root := ""
+	if active := app.worktreeManager.Active(); active != nil {
+		root = active.Path
+	}
+	cfg, doc, err := config.NewLoader().Reload(ctx, app.configPath, root, app.originalConfig)
+	if err != nil {
+		return failed(err)
+	}
+	app.applyOverrides(cfg)
+	app.configDoc.Store(doc)
+	app.watchConfigFiles()
+
+	diff := config.DiffConfigs(app.originalConfig, cfg)
+	if len(diff) == 0 {
+		log.Printf("Config file %s changed, but the config is the same", app.configPath)
+		return nil, true
+	}
+
+	expandedConfig, err := config.NewTemplateExpander().ExpandConfig(cfg, app.templateContext(cfg))
+	if err != nil {
+		return failed(err)
+	}
+
+	log.Printf("Reloading config:\n%s", diff)
+	app.originalConfig = cfg
+	app.config = expandedConfig
+
+	applied, restart := []string{}, []string{}
+	for _, section := range diff.Sections() {
+		if app.canReload(section) {
+			applied = append(applied, section)
+		} else {
+			restart = append(restart, section)
+		}
+	}
+	app.applyConfigChanges(ctx, diff, expandedConfig)
+
+	if len(restart) > 0 {
+		log.Printf("Config sections changed that need a restart to take effect: %v", restart)
+	}
+	return map[string]interface{}{
+		"path":             app.configPath,
+		"changes":          diff.Lines(),
+		"diff":             diff.String(),
+		"applied":          applied,
+		"restart_required": restart,
+	}, true
+}
+
+// templateContext returns the template context cfg is expanded with for
+// the active worktree.
+func (app *App) templateContext(cfg *config.Config) *config.TemplateContext {
+	if active := app.worktreeManager.Active(); active != nil {
+		return app.worktreeTemplateContext(cfg, active)
+	}
+	return &config.TemplateContext{}
+}
+
+// canReload reports whether changes to a config section are applied to the
+// running server. Components created at startup only when their section was
+// set, such as the proxy, need a restart to be added.
+func (app *App) canReload(section string) bool {
+	switch section {
+	case "services", "workflows", "output_parsers", "logging_defaults":
+		return true
+	case "watch":
+		return app.binaryWatcher != nil
+	case "log_viewers":
+		return app.logManager != nil
+	case "trace", "trace_groups":
+		return app.traceManager != nil
+	case "proxy":
+		return app.proxyManager != nil
+	case "secrets":
+		return app.secretManager != nil
+	}
+	return reloadNoops[section]
+}
+
+// applyConfigChanges brings the running components in line with cfg, the
+// newly expanded config. Only the components whose sections changed are
+// touched: services with unchanged config keep running, and proxy listeners
+// with unchanged config keep serving. Caller must hold app.configMu.
+func (app *App) applyConfigChanges(ctx context.Context, diff config.Diff, cfg *config.Config) {
+	servicesChanged := diff.Has("services") || diff.Has("logging_defaults")
+
+	if diff.Has("services") {
+		app.applyServiceChanges(ctx, diff.Items("services"), cfg)
+	}
+
+	if diff.Has("watch") && app.binaryWatcher != nil {
+		app.binaryWatcher.SetDebounce(config.ParseDuration(cfg.Watch.Debounce, 100*time.Millisecond))
+	}
+
+	if diff.Has("workflows") && app.workflowRunner != nil {
+		workingDir := ""
+		if active := app.worktreeManager.Active(); active != nil {
+			workingDir = active.Path
+		}
+		workflows := convertWorkflows(cfg.Workflows)
+		app.workflowRunner.UpdateConfig(workflows, workingDir)
+		app.updateWorkflowTriggers(workflows)
+	}
+
+	if diff.Has("output_parsers") && app.workflowRunner != nil {
+		app.workflowRunner.SetParsers(convertOutputParsers(cfg.OutputParsers))
+	}
+
+	if diff.Has("secrets") && app.secretManager != nil {
+		app.secretManager.SetCommand(cfg.Secrets.Command)
+	}
+
+	if diff.Has("proxy") && app.proxyManager != nil {
+		app.syncProxyAccessLogs(cfg.Proxy)
+		if err := app.proxyManager.Update(ctx, cfg.Proxy); err != nil {
+			log.Printf("Warning: failed to update proxy listeners: %v", err)
+		}
+	}
+
+	// Viewers created at runtime join the new config as they did the old
+	if app.logManager != nil {
+		if diff.Has("log_viewers") {
+			if err := app.logManager.UpdateConfigs(cfg.LogViewers); err != nil {
+				log.Printf("Warning: failed to update log viewers: %v", err)
+			}
+		}
+		if servicesChanged {
+			app.logManager.RemoveServiceViewers()
+		}
+		var viewerNames []string
+		if len(cfg.Services) > 0 {
+			viewerNames = app.createServiceLogViewers(cfg)
+		}
+		viewerNames = append(viewerNames, app.createProxyLogViewers(cfg)...)
+		app.injectServicesTraceGroup(cfg, viewerNames)
+	}
+
+	if app.traceManager != nil {
+		app.traceManager.UpdateConfigs(cfg)
+	}
+
+	if servicesChanged && app.crashManager != nil {
+		serviceIDFields := config.BuildServiceIDFields(cfg.Services, &cfg.LoggingDefaults)
+		app.crashManager.UpdateServiceIDFields(serviceIDFields)
+	}
+}
+
+// applyServiceChanges updates the service manager with the new service
+// configs. Changed and removed services are stopped first; changed ones
+// that were running are started again with their new config, as are added
+// services that are enabled.
+func (app *App) applyServiceChanges(ctx context.Context, items map[string]string, cfg *config.Config) {
+	wasRunning := make(map[string]bool)
+	for name, action := range items {
+		if action == config.ChangeAdded {
+			continue
+		}
+		status, err := app.serviceManager.Status(name)
+		if err != nil || (status.State != service.StatusRunning && status.State != service.StatusStarting) {
+			continue
+		}
+		wasRunning[name] = true
+		if err := app.serviceManager.Stop(ctx, name); err != nil {
+			log.Printf("Warning: failed to stop service %s for reload: %v", name, err)
+		}
+	}
+
+	app.serviceManager.UpdateConfigs(cfg.Services)
+	app.updateBinaryWatches(cfg)
+
+	for _, svc := range cfg.Services {
+		switch items[svc.Name] {
+		case config.ChangeChanged:
+			if !wasRunning[svc.Name] {
+				continue
+			}
+		case config.ChangeAdded:
+			if !svc.IsEnabled() {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := app.serviceManager.Start(ctx, svc.Name); err != nil {
+			log.Printf("Warning: failed to start service %s after reload: %v", svc.Name, err)
+		}
+	}
+}
+
+// syncProxyAccessLogs creates access log sources for listeners that log and
+// don't have one, and removes the sources and viewers of listeners that
+// were removed or stopped logging. Viewers for new sources are created with
+// the other runtime viewers.
+func (app *App) syncProxyAccessLogs(listeners []config.ProxyListenerConfig) {
+	if app.logManager == nil {
+		return
+	}
+	app.proxyAccessLogsMu.Lock()
+	sources := newProxyAccessLogs(listeners)
+	var removed []string
+	for listen, source := range app.proxyAccessLogs {
+		if _, ok := sources[listen]; ok {
+			sources[listen] = source
+			continue
+		}
+		removed = append(removed, source.Name())
+	}
+	app.proxyAccessLogs = sources
+	app.proxyAccessLogsMu.Unlock()
+
+	for _, name := range removed {
+		app.logManager.RemoveViewer(name)
+	}
+}
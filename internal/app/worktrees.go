@@ -0,0 +1,150 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/service"
+	"github.com/wingedpig/trellis/internal/worktree"
+)
+
+// initWorktreeServices sets up the worktree port allocator and the pool
+// running the services of worktrees other than the active one.
+func (app *App) initWorktreeServices(cfg *config.Config) {
+	app.concurrentWorktrees = cfg.Worktree.Concurrent
+
+	portsPath := filepath.Join(filepath.Dir(app.configPath), ".trellis", "ports.json")
+	ports, err := worktree.NewPortAllocator(portsPath, cfg.Worktree.Ports.Start, cfg.Worktree.Ports.End)
+	if err != nil {
+		log.Printf("Warning: ignoring worktree port allocations: %v", err)
+		ports, _ = worktree.NewPortAllocator("", cfg.Worktree.Ports.Start, cfg.Worktree.Ports.End)
+	}
+	app.worktreePorts = ports
+
+	// Free the ports of worktrees removed while trellis wasn't running
+	for _, name := range ports.Worktrees() {
+		if _, ok := app.worktreeManager.GetByName(name); !ok {
+			if err := ports.Release(name); err != nil {
+				log.Printf("Warning: failed to release ports of worktree %s: %v", name, err)
+			}
+		}
+	}
+
+	app.servicePool = service.NewPool(app.newWorktreeServices)
+
+	if _, err := app.eventBus.SubscribeAsync(events.EventWorktreeDeleted, func(ctx context.Context, event events.Event) error {
+		name, _ := event.Payload["canonicalName"].(string)
+		if name == "" {
+			return nil
+		}
+		if err := app.servicePool.Stop(ctx, name); err != nil && !errors.Is(err, service.ErrNoWorktreeServices) {
+			log.Printf("Warning: failed to stop services of removed worktree %s: %v", name, err)
+		}
+		return app.worktreePorts.Release(name)
+	}, 4); err != nil {
+		log.Printf("Warning: failed to subscribe worktree services to worktree removal: %v", err)
+	}
+}
+
+// newWorktreeServices creates the manager of a worktree's services when it
+// runs them alongside the active worktree's. They get the worktree's own
+// config, overlay included, expanded with its ports, and their events name
+// the worktree. Called with configMu held during worktree switches, so it
+// must not take it.
+func (app *App) newWorktreeServices(name string) (*service.ServiceManager, error) {
+	if !app.concurrentWorktrees {
+		return nil, errors.New("running services for more than one worktree is disabled; set worktree.concurrent")
+	}
+	wt, ok := app.worktreeManager.GetByName(name)
+	if !ok {
+		return nil, fmt.Errorf("worktree %q not found", name)
+	}
+	if active := app.worktreeManager.Active(); active != nil && active.Path == wt.Path {
+		return nil, fmt.Errorf("worktree %s is active; its services are the main services", wt.Name())
+	}
+
+	doc, err := app.configDoc.Load().WithWorktree(wt.Path)
+	if err != nil {
+		return nil, fmt.Errorf("worktree %s config: %w", wt.Name(), err)
+	}
+	cfg, err := doc.Config()
+	if err != nil {
+		return nil, fmt.Errorf("worktree %s config: %w", wt.Name(), err)
+	}
+	app.applyOverrides(cfg)
+	expanded, err := config.NewTemplateExpander().ExpandConfig(cfg, app.worktreeTemplateContext(cfg, &wt))
+	if err != nil {
+		return nil, fmt.Errorf("worktree %s config: %w", wt.Name(), err)
+	}
+
+	mgr := service.NewManager(expanded.Services, events.WithWorktree(app.eventBus, wt.Name()), nil)
+	mgr.SetLogDir(filepath.Join(filepath.Dir(app.configPath), ".trellis", "logs", "worktrees", wt.Name(), "services"))
+	mgr.SetSecrets(app.secretManager)
+	return mgr, nil
+}
+
+// switchWorktreeServices keeps the previously active worktree's services
+// running in the pool after a switch, and moves the newly active
+// worktree's out of it: they become the main services. Both restart, with
+// the config of the namespace they move to.
+func (app *App) switchWorktreeServices(ctx context.Context, previous, next string) {
+	if app.servicePool == nil || !app.concurrentWorktrees {
+		return
+	}
+	if err := app.servicePool.Stop(ctx, next); err != nil && !errors.Is(err, service.ErrNoWorktreeServices) {
+		log.Printf("Warning: failed to stop services of worktree %s: %v", next, err)
+	}
+	if previous == "" || previous == next {
+		return
+	}
+	// Start in the background: the main services mustn't wait on them
+	go func() {
+		if err := app.servicePool.Start(context.Background(), previous); err != nil {
+			log.Printf("Warning: failed to keep services of worktree %s running: %v", previous, err)
+		}
+	}()
+}
+
+// worktreeTemplateContext returns the template context cfg is expanded
+// with for wt.
+func (app *App) worktreeTemplateContext(cfg *config.Config, wt *worktree.WorktreeInfo) *config.TemplateContext {
+	templateCtx := &config.TemplateContext{
+		Worktree: config.WorktreeTemplateData{
+			Root:   wt.Path,
+			Branch: wt.Branch,
+			Name:   wt.Name(),
+			Ports:  app.portAllocator(),
+		},
+	}
+	if expandedBin, err := config.NewTemplateExpander().Expand(cfg.Worktree.Binaries.Path, templateCtx); err == nil {
+		templateCtx.Worktree.Binaries = expandedBin
+	}
+	return templateCtx
+}
+
+// portAllocator returns the allocator backing {{.Worktree.Port "name"}}.
+// A nil allocator must not become a non-nil interface.
+func (app *App) portAllocator() config.PortAllocator {
+	if app.worktreePorts == nil {
+		return nil
+	}
+	return app.worktreePorts
+}
+
+// servicesRunning reports whether any of the main services is up.
+func (app *App) servicesRunning() bool {
+	for _, svc := range app.serviceManager.List() {
+		if svc.Status.State == service.StatusRunning || svc.Status.State == service.StatusStarting {
+			return true
+		}
+	}
+	return false
+}
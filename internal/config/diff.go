@@ -0,0 +1,211 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Change actions.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// listKeys names the field identifying each entry of the list sections, so
+// entries are compared by name rather than position.
+var listKeys = map[string]string{
+	"services":     "name",
+	"workflows":    "id",
+	"log_viewers":  "name",
+	"trace_groups": "name",
+	"alerts":       "name",
+	"proxy":        "listen",
+}
+
+// Change describes how one part of the config differs between two versions.
+type Change struct {
+	Section string   `json:"section"`          // Top-level key, e.g. "services" or "server"
+	Item    string   `json:"item,omitempty"`   // Entry of a list section, e.g. a service name
+	Action  string   `json:"action"`           // "added", "removed" or "changed"
+	Fields  []string `json:"fields,omitempty"` // Keys that differ in a changed section or entry
+}
+
+// String describes the change, e.g. "services.api changed (command, env)".
+func (c Change) String() string {
+	s := c.Section
+	if c.Item != "" {
+		s += "." + c.Item
+	}
+	s += " " + c.Action
+	if len(c.Fields) > 0 {
+		s += " (" + strings.Join(c.Fields, ", ") + ")"
+	}
+	return s
+}
+
+// Diff lists the changes between two configs, in config file order.
+type Diff []Change
+
+// DiffConfigs compares two configs section by section. Entries of list
+// sections such as services and proxy are matched by name.
+func DiffConfigs(old, new *Config) Diff {
+	oldVal, newVal := reflect.ValueOf(*old), reflect.ValueOf(*new)
+	var diff Diff
+	t := oldVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		section := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		a, b := toValue(oldVal.Field(i).Interface()), toValue(newVal.Field(i).Interface())
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		if key, ok := listKeys[section]; ok {
+			diff = append(diff, diffList(section, key, toList(a), toList(b))...)
+			continue
+		}
+		change := Change{Section: section, Action: ChangeChanged}
+		if am, ok := a.(map[string]any); ok {
+			if bm, ok := b.(map[string]any); ok {
+				change.Fields = changedKeys(am, bm)
+			}
+		}
+		diff = append(diff, change)
+	}
+	return diff
+}
+
+// diffList compares the entries of a list section by their key field.
+// Entries without a key are compared by position.
+func diffList(section, key string, old, new []map[string]any) Diff {
+	name := func(entry map[string]any, i int) string {
+		if s, ok := entry[key].(string); ok && s != "" {
+			return s
+		}
+		return "[" + strconv.Itoa(i) + "]"
+	}
+	oldByName := make(map[string]map[string]any, len(old))
+	for i, entry := range old {
+		oldByName[name(entry, i)] = entry
+	}
+
+	var diff Diff
+	seen := make(map[string]bool, len(new))
+	for i, entry := range new {
+		n := name(entry, i)
+		seen[n] = true
+		prev, ok := oldByName[n]
+		if !ok {
+			diff = append(diff, Change{Section: section, Item: n, Action: ChangeAdded})
+			continue
+		}
+		if fields := changedKeys(prev, entry); len(fields) > 0 {
+			diff = append(diff, Change{Section: section, Item: n, Action: ChangeChanged, Fields: fields})
+		}
+	}
+	for i, entry := range old {
+		if n := name(entry, i); !seen[n] {
+			diff = append(diff, Change{Section: section, Item: n, Action: ChangeRemoved})
+		}
+	}
+	if len(diff) == 0 && len(old) == len(new) {
+		for i := range old {
+			if name(old[i], i) != name(new[i], i) {
+				// Same entries in a different order
+				return Diff{{Section: section, Action: ChangeChanged, Fields: []string{"order"}}}
+			}
+		}
+	}
+	return diff
+}
+
+// changedKeys returns the sorted keys whose values differ between a and b.
+func changedKeys(a, b map[string]any) []string {
+	var keys []string
+	for k, v := range a {
+		if !reflect.DeepEqual(v, b[k]) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toValue converts a config value to its generic JSON form, so values
+// compare the same way they are written in the config file.
+func toValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	json.Unmarshal(data, &out)
+	return out
+}
+
+// toList returns the entries of a list section's generic JSON form.
+func toList(v any) []map[string]any {
+	items, _ := v.([]any)
+	out := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		m, _ := item.(map[string]any)
+		out = append(out, m)
+	}
+	return out
+}
+
+// Has reports whether the diff changes the section.
+func (d Diff) Has(section string) bool {
+	for _, c := range d {
+		if c.Section == section {
+			return true
+		}
+	}
+	return false
+}
+
+// Sections returns the changed sections in config file order.
+func (d Diff) Sections() []string {
+	var sections []string
+	for _, c := range d {
+		if len(sections) == 0 || sections[len(sections)-1] != c.Section {
+			sections = append(sections, c.Section)
+		}
+	}
+	return sections
+}
+
+// Items returns the action for each changed entry of a list section.
+func (d Diff) Items(section string) map[string]string {
+	items := make(map[string]string)
+	for _, c := range d {
+		if c.Section == section && c.Item != "" {
+			items[c.Item] = c.Action
+		}
+	}
+	return items
+}
+
+// Lines returns a description of each change.
+func (d Diff) Lines() []string {
+	lines := make([]string, len(d))
+	for i, c := range d {
+		lines[i] = c.String()
+	}
+	return lines
+}
+
+// String describes the changes, one per line.
+func (d Diff) String() string {
+	return strings.Join(d.Lines(), "\n")
+}
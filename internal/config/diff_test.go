@@ -0,0 +1,61 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	old := loadFromString(t, `{
+		version: "1.0"
+		project: { name: "test" }
+		server: { port: 8080 }
+		services: [
+			{ name: "api", command: "./api" }
+			{ name: "worker", command: "./worker" }
+		]
+		proxy: [
+			{ listen: ":8443", routes: [{ upstream: "localhost:3000" }] }
+		]
+	}`)
+
+	assert.Empty(t, DiffConfigs(old, old))
+
+	updated := loadFromString(t, `{
+		version: "1.0"
+		project: { name: "test" }
+		server: { port: 9090, host: "0.0.0.0" }
+		services: [
+			{ name: "api", command: "./api", env: { DEBUG: "1" } }
+			{ name: "web", command: "./web" }
+		]
+		proxy: [
+			{ listen: ":8443", routes: [{ upstream: "localhost:3000" }] }
+		]
+	}`)
+
+	diff := DiffConfigs(old, updated)
+	assert.Equal(t, []string{
+		"server changed (host, port)",
+		"services.api changed (env)",
+		"services.web added",
+		"services.worker removed",
+	}, diff.Lines())
+	assert.Equal(t, []string{"server", "services"}, diff.Sections())
+	assert.True(t, diff.Has("services"))
+	assert.False(t, diff.Has("proxy"))
+	assert.Equal(t, map[string]string{"api": ChangeChanged, "web": ChangeAdded, "worker": ChangeRemoved}, diff.Items("services"))
+}
+
+func TestDiffConfigs_ListOrder(t *testing.T) {
+	old := &Config{Services: []ServiceConfig{{Name: "api"}, {Name: "web"}}}
+	reordered := &Config{Services: []ServiceConfig{{Name: "web"}, {Name: "api"}}}
+	assert.Equal(t, "services changed (order)", DiffConfigs(old, reordered).String())
+
+	// An empty list is the same as no list
+	assert.Empty(t, DiffConfigs(&Config{}, &Config{Services: []ServiceConfig{}}))
+}
@@ -0,0 +1,469 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hjson/hjson-go/v4"
+)
+
+// Layer kinds, from lowest to highest precedence. Included files sit below
+// the file that includes them.
+const (
+	LayerDefault  = "default"  // Filled in by Trellis, not set in any file
+	LayerInclude  = "include"  // A file named by another file's include
+	LayerBase     = "base"     // The config file itself, e.g. trellis.hjson
+	LayerUser     = "user"     // Per-user overrides in the user config directory
+	LayerLocal    = "local"    // Git-ignored overrides next to the config file
+	LayerWorktree = "worktree" // The active worktree's overlay file
+)
+
+// WorktreeOverlayName is the file at the root of a worktree whose settings
+// are layered on top of the config while the worktree is active.
+const WorktreeOverlayName = "trellis.worktree.hjson"
+
+// includeKey is the top-level key naming files to include.
+const includeKey = "include"
+
+// Layer is a file that contributes settings to the config.
+type Layer struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// Value is one setting of the effective config and the layer it came from.
+type Value struct {
+	Path   string `json:"path"` // e.g. "server.port" or "services[api].env.DEBUG"
+	Value  any    `json:"value"`
+	Origin Layer  `json:"origin"`
+}
+
+// Document is a config assembled from layered files, before it is decoded
+// into a Config. Maps are merged key by key, entries of list sections such
+// as services are merged by name, and anything else set by a higher layer
+// replaces the value below it. Each setting remembers the layer that set it.
+type Document struct {
+	values  map[string]any
+	origins map[string]int // setting path -> index into layers
+	layers  []Layer
+	watch   []string  // Files that change the document when written or created
+	base    *Document // The document without its worktree overlay, if it has one
+}
+
+// LocalConfigPath returns the path of the local override file for the config
+// at path: trellis.hjson has trellis.local.hjson.
+func LocalConfigPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".local" + ext
+}
+
+// LoadDocument reads the config at path and layers it with the files it
+// includes, the user's override file for the project, if any, and the local
+// override file next to it, if any.
+func (l *Loader) LoadDocument(ctx context.Context, path string) (*Document, error) {
+	d := &Document{values: map[string]any{}, origins: map[string]int{}, watch: []string{path}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := d.mergeData(data, path, LayerBase, nil); err != nil {
+		return nil, err
+	}
+
+	if name, _ := lookupMap(d.values, "project")["name"].(string); name != "" && l.UserDir != "" {
+		if err := d.mergeOptional(filepath.Join(l.UserDir, filepath.Base(name)+".hjson"), LayerUser); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.mergeOptional(LocalConfigPath(path), LayerLocal); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// WithWorktree returns the document with the overlay file of the worktree at
+// root layered on top, replacing any overlay d already has. An empty root
+// returns the document without an overlay.
+func (d *Document) WithWorktree(root string) (*Document, error) {
+	base := d
+	if d.base != nil {
+		base = d.base
+	}
+	if root == "" {
+		return base, nil
+	}
+	overlaid := base.copy()
+	overlaid.base = base
+	if err := overlaid.mergeOptional(filepath.Join(root, WorktreeOverlayName), LayerWorktree); err != nil {
+		return nil, err
+	}
+	return overlaid, nil
+}
+
+// Layers returns the files that contributed to the document, lowest
+// precedence first.
+func (d *Document) Layers() []Layer {
+	return append([]Layer(nil), d.layers...)
+}
+
+// Overlay returns the worktree overlay file layered into the document, or
+// "" if it has none.
+func (d *Document) Overlay() string {
+	if n := len(d.layers); n > 0 && d.layers[n-1].Kind == LayerWorktree {
+		return d.layers[n-1].Path
+	}
+	return ""
+}
+
+// WatchFiles returns the files whose writes or creation change the
+// document, including optional layers that don't exist yet.
+func (d *Document) WatchFiles() []string {
+	return append([]string(nil), d.watch...)
+}
+
+// Decode converts the document into a Config without applying defaults.
+func (d *Document) Decode() (*Config, error) {
+	jsonData, err := json.Marshal(d.values)
+	if err != nil {
+		return nil, fmt.Errorf("convert to json: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(jsonData, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Config decodes the document, applies defaults and logs validation errors
+// as warnings, as LoadWithDefaults does.
+func (d *Document) Config() (*Config, error) {
+	cfg, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+	applyDefaults(cfg)
+	logValidation(cfg)
+	return cfg, nil
+}
+
+// Effective returns every setting of the config in path order, with the
+// layer that set it. Values Trellis fills in by default are included with
+// the default layer as their origin.
+func (d *Document) Effective() ([]Value, error) {
+	leaves := map[string]any{}
+	flatten(d.values, "", leaves)
+	byPath := make(map[string]Value, len(leaves))
+	for path, v := range leaves {
+		value := Value{Path: path, Value: v}
+		if i, ok := d.origins[path]; ok {
+			value.Origin = d.layers[i]
+		}
+		byPath[path] = value
+	}
+
+	cfg, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+	raw := map[string]any{}
+	flatten(toValue(cfg), "", raw)
+	applyDefaults(cfg)
+	defaulted := map[string]any{}
+	flatten(toValue(cfg), "", defaulted)
+	for path, v := range defaulted {
+		if !reflect.DeepEqual(v, raw[path]) {
+			byPath[path] = Value{Path: path, Value: v, Origin: Layer{Kind: LayerDefault}}
+		}
+	}
+
+	values := make([]Value, 0, len(byPath))
+	for _, v := range byPath {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Path < values[j].Path })
+	return values, nil
+}
+
+// mergeOptional merges the file at path as a layer of the given kind if it
+// exists. The file is watched either way, so creating it is noticed.
+func (d *Document) mergeOptional(path, kind string) error {
+	d.watchFile(path)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	return d.mergeData(data, path, kind, nil)
+}
+
+// mergeData parses a config file and merges it into the document, after the
+// files it includes. including holds the files that led to this one, to
+// catch include cycles.
+func (d *Document) mergeData(data []byte, path, kind string, including []string) error {
+	var raw map[string]interface{}
+	if err := hjson.Unmarshal(data, &raw); err != nil {
+		if kind == LayerBase {
+			return fmt.Errorf("parse hjson: %w", err)
+		}
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	includes, err := includePaths(raw[includeKey], path)
+	if err != nil {
+		return err
+	}
+	delete(raw, includeKey)
+	including = append(including, path)
+	for _, inc := range includes {
+		for _, p := range including {
+			if p == inc {
+				return fmt.Errorf("include cycle: %s", strings.Join(append(including, inc), " -> "))
+			}
+		}
+		d.watchFile(inc)
+		incData, err := os.ReadFile(inc)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", inc, err)
+		}
+		if err := d.mergeData(incData, inc, LayerInclude, including); err != nil {
+			return err
+		}
+	}
+
+	d.layers = append(d.layers, Layer{Kind: kind, Path: path})
+	d.merge(d.values, raw, "", len(d.layers)-1)
+	return nil
+}
+
+// includePaths returns the files named by an include value, a path or a
+// list of paths relative to the including file.
+func includePaths(v any, from string) ([]string, error) {
+	var names []any
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = []any{v}
+	case []any:
+		names = v
+	default:
+		return nil, fmt.Errorf("%s: include must be a path or a list of paths", from)
+	}
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		s, ok := name.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s: include must be a path or a list of paths", from)
+		}
+		if !filepath.IsAbs(s) {
+			s = filepath.Join(filepath.Dir(from), s)
+		}
+		paths = append(paths, s)
+	}
+	return paths, nil
+}
+
+// merge merges src into dst, recording layer as the origin of every setting
+// it sets. prefix is the path of dst.
+func (d *Document) merge(dst, src map[string]any, prefix string, layer int) {
+	for k, v := range src {
+		path := joinPath(prefix, k)
+		switch v := v.(type) {
+		case map[string]any:
+			existing, ok := dst[k].(map[string]any)
+			if !ok {
+				d.clearOrigins(path)
+				existing = map[string]any{}
+				dst[k] = existing
+				if len(v) == 0 {
+					d.origins[path] = layer
+				}
+			}
+			d.merge(existing, v, path, layer)
+		case []any:
+			if key, ok := listKeys[k]; ok && prefix == "" {
+				dst[k] = d.mergeList(dst[k], v, path, key, layer)
+				continue
+			}
+			d.clearOrigins(path)
+			dst[k] = v
+			d.origins[path] = layer
+		default:
+			d.clearOrigins(path)
+			dst[k] = v
+			d.origins[path] = layer
+		}
+	}
+}
+
+// mergeList merges the entries of a list section into the entries below
+// them, matching entries by key. Entries with new keys are appended.
+func (d *Document) mergeList(dst any, src []any, path, key string, layer int) []any {
+	list, ok := dst.([]any)
+	if !ok {
+		d.clearOrigins(path)
+	}
+	// Each entry below is merged with at most one entry of src, so duplicate
+	// names within a file stay separate entries for the validator to report
+	below := len(list)
+	merged := make(map[int]bool)
+	for _, item := range src {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		var target map[string]any
+		if name, _ := entry[key].(string); name != "" {
+			for i, existing := range list[:below] {
+				if m, ok := existing.(map[string]any); ok && m[key] == name && !merged[i] {
+					target = m
+					merged[i] = true
+					break
+				}
+			}
+		}
+		if target == nil {
+			target = map[string]any{}
+			list = append(list, target)
+			d.merge(target, entry, entryPath(path, entry, key, len(list)-1), layer)
+			continue
+		}
+		// The key only names the entry, so it keeps the origin of the layer
+		// that added the entry
+		fields := make(map[string]any, len(entry))
+		for k, v := range entry {
+			if k != key {
+				fields[k] = v
+			}
+		}
+		d.merge(target, fields, entryPath(path, target, key, 0), layer)
+	}
+	return list
+}
+
+// clearOrigins forgets the origins of path and everything below it, for a
+// value that is being replaced.
+func (d *Document) clearOrigins(path string) {
+	for p := range d.origins {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			delete(d.origins, p)
+		}
+	}
+}
+
+// watchFile adds path to the files that change the document.
+func (d *Document) watchFile(path string) {
+	for _, p := range d.watch {
+		if p == path {
+			return
+		}
+	}
+	d.watch = append(d.watch, path)
+}
+
+// copy returns a deep copy of the document.
+func (d *Document) copy() *Document {
+	c := &Document{
+		values:  deepCopy(d.values).(map[string]any),
+		origins: make(map[string]int, len(d.origins)),
+		layers:  append([]Layer(nil), d.layers...),
+		watch:   append([]string(nil), d.watch...),
+	}
+	for k, v := range d.origins {
+		c.origins[k] = v
+	}
+	return c
+}
+
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = deepCopy(e)
+		}
+		return s
+	}
+	return v
+}
+
+// flatten collects the leaves of a generic config value by path. Entries of
+// list sections are named by their key; empty maps and lists are leaves.
+func flatten(v any, prefix string, out map[string]any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for k, e := range v {
+			path := joinPath(prefix, k)
+			if key, ok := listKeys[k]; ok && prefix == "" {
+				if list, ok := e.([]any); ok && len(list) > 0 {
+					for i, item := range list {
+						entry, _ := item.(map[string]any)
+						flatten(entry, entryPath(path, entry, key, i), out)
+					}
+					continue
+				}
+			}
+			flatten(e, path, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
+
+// entryPath returns the path of an entry of a list section, named by its key
+// or, failing that, its index.
+func entryPath(path string, entry map[string]any, key string, index int) string {
+	if name, _ := entry[key].(string); name != "" {
+		return path + "[" + name + "]"
+	}
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func lookupMap(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+// logValidation logs the validation errors of cfg as warnings.
+func logValidation(cfg *Config) {
+	if err := NewValidator().Validate(cfg); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			for _, fe := range verr.Errors {
+				log.Printf("config warning: %s: %s", fe.Field, fe.Message)
+			}
+		} else {
+			log.Printf("config warning: %v", err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func effectiveByPath(t *testing.T, doc *Document) map[string]Value {
+	t.Helper()
+	values, err := doc.Effective()
+	require.NoError(t, err)
+	out := make(map[string]Value)
+	for _, v := range values {
+		out[v.Path] = v
+	}
+	return out
+}
+
+func TestLoadDocument_Layers(t *testing.T) {
+	dir := t.TempDir()
+	userDir := t.TempDir()
+	path := filepath.Join(dir, "trellis.hjson")
+	writeFile(t, filepath.Join(dir, "config", "common.hjson"), `{
+		server: { port: 4000, host: "0.0.0.0" }
+		services: [{ name: "api", command: "./api", env: { LOG: "info" } }]
+	}`)
+	writeFile(t, path, `{
+		include: "config/common.hjson"
+		version: "1.0"
+		project: { name: "myapp" }
+		server: { port: 5000 }
+		services: [{ name: "web", command: "./web" }]
+	}`)
+	writeFile(t, filepath.Join(userDir, "myapp.hjson"), `{
+		services: [{ name: "api", env: { DEBUG: "1" } }]
+	}`)
+	writeFile(t, filepath.Join(dir, "trellis.local.hjson"), `{
+		server: { port: 6000 }
+		services: [{ name: "web", args: ["-v"] }]
+	}`)
+
+	loader := &Loader{UserDir: userDir}
+	doc, err := loader.LoadDocument(context.Background(), path)
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, l := range doc.Layers() {
+		kinds = append(kinds, l.Kind)
+	}
+	assert.Equal(t, []string{LayerInclude, LayerBase, LayerUser, LayerLocal}, kinds)
+
+	cfg, err := doc.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, 6000, cfg.Server.Port)
+	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+	require.Len(t, cfg.Services, 2)
+	assert.Equal(t, "api", cfg.Services[0].Name)
+	assert.Equal(t, "./api", cfg.Services[0].Command)
+	assert.Equal(t, map[string]string{"LOG": "info", "DEBUG": "1"}, cfg.Services[0].Env)
+	assert.Equal(t, "./web", cfg.Services[1].Command)
+	assert.Equal(t, []string{"-v"}, cfg.Services[1].Args)
+
+	values := effectiveByPath(t, doc)
+	assert.Equal(t, LayerLocal, values["server.port"].Origin.Kind)
+	assert.Equal(t, LayerInclude, values["server.host"].Origin.Kind)
+	assert.Equal(t, filepath.Join(dir, "config", "common.hjson"), values["server.host"].Origin.Path)
+	assert.Equal(t, LayerUser, values["services[api].env.DEBUG"].Origin.Kind)
+	assert.Equal(t, LayerInclude, values["services[api].env.LOG"].Origin.Kind)
+	assert.Equal(t, LayerBase, values["services[web].command"].Origin.Kind)
+	assert.Equal(t, LayerInclude, values["services[api].name"].Origin.Kind)
+	assert.Equal(t, LayerDefault, values["terminal.backend"].Origin.Kind)
+	assert.Equal(t, "tmux", values["terminal.backend"].Value)
+	assert.NotContains(t, values, "include")
+
+	assert.Contains(t, doc.WatchFiles(), filepath.Join(dir, "trellis.local.hjson"))
+	assert.Contains(t, doc.WatchFiles(), filepath.Join(dir, "config", "common.hjson"))
+}
+
+func TestLoadDocument_ReplacedValuesLoseOrigins(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trellis.hjson")
+	writeFile(t, path, `{
+		version: "1.0"
+		services: [{ name: "api", command: "./api", args: ["-a", "-b"], env: { A: "1" } }]
+	}`)
+	writeFile(t, filepath.Join(dir, "trellis.local.hjson"), `{
+		services: [{ name: "api", args: ["-c"] }]
+	}`)
+
+	doc, err := (&Loader{}).LoadDocument(context.Background(), path)
+	require.NoError(t, err)
+	cfg, err := doc.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-c"}, cfg.Services[0].Args, "lists other than sections replace")
+
+	values := effectiveByPath(t, doc)
+	assert.Equal(t, LayerLocal, values["services[api].args"].Origin.Kind)
+	assert.Equal(t, LayerBase, values["services[api].env.A"].Origin.Kind)
+}
+
+func TestLoadDocument_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trellis.hjson")
+	writeFile(t, path, `{ include: ["a.hjson"] }`)
+	writeFile(t, filepath.Join(dir, "a.hjson"), `{ include: "trellis.hjson" }`)
+
+	_, err := (&Loader{}).LoadDocument(context.Background(), path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle")
+
+	writeFile(t, path, `{ include: "missing.hjson" }`)
+	_, err = (&Loader{}).LoadDocument(context.Background(), path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.hjson")
+
+	writeFile(t, path, `{ include: 3 }`)
+	_, err = (&Loader{}).LoadDocument(context.Background(), path)
+	assert.Error(t, err)
+}
+
+func TestDocument_WithWorktree(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trellis.hjson")
+	writeFile(t, path, `{
+		version: "1.0"
+		services: [{ name: "api", command: "./api" }]
+	}`)
+	feature := t.TempDir()
+	writeFile(t, filepath.Join(feature, WorktreeOverlayName), `{
+		services: [{ name: "api", env: { FEATURE: "on" } }]
+	}`)
+	plain := t.TempDir()
+
+	base, err := (&Loader{}).LoadDocument(context.Background(), path)
+	require.NoError(t, err)
+
+	overlaid, err := base.WithWorktree(feature)
+	require.NoError(t, err)
+	cfg, err := overlaid.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "on", cfg.Services[0].Env["FEATURE"])
+	assert.Equal(t, LayerWorktree, overlaid.Layers()[len(overlaid.Layers())-1].Kind)
+	assert.Equal(t, LayerWorktree, effectiveByPath(t, overlaid)["services[api].env.FEATURE"].Origin.Kind)
+
+	// Switching worktrees replaces the overlay
+	switched, err := overlaid.WithWorktree(plain)
+	require.NoError(t, err)
+	cfg, err = switched.Decode()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Services[0].Env)
+	assert.Contains(t, switched.WatchFiles(), filepath.Join(plain, WorktreeOverlayName))
+
+	// The base document is untouched
+	cfg, err = base.Decode()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Services[0].Env)
+
+	unlayered, err := overlaid.WithWorktree("")
+	require.NoError(t, err)
+	assert.Same(t, base, unlayered)
+}
+
+func TestLocalConfigPath(t *testing.T) {
+	assert.Equal(t, "/repo/trellis.local.hjson", LocalConfigPath("/repo/trellis.hjson"))
+	assert.Equal(t, "/repo/trellis.local.json", LocalConfigPath("/repo/trellis.json"))
+}
@@ -5,15 +5,12 @@ package config
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
-
-	"github.com/hjson/hjson-go/v4"
 )
 
 // APIBaseURL returns the URL clients (trellis-ctl, terminals via TRELLIS_API)
@@ -45,38 +42,29 @@ func (c *Config) APIBaseURL() string {
 }
 
 // Loader handles configuration file loading.
-type Loader struct{}
+type Loader struct {
+	// UserDir holds per-user override files named after the project, e.g.
+	// ~/.config/trellis/myapp.hjson. Empty disables them.
+	UserDir string
+}
 
 // NewLoader creates a new config loader.
 func NewLoader() *Loader {
-	return &Loader{}
+	l := &Loader{}
+	if dir, err := os.UserConfigDir(); err == nil {
+		l.UserDir = filepath.Join(dir, "trellis")
+	}
+	return l
 }
 
-// Load reads and parses the configuration from the given path.
+// Load reads and parses the configuration from the given path, layered
+// with the files described by LoadDocument.
 func (l *Loader) Load(ctx context.Context, path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	doc, err := l.LoadDocument(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
-	}
-
-	// Parse HJSON to intermediate map
-	var raw map[string]interface{}
-	if err := hjson.Unmarshal(data, &raw); err != nil {
-		return nil, fmt.Errorf("parse hjson: %w", err)
-	}
-
-	// Convert to JSON and unmarshal to struct (for type safety)
-	jsonData, err := json.Marshal(raw)
-	if err != nil {
-		return nil, fmt.Errorf("convert to json: %w", err)
-	}
-
-	var cfg Config
-	if err := json.Unmarshal(jsonData, &cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal config: %w", err)
+		return nil, err
 	}
-
-	return &cfg, nil
+	return doc.Decode()
 }
 
 // LoadWithDefaults loads config with default values applied. The config is
@@ -96,19 +84,63 @@ func (l *Loader) LoadWithDefaults(ctx context.Context, path string) (*Config, er
 	}
 
 	applyDefaults(cfg)
+	logValidation(cfg)
 
-	if err := NewValidator().Validate(cfg); err != nil {
-		var verr *ValidationError
-		if errors.As(err, &verr) {
-			for _, fe := range verr.Errors {
-				log.Printf("config warning: %s: %s", fe.Field, fe.Message)
+	return cfg, nil
+}
+
+// Reload loads the config at path to replace current, the config the server
+// is running with. Unlike LoadWithDefaults, validation errors are returned
+// rather than logged so a bad edit can be rejected. Errors the current config
+// already has are still only warnings, so a config that started with a
+// benign typo can be edited without fixing it first.
+//
+// The overlay of the worktree at worktreeRoot, if any, is applied. The
+// layered document is returned with the config.
+func (l *Loader) Reload(ctx context.Context, path, worktreeRoot string, current *Config) (*Config, *Document, error) {
+	doc, err := l.LoadDocument(ctx, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if doc, err = doc.WithWorktree(worktreeRoot); err != nil {
+		return nil, nil, err
+	}
+	cfg, err := doc.Decode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applyDefaults(cfg)
+
+	err = NewValidator().Validate(cfg)
+	if err == nil {
+		return cfg, doc, nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return nil, nil, err
+	}
+	known := make(map[FieldError]bool)
+	if current != nil {
+		var cerr *ValidationError
+		if errors.As(NewValidator().Validate(current), &cerr) {
+			for _, fe := range cerr.Errors {
+				known[fe] = true
 			}
-		} else {
-			log.Printf("config warning: %v", err)
 		}
 	}
-
-	return cfg, nil
+	added := &ValidationError{}
+	for _, fe := range verr.Errors {
+		if known[fe] {
+			log.Printf("config warning: %s: %s", fe.Field, fe.Message)
+			continue
+		}
+		added.Errors = append(added.Errors, fe)
+	}
+	if !added.IsEmpty() {
+		return nil, nil, added
+	}
+	return cfg, doc, nil
 }
 
 // FindConfig searches for a config file in the current directory.
@@ -215,12 +247,18 @@ func applyDefaults(cfg *Config) {
 		cfg.Watch.Debounce = "100ms"
 	}
 
-	// Events defaults
+	// Events defaults (persisted history keeps more, for longer)
 	if cfg.Events.History.MaxEvents == 0 {
 		cfg.Events.History.MaxEvents = 10000
+		if cfg.Events.History.Persist {
+			cfg.Events.History.MaxEvents = 100000
+		}
 	}
 	if cfg.Events.History.MaxAge == "" {
 		cfg.Events.History.MaxAge = "1h"
+		if cfg.Events.History.Persist {
+			cfg.Events.History.MaxAge = "168h"
+		}
 	}
 
 	// UI defaults
@@ -257,6 +295,12 @@ func applyDefaults(cfg *Config) {
 	if cfg.Worktree.Discovery.Mode == "" {
 		cfg.Worktree.Discovery.Mode = "git"
 	}
+	if cfg.Worktree.Ports.Start == 0 {
+		cfg.Worktree.Ports.Start = 20000
+	}
+	if cfg.Worktree.Ports.End == 0 {
+		cfg.Worktree.Ports.End = min(cfg.Worktree.Ports.Start+999, 65535)
+	}
 
 	// Trace defaults
 	if cfg.Trace.ReportsDir == "" {
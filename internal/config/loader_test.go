@@ -305,6 +305,60 @@ func TestLoader_Load_Defaults(t *testing.T) {
 	assert.Equal(t, "json", cfg.Logging.Format)
 }
 
+func TestLoader_Load_PersistedEventHistoryDefaults(t *testing.T) {
+	configContent := `{
+		version: "1.0"
+		project: { name: "test" }
+		events: { history: { persist: true } }
+	}`
+
+	loader := NewLoader()
+	cfg, err := loader.LoadWithDefaults(context.Background(), writeTestConfig(t, configContent))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Events.History.Persist)
+	assert.Equal(t, 100000, cfg.Events.History.MaxEvents)
+	assert.Equal(t, "168h", cfg.Events.History.MaxAge)
+}
+
+func TestLoader_Reload(t *testing.T) {
+	loader := NewLoader()
+	path := writeTestConfig(t, `{
+		version: "1.0"
+		project: { name: "test" }
+		services: [{ name: "api", command: "./api" }]
+	}`)
+	current, err := loader.LoadWithDefaults(context.Background(), path)
+	require.NoError(t, err)
+
+	// Defaults are applied to the reloaded config
+	cfg, _, err := loader.Reload(context.Background(), path, "", current)
+	require.NoError(t, err)
+	assert.Equal(t, 1234, cfg.Server.Port)
+
+	// A new validation error rejects the edit
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		version: "1.0"
+		project: { name: "test" }
+		services: [{ name: "api", command: "./api" }, { name: "api", command: "./api2" }]
+	}`), 0644))
+	_, _, err = loader.Reload(context.Background(), path, "", current)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "services[1].name", verr.Errors[0].Field)
+
+	// ...unless the running config already had it
+	current = cfg
+	current.Services = append(current.Services, ServiceConfig{Name: "api", Command: "./api2"})
+	_, _, err = loader.Reload(context.Background(), path, "", current)
+	assert.NoError(t, err)
+
+	// Parse errors are always returned
+	require.NoError(t, os.WriteFile(path, []byte(`{ version: `), 0644))
+	_, _, err = loader.Reload(context.Background(), path, "", current)
+	assert.Error(t, err)
+}
+
 func TestLoader_Load_FileNotFound(t *testing.T) {
 	loader := NewLoader()
 	_, err := loader.Load(context.Background(), "/nonexistent/path/config.hjson")
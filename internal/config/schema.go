@@ -27,10 +27,23 @@ type Config struct {
 	LogViewerSettings LogViewerSettings     `json:"log_viewer_settings"`
 	Trace             TraceConfig           `json:"trace"`
 	TraceGroups       []TraceGroupConfig    `json:"trace_groups"`
+	Alerts            []AlertRuleConfig     `json:"alerts"`
 	Crashes           CrashesConfig         `json:"crashes"`
+	WorkflowHistory   WorkflowHistoryConfig `json:"workflow_history"`
+	OutputParsers     []OutputParserConfig  `json:"output_parsers"`
 	Proxy             []ProxyListenerConfig `json:"proxy"`
 	Cases             CasesConfig           `json:"cases"`
 	Agent             AgentConfig           `json:"agent"`
+	Secrets           SecretsConfig         `json:"secrets"`
+}
+
+// SecretsConfig configures where secrets referenced with {{ secret "name" }}
+// come from when they aren't in the local store.
+type SecretsConfig struct {
+	// Command prints the value of a secret, e.g. ["pass", "show", "myapp/{name}"]
+	// or ["op", "read", "op://dev/myapp/{name}"]; {name} in an argument is
+	// replaced with the secret name.
+	Command []string `json:"command"`
 }
 
 // CasesConfig configures case objects storage.
@@ -101,26 +114,139 @@ type ServerConfig struct {
 
 // ProxyListenerConfig configures a reverse proxy listener.
 type ProxyListenerConfig struct {
-	Listen       string             `json:"listen"`        // Address to bind (e.g., ":443", "0.0.0.0:8080")
-	TLSCert      string             `json:"tls_cert"`      // Path to TLS certificate (supports ~ expansion)
-	TLSKey       string             `json:"tls_key"`       // Path to TLS private key (supports ~ expansion)
-	TLSTailscale bool               `json:"tls_tailscale"` // Use Tailscale daemon for automatic TLS certificates
-	Routes       []ProxyRouteConfig `json:"routes"`        // Ordered route rules (first match wins)
+	Listen       string               `json:"listen"`        // Address to bind (e.g., ":443", "0.0.0.0:8080")
+	TLSCert      string               `json:"tls_cert"`      // Path to TLS certificate (supports ~ expansion)
+	TLSKey       string               `json:"tls_key"`       // Path to TLS private key (supports ~ expansion)
+	TLSTailscale bool                 `json:"tls_tailscale"` // Use Tailscale daemon for automatic TLS certificates
+	Routes       []ProxyRouteConfig   `json:"routes"`        // Ordered route rules (first match wins)
+	Capture      ProxyCaptureConfig   `json:"capture"`       // Record request/response pairs for inspection and replay
+	Worktrees    ProxyWorktreesConfig `json:"worktrees"`     // Route each request to a selected worktree's upstreams
+	AccessLog    ProxyAccessLogConfig `json:"access_log"`    // Log each request to a proxy:<listen> log viewer
+}
+
+// ProxyAccessLogViewerPrefix starts the names of proxy access log viewers.
+const ProxyAccessLogViewerPrefix = "proxy:"
+
+// AccessLogViewer returns the name of the log viewer showing the listener's
+// access log ("proxy:8080" for listen ":8080"), or "" if it is disabled.
+func (c ProxyListenerConfig) AccessLogViewer() string {
+	if c.AccessLog.Disabled || c.Listen == "" {
+		return ""
+	}
+	return ProxyAccessLogViewerPrefix + strings.TrimPrefix(c.Listen, ":")
 }
 
-// ProxyRouteConfig configures a single proxy route rule.
+// ProxyAccessLogConfig configures a proxy listener's access log. Each request
+// is logged as a JSON entry with its trace ID, so trace groups that include
+// the viewer pull in the backend entries for a request.
+type ProxyAccessLogConfig struct {
+	Disabled    bool   `json:"disabled"`     // Don't log requests
+	TraceHeader string `json:"trace_header"` // Header holding the trace ID (default: traceparent, then X-Request-Id, X-Trace-Id, X-Correlation-Id)
+}
+
+// ProxyWorktreesConfig lets one listener reach every worktree's services.
+// When enabled, route upstreams and header values are expanded per request
+// for the selected worktree (the active worktree when none is selected)
+// rather than once at startup.
+type ProxyWorktreesConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Header    string `json:"header"`    // Request header naming the worktree (default: X-Trellis-Worktree)
+	Cookie    string `json:"cookie"`    // Cookie naming the worktree (default: trellis_worktree)
+	Subdomain bool   `json:"subdomain"` // Select the worktree named by the host's first label (feature-x.localhost)
+}
+
+// ProxyCaptureConfig configures traffic capture for a proxy listener.
+type ProxyCaptureConfig struct {
+	Enabled      bool `json:"enabled"`        // Record requests passing through this listener
+	MaxRequests  int  `json:"max_requests"`   // Requests kept in memory (default: 1000)
+	MaxBodyBytes int  `json:"max_body_bytes"` // Bytes of each request/response body kept (default: 65536)
+}
+
+// ProxyRouteConfig configures a single proxy route rule. A route matches
+// when every matcher it sets matches; a route with no matchers is a catch-all.
 type ProxyRouteConfig struct {
-	PathRegexp string `json:"path_regexp"` // Regex to match request path (omit for catch-all)
-	Upstream   string `json:"upstream"`    // Target address (host:port)
+	PathRegexp      string             `json:"path_regexp"`      // Regex to match request path
+	Hosts           []string           `json:"hosts"`            // Host names to match ("api.example.com", "*.example.com", "admin.*")
+	Methods         []string           `json:"methods"`          // HTTP methods to match
+	Headers         map[string]string  `json:"headers"`          // Header name -> regex its value must match ("" = present)
+	Query           map[string]string  `json:"query"`            // Query parameter -> regex its value must match ("" = present)
+	Upstream        string             `json:"upstream"`         // Target address (host:port)
+	StripPrefix     string             `json:"strip_prefix"`     // Path prefix removed before proxying
+	Rewrite         ProxyRewriteConfig `json:"rewrite"`          // Regex path rewrite, applied after strip_prefix
+	RequestHeaders  ProxyHeaderActions `json:"request_headers"`  // Headers changed on the upstream request
+	ResponseHeaders ProxyHeaderActions `json:"response_headers"` // Headers changed on the response
+	Faults          ProxyFaultConfig   `json:"faults"`           // Injected latency and failures (changeable at runtime)
+	Mock            ProxyMockConfig    `json:"mock"`             // Canned and recorded responses served instead of the upstream
+}
+
+// ProxyMockConfig answers a route's requests with canned responses or with
+// fixtures recorded from the upstream, so the frontend can run without it.
+type ProxyMockConfig struct {
+	Responses []ProxyMockResponse `json:"responses"` // Stub responses, checked in order before fixtures
+	Fixtures  string              `json:"fixtures"`  // Fixture directory (supports template variables; relative to the worktree root)
+	Mode      string              `json:"mode"`      // "replay" serves fixtures (default), "record" saves upstream responses as fixtures
+	Fallback  bool                `json:"fallback"`  // Proxy requests no stub or fixture answers to the upstream
+}
+
+// Configured reports whether the route serves any mock responses.
+func (m ProxyMockConfig) Configured() bool {
+	return len(m.Responses) > 0 || m.Fixtures != ""
+}
+
+// ProxyMockResponse is a canned response served for matching requests.
+type ProxyMockResponse struct {
+	Method      string            `json:"method"`       // HTTP method to match (default: any)
+	Path        string            `json:"path"`         // Regex the path must match; named groups are available to the body as .Params
+	RequestBody string            `json:"request_body"` // Regex the request body must match
+	Status      int               `json:"status"`       // Response status (default: 200)
+	Headers     map[string]string `json:"headers"`      // Response headers
+	Body        string            `json:"body"`         // Response body, a Go template over the request
+	File        string            `json:"file"`         // Serve this file's contents instead of body (relative to the worktree root)
+}
+
+// ProxyFaultConfig injects latency and failures into a route's traffic, for
+// testing how clients cope with slow or failing backends. Rates are
+// percentages of requests.
+type ProxyFaultConfig struct {
+	Enabled            bool    `json:"enabled"`
+	Latency            string  `json:"latency"`              // Delay added before proxying (e.g., "200ms")
+	LatencyMax         string  `json:"latency_max"`          // When set, the delay is uniformly random between latency and latency_max
+	ErrorRate          float64 `json:"error_rate"`           // Requests answered with error_status instead of being proxied
+	ErrorStatus        int     `json:"error_status"`         // Status for injected errors (default: 503)
+	AbortRate          float64 `json:"abort_rate"`           // Requests whose connection is closed without a response
+	Bandwidth          int     `json:"bandwidth"`            // Response bytes per second (0 = unlimited)
+	WebSocketDropRate  float64 `json:"websocket_drop_rate"`  // WebSocket connections dropped while open
+	WebSocketDropAfter string  `json:"websocket_drop_after"` // How long a dropped WebSocket connection stays open (default: 10s)
+}
+
+// ProxyRewriteConfig rewrites the request path with a regex replacement.
+type ProxyRewriteConfig struct {
+	Pattern     string `json:"pattern"`     // Regex matched against the path
+	Replacement string `json:"replacement"` // Replacement; may reference groups ($1, ${name})
+}
+
+// ProxyHeaderActions sets and removes HTTP headers.
+type ProxyHeaderActions struct {
+	Set    map[string]string `json:"set"`    // Header name -> value (supports template variables)
+	Remove []string          `json:"remove"` // Header names to remove
 }
 
 // WorktreeConfig configures worktree management.
 type WorktreeConfig struct {
-	RepoDir   string           `json:"repo_dir"`   // Directory for git worktree discovery (defaults to config file dir)
-	CreateDir string           `json:"create_dir"` // Directory where new worktrees are created (defaults to parent of repo_dir)
-	Discovery DiscoveryConfig  `json:"discovery"`
-	Binaries  BinariesConfig   `json:"binaries"`
-	Lifecycle LifecycleConfig  `json:"lifecycle"`
+	RepoDir    string              `json:"repo_dir"`   // Directory for git worktree discovery (defaults to config file dir)
+	CreateDir  string              `json:"create_dir"` // Directory where new worktrees are created (defaults to parent of repo_dir)
+	Discovery  DiscoveryConfig     `json:"discovery"`
+	Binaries   BinariesConfig      `json:"binaries"`
+	Lifecycle  LifecycleConfig     `json:"lifecycle"`
+	Concurrent bool                `json:"concurrent"` // Let worktrees other than the active one run their services
+	Ports      WorktreePortsConfig `json:"ports"`
+}
+
+// WorktreePortsConfig sets the range {{.Worktree.Port "name"}} allocates
+// ports from.
+type WorktreePortsConfig struct {
+	Start int `json:"start"` // First port (default: 20000)
+	End   int `json:"end"`   // Last port (default: start + 999)
 }
 
 // DiscoveryConfig configures worktree discovery.
@@ -153,6 +279,7 @@ type ServiceConfig struct {
 	Args          []string             `json:"args"`
 	WorkDir       string               `json:"work_dir"`
 	Env           map[string]string    `json:"env"`
+	EnvFile       string               `json:"env_file"` // dotenv file read at start, relative to work_dir; env wins over it
 	Restart       RestartConfig        `json:"restart"`
 	RestartPolicy string               `json:"restart_policy"` // "always", "on-failure", "never"
 	RestartDelay  string               `json:"restart_delay"`
@@ -168,6 +295,36 @@ type ServiceConfig struct {
 	Enabled       *bool                `json:"enabled"`
 	Disabled      *bool                `json:"disabled"`
 	DependsOn     []string             `json:"depends_on"`
+	Readiness     *ProbeConfig         `json:"readiness"` // Check that must pass before the service counts as running
+	Liveness      *ProbeConfig         `json:"liveness"`  // Ongoing check; repeated failures restart the service
+}
+
+// ProbeConfig configures a service health check. Exactly one of HTTP, TCP,
+// LogLine, or Exec selects the check type.
+type ProbeConfig struct {
+	HTTP     string   `json:"http"`     // URL to GET; any 2xx/3xx response passes
+	TCP      string   `json:"tcp"`      // host:port that must accept a connection
+	LogLine  string   `json:"log_line"` // Regex a service output line must match (readiness only)
+	Exec     []string `json:"exec"`     // Command run in the service work_dir; exit 0 passes
+	Interval string   `json:"interval"` // Time between attempts (default: 1s)
+	Timeout  string   `json:"timeout"`  // Per-attempt timeout; for log_line, the overall wait (default: 1s, log_line: interval*retries)
+	Retries  int      `json:"retries"`  // Readiness: attempts before giving up (default 30). Liveness: consecutive failures before restart (default 3)
+}
+
+// Type returns the probe type: "http", "tcp", "log_line", "exec", or "" if none is set.
+func (p *ProbeConfig) Type() string {
+	switch {
+	case p.HTTP != "":
+		return "http"
+	case p.TCP != "":
+		return "tcp"
+	case p.LogLine != "":
+		return "log_line"
+	case len(p.Exec) > 0:
+		return "exec"
+	default:
+		return ""
+	}
 }
 
 // RestartConfig configures restart behavior.
@@ -178,6 +335,7 @@ type RestartConfig struct {
 // ServiceLoggingConfig configures per-service logging.
 type ServiceLoggingConfig struct {
 	BufferSize int                        `json:"buffer_size"`
+	Persist    bool                       `json:"persist"` // Keep the log buffer on disk across restarts
 	Parser     LogParserConfig            `json:"parser"`
 	Derive     map[string]DeriveConfig    `json:"derive"` // Derived fields computed from parsed fields
 	Layout     []LayoutColumnConfig       `json:"layout"` // Columns to display (in order)
@@ -199,18 +357,76 @@ type WorkflowInput struct {
 
 // WorkflowConfig defines a workflow action.
 type WorkflowConfig struct {
-	ID              string          `json:"id"`
-	Name            string          `json:"name"`
-	Description     string          `json:"description"` // Description for CLI help
-	Command         interface{}     `json:"command"`     // string or []string (single command, for backwards compat)
-	Commands        interface{}     `json:"commands"`    // array of commands to run in sequence
-	Timeout         string          `json:"timeout"`
-	OutputParser    string          `json:"output_parser"`
-	Confirm         bool            `json:"confirm"`
-	ConfirmMessage  string          `json:"confirm_message"`
-	RequiresStopped []string        `json:"requires_stopped"`
-	RestartServices bool            `json:"restart_services"`
-	Inputs          []WorkflowInput `json:"inputs"` // Input parameters to prompt user for
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"` // Description for CLI help
+	Command         interface{}            `json:"command"`     // string or []string (single command, for backwards compat)
+	Commands        interface{}            `json:"commands"`    // array of commands to run in sequence
+	Timeout         string                 `json:"timeout"`
+	OutputParser    string                 `json:"output_parser"`
+	Results         []string               `json:"results"` // globs of files, e.g. JUnit reports, the output parser reads instead of the output
+	Confirm         bool                   `json:"confirm"`
+	ConfirmMessage  string                 `json:"confirm_message"`
+	RequiresStopped []string               `json:"requires_stopped"`
+	RestartServices bool                   `json:"restart_services"`
+	Inputs          []WorkflowInput        `json:"inputs"` // Input parameters to prompt user for
+	Env             map[string]string      `json:"env"`
+	EnvFile         string                 `json:"env_file"`  // dotenv file read at run, relative to the worktree; env wins over it
+	Steps           []WorkflowStepConfig   `json:"steps"`     // named steps run as their needs allow, instead of command(s)
+	Triggers        *WorkflowTriggerConfig `json:"triggers"`  // start runs on file changes, events and schedules
+	Artifacts       []string               `json:"artifacts"` // globs of files kept with each run, e.g. binaries, coverage files and reports
+	CacheKey        *CacheKeyConfig        `json:"cache_key"` // skip the run when nothing it depends on changed since a recorded run
+}
+
+// CacheKeyConfig defines what a workflow or step depends on, so a run can
+// be skipped and its results and artifacts restored from a recorded run
+// with the same key. The key always covers the workflow or step's config
+// and the run's inputs.
+type CacheKeyConfig struct {
+	Files  []string `json:"files"`  // globs of files, relative to the worktree root, whose contents the key covers; "**" matches any number of directories
+	Commit bool     `json:"commit"` // the key covers the commit checked out; runs with uncommitted changes are never cached
+}
+
+// WorkflowTriggerConfig defines what starts a workflow without anyone
+// asking for it.
+type WorkflowTriggerConfig struct {
+	Files    []string `json:"files"`    // globs relative to the worktree root; "**" matches any number of directories
+	Ignore   []string `json:"ignore"`   // globs of files and directories not to watch
+	Events   []string `json:"events"`   // event type patterns, e.g. "binary.changed" or "service.*"
+	Schedule string   `json:"schedule"` // cron schedule, e.g. "*/30 9-17 * * mon-fri" or "@every 1h"
+	Debounce string   `json:"debounce"` // quiet period after file changes and events (default: 500ms)
+	Policy   string   `json:"policy"`   // "queue" (default), "cancel" or "skip" when a triggered run is in flight
+}
+
+// WorkflowStepConfig defines a step of a workflow composed of steps.
+type WorkflowStepConfig struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Command         interface{}       `json:"command"`           // string or []string
+	Workflow        string            `json:"workflow"`          // ID of a workflow to run as this step, instead of command
+	Inputs          map[string]any    `json:"inputs"`            // inputs of workflow; strings may use {{.Inputs.*}}
+	Needs           []string          `json:"needs"`             // IDs of steps that must finish first
+	If              string            `json:"if"`                // condition deciding whether the step runs
+	ContinueOnError bool              `json:"continue_on_error"` // a failure doesn't fail the run or skip dependent steps
+	Timeout         string            `json:"timeout"`
+	Env             map[string]string `json:"env"`           // added to the workflow's env
+	OutputParser    string            `json:"output_parser"` // defaults to the workflow's
+	Results         []string          `json:"results"`       // globs of files the output parser reads instead of the output
+	Artifacts       []string          `json:"artifacts"`     // globs of files kept after the step
+	CacheKey        *CacheKeyConfig   `json:"cache_key"`     // skip the step when nothing it depends on changed
+}
+
+// OutputParserConfig declares an output parser that maps lines of output
+// to errors, warnings and test results with regular expressions.
+type OutputParserConfig struct {
+	Name  string                   `json:"name"`  // referenced by output_parser
+	Rules []OutputParserRuleConfig `json:"rules"` // each line is parsed by the first rule matching it
+}
+
+// OutputParserRuleConfig maps the lines matching a pattern to results.
+type OutputParserRuleConfig struct {
+	Pattern string `json:"pattern"` // named groups file, line, col, message, test and package fill in the result
+	Type    string `json:"type"`    // "error", "warning", "test_pass", "test_fail" or "test_skip"
 }
 
 // CrashesConfig configures crash history storage.
@@ -220,6 +436,13 @@ type CrashesConfig struct {
 	MaxCount   int    `json:"max_count"`   // Max number of crashes to keep (default: 100)
 }
 
+// WorkflowHistoryConfig configures storage of completed workflow runs.
+type WorkflowHistoryConfig struct {
+	Dir     string `json:"dir"`      // Directory to store runs in (default: .trellis/workflows)
+	MaxAge  string `json:"max_age"`  // Max age of runs to keep (default: 30d)
+	MaxRuns int    `json:"max_runs"` // Max number of runs to keep per workflow (default: 200)
+}
+
 // TerminalConfig configures the terminal system.
 type TerminalConfig struct {
 	Backend       string               `json:"backend"` // "tmux"
@@ -294,13 +517,33 @@ type EventsConfig struct {
 type HistoryConfig struct {
 	MaxEvents int    `json:"max_events"`
 	MaxAge    string `json:"max_age"`
+	Persist   bool   `json:"persist"` // Keep history on disk across restarts
+	Dir       string `json:"dir"`     // Directory for persisted history (default: .trellis/events)
 }
 
 // WebhookConfig defines an event webhook.
 type WebhookConfig struct {
-	ID     string   `json:"id"`
-	URL    string   `json:"url"`
-	Events []string `json:"events"`
+	ID      string   `json:"id"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`  // Event patterns to deliver (default: all)
+	Secret  string   `json:"secret"`  // HMAC-SHA256 signing secret (optional)
+	Timeout string   `json:"timeout"` // Per-attempt request timeout (default: 10s)
+	Retries int      `json:"retries"` // Retries after the first attempt (default: 2)
+}
+
+// AlertRuleConfig defines a log-based alert rule. A rule watches either a
+// log viewer or a service's log buffer and fires when at least Threshold
+// entries matching Filter arrive within Window.
+type AlertRuleConfig struct {
+	Name      string `json:"name"`
+	Viewer    string `json:"viewer"`    // Log viewer to watch (e.g., "nginx" or "svc:api")
+	Service   string `json:"service"`   // Service whose log buffer to watch (instead of viewer)
+	Filter    string `json:"filter"`    // Log filter expression entries must match
+	Threshold int    `json:"threshold"` // Matching entries within the window needed to fire (default: 1)
+	Window    string `json:"window"`    // Sliding window for counting matches (default: 1m)
+	Cooldown  string `json:"cooldown"`  // Minimum time between firings (default: 5m)
+	Severity  string `json:"severity"`  // "info", "warning", or "critical" (default: warning)
+	Message   string `json:"message"`   // Description included in notifications (optional)
 }
 
 // WatchConfig configures file watching.
@@ -357,6 +600,23 @@ type WorktreeTemplateData struct {
 	Name     string
 	Branch   string
 	Binaries string
+	Ports    PortAllocator // Backs {{.Worktree.Port "name"}}
+}
+
+// PortAllocator hands out ports that stay the same for a worktree and name
+// and don't collide with any other worktree's.
+type PortAllocator interface {
+	Port(worktree, name string) (int, error)
+}
+
+// Port returns the port allocated to the worktree for name, so the same
+// config can run in several worktrees at once. Without an allocator, as
+// when templates are only being checked, it returns 0.
+func (w WorktreeTemplateData) Port(name string) (int, error) {
+	if w.Ports == nil {
+		return 0, nil
+	}
+	return w.Ports.Port(w.Name, name)
 }
 
 // ProjectTemplateData provides project data for templates.
@@ -529,8 +789,8 @@ func (c LogViewerConfig) GetMode() string {
 
 // LogSourceConfig defines where logs come from.
 type LogSourceConfig struct {
-	Type           string   `json:"type"`            // "ssh", "file", "command", "docker", "kubernetes"
-	Host           string   `json:"host"`            // SSH host
+	Type           string   `json:"type"`            // "ssh", "file", "command", "docker", "kubernetes", "journald", "syslog_listen", "otlp"
+	Host           string   `json:"host"`            // SSH host (for journald, read the journal over SSH)
 	Path           string   `json:"path"`            // Log directory or file path
 	Current        string   `json:"current"`         // Active log file name (for SSH)
 	RotatedPattern string   `json:"rotated_pattern"` // Glob pattern for rotated logs
@@ -541,6 +801,10 @@ type LogSourceConfig struct {
 	Pod            string   `json:"pod"`             // Kubernetes pod name
 	Follow         *bool    `json:"follow"`          // Follow log output
 	Since          string   `json:"since"`           // How far back to start
+	Units          []string `json:"units"`           // Journald: systemd units to include
+	Priority       string   `json:"priority"`        // Journald: max priority, e.g. "warning" or "0..3"
+	Listen         string   `json:"listen"`          // Syslog listener/OTLP: address to bind, e.g. ":5514"
+	Protocol       string   `json:"protocol"`        // Syslog listener: "udp" (default) or "tcp"
 }
 
 // IsFollow returns whether to follow log output.
@@ -563,6 +827,19 @@ type LogParserConfig struct {
 	Stack           string `json:"stack,omitempty"`  // Field name containing stack trace for crash reports
 	File            string `json:"file,omitempty"`   // Field name containing source file path
 	Line            string `json:"line,omitempty"`   // Field name containing source line number
+
+	Multiline *MultilineConfig `json:"multiline,omitempty"` // Fold continuation lines into one entry
+}
+
+// MultilineConfig folds lines that continue an entry (stack traces,
+// pretty-printed JSON) into the entry before them. A line matching Start
+// begins a new entry; any other line continues the previous one unless
+// Continuation is set and it doesn't match. At least one pattern is required.
+type MultilineConfig struct {
+	Start        string `json:"start,omitempty"`        // Regex matching the first line of an entry
+	Continuation string `json:"continuation,omitempty"` // Regex matching lines that continue the previous entry
+	MaxLines     int    `json:"max_lines,omitempty"`    // Max lines folded into one entry (default: 500)
+	Timeout      string `json:"timeout,omitempty"`      // Emit a pending entry after this long without new lines (default: "1s")
 }
 
 // DeriveConfig defines a derived field computed from parsed fields.
@@ -721,13 +998,17 @@ func mergeParserConfig(cfg, defaults LogParserConfig) LogParserConfig {
 	if cfg.Line == "" {
 		cfg.Line = defaults.Line
 	}
+	if cfg.Multiline == nil {
+		cfg.Multiline = defaults.Multiline
+	}
 	return cfg
 }
 
 // LogBufferConfig defines buffer settings.
 type LogBufferConfig struct {
-	MaxEntries int  `json:"max_entries"` // Max entries in memory
-	Persist    bool `json:"persist"`     // Persist across restarts
+	MaxEntries        int  `json:"max_entries"`         // Max entries in memory
+	Persist           bool `json:"persist"`             // Persist across restarts
+	PersistMaxEntries int  `json:"persist_max_entries"` // Max entries kept on disk (default: 10x max_entries)
 }
 
 // TraceConfig configures distributed tracing.
@@ -6,9 +6,12 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
+
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // TemplateExpander handles Go text/template variable expansion in config values.
@@ -26,6 +29,9 @@ func NewTemplateExpander() *TemplateExpander {
 			"lower":   strings.ToLower,
 			"default": Default,
 			"quote":   Quote,
+			// Secrets are resolved when a process starts, so the value
+			// never lands in the expanded config
+			"secret": secrets.Ref,
 		},
 	}
 }
@@ -130,6 +136,15 @@ func (e *TemplateExpander) ExpandConfig(cfg *Config, ctx *TemplateContext) (*Con
 		expanded.Crashes.ReportsDir = path
 	}
 
+	// Expand workflow history directory
+	if expanded.WorkflowHistory.Dir != "" {
+		path, err := e.Expand(expanded.WorkflowHistory.Dir, ctx)
+		if err != nil {
+			return nil, err
+		}
+		expanded.WorkflowHistory.Dir = path
+	}
+
 	// Expand service configs
 	expandedServices := make([]ServiceConfig, len(cfg.Services))
 	for i, svc := range cfg.Services {
@@ -202,16 +217,14 @@ func (e *TemplateExpander) expandProxyListener(listener ProxyListenerConfig, ctx
 		expanded.Listen = v
 	}
 
-	if len(listener.Routes) > 0 {
+	// Routes of a worktree-routing listener are expanded per request by the
+	// proxy, for whichever worktree the request selects
+	if len(listener.Routes) > 0 && !listener.Worktrees.Enabled {
 		expandedRoutes := make([]ProxyRouteConfig, len(listener.Routes))
 		for i, route := range listener.Routes {
-			expandedRoute := route
-			if route.Upstream != "" {
-				v, err := e.Expand(route.Upstream, ctx)
-				if err != nil {
-					return expanded, err
-				}
-				expandedRoute.Upstream = v
+			expandedRoute, err := e.ExpandProxyRoute(route, ctx)
+			if err != nil {
+				return expanded, err
 			}
 			expandedRoutes[i] = expandedRoute
 		}
@@ -221,6 +234,75 @@ func (e *TemplateExpander) expandProxyListener(listener ProxyListenerConfig, ctx
 	return expanded, nil
 }
 
+// ExpandProxyRoute expands template variables in a proxy route's upstream
+// and header values.
+func (e *TemplateExpander) ExpandProxyRoute(route ProxyRouteConfig, ctx *TemplateContext) (ProxyRouteConfig, error) {
+	expanded := route
+	if route.Upstream != "" {
+		v, err := e.Expand(route.Upstream, ctx)
+		if err != nil {
+			return expanded, err
+		}
+		expanded.Upstream = v
+	}
+	var err error
+	if expanded.RequestHeaders.Set, err = e.expandHeaderValues(route.RequestHeaders.Set, ctx); err != nil {
+		return expanded, err
+	}
+	if expanded.ResponseHeaders.Set, err = e.expandHeaderValues(route.ResponseHeaders.Set, ctx); err != nil {
+		return expanded, err
+	}
+
+	// Mock bodies are templates over the request, expanded by the proxy;
+	// only the fixture and file paths are expanded here
+	if expanded.Mock.Fixtures, err = e.expandWorktreePath(route.Mock.Fixtures, ctx); err != nil {
+		return expanded, err
+	}
+	if len(route.Mock.Responses) > 0 {
+		expanded.Mock.Responses = make([]ProxyMockResponse, len(route.Mock.Responses))
+		for i, resp := range route.Mock.Responses {
+			if resp.File, err = e.expandWorktreePath(resp.File, ctx); err != nil {
+				return expanded, err
+			}
+			expanded.Mock.Responses[i] = resp
+		}
+	}
+	return expanded, nil
+}
+
+// expandWorktreePath expands template variables in a path and resolves it
+// against the worktree root if it is relative.
+func (e *TemplateExpander) expandWorktreePath(path string, ctx *TemplateContext) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	v, err := e.Expand(path, ctx)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(v) && !strings.HasPrefix(v, "~") && ctx.Worktree.Root != "" {
+		v = filepath.Join(ctx.Worktree.Root, v)
+	}
+	return v, nil
+}
+
+// expandHeaderValues expands template variables in header values, returning
+// a new map so the unexpanded config is left untouched.
+func (e *TemplateExpander) expandHeaderValues(headers map[string]string, ctx *TemplateContext) (map[string]string, error) {
+	if len(headers) == 0 {
+		return headers, nil
+	}
+	expanded := make(map[string]string, len(headers))
+	for name, value := range headers {
+		v, err := e.Expand(value, ctx)
+		if err != nil {
+			return nil, err
+		}
+		expanded[name] = v
+	}
+	return expanded, nil
+}
+
 // expandService expands template variables in a service config.
 func (e *TemplateExpander) expandService(svc ServiceConfig, ctx *TemplateContext) (ServiceConfig, error) {
 	// Create service-specific context
@@ -323,48 +405,78 @@ func (e *TemplateExpander) expandService(svc ServiceConfig, ctx *TemplateContext
 		expanded.Env = expandedEnv
 	}
 
+	// Expand env_file (per-worktree env files)
+	if svc.EnvFile != "" {
+		ef, err := e.Expand(svc.EnvFile, svcCtx)
+		if err != nil {
+			return expanded, err
+		}
+		expanded.EnvFile = ef
+	}
+
+	// Expand readiness/liveness probe targets (ports often come from templates)
+	if svc.Readiness != nil {
+		probe, err := e.expandProbe(*svc.Readiness, svcCtx)
+		if err != nil {
+			return expanded, err
+		}
+		expanded.Readiness = &probe
+	}
+	if svc.Liveness != nil {
+		probe, err := e.expandProbe(*svc.Liveness, svcCtx)
+		if err != nil {
+			return expanded, err
+		}
+		expanded.Liveness = &probe
+	}
+
 	return expanded, nil
 }
 
-// expandWorkflow expands template variables in a workflow config.
-func (e *TemplateExpander) expandWorkflow(wf WorkflowConfig, ctx *TemplateContext) (WorkflowConfig, error) {
-	expanded := wf
+// expandProbe expands template variables in a probe config.
+func (e *TemplateExpander) expandProbe(probe ProbeConfig, ctx *TemplateContext) (ProbeConfig, error) {
+	expanded := probe
 
-	// Expand command (string or array)
-	switch cmd := wf.Command.(type) {
-	case string:
-		expandedCmd, err := e.Expand(cmd, ctx)
+	if probe.HTTP != "" {
+		v, err := e.Expand(probe.HTTP, ctx)
 		if err != nil {
 			return expanded, err
 		}
-		expanded.Command = expandedCmd
-	case []interface{}:
-		expandedCmd := make([]string, len(cmd))
-		for i, v := range cmd {
-			str, ok := v.(string)
-			if !ok {
-				expandedCmd[i] = ""
-				continue
-			}
-			exp, err := e.Expand(str, ctx)
-			if err != nil {
-				return expanded, err
-			}
-			expandedCmd[i] = exp
+		expanded.HTTP = v
+	}
+	if probe.TCP != "" {
+		v, err := e.Expand(probe.TCP, ctx)
+		if err != nil {
+			return expanded, err
 		}
-		expanded.Command = expandedCmd
-	case []string:
-		expandedCmd := make([]string, len(cmd))
-		for i, str := range cmd {
-			exp, err := e.Expand(str, ctx)
+		expanded.TCP = v
+	}
+	if len(probe.Exec) > 0 {
+		expandedExec := make([]string, len(probe.Exec))
+		for i, arg := range probe.Exec {
+			v, err := e.Expand(arg, ctx)
 			if err != nil {
 				return expanded, err
 			}
-			expandedCmd[i] = exp
+			expandedExec[i] = v
 		}
-		expanded.Command = expandedCmd
+		expanded.Exec = expandedExec
 	}
 
+	return expanded, nil
+}
+
+// expandWorkflow expands template variables in a workflow config.
+func (e *TemplateExpander) expandWorkflow(wf WorkflowConfig, ctx *TemplateContext) (WorkflowConfig, error) {
+	expanded := wf
+
+	// Expand command (string or array)
+	cmd, err := e.expandCommand(wf.Command, ctx)
+	if err != nil {
+		return expanded, err
+	}
+	expanded.Command = cmd
+
 	// Expand commands (array of arrays)
 	switch cmds := wf.Commands.(type) {
 	case []interface{}:
@@ -403,9 +515,90 @@ func (e *TemplateExpander) expandWorkflow(wf WorkflowConfig, ctx *TemplateContex
 		expanded.Commands = expandedCmds
 	}
 
+	// Expand environment variable values and env_file
+	if len(wf.Env) > 0 {
+		expandedEnv := make(map[string]string, len(wf.Env))
+		for k, v := range wf.Env {
+			expVal, err := e.Expand(v, ctx)
+			if err != nil {
+				return expanded, err
+			}
+			expandedEnv[k] = expVal
+		}
+		expanded.Env = expandedEnv
+	}
+	if wf.EnvFile != "" {
+		ef, err := e.Expand(wf.EnvFile, ctx)
+		if err != nil {
+			return expanded, err
+		}
+		expanded.EnvFile = ef
+	}
+
+	// Expand step commands and environments
+	if len(wf.Steps) > 0 {
+		expanded.Steps = make([]WorkflowStepConfig, len(wf.Steps))
+		for i, step := range wf.Steps {
+			cmd, err := e.expandCommand(step.Command, ctx)
+			if err != nil {
+				return expanded, err
+			}
+			step.Command = cmd
+			if len(step.Env) > 0 {
+				env := make(map[string]string, len(step.Env))
+				for k, v := range step.Env {
+					if env[k], err = e.Expand(v, ctx); err != nil {
+						return expanded, err
+					}
+				}
+				step.Env = env
+			}
+			expanded.Steps[i] = step
+		}
+	}
+
 	return expanded, nil
 }
 
+// expandCommand expands template variables in a command, a string or an
+// array of strings.
+func (e *TemplateExpander) expandCommand(cmd interface{}, ctx *TemplateContext) (interface{}, error) {
+	switch cmd := cmd.(type) {
+	case string:
+		expandedCmd, err := e.Expand(cmd, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return expandedCmd, nil
+	case []interface{}:
+		expandedCmd := make([]string, len(cmd))
+		for i, v := range cmd {
+			str, ok := v.(string)
+			if !ok {
+				expandedCmd[i] = ""
+				continue
+			}
+			exp, err := e.Expand(str, ctx)
+			if err != nil {
+				return nil, err
+			}
+			expandedCmd[i] = exp
+		}
+		return expandedCmd, nil
+	case []string:
+		expandedCmd := make([]string, len(cmd))
+		for i, str := range cmd {
+			exp, err := e.Expand(str, ctx)
+			if err != nil {
+				return nil, err
+			}
+			expandedCmd[i] = exp
+		}
+		return expandedCmd, nil
+	}
+	return cmd, nil
+}
+
 // Slugify converts a string to a URL-friendly slug.
 func Slugify(s string) string {
 	// Convert to lowercase
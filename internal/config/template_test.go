@@ -446,7 +446,11 @@ func TestTemplateExpander_ExpandConfig_ProxyConfig(t *testing.T) {
 				Listen:       ":{{.Worktree.Name}}",
 				TLSTailscale: true,
 				Routes: []ProxyRouteConfig{
-					{Upstream: "{{.Worktree.Name}}.localhost:8080"},
+					{
+						Upstream:        "{{.Worktree.Name}}.localhost:8080",
+						RequestHeaders:  ProxyHeaderActions{Set: map[string]string{"X-Worktree": "{{.Worktree.Name}}"}},
+						ResponseHeaders: ProxyHeaderActions{Set: map[string]string{"X-Root": "{{.Worktree.Root}}"}},
+					},
 				},
 			},
 		},
@@ -466,6 +470,63 @@ func TestTemplateExpander_ExpandConfig_ProxyConfig(t *testing.T) {
 	assert.Equal(t, ":main", expanded.Proxy[1].Listen)
 	assert.True(t, expanded.Proxy[1].TLSTailscale)
 	assert.Equal(t, "main.localhost:8080", expanded.Proxy[1].Routes[0].Upstream)
+	assert.Equal(t, "main", expanded.Proxy[1].Routes[0].RequestHeaders.Set["X-Worktree"])
+	assert.Equal(t, "/home/user/project", expanded.Proxy[1].Routes[0].ResponseHeaders.Set["X-Root"])
+	assert.Equal(t, "{{.Worktree.Name}}", cfg.Proxy[1].Routes[0].RequestHeaders.Set["X-Worktree"])
+}
+
+func TestTemplateExpander_ExpandConfig_ProxyWorktreeRouting(t *testing.T) {
+	expander := NewTemplateExpander()
+	ctx := &TemplateContext{
+		Worktree: WorktreeTemplateData{Root: "/project", Name: "main"},
+	}
+
+	cfg := &Config{
+		Proxy: []ProxyListenerConfig{{
+			Listen:    ":{{.Worktree.Name}}",
+			Worktrees: ProxyWorktreesConfig{Enabled: true},
+			Routes: []ProxyRouteConfig{{
+				Upstream:       "{{.Worktree.Name}}.localhost:3000",
+				RequestHeaders: ProxyHeaderActions{Set: map[string]string{"X-Worktree": "{{.Worktree.Name}}"}},
+			}},
+		}},
+	}
+
+	expanded, err := expander.ExpandConfig(cfg, ctx)
+	require.NoError(t, err)
+
+	// Routes are left for the proxy to expand per selected worktree
+	assert.Equal(t, ":main", expanded.Proxy[0].Listen)
+	assert.Equal(t, "{{.Worktree.Name}}.localhost:3000", expanded.Proxy[0].Routes[0].Upstream)
+	assert.Equal(t, "{{.Worktree.Name}}", expanded.Proxy[0].Routes[0].RequestHeaders.Set["X-Worktree"])
+
+	route, err := expander.ExpandProxyRoute(expanded.Proxy[0].Routes[0], &TemplateContext{Worktree: WorktreeTemplateData{Name: "feature"}})
+	require.NoError(t, err)
+	assert.Equal(t, "feature.localhost:3000", route.Upstream)
+	assert.Equal(t, "feature", route.RequestHeaders.Set["X-Worktree"])
+}
+
+func TestTemplateExpander_ExpandProxyRoute_Mock(t *testing.T) {
+	expander := NewTemplateExpander()
+	route := ProxyRouteConfig{
+		Mock: ProxyMockConfig{
+			Fixtures: ".trellis/fixtures/{{.Worktree.Name}}",
+			Responses: []ProxyMockResponse{
+				{File: "testdata/user.json"},
+				{File: "/srv/logo.svg"},
+				{Body: `{"id": {{.Params.id}}}`},
+			},
+		},
+	}
+
+	expanded, err := expander.ExpandProxyRoute(route, &TemplateContext{Worktree: WorktreeTemplateData{Root: "/project", Name: "main"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/project/.trellis/fixtures/main", expanded.Mock.Fixtures)
+	assert.Equal(t, "/project/testdata/user.json", expanded.Mock.Responses[0].File)
+	assert.Equal(t, "/srv/logo.svg", expanded.Mock.Responses[1].File)
+	// Bodies are templates over the request, expanded by the proxy
+	assert.Equal(t, `{"id": {{.Params.id}}}`, expanded.Mock.Responses[2].Body)
+	assert.Equal(t, "testdata/user.json", route.Mock.Responses[0].File)
 }
 
 func TestTemplateExpander_ExpandConfig_ProxyPreservesNonTemplates(t *testing.T) {
@@ -540,3 +601,113 @@ func TestTemplateExpander_ExpandConfig_WorkflowCommands(t *testing.T) {
 	}
 	assert.Equal(t, expectedCommands, expanded.Workflows[1].Commands)
 }
+
+func TestTemplateExpander_ExpandConfig_ServiceProbes(t *testing.T) {
+	expander := NewTemplateExpander()
+	ctx := &TemplateContext{
+		Worktree: WorktreeTemplateData{
+			Root: "/project",
+			Name: "feature",
+		},
+	}
+
+	readiness := &ProbeConfig{HTTP: "http://{{.Worktree.Name}}.localhost/health"}
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{
+				Name:      "api",
+				Command:   "./api",
+				Readiness: readiness,
+				Liveness:  &ProbeConfig{Exec: []string{"{{.Worktree.Root}}/bin/check", "{{.Service.Name}}"}},
+			},
+		},
+	}
+
+	expanded, err := expander.ExpandConfig(cfg, ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://feature.localhost/health", expanded.Services[0].Readiness.HTTP)
+	assert.Equal(t, []string{"/project/bin/check", "api"}, expanded.Services[0].Liveness.Exec)
+	// The original probe must not be mutated (it's reused on worktree switch)
+	assert.Equal(t, "http://{{.Worktree.Name}}.localhost/health", readiness.HTTP)
+}
+
+func TestTemplateExpander_ExpandConfig_SecretsAndEnvFiles(t *testing.T) {
+	expander := NewTemplateExpander()
+	ctx := &TemplateContext{
+		Worktree: WorktreeTemplateData{
+			Root: "/project",
+			Name: "feature",
+		},
+	}
+
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{
+				Name:    "api",
+				Command: "./api",
+				EnvFile: "{{.Worktree.Root}}/.env.{{.Worktree.Name}}",
+				Env:     map[string]string{"STRIPE_KEY": `{{ secret "stripe_key" }}`, "MIXED": `{{.Service.Name}}:{{secret "token"}}`},
+			},
+		},
+		Workflows: []WorkflowConfig{
+			{
+				ID:      "deploy",
+				Command: "./deploy",
+				EnvFile: "{{.Worktree.Root}}/deploy.env",
+				Env:     map[string]string{"TARGET": "{{.Worktree.Name}}", "TOKEN": `{{ secret "token" }}`},
+			},
+		},
+	}
+
+	expanded, err := expander.ExpandConfig(cfg, ctx)
+	require.NoError(t, err)
+
+	// Secret references survive expansion; they're resolved at process start
+	svc := expanded.Services[0]
+	assert.Equal(t, "/project/.env.feature", svc.EnvFile)
+	assert.Equal(t, `{{ secret "stripe_key" }}`, svc.Env["STRIPE_KEY"])
+	assert.Equal(t, `api:{{ secret "token" }}`, svc.Env["MIXED"])
+
+	wf := expanded.Workflows[0]
+	assert.Equal(t, "/project/deploy.env", wf.EnvFile)
+	assert.Equal(t, "feature", wf.Env["TARGET"])
+	assert.Equal(t, `{{ secret "token" }}`, wf.Env["TOKEN"])
+}
+
+// fakePorts allocates ports from a fixed base in request order.
+type fakePorts map[string]int
+
+func (f fakePorts) Port(worktree, name string) (int, error) {
+	key := worktree + "/" + name
+	if _, ok := f[key]; !ok {
+		f[key] = 20000 + len(f)
+	}
+	return f[key], nil
+}
+
+func TestTemplateExpander_WorktreePort(t *testing.T) {
+	expander := NewTemplateExpander()
+	ports := fakePorts{}
+	data := func(name string) *TemplateContext {
+		return &TemplateContext{Worktree: WorktreeTemplateData{Name: name, Ports: ports}}
+	}
+
+	got, err := expander.Expand(`--port={{.Worktree.Port "api"}}`, data("main"))
+	require.NoError(t, err)
+	assert.Equal(t, "--port=20000", got)
+
+	got, err = expander.Expand(`{{.Worktree.Port "api"}},{{.Worktree.Port "db"}}`, data("feature"))
+	require.NoError(t, err)
+	assert.Equal(t, "20001,20002", got)
+
+	// The same worktree and name always get the same port
+	got, err = expander.Expand(`{{.Worktree.Port "api"}}`, data("main"))
+	require.NoError(t, err)
+	assert.Equal(t, "20000", got)
+
+	// Without an allocator templates still expand
+	got, err = expander.Expand(`{{.Worktree.Port "api"}}`, &TemplateContext{})
+	require.NoError(t, err)
+	assert.Equal(t, "0", got)
+}
@@ -4,12 +4,18 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // Validator validates configuration against schema rules.
@@ -60,10 +66,12 @@ func (v *Validator) Validate(cfg *Config) error {
 	v.validateTerminal(cfg, errs)
 	v.validateLogging(cfg, errs)
 	v.validateUI(cfg, errs)
+	v.validateWorktree(cfg, errs)
 	v.validateDurations(cfg, errs)
 	v.validateCrossReferences(cfg, errs)
 	v.validateTraceGroups(cfg, errs)
 	v.validateLogViewers(cfg, errs)
+	v.validateAlerts(cfg, errs)
 	v.validateProxy(cfg, errs)
 
 	if errs.IsEmpty() {
@@ -184,19 +192,83 @@ func (v *Validator) validateServices(cfg *Config, errs *ValidationError) {
 				errs.Add(field, fmt.Sprintf("invalid policy '%s', must be one of: always, on_failure, on-failure, never", restartPolicy))
 			}
 		}
+
+		if svc.Readiness != nil {
+			v.validateProbe(svc.Readiness, prefix+".readiness", true, errs)
+		}
+		if svc.Liveness != nil {
+			v.validateProbe(svc.Liveness, prefix+".liveness", false, errs)
+		}
+
+		svc.Env = nil
+		if hasSecretRef(svc) {
+			errs.Add(prefix, "secret references are only allowed in env values")
+		}
+	}
+}
+
+// hasSecretRef reports whether any string in v references a secret.
+func hasSecretRef(v any) bool {
+	data, err := json.Marshal(v)
+	return err == nil && secrets.HasRef(string(data))
+}
+
+// validateProbe checks a readiness or liveness probe. log_line probes only
+// make sense for readiness: a line either appeared during startup or it didn't.
+func (v *Validator) validateProbe(probe *ProbeConfig, prefix string, readiness bool, errs *ValidationError) {
+	set := 0
+	for _, present := range []bool{probe.HTTP != "", probe.TCP != "", probe.LogLine != "", len(probe.Exec) > 0} {
+		if present {
+			set++
+		}
+	}
+	if set != 1 {
+		errs.Add(prefix, "exactly one of http, tcp, log_line, or exec must be specified")
+	}
+
+	if probe.LogLine != "" {
+		if !readiness {
+			errs.Add(prefix+".log_line", "is only supported for readiness probes")
+		} else if _, err := regexp.Compile(probe.LogLine); err != nil {
+			errs.Add(prefix+".log_line", fmt.Sprintf("invalid regex: %s", err))
+		}
+	}
+
+	for _, d := range []struct{ field, value string }{
+		{"interval", probe.Interval},
+		{"timeout", probe.Timeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(d.value)
+		if err != nil {
+			errs.Add(prefix+"."+d.field, fmt.Sprintf("invalid duration format: %s", err))
+		} else if dur <= 0 {
+			errs.Add(prefix+"."+d.field, "must be positive")
+		}
+	}
+
+	if probe.Retries < 0 {
+		errs.Add(prefix+".retries", "must not be negative")
 	}
 }
 
+// builtinParsers are the names of the built-in workflow output parsers.
+var builtinParsers = []string{"go", "go_test_json", "generic", "none", "html", "junit", "tap", "eslint", "tsc", "pytest", "cargo"}
+
+// invalidParser returns the error for an unknown output parser.
+func invalidParser(name string) string {
+	return fmt.Sprintf("invalid parser '%s', must be one of: %s, or the name of one of output_parsers", name, strings.Join(builtinParsers, ", "))
+}
+
 func (v *Validator) validateWorkflows(cfg *Config, errs *ValidationError) {
 	seenIDs := make(map[string]bool)
-	validParsers := map[string]bool{
-		"":             true,
-		"go":           true,
-		"go_test_json": true,
-		"generic":      true,
-		"none":         true,
-		"html":         true,
+	validParsers := map[string]bool{"": true}
+	for _, name := range builtinParsers {
+		validParsers[name] = true
 	}
+	v.validateOutputParsers(cfg.OutputParsers, validParsers, errs)
 
 	for i, wf := range cfg.Workflows {
 		prefix := fmt.Sprintf("workflows[%d]", i)
@@ -213,30 +285,316 @@ func (v *Validator) validateWorkflows(cfg *Config, errs *ValidationError) {
 			errs.Add(prefix+".name", "is required")
 		}
 
-		// Either command or commands must be set
-		hasCommand := false
-		if wf.Command != nil {
-			switch cmd := wf.Command.(type) {
-			case []interface{}:
-				hasCommand = len(cmd) > 0
-			case []string:
-				hasCommand = len(cmd) > 0
-			case string:
-				hasCommand = cmd != ""
-			}
-		}
+		// Exactly one of command, commands or steps must be set
+		hasCommand := hasCommandSet(wf.Command)
 		hasCommands := false
 		if arr, ok := wf.Commands.([]interface{}); ok {
 			hasCommands = len(arr) > 0
 		}
-		if !hasCommand && !hasCommands {
-			errs.Add(prefix+".command", "either command or commands is required")
+		if len(wf.Steps) > 0 {
+			if hasCommand || hasCommands {
+				errs.Add(prefix+".steps", "cannot be combined with command or commands")
+			}
+			v.validateSteps(wf.Steps, prefix, wf.OutputParser, validParsers, errs)
+		} else if !hasCommand && !hasCommands {
+			errs.Add(prefix+".command", "either command, commands or steps is required")
 		}
 
 		if !validParsers[wf.OutputParser] {
-			errs.Add(prefix+".output_parser", fmt.Sprintf("invalid parser '%s', must be one of: go, go_test_json, generic, none, html", wf.OutputParser))
+			errs.Add(prefix+".output_parser", invalidParser(wf.OutputParser))
+		}
+		if len(wf.Steps) > 0 && len(wf.Results) > 0 {
+			errs.Add(prefix+".results", "set results on the steps of a workflow composed of steps")
+		}
+		validateResults(wf.Results, wf.OutputParser, prefix, errs)
+		validateArtifacts(wf.Artifacts, wf.CacheKey, prefix, errs)
+
+		if wf.Triggers != nil {
+			v.validateTriggers(wf.Triggers, prefix+".triggers", errs)
+		}
+
+		wf.Env = nil
+		wf.Steps = append([]WorkflowStepConfig(nil), wf.Steps...)
+		for j := range wf.Steps {
+			wf.Steps[j].Env = nil
+		}
+		if hasSecretRef(wf) {
+			errs.Add(prefix, "secret references are only allowed in env values")
+		}
+	}
+}
+
+// hasCommandSet reports whether a command, a string or an array, is set.
+func hasCommandSet(cmd interface{}) bool {
+	switch cmd := cmd.(type) {
+	case []interface{}:
+		return len(cmd) > 0
+	case []string:
+		return len(cmd) > 0
+	case string:
+		return cmd != ""
+	}
+	return false
+}
+
+// validateSteps checks the steps of a workflow: unique IDs, a command or a
+// workflow each, needs naming other steps without a cycle, and valid
+// timeouts, parsers and conditions. Workflows run as steps are checked in
+// validateCrossReferences.
+func (v *Validator) validateSteps(steps []WorkflowStepConfig, prefix, parser string, validParsers map[string]bool, errs *ValidationError) {
+	ids := make(map[string]bool, len(steps))
+	for j, step := range steps {
+		stepPrefix := fmt.Sprintf("%s.steps[%d]", prefix, j)
+		if step.ID == "" {
+			errs.Add(stepPrefix+".id", "is required")
+		} else if ids[step.ID] {
+			errs.Add(stepPrefix+".id", fmt.Sprintf("duplicate step id '%s'", step.ID))
+		}
+		ids[step.ID] = true
+	}
+
+	for j, step := range steps {
+		stepPrefix := fmt.Sprintf("%s.steps[%d]", prefix, j)
+
+		hasCommand := hasCommandSet(step.Command)
+		if hasCommand == (step.Workflow != "") {
+			errs.Add(stepPrefix, "exactly one of command or workflow is required")
+		}
+		if len(step.Inputs) > 0 && step.Workflow == "" {
+			errs.Add(stepPrefix+".inputs", "only apply to steps running a workflow")
+		}
+
+		for _, need := range step.Needs {
+			if need == step.ID {
+				errs.Add(stepPrefix+".needs", "a step cannot need itself")
+			} else if !ids[need] {
+				errs.Add(stepPrefix+".needs", fmt.Sprintf("references unknown step '%s'", need))
+			}
+		}
+
+		if step.Timeout != "" {
+			d, err := time.ParseDuration(step.Timeout)
+			if err != nil {
+				errs.Add(stepPrefix+".timeout", fmt.Sprintf("invalid duration format: %s", err))
+			} else if d < 0 {
+				errs.Add(stepPrefix+".timeout", "must be positive")
+			}
+		}
+
+		if !validParsers[step.OutputParser] {
+			errs.Add(stepPrefix+".output_parser", invalidParser(step.OutputParser))
+		}
+		if step.Workflow == "" {
+			stepParser := step.OutputParser
+			if stepParser == "" {
+				stepParser = parser
+			}
+			validateResults(step.Results, stepParser, stepPrefix, errs)
+		}
+		validateArtifacts(step.Artifacts, step.CacheKey, stepPrefix, errs)
+
+		if step.If != "" {
+			cond := step.If
+			if !strings.Contains(cond, "{{") {
+				cond = "{{" + cond + "}}"
+			}
+			stub := func() bool { return true }
+			funcs := template.FuncMap{"success": stub, "failure": stub, "always": stub}
+			if _, err := template.New("if").Funcs(funcs).Parse(cond); err != nil {
+				errs.Add(stepPrefix+".if", fmt.Sprintf("invalid condition: %s", err))
+			}
+		}
+	}
+
+	keys := make([]string, len(steps))
+	needs := make(map[string][]string, len(steps))
+	for j, step := range steps {
+		keys[j] = step.ID
+		needs[step.ID] = append(needs[step.ID], step.Needs...)
+	}
+	if cycle := findCycle(keys, needs); cycle != nil {
+		errs.Add(prefix+".steps", fmt.Sprintf("needs form a cycle: %s", strings.Join(cycle, " -> ")))
+	}
+}
+
+// validateOutputParsers checks the output parsers declared in the config
+// and adds their names to validParsers.
+func (v *Validator) validateOutputParsers(parsers []OutputParserConfig, validParsers map[string]bool, errs *ValidationError) {
+	for i, p := range parsers {
+		prefix := fmt.Sprintf("output_parsers[%d]", i)
+		switch {
+		case p.Name == "":
+			errs.Add(prefix+".name", "is required")
+		case validParsers[p.Name]:
+			errs.Add(prefix+".name", fmt.Sprintf("parser '%s' is already defined", p.Name))
+		default:
+			validParsers[p.Name] = true
+		}
+
+		if len(p.Rules) == 0 {
+			errs.Add(prefix+".rules", "at least one rule is required")
+		}
+		for j, rule := range p.Rules {
+			rulePrefix := fmt.Sprintf("%s.rules[%d]", prefix, j)
+			re, err := regexp.Compile(rule.Pattern)
+			switch {
+			case rule.Pattern == "":
+				errs.Add(rulePrefix+".pattern", "is required")
+			case err != nil:
+				errs.Add(rulePrefix+".pattern", fmt.Sprintf("invalid regex: %s", err))
+			default:
+				for _, group := range re.SubexpNames() {
+					switch group {
+					case "", "file", "line", "col", "message", "test", "package":
+					default:
+						errs.Add(rulePrefix+".pattern", fmt.Sprintf("unknown group '%s', must be one of: file, line, col, message, test, package", group))
+					}
+				}
+			}
+			switch rule.Type {
+			case "error", "warning", "test_pass", "test_fail", "test_skip":
+			default:
+				errs.Add(rulePrefix+".type", fmt.Sprintf("invalid type '%s', must be one of: error, warning, test_pass, test_fail, test_skip", rule.Type))
+			}
+		}
+	}
+}
+
+// validateResults checks the result files of a workflow or step, which are
+// only read by an output parser.
+func validateResults(results []string, parser, prefix string, errs *ValidationError) {
+	if len(results) > 0 && parser == "" {
+		errs.Add(prefix+".results", "requires output_parser")
+	}
+	for j, glob := range results {
+		if !validGlob(glob) || strings.Contains(glob, "**") {
+			errs.Add(fmt.Sprintf("%s.results[%d]", prefix, j), fmt.Sprintf("invalid glob '%s'", glob))
+		}
+	}
+}
+
+// validateArtifacts checks the artifacts and cache key of a workflow or
+// step. Artifacts must stay inside the worktree, as they're restored into
+// it from cached runs.
+func validateArtifacts(artifacts []string, ck *CacheKeyConfig, prefix string, errs *ValidationError) {
+	for j, glob := range artifacts {
+		clean := path.Clean(glob)
+		switch {
+		case !validGlob(glob) || strings.Contains(glob, "**"):
+			errs.Add(fmt.Sprintf("%s.artifacts[%d]", prefix, j), fmt.Sprintf("invalid glob '%s'", glob))
+		case path.IsAbs(glob) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../"):
+			errs.Add(fmt.Sprintf("%s.artifacts[%d]", prefix, j), fmt.Sprintf("'%s' must be relative to the worktree and inside it", glob))
+		}
+	}
+	if ck == nil {
+		return
+	}
+	if len(ck.Files) == 0 && !ck.Commit {
+		errs.Add(prefix+".cache_key", "must set files or commit")
+	}
+	for j, glob := range ck.Files {
+		if !validGlob(glob) {
+			errs.Add(fmt.Sprintf("%s.cache_key.files[%d]", prefix, j), fmt.Sprintf("invalid glob '%s'", glob))
+		}
+	}
+}
+
+// validateTriggers checks the triggers of a workflow. Schedules are only
+// checked for their shape here; the workflow package parses them fully.
+func (v *Validator) validateTriggers(tr *WorkflowTriggerConfig, prefix string, errs *ValidationError) {
+	if len(tr.Files) == 0 && len(tr.Events) == 0 && tr.Schedule == "" {
+		errs.Add(prefix, "must set files, events or schedule")
+	}
+	for j, glob := range tr.Files {
+		if !validGlob(glob) {
+			errs.Add(fmt.Sprintf("%s.files[%d]", prefix, j), fmt.Sprintf("invalid glob '%s'", glob))
+		}
+	}
+	for j, glob := range tr.Ignore {
+		if !validGlob(glob) {
+			errs.Add(fmt.Sprintf("%s.ignore[%d]", prefix, j), fmt.Sprintf("invalid glob '%s'", glob))
+		}
+	}
+	for j, pattern := range tr.Events {
+		if pattern == "" {
+			errs.Add(fmt.Sprintf("%s.events[%d]", prefix, j), "must not be empty")
+		}
+	}
+	if tr.Schedule != "" && !strings.HasPrefix(tr.Schedule, "@") && len(strings.Fields(tr.Schedule)) != 5 {
+		errs.Add(prefix+".schedule", "must have 5 fields (minute hour day-of-month month day-of-week) or be a descriptor such as @hourly")
+	}
+	if tr.Debounce != "" {
+		d, err := time.ParseDuration(tr.Debounce)
+		if err != nil {
+			errs.Add(prefix+".debounce", fmt.Sprintf("invalid duration format: %s", err))
+		} else if d < 0 {
+			errs.Add(prefix+".debounce", "must be positive")
+		}
+	}
+	switch tr.Policy {
+	case "", "queue", "cancel", "skip":
+	default:
+		errs.Add(prefix+".policy", fmt.Sprintf("invalid policy '%s', must be one of: queue, cancel, skip", tr.Policy))
+	}
+}
+
+// validGlob reports whether each slash-separated segment of a glob is a
+// valid path.Match pattern.
+func validGlob(glob string) bool {
+	if glob == "" {
+		return false
+	}
+	for _, seg := range strings.Split(glob, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// findCycle returns a cycle in the graph where each of keys points to the
+// keys in next, as the keys around it, or nil if there is none. Keys not in
+// next are ignored.
+func findCycle(keys []string, next map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(keys))
+	var path []string
+	var visit func(key string) []string
+	visit = func(key string) []string {
+		switch state[key] {
+		case visiting:
+			for i, k := range path {
+				if k == key {
+					return append(append([]string(nil), path[i:]...), key)
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[key] = visiting
+		path = append(path, key)
+		for _, to := range next[key] {
+			if _, ok := next[to]; !ok {
+				continue
+			}
+			if cycle := visit(to); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[key] = visited
+		return nil
+	}
+	for _, key := range keys {
+		if cycle := visit(key); cycle != nil {
+			return cycle
 		}
 	}
+	return nil
 }
 
 func (v *Validator) validateTerminal(cfg *Config, errs *ValidationError) {
@@ -282,6 +640,19 @@ func (v *Validator) validateUI(cfg *Config, errs *ValidationError) {
 	}
 }
 
+func (v *Validator) validateWorktree(cfg *Config, errs *ValidationError) {
+	ports := cfg.Worktree.Ports
+	if ports.Start == 0 && ports.End == 0 {
+		return
+	}
+	if ports.Start < 1 || ports.Start > 65535 {
+		errs.Add("worktree.ports.start", "must be between 1 and 65535")
+	}
+	if ports.End < ports.Start || ports.End > 65535 {
+		errs.Add("worktree.ports.end", fmt.Sprintf("must be between start (%d) and 65535", ports.Start))
+	}
+}
+
 func (v *Validator) validateDurations(cfg *Config, errs *ValidationError) {
 	// Validate watch debounce
 	if cfg.Watch.Debounce != "" {
@@ -303,6 +674,39 @@ func (v *Validator) validateDurations(cfg *Config, errs *ValidationError) {
 		}
 	}
 
+	// Validate webhooks
+	webhookIDs := make(map[string]bool)
+	for i, hook := range cfg.Events.Webhooks {
+		prefix := fmt.Sprintf("events.webhooks[%d]", i)
+		if hook.ID != "" {
+			if webhookIDs[hook.ID] {
+				errs.Add(prefix+".id", fmt.Sprintf("duplicate webhook id: %s", hook.ID))
+			}
+			webhookIDs[hook.ID] = true
+		}
+		if hook.URL == "" {
+			errs.Add(prefix+".url", "is required")
+		} else if u, err := url.Parse(hook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add(prefix+".url", "must be an http or https URL")
+		}
+		for j, pattern := range hook.Events {
+			if pattern == "" {
+				errs.Add(fmt.Sprintf("%s.events[%d]", prefix, j), "must not be empty")
+			}
+		}
+		if hook.Timeout != "" {
+			d, err := time.ParseDuration(hook.Timeout)
+			if err != nil {
+				errs.Add(prefix+".timeout", fmt.Sprintf("invalid duration format: %s", err))
+			} else if d <= 0 {
+				errs.Add(prefix+".timeout", "must be positive")
+			}
+		}
+		if hook.Retries < 0 {
+			errs.Add(prefix+".retries", "must not be negative")
+		}
+	}
+
 	// Validate workflow timeouts
 	for i, wf := range cfg.Workflows {
 		if wf.Timeout != "" {
@@ -354,6 +758,36 @@ func (v *Validator) validateCrossReferences(cfg *Config, errs *ValidationError)
 			}
 		}
 	}
+
+	// Validate steps run workflows that exist, without a workflow running
+	// itself through its steps
+	workflowIDs := make(map[string]bool)
+	for _, wf := range cfg.Workflows {
+		workflowIDs[wf.ID] = true
+	}
+	for i, wf := range cfg.Workflows {
+		for j, step := range wf.Steps {
+			if step.Workflow != "" && !workflowIDs[step.Workflow] {
+				errs.Add(fmt.Sprintf("workflows[%d].steps[%d].workflow", i, j),
+					fmt.Sprintf("references unknown workflow '%s'", step.Workflow))
+			}
+		}
+	}
+	keys := make([]string, len(cfg.Workflows))
+	uses := make(map[string][]string, len(cfg.Workflows))
+	for i, wf := range cfg.Workflows {
+		keys[i] = wf.ID
+		var used []string
+		for _, step := range wf.Steps {
+			if step.Workflow != "" {
+				used = append(used, step.Workflow)
+			}
+		}
+		uses[wf.ID] = used
+	}
+	if cycle := findCycle(keys, uses); cycle != nil {
+		errs.Add("workflows", fmt.Sprintf("steps run workflows in a cycle: %s", strings.Join(cycle, " -> ")))
+	}
 }
 
 func (v *Validator) validateTraceGroups(cfg *Config, errs *ValidationError) {
@@ -362,6 +796,11 @@ func (v *Validator) validateTraceGroups(cfg *Config, errs *ValidationError) {
 	for _, lv := range cfg.LogViewers {
 		logViewerNames[lv.Name] = true
 	}
+	for _, listener := range cfg.Proxy {
+		if name := listener.AccessLogViewer(); name != "" {
+			logViewerNames[name] = true
+		}
+	}
 
 	// Track seen trace group names for uniqueness check
 	seenNames := make(map[string]bool)
@@ -409,6 +848,15 @@ func (v *Validator) validateLogViewers(cfg *Config, errs *ValidationError) {
 			errs.Add(fmt.Sprintf("log_viewers[%d].mode", i),
 				fmt.Sprintf("invalid mode '%s', must be one of: live, explore", lv.Mode))
 		}
+		if strings.HasPrefix(lv.Name, ProxyAccessLogViewerPrefix) {
+			errs.Add(fmt.Sprintf("log_viewers[%d].name", i),
+				fmt.Sprintf("names starting with '%s' are reserved for proxy access logs", ProxyAccessLogViewerPrefix))
+		}
+		v.validateMultiline(lv.Parser.Multiline, fmt.Sprintf("log_viewers[%d].parser.multiline", i), errs)
+	}
+	v.validateMultiline(cfg.LoggingDefaults.Parser.Multiline, "logging_defaults.parser.multiline", errs)
+	for i, svc := range cfg.Services {
+		v.validateMultiline(svc.Logging.Parser.Multiline, fmt.Sprintf("services[%d].logging.parser.multiline", i), errs)
 	}
 
 	if cfg.LogViewerSettings.IdleTimeout != "" && cfg.LogViewerSettings.IdleTimeout != "0" {
@@ -430,6 +878,112 @@ func (v *Validator) validateLogViewers(cfg *Config, errs *ValidationError) {
 	}
 }
 
+// validateMultiline checks a parser's multiline patterns and limits.
+func (v *Validator) validateMultiline(m *MultilineConfig, prefix string, errs *ValidationError) {
+	if m == nil {
+		return
+	}
+	if m.Start == "" && m.Continuation == "" {
+		errs.Add(prefix, "requires start or continuation")
+	}
+	if m.Start != "" {
+		if _, err := regexp.Compile(m.Start); err != nil {
+			errs.Add(prefix+".start", fmt.Sprintf("invalid regex: %s", err))
+		}
+	}
+	if m.Continuation != "" {
+		if _, err := regexp.Compile(m.Continuation); err != nil {
+			errs.Add(prefix+".continuation", fmt.Sprintf("invalid regex: %s", err))
+		}
+	}
+	if m.MaxLines < 0 {
+		errs.Add(prefix+".max_lines", "must be positive")
+	}
+	if m.Timeout != "" {
+		d, err := time.ParseDuration(m.Timeout)
+		if err != nil {
+			errs.Add(prefix+".timeout", fmt.Sprintf("invalid duration format: %s", err))
+		} else if d <= 0 {
+			errs.Add(prefix+".timeout", "must be positive")
+		}
+	}
+}
+
+func (v *Validator) validateAlerts(cfg *Config, errs *ValidationError) {
+	viewerNames := make(map[string]bool)
+	for _, lv := range cfg.LogViewers {
+		viewerNames[lv.Name] = true
+	}
+	for _, listener := range cfg.Proxy {
+		if name := listener.AccessLogViewer(); name != "" {
+			viewerNames[name] = true
+		}
+	}
+	serviceNames := make(map[string]bool)
+	for _, svc := range cfg.Services {
+		serviceNames[svc.Name] = true
+	}
+
+	seenNames := make(map[string]bool)
+	for i, rule := range cfg.Alerts {
+		prefix := fmt.Sprintf("alerts[%d]", i)
+
+		if rule.Name == "" {
+			errs.Add(prefix+".name", "is required")
+		} else if seenNames[rule.Name] {
+			errs.Add(prefix+".name", fmt.Sprintf("duplicate alert name '%s'", rule.Name))
+		} else {
+			seenNames[rule.Name] = true
+		}
+
+		switch {
+		case rule.Viewer == "" && rule.Service == "":
+			errs.Add(prefix, "must set viewer or service")
+		case rule.Viewer != "" && rule.Service != "":
+			errs.Add(prefix, "must set only one of viewer or service")
+		case rule.Service != "":
+			if !serviceNames[rule.Service] {
+				errs.Add(prefix+".service", fmt.Sprintf("references unknown service '%s'", rule.Service))
+			}
+		case strings.HasPrefix(rule.Viewer, "svc:"):
+			// Service viewers are created at startup from services with a parser
+			if !serviceNames[strings.TrimPrefix(rule.Viewer, "svc:")] {
+				errs.Add(prefix+".viewer", fmt.Sprintf("references unknown service '%s'", strings.TrimPrefix(rule.Viewer, "svc:")))
+			}
+		case !viewerNames[rule.Viewer]:
+			errs.Add(prefix+".viewer", fmt.Sprintf("references unknown log viewer '%s'", rule.Viewer))
+		}
+
+		if strings.TrimSpace(rule.Filter) == "" {
+			errs.Add(prefix+".filter", "is required")
+		}
+		if rule.Threshold < 0 {
+			errs.Add(prefix+".threshold", "must not be negative")
+		}
+		if rule.Window != "" {
+			d, err := time.ParseDuration(rule.Window)
+			if err != nil {
+				errs.Add(prefix+".window", fmt.Sprintf("invalid duration format: %s", err))
+			} else if d <= 0 {
+				errs.Add(prefix+".window", "must be positive")
+			}
+		}
+		if rule.Cooldown != "" {
+			d, err := time.ParseDuration(rule.Cooldown)
+			if err != nil {
+				errs.Add(prefix+".cooldown", fmt.Sprintf("invalid duration format: %s", err))
+			} else if d < 0 {
+				errs.Add(prefix+".cooldown", "must be positive")
+			}
+		}
+		switch rule.Severity {
+		case "", "info", "warning", "critical":
+		default:
+			errs.Add(prefix+".severity", fmt.Sprintf("invalid severity '%s', must be one of: info, warning, critical", rule.Severity))
+		}
+	}
+}
+
 func (v *Validator) validateProxy(cfg *Config, errs *ValidationError) {
 	for i, listener := range cfg.Proxy {
 		prefix := fmt.Sprintf("proxy[%d]", i)
@@ -451,9 +1005,29 @@ func (v *Validator) validateProxy(cfg *Config, errs *ValidationError) {
 			errs.Add(prefix, "both tls_cert and tls_key must be specified together")
 		}
 
+		if listener.Capture.MaxRequests < 0 {
+			errs.Add(prefix+".capture.max_requests", "must not be negative")
+		}
+		if listener.Capture.MaxBodyBytes < 0 {
+			errs.Add(prefix+".capture.max_body_bytes", "must not be negative")
+		}
+
+		if h := listener.AccessLog.TraceHeader; h != "" && !httpTokenPattern.MatchString(h) {
+			errs.Add(prefix+".access_log.trace_header", fmt.Sprintf("invalid header name %q", h))
+		}
+
+		if wt := listener.Worktrees; wt.Enabled {
+			if wt.Header != "" && !httpTokenPattern.MatchString(wt.Header) {
+				errs.Add(prefix+".worktrees.header", fmt.Sprintf("invalid header name %q", wt.Header))
+			}
+			if wt.Cookie != "" && !httpTokenPattern.MatchString(wt.Cookie) {
+				errs.Add(prefix+".worktrees.cookie", fmt.Sprintf("invalid cookie name %q", wt.Cookie))
+			}
+		}
+
 		for j, route := range listener.Routes {
 			routePrefix := fmt.Sprintf("%s.routes[%d]", prefix, j)
-			if route.Upstream == "" {
+			if route.Upstream == "" && !route.Mock.servesAll() {
 				errs.Add(routePrefix+".upstream", "is required")
 			}
 			if route.PathRegexp != "" {
@@ -461,8 +1035,196 @@ func (v *Validator) validateProxy(cfg *Config, errs *ValidationError) {
 					errs.Add(routePrefix+".path_regexp", fmt.Sprintf("invalid regex: %s", err))
 				}
 			}
+			v.validateProxyRoute(route, routePrefix, errs)
+		}
+	}
+}
+
+// httpMethodPattern matches an HTTP method token.
+var httpMethodPattern = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// httpTokenPattern matches an HTTP token, as used for header and cookie names.
+var httpTokenPattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// validateProxyRoute checks a route's host, method, header and query
+// matchers and its rewrite and header actions.
+func (v *Validator) validateProxyRoute(route ProxyRouteConfig, prefix string, errs *ValidationError) {
+	for k, host := range route.Hosts {
+		if err := validateHostPattern(host); err != nil {
+			errs.Add(fmt.Sprintf("%s.hosts[%d]", prefix, k), err.Error())
+		}
+	}
+	for k, method := range route.Methods {
+		if !httpMethodPattern.MatchString(method) {
+			errs.Add(fmt.Sprintf("%s.methods[%d]", prefix, k), fmt.Sprintf("invalid HTTP method %q", method))
+		}
+	}
+	for name, pattern := range route.Headers {
+		if name == "" {
+			errs.Add(prefix+".headers", "header name must not be empty")
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			errs.Add(prefix+".headers."+name, fmt.Sprintf("invalid regex: %s", err))
+		}
+	}
+	for name, pattern := range route.Query {
+		if name == "" {
+			errs.Add(prefix+".query", "parameter name must not be empty")
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			errs.Add(prefix+".query."+name, fmt.Sprintf("invalid regex: %s", err))
+		}
+	}
+
+	if route.StripPrefix != "" && !strings.HasPrefix(route.StripPrefix, "/") {
+		errs.Add(prefix+".strip_prefix", "must start with /")
+	}
+	if route.Rewrite.Pattern != "" {
+		if _, err := regexp.Compile(route.Rewrite.Pattern); err != nil {
+			errs.Add(prefix+".rewrite.pattern", fmt.Sprintf("invalid regex: %s", err))
+		}
+	} else if route.Rewrite.Replacement != "" {
+		errs.Add(prefix+".rewrite.pattern", "is required with rewrite.replacement")
+	}
+
+	if err := route.Faults.Validate(); err != nil {
+		errs.Add(prefix+".faults", err.Error())
+	}
+	validateProxyMock(route.Mock, prefix+".mock", errs)
+
+	headerActions := []struct {
+		field   string
+		actions ProxyHeaderActions
+	}{
+		{"request_headers", route.RequestHeaders},
+		{"response_headers", route.ResponseHeaders},
+	}
+	for _, ha := range headerActions {
+		field, actions := ha.field, ha.actions
+		for name := range actions.Set {
+			if name == "" {
+				errs.Add(prefix+"."+field+".set", "header name must not be empty")
+			}
+		}
+		for k, name := range actions.Remove {
+			if name == "" {
+				errs.Add(fmt.Sprintf("%s.%s.remove[%d]", prefix, field, k), "header name must not be empty")
+			}
+		}
+	}
+}
+
+// servesAll reports whether the mock answers every request itself, so the
+// route needs no upstream.
+func (m ProxyMockConfig) servesAll() bool {
+	return m.Configured() && m.Mode != "record" && !m.Fallback
+}
+
+// validateProxyMock checks a route's mock mode and stub matchers.
+func validateProxyMock(m ProxyMockConfig, prefix string, errs *ValidationError) {
+	switch m.Mode {
+	case "", "replay", "record":
+		if m.Mode != "" && m.Fixtures == "" {
+			errs.Add(prefix+".fixtures", fmt.Sprintf("is required with mode %q", m.Mode))
+		}
+	default:
+		errs.Add(prefix+".mode", fmt.Sprintf("must be replay or record, got %q", m.Mode))
+	}
+	for k, resp := range m.Responses {
+		respPrefix := fmt.Sprintf("%s.responses[%d]", prefix, k)
+		if resp.Method != "" && !httpMethodPattern.MatchString(resp.Method) {
+			errs.Add(respPrefix+".method", fmt.Sprintf("invalid HTTP method %q", resp.Method))
+		}
+		for _, re := range []struct{ field, pattern string }{
+			{"path", resp.Path},
+			{"request_body", resp.RequestBody},
+		} {
+			if _, err := regexp.Compile(re.pattern); err != nil {
+				errs.Add(respPrefix+"."+re.field, fmt.Sprintf("invalid regex: %s", err))
+			}
+		}
+		if resp.Status != 0 && (resp.Status < 100 || resp.Status > 599) {
+			errs.Add(respPrefix+".status", fmt.Sprintf("invalid HTTP status %d", resp.Status))
+		}
+		if resp.Body != "" && resp.File != "" {
+			errs.Add(respPrefix, "body and file are mutually exclusive")
+		}
+	}
+}
+
+// Validate checks the fault settings' durations, rates and status.
+func (f ProxyFaultConfig) Validate() error {
+	var latency, latencyMax time.Duration
+	for _, d := range []struct {
+		field string
+		value string
+		out   *time.Duration
+	}{
+		{"latency", f.Latency, &latency},
+		{"latency_max", f.LatencyMax, &latencyMax},
+		{"websocket_drop_after", f.WebSocketDropAfter, nil},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %s", d.field, d.value)
+		}
+		if v < 0 {
+			return fmt.Errorf("%s must not be negative", d.field)
+		}
+		if d.out != nil {
+			*d.out = v
+		}
+	}
+	if f.LatencyMax != "" && latencyMax < latency {
+		return fmt.Errorf("latency_max must not be less than latency")
+	}
+	for _, r := range []struct {
+		field string
+		value float64
+	}{
+		{"error_rate", f.ErrorRate},
+		{"abort_rate", f.AbortRate},
+		{"websocket_drop_rate", f.WebSocketDropRate},
+	} {
+		if r.value < 0 || r.value > 100 {
+			return fmt.Errorf("%s must be between 0 and 100", r.field)
+		}
+	}
+	if f.ErrorRate+f.AbortRate > 100 {
+		return fmt.Errorf("error_rate and abort_rate must not add up to more than 100")
+	}
+	if f.ErrorStatus != 0 && (f.ErrorStatus < 400 || f.ErrorStatus > 599) {
+		return fmt.Errorf("error_status must be a 4xx or 5xx status")
+	}
+	if f.Bandwidth < 0 {
+		return fmt.Errorf("bandwidth must not be negative")
+	}
+	return nil
+}
+
+// validateHostPattern checks a proxy route host pattern. A wildcard may only
+// stand for whole labels at the start ("*.example.com") or end ("admin.*").
+func validateHostPattern(host string) error {
+	if host == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if host == "*" {
+		return nil
+	}
+	if strings.Contains(host, ":") {
+		return fmt.Errorf("host pattern %q must not include a port", host)
+	}
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if label == "" {
+			return fmt.Errorf("host pattern %q has an empty label", host)
+		}
+		if strings.Contains(label, "*") && (label != "*" || (i != 0 && i != len(labels)-1)) {
+			return fmt.Errorf("host pattern %q: * may only be the first or last label", host)
 		}
 	}
+	return nil
 }
 
 // parseDurationWithDays parses a duration string that may include days (e.g., "7d").
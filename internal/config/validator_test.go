@@ -277,6 +277,353 @@ func TestValidator_Validate_DuplicateWorkflowIDs(t *testing.T) {
 	assert.Contains(t, err.Error(), "duplicate")
 }
 
+func TestValidator_Validate_WorkflowSteps(t *testing.T) {
+	lint := WorkflowConfig{ID: "lint", Name: "Lint", Command: "golangci-lint run"}
+	tests := []struct {
+		name        string
+		workflow    WorkflowConfig
+		errContains string
+	}{
+		{
+			name: "valid",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "lint", Workflow: "lint"},
+				{ID: "test", Command: "go test ./...", Timeout: "5m", Env: map[string]string{"TOKEN": `{{ secret "token" }}`}},
+				{ID: "report", Command: []interface{}{"echo", "failed"}, Needs: []string{"lint", "test"}, If: "failure"},
+			}},
+		},
+		{
+			name: "steps with command",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Command: "make", Steps: []WorkflowStepConfig{
+				{ID: "test", Command: "go test ./..."},
+			}},
+			errContains: "cannot be combined",
+		},
+		{
+			name: "duplicate step id",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "test", Command: "go test ./..."},
+				{ID: "test", Command: "go vet ./..."},
+			}},
+			errContains: "duplicate step id",
+		},
+		{
+			name: "command and workflow",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "lint", Command: "make lint", Workflow: "lint"},
+			}},
+			errContains: "exactly one of command or workflow",
+		},
+		{
+			name: "unknown need",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "build", Command: "make", Needs: []string{"test"}},
+			}},
+			errContains: "unknown step 'test'",
+		},
+		{
+			name: "needs cycle",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "a", Command: "true", Needs: []string{"c"}},
+				{ID: "b", Command: "true", Needs: []string{"a"}},
+				{ID: "c", Command: "true", Needs: []string{"b"}},
+			}},
+			errContains: "cycle: a -> c -> b -> a",
+		},
+		{
+			name: "invalid condition",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "test", Command: "go test ./...", If: "{{ eq .Inputs.env"},
+			}},
+			errContains: "steps[0].if",
+		},
+		{
+			name: "unknown workflow",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "deploy", Workflow: "deploy"},
+			}},
+			errContains: "unknown workflow 'deploy'",
+		},
+		{
+			name: "workflow running itself",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "again", Workflow: "check"},
+			}},
+			errContains: "cycle: check -> check",
+		},
+		{
+			name: "secret outside env",
+			workflow: WorkflowConfig{ID: "check", Name: "Check", Steps: []WorkflowStepConfig{
+				{ID: "test", Command: `curl -H {{ secret "token" }}`},
+			}},
+			errContains: "secret references",
+		},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Version:   "1.0",
+				Project:   ProjectConfig{Name: "test"},
+				Workflows: []WorkflowConfig{lint, tt.workflow},
+			}
+			err := validator.Validate(cfg)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidator_Validate_WorkflowTriggers(t *testing.T) {
+	tests := []struct {
+		name        string
+		triggers    WorkflowTriggerConfig
+		errContains string
+	}{
+		{
+			name: "valid",
+			triggers: WorkflowTriggerConfig{
+				Files:    []string{"**/*.go", "go.mod"},
+				Ignore:   []string{"vendor/**"},
+				Events:   []string{"binary.changed", "service.*"},
+				Schedule: "*/30 9-17 * * mon-fri",
+				Debounce: "1s",
+				Policy:   "cancel",
+			},
+		},
+		{
+			name:     "descriptor schedule",
+			triggers: WorkflowTriggerConfig{Schedule: "@every 10m"},
+		},
+		{
+			name:        "nothing to trigger on",
+			triggers:    WorkflowTriggerConfig{Policy: "skip"},
+			errContains: "must set files, events or schedule",
+		},
+		{
+			name:        "invalid glob",
+			triggers:    WorkflowTriggerConfig{Files: []string{"internal/[a"}},
+			errContains: "triggers.files[0]",
+		},
+		{
+			name:        "empty event pattern",
+			triggers:    WorkflowTriggerConfig{Events: []string{""}},
+			errContains: "triggers.events[0]",
+		},
+		{
+			name:        "schedule fields",
+			triggers:    WorkflowTriggerConfig{Schedule: "0 9 * *"},
+			errContains: "must have 5 fields",
+		},
+		{
+			name:        "invalid debounce",
+			triggers:    WorkflowTriggerConfig{Files: []string{"*.go"}, Debounce: "soon"},
+			errContains: "triggers.debounce",
+		},
+		{
+			name:        "invalid policy",
+			triggers:    WorkflowTriggerConfig{Files: []string{"*.go"}, Policy: "parallel"},
+			errContains: "invalid policy 'parallel'",
+		},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			triggers := tt.triggers
+			cfg := &Config{
+				Version:   "1.0",
+				Project:   ProjectConfig{Name: "test"},
+				Workflows: []WorkflowConfig{{ID: "test", Name: "Test", Command: "go test ./...", Triggers: &triggers}},
+			}
+			err := validator.Validate(cfg)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidator_Validate_OutputParsers(t *testing.T) {
+	lint := OutputParserConfig{
+		Name:  "lint",
+		Rules: []OutputParserRuleConfig{{Pattern: `^(?P<file>[^:]+):(?P<line>\d+): (?P<message>.*)$`, Type: "error"}},
+	}
+	tests := []struct {
+		name        string
+		parsers     []OutputParserConfig
+		workflow    WorkflowConfig
+		errContains string
+	}{
+		{
+			name:     "custom parser",
+			parsers:  []OutputParserConfig{lint},
+			workflow: WorkflowConfig{Command: "make lint", OutputParser: "lint"},
+		},
+		{
+			name:     "junit results",
+			workflow: WorkflowConfig{Command: "npm test", OutputParser: "junit", Results: []string{"reports/*.xml"}},
+		},
+		{
+			name:        "unknown parser",
+			workflow:    WorkflowConfig{Command: "npm test", OutputParser: "jest"},
+			errContains: "invalid parser 'jest'",
+		},
+		{
+			name:        "built-in name",
+			parsers:     []OutputParserConfig{{Name: "tap", Rules: lint.Rules}},
+			workflow:    WorkflowConfig{Command: "make lint"},
+			errContains: "parser 'tap' is already defined",
+		},
+		{
+			name:        "no rules",
+			parsers:     []OutputParserConfig{{Name: "lint"}},
+			workflow:    WorkflowConfig{Command: "make lint"},
+			errContains: "output_parsers[0].rules",
+		},
+		{
+			name:        "invalid pattern",
+			parsers:     []OutputParserConfig{{Name: "lint", Rules: []OutputParserRuleConfig{{Pattern: "(", Type: "error"}}}},
+			workflow:    WorkflowConfig{Command: "make lint"},
+			errContains: "invalid regex",
+		},
+		{
+			name:        "unknown group",
+			parsers:     []OutputParserConfig{{Name: "lint", Rules: []OutputParserRuleConfig{{Pattern: "(?P<path>.*)", Type: "error"}}}},
+			workflow:    WorkflowConfig{Command: "make lint"},
+			errContains: "unknown group 'path'",
+		},
+		{
+			name:        "invalid type",
+			parsers:     []OutputParserConfig{{Name: "lint", Rules: []OutputParserRuleConfig{{Pattern: "x", Type: "fatal"}}}},
+			workflow:    WorkflowConfig{Command: "make lint"},
+			errContains: "invalid type 'fatal'",
+		},
+		{
+			name:        "results without parser",
+			workflow:    WorkflowConfig{Command: "npm test", Results: []string{"reports/*.xml"}},
+			errContains: "results: requires output_parser",
+		},
+		{
+			name: "step results with workflow parser",
+			workflow: WorkflowConfig{OutputParser: "junit", Steps: []WorkflowStepConfig{
+				{ID: "test", Command: "npm test", Results: []string{"reports/*.xml"}},
+			}},
+		},
+		{
+			name: "results on workflow with steps",
+			workflow: WorkflowConfig{OutputParser: "junit", Results: []string{"reports/*.xml"}, Steps: []WorkflowStepConfig{
+				{ID: "test", Command: "npm test"},
+			}},
+			errContains: "set results on the steps",
+		},
+		{
+			name:        "invalid results glob",
+			workflow:    WorkflowConfig{Command: "npm test", OutputParser: "junit", Results: []string{"reports/**/*.xml"}},
+			errContains: "results[0]",
+		},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := tt.workflow
+			wf.ID, wf.Name = "test", "Test"
+			cfg := &Config{
+				Version:       "1.0",
+				Project:       ProjectConfig{Name: "test"},
+				Workflows:     []WorkflowConfig{wf},
+				OutputParsers: tt.parsers,
+			}
+			err := validator.Validate(cfg)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestValidator_Validate_Artifacts(t *testing.T) {
+	tests := []struct {
+		name        string
+		workflow    WorkflowConfig
+		errContains string
+	}{
+		{
+			name: "artifacts and cache key",
+			workflow: WorkflowConfig{
+				Command:   "go build -o bin/app",
+				Artifacts: []string{"bin/*", "coverage.out"},
+				CacheKey:  &CacheKeyConfig{Files: []string{"**/*.go", "go.sum"}, Commit: true},
+			},
+		},
+		{
+			name: "step artifacts and cache key",
+			workflow: WorkflowConfig{Steps: []WorkflowStepConfig{
+				{ID: "build", Command: "make", Artifacts: []string{"dist"}, CacheKey: &CacheKeyConfig{Files: []string{"src/**"}}},
+			}},
+		},
+		{
+			name:        "artifacts outside the worktree",
+			workflow:    WorkflowConfig{Command: "make", Artifacts: []string{"../out/*"}},
+			errContains: "must be relative to the worktree and inside it",
+		},
+		{
+			name:        "absolute artifacts",
+			workflow:    WorkflowConfig{Command: "make", Artifacts: []string{"/tmp/out"}},
+			errContains: "artifacts[0]",
+		},
+		{
+			name:        "recursive artifacts glob",
+			workflow:    WorkflowConfig{Command: "make", Artifacts: []string{"dist/**/*.js"}},
+			errContains: "invalid glob 'dist/**/*.js'",
+		},
+		{
+			name:        "empty cache key",
+			workflow:    WorkflowConfig{Command: "make", CacheKey: &CacheKeyConfig{}},
+			errContains: "cache_key: must set files or commit",
+		},
+		{
+			name: "invalid step cache key glob",
+			workflow: WorkflowConfig{Steps: []WorkflowStepConfig{
+				{ID: "build", Command: "make", CacheKey: &CacheKeyConfig{Files: []string{"src/["}}},
+			}},
+			errContains: "steps[0].cache_key.files[0]",
+		},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := tt.workflow
+			wf.ID, wf.Name = "test", "Test"
+			cfg := &Config{
+				Version:   "1.0",
+				Project:   ProjectConfig{Name: "test"},
+				Workflows: []WorkflowConfig{wf},
+			}
+			err := validator.Validate(cfg)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
 func TestValidator_Validate_ServerConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -558,6 +905,146 @@ func TestValidator_Validate_ProxyConfig(t *testing.T) {
 			},
 			errContains: "mutually exclusive",
 		},
+		{
+			name: "negative capture max_body_bytes",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Capture: ProxyCaptureConfig{Enabled: true, MaxBodyBytes: -1}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].capture.max_body_bytes",
+		},
+		{
+			name: "host wildcard in middle label",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Hosts: []string{"api.*.example.com"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].hosts[0]",
+		},
+		{
+			name: "host with port",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Hosts: []string{"api.example.com:443"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "must not include a port",
+		},
+		{
+			name: "invalid method",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Methods: []string{"GET POST"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].methods[0]",
+		},
+		{
+			name: "invalid header regex",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Headers: map[string]string{"X-Canary": "[x"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].headers.X-Canary",
+		},
+		{
+			name: "invalid query regex",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Query: map[string]string{"v": "(x"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].query.v",
+		},
+		{
+			name: "strip_prefix without slash",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{StripPrefix: "api", Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].strip_prefix",
+		},
+		{
+			name: "rewrite replacement without pattern",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Rewrite: ProxyRewriteConfig{Replacement: "/v2"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].rewrite.pattern",
+		},
+		{
+			name: "empty response header to remove",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{ResponseHeaders: ProxyHeaderActions{Remove: []string{""}}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].response_headers.remove[0]",
+		},
+		{
+			name: "invalid worktree header",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Worktrees: ProxyWorktreesConfig{Enabled: true, Header: "X Worktree"}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].worktrees.header",
+		},
+		{
+			name: "invalid worktree cookie",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Worktrees: ProxyWorktreesConfig{Enabled: true, Cookie: "wt;x"}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].worktrees.cookie",
+		},
+		{
+			name: "invalid access log trace header",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", AccessLog: ProxyAccessLogConfig{TraceHeader: "X Trace"}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].access_log.trace_header",
+		},
+		{
+			name: "latency_max below latency",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Faults: ProxyFaultConfig{Latency: "2s", LatencyMax: "1s"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].faults: latency_max",
+		},
+		{
+			name: "fault rates over 100",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Faults: ProxyFaultConfig{ErrorRate: 60, AbortRate: 50}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].faults: error_rate and abort_rate",
+		},
+		{
+			name: "fault status not an error",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Faults: ProxyFaultConfig{ErrorRate: 10, ErrorStatus: 200}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].faults: error_status",
+		},
+		{
+			name: "mock falling back without upstream",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Mock: ProxyMockConfig{Fixtures: "fixtures", Fallback: true}}}},
+			},
+			errContains: "proxy[0].routes[0].upstream",
+		},
+		{
+			name: "mock mode without fixtures",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Mock: ProxyMockConfig{Mode: "record"}, Upstream: "localhost:3000"}}},
+			},
+			errContains: "proxy[0].routes[0].mock.fixtures",
+		},
+		{
+			name: "invalid mock mode",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Mock: ProxyMockConfig{Fixtures: "f", Mode: "playback"}}}},
+			},
+			errContains: "proxy[0].routes[0].mock.mode",
+		},
+		{
+			name: "invalid mock response",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Mock: ProxyMockConfig{Responses: []ProxyMockResponse{{Path: "(", Body: "x"}}}}}},
+			},
+			errContains: "proxy[0].routes[0].mock.responses[0].path",
+		},
+		{
+			name: "mock response with body and file",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{{Mock: ProxyMockConfig{Responses: []ProxyMockResponse{{Body: "x", File: "x.json"}}}}}},
+			},
+			errContains: "mutually exclusive",
+		},
 	}
 
 	validator := NewValidator()
@@ -617,6 +1104,44 @@ func TestValidator_Validate_ProxyConfig_Valid(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "with host, method, header and query matchers and actions",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{
+					{Hosts: []string{"api.*", "*.api.example.com"}, Methods: []string{"GET", "post"}, Upstream: "localhost:3001"},
+					{
+						Headers:         map[string]string{"X-Canary": "^1$", "X-Debug": ""},
+						Query:           map[string]string{"preview": ""},
+						StripPrefix:     "/app",
+						Rewrite:         ProxyRewriteConfig{Pattern: "^/v1/(.*)", Replacement: "/api/$1"},
+						RequestHeaders:  ProxyHeaderActions{Set: map[string]string{"X-Env": "dev"}, Remove: []string{"Cookie"}},
+						ResponseHeaders: ProxyHeaderActions{Remove: []string{"Server"}},
+						Upstream:        "localhost:3002",
+					},
+					{Hosts: []string{"*"}, Upstream: "localhost:3000"},
+				}},
+			},
+		},
+		{
+			name: "with mock responses and fixtures",
+			proxy: []ProxyListenerConfig{
+				{Listen: ":443", Routes: []ProxyRouteConfig{
+					{PathRegexp: "^/stripe/", Mock: ProxyMockConfig{Responses: []ProxyMockResponse{{Method: "POST", Path: "/charges$", Status: 201, Body: `{"id": "ch_1"}`}}}},
+					{PathRegexp: "^/api/", Mock: ProxyMockConfig{Fixtures: "fixtures/api", Mode: "record"}, Upstream: "localhost:3001"},
+					{Mock: ProxyMockConfig{Fixtures: "fixtures/web", Fallback: true}, Upstream: "localhost:3000"},
+				}},
+			},
+		},
+		{
+			name: "with worktree routing",
+			proxy: []ProxyListenerConfig{
+				{
+					Listen:    ":443",
+					Worktrees: ProxyWorktreesConfig{Enabled: true, Header: "X-Branch", Cookie: "branch", Subdomain: true},
+					Routes:    []ProxyRouteConfig{{Upstream: "{{.Worktree.Name}}.localhost:3000"}},
+				},
+			},
+		},
 		{
 			name:  "no proxy configured",
 			proxy: nil,
@@ -637,6 +1162,37 @@ func TestValidator_Validate_ProxyConfig_Valid(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_ProxyAccessLogViewers(t *testing.T) {
+	validator := NewValidator()
+	cfg := &Config{
+		Version: "1.0",
+		Project: ProjectConfig{Name: "test"},
+		Proxy: []ProxyListenerConfig{
+			{Listen: ":8080", AccessLog: ProxyAccessLogConfig{TraceHeader: "X-Request-Id"}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+			{Listen: ":8443", AccessLog: ProxyAccessLogConfig{Disabled: true}, Routes: []ProxyRouteConfig{{Upstream: "localhost:3000"}}},
+		},
+		LogViewers:  []LogViewerConfig{{Name: "nginx"}},
+		TraceGroups: []TraceGroupConfig{{Name: "web", LogViewers: []string{"proxy:8080", "nginx"}}},
+		Alerts:      []AlertRuleConfig{{Name: "5xx", Viewer: "proxy:8080", Filter: "status:>=500"}},
+	}
+	assert.Equal(t, "proxy:8080", cfg.Proxy[0].AccessLogViewer())
+	assert.Empty(t, cfg.Proxy[1].AccessLogViewer())
+	assert.NoError(t, validator.Validate(cfg))
+
+	// Disabled access logs have no viewer
+	cfg.TraceGroups[0].LogViewers = []string{"proxy:8443"}
+	err := validator.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown log viewer 'proxy:8443'")
+
+	// The prefix is reserved
+	cfg.TraceGroups = nil
+	cfg.LogViewers = []LogViewerConfig{{Name: "proxy:legacy"}}
+	err = validator.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "log_viewers[0].name")
+}
+
 func TestValidator_Validate_LogViewerMode(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -712,6 +1268,48 @@ func TestValidator_Validate_LogViewerSettingsDurations(t *testing.T) {
 	}
 }
 
+func TestValidator_Validate_LogParserMultiline(t *testing.T) {
+	tests := []struct {
+		name        string
+		multiline   *MultilineConfig
+		errContains string
+	}{
+		{name: "no multiline is valid", multiline: nil},
+		{name: "start pattern", multiline: &MultilineConfig{Start: `^\d{4}-`}},
+		{name: "continuation pattern", multiline: &MultilineConfig{Continuation: `^\s`, MaxLines: 50, Timeout: "500ms"}},
+		{name: "no patterns", multiline: &MultilineConfig{}, errContains: "start or continuation"},
+		{name: "bad start", multiline: &MultilineConfig{Start: "("}, errContains: "parser.multiline.start"},
+		{name: "bad continuation", multiline: &MultilineConfig{Continuation: "["}, errContains: "parser.multiline.continuation"},
+		{name: "negative max_lines", multiline: &MultilineConfig{Start: "^x", MaxLines: -1}, errContains: "parser.multiline.max_lines"},
+		{name: "bad timeout", multiline: &MultilineConfig{Start: "^x", Timeout: "soon"}, errContains: "parser.multiline.timeout"},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Version: "1.0",
+				Project: ProjectConfig{Name: "test"},
+				LogViewers: []LogViewerConfig{
+					{
+						Name:   "viewer1",
+						Source: LogSourceConfig{Type: "file", Path: "/var/log/app.log"},
+						Parser: LogParserConfig{Type: "json", Multiline: tt.multiline},
+					},
+				},
+			}
+			err := validator.Validate(cfg)
+			if tt.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				assert.Contains(t, err.Error(), "log_viewers[0]")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidationError_Error(t *testing.T) {
 	err := &ValidationError{
 		Errors: []FieldError{
@@ -732,3 +1330,187 @@ func TestValidationError_IsEmpty(t *testing.T) {
 	err.Errors = append(err.Errors, FieldError{Field: "test", Message: "error"})
 	assert.False(t, err.IsEmpty())
 }
+
+func TestValidator_Validate_ServiceProbes(t *testing.T) {
+	validator := NewValidator()
+
+	base := func(readiness, liveness *ProbeConfig) *Config {
+		return &Config{
+			Version: "1.0",
+			Project: ProjectConfig{Name: "test"},
+			Services: []ServiceConfig{
+				{Name: "api", Command: "./api", Readiness: readiness, Liveness: liveness},
+			},
+		}
+	}
+
+	assert.NoError(t, validator.Validate(base(
+		&ProbeConfig{HTTP: "http://localhost:8080/health", Interval: "500ms", Retries: 10},
+		&ProbeConfig{TCP: "localhost:8080"},
+	)))
+
+	// No check type
+	err := validator.Validate(base(&ProbeConfig{Interval: "1s"}, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "services[0].readiness")
+
+	// Two check types
+	err = validator.Validate(base(&ProbeConfig{HTTP: "http://x", TCP: "x:1"}, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exactly one of")
+
+	// log_line is readiness-only
+	err = validator.Validate(base(nil, &ProbeConfig{LogLine: "ok"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "services[0].liveness.log_line")
+
+	// Bad regex and duration
+	err = validator.Validate(base(&ProbeConfig{LogLine: "(", Timeout: "soon"}, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid regex")
+	assert.Contains(t, err.Error(), "services[0].readiness.timeout")
+}
+
+func TestValidator_Validate_SecretRefs(t *testing.T) {
+	validator := NewValidator()
+	ref := `{{ secret "stripe_key" }}`
+
+	cfg := &Config{
+		Version: "1.0",
+		Project: ProjectConfig{Name: "test"},
+		Services: []ServiceConfig{
+			{Name: "api", Command: "./api", Env: map[string]string{"STRIPE_KEY": ref}, EnvFile: ".env"},
+		},
+		Workflows: []WorkflowConfig{
+			{ID: "deploy", Name: "Deploy", Command: "./deploy", Env: map[string]string{"TOKEN": ref}},
+		},
+	}
+	assert.NoError(t, validator.Validate(cfg))
+
+	// Secrets would show up in process listings and logged commands
+	cfg.Services[0].Args = []string{"--key", ref}
+	cfg.Workflows[0].Command = []interface{}{"./deploy", ref}
+	err := validator.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "services[0]: secret references are only allowed in env values")
+	assert.Contains(t, err.Error(), "workflows[0]: secret references")
+}
+
+func TestValidator_Validate_WorktreePorts(t *testing.T) {
+	validator := NewValidator()
+	cfg := &Config{
+		Version:  "1.0",
+		Project:  ProjectConfig{Name: "test"},
+		Worktree: WorktreeConfig{Ports: WorktreePortsConfig{Start: 30000, End: 30099}},
+	}
+	assert.NoError(t, validator.Validate(cfg))
+
+	cfg.Worktree.Ports = WorktreePortsConfig{Start: 30000, End: 29000}
+	err := validator.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "worktree.ports.end")
+
+	cfg.Worktree.Ports = WorktreePortsConfig{Start: 70000, End: 70001}
+	err = validator.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "worktree.ports.start")
+}
+
+func TestValidator_Validate_Webhooks(t *testing.T) {
+	validator := NewValidator()
+
+	base := func(hooks ...WebhookConfig) *Config {
+		return &Config{
+			Version: "1.0",
+			Project: ProjectConfig{Name: "test"},
+			Events:  EventsConfig{Webhooks: hooks},
+		}
+	}
+
+	assert.NoError(t, validator.Validate(base(WebhookConfig{
+		ID:      "chat",
+		URL:     "https://chat.example.com/hook",
+		Events:  []string{"service.crashed", "workflow.*"},
+		Secret:  "s3cret",
+		Timeout: "5s",
+		Retries: 3,
+	})))
+
+	// Missing URL
+	err := validator.Validate(base(WebhookConfig{ID: "chat"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "events.webhooks[0].url")
+
+	// Non-HTTP URL
+	err = validator.Validate(base(WebhookConfig{ID: "chat", URL: "ftp://example.com"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "http or https")
+
+	// Duplicate IDs
+	err = validator.Validate(base(
+		WebhookConfig{ID: "chat", URL: "http://a"},
+		WebhookConfig{ID: "chat", URL: "http://b"},
+	))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate webhook id")
+
+	// Bad timeout
+	err = validator.Validate(base(WebhookConfig{URL: "http://a", Timeout: "soon"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "events.webhooks[0].timeout")
+
+	// Negative retries
+	err = validator.Validate(base(WebhookConfig{URL: "http://a", Retries: -1}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "events.webhooks[0].retries")
+}
+
+func TestValidator_Validate_Alerts(t *testing.T) {
+	validator := NewValidator()
+
+	base := func(rules ...AlertRuleConfig) *Config {
+		return &Config{
+			Version:    "1.0",
+			Project:    ProjectConfig{Name: "test"},
+			Services:   []ServiceConfig{{Name: "api", Command: "./api"}},
+			LogViewers: []LogViewerConfig{{Name: "nginx"}},
+			Alerts:     rules,
+		}
+	}
+
+	assert.NoError(t, validator.Validate(base(
+		AlertRuleConfig{Name: "errors", Viewer: "nginx", Filter: "level:error", Threshold: 6, Window: "1m", Cooldown: "10m", Severity: "critical"},
+		AlertRuleConfig{Name: "deadlock", Service: "api", Filter: `"deadlock detected"`},
+		AlertRuleConfig{Name: "svc-viewer", Viewer: "svc:api", Filter: "level:error"},
+	)))
+
+	tests := []struct {
+		rule AlertRuleConfig
+		want string
+	}{
+		{AlertRuleConfig{Viewer: "nginx", Filter: "x"}, "alerts[0].name"},
+		{AlertRuleConfig{Name: "a", Filter: "x"}, "must set viewer or service"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx", Service: "api", Filter: "x"}, "only one of viewer or service"},
+		{AlertRuleConfig{Name: "a", Viewer: "missing", Filter: "x"}, "unknown log viewer 'missing'"},
+		{AlertRuleConfig{Name: "a", Service: "missing", Filter: "x"}, "unknown service 'missing'"},
+		{AlertRuleConfig{Name: "a", Viewer: "svc:missing", Filter: "x"}, "unknown service 'missing'"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx"}, "alerts[0].filter"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "x", Threshold: -1}, "alerts[0].threshold"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "x", Window: "0s"}, "alerts[0].window"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "x", Cooldown: "later"}, "alerts[0].cooldown"},
+		{AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "x", Severity: "page"}, "alerts[0].severity"},
+	}
+	for _, tt := range tests {
+		err := validator.Validate(base(tt.rule))
+		require.Error(t, err, tt.want)
+		assert.Contains(t, err.Error(), tt.want)
+	}
+
+	// Duplicate names
+	err := validator.Validate(base(
+		AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "x"},
+		AlertRuleConfig{Name: "a", Viewer: "nginx", Filter: "y"},
+	))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate alert name")
+}
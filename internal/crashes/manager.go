@@ -40,6 +40,7 @@ type Manager struct {
 	defaultIDField  string            // Default field name for trace IDs
 	serviceIDFields map[string]string // Per-service ID field overrides
 	stackField      string            // Field name containing stack trace
+	redact          func(string) string
 }
 
 // NewManager creates a new crash manager.
@@ -95,6 +96,14 @@ func (m *Manager) handleCrashEvent(e events.Event) {
 		return
 	}
 
+	// Reports cover the active worktree's services. Services running for
+	// other worktrees label their events with their own worktree.
+	if m.worktreeManager != nil && e.Worktree != "" {
+		if active := m.worktreeManager.Active(); active != nil && e.Worktree != active.Name() {
+			return
+		}
+	}
+
 	// Build crash record
 	crash := Crash{
 		Version:   crashReportVersion,
@@ -322,11 +331,49 @@ func generateCrashID() string {
 	return time.Now().Format("20060102-150405.000")
 }
 
+// SetRedactor sets the function that removes secrets from the text of
+// saved crashes.
+func (m *Manager) SetRedactor(redact func(string) string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.redact = redact
+}
+
+// redacted returns a copy of crash with its error and log entries passed
+// through m.redact. The entries' fields are copied, not changed, as they
+// are shared with the service log buffers. Caller must hold m.mu.
+func (m *Manager) redacted(crash Crash) Crash {
+	if m.redact == nil {
+		return crash
+	}
+	crash.Error = m.redact(crash.Error)
+	entries := make([]CrashEntry, len(crash.Entries))
+	for i, entry := range crash.Entries {
+		entry.Message = m.redact(entry.Message)
+		entry.Raw = m.redact(entry.Raw)
+		if len(entry.Fields) > 0 {
+			fields := make(map[string]any, len(entry.Fields))
+			for k, v := range entry.Fields {
+				if str, ok := v.(string); ok {
+					v = m.redact(str)
+				}
+				fields[k] = v
+			}
+			entry.Fields = fields
+		}
+		entries[i] = entry
+	}
+	crash.Entries = entries
+	return crash
+}
+
 // Save saves a crash to disk.
 func (m *Manager) Save(crash Crash) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	crash = m.redacted(crash)
+
 	filename := filepath.Join(m.config.ReportsDir, crash.ID+".json")
 	data, err := json.MarshalIndent(crash, "", "  ")
 	if err != nil {
@@ -6,6 +6,7 @@ package crashes
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,6 +55,33 @@ func TestManager_SaveAndGet(t *testing.T) {
 	assert.Equal(t, crash.ExitCode, loaded.ExitCode)
 }
 
+func TestManager_Save_Redacts(t *testing.T) {
+	mgr, err := NewManager(Config{ReportsDir: t.TempDir()}, nil, nil, nil, "id", nil, "")
+	require.NoError(t, err)
+	mgr.SetRedactor(func(s string) string {
+		return strings.ReplaceAll(s, "sk_live_1", "[secret:stripe]")
+	})
+
+	fields := map[string]any{"key": "sk_live_1", "n": 1.0}
+	crash := Crash{
+		ID:    "20240101-120000.000",
+		Error: "panic: bad key sk_live_1",
+		Entries: []CrashEntry{
+			{Message: "using sk_live_1", Raw: `{"key":"sk_live_1"}`, Fields: fields},
+		},
+	}
+	require.NoError(t, mgr.Save(crash))
+
+	loaded, err := mgr.Get(crash.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "panic: bad key [secret:stripe]", loaded.Error)
+	assert.Equal(t, "using [secret:stripe]", loaded.Entries[0].Message)
+	assert.Equal(t, `{"key":"[secret:stripe]"}`, loaded.Entries[0].Raw)
+	assert.Equal(t, map[string]any{"key": "[secret:stripe]", "n": 1.0}, loaded.Entries[0].Fields)
+	// The log entry's fields are shared with the service log buffer
+	assert.Equal(t, "sk_live_1", fields["key"])
+}
+
 func TestManager_List(t *testing.T) {
 	dir := t.TempDir()
 	mgr, err := NewManager(Config{ReportsDir: dir}, nil, nil, nil, "id", nil, "")
@@ -4,11 +4,28 @@
 package events
 
 import (
+	"encoding/json"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// HistoryStore retains events for History queries.
+type HistoryStore interface {
+	// Add stores an event.
+	Add(event Event) error
+
+	// Query retrieves events matching filter, oldest first.
+	Query(filter EventFilter) ([]Event, error)
+
+	// Prune removes events older than the retention window or beyond the size limit.
+	Prune() error
+
+	// Close releases resources.
+	Close() error
+}
+
 // EventHistoryConfig configures event history.
 type EventHistoryConfig struct {
 	MaxEvents int
@@ -62,18 +79,35 @@ func (h *EventHistory) Query(filter EventFilter) ([]Event, error) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	result := make([]Event, 0)
+	// Sort by timestamp (oldest first)
+	all := make([]Event, len(h.events))
+	copy(all, h.events)
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Timestamp.Before(all[j].Timestamp)
+	})
 
-	for _, event := range h.events {
-		if h.matchesFilter(event, filter) {
-			result = append(result, event)
+	// Apply cursor: only events before the cursor event
+	if filter.Before != "" {
+		idx := -1
+		for i, event := range all {
+			if event.ID == filter.Before {
+				idx = i
+				break
+			}
 		}
+		if idx < 0 {
+			return []Event{}, nil // Cursor expired
+		}
+		all = all[:idx]
 	}
 
-	// Sort by timestamp (oldest first)
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Timestamp.Before(result[j].Timestamp)
-	})
+	search := newSearchTerms(filter.Search)
+	result := make([]Event, 0)
+	for _, event := range all {
+		if matchesFilter(h.matcher, event, filter) && search.match(event) {
+			result = append(result, event)
+		}
+	}
 
 	// Apply limit
 	if filter.Limit > 0 && len(result) > filter.Limit {
@@ -83,13 +117,14 @@ func (h *EventHistory) Query(filter EventFilter) ([]Event, error) {
 	return result, nil
 }
 
-// matchesFilter checks if an event matches the filter criteria.
-func (h *EventHistory) matchesFilter(event Event, filter EventFilter) bool {
+// matchesFilter checks if an event matches the filter's type, worktree, and
+// time criteria. Search and cursor handling are left to the caller.
+func matchesFilter(matcher *PatternMatcher, event Event, filter EventFilter) bool {
 	// Type filter
 	if len(filter.Types) > 0 {
 		matched := false
 		for _, pattern := range filter.Types {
-			if h.matcher.Match(event.Type, pattern) {
+			if matcher.Match(event.Type, pattern) {
 				matched = true
 				break
 			}
@@ -117,6 +152,34 @@ func (h *EventHistory) matchesFilter(event Event, filter EventFilter) bool {
 	return true
 }
 
+// searchTerms is a parsed full-text search query.
+type searchTerms []string
+
+// newSearchTerms splits a search query into lowercase terms.
+func newSearchTerms(query string) searchTerms {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// match reports whether every term appears in the event's type, worktree,
+// or JSON-encoded payload.
+func (terms searchTerms) match(event Event) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	text := strings.ToLower(event.Type + " " + event.Worktree)
+	if len(event.Payload) > 0 {
+		if b, err := json.Marshal(event.Payload); err == nil {
+			text += " " + strings.ToLower(string(b))
+		}
+	}
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
 // Prune removes events older than max age or exceeding max count.
 func (h *EventHistory) Prune() error {
 	h.mu.Lock()
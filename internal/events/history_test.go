@@ -359,3 +359,52 @@ func TestEventHistory_Integration_WithBus(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, history, 10)
 }
+
+func TestEventHistory_Query_Search(t *testing.T) {
+	history := NewEventHistory(EventHistoryConfig{
+		MaxEvents: 100,
+		MaxAge:    time.Hour,
+	})
+	defer history.Close()
+
+	history.Add(Event{ID: "1", Type: "service.crashed", Worktree: "main", Timestamp: time.Now(), Payload: map[string]interface{}{"service": "backend"}})
+	history.Add(Event{ID: "2", Type: "service.crashed", Worktree: "main", Timestamp: time.Now(), Payload: map[string]interface{}{"service": "frontend"}})
+	history.Add(Event{ID: "3", Type: "workflow.finished", Worktree: "feature", Timestamp: time.Now()})
+
+	result, err := history.Query(EventFilter{Search: "Backend"})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "1", result[0].ID)
+
+	result, err = history.Query(EventFilter{Search: "crashed main"})
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	result, err = history.Query(EventFilter{Search: "feature"})
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "3", result[0].ID)
+}
+
+func TestEventHistory_Query_Before(t *testing.T) {
+	history := NewEventHistory(EventHistoryConfig{
+		MaxEvents: 100,
+		MaxAge:    time.Hour,
+	})
+	defer history.Close()
+
+	base := time.Now()
+	for i := 0; i < 5; i++ {
+		history.Add(Event{ID: string(rune('a' + i)), Type: "service.started", Timestamp: base.Add(time.Duration(i) * time.Millisecond)})
+	}
+
+	result, err := history.Query(EventFilter{Before: "d", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "b", result[0].ID)
+	assert.Equal(t, "c", result[1].ID)
+
+	result, err = history.Query(EventFilter{Before: "missing"})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
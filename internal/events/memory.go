@@ -25,13 +25,14 @@ var ErrSubscriptionNotFound = errors.New("subscription not found")
 type MemoryBusConfig struct {
 	HistoryMaxEvents int
 	HistoryMaxAge    time.Duration
+	HistoryDir       string // If set, history is persisted here across restarts
 }
 
 // MemoryEventBus is an in-memory event bus implementation.
 type MemoryEventBus struct {
 	mu              sync.RWMutex
 	subscriptions   map[SubscriptionID]*subscription
-	history         *EventHistory
+	history         HistoryStore
 	matcher         *PatternMatcher
 	closed          atomic.Bool
 	wg              sync.WaitGroup
@@ -53,12 +54,28 @@ type subscription struct {
 func NewMemoryEventBus(cfg MemoryBusConfig) *MemoryEventBus {
 	bus := &MemoryEventBus{
 		subscriptions: make(map[SubscriptionID]*subscription),
-		history: NewEventHistory(EventHistoryConfig{
+		matcher:       NewPatternMatcher(),
+		stopPruner:    make(chan struct{}),
+	}
+
+	// Persist history to disk when configured, falling back to memory
+	if cfg.HistoryDir != "" {
+		fh, err := NewFileHistory(FileHistoryConfig{
+			Dir:       cfg.HistoryDir,
 			MaxEvents: cfg.HistoryMaxEvents,
 			MaxAge:    cfg.HistoryMaxAge,
-		}),
-		matcher:    NewPatternMatcher(),
-		stopPruner: make(chan struct{}),
+		})
+		if err != nil {
+			log.Printf("EventBus: persistent history unavailable, using memory: %v", err)
+		} else {
+			bus.history = fh
+		}
+	}
+	if bus.history == nil {
+		bus.history = NewEventHistory(EventHistoryConfig{
+			MaxEvents: cfg.HistoryMaxEvents,
+			MaxAge:    cfg.HistoryMaxAge,
+		})
 	}
 
 	// Start background pruner to enforce max_age
@@ -118,7 +135,9 @@ func (bus *MemoryEventBus) Publish(ctx context.Context, event Event) error {
 	}
 
 	// Store in history
-	bus.history.Add(event)
+	if err := bus.history.Add(event); err != nil {
+		log.Printf("EventBus: failed to record %s in history: %v", event.Type, err)
+	}
 
 	// Notify subscribers
 	bus.mu.RLock()
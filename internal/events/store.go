@@ -0,0 +1,369 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFileHistoryMaxEvents = 100000
+	defaultFileHistoryMaxAge    = 7 * 24 * time.Hour
+
+	segmentPrefix     = "events-"
+	segmentSuffix     = ".jsonl"
+	segmentDayFormat  = "20060102"
+	maxEventLineBytes = 16 << 20
+)
+
+// FileHistoryConfig configures persistent event history.
+type FileHistoryConfig struct {
+	Dir       string        // Directory holding segment files
+	MaxEvents int           // Maximum events retained across all segments
+	MaxAge    time.Duration // Maximum age of retained events
+}
+
+// FileHistory is an append-only event store on disk. Events are written as
+// JSON lines to one segment file per UTC day (events-YYYYMMDD.jsonl), so
+// history survives restarts and retention can drop whole files.
+type FileHistory struct {
+	mu        sync.Mutex
+	dir       string
+	maxEvents int
+	maxAge    time.Duration
+	matcher   *PatternMatcher
+	segments  []*segment // Oldest first
+	current   *os.File   // Append handle for the newest segment (opened lazily)
+}
+
+// segment is one day's segment file.
+type segment struct {
+	name  string
+	day   time.Time
+	count int
+}
+
+// NewFileHistory opens (or creates) a persistent event history in cfg.Dir.
+func NewFileHistory(cfg FileHistoryConfig) (*FileHistory, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("event history directory is required")
+	}
+	if cfg.MaxEvents <= 0 {
+		cfg.MaxEvents = defaultFileHistoryMaxEvents
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = defaultFileHistoryMaxAge
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("create event history directory: %w", err)
+	}
+
+	h := &FileHistory{
+		dir:       cfg.Dir,
+		maxEvents: cfg.MaxEvents,
+		maxAge:    cfg.MaxAge,
+		matcher:   NewPatternMatcher(),
+	}
+	if err := h.loadSegments(); err != nil {
+		return nil, err
+	}
+	if err := h.Prune(); err != nil {
+		log.Printf("EventHistory: initial prune failed: %v", err)
+	}
+	return h, nil
+}
+
+// loadSegments scans the directory for existing segment files.
+func (h *FileHistory) loadSegments() error {
+	entries, err := os.ReadDir(h.dir)
+	if err != nil {
+		return fmt.Errorf("read event history directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		day, err := time.Parse(segmentDayFormat, strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix))
+		if err != nil {
+			continue
+		}
+		count, err := countLines(filepath.Join(h.dir, name))
+		if err != nil {
+			return fmt.Errorf("read segment %s: %w", name, err)
+		}
+		h.segments = append(h.segments, &segment{name: name, day: day, count: count})
+	}
+	sort.Slice(h.segments, func(i, j int) bool {
+		return h.segments[i].day.Before(h.segments[j].day)
+	})
+	return nil
+}
+
+// Add appends an event to the current day's segment.
+func (h *FileHistory) Add(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	day := segmentDay(event.Timestamp)
+	var seg *segment
+	if n := len(h.segments); n > 0 {
+		seg = h.segments[n-1]
+	}
+	// Events are appended in arrival order; an event stamped earlier than the
+	// newest segment (clock skew) still goes into the newest segment.
+	if seg == nil || day.After(seg.day) {
+		h.closeCurrent()
+		seg = &segment{name: segmentPrefix + day.Format(segmentDayFormat) + segmentSuffix, day: day}
+		h.segments = append(h.segments, seg)
+	}
+
+	if h.current == nil {
+		f, err := os.OpenFile(filepath.Join(h.dir, seg.name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		h.current = f
+	}
+	if _, err := h.current.Write(line); err != nil {
+		return err
+	}
+	seg.count++
+	return nil
+}
+
+// Query retrieves events matching filter, oldest first. Segments are read
+// newest first so a limited query only touches the most recent files.
+func (h *FileHistory) Query(filter EventFilter) ([]Event, error) {
+	h.mu.Lock()
+	segments := make([]*segment, len(h.segments))
+	copy(segments, h.segments)
+	h.mu.Unlock()
+
+	search := newSearchTerms(filter.Search)
+	seekingCursor := filter.Before != ""
+	result := make([]Event, 0)
+
+scan:
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if !filter.Since.IsZero() && seg.day.Add(24*time.Hour).Before(filter.Since) && !seekingCursor {
+			break
+		}
+		if !filter.Until.IsZero() && seg.day.After(filter.Until) && !seekingCursor {
+			continue
+		}
+
+		events, err := h.readSegment(seg)
+		if err != nil {
+			return nil, err
+		}
+		for j := len(events) - 1; j >= 0; j-- {
+			event := events[j]
+			if seekingCursor {
+				if event.ID == filter.Before {
+					seekingCursor = false
+				}
+				continue
+			}
+			if !matchesFilter(h.matcher, event, filter) || !search.match(event) {
+				continue
+			}
+			result = append(result, event)
+			if filter.Limit > 0 && len(result) >= filter.Limit {
+				break scan
+			}
+		}
+	}
+
+	// Reverse to oldest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// Prune drops events older than max age and trims the oldest events once
+// the store exceeds max events. Whole segments are deleted where possible;
+// the oldest surviving segment is rewritten if it is only partially expired.
+func (h *FileHistory) Prune() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := time.Now().Add(-h.maxAge)
+
+	// Drop segments that ended before the cutoff
+	for len(h.segments) > 0 && h.segments[0].day.Add(24*time.Hour).Before(cutoff) {
+		if err := h.removeSegment(0); err != nil {
+			return err
+		}
+	}
+
+	// Drop whole segments beyond max events
+	total := 0
+	for _, seg := range h.segments {
+		total += seg.count
+	}
+	for len(h.segments) > 1 && total-h.segments[0].count >= h.maxEvents {
+		total -= h.segments[0].count
+		if err := h.removeSegment(0); err != nil {
+			return err
+		}
+	}
+
+	if len(h.segments) == 0 {
+		return nil
+	}
+
+	// Compact the oldest segment if it holds expired or excess events
+	oldest := h.segments[0]
+	excess := total - h.maxEvents
+	if excess > 0 || oldest.day.Before(cutoff) {
+		return h.compactSegment(oldest, excess, cutoff)
+	}
+	return nil
+}
+
+// compactSegment rewrites seg without its first skip events and without
+// events older than cutoff.
+func (h *FileHistory) compactSegment(seg *segment, skip int, cutoff time.Time) error {
+	events, err := h.readSegment(seg)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	kept := 0
+	for i, event := range events {
+		if i < skip || event.Timestamp.Before(cutoff) {
+			continue
+		}
+		line, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+		kept++
+	}
+	if kept == len(events) && kept == seg.count {
+		return nil
+	}
+
+	if seg == h.segments[len(h.segments)-1] {
+		h.closeCurrent()
+	}
+	path := filepath.Join(h.dir, seg.name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	seg.count = kept
+	return nil
+}
+
+// removeSegment deletes the segment at index i.
+func (h *FileHistory) removeSegment(i int) error {
+	if i == len(h.segments)-1 {
+		h.closeCurrent()
+	}
+	if err := os.Remove(filepath.Join(h.dir, h.segments[i].name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	h.segments = append(h.segments[:i], h.segments[i+1:]...)
+	return nil
+}
+
+// readSegment decodes every event in a segment. Lines that fail to decode
+// (e.g. a partial write from a crash) are skipped.
+func (h *FileHistory) readSegment(seg *segment) ([]Event, error) {
+	f, err := os.Open(filepath.Join(h.dir, seg.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxEventLineBytes)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// closeCurrent closes the append handle; the next Add reopens it.
+func (h *FileHistory) closeCurrent() {
+	if h.current != nil {
+		h.current.Close()
+		h.current = nil
+	}
+}
+
+// Close releases the append handle.
+func (h *FileHistory) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closeCurrent()
+	return nil
+}
+
+// segmentDay returns the UTC day a timestamp belongs to.
+func segmentDay(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// countLines counts newline-terminated lines in a file.
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	count := 0
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := f.Read(buf)
+		count += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
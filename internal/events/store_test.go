@@ -0,0 +1,213 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFileHistory(t *testing.T, dir string, maxEvents int, maxAge time.Duration) *FileHistory {
+	t.Helper()
+	h, err := NewFileHistory(FileHistoryConfig{Dir: dir, MaxEvents: maxEvents, MaxAge: maxAge})
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func TestFileHistory_PersistsAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	h := newTestFileHistory(t, dir, 0, 0)
+	now := time.Now()
+	require.NoError(t, h.Add(Event{ID: "1", Type: "service.crashed", Worktree: "main", Timestamp: now.Add(-time.Minute), Payload: map[string]interface{}{"service": "backend"}}))
+	require.NoError(t, h.Add(Event{ID: "2", Type: "workflow.finished", Worktree: "feature", Timestamp: now}))
+	require.NoError(t, h.Close())
+
+	reopened := newTestFileHistory(t, dir, 0, 0)
+	events, err := reopened.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "1", events[0].ID)
+	assert.Equal(t, "backend", events[0].Payload["service"])
+	assert.Equal(t, "2", events[1].ID)
+
+	// New events append after the existing ones
+	require.NoError(t, reopened.Add(Event{ID: "3", Type: "service.started", Timestamp: now.Add(time.Second)}))
+	events, err = reopened.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "3", events[2].ID)
+}
+
+func TestFileHistory_QueryFilters(t *testing.T) {
+	h := newTestFileHistory(t, t.TempDir(), 0, 0)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		typ := "service.started"
+		if i%2 == 0 {
+			typ = "service.crashed"
+		}
+		require.NoError(t, h.Add(Event{
+			ID:        fmt.Sprint(i),
+			Type:      typ,
+			Worktree:  "main",
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Payload:   map[string]interface{}{"service": fmt.Sprintf("svc%d", i)},
+		}))
+	}
+
+	// Type filter with limit returns the newest matches, oldest first
+	events, err := h.Query(EventFilter{Types: []string{"service.crashed"}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "6", events[0].ID)
+	assert.Equal(t, "8", events[1].ID)
+
+	// Time window
+	events, err = h.Query(EventFilter{Since: base.Add(3 * time.Minute), Until: base.Add(5 * time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	// Full-text search over payload
+	events, err = h.Query(EventFilter{Search: "SVC7"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "7", events[0].ID)
+
+	// All terms must match
+	events, err = h.Query(EventFilter{Search: "crashed svc4"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "4", events[0].ID)
+}
+
+func TestFileHistory_CursorPagination(t *testing.T) {
+	h := newTestFileHistory(t, t.TempDir(), 0, 0)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		require.NoError(t, h.Add(Event{ID: fmt.Sprint(i), Type: "service.started", Timestamp: base.Add(time.Duration(i) * time.Second)}))
+	}
+
+	var pages [][]string
+	cursor := ""
+	for {
+		events, err := h.Query(EventFilter{Limit: 3, Before: cursor})
+		require.NoError(t, err)
+		if len(events) == 0 {
+			break
+		}
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		pages = append(pages, ids)
+		cursor = events[0].ID
+	}
+
+	assert.Equal(t, [][]string{{"4", "5", "6"}, {"1", "2", "3"}, {"0"}}, pages)
+
+	// Unknown cursor yields nothing
+	events, err := h.Query(EventFilter{Before: "missing"})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestFileHistory_SegmentsByDay(t *testing.T) {
+	dir := t.TempDir()
+	h := newTestFileHistory(t, dir, 0, 30*24*time.Hour)
+
+	now := time.Now()
+	require.NoError(t, h.Add(Event{ID: "old", Type: "service.crashed", Timestamp: now.Add(-48 * time.Hour)}))
+	require.NoError(t, h.Add(Event{ID: "new", Type: "service.crashed", Timestamp: now}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "events-*.jsonl"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Since skips the older segment
+	events, err := h.Query(EventFilter{Since: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "new", events[0].ID)
+}
+
+func TestFileHistory_PruneMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	h := newTestFileHistory(t, dir, 0, 24*time.Hour)
+
+	now := time.Now()
+	require.NoError(t, h.Add(Event{ID: "ancient", Type: "service.crashed", Timestamp: now.Add(-10 * 24 * time.Hour)}))
+	require.NoError(t, h.Add(Event{ID: "stale", Type: "service.crashed", Timestamp: now.Add(-25 * time.Hour)}))
+	require.NoError(t, h.Add(Event{ID: "fresh", Type: "service.crashed", Timestamp: now}))
+
+	require.NoError(t, h.Prune())
+
+	events, err := h.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "fresh", events[0].ID)
+
+	_, err = os.Stat(filepath.Join(dir, "events-"+now.Add(-10*24*time.Hour).UTC().Format("20060102")+".jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileHistory_PruneMaxEvents(t *testing.T) {
+	dir := t.TempDir()
+	h := newTestFileHistory(t, dir, 5, 0)
+
+	now := time.Now()
+	for i := 0; i < 8; i++ {
+		require.NoError(t, h.Add(Event{ID: fmt.Sprint(i), Type: "service.started", Timestamp: now.Add(time.Duration(i) * time.Millisecond)}))
+	}
+	require.NoError(t, h.Prune())
+
+	events, err := h.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	assert.Equal(t, "3", events[0].ID)
+
+	// Appends continue after compaction of the current segment
+	require.NoError(t, h.Add(Event{ID: "8", Type: "service.started", Timestamp: now.Add(time.Second)}))
+	events, err = h.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 6)
+	assert.Equal(t, "8", events[5].ID)
+}
+
+func TestFileHistory_SkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	name := "events-" + time.Now().UTC().Format("20060102") + ".jsonl"
+	content := `{"id":"1","type":"service.started","timestamp":"` + time.Now().Format(time.RFC3339Nano) + `"}` + "\n" + `{"id":"2","type":"serv`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+
+	h := newTestFileHistory(t, dir, 0, 0)
+	events, err := h.Query(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "1", events[0].ID)
+}
+
+func TestMemoryEventBus_PersistentHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	bus := NewMemoryEventBus(MemoryBusConfig{HistoryDir: dir})
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "service.crashed", Worktree: "main", Payload: map[string]interface{}{"service": "backend"}}))
+	require.NoError(t, bus.Close())
+
+	bus = NewMemoryEventBus(MemoryBusConfig{HistoryDir: dir})
+	defer bus.Close()
+
+	events, err := bus.History(EventFilter{Types: []string{"service.crashed"}, Search: "backend"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "main", events[0].Worktree)
+}
@@ -31,6 +31,8 @@ type EventFilter struct {
 	Worktree string    // Filter by worktree
 	Since    time.Time // Events after this time
 	Until    time.Time // Events before this time
+	Search   string    // Case-insensitive terms that must all appear in type, worktree, or payload
+	Before   string    // Cursor: only events older than the event with this ID
 	Limit    int       // Maximum events to return
 }
 
@@ -65,6 +67,8 @@ const (
 	EventServiceStopped   = "service.stopped"
 	EventServiceCrashed   = "service.crashed"
 	EventServiceRestarted = "service.restarted"
+	EventServiceReady     = "service.ready"     // Readiness check passed
+	EventServiceUnhealthy = "service.unhealthy" // Readiness or liveness check failed
 
 	// Worktree events
 	EventWorktreeDeactivating = "worktree.deactivating"
@@ -81,11 +85,21 @@ const (
 	// Binary events
 	EventBinaryChanged = "binary.changed"
 
+	// Config file reload events. config.reloaded carries {path, changes,
+	// diff, applied, restart_required}; config.reload_failed carries {path,
+	// error} and means the running config was left untouched.
+	EventConfigReloaded     = "config.reloaded"
+	EventConfigReloadFailed = "config.reload_failed"
+
 	// Trace events
 	EventTraceStarted   = "trace.started"
 	EventTraceCompleted = "trace.completed"
 	EventTraceFailed    = "trace.failed"
 
+	// Log alert events. Carries {rule, state, severity, count, ...} where
+	// state is AlertStateFiring or AlertStateResolved.
+	EventLogAlert = "log.alert"
+
 	// Claude session events
 	EventClaudeSessionMoved = "claude.session.moved"
 
@@ -118,6 +132,12 @@ const (
 	SessionStateNeedsYou = "needs_you"
 )
 
+// Log alert state values used as the `state` payload field on EventLogAlert.
+const (
+	AlertStateFiring   = "firing"
+	AlertStateResolved = "resolved"
+)
+
 // Session inbox reason values used as the `reason` payload field on
 // EventSessionStateChanged. `reason` refines `state` for presentation only
 // (icon, urgency sort) and never affects ordering or transition detection.
@@ -0,0 +1,265 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookAttempts    = 3
+	defaultWebhookBackoff     = time.Second
+	defaultWebhookBufferSize  = 256
+	defaultWebhookMaxDelivery = 200
+)
+
+// Webhook is an outbound HTTP endpoint that receives matching events.
+type Webhook struct {
+	ID          string
+	URL         string
+	Events      []string      // Patterns to match; empty matches all events
+	Secret      string        // HMAC-SHA256 signing secret (optional)
+	Timeout     time.Duration // Per-attempt request timeout
+	MaxAttempts int           // Total delivery attempts including the first
+}
+
+// WebhookDelivery records the outcome of delivering one event to one webhook.
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	URL        string    `json:"url"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Timestamp  time.Time `json:"timestamp"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// WebhookDispatcher POSTs events from the bus to configured webhooks.
+type WebhookDispatcher struct {
+	bus     EventBus
+	hooks   []Webhook
+	client  *http.Client
+	matcher *PatternMatcher
+	backoff time.Duration // Base delay between attempts, doubled on each retry
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	subs   []SubscriptionID
+
+	mu            sync.Mutex
+	deliveries    []WebhookDelivery // Most recent last
+	maxDeliveries int
+	nextID        uint64
+}
+
+// NewWebhookDispatcher creates a dispatcher for the given webhooks.
+// Call Start to begin delivering events.
+func NewWebhookDispatcher(bus EventBus, hooks []Webhook) *WebhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookDispatcher{
+		bus:           bus,
+		hooks:         hooks,
+		client:        &http.Client{},
+		matcher:       NewPatternMatcher(),
+		backoff:       defaultWebhookBackoff,
+		ctx:           ctx,
+		cancel:        cancel,
+		maxDeliveries: defaultWebhookMaxDelivery,
+	}
+}
+
+// Start subscribes each webhook to the event bus. Each webhook gets its own
+// async subscription so a slow or failing endpoint doesn't delay the others.
+func (d *WebhookDispatcher) Start() error {
+	for i := range d.hooks {
+		hook := d.hooks[i]
+		if hook.Timeout <= 0 {
+			hook.Timeout = defaultWebhookTimeout
+		}
+		if hook.MaxAttempts <= 0 {
+			hook.MaxAttempts = defaultWebhookAttempts
+		}
+		id, err := d.bus.SubscribeAsync("*", func(ctx context.Context, event Event) error {
+			if !d.matches(hook, event.Type) {
+				return nil
+			}
+			d.deliver(hook, event)
+			return nil
+		}, defaultWebhookBufferSize)
+		if err != nil {
+			return fmt.Errorf("subscribe webhook %s: %w", hook.ID, err)
+		}
+		d.subs = append(d.subs, id)
+	}
+	return nil
+}
+
+// Close cancels in-flight deliveries and unsubscribes from the bus.
+func (d *WebhookDispatcher) Close() {
+	d.cancel()
+	for _, id := range d.subs {
+		d.bus.Unsubscribe(id)
+	}
+	d.subs = nil
+}
+
+// Deliveries returns the most recent deliveries, newest first.
+// A limit of 0 returns all retained deliveries.
+func (d *WebhookDispatcher) Deliveries(limit int) []WebhookDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := len(d.deliveries)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	result := make([]WebhookDelivery, 0, n)
+	for i := len(d.deliveries) - 1; i >= 0 && len(result) < n; i-- {
+		result = append(result, d.deliveries[i])
+	}
+	return result
+}
+
+// matches reports whether the event type matches any of the hook's patterns.
+func (d *WebhookDispatcher) matches(hook Webhook, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, pattern := range hook.Events {
+		if d.matcher.Match(eventType, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver POSTs the event to the hook, retrying with exponential backoff on
+// network errors, 5xx, and 429 responses. Other 4xx responses are not retried.
+func (d *WebhookDispatcher) deliver(hook Webhook, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Webhook %s: failed to encode event %s: %v", hook.ID, event.ID, err)
+		return
+	}
+
+	delivery := WebhookDelivery{
+		ID:        d.generateID(),
+		WebhookID: hook.ID,
+		URL:       hook.URL,
+		EventID:   event.ID,
+		EventType: event.Type,
+		Timestamp: time.Now(),
+	}
+
+	delay := d.backoff
+	for attempt := 1; attempt <= hook.MaxAttempts; attempt++ {
+		delivery.Attempts = attempt
+		status, err := d.post(hook, delivery.ID, event.Type, body)
+		delivery.StatusCode = status
+		if err == nil {
+			delivery.Success = true
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+		if !retryable(status) || attempt == hook.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(delay):
+			delay *= 2
+		case <-d.ctx.Done():
+			delivery.Error = "cancelled: " + delivery.Error
+			delivery.DurationMs = time.Since(delivery.Timestamp).Milliseconds()
+			d.record(delivery)
+			return
+		}
+	}
+
+	delivery.DurationMs = time.Since(delivery.Timestamp).Milliseconds()
+	if !delivery.Success {
+		log.Printf("Webhook %s: delivery of %s failed after %d attempt(s): %s", hook.ID, event.Type, delivery.Attempts, delivery.Error)
+	}
+	d.record(delivery)
+}
+
+// post performs a single delivery attempt, returning the HTTP status code
+// (0 if no response was received) and an error if the attempt failed.
+func (d *WebhookDispatcher) post(hook Webhook, deliveryID, eventType string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, hook.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Trellis-Webhook/1.0")
+	req.Header.Set("X-Trellis-Event", eventType)
+	req.Header.Set("X-Trellis-Delivery", deliveryID)
+	req.Header.Set("X-Trellis-Webhook", hook.ID)
+	if hook.Secret != "" {
+		req.Header.Set("X-Trellis-Signature", "sha256="+SignWebhookPayload(hook.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// record appends a delivery to the bounded delivery log.
+func (d *WebhookDispatcher) record(delivery WebhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deliveries = append(d.deliveries, delivery)
+	if len(d.deliveries) > d.maxDeliveries {
+		d.deliveries = d.deliveries[len(d.deliveries)-d.maxDeliveries:]
+	}
+}
+
+func (d *WebhookDispatcher) generateID() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextID++
+	return fmt.Sprintf("whd-%d-%d", time.Now().UnixNano(), d.nextID)
+}
+
+// retryable reports whether a failed attempt with the given status should be
+// retried. A zero status means the request never got a response.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
+
+// SignWebhookPayload returns the hex-encoded HMAC-SHA256 of body keyed by
+// secret, as sent in the X-Trellis-Signature header.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
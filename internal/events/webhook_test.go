@@ -0,0 +1,209 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForDeliveries(t *testing.T, d *WebhookDispatcher, n int) []WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if got := d.Deliveries(0); len(got) >= n {
+			return got
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d deliveries, have %d", n, len(d.Deliveries(0)))
+	return nil
+}
+
+func TestWebhookDispatcher_DeliversMatchingEvents(t *testing.T) {
+	received := make(chan Event, 10)
+	headers := make(chan http.Header, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		received <- e
+		headers <- r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, []Webhook{{
+		ID:     "chat",
+		URL:    srv.URL,
+		Events: []string{"service.crashed", "workflow.*"},
+	}})
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	ctx := context.Background()
+	require.NoError(t, bus.Publish(ctx, Event{Type: "service.started"}))
+	require.NoError(t, bus.Publish(ctx, Event{Type: "service.crashed", Payload: map[string]interface{}{"service": "api"}}))
+	require.NoError(t, bus.Publish(ctx, Event{Type: "workflow.finished"}))
+
+	deliveries := waitForDeliveries(t, d, 2)
+	assert.Len(t, deliveries, 2)
+
+	e := <-received
+	assert.Equal(t, "service.crashed", e.Type)
+	assert.Equal(t, "api", e.Payload["service"])
+	h := <-headers
+	assert.Equal(t, "service.crashed", h.Get("X-Trellis-Event"))
+	assert.Equal(t, "chat", h.Get("X-Trellis-Webhook"))
+	assert.NotEmpty(t, h.Get("X-Trellis-Delivery"))
+	assert.Empty(t, h.Get("X-Trellis-Signature"))
+
+	e = <-received
+	assert.Equal(t, "workflow.finished", e.Type)
+
+	// Newest first
+	assert.Equal(t, "workflow.finished", deliveries[0].EventType)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].StatusCode)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+}
+
+func TestWebhookDispatcher_SignsPayload(t *testing.T) {
+	type request struct {
+		body      []byte
+		signature string
+	}
+	received := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- request{body: body, signature: r.Header.Get("X-Trellis-Signature")}
+	}))
+	defer srv.Close()
+
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, []Webhook{{ID: "signed", URL: srv.URL, Secret: "s3cret"}})
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "service.crashed"}))
+
+	select {
+	case req := <-received:
+		assert.Equal(t, "sha256="+SignWebhookPayload("s3cret", req.body), req.signature)
+		assert.NotEqual(t, "sha256="+SignWebhookPayload("wrong", req.body), req.signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+}
+
+func TestWebhookDispatcher_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, []Webhook{{ID: "flaky", URL: srv.URL, MaxAttempts: 5}})
+	d.backoff = time.Millisecond
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "workflow.finished"}))
+
+	deliveries := waitForDeliveries(t, d, 1)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestWebhookDispatcher_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, []Webhook{{ID: "bad", URL: srv.URL, MaxAttempts: 5}})
+	d.backoff = time.Millisecond
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "service.crashed"}))
+
+	deliveries := waitForDeliveries(t, d, 1)
+	assert.False(t, deliveries[0].Success)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusBadRequest, deliveries[0].StatusCode)
+	assert.Contains(t, deliveries[0].Error, "400")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookDispatcher_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, []Webhook{{ID: "slow", URL: srv.URL, Timeout: 50 * time.Millisecond, MaxAttempts: 2}})
+	d.backoff = time.Millisecond
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "service.crashed"}))
+
+	deliveries := waitForDeliveries(t, d, 1)
+	assert.False(t, deliveries[0].Success)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Equal(t, 0, deliveries[0].StatusCode)
+}
+
+func TestWebhookDispatcher_DeliveryLogBounded(t *testing.T) {
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+
+	d := NewWebhookDispatcher(bus, nil)
+	d.maxDeliveries = 3
+	for i := 0; i < 5; i++ {
+		d.record(WebhookDelivery{EventID: string(rune('a' + i))})
+	}
+
+	all := d.Deliveries(0)
+	require.Len(t, all, 3)
+	assert.Equal(t, "e", all[0].EventID)
+	assert.Equal(t, "c", all[2].EventID)
+
+	assert.Len(t, d.Deliveries(2), 2)
+}
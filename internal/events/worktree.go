@@ -0,0 +1,35 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import "context"
+
+// WithWorktree returns a bus that publishes to bus, labelling events that
+// don't name a worktree with worktree rather than the bus's default. It
+// lets components that run for a worktree other than the active one, such
+// as its services, share the bus.
+func WithWorktree(bus EventBus, worktree string) EventBus {
+	return &worktreeBus{EventBus: bus, worktree: worktree}
+}
+
+type worktreeBus struct {
+	EventBus
+	worktree string
+}
+
+func (b *worktreeBus) Publish(ctx context.Context, event Event) error {
+	if event.Worktree == "" {
+		event.Worktree = b.worktree
+	}
+	return b.EventBus.Publish(ctx, event)
+}
+
+// SetDefaultWorktree does nothing: the worktree is fixed, and the shared
+// bus's default belongs to the active worktree.
+func (b *worktreeBus) SetDefaultWorktree(string) {}
+
+// Close does nothing; the shared bus is closed by its owner.
+func (b *worktreeBus) Close() error {
+	return nil
+}
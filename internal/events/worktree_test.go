@@ -0,0 +1,36 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithWorktree(t *testing.T) {
+	bus := NewMemoryEventBus(MemoryBusConfig{})
+	defer bus.Close()
+	bus.SetDefaultWorktree("main")
+
+	var got []string
+	_, err := bus.Subscribe("*", func(ctx context.Context, e Event) error {
+		got = append(got, e.Worktree)
+		return nil
+	})
+	require.NoError(t, err)
+
+	feature := WithWorktree(bus, "feature")
+	feature.SetDefaultWorktree("other")
+	require.NoError(t, feature.Publish(context.Background(), Event{Type: "service.started"}))
+	require.NoError(t, feature.Publish(context.Background(), Event{Type: "service.started", Worktree: "named"}))
+	require.NoError(t, bus.Publish(context.Background(), Event{Type: "service.started"}))
+	assert.Equal(t, []string{"feature", "named", "main"}, got)
+
+	// Closing the wrapper leaves the shared bus open
+	require.NoError(t, feature.Close())
+	assert.NoError(t, bus.Publish(context.Background(), Event{Type: "service.started"}))
+}
@@ -4,6 +4,7 @@
 package logs
 
 import (
+	"log"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,10 +14,11 @@ import (
 type Buffer struct {
 	mu       sync.RWMutex
 	entries  []LogEntry
-	head     int    // Next write position
-	size     int    // Current number of entries
-	maxSize  int    // Maximum capacity
-	sequence uint64 // Monotonically increasing sequence number
+	head     int         // Next write position
+	size     int         // Current number of entries
+	maxSize  int         // Maximum capacity
+	sequence uint64      // Monotonically increasing sequence number
+	store    *EntryStore // Optional on-disk spill (write-through), nil if not persisted
 }
 
 // NewBuffer creates a new log entry buffer.
@@ -30,6 +32,44 @@ func NewBuffer(maxSize int) *Buffer {
 	}
 }
 
+// AttachStore makes the buffer persistent: the newest stored entries are
+// loaded into the ring, the sequence counter resumes after the last stored
+// entry, and every entry added from now on is also appended to the store.
+// Reads that reach past the in-memory window fall through to the store.
+func (b *Buffer) AttachStore(store *EntryStore) error {
+	recent, err := store.Recent(b.maxSize)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries = make([]LogEntry, b.maxSize)
+	b.head = 0
+	b.size = 0
+	for _, entry := range recent {
+		b.entries[b.head] = entry
+		b.head = (b.head + 1) % b.maxSize
+		b.size++
+	}
+	if last := store.LastSequence(); last > atomic.LoadUint64(&b.sequence) {
+		atomic.StoreUint64(&b.sequence, last)
+	}
+	b.store = store
+	return nil
+}
+
+// persist appends entries to the attached store, if any. Caller must hold b.mu.
+func (b *Buffer) persist(entries ...LogEntry) {
+	if b.store == nil {
+		return
+	}
+	if err := b.store.Append(entries...); err != nil {
+		log.Printf("logs: failed to persist %d entries: %v", len(entries), err)
+	}
+}
+
 // Add adds an entry to the buffer and returns the stored entry with its
 // assigned sequence number. Callers that rebroadcast the entry must use the
 // returned copy — the parameter is passed by value, so the sequence
@@ -49,6 +89,7 @@ func (b *Buffer) Add(entry LogEntry) LogEntry {
 		b.size++
 	}
 
+	b.persist(entry)
 	return entry
 }
 
@@ -69,6 +110,8 @@ func (b *Buffer) AddBatch(entries []LogEntry) {
 			b.size++
 		}
 	}
+
+	b.persist(entries...)
 }
 
 // Get returns entries from the buffer.
@@ -223,13 +266,39 @@ func (b *Buffer) GetLastAfter(afterSeq uint64, limit int) ([]LogEntry, int) {
 }
 
 // GetBefore returns entries before the given sequence number (for scrollback).
-// Returns entries in chronological order (oldest first).
+// Returns entries in chronological order (oldest first). When the buffer is
+// persisted and the in-memory window runs out, older entries come from disk.
 func (b *Buffer) GetBefore(beforeSeq uint64, limit int) []LogEntry {
+	result, oldestSeq, store := b.getBeforeMemory(beforeSeq, limit)
+	if store == nil || (limit > 0 && len(result) >= limit) {
+		return result
+	}
+
+	// Continue below whichever is lower: the cursor or the oldest entry in memory
+	diskBefore := beforeSeq
+	if oldestSeq > 0 && oldestSeq < diskBefore {
+		diskBefore = oldestSeq
+	}
+	remaining := 0
+	if limit > 0 {
+		remaining = limit - len(result)
+	}
+	older, err := store.Before(diskBefore, remaining)
+	if err != nil {
+		log.Printf("logs: failed to read persisted entries: %v", err)
+		return result
+	}
+	return append(older, result...)
+}
+
+// getBeforeMemory returns in-memory entries before beforeSeq, the sequence
+// of the oldest in-memory entry (0 if empty), and the attached store.
+func (b *Buffer) getBeforeMemory(beforeSeq uint64, limit int) ([]LogEntry, uint64, *EntryStore) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
 	if b.size == 0 {
-		return nil
+		return nil, 0, b.store
 	}
 
 	// Collect entries with sequence < beforeSeq, in reverse order (newest first)
@@ -259,7 +328,7 @@ func (b *Buffer) GetBefore(beforeSeq uint64, limit int) []LogEntry {
 		result[len(reversed)-1-i] = entry
 	}
 
-	return result
+	return result, b.entries[start].Sequence, b.store
 }
 
 // GetBeforeTime returns entries before the given timestamp.
@@ -296,9 +365,20 @@ func (b *Buffer) GetBeforeTime(before time.Time, limit int) []LogEntry {
 	return result
 }
 
-// GetRange returns entries in the given time range.
+// GetRange returns entries in the given time range. When the buffer is
+// persisted and the range starts before the in-memory window, the whole
+// range is served from disk (the store holds everything the ring does).
 func (b *Buffer) GetRange(start, end time.Time, limit int) []LogEntry {
 	b.mu.RLock()
+	store := b.store
+	if store != nil && (b.size == 0 || start.Before(b.oldestTimestampLocked())) {
+		b.mu.RUnlock()
+		entries, err := store.Range(start, end, limit)
+		if err != nil {
+			log.Printf("logs: failed to read persisted entries: %v", err)
+		}
+		return entries
+	}
 	defer b.mu.RUnlock()
 
 	if b.size == 0 {
@@ -366,7 +446,11 @@ func (b *Buffer) Clear() {
 func (b *Buffer) OldestTimestamp() time.Time {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
+	return b.oldestTimestampLocked()
+}
 
+// oldestTimestampLocked returns the oldest in-memory timestamp. Caller must hold b.mu.
+func (b *Buffer) oldestTimestampLocked() time.Time {
 	if b.size == 0 {
 		return time.Time{}
 	}
@@ -13,17 +13,38 @@ import (
 
 // Filter represents a parsed filter query.
 type Filter struct {
-	clauses []filterClause
+	root filterNode // nil matches everything
+}
+
+// filterNode is a node in a parsed filter expression.
+type filterNode interface {
+	match(entry LogEntry) bool
+	String() string
+}
+
+// andNode matches when all children match.
+type andNode struct {
+	children []filterNode
+}
+
+// orNode matches when any child matches.
+type orNode struct {
+	children []filterNode
+}
+
+// notNode inverts its child.
+type notNode struct {
+	child filterNode
 }
 
 // filterClause represents a single filter condition.
 type filterClause struct {
-	field    string       // Field name (empty for full-text search)
-	op       filterOp     // Comparison operator
-	values   []string     // Values to match (multiple for OR)
-	negate   bool         // Negate the match
-	contains bool         // Contains match (vs exact)
-	regex    *regexp.Regexp // Compiled regex for contains
+	field    string         // Field name (empty for full-text search)
+	op       filterOp       // Comparison operator
+	values   []string       // Values to match (multiple for OR)
+	negate   bool           // Negate the match
+	contains bool           // Contains match (vs exact)
+	regex    *regexp.Regexp // Compiled pattern for field:/re/
 }
 
 // filterOp represents a comparison operator.
@@ -36,74 +57,330 @@ const (
 	opGreaterOrEqual
 	opLess
 	opLessOrEqual
+	opRegex
+	opExists
 )
 
+// FilterSyntaxError reports a malformed filter query. Pos is the 1-based
+// column in the query where the problem was found.
+type FilterSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Pos, e.Msg)
+}
+
 // ParseFilter parses a filter query string.
 // Supported syntax:
 //   - field:value       - Exact match
 //   - field:val1,val2   - OR match
+//   - field:in(a,"b c") - OR match with quoted values
 //   - field:~"text"     - Contains
+//   - field:/regex/     - Regular expression match (bare /regex/ matches the message)
 //   - field:>"value"    - Greater than
 //   - field:<"value"    - Less than
 //   - field:>="value"   - Greater or equal
 //   - field:<="value"   - Less or equal
+//   - has:field         - Field is present
+//   - missing:field     - Field is absent
 //   - -field:value      - Exclude
 //   - "quoted text"     - Message contains
-//   - term1 term2       - AND (space-separated)
+//   - term1 term2       - AND (space-separated, or explicit AND)
+//   - a OR b            - Either matches (binds looser than AND)
+//   - NOT a, -(a OR b)  - Negation
+//   - (a OR b) c        - Grouping
 func ParseFilter(query string) (*Filter, error) {
-	if query == "" {
+	if strings.TrimSpace(query) == "" {
 		return &Filter{}, nil
 	}
 
-	var clauses []filterClause
-	remaining := strings.TrimSpace(query)
+	tokens, err := lexFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{query: query, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok.describe())
+	}
+
+	return &Filter{root: root}, nil
+}
+
+// filterTokenKind identifies a lexical token in a filter query.
+type filterTokenKind int
+
+const (
+	tokEOF filterTokenKind = iota
+	tokTerm
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+// filterToken is a lexical token and its 0-based byte offset in the query.
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+func (t filterToken) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokTerm:
+		return fmt.Sprintf("%q", t.text)
+	case tokLParen:
+		return `"("`
+	case tokRParen:
+		return `")"`
+	default:
+		return t.text
+	}
+}
 
-	for remaining != "" {
-		remaining = strings.TrimLeft(remaining, " ")
-		if remaining == "" {
-			break
+// lexFilter splits a query into tokens. Quoted strings, /regex/ values and
+// in(...) lists are kept inside their term even if they contain spaces or
+// parentheses.
+func lexFilter(query string) ([]filterToken, error) {
+	var tokens []filterToken
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: tokLParen, text: "(", pos: i})
+			i++
+			continue
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: tokRParen, text: ")", pos: i})
+			i++
+			continue
+		case c == '-' && i+1 < len(query) && query[i+1] == '(':
+			tokens = append(tokens, filterToken{kind: tokNot, text: "-", pos: i})
+			i++
+			continue
 		}
 
-		// Handle quoted string (full-text search in message)
-		if remaining[0] == '"' {
-			end := strings.Index(remaining[1:], "\"")
-			if end == -1 {
-				return nil, fmt.Errorf("unclosed quote in filter")
+		start := i
+		for i < len(query) {
+			c := query[i]
+			if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ')' {
+				break
 			}
-			text := remaining[1 : end+1]
-			clauses = append(clauses, filterClause{
-				field:    "",
-				op:       opContains,
-				values:   []string{strings.ToLower(text)},
-				contains: true,
-			})
-			remaining = remaining[end+2:]
-			continue
+			if c == '(' {
+				// Only an in( list may open a paren inside a term
+				if !strings.HasSuffix(strings.ToLower(query[start:i]), ":in") {
+					break
+				}
+				end, err := scanInList(query, i)
+				if err != nil {
+					return nil, err
+				}
+				i = end
+				continue
+			}
+			if c == '"' {
+				end, err := scanDelimited(query, i, '"', "unclosed quote")
+				if err != nil {
+					return nil, err
+				}
+				i = end
+				continue
+			}
+			if c == '/' && startsValue(query, start, i) {
+				end, err := scanDelimited(query, i, '/', "unclosed regular expression")
+				if err != nil {
+					return nil, err
+				}
+				i = end
+				continue
+			}
+			i++
 		}
 
-		// Find next token boundary
-		nextSpace := strings.Index(remaining, " ")
-		var token string
-		if nextSpace == -1 {
-			token = remaining
-			remaining = ""
-		} else {
-			token = remaining[:nextSpace]
-			remaining = remaining[nextSpace+1:]
+		text := query[start:i]
+		kind := tokTerm
+		switch text {
+		case "AND", "&&":
+			kind = tokAnd
+		case "OR", "||":
+			kind = tokOr
+		case "NOT":
+			kind = tokNot
 		}
+		tokens = append(tokens, filterToken{kind: kind, text: text, pos: start})
+	}
+	return append(tokens, filterToken{kind: tokEOF, pos: len(query)}), nil
+}
 
-		if token == "" {
+// startsValue reports whether offset i begins a term's value: the start of
+// the term, just after a leading '-', or just after the field's colon.
+func startsValue(query string, start, i int) bool {
+	if i == start || (i == start+1 && query[start] == '-') {
+		return true
+	}
+	return query[i-1] == ':' && !strings.Contains(query[start:i-1], ":")
+}
+
+// scanDelimited returns the offset just past the closing delimiter matching
+// the one at query[open]. Backslash escapes the delimiter.
+func scanDelimited(query string, open int, delim byte, msg string) (int, error) {
+	for j := open + 1; j < len(query); j++ {
+		if query[j] == '\\' {
+			j++
 			continue
 		}
+		if query[j] == delim {
+			return j + 1, nil
+		}
+	}
+	return 0, &FilterSyntaxError{Pos: open + 1, Msg: msg}
+}
+
+// scanInList returns the offset just past the ')' closing the in( list that
+// opens at query[open].
+func scanInList(query string, open int) (int, error) {
+	for j := open + 1; j < len(query); j++ {
+		switch query[j] {
+		case '"':
+			end, err := scanDelimited(query, j, '"', "unclosed quote")
+			if err != nil {
+				return 0, err
+			}
+			j = end - 1
+		case ')':
+			return j + 1, nil
+		}
+	}
+	return 0, &FilterSyntaxError{Pos: open + 1, Msg: `unclosed "in(" list`}
+}
+
+// filterParser is a recursive-descent parser over filter tokens:
+//
+//	or    := and ("OR" and)*
+//	and   := unary ("AND"? unary)*
+//	unary := ("NOT" | "-") unary | primary
+//	primary := "(" or ")" | term
+type filterParser struct {
+	query  string
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser) errorf(offset int, format string, args ...any) error {
+	return &FilterSyntaxError{Pos: offset + 1, Msg: fmt.Sprintf(format, args...)}
+}
 
-		clause, err := parseClause(token)
+func (p *filterParser) parseOr() (filterNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []filterNode{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		child, err := p.parseAnd()
 		if err != nil {
 			return nil, err
 		}
-		clauses = append(clauses, clause)
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
 	}
+	return &orNode{children: children}, nil
+}
 
-	return &Filter{clauses: clauses}, nil
+func (p *filterParser) parseAnd() (filterNode, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	children := []filterNode{first}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokTerm, tokNot, tokLParen:
+			// Implicit AND
+		default:
+			if len(children) == 1 {
+				return first, nil
+			}
+			return &andNode{children: children}, nil
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, p.errorf(tok.pos, "empty group")
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf(tok.pos, `"(" is never closed`)
+		}
+		p.next()
+		return inner, nil
+	case tokTerm:
+		clause, err := parseClause(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok.pos, "%v", err)
+		}
+		return &clause, nil
+	case tokEOF:
+		return nil, p.errorf(tok.pos, "expected a filter term at end of filter")
+	default:
+		return nil, p.errorf(tok.pos, "expected a filter term, found %s", tok.describe())
+	}
 }
 
 // parseClause parses a single filter clause.
@@ -111,11 +388,24 @@ func parseClause(token string) (filterClause, error) {
 	clause := filterClause{op: opEqual}
 
 	// Check for negation
-	if strings.HasPrefix(token, "-") {
+	if strings.HasPrefix(token, "-") && len(token) > 1 {
 		clause.negate = true
 		token = token[1:]
 	}
 
+	// Bare /regex/ matches the message
+	if isRegexLiteral(token) {
+		return compileRegexClause(clause, token)
+	}
+
+	// Quoted text is a message contains, even if it holds a colon
+	if strings.HasPrefix(token, "\"") {
+		clause.op = opContains
+		clause.values = []string{strings.ToLower(unquoteFilterValue(token))}
+		clause.contains = true
+		return clause, nil
+	}
+
 	// Find field:value separator
 	colonIdx := strings.Index(token, ":")
 	if colonIdx == -1 {
@@ -130,28 +420,54 @@ func parseClause(token string) (filterClause, error) {
 	clause.field = token[:colonIdx]
 	value := token[colonIdx+1:]
 
+	if clause.field == "" {
+		return clause, fmt.Errorf("missing field name before ':'")
+	}
+
+	// has:field / missing:field test for presence
+	if clause.field == "has" || clause.field == "missing" {
+		if value == "" {
+			return clause, fmt.Errorf("%s: needs a field name", clause.field)
+		}
+		clause.negate = clause.negate != (clause.field == "missing")
+		clause.field = value
+		clause.op = opExists
+		clause.values = nil
+		return clause, nil
+	}
+
 	// Parse operator and value
-	if strings.HasPrefix(value, "~") {
+	if isRegexLiteral(value) {
+		return compileRegexClause(clause, value)
+	} else if strings.HasPrefix(value, "~") {
 		clause.op = opContains
 		clause.contains = true
-		value = strings.Trim(value[1:], "\"")
+		value = unquoteFilterValue(value[1:])
 		clause.values = []string{strings.ToLower(value)}
 	} else if strings.HasPrefix(value, ">=") {
 		clause.op = opGreaterOrEqual
-		value = strings.Trim(value[2:], "\"")
+		value = unquoteFilterValue(value[2:])
 		clause.values = []string{value}
 	} else if strings.HasPrefix(value, "<=") {
 		clause.op = opLessOrEqual
-		value = strings.Trim(value[2:], "\"")
+		value = unquoteFilterValue(value[2:])
 		clause.values = []string{value}
 	} else if strings.HasPrefix(value, ">") {
 		clause.op = opGreater
-		value = strings.Trim(value[1:], "\"")
+		value = unquoteFilterValue(value[1:])
 		clause.values = []string{value}
 	} else if strings.HasPrefix(value, "<") {
 		clause.op = opLess
-		value = strings.Trim(value[1:], "\"")
+		value = unquoteFilterValue(value[1:])
 		clause.values = []string{value}
+	} else if len(value) >= 3 && strings.EqualFold(value[:3], "in(") {
+		values, err := splitInList(value[3:])
+		if err != nil {
+			return clause, err
+		}
+		clause.values = values
+	} else if strings.HasPrefix(value, "\"") {
+		clause.values = []string{unquoteFilterValue(value)}
 	} else {
 		// Check for OR values (comma-separated)
 		clause.values = strings.Split(value, ",")
@@ -160,21 +476,98 @@ func parseClause(token string) (filterClause, error) {
 	return clause, nil
 }
 
+// isRegexLiteral reports whether s is a /regex/ literal.
+func isRegexLiteral(s string) bool {
+	return len(s) >= 2 && s[0] == '/' && s[len(s)-1] == '/'
+}
+
+// compileRegexClause completes a clause for a /regex/ literal.
+func compileRegexClause(clause filterClause, literal string) (filterClause, error) {
+	pattern := strings.ReplaceAll(literal[1:len(literal)-1], `\/`, "/")
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return clause, fmt.Errorf("invalid regular expression %s: %v", literal, err)
+	}
+	clause.op = opRegex
+	clause.regex = re
+	clause.values = []string{pattern}
+	return clause, nil
+}
+
+// splitInList splits the body of an in(...) list (without the leading
+// "in(") into values. Values may be quoted to include commas or spaces.
+func splitInList(body string) ([]string, error) {
+	if !strings.HasSuffix(body, ")") {
+		return nil, fmt.Errorf(`unclosed "in(" list`)
+	}
+	body = body[:len(body)-1]
+
+	var values []string
+	var current strings.Builder
+	inQuote := false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && inQuote && i+1 < len(body):
+			i++
+			current.WriteByte(body[i])
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			values = append(values, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	values = append(values, strings.TrimSpace(current.String()))
+	if len(values) == 1 && values[0] == "" {
+		return nil, fmt.Errorf(`empty "in()" list`)
+	}
+	return values, nil
+}
+
+// unquoteFilterValue strips surrounding double quotes, honoring backslash escapes.
+func unquoteFilterValue(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if v, err := strconv.Unquote(s); err == nil {
+			return v
+		}
+		return s[1 : len(s)-1]
+	}
+	return strings.Trim(s, "\"")
+}
+
 // Match returns true if the entry matches the filter.
 func (f *Filter) Match(entry LogEntry) bool {
-	if len(f.clauses) == 0 {
+	if f.root == nil {
 		return true
 	}
+	return f.root.match(entry)
+}
 
-	// All clauses must match (AND)
-	for _, clause := range f.clauses {
-		if !clause.match(entry) {
+func (n *andNode) match(entry LogEntry) bool {
+	for _, child := range n.children {
+		if !child.match(entry) {
 			return false
 		}
 	}
 	return true
 }
 
+func (n *orNode) match(entry LogEntry) bool {
+	for _, child := range n.children {
+		if child.match(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *notNode) match(entry LogEntry) bool {
+	return !n.child.match(entry)
+}
+
 // match checks if a single clause matches the entry.
 func (c *filterClause) match(entry LogEntry) bool {
 	result := c.matchInternal(entry)
@@ -184,22 +577,42 @@ func (c *filterClause) match(entry LogEntry) bool {
 	return result
 }
 
-// matchInternal performs the actual match logic.
-func (c *filterClause) matchInternal(entry LogEntry) bool {
-	// Get the field value
-	var fieldValue string
+// fieldValue looks up a field by name, reporting whether it is present.
+func (c *filterClause) fieldValue(entry LogEntry) (string, bool) {
 	switch c.field {
 	case "", "message", "msg":
-		fieldValue = entry.Message
+		return entry.Message, entry.Message != ""
 	case "level":
-		fieldValue = string(entry.Level)
+		return string(entry.Level), entry.Level != ""
+	case "timestamp", "ts", "time":
+		return entry.Timestamp.Format(time.RFC3339Nano), !entry.Timestamp.IsZero()
+	}
+	// Look in fields
+	v, ok := entry.Fields[c.field]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%v", v), true
+}
+
+// matchInternal performs the actual match logic.
+func (c *filterClause) matchInternal(entry LogEntry) bool {
+	fieldValue, present := c.fieldValue(entry)
+
+	switch c.op {
+	case opExists:
+		return present
+	case opRegex:
+		return present && c.regex.MatchString(fieldValue)
+	}
+
+	switch c.field {
+	case "", "message", "msg", "level":
+		// Built-in fields match even when empty
 	case "timestamp", "ts", "time":
 		return c.matchTimestamp(entry.Timestamp)
 	default:
-		// Look in fields
-		if v, ok := entry.Fields[c.field]; ok {
-			fieldValue = fmt.Sprintf("%v", v)
-		} else {
+		if !present {
 			return false
 		}
 	}
@@ -336,22 +749,43 @@ func parseNumber(s string) (float64, error) {
 
 // IsEmpty returns true if the filter has no clauses.
 func (f *Filter) IsEmpty() bool {
-	return len(f.clauses) == 0
+	return f.root == nil
 }
 
 // String returns the string representation of the filter.
 func (f *Filter) String() string {
-	if len(f.clauses) == 0 {
+	if f.root == nil {
 		return ""
 	}
+	return f.root.String()
+}
 
-	var parts []string
-	for _, c := range f.clauses {
-		parts = append(parts, c.String())
+func (n *andNode) String() string {
+	parts := make([]string, len(n.children))
+	for i, child := range n.children {
+		parts[i] = child.String()
+		if _, ok := child.(*orNode); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
 	}
 	return strings.Join(parts, " ")
 }
 
+func (n *orNode) String() string {
+	parts := make([]string, len(n.children))
+	for i, child := range n.children {
+		parts[i] = child.String()
+	}
+	return strings.Join(parts, " OR ")
+}
+
+func (n *notNode) String() string {
+	if _, ok := n.child.(*filterClause); ok {
+		return "NOT " + n.child.String()
+	}
+	return "-(" + n.child.String() + ")"
+}
+
 // String returns the string representation of a clause.
 func (c *filterClause) String() string {
 	var prefix string
@@ -359,6 +793,20 @@ func (c *filterClause) String() string {
 		prefix = "-"
 	}
 
+	switch c.op {
+	case opExists:
+		if c.negate {
+			return "missing:" + c.field
+		}
+		return "has:" + c.field
+	case opRegex:
+		pattern := "/" + strings.ReplaceAll(c.values[0], "/", `\/`) + "/"
+		if c.field == "" {
+			return prefix + pattern
+		}
+		return prefix + c.field + ":" + pattern
+	}
+
 	var op string
 	switch c.op {
 	case opContains:
@@ -379,5 +827,18 @@ func (c *filterClause) String() string {
 		return fmt.Sprintf(`%s"%s"`, prefix, strings.Join(c.values, ","))
 	}
 
+	for _, v := range c.values {
+		if strings.ContainsAny(v, " ,()\"") {
+			quoted := make([]string, len(c.values))
+			for i, v := range c.values {
+				quoted[i] = strconv.Quote(v)
+			}
+			if c.op == opEqual {
+				return fmt.Sprintf("%s%s:in(%s)", prefix, c.field, strings.Join(quoted, ","))
+			}
+			return fmt.Sprintf("%s%s%s%s", prefix, c.field, op, quoted[0])
+		}
+	}
+
 	return fmt.Sprintf("%s%s%s%s", prefix, c.field, op, strings.Join(c.values, ","))
 }
@@ -4,6 +4,7 @@
 package logs
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -420,3 +421,139 @@ func TestClauseString(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterBooleanExpressions(t *testing.T) {
+	entry := LogEntry{
+		Level:   LevelWarn,
+		Message: "GET /api/users slow",
+		Fields: map[string]any{
+			"status":  float64(503),
+			"path":    "/api/users",
+			"user_id": "u42",
+			"method":  "GET",
+		},
+	}
+	health := LogEntry{
+		Level:   LevelError,
+		Message: "health check failed",
+		Fields:  map[string]any{"status": float64(500), "path": "/health"},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		entry   LogEntry
+		matches bool
+	}{
+		{"or first", "level:error OR status:>=500", entry, true},
+		{"or none", "level:error OR status:<400", entry, false},
+		{"grouped and negated", `(level:error OR status:>=500) AND -path:~"/health"`, entry, true},
+		{"grouped and negated excludes", `(level:error OR status:>=500) AND -path:~"/health"`, health, false},
+		{"implicit and with group", "(level:error OR level:warn) method:GET", entry, true},
+		{"and binds tighter than or", "level:error method:POST OR user_id:u42", entry, true},
+		{"not keyword", "NOT level:warn", entry, false},
+		{"not group", "-(level:error OR level:debug)", entry, true},
+		{"nested groups", "((status:503) OR (status:504)) AND has:user_id", entry, true},
+		{"has present", "has:user_id", entry, true},
+		{"has absent", "has:user_id", health, false},
+		{"missing absent", "missing:user_id", health, true},
+		{"missing present", "missing:user_id", entry, false},
+		{"has builtin", "has:level", entry, true},
+		{"regex field", `path:/^\/api\/(users|orders)$/`, entry, true},
+		{"regex field no match", `path:/^\/health/`, entry, false},
+		{"regex bare message", "/users\\s+slow/", entry, true},
+		{"regex missing field", "trace:/./", entry, false},
+		{"regex with space", `msg:/check failed/`, health, true},
+		{"negated regex", "-path:/health/", health, false},
+		{"in list", "status:in(500,503)", entry, true},
+		{"in list no match", "status:in(500,504)", entry, false},
+		{"in list quoted", `method:in("GET","HEAD")`, entry, true},
+		{"quoted exact value", `path:"/api/users"`, entry, true},
+		{"symbolic operators", "level:error || (status:503 && method:GET)", entry, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.query)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error: %v", tt.query, err)
+			}
+			if got := filter.Match(tt.entry); got != tt.matches {
+				t.Errorf("Filter(%q).Match() = %v, want %v", tt.query, got, tt.matches)
+			}
+		})
+	}
+}
+
+func TestFilterSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"(level:error OR status:500", 1, `"(" is never closed`},
+		{"level:error)", 12, `unexpected ")"`},
+		{"level:error OR", 15, "expected a filter term"},
+		{"OR level:error", 1, "expected a filter term"},
+		{"level:error AND AND x", 17, "expected a filter term"},
+		{"()", 1, "empty group"},
+		{`msg:~"unclosed`, 6, "unclosed quote"},
+		{"path:/unclosed", 6, "unclosed regular expression"},
+		{"path:/([a-z/", 1, "invalid regular expression"},
+		{"x has:", 3, "needs a field name"},
+		{"status:in(1,2", 10, `unclosed "in(" list`},
+		{":value", 1, "missing field name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseFilter(tt.query)
+			if err == nil {
+				t.Fatalf("ParseFilter(%q) succeeded, want error", tt.query)
+			}
+			synErr, ok := err.(*FilterSyntaxError)
+			if !ok {
+				t.Fatalf("ParseFilter(%q) error type %T, want *FilterSyntaxError", tt.query, err)
+			}
+			if synErr.Pos != tt.pos {
+				t.Errorf("ParseFilter(%q) error position = %d, want %d (%v)", tt.query, synErr.Pos, tt.pos, err)
+			}
+			if !strings.Contains(synErr.Msg, tt.msg) {
+				t.Errorf("ParseFilter(%q) error = %q, want it to contain %q", tt.query, synErr.Msg, tt.msg)
+			}
+		})
+	}
+}
+
+func TestFilterExpressionString(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"level:error OR status:>=500", "level:error OR status:>=500"},
+		{"(level:error OR level:warn) has:user_id", "(level:error OR level:warn) has:user_id"},
+		{"-has:user_id", "missing:user_id"},
+		{"NOT level:debug", "NOT level:debug"},
+		{"-(a OR b)", `-("a" OR "b")`},
+		{"path:/^\\/api/", "path:/^\\/api/"},
+		{`method:in("GET","HEAD")`, "method:GET,HEAD"},
+		{`path:in("a b",c)`, `path:in("a b","c")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filter, err := ParseFilter(tt.query)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error: %v", tt.query, err)
+			}
+			got := filter.String()
+			if got != tt.expected {
+				t.Errorf("Filter(%q).String() = %q, want %q", tt.query, got, tt.expected)
+			}
+			// The string form must parse back to an equivalent filter
+			if _, err := ParseFilter(got); err != nil {
+				t.Errorf("ParseFilter(%q) round-trip error: %v", got, err)
+			}
+		})
+	}
+}
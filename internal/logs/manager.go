@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +29,7 @@ type Manager struct {
 	disconnectGrace  time.Duration                 // duration after the last subscriber leaves before the tail is stopped
 	autoPauseRate    int                           // lines/sec above which the UI auto-pauses following (0 = disabled)
 	cleanupCancel    context.CancelFunc            // cancel function for cleanup goroutine
+	persistDir       string                        // directory for persisted viewer buffers ("" = persistence disabled)
 }
 
 // NewManager creates a new log viewer manager.
@@ -70,15 +72,23 @@ func (m *Manager) AutoPauseRate() int {
 	return m.autoPauseRate
 }
 
+// SetPersistDir sets the directory under which viewers with buffer.persist
+// keep their buffers. Must be called before Initialize.
+func (m *Manager) SetPersistDir(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.persistDir = dir
+}
+
 // Initialize creates viewers from configuration.
 func (m *Manager) Initialize(configs []config.LogViewerConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for _, cfg := range configs {
-		viewer, err := NewViewer(cfg)
+		viewer, err := m.newViewer(cfg)
 		if err != nil {
-			return fmt.Errorf("creating viewer %s: %w", cfg.Name, err)
+			return err
 		}
 		m.viewers[cfg.Name] = viewer
 	}
@@ -86,28 +96,77 @@ func (m *Manager) Initialize(configs []config.LogViewerConfig) error {
 	return nil
 }
 
+// newViewer creates a viewer from config, attaching its on-disk store when
+// buffer.persist is set. A store that fails to open is logged and the viewer
+// falls back to memory only. Caller must hold m.mu.
+func (m *Manager) newViewer(cfg config.LogViewerConfig) (*Viewer, error) {
+	viewer, err := NewViewer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating viewer %s: %w", cfg.Name, err)
+	}
+
+	if cfg.Buffer.Persist && m.persistDir != "" {
+		maxEntries := cfg.Buffer.PersistMaxEntries
+		if maxEntries <= 0 {
+			maxEntries = viewer.buffer.MaxSize() * 10
+		}
+		dir := filepath.Join(m.persistDir, StoreDirName(cfg.Name))
+		if err := viewer.EnablePersistence(dir, maxEntries); err != nil {
+			log.Printf("Log viewer %s: persistence disabled: %v", cfg.Name, err)
+		}
+	}
+
+	return viewer, nil
+}
+
 // AddViewer registers a programmatically-created viewer (e.g., service log viewers).
+// A resident viewer added after Start is started right away.
 func (m *Manager) AddViewer(viewer *Viewer) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.viewers[viewer.Name()] = viewer
+	if m.ctx != nil && viewer.Resident() {
+		if _, running := m.monitorCancel[viewer.Name()]; !running {
+			m.startViewerLocked(viewer.Name(), viewer)
+		}
+	}
+}
+
+// isAddedViewer reports whether a viewer is created by Trellis with
+// AddViewer rather than from log_viewers config: svc:* viewers for service
+// output and proxy:* viewers for proxy access logs.
+func isAddedViewer(name string) bool {
+	return strings.HasPrefix(name, "svc:") || strings.HasPrefix(name, config.ProxyAccessLogViewerPrefix)
 }
 
 // RemoveServiceViewers stops and removes all viewers whose names start with "svc:".
 func (m *Manager) RemoveServiceViewers() {
-	m.mu.Lock()
-
+	m.mu.RLock()
 	var toRemove []string
 	for name := range m.viewers {
 		if strings.HasPrefix(name, "svc:") {
 			toRemove = append(toRemove, name)
 		}
 	}
+	m.mu.RUnlock()
+
+	m.removeViewers(toRemove)
+}
+
+// RemoveViewer stops and removes a viewer added with AddViewer, e.g. the
+// access log viewer of a proxy listener that was removed from config.
+func (m *Manager) RemoveViewer(name string) {
+	m.removeViewers([]string{name})
+}
+
+// removeViewers stops and removes the named viewers.
+func (m *Manager) removeViewers(toRemove []string) {
+	m.mu.Lock()
 
 	// Track how many goroutines we're cancelling so we can wait for just those
 	var wg sync.WaitGroup
 
-	// Cancel monitor goroutines for service viewers
+	// Cancel monitor goroutines for the viewers
 	for _, name := range toRemove {
 		if cancel, ok := m.monitorCancel[name]; ok {
 			wg.Add(1)
@@ -119,11 +178,11 @@ func (m *Manager) RemoveServiceViewers() {
 		}
 	}
 
-	// Stop and remove service viewers
+	// Stop and remove the viewers
 	for _, name := range toRemove {
 		if viewer, ok := m.viewers[name]; ok {
 			if err := viewer.Stop(); err != nil {
-				log.Printf("Failed to stop service viewer %s: %v", name, err)
+				log.Printf("Failed to stop log viewer %s: %v", name, err)
 			}
 			delete(m.viewers, name)
 		}
@@ -153,6 +212,8 @@ func (m *Manager) Start(ctx context.Context) error {
 		log.Printf("Log manager ready with %d viewers (lazy startup, no idle timeout)", len(m.viewers))
 	}
 
+	m.startResidentViewersLocked()
+
 	return nil
 }
 
@@ -180,6 +241,28 @@ func (m *Manager) EnsureStarted(name string) error {
 	}
 
 	log.Printf("Starting log viewer %s on-demand (source: %s)", name, viewer.source.Name())
+	return m.startViewerLocked(name, viewer)
+}
+
+// startResidentViewersLocked starts viewers whose sources must keep running
+// to avoid losing pushed data. Failures are logged and reported as
+// log.error events. Caller must hold m.mu and m.ctx must be set.
+func (m *Manager) startResidentViewersLocked() {
+	for name, viewer := range m.viewers {
+		if !viewer.Resident() {
+			continue
+		}
+		if _, running := m.monitorCancel[name]; running {
+			continue
+		}
+		log.Printf("Starting resident log viewer %s (source: %s)", name, viewer.source.Name())
+		m.startViewerLocked(name, viewer)
+	}
+}
+
+// startViewerLocked starts a viewer and its error monitor. Caller must hold
+// m.mu and m.ctx must be set.
+func (m *Manager) startViewerLocked(name string, viewer *Viewer) error {
 	if err := viewer.Start(m.ctx); err != nil {
 		log.Printf("Failed to start log viewer %s: %v", name, err)
 		m.emitEvent("log.error", map[string]any{
@@ -199,9 +282,9 @@ func (m *Manager) EnsureStarted(name string) error {
 	monitorCtx, cancel := context.WithCancel(m.ctx)
 	m.monitorCancel[name] = cancel
 
-	// Use separate wait groups for service vs config-based viewers
+	// Use separate wait groups for added vs config-based viewers
 	// so UpdateConfigs can wait only for config-based viewers
-	if strings.HasPrefix(name, "svc:") {
+	if isAddedViewer(name) {
 		m.serviceMonitorWg.Add(1)
 		go m.monitorErrorsService(monitorCtx, name, viewer)
 	} else {
@@ -233,6 +316,7 @@ func (m *Manager) Stop() {
 		if err := viewer.Stop(); err != nil {
 			log.Printf("Failed to stop log viewer %s: %v", name, err)
 		}
+		viewer.ClosePersistence()
 	}
 
 	m.mu.Unlock()
@@ -243,14 +327,14 @@ func (m *Manager) Stop() {
 }
 
 // UpdateConfigs stops explicit (non-service) viewers and reinitializes with new configs.
-// Service viewers (names starting with "svc:") are preserved — they are managed
-// separately via RemoveServiceViewers/AddViewer.
+// Viewers added with AddViewer (see isAddedViewer) are preserved — they are
+// managed separately via RemoveServiceViewers/AddViewer.
 func (m *Manager) UpdateConfigs(configs []config.LogViewerConfig) error {
 	m.mu.Lock()
 
 	// Cancel monitor goroutines for non-service viewers only
 	for name, cancel := range m.monitorCancel {
-		if !strings.HasPrefix(name, "svc:") {
+		if !isAddedViewer(name) {
 			cancel()
 			delete(m.monitorCancel, name)
 		}
@@ -258,7 +342,7 @@ func (m *Manager) UpdateConfigs(configs []config.LogViewerConfig) error {
 
 	// Stop non-service viewers
 	for name, viewer := range m.viewers {
-		if !strings.HasPrefix(name, "svc:") {
+		if !isAddedViewer(name) {
 			if err := viewer.Stop(); err != nil {
 				log.Printf("Failed to stop log viewer %s during config update: %v", name, err)
 			}
@@ -275,25 +359,31 @@ func (m *Manager) UpdateConfigs(configs []config.LogViewerConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Preserve service viewers, clear explicit viewers
+	// Preserve service viewers, clear explicit viewers. Discarded viewers
+	// release their stores so replacements can reopen the same directory.
 	preserved := make(map[string]*Viewer)
 	for name, viewer := range m.viewers {
-		if strings.HasPrefix(name, "svc:") {
+		if isAddedViewer(name) {
 			preserved[name] = viewer
+		} else {
+			viewer.ClosePersistence()
 		}
 	}
 	m.viewers = preserved
 
 	// Create new viewers from updated config
 	for _, cfg := range configs {
-		viewer, err := NewViewer(cfg)
+		viewer, err := m.newViewer(cfg)
 		if err != nil {
-			return fmt.Errorf("creating viewer %s: %w", cfg.Name, err)
+			return err
 		}
 		m.viewers[cfg.Name] = viewer
 	}
 
-	log.Printf("Updated %d log viewers with new config (preserved %d service viewers)", len(configs), len(preserved))
+	log.Printf("Updated %d log viewers with new config (preserved %d service and proxy viewers)", len(configs), len(preserved))
+	if m.ctx != nil {
+		m.startResidentViewersLocked()
+	}
 	return nil
 }
 
@@ -484,8 +574,8 @@ func (m *Manager) stopIdleViewers() {
 			continue
 		}
 
-		// Skip if has active subscribers
-		if viewer.SubscriberCount() > 0 {
+		// Skip if has active subscribers or must keep receiving
+		if viewer.SubscriberCount() > 0 || viewer.Resident() {
 			continue
 		}
 
@@ -913,6 +913,52 @@ func TestManagerUpdateConfigs_PreservesServiceViewers(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestManagerUpdateConfigs_PreservesProxyViewers(t *testing.T) {
+	manager := NewManager(nil, config.LogViewerSettings{})
+	require.NoError(t, manager.Initialize(nil))
+
+	viewer, err := NewViewerWithSource(config.LogViewerConfig{
+		Name:   "proxy:8080",
+		Parser: config.LogParserConfig{Type: "json"},
+	}, NewPushSource("proxy:8080"))
+	require.NoError(t, err)
+	manager.AddViewer(viewer)
+
+	require.NoError(t, manager.UpdateConfigs(nil))
+	got, ok := manager.Get("proxy:8080")
+	require.True(t, ok)
+	assert.Same(t, viewer, got)
+
+	// Only svc:* viewers are removed with the services
+	manager.RemoveServiceViewers()
+	_, ok = manager.Get("proxy:8080")
+	assert.True(t, ok)
+}
+
+func TestManagerAddViewer_AfterStart(t *testing.T) {
+	manager := NewManager(nil, config.LogViewerSettings{})
+	require.NoError(t, manager.Initialize(nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	// A resident viewer added later (a proxy listener added by a config
+	// reload) starts at once, so no pushed entries are lost
+	src := NewPushSource("proxy:9090")
+	viewer, err := NewViewerWithSource(config.LogViewerConfig{
+		Name:   "proxy:9090",
+		Parser: config.LogParserConfig{Type: "json"},
+	}, src)
+	require.NoError(t, err)
+	manager.AddViewer(viewer)
+	assert.True(t, src.Status().Connected)
+
+	manager.RemoveViewer("proxy:9090")
+	_, ok := manager.Get("proxy:9090")
+	assert.False(t, ok)
+}
+
 func TestManagerUpdateConfigs_OldBehaviorWithoutServiceViewers(t *testing.T) {
 	manager := NewManager(nil, config.LogViewerSettings{})
 
@@ -1060,3 +1106,85 @@ func TestManagerStopIdleViewers_GraceDisabled(t *testing.T) {
 	_, stillTracked := manager.monitorCancel["test-viewer"]
 	assert.True(t, stillTracked, "viewer should remain marked running when disconnect grace is disabled")
 }
+
+func TestManager_PersistedViewers(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(nil, config.LogViewerSettings{})
+	manager.SetPersistDir(dir)
+
+	configs := []config.LogViewerConfig{
+		{
+			Name:   "kept",
+			Source: config.LogSourceConfig{Type: "file", Path: "/var/log/test.log"},
+			Buffer: config.LogBufferConfig{MaxEntries: 100, Persist: true},
+		},
+		{
+			Name:   "memory",
+			Source: config.LogSourceConfig{Type: "file", Path: "/var/log/test2.log"},
+			Buffer: config.LogBufferConfig{MaxEntries: 100},
+		},
+	}
+	require.NoError(t, manager.Initialize(configs))
+
+	kept, ok := manager.Get("kept")
+	require.True(t, ok)
+	assert.True(t, kept.IsPersistent())
+	kept.buffer.Add(LogEntry{Raw: "survives", Timestamp: time.Now()})
+
+	memory, ok := manager.Get("memory")
+	require.True(t, ok)
+	assert.False(t, memory.IsPersistent())
+
+	// Recreated viewers reload what the previous instance stored
+	require.NoError(t, manager.UpdateConfigs(configs))
+	kept, ok = manager.Get("kept")
+	require.True(t, ok)
+	entries := kept.GetEntries(nil, 0)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "survives", entries[0].Raw)
+	assert.Equal(t, uint64(1), entries[0].Sequence)
+
+	manager.Stop()
+}
+
+func TestManagerStartsResidentViewers(t *testing.T) {
+	manager := NewManager(nil, config.LogViewerSettings{IdleTimeout: "1ms"})
+	manager.Initialize([]config.LogViewerConfig{
+		{
+			Name:   "syslog",
+			Source: config.LogSourceConfig{Type: "syslog_listen", Listen: "127.0.0.1:0"},
+		},
+		{
+			Name:   "cmd",
+			Source: config.LogSourceConfig{Type: "command", Command: []string{"sleep", "10"}},
+			Parser: config.LogParserConfig{Type: "none"},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Start(ctx); err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+	defer manager.Stop()
+
+	syslog, _ := manager.Get("syslog")
+	if !syslog.IsRunning() {
+		t.Fatal("resident viewer should start with the manager")
+	}
+	if syslog.Config().Parser.Type != "syslog" {
+		t.Errorf("parser type = %q, want syslog", syslog.Config().Parser.Type)
+	}
+	cmd, _ := manager.Get("cmd")
+	if cmd.IsRunning() {
+		t.Error("non-resident viewer should start lazily")
+	}
+
+	// Idle cleanup leaves resident viewers running
+	syslog.Touch()
+	time.Sleep(5 * time.Millisecond)
+	manager.stopIdleViewers()
+	if !syslog.IsRunning() {
+		t.Error("resident viewer should not be stopped when idle")
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// Multiline defaults.
+const (
+	defaultMultilineMaxLines = 500
+	defaultMultilineTimeout  = time.Second
+	defaultStackField        = "stack"
+)
+
+// Multiline decides which raw lines continue the entry before them. Lines
+// are folded into one newline-joined text before parsing; a parser created
+// with a multiline config knows how to parse that text.
+type Multiline struct {
+	start    *regexp.Regexp
+	cont     *regexp.Regexp
+	maxLines int
+	timeout  time.Duration
+}
+
+// NewMultiline compiles a multiline config. It returns nil when cfg is nil,
+// and every Multiline method treats a nil receiver as "no folding".
+func NewMultiline(cfg *config.MultilineConfig) (*Multiline, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Start == "" && cfg.Continuation == "" {
+		return nil, fmt.Errorf("multiline requires start or continuation")
+	}
+
+	m := &Multiline{
+		maxLines: defaultMultilineMaxLines,
+		timeout:  defaultMultilineTimeout,
+	}
+	var err error
+	if cfg.Start != "" {
+		if m.start, err = regexp.Compile(cfg.Start); err != nil {
+			return nil, fmt.Errorf("invalid multiline start pattern: %w", err)
+		}
+	}
+	if cfg.Continuation != "" {
+		if m.cont, err = regexp.Compile(cfg.Continuation); err != nil {
+			return nil, fmt.Errorf("invalid multiline continuation pattern: %w", err)
+		}
+	}
+	if cfg.MaxLines > 0 {
+		m.maxLines = cfg.MaxLines
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid multiline timeout %q", cfg.Timeout)
+		}
+		m.timeout = d
+	}
+	return m, nil
+}
+
+// Continues reports whether line belongs to the entry before it.
+func (m *Multiline) Continues(line string) bool {
+	if m == nil {
+		return false
+	}
+	if m.start != nil && m.start.MatchString(line) {
+		return false
+	}
+	if m.cont != nil {
+		return m.cont.MatchString(line)
+	}
+	return true
+}
+
+// MaxLines returns the most lines folded into one entry.
+func (m *Multiline) MaxLines() int {
+	if m == nil {
+		return 1
+	}
+	return m.maxLines
+}
+
+// Timeout returns how long a pending entry waits for more lines.
+func (m *Multiline) Timeout() time.Duration {
+	if m == nil {
+		return 0
+	}
+	return m.timeout
+}
+
+// Group folds a chronological batch of lines into entry texts. It is used
+// when reading backward, where a batch can begin in the middle of an entry:
+// continuation lines before the batch's first start line are returned as
+// head (they belong to an older batch), and tail — the head of the
+// previously read, newer batch — is appended to the batch's last entry.
+func (m *Multiline) Group(lines, tail []string) (texts, head []string) {
+	if m == nil {
+		return lines, nil
+	}
+
+	var cur []string
+	for _, line := range lines {
+		switch {
+		case !m.Continues(line):
+			if cur != nil {
+				texts = append(texts, strings.Join(cur, "\n"))
+			}
+			cur = []string{line}
+		case cur == nil:
+			head = append(head, line)
+		case len(cur) >= m.maxLines:
+			texts = append(texts, strings.Join(cur, "\n"))
+			cur = []string{line}
+		default:
+			cur = append(cur, line)
+		}
+	}
+	if cur == nil {
+		return nil, append(head, tail...)
+	}
+	cur = append(cur, tail...)
+	return append(texts, strings.Join(cur, "\n")), head
+}
+
+// LineAssembler folds a stream of lines into entry texts. It is not safe
+// for concurrent use.
+type LineAssembler struct {
+	m       *Multiline
+	pending []string
+}
+
+// NewLineAssembler creates an assembler. With a nil Multiline every line is
+// its own entry.
+func NewLineAssembler(m *Multiline) *LineAssembler {
+	return &LineAssembler{m: m}
+}
+
+// Add feeds one line. If it completes the pending entry, that entry's text
+// is returned with ok=true.
+func (a *LineAssembler) Add(line string) (text string, ok bool) {
+	if a.m == nil {
+		return line, true
+	}
+	if len(a.pending) > 0 && len(a.pending) < a.m.maxLines && a.m.Continues(line) {
+		a.pending = append(a.pending, line)
+		return "", false
+	}
+	text, ok = a.Flush()
+	a.pending = append(a.pending[:0], line)
+	return text, ok
+}
+
+// Flush returns the pending entry, if any, and clears it.
+func (a *LineAssembler) Flush() (text string, ok bool) {
+	if len(a.pending) == 0 {
+		return "", false
+	}
+	text = strings.Join(a.pending, "\n")
+	a.pending = a.pending[:0]
+	return text, true
+}
+
+// Pending reports whether an entry is waiting for more lines.
+func (a *LineAssembler) Pending() bool {
+	return len(a.pending) > 0
+}
+
+// multilineParser parses texts assembled from several lines. Pretty-printed
+// JSON is parsed whole; otherwise the first line is parsed and the rest is
+// folded into the stack field. Single lines go straight to the inner parser.
+type multilineParser struct {
+	inner LogParser
+	stack string
+}
+
+// Parse implements LogParser.
+func (p *multilineParser) Parse(text string) LogEntry {
+	first, rest, ok := strings.Cut(text, "\n")
+	if !ok {
+		return p.inner.Parse(text)
+	}
+
+	if _, isJSON := p.inner.(*JSONParser); isJSON && json.Valid([]byte(text)) {
+		return p.inner.Parse(text)
+	}
+
+	entry := p.inner.Parse(first)
+	entry.Raw = text
+	if entry.Fields == nil {
+		entry.Fields = make(map[string]any)
+	}
+	if existing, _ := entry.Fields[p.stack].(string); existing != "" {
+		rest = existing + "\n" + rest
+	}
+	entry.Fields[p.stack] = rest
+	return entry
+}
+
+// Name implements LogParser.
+func (p *multilineParser) Name() string {
+	return p.inner.Name()
+}
+
+// ExtractTimestamp implements TimestampExtractor using the entry's first
+// line. Inner parsers without their own extractor get the same
+// Parse-based fallback callers would otherwise use.
+func (p *multilineParser) ExtractTimestamp(line string) (time.Time, bool) {
+	first, _, _ := strings.Cut(line, "\n")
+	if ex, ok := p.inner.(TimestampExtractor); ok {
+		return ex.ExtractTimestamp(first)
+	}
+	return p.inner.Parse(first).Timestamp, true
+}
@@ -0,0 +1,298 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// javaLines is two logfmt entries, the first followed by a Java stack trace.
+var javaLines = []string{
+	`ts=2026-01-15T10:30:00Z level=error msg="request failed"`,
+	`java.lang.IllegalStateException: boom`,
+	`	at com.example.Api.handle(Api.java:42)`,
+	`	at com.example.Server.run(Server.java:7)`,
+	`ts=2026-01-15T10:30:01Z level=info msg="next request"`,
+}
+
+func startPattern() *config.MultilineConfig {
+	return &config.MultilineConfig{Start: `^ts=`}
+}
+
+func TestNewMultiline(t *testing.T) {
+	m, err := NewMultiline(nil)
+	if m != nil || err != nil {
+		t.Errorf("NewMultiline(nil) = %v, %v", m, err)
+	}
+
+	bad := []*config.MultilineConfig{
+		{},
+		{Start: "("},
+		{Continuation: "["},
+		{Start: "^x", Timeout: "soon"},
+	}
+	for _, cfg := range bad {
+		if _, err := NewMultiline(cfg); err == nil {
+			t.Errorf("NewMultiline(%+v) expected error", cfg)
+		}
+	}
+
+	m, err = NewMultiline(&config.MultilineConfig{Start: "^x", MaxLines: 3, Timeout: "250ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MaxLines() != 3 || m.Timeout() != 250*time.Millisecond {
+		t.Errorf("MaxLines=%d Timeout=%v", m.MaxLines(), m.Timeout())
+	}
+}
+
+func TestMultilineContinues(t *testing.T) {
+	tests := []struct {
+		cfg  config.MultilineConfig
+		line string
+		want bool
+	}{
+		{config.MultilineConfig{Start: `^ts=`}, `ts=1 msg=a`, false},
+		{config.MultilineConfig{Start: `^ts=`}, `  at x`, true},
+		{config.MultilineConfig{Continuation: `^\s`}, `  at x`, true},
+		{config.MultilineConfig{Continuation: `^\s`}, `Caused by: y`, false},
+		{config.MultilineConfig{Start: `^ts=`, Continuation: `^\s`}, `Caused by: y`, false},
+		{config.MultilineConfig{Start: `^ts=`, Continuation: `^\s`}, `  at x`, true},
+	}
+	for _, tt := range tests {
+		m, err := NewMultiline(&tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.Continues(tt.line); got != tt.want {
+			t.Errorf("%+v Continues(%q) = %v, want %v", tt.cfg, tt.line, got, tt.want)
+		}
+	}
+
+	var none *Multiline
+	if none.Continues("  at x") {
+		t.Error("nil Multiline should never fold")
+	}
+}
+
+func TestLineAssembler(t *testing.T) {
+	m, _ := NewMultiline(startPattern())
+	asm := NewLineAssembler(m)
+
+	var texts []string
+	for _, line := range javaLines {
+		if text, ok := asm.Add(line); ok {
+			texts = append(texts, text)
+		}
+	}
+	if !asm.Pending() {
+		t.Error("expected the last entry to be pending")
+	}
+	if text, ok := asm.Flush(); ok {
+		texts = append(texts, text)
+	}
+
+	if len(texts) != 2 {
+		t.Fatalf("got %d texts, want 2: %q", len(texts), texts)
+	}
+	if texts[0] != strings.Join(javaLines[:4], "\n") || texts[1] != javaLines[4] {
+		t.Errorf("texts = %q", texts)
+	}
+
+	// A nil Multiline passes lines straight through
+	plain := NewLineAssembler(nil)
+	if text, ok := plain.Add("  at x"); !ok || text != "  at x" {
+		t.Errorf("passthrough Add = %q, %v", text, ok)
+	}
+}
+
+func TestLineAssemblerMaxLines(t *testing.T) {
+	m, _ := NewMultiline(&config.MultilineConfig{Start: `^ts=`, MaxLines: 2})
+	asm := NewLineAssembler(m)
+	var texts []string
+	for _, line := range javaLines {
+		if text, ok := asm.Add(line); ok {
+			texts = append(texts, text)
+		}
+	}
+	if text, ok := asm.Flush(); ok {
+		texts = append(texts, text)
+	}
+	want := []string{
+		javaLines[0] + "\n" + javaLines[1],
+		javaLines[2] + "\n" + javaLines[3],
+		javaLines[4],
+	}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+}
+
+func TestMultilineGroup(t *testing.T) {
+	m, _ := NewMultiline(startPattern())
+
+	// A batch starting mid-trace, with the newer batch's head as tail
+	texts, head := m.Group(javaLines[2:], []string{"	at tail"})
+	if len(head) != 2 || head[0] != javaLines[2] {
+		t.Errorf("head = %q", head)
+	}
+	if len(texts) != 1 || texts[0] != javaLines[4]+"\n\tat tail" {
+		t.Errorf("texts = %q", texts)
+	}
+
+	// A batch with no start line carries everything over
+	texts, head = m.Group(javaLines[1:2], head)
+	if texts != nil || len(head) != 3 {
+		t.Errorf("texts = %q, head = %q", texts, head)
+	}
+
+	texts, head = m.Group(javaLines[:1], head)
+	if head != nil || len(texts) != 1 || texts[0] != strings.Join(javaLines[:4], "\n") {
+		t.Errorf("texts = %q, head = %q", texts, head)
+	}
+}
+
+func TestMultilineParser(t *testing.T) {
+	parser, err := NewParser(config.LogParserConfig{
+		Type: "logfmt", Timestamp: "ts", Level: "level", Message: "msg", Multiline: startPattern(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Join(javaLines[:4], "\n")
+	entry := parser.Parse(text)
+	if entry.Message != "request failed" || entry.Level != LevelError || entry.Raw != text {
+		t.Errorf("entry = %+v", entry)
+	}
+	if entry.Fields["stack"] != strings.Join(javaLines[1:4], "\n") {
+		t.Errorf("stack = %q", entry.Fields["stack"])
+	}
+	if ts, ok := parser.(TimestampExtractor).ExtractTimestamp(text); !ok || ts.Unix() != 1768473000 {
+		t.Errorf("ExtractTimestamp = %v, %v", ts, ok)
+	}
+
+	// A configured stack field is appended to
+	parser, _ = NewParser(config.LogParserConfig{
+		Type: "json", Level: "level", Message: "msg", Stack: "trace", Multiline: &config.MultilineConfig{Start: `^\{`},
+	})
+	entry = parser.Parse(`{"msg":"crash","trace":"main.go:10"}` + "\n" + "goroutine 1 [running]:")
+	if entry.Message != "crash" || entry.Fields["trace"] != "main.go:10\ngoroutine 1 [running]:" {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	// Pretty-printed JSON is parsed whole
+	entry = parser.Parse("{\n  \"msg\": \"pretty\",\n  \"level\": \"warn\"\n}")
+	if entry.Message != "pretty" || entry.Level != LevelWarn {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	if _, err := NewParser(config.LogParserConfig{Multiline: &config.MultilineConfig{}}); err == nil {
+		t.Error("expected error for multiline without patterns")
+	}
+}
+
+func multilineViewer(t *testing.T, src LogSource) *Viewer {
+	t.Helper()
+	v, err := NewViewerWithSource(config.LogViewerConfig{
+		Name: "java",
+		Parser: config.LogParserConfig{
+			Type:      "logfmt",
+			Timestamp: "ts",
+			Level:     "level",
+			Message:   "msg",
+			Multiline: &config.MultilineConfig{Start: `^ts=`, Timeout: "20ms"},
+		},
+	}, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestViewerMultilineLive(t *testing.T) {
+	v := multilineViewer(t, &continuousSource{replaySource{lines: javaLines}})
+	if err := v.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer v.Stop()
+
+	// The last entry is emitted by the flush timeout
+	waitForCondition(t, "two entries", func() bool { return v.buffer.Size() == 2 })
+	entries := v.buffer.Get(0)
+	if entries[0].Fields["stack"] != strings.Join(javaLines[1:4], "\n") {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].Message != "next request" {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+}
+
+func TestViewerMultilineHistorical(t *testing.T) {
+	v := multilineViewer(t, &historySource{historyLines: javaLines})
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	entries, err := v.GetHistoricalEntries(context.Background(), start, end, nil, 0, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || !strings.Contains(entries[0].Raw, "Server.java") {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	// Grep matches anywhere in the assembled entry
+	entries, err = v.GetHistoricalEntries(context.Background(), start, end, nil, 0, "IllegalState", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Message != "request failed" {
+		t.Errorf("grep entries = %+v", entries)
+	}
+}
+
+func TestViewerMultilineBackward(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Join(append(append([]string{}, javaLines...), javaLines...), "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "active.log"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srcCfg := config.LogSourceConfig{Type: "file", Path: filepath.Join(dir, "anchor.log"), Current: "active.log"}
+	src, err := NewFileSource(srcCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := multilineViewer(t, src)
+
+	// One entry per page; the trace's start line is older than its
+	// continuation lines, so each page has to read past them.
+	var messages []string
+	cur := BackwardCursor{Offset: -1}
+	for i := 0; i < 10; i++ {
+		entries, next, done, _, err := v.ReadEntriesBackward(context.Background(), cur, 1, nil, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range entries {
+			messages = append(messages, e.Message)
+			if e.Message == "request failed" && !strings.Contains(e.Raw, "Server.java") {
+				t.Errorf("trace entry missing continuation lines: %q", e.Raw)
+			}
+		}
+		cur = next
+		if done {
+			break
+		}
+	}
+	want := "next request|request failed|next request|request failed"
+	if strings.Join(messages, "|") != want {
+		t.Errorf("messages = %q, want %q", messages, want)
+	}
+}
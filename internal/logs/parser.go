@@ -24,11 +24,12 @@ type LogParser interface {
 type ParserType string
 
 const (
-	ParserTypeJSON   ParserType = "json"
-	ParserTypeLogfmt ParserType = "logfmt"
-	ParserTypeRegex  ParserType = "regex"
-	ParserTypeSyslog ParserType = "syslog"
-	ParserTypeNone   ParserType = "none"
+	ParserTypeJSON     ParserType = "json"
+	ParserTypeLogfmt   ParserType = "logfmt"
+	ParserTypeRegex    ParserType = "regex"
+	ParserTypeSyslog   ParserType = "syslog"
+	ParserTypeJournald ParserType = "journald"
+	ParserTypeNone     ParserType = "none"
 )
 
 // TimestampExtractor is an optional interface a parser may implement to
@@ -45,8 +46,26 @@ type TimestampExtractor interface {
 	ExtractTimestamp(line string) (time.Time, bool)
 }
 
-// NewParser creates a new LogParser from configuration.
+// NewParser creates a new LogParser from configuration. With a multiline
+// config, the parser also accepts newline-joined texts assembled by a
+// LineAssembler.
 func NewParser(cfg config.LogParserConfig) (LogParser, error) {
+	parser, err := newLineParser(cfg)
+	if err != nil || cfg.Multiline == nil {
+		return parser, err
+	}
+	if _, err := NewMultiline(cfg.Multiline); err != nil {
+		return nil, err
+	}
+	stack := cfg.Stack
+	if stack == "" {
+		stack = defaultStackField
+	}
+	return &multilineParser{inner: parser, stack: stack}, nil
+}
+
+// newLineParser creates the parser for a single line.
+func newLineParser(cfg config.LogParserConfig) (LogParser, error) {
 	switch ParserType(cfg.Type) {
 	case ParserTypeJSON, "":
 		return NewJSONParser(cfg), nil
@@ -56,6 +75,8 @@ func NewParser(cfg config.LogParserConfig) (LogParser, error) {
 		return NewRegexParser(cfg)
 	case ParserTypeSyslog:
 		return NewSyslogParser(cfg), nil
+	case ParserTypeJournald:
+		return NewJournaldParser(cfg), nil
 	case ParserTypeNone:
 		return NewNoneParser(), nil
 	default:
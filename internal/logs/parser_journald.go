@@ -0,0 +1,153 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// journaldAliases maps journal fields to the shorter names they are also
+// stored under in LogEntry.Fields, so layouts and filters can say
+// "unit:nginx.service" instead of "_SYSTEMD_UNIT:nginx.service".
+var journaldAliases = map[string]string{
+	"_SYSTEMD_UNIT":     "unit",
+	"_HOSTNAME":         "hostname",
+	"SYSLOG_IDENTIFIER": "identifier",
+	"_PID":              "pid",
+	"_COMM":             "comm",
+}
+
+// JournaldParser parses the JSON export of `journalctl -o json`.
+type JournaldParser struct {
+	BaseParser
+}
+
+// NewJournaldParser creates a new journald parser.
+func NewJournaldParser(cfg config.LogParserConfig) *JournaldParser {
+	return &JournaldParser{BaseParser: BaseParser{cfg: cfg}}
+}
+
+// Name returns the parser name.
+func (p *JournaldParser) Name() string {
+	return "journald"
+}
+
+// ExtractTimestamp implements TimestampExtractor using the entry's
+// __REALTIME_TIMESTAMP (microseconds since the epoch).
+func (p *JournaldParser) ExtractTimestamp(line string) (time.Time, bool) {
+	var data map[string]any
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		return time.Time{}, false
+	}
+	return journalTimestamp(data)
+}
+
+// Parse parses a journal entry. MESSAGE becomes the message, PRIORITY the
+// level and __REALTIME_TIMESTAMP the timestamp. Every other field except the
+// journal's internal "__" addressing fields is kept in Fields under its
+// journal name, with the common trusted fields also aliased to short names.
+func (p *JournaldParser) Parse(line string) LogEntry {
+	entry := LogEntry{
+		Raw:       line,
+		Timestamp: time.Now(),
+		Fields:    make(map[string]any),
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		entry.Message = line
+		return entry
+	}
+
+	if ts, ok := journalTimestamp(data); ok {
+		entry.Timestamp = ts
+	}
+	if prio, ok := data["PRIORITY"].(string); ok {
+		entry.Level = priorityToLevel(prio)
+	}
+	entry.Message = journalString(data["MESSAGE"])
+
+	for k, v := range data {
+		if strings.HasPrefix(k, "__") {
+			continue
+		}
+		v = journalValue(v)
+		entry.Fields[k] = v
+		if alias, ok := journaldAliases[k]; ok {
+			entry.Fields[alias] = v
+		}
+	}
+
+	return entry
+}
+
+// journalTimestamp returns the entry's wall-clock time.
+func journalTimestamp(data map[string]any) (time.Time, bool) {
+	s, ok := data["__REALTIME_TIMESTAMP"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	usec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(usec), true
+}
+
+// journalValue normalizes a journal field value. journalctl encodes fields
+// that aren't valid UTF-8 as arrays of byte values, and fields that occur
+// more than once as arrays of values.
+func journalValue(v any) any {
+	arr, ok := v.([]any)
+	if !ok {
+		return v
+	}
+	if b, ok := journalBytes(arr); ok {
+		return string(b)
+	}
+	for i := range arr {
+		arr[i] = journalValue(arr[i])
+	}
+	return arr
+}
+
+// journalString returns a field value as a string. Repeated fields are
+// joined with newlines.
+func journalString(v any) string {
+	switch v := journalValue(v).(type) {
+	case string:
+		return v
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+// journalBytes decodes an array of byte values.
+func journalBytes(arr []any) ([]byte, bool) {
+	if len(arr) == 0 {
+		return nil, false
+	}
+	b := make([]byte, len(arr))
+	for i, v := range arr {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n > 255 || n != float64(int(n)) {
+			return nil, false
+		}
+		b[i] = byte(n)
+	}
+	return b, true
+}
@@ -437,3 +437,60 @@ func TestRegexParserNamedGroups(t *testing.T) {
 		t.Errorf("Message = %q, want %q", entry.Message, "Connection timeout")
 	}
 }
+
+func TestJournaldParser(t *testing.T) {
+	parser := NewJournaldParser(config.LogParserConfig{Type: "journald"})
+
+	line := `{"__CURSOR":"s=abc","__REALTIME_TIMESTAMP":"1700000000123456","PRIORITY":"4","MESSAGE":"disk almost full","_SYSTEMD_UNIT":"api.service","_HOSTNAME":"stage01","SYSLOG_IDENTIFIER":"api","_PID":"42","REQUEST_ID":"r-1"}`
+	entry := parser.Parse(line)
+
+	if entry.Message != "disk almost full" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	if entry.Level != LevelWarn {
+		t.Errorf("Level = %v, want %v", entry.Level, LevelWarn)
+	}
+	if want := time.UnixMicro(1700000000123456); !entry.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", entry.Timestamp, want)
+	}
+	for k, want := range map[string]string{
+		"unit":          "api.service",
+		"_SYSTEMD_UNIT": "api.service",
+		"hostname":      "stage01",
+		"identifier":    "api",
+		"pid":           "42",
+		"REQUEST_ID":    "r-1",
+	} {
+		if entry.Fields[k] != want {
+			t.Errorf("Fields[%q] = %v, want %q", k, entry.Fields[k], want)
+		}
+	}
+	if _, ok := entry.Fields["__CURSOR"]; ok {
+		t.Error("__CURSOR should not be stored in Fields")
+	}
+
+	ts, ok := parser.ExtractTimestamp(line)
+	if !ok || !ts.Equal(entry.Timestamp) {
+		t.Errorf("ExtractTimestamp() = %v, %v", ts, ok)
+	}
+
+	t.Run("binary message", func(t *testing.T) {
+		entry := parser.Parse(`{"MESSAGE":[104,105,10],"PRIORITY":"6"}`)
+		if entry.Message != "hi\n" {
+			t.Errorf("Message = %q, want %q", entry.Message, "hi\n")
+		}
+		if entry.Level != LevelInfo {
+			t.Errorf("Level = %v, want %v", entry.Level, LevelInfo)
+		}
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		entry := parser.Parse("-- No entries --")
+		if entry.Message != "-- No entries --" {
+			t.Errorf("Message = %q", entry.Message)
+		}
+		if _, ok := parser.ExtractTimestamp("-- No entries --"); ok {
+			t.Error("ExtractTimestamp() should fail for non-JSON")
+		}
+	})
+}
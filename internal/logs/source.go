@@ -71,6 +71,23 @@ type ContinuousSource interface {
 	ContinuousStart() bool
 }
 
+// ResidentSource is an optional capability for LogSources that receive
+// pushed data rather than pulling it (SyslogListenSource, OTLPSource,
+// PushSource). Nothing re-sends what arrives while such a source is stopped,
+// so the manager starts resident viewers as soon as it starts and never
+// stops them for being idle.
+type ResidentSource interface {
+	Resident() bool
+}
+
+// TraceIndex is an optional capability for sources that index their
+// retained lines by trace ID (OTLPSource). It lets trace searches fetch a
+// trace's lines directly instead of grepping history for the ID.
+type TraceIndex interface {
+	// TraceLines returns the raw lines recorded for a trace ID, or nil.
+	TraceLines(traceID string) []string
+}
+
 // SourceStatus represents the connection status of a log source.
 type SourceStatus struct {
 	Connected   bool      `json:"connected"`
@@ -160,11 +177,14 @@ type RotatedFile struct {
 type SourceType string
 
 const (
-	SourceTypeSSH        SourceType = "ssh"
-	SourceTypeFile       SourceType = "file"
-	SourceTypeCommand    SourceType = "command"
-	SourceTypeDocker     SourceType = "docker"
-	SourceTypeKubernetes SourceType = "kubernetes"
+	SourceTypeSSH          SourceType = "ssh"
+	SourceTypeFile         SourceType = "file"
+	SourceTypeCommand      SourceType = "command"
+	SourceTypeDocker       SourceType = "docker"
+	SourceTypeKubernetes   SourceType = "kubernetes"
+	SourceTypeJournald     SourceType = "journald"
+	SourceTypeSyslogListen SourceType = "syslog_listen"
+	SourceTypeOTLP         SourceType = "otlp"
 )
 
 // sourceBase provides the common fields and status-tracking methods shared
@@ -302,6 +322,12 @@ func NewSource(cfg config.LogSourceConfig) (LogSource, error) {
 		return NewDockerSource(cfg)
 	case SourceTypeKubernetes:
 		return NewKubernetesSource(cfg)
+	case SourceTypeJournald:
+		return NewJournaldSource(cfg)
+	case SourceTypeSyslogListen:
+		return NewSyslogListenSource(cfg)
+	case SourceTypeOTLP:
+		return NewOTLPSource(cfg)
 	default:
 		return nil, fmt.Errorf("unknown source type: %s", cfg.Type)
 	}
@@ -0,0 +1,232 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// JournaldSource reads entries from the systemd journal with
+// `journalctl -o json`, either locally or on a remote host over SSH.
+type JournaldSource struct {
+	sourceBase
+}
+
+// NewJournaldSource creates a new journald log source.
+func NewJournaldSource(cfg config.LogSourceConfig) (*JournaldSource, error) {
+	for _, unit := range cfg.Units {
+		if strings.TrimSpace(unit) == "" {
+			return nil, fmt.Errorf("journald source has an empty unit name")
+		}
+	}
+	return &JournaldSource{sourceBase: sourceBase{cfg: cfg}}, nil
+}
+
+// Name returns the source name.
+func (s *JournaldSource) Name() string {
+	name := "journald"
+	if s.cfg.Host != "" {
+		name += ":" + s.cfg.Host
+	}
+	if len(s.cfg.Units) > 0 {
+		name += ":" + strings.Join(s.cfg.Units, ",")
+	}
+	return name
+}
+
+// Start begins streaming journal entries.
+func (s *JournaldSource) Start(ctx context.Context, lineCh chan<- string, errCh chan<- error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(lineCh)
+
+		s.streamJournal(ctx, lineCh, errCh)
+	}()
+
+	return nil
+}
+
+// streamJournal runs journalctl and streams its output.
+func (s *JournaldSource) streamJournal(ctx context.Context, lineCh chan<- string, errCh chan<- error) {
+	var extra []string
+	if s.cfg.Since != "" {
+		extra = append(extra, "--since", journalSince(s.cfg.Since, time.Now()))
+	} else {
+		extra = append(extra, "-n", "1000")
+	}
+	if s.cfg.IsFollow() {
+		extra = append(extra, "-f")
+	}
+
+	cmd := s.command(ctx, extra...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		s.setError(err)
+		errCh <- fmt.Errorf("creating stdout pipe: %w", err)
+		return
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		s.setError(err)
+		errCh <- fmt.Errorf("creating stderr pipe: %w", err)
+		return
+	}
+
+	if err := cmd.Start(); err != nil {
+		s.setError(err)
+		errCh <- fmt.Errorf("starting journalctl: %w", err)
+		return
+	}
+
+	s.setConnected()
+
+	// Keep the last stderr line so an early exit (unknown unit, missing
+	// permissions, SSH failure) reports why.
+	var stderrMu sync.Mutex
+	var lastStderr string
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// journalctl prints advisory hints about journal access
+			if line == "" || strings.HasPrefix(line, "Hint:") {
+				continue
+			}
+			stderrMu.Lock()
+			lastStderr = line
+			stderrMu.Unlock()
+		}
+	}()
+
+	s.forwardLines(ctx, stdout, lineCh, errCh)
+	<-stderrDone
+
+	// Always reap the process — on ctx cancel CommandContext has already
+	// killed it, and skipping Wait would leak a zombie plus the pipe FDs.
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() == nil {
+			stderrMu.Lock()
+			if lastStderr != "" {
+				err = fmt.Errorf("%w: %s", err, lastStderr)
+			}
+			stderrMu.Unlock()
+			s.setError(err)
+			errCh <- fmt.Errorf("journalctl exited: %w", err)
+		}
+	}
+}
+
+// command builds the journalctl invocation with the configured unit and
+// priority filters followed by extra.
+func (s *JournaldSource) command(ctx context.Context, extra ...string) *exec.Cmd {
+	args := journalctlArgs(s.cfg, extra...)
+	if s.cfg.Host == "" {
+		return exec.CommandContext(ctx, "journalctl", args...)
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	remoteCmd := "journalctl " + strings.Join(quoted, " ")
+	return exec.CommandContext(ctx, "ssh", s.cfg.Host, remoteCmd)
+}
+
+// journalctlArgs returns the journalctl arguments for cfg.
+func journalctlArgs(cfg config.LogSourceConfig, extra ...string) []string {
+	args := []string{"-o", "json", "--no-pager"}
+	for _, unit := range cfg.Units {
+		args = append(args, "-u", unit)
+	}
+	if cfg.Priority != "" {
+		args = append(args, "-p", cfg.Priority)
+	}
+	return append(args, extra...)
+}
+
+// journalSince converts a since value to a journalctl --since argument.
+// Go durations ("1h", "30m") become an absolute "@<unix seconds>" time;
+// anything else ("today", "2024-01-15 10:00") is passed through.
+func journalSince(since string, now time.Time) string {
+	if d, err := time.ParseDuration(since); err == nil {
+		return "@" + strconv.FormatInt(now.Add(-d).Unix(), 10)
+	}
+	return since
+}
+
+// ListRotatedFiles returns available rotated log files.
+// The journal manages its own storage, so there are none.
+func (s *JournaldSource) ListRotatedFiles(ctx context.Context) ([]RotatedFile, error) {
+	return nil, nil
+}
+
+// ReadRange reads journal entries from a time range using journalctl
+// --since and --until.
+func (s *JournaldSource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
+	_, _, _ = grep, grepBefore, grepAfter // grep filtering done client-side for journald
+	var extra []string
+	if !start.IsZero() {
+		extra = append(extra, "--since", "@"+strconv.FormatInt(start.Unix(), 10))
+	}
+	if !end.IsZero() {
+		// --until takes whole seconds; round up so the last partial second
+		// isn't cut off.
+		extra = append(extra, "--until", "@"+strconv.FormatInt(end.Add(time.Second-1).Unix(), 10))
+	}
+
+	cmd := s.command(ctx, extra...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("creating stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting journalctl: %w", err)
+	}
+
+	reader := bufio.NewReaderSize(stdout, 64*1024)
+	const maxLineSize = 1024 * 1024 // 1MB max line size
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if len(line) > 0 {
+			line = strings.TrimSuffix(line, "\n")
+			if len(line) > maxLineSize {
+				line = line[:maxLineSize] + "... [truncated]"
+			}
+			select {
+			case <-ctx.Done():
+				cmd.Process.Kill()
+				cmd.Wait()
+				return ctx.Err()
+			case lineCh <- line:
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF {
+				cmd.Wait()
+				return fmt.Errorf("reading: %w", readErr)
+			}
+			break
+		}
+	}
+
+	return cmd.Wait()
+}
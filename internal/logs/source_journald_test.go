@@ -0,0 +1,110 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestJournalctlArgs(t *testing.T) {
+	cfg := config.LogSourceConfig{
+		Type:     "journald",
+		Units:    []string{"api.service", "worker.service"},
+		Priority: "warning",
+	}
+	got := journalctlArgs(cfg, "-n", "1000", "-f")
+	want := []string{"-o", "json", "--no-pager", "-u", "api.service", "-u", "worker.service", "-p", "warning", "-n", "1000", "-f"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("journalctlArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestJournalSince(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if got := journalSince("1h", now); got != "@1699996400" {
+		t.Errorf("journalSince(1h) = %q, want @1699996400", got)
+	}
+	if got := journalSince("today", now); got != "today" {
+		t.Errorf("journalSince(today) = %q, want today", got)
+	}
+}
+
+func TestJournaldSourceName(t *testing.T) {
+	tests := []struct {
+		cfg  config.LogSourceConfig
+		want string
+	}{
+		{config.LogSourceConfig{Type: "journald"}, "journald"},
+		{config.LogSourceConfig{Type: "journald", Units: []string{"a.service", "b.service"}}, "journald:a.service,b.service"},
+		{config.LogSourceConfig{Type: "journald", Host: "stage01", Units: []string{"a.service"}}, "journald:stage01:a.service"},
+	}
+	for _, tt := range tests {
+		src, err := NewJournaldSource(tt.cfg)
+		if err != nil {
+			t.Fatalf("NewJournaldSource() error: %v", err)
+		}
+		if got := src.Name(); got != tt.want {
+			t.Errorf("Name() = %q, want %q", got, tt.want)
+		}
+	}
+
+	if _, err := NewJournaldSource(config.LogSourceConfig{Type: "journald", Units: []string{" "}}); err == nil {
+		t.Error("expected error for empty unit name")
+	}
+}
+
+// TestJournaldSourceStreams runs the source against a stub journalctl on
+// PATH that echoes its arguments back as a journal entry.
+func TestJournaldSourceStreams(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		`printf '{"__REALTIME_TIMESTAMP":"1700000000000000","PRIORITY":"3","MESSAGE":"%s","_SYSTEMD_UNIT":"api.service"}\n' "$*"` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "journalctl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	follow := false
+	src, err := NewJournaldSource(config.LogSourceConfig{
+		Type:   "journald",
+		Units:  []string{"api.service"},
+		Follow: &follow,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lineCh := make(chan string, 10)
+	errCh := make(chan error, 10)
+	if err := src.Start(ctx, lineCh, errCh); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer src.Stop()
+
+	var lines []string
+	for line := range lineCh {
+		lines = append(lines, line)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1: %v", len(lines), lines)
+	}
+
+	entry := NewJournaldParser(config.LogParserConfig{}).Parse(lines[0])
+	if entry.Message != "-o json --no-pager -u api.service -n 1000" {
+		t.Errorf("journalctl invoked with %q", entry.Message)
+	}
+	if entry.Level != LevelError {
+		t.Errorf("Level = %v, want %v", entry.Level, LevelError)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/otlp"
+)
+
+// defaultOTLPListen is the standard OTLP/HTTP port, bound to loopback since
+// the receiver is meant for local services.
+const defaultOTLPListen = "localhost:4318"
+
+// otlpRetainedEntries caps how many converted records and spans an
+// OTLPSource keeps for history and trace lookups.
+const otlpRetainedEntries = 100000
+
+// otlpReservedKeys are the top-level keys of a converted entry. Attributes
+// with the same name are stored as "attr.<name>" instead.
+var otlpReservedKeys = map[string]bool{
+	"time": true, "type": true, "level": true, "message": true,
+	"service": true, "scope": true, "trace_id": true, "span_id": true,
+	"parent_span_id": true, "span_kind": true, "duration_ms": true,
+	"end_time": true, "status": true, "status_message": true,
+	"event_name": true, "severity_number": true,
+}
+
+// OTLP entry types, stored in the "type" field.
+const (
+	OTLPTypeLog       = "log"
+	OTLPTypeSpan      = "span"
+	OTLPTypeSpanEvent = "span_event"
+)
+
+// OTLPSource runs an embedded OTLP receiver. Log records, spans, and span
+// events sent to it are converted to JSON lines with trace_id and span_id
+// fields. Converted lines are retained in memory and indexed by trace ID.
+type OTLPSource struct {
+	sourceBase
+	listen   string
+	retained *traceStore
+
+	sinkMu sync.RWMutex
+	sink   chan<- string
+	ctx    context.Context
+
+	addrMu sync.RWMutex
+	addr   net.Addr
+}
+
+// NewOTLPSource creates a new OTLP receiver source.
+func NewOTLPSource(cfg config.LogSourceConfig) (*OTLPSource, error) {
+	listen := cfg.Listen
+	if listen == "" {
+		listen = defaultOTLPListen
+	}
+	return &OTLPSource{
+		sourceBase: sourceBase{cfg: cfg},
+		listen:     listen,
+		retained:   newTraceStore(otlpRetainedEntries),
+	}, nil
+}
+
+// Name returns the source name.
+func (s *OTLPSource) Name() string {
+	return "otlp:" + s.listen
+}
+
+// ContinuousStart implements ContinuousSource: nothing is replayed when the
+// receiver starts.
+func (s *OTLPSource) ContinuousStart() bool {
+	return true
+}
+
+// Resident implements ResidentSource: exporters drop what they can't send,
+// so the receiver runs for as long as Trellis does.
+func (s *OTLPSource) Resident() bool {
+	return true
+}
+
+// Addr returns the bound address while the source is running, or nil.
+func (s *OTLPSource) Addr() net.Addr {
+	s.addrMu.RLock()
+	defer s.addrMu.RUnlock()
+	return s.addr
+}
+
+func (s *OTLPSource) setAddr(addr net.Addr) {
+	s.addrMu.Lock()
+	defer s.addrMu.Unlock()
+	s.addr = addr
+}
+
+// Start binds the listen address and begins accepting exports. Bind
+// failures are returned directly.
+func (s *OTLPSource) Start(ctx context.Context, lineCh chan<- string, errCh chan<- error) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", s.listen)
+	if err != nil {
+		cancel()
+		s.setError(err)
+		return fmt.Errorf("listening on %s: %w", s.listen, err)
+	}
+
+	// OTLP/gRPC clients speak HTTP/2 without TLS
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv := &http.Server{
+		Handler:           otlp.NewReceiver(s.receiveLogs, s.receiveSpans),
+		Protocols:         protocols,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	s.cancel = cancel
+	s.sinkMu.Lock()
+	s.sink = lineCh
+	s.ctx = ctx
+	s.sinkMu.Unlock()
+	s.setAddr(ln.Addr())
+	s.setConnected()
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.setError(err)
+			errCh <- fmt.Errorf("otlp receiver: %w", err)
+			cancel()
+		}
+	}()
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+
+		// In-flight handlers give up on ctx, so this returns promptly
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		srv.Shutdown(shutdownCtx)
+		done()
+
+		s.sinkMu.Lock()
+		s.sink = nil
+		close(lineCh)
+		s.sinkMu.Unlock()
+		s.setAddr(nil)
+	}()
+
+	return nil
+}
+
+// receiveLogs converts and forwards exported log records.
+func (s *OTLPSource) receiveLogs(records []otlp.LogRecord) {
+	for _, rec := range records {
+		ts := rec.Time
+		if ts.IsZero() {
+			ts = rec.ObservedTime
+		}
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+
+		fields := map[string]any{
+			"time":  ts.UTC().Format(time.RFC3339Nano),
+			"type":  OTLPTypeLog,
+			"level": otlp.SeverityLevel(rec.SeverityNumber, rec.SeverityText),
+		}
+		switch body := rec.Body.(type) {
+		case string:
+			fields["message"] = body
+		case nil:
+			fields["message"] = rec.EventName
+		default:
+			data, _ := json.Marshal(body)
+			fields["message"] = string(data)
+		}
+		if rec.SeverityNumber > 0 {
+			fields["severity_number"] = rec.SeverityNumber
+		}
+		if rec.EventName != "" {
+			fields["event_name"] = rec.EventName
+		}
+		addOTLPContext(fields, rec.Resource, rec.Scope, rec.TraceID, rec.SpanID, rec.Attributes)
+		s.emit(ts, rec.TraceID, fields)
+	}
+}
+
+// receiveSpans converts and forwards exported spans and their events.
+func (s *OTLPSource) receiveSpans(spans []otlp.Span) {
+	for _, span := range spans {
+		start := span.Start
+		if start.IsZero() {
+			start = time.Now()
+		}
+		level := "info"
+		if span.StatusCode == otlp.StatusCodeError {
+			level = "error"
+		}
+
+		fields := map[string]any{
+			"time":      start.UTC().Format(time.RFC3339Nano),
+			"type":      OTLPTypeSpan,
+			"level":     level,
+			"message":   span.Name,
+			"span_kind": otlp.SpanKindName(span.Kind),
+			"status":    otlp.StatusCodeName(span.StatusCode),
+		}
+		if !span.End.IsZero() && !span.End.Before(start) {
+			fields["end_time"] = span.End.UTC().Format(time.RFC3339Nano)
+			fields["duration_ms"] = float64(span.End.Sub(start).Microseconds()) / 1000
+		}
+		if span.ParentSpanID != "" {
+			fields["parent_span_id"] = span.ParentSpanID
+		}
+		if span.StatusMessage != "" {
+			fields["status_message"] = span.StatusMessage
+		}
+		addOTLPContext(fields, span.Resource, span.Scope, span.TraceID, span.SpanID, span.Attributes)
+		s.emit(start, span.TraceID, fields)
+
+		for _, ev := range span.Events {
+			ts := ev.Time
+			if ts.IsZero() {
+				ts = start
+			}
+			evLevel := "info"
+			if ev.Name == "exception" {
+				evLevel = "error"
+			}
+			evFields := map[string]any{
+				"time":    ts.UTC().Format(time.RFC3339Nano),
+				"type":    OTLPTypeSpanEvent,
+				"level":   evLevel,
+				"message": ev.Name,
+			}
+			addOTLPContext(evFields, span.Resource, span.Scope, span.TraceID, span.SpanID, ev.Attributes)
+			s.emit(ts, span.TraceID, evFields)
+		}
+	}
+}
+
+// addOTLPContext adds the resource, scope, IDs, and attributes shared by
+// every converted entry.
+func addOTLPContext(fields map[string]any, resource map[string]any, scope, traceID, spanID string, attrs map[string]any) {
+	if service := otlp.ServiceName(resource); service != "" {
+		fields["service"] = service
+	}
+	if scope != "" {
+		fields["scope"] = scope
+	}
+	if traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	if spanID != "" {
+		fields["span_id"] = spanID
+	}
+	for k, v := range attrs {
+		if otlpReservedKeys[k] {
+			k = "attr." + k
+		}
+		fields[k] = v
+	}
+}
+
+// emit retains a converted entry and forwards it to the viewer.
+func (s *OTLPSource) emit(ts time.Time, traceID string, fields map[string]any) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return
+	}
+	line := string(data)
+
+	s.sinkMu.RLock()
+	defer s.sinkMu.RUnlock()
+	if s.sink == nil {
+		return
+	}
+	s.retained.add(ts, traceID, line)
+	select {
+	case <-s.ctx.Done():
+	case s.sink <- line:
+		s.incrementLines()
+	}
+}
+
+// TraceLines implements TraceIndex.
+func (s *OTLPSource) TraceLines(traceID string) []string {
+	return s.retained.trace(traceID)
+}
+
+// ListRotatedFiles returns available rotated log files.
+// OTLP sources don't support rotated files.
+func (s *OTLPSource) ListRotatedFiles(ctx context.Context) ([]RotatedFile, error) {
+	return nil, nil
+}
+
+// ReadRange reads retained entries from a time range.
+func (s *OTLPSource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
+	_, _, _ = grep, grepBefore, grepAfter // grep filtering done client-side for OTLP
+	for _, line := range s.retained.rangeLines(start, end) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case lineCh <- line:
+		}
+	}
+	return nil
+}
+
+// traceStore is a bounded ring of lines indexed by trace ID. It backs the
+// history and trace lookups of sources that receive pushed entries.
+type traceStore struct {
+	mu      sync.RWMutex
+	max     int
+	items   []traceItem
+	start   int    // index of the oldest item once the ring is full
+	nextSeq uint64 // sequence number of the next item added
+	byTrace map[string][]uint64
+}
+
+type traceItem struct {
+	seq     uint64
+	ts      time.Time
+	traceID string
+	line    string
+}
+
+func newTraceStore(max int) *traceStore {
+	return &traceStore{max: max, byTrace: make(map[string][]uint64)}
+}
+
+func (st *traceStore) add(ts time.Time, traceID, line string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	item := traceItem{seq: st.nextSeq, ts: ts, traceID: traceID, line: line}
+	st.nextSeq++
+	if len(st.items) < st.max {
+		st.items = append(st.items, item)
+	} else {
+		// Evictions happen in sequence order, so the evicted item is the
+		// first one indexed under its trace.
+		old := st.items[st.start]
+		if ids := st.byTrace[old.traceID]; len(ids) > 0 && ids[0] == old.seq {
+			if len(ids) == 1 {
+				delete(st.byTrace, old.traceID)
+			} else {
+				st.byTrace[old.traceID] = ids[1:]
+			}
+		}
+		st.items[st.start] = item
+		st.start = (st.start + 1) % st.max
+	}
+	if traceID != "" {
+		st.byTrace[traceID] = append(st.byTrace[traceID], item.seq)
+	}
+}
+
+// trace returns the retained lines for a trace in arrival order.
+func (st *traceStore) trace(traceID string) []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	seqs := st.byTrace[traceID]
+	if len(seqs) == 0 {
+		return nil
+	}
+	oldest := st.nextSeq - uint64(len(st.items))
+	lines := make([]string, 0, len(seqs))
+	for _, seq := range seqs {
+		idx := (st.start + int(seq-oldest)) % len(st.items)
+		lines = append(lines, st.items[idx].line)
+	}
+	return lines
+}
+
+// rangeLines returns the retained lines with timestamps in [start, end] in
+// chronological order. Zero bounds are open.
+func (st *traceStore) rangeLines(start, end time.Time) []string {
+	st.mu.RLock()
+	var matched []traceItem
+	for i := range st.items {
+		item := st.items[(st.start+i)%len(st.items)]
+		if (!start.IsZero() && item.ts.Before(start)) || (!end.IsZero() && item.ts.After(end)) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	st.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].ts.Before(matched[j].ts) })
+	lines := make([]string, len(matched))
+	for i, item := range matched {
+		lines[i] = item.line
+	}
+	return lines
+}
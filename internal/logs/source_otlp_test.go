@@ -0,0 +1,168 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/otlp"
+)
+
+const (
+	otlpTestTrace = "5b8efff798038103d269b633813fc60c"
+	otlpTestRoot  = "eee19b7ec3c1b173"
+	otlpTestChild = "eee19b7ec3c1b174"
+)
+
+func TestOTLPSource(t *testing.T) {
+	src, err := NewOTLPSource(config.LogSourceConfig{Type: "otlp", Listen: "127.0.0.1:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Name() != "otlp:127.0.0.1:0" {
+		t.Errorf("Name() = %q", src.Name())
+	}
+
+	lineCh := make(chan string, 20)
+	errCh := make(chan error, 10)
+	if err := src.Start(context.Background(), lineCh, errCh); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer src.Stop()
+
+	start := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	resource := map[string]any{"service.name": "checkout"}
+	exp := otlp.NewExporter("http://" + src.Addr().String())
+	ctx := context.Background()
+	err = exp.ExportSpans(ctx, []otlp.Span{
+		{
+			TraceID: otlpTestTrace, SpanID: otlpTestRoot, Name: "GET /checkout", Kind: otlp.SpanKindServer,
+			Start: start, End: start.Add(40 * time.Millisecond), Resource: resource,
+		},
+		{
+			TraceID: otlpTestTrace, SpanID: otlpTestChild, ParentSpanID: otlpTestRoot, Name: "charge",
+			Start: start.Add(10 * time.Millisecond), End: start.Add(30 * time.Millisecond),
+			Events:     []otlp.SpanEvent{{Time: start.Add(29 * time.Millisecond), Name: "exception"}},
+			StatusCode: otlp.StatusCodeError, Resource: resource,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExportSpans() error: %v", err)
+	}
+	err = exp.ExportLogs(ctx, []otlp.LogRecord{{
+		Time: start.Add(5 * time.Millisecond), SeverityNumber: 13, Body: "slow request",
+		Attributes: map[string]any{"level": "custom", "rows": int64(3)},
+		TraceID:    otlpTestTrace, SpanID: otlpTestRoot, Resource: resource,
+	}})
+	if err != nil {
+		t.Fatalf("ExportLogs() error: %v", err)
+	}
+
+	var lines []map[string]any
+	for i := 0; i < 4; i++ {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(receiveLine(t, lineCh)), &fields); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fields)
+	}
+
+	root, child, event, record := lines[0], lines[1], lines[2], lines[3]
+	if root["type"] != "span" || root["message"] != "GET /checkout" || root["span_kind"] != "server" {
+		t.Errorf("root span = %v", root)
+	}
+	if root["duration_ms"] != 40.0 || root["service"] != "checkout" || root["trace_id"] != otlpTestTrace {
+		t.Errorf("root span = %v", root)
+	}
+	if child["parent_span_id"] != otlpTestRoot || child["level"] != "error" || child["status"] != "error" {
+		t.Errorf("child span = %v", child)
+	}
+	if event["type"] != "span_event" || event["level"] != "error" || event["span_id"] != otlpTestChild {
+		t.Errorf("span event = %v", event)
+	}
+	if record["type"] != "log" || record["level"] != "warn" || record["message"] != "slow request" {
+		t.Errorf("log record = %v", record)
+	}
+	if record["attr.level"] != "custom" || record["rows"] != 3.0 {
+		t.Errorf("log attributes = %v", record)
+	}
+
+	if got := len(src.TraceLines(otlpTestTrace)); got != 4 {
+		t.Errorf("TraceLines() returned %d lines, want 4", got)
+	}
+	if got := src.TraceLines("0000"); got != nil {
+		t.Errorf("TraceLines(unknown) = %v", got)
+	}
+
+	// History comes back in timestamp order, not arrival order
+	rangeCh := make(chan string, 10)
+	if err := src.ReadRange(ctx, start, start.Add(20*time.Millisecond), rangeCh, "", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	close(rangeCh)
+	var messages []string
+	for line := range rangeCh {
+		var fields map[string]any
+		json.Unmarshal([]byte(line), &fields)
+		messages = append(messages, fields["message"].(string))
+	}
+	if len(messages) != 3 || messages[0] != "GET /checkout" || messages[1] != "slow request" || messages[2] != "charge" {
+		t.Errorf("ReadRange messages = %v", messages)
+	}
+
+	// The viewer serves trace lookups from the index
+	viewer, err := NewViewerWithSource(config.LogViewerConfig{
+		Name:   "otel",
+		Parser: config.LogParserConfig{Type: "json", Timestamp: "time", Level: "level", Message: "message"},
+	}, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, _ := ParseFilter("type:span")
+	entries, ok := viewer.LookupTrace([]string{otlpTestTrace}, start, start.Add(time.Second), filter)
+	if !ok || len(entries) != 2 {
+		t.Fatalf("LookupTrace() = %d entries, ok=%v", len(entries), ok)
+	}
+	if entries[0].Source != "otel" || !entries[0].Timestamp.Equal(start) {
+		t.Errorf("entry = %+v", entries[0])
+	}
+}
+
+func TestTraceStoreEviction(t *testing.T) {
+	st := newTraceStore(3)
+	t0 := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		trace := "a"
+		if i%2 == 1 {
+			trace = "b"
+		}
+		st.add(t0.Add(time.Duration(i)*time.Second), trace, strconv.Itoa(i))
+	}
+
+	// Items 0 and 1 were evicted
+	if got := st.trace("a"); len(got) != 2 || got[0] != "2" || got[1] != "4" {
+		t.Errorf("trace(a) = %v", got)
+	}
+	if got := st.trace("b"); len(got) != 1 || got[0] != "3" {
+		t.Errorf("trace(b) = %v", got)
+	}
+	if got := st.rangeLines(time.Time{}, time.Time{}); len(got) != 3 || got[0] != "2" {
+		t.Errorf("rangeLines() = %v", got)
+	}
+}
+
+func TestViewerLookupTraceWithoutIndex(t *testing.T) {
+	viewer, err := NewViewerWithSource(config.LogViewerConfig{Name: "plain"}, NewServiceSource("svc", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := viewer.LookupTrace([]string{otlpTestTrace}, time.Time{}, time.Now(), nil); ok {
+		t.Error("expected LookupTrace to report no index")
+	}
+}
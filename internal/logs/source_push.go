@@ -0,0 +1,120 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// pushRetainedEntries caps how many entries a PushSource keeps for history
+// and trace lookups.
+const pushRetainedEntries = 50000
+
+// PushSource implements LogSource for entries written by Trellis itself,
+// such as proxy access logs. Entries are stored as JSON lines, retained in
+// memory and indexed by trace ID.
+type PushSource struct {
+	sourceBase
+	name     string
+	retained *traceStore
+
+	sinkMu sync.RWMutex
+	sink   chan<- string
+}
+
+// NewPushSource creates a new in-process source with the given name.
+func NewPushSource(name string) *PushSource {
+	return &PushSource{
+		name:     name,
+		retained: newTraceStore(pushRetainedEntries),
+	}
+}
+
+// Name returns the source name.
+func (s *PushSource) Name() string {
+	return s.name
+}
+
+// ContinuousStart implements ContinuousSource: nothing is replayed when the
+// source starts.
+func (s *PushSource) ContinuousStart() bool {
+	return true
+}
+
+// Resident implements ResidentSource: entries are only streamed to the
+// viewer while it runs, so it runs for as long as Trellis does.
+func (s *PushSource) Resident() bool {
+	return true
+}
+
+// Start forwards emitted entries to lineCh until ctx is cancelled.
+func (s *PushSource) Start(ctx context.Context, lineCh chan<- string, errCh chan<- error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.sinkMu.Lock()
+	s.sink = lineCh
+	s.sinkMu.Unlock()
+	s.setConnected()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		<-ctx.Done()
+		s.sinkMu.Lock()
+		s.sink = nil
+		close(lineCh)
+		s.sinkMu.Unlock()
+	}()
+	return nil
+}
+
+// Emit records an entry. Entries are retained whether or not the viewer is
+// running; a viewer that can't keep up misses them live but still finds
+// them in history and trace lookups, so Emit never blocks the caller.
+func (s *PushSource) Emit(ts time.Time, traceID string, fields map[string]any) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return
+	}
+	line := string(data)
+	s.retained.add(ts, traceID, line)
+
+	s.sinkMu.RLock()
+	defer s.sinkMu.RUnlock()
+	if s.sink == nil {
+		return
+	}
+	select {
+	case s.sink <- line:
+		s.incrementLines()
+	default:
+	}
+}
+
+// TraceLines implements TraceIndex.
+func (s *PushSource) TraceLines(traceID string) []string {
+	return s.retained.trace(traceID)
+}
+
+// ListRotatedFiles returns available rotated log files.
+// Push sources don't support rotated files.
+func (s *PushSource) ListRotatedFiles(ctx context.Context) ([]RotatedFile, error) {
+	return nil, nil
+}
+
+// ReadRange reads retained entries from a time range.
+func (s *PushSource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
+	_, _, _ = grep, grepBefore, grepAfter // grep filtering done client-side for push sources
+	for _, line := range s.retained.rangeLines(start, end) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case lineCh <- line:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestPushSource(t *testing.T) {
+	src := NewPushSource("proxy:8080")
+	if src.Name() != "proxy:8080" {
+		t.Errorf("Name() = %q", src.Name())
+	}
+
+	// Entries emitted before the source starts are kept for history
+	start := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+	src.Emit(start, "abc", map[string]any{"time": start.Format(time.RFC3339Nano), "message": "GET /", "trace_id": "abc"})
+
+	lineCh := make(chan string, 1)
+	if err := src.Start(context.Background(), lineCh, make(chan error, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if !src.Status().Connected {
+		t.Error("source not connected after Start")
+	}
+
+	t1 := start.Add(time.Second)
+	src.Emit(t1, "abc", map[string]any{"time": t1.Format(time.RFC3339Nano), "message": "POST /orders", "trace_id": "abc"})
+	// The channel is full; Emit drops the live line rather than block
+	t2 := start.Add(2 * time.Second)
+	src.Emit(t2, "", map[string]any{"time": t2.Format(time.RFC3339Nano), "message": "GET /health"})
+
+	if line := <-lineCh; line != `{"message":"POST /orders","time":"2026-01-15T10:30:01Z","trace_id":"abc"}` {
+		t.Errorf("streamed line = %s", line)
+	}
+	if got := src.Status().LinesRead; got != 1 {
+		t.Errorf("LinesRead = %d, want 1", got)
+	}
+	if got := len(src.TraceLines("abc")); got != 2 {
+		t.Errorf("TraceLines() returned %d lines, want 2", got)
+	}
+
+	rangeCh := make(chan string, 10)
+	if err := src.ReadRange(context.Background(), start, t2, rangeCh, "", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	close(rangeCh)
+	if got := len(rangeCh); got != 3 {
+		t.Errorf("ReadRange returned %d lines, want 3", got)
+	}
+
+	viewer, err := NewViewerWithSource(config.LogViewerConfig{
+		Name:   "proxy:8080",
+		Parser: config.LogParserConfig{Type: "json", Timestamp: "time", Message: "message", ID: "trace_id"},
+	}, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, ok := viewer.LookupTrace([]string{"abc"}, start, t2, nil)
+	if !ok || len(entries) != 2 || entries[1].Message != "POST /orders" {
+		t.Errorf("LookupTrace() = %+v, ok=%v", entries, ok)
+	}
+
+	src.Stop()
+	if _, open := <-lineCh; open {
+		t.Error("line channel not closed after Stop")
+	}
+}
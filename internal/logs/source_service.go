@@ -18,6 +18,13 @@ type ServiceLogProvider interface {
 	ServiceLogSize(name string) (int, error)
 }
 
+// ServiceLogRangeProvider is optionally implemented by a ServiceLogProvider
+// whose buffers are persisted, so ReadRange can reach lines that have already
+// scrolled out of memory.
+type ServiceLogRangeProvider interface {
+	ServiceLogRange(name string, start, end time.Time) ([]string, error)
+}
+
 // ServiceSource implements LogSource for in-memory service log buffers.
 // It reads log lines from a running service's LogBuffer via the ServiceLogProvider
 // interface, enabling the trace system to search dev service logs.
@@ -93,21 +100,12 @@ func (s *ServiceSource) HandlesGrep() bool { return true }
 
 // ReadRange reads log lines from the service's in-memory buffer.
 // It retrieves all lines from the buffer and sends them through the channel,
-// optionally filtering by grep pattern. Time filtering is handled by the
-// Viewer after parsing.
+// optionally filtering by grep pattern. If the provider implements
+// ServiceLogRangeProvider, lines written between start and end are read
+// instead, including persisted ones older than the buffer. Time filtering is
+// handled by the Viewer after parsing.
 func (s *ServiceSource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
-	// Get the buffer size so we can request all lines
-	size, err := s.provider.ServiceLogSize(s.serviceName)
-	if err != nil {
-		return err
-	}
-
-	if size == 0 {
-		return nil
-	}
-
-	// Get all lines from the buffer
-	lines, err := s.provider.ServiceLogs(s.serviceName, size)
+	lines, err := s.readLines(start, end)
 	if err != nil {
 		return err
 	}
@@ -154,3 +152,23 @@ func (s *ServiceSource) ReadRange(ctx context.Context, start, end time.Time, lin
 
 	return nil
 }
+
+// readLines returns the service's lines for ReadRange.
+func (s *ServiceSource) readLines(start, end time.Time) ([]string, error) {
+	if rp, ok := s.provider.(ServiceLogRangeProvider); ok {
+		return rp.ServiceLogRange(s.serviceName, start, end)
+	}
+
+	// Get the buffer size so we can request all lines
+	size, err := s.provider.ServiceLogSize(s.serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if size == 0 {
+		return nil, nil
+	}
+
+	// Get all lines from the buffer
+	return s.provider.ServiceLogs(s.serviceName, size)
+}
@@ -49,6 +49,20 @@ func (m *mockServiceLogProvider) ServiceLogSize(name string) (int, error) {
 	return len(lines), nil
 }
 
+// mockServiceLogRangeProvider adds ServiceLogRangeProvider, serving older
+// lines than its buffer holds.
+type mockServiceLogRangeProvider struct {
+	*mockServiceLogProvider
+	persisted []string
+	start     time.Time
+	end       time.Time
+}
+
+func (m *mockServiceLogRangeProvider) ServiceLogRange(name string, start, end time.Time) ([]string, error) {
+	m.start, m.end = start, end
+	return m.persisted, nil
+}
+
 func TestNewServiceSource(t *testing.T) {
 	provider := newMockProvider(nil)
 	src := NewServiceSource("api", provider)
@@ -165,6 +179,29 @@ func TestServiceSourceReadRange_BasicLines(t *testing.T) {
 	assert.Greater(t, status.BytesRead, int64(0))
 }
 
+func TestServiceSourceReadRange_PersistedLines(t *testing.T) {
+	provider := &mockServiceLogRangeProvider{
+		mockServiceLogProvider: newMockProvider(map[string][]string{"api": {"line 3"}}),
+		persisted:              []string{"line 1", "line 2", "line 3"},
+	}
+	src := NewServiceSource("api", provider)
+
+	start := time.Now().Add(-time.Hour)
+	end := time.Now()
+	lineCh := make(chan string, 10)
+	require.NoError(t, src.ReadRange(context.Background(), start, end, lineCh, "", 0, 0))
+	close(lineCh)
+
+	var received []string
+	for line := range lineCh {
+		received = append(received, line)
+	}
+
+	assert.Equal(t, []string{"line 1", "line 2", "line 3"}, received)
+	assert.Equal(t, start, provider.start)
+	assert.Equal(t, end, provider.end)
+}
+
 func TestServiceSourceReadRange_EmptyBuffer(t *testing.T) {
 	provider := newMockProvider(map[string][]string{
 		"api": {},
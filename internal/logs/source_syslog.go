@@ -0,0 +1,257 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// maxSyslogMessage caps a single syslog message. Longer newline-framed
+// messages are truncated; longer octet-counted frames close the connection.
+const maxSyslogMessage = 1024 * 1024
+
+// SyslogListenSource accepts syslog messages pushed to a local UDP or TCP
+// port. Messages are passed through as lines for the syslog parser.
+type SyslogListenSource struct {
+	sourceBase
+	network string
+
+	addrMu sync.RWMutex
+	addr   net.Addr
+}
+
+// NewSyslogListenSource creates a new syslog listener source.
+func NewSyslogListenSource(cfg config.LogSourceConfig) (*SyslogListenSource, error) {
+	if cfg.Listen == "" {
+		return nil, fmt.Errorf("syslog_listen source requires listen address")
+	}
+	network := strings.ToLower(cfg.Protocol)
+	switch network {
+	case "":
+		network = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("syslog_listen source protocol must be udp or tcp, got %q", cfg.Protocol)
+	}
+	return &SyslogListenSource{sourceBase: sourceBase{cfg: cfg}, network: network}, nil
+}
+
+// Name returns the source name.
+func (s *SyslogListenSource) Name() string {
+	return fmt.Sprintf("syslog:%s:%s", s.network, s.cfg.Listen)
+}
+
+// ContinuousStart implements ContinuousSource: messages are only received
+// once, so there is no backlog to replay on restart.
+func (s *SyslogListenSource) ContinuousStart() bool {
+	return true
+}
+
+// Resident implements ResidentSource: senders don't retry, so anything
+// pushed while the listener is closed would be lost.
+func (s *SyslogListenSource) Resident() bool {
+	return true
+}
+
+// Addr returns the bound address while the source is running, or nil.
+func (s *SyslogListenSource) Addr() net.Addr {
+	s.addrMu.RLock()
+	defer s.addrMu.RUnlock()
+	return s.addr
+}
+
+func (s *SyslogListenSource) setAddr(addr net.Addr) {
+	s.addrMu.Lock()
+	defer s.addrMu.Unlock()
+	s.addr = addr
+}
+
+// Start binds the listen address and begins receiving messages. Bind
+// failures are returned directly.
+func (s *SyslogListenSource) Start(ctx context.Context, lineCh chan<- string, errCh chan<- error) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var lc net.ListenConfig
+	var closer io.Closer
+	var run func()
+	switch s.network {
+	case "udp":
+		conn, err := lc.ListenPacket(ctx, "udp", s.cfg.Listen)
+		if err != nil {
+			cancel()
+			s.setError(err)
+			return fmt.Errorf("listening on udp %s: %w", s.cfg.Listen, err)
+		}
+		s.setAddr(conn.LocalAddr())
+		closer = conn
+		run = func() { s.receiveUDP(ctx, conn, lineCh, errCh) }
+	default:
+		ln, err := lc.Listen(ctx, "tcp", s.cfg.Listen)
+		if err != nil {
+			cancel()
+			s.setError(err)
+			return fmt.Errorf("listening on tcp %s: %w", s.cfg.Listen, err)
+		}
+		s.setAddr(ln.Addr())
+		closer = ln
+		run = func() { s.acceptTCP(ctx, ln, lineCh, errCh) }
+	}
+
+	s.cancel = cancel
+	s.setConnected()
+
+	// Closing the socket unblocks the reader on Stop
+	go func() {
+		<-ctx.Done()
+		closer.Close()
+	}()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(lineCh)
+		defer s.setAddr(nil)
+
+		run()
+	}()
+
+	return nil
+}
+
+// receiveUDP reads one message per datagram until the socket is closed.
+func (s *SyslogListenSource) receiveUDP(ctx context.Context, conn net.PacketConn, lineCh chan<- string, errCh chan<- error) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if n > 0 {
+			if !s.send(ctx, string(buf[:n]), lineCh) {
+				return
+			}
+		}
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.setError(err)
+				errCh <- fmt.Errorf("reading: %w", err)
+			}
+			return
+		}
+	}
+}
+
+// acceptTCP accepts connections until the listener is closed and waits for
+// their readers to finish.
+func (s *SyslogListenSource) acceptTCP(ctx context.Context, ln net.Listener, lineCh chan<- string, errCh chan<- error) {
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.setError(err)
+				errCh <- fmt.Errorf("accepting: %w", err)
+			}
+			return
+		}
+
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			s.readTCP(ctx, conn, lineCh)
+		}()
+	}
+}
+
+// readTCP reads messages from one connection. Both RFC 6587 framings are
+// accepted: octet counting ("<len> <msg>") and newline-delimited.
+func (s *SyslogListenSource) readTCP(ctx context.Context, conn net.Conn, lineCh chan<- string) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	reader := bufio.NewReaderSize(conn, 64*1024)
+	for {
+		msg, err := readSyslogFrame(reader)
+		if msg != "" {
+			if !s.send(ctx, msg, lineCh) {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readSyslogFrame reads the next message from a TCP syslog stream.
+func readSyslogFrame(r *bufio.Reader) (string, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return "", err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		prefix, err := r.ReadString(' ')
+		if err != nil {
+			return "", err
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(prefix, " "))
+		if err != nil {
+			return "", fmt.Errorf("invalid frame length %q", prefix)
+		}
+		if n > maxSyslogMessage {
+			return "", fmt.Errorf("frame length %d exceeds limit", n)
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+
+	line, err := r.ReadString('\n')
+	if len(line) > maxSyslogMessage {
+		line = line[:maxSyslogMessage] + "... [truncated]"
+	}
+	return line, err
+}
+
+// send forwards one message, trimming trailing newlines and NULs. Returns
+// false if the source is stopping.
+func (s *SyslogListenSource) send(ctx context.Context, msg string, lineCh chan<- string) bool {
+	msg = strings.TrimRight(msg, "\r\n\x00")
+	if msg == "" {
+		return true
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case lineCh <- msg:
+		s.incrementLines()
+		return true
+	}
+}
+
+// ListRotatedFiles returns available rotated log files.
+// Syslog listeners don't support rotated files.
+func (s *SyslogListenSource) ListRotatedFiles(ctx context.Context) ([]RotatedFile, error) {
+	return nil, nil
+}
+
+// ReadRange reads log lines from a time range.
+// Syslog listeners only see messages as they arrive.
+func (s *SyslogListenSource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
+	return fmt.Errorf("syslog_listen source does not support historical access")
+}
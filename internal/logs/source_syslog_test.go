@@ -0,0 +1,145 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func startSyslogListener(t *testing.T, protocol string) (*SyslogListenSource, chan string) {
+	t.Helper()
+	src, err := NewSyslogListenSource(config.LogSourceConfig{
+		Type:     "syslog_listen",
+		Listen:   "127.0.0.1:0",
+		Protocol: protocol,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lineCh := make(chan string, 10)
+	errCh := make(chan error, 10)
+	if err := src.Start(context.Background(), lineCh, errCh); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	t.Cleanup(func() { src.Stop() })
+
+	if !src.Status().Connected {
+		t.Error("expected source to be connected after Start")
+	}
+	return src, lineCh
+}
+
+func receiveLine(t *testing.T, lineCh chan string) string {
+	t.Helper()
+	select {
+	case line := <-lineCh:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return ""
+	}
+}
+
+func TestSyslogListenSourceUDP(t *testing.T) {
+	src, lineCh := startSyslogListener(t, "")
+	if src.Name() != "syslog:udp:127.0.0.1:0" {
+		t.Errorf("Name() = %q", src.Name())
+	}
+
+	conn, err := net.Dial("udp", src.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("<11>Jan 15 10:30:00 stage01 api: request failed\n"))
+
+	line := receiveLine(t, lineCh)
+	if line != "<11>Jan 15 10:30:00 stage01 api: request failed" {
+		t.Errorf("line = %q", line)
+	}
+	entry := NewSyslogParser(config.LogParserConfig{}).Parse(line)
+	if entry.Level != LevelError || entry.Fields["hostname"] != "stage01" {
+		t.Errorf("parsed entry = %+v", entry)
+	}
+}
+
+func TestSyslogListenSourceTCP(t *testing.T) {
+	src, lineCh := startSyslogListener(t, "tcp")
+
+	conn, err := net.Dial("tcp", src.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Newline framing, then octet counting with an embedded newline
+	framed := "<14>1 2024-01-15T10:30:00Z host app 1 - - two\nlines"
+	conn.Write([]byte("<13>Jan 15 10:30:00 host app: first\n"))
+	conn.Write([]byte(strconv.Itoa(len(framed)) + " " + framed))
+
+	if got := receiveLine(t, lineCh); got != "<13>Jan 15 10:30:00 host app: first" {
+		t.Errorf("first message = %q", got)
+	}
+	if got := receiveLine(t, lineCh); got != framed {
+		t.Errorf("second message = %q, want %q", got, framed)
+	}
+
+	src.Stop()
+	if src.Addr() != nil {
+		t.Error("Addr() should be nil after Stop")
+	}
+}
+
+func TestSyslogListenSourceBindError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	src, _ := NewSyslogListenSource(config.LogSourceConfig{
+		Type:     "syslog_listen",
+		Listen:   ln.Addr().String(),
+		Protocol: "tcp",
+	})
+	err = src.Start(context.Background(), make(chan string), make(chan error, 1))
+	if err == nil {
+		src.Stop()
+		t.Fatal("expected bind error")
+	}
+	if src.Status().Connected {
+		t.Error("source should not be connected after a bind error")
+	}
+}
+
+func TestReadSyslogFrame(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("5 hello<13>plain line\n3 abc"))
+	want := []string{"hello", "<13>plain line\n", "abc"}
+	for _, w := range want {
+		got, err := readSyslogFrame(r)
+		if err != nil {
+			t.Fatalf("readSyslogFrame() error: %v", err)
+		}
+		if got != w {
+			t.Errorf("readSyslogFrame() = %q, want %q", got, w)
+		}
+	}
+	if _, err := readSyslogFrame(r); err == nil {
+		t.Error("expected EOF")
+	}
+
+	r = bufio.NewReader(strings.NewReader("99999999 x"))
+	if _, err := readSyslogFrame(r); err == nil {
+		t.Error("expected error for oversized frame")
+	}
+}
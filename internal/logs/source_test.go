@@ -140,6 +140,45 @@ func TestNewSource(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			name: "journald source",
+			cfg: config.LogSourceConfig{
+				Type:  "journald",
+				Units: []string{"nginx.service"},
+			},
+			shouldErr: false,
+		},
+		{
+			name: "syslog_listen source",
+			cfg: config.LogSourceConfig{
+				Type:   "syslog_listen",
+				Listen: ":5514",
+			},
+			shouldErr: false,
+		},
+		{
+			name: "syslog_listen source missing listen",
+			cfg: config.LogSourceConfig{
+				Type: "syslog_listen",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "syslog_listen source bad protocol",
+			cfg: config.LogSourceConfig{
+				Type:     "syslog_listen",
+				Listen:   ":5514",
+				Protocol: "sctp",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "otlp source default listen",
+			cfg: config.LogSourceConfig{
+				Type: "otlp",
+			},
+			shouldErr: false,
+		},
 		{
 			name: "unknown source type",
 			cfg: config.LogSourceConfig{
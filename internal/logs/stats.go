@@ -0,0 +1,416 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+// histogramTargetBuckets is the approximate number of buckets AutoBucket
+// aims for when no bucket width is given.
+const histogramTargetBuckets = 60
+
+// histogramMaxBuckets caps the number of buckets a single query may produce
+// so a tiny bucket over a wide range can't exhaust memory.
+const histogramMaxBuckets = 10000
+
+// histogramBucketSizes are the widths AutoBucket chooses from.
+var histogramBucketSizes = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// StatsQuery describes an aggregation over log entries.
+type StatsQuery struct {
+	// GroupBy is the field entries are grouped by (e.g. "path", "level").
+	// Empty means no grouping.
+	GroupBy string
+	// Field is a numeric field summarized with avg/min/max and percentiles
+	// (e.g. "duration"). Values with duration suffixes are normalized to
+	// milliseconds, matching filter comparisons. Empty means count only.
+	Field string
+	// Top limits the number of groups returned, largest first. 0 means all.
+	Top int
+	// Bucket is the histogram bucket width. 0 means no histogram.
+	Bucket time.Duration
+	// Start and End bound the entries considered. Zero values are unbounded.
+	Start time.Time
+	End   time.Time
+}
+
+// ValueStats summarizes the numeric values of a field.
+type ValueStats struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+}
+
+// GroupStats holds the aggregate for one group-by value.
+type GroupStats struct {
+	Key    string      `json:"key"`
+	Count  int         `json:"count"`
+	Values *ValueStats `json:"values,omitempty"`
+}
+
+// HistogramBucket counts entries in one time bucket, broken down by level.
+type HistogramBucket struct {
+	Start  time.Time      `json:"start"`
+	Count  int            `json:"count"`
+	Levels map[string]int `json:"levels,omitempty"`
+}
+
+// StatsResult is the outcome of a StatsQuery.
+type StatsResult struct {
+	// Total is the number of entries that matched.
+	Total int `json:"total"`
+	// Oldest and Newest are the timestamps of the first and last matching entries.
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+	// GroupBy and Field echo the query.
+	GroupBy string `json:"group_by,omitempty"`
+	Field   string `json:"field,omitempty"`
+	// Groups are ordered by count, largest first.
+	Groups []GroupStats `json:"groups,omitempty"`
+	// Ungrouped counts entries that lacked the group-by field.
+	Ungrouped int `json:"ungrouped,omitempty"`
+	// OtherGroups is the number of groups dropped by the Top limit.
+	OtherGroups int `json:"other_groups,omitempty"`
+	// Values summarizes Field across all matching entries.
+	Values *ValueStats `json:"values,omitempty"`
+	// Bucket is the histogram bucket width (e.g. "1m0s").
+	Bucket string `json:"bucket,omitempty"`
+	// Histogram is ordered oldest first, with empty buckets filled in.
+	Histogram []HistogramBucket `json:"histogram,omitempty"`
+}
+
+// StatsAggregator accumulates entries for a StatsQuery. It is not safe for
+// concurrent use.
+type StatsAggregator struct {
+	query     StatsQuery
+	groupKey  *filterClause
+	valueKey  *filterClause
+	total     int
+	oldest    time.Time
+	newest    time.Time
+	groups    map[string]*groupAccumulator
+	ungrouped int
+	values    []float64
+	buckets   map[int64]*HistogramBucket
+}
+
+// groupAccumulator collects one group's count and values.
+type groupAccumulator struct {
+	count  int
+	values []float64
+}
+
+// NewStatsAggregator creates an aggregator for the query.
+func NewStatsAggregator(query StatsQuery) *StatsAggregator {
+	a := &StatsAggregator{
+		query:   query,
+		groups:  make(map[string]*groupAccumulator),
+		buckets: make(map[int64]*HistogramBucket),
+	}
+	// Field lookups share the filter's rules for built-in names
+	// (message, level, timestamp) and parsed fields.
+	if query.GroupBy != "" {
+		a.groupKey = &filterClause{field: query.GroupBy}
+	}
+	if query.Field != "" {
+		a.valueKey = &filterClause{field: query.Field}
+	}
+	return a
+}
+
+// Add folds an entry into the aggregate. Entries outside the query's time
+// range are ignored.
+func (a *StatsAggregator) Add(entry LogEntry) {
+	if !a.query.Start.IsZero() && entry.Timestamp.Before(a.query.Start) {
+		return
+	}
+	if !a.query.End.IsZero() && entry.Timestamp.After(a.query.End) {
+		return
+	}
+
+	a.total++
+	if a.oldest.IsZero() || entry.Timestamp.Before(a.oldest) {
+		a.oldest = entry.Timestamp
+	}
+	if entry.Timestamp.After(a.newest) {
+		a.newest = entry.Timestamp
+	}
+
+	value, hasValue := a.numericValue(entry)
+	if hasValue {
+		a.values = append(a.values, value)
+	}
+
+	if a.groupKey != nil {
+		key, ok := a.groupKey.fieldValue(entry)
+		if !ok {
+			a.ungrouped++
+		} else {
+			g := a.groups[key]
+			if g == nil {
+				g = &groupAccumulator{}
+				a.groups[key] = g
+			}
+			g.count++
+			if hasValue {
+				g.values = append(g.values, value)
+			}
+		}
+	}
+
+	if a.query.Bucket > 0 {
+		start := entry.Timestamp.Truncate(a.query.Bucket)
+		b := a.buckets[start.UnixNano()]
+		if b == nil {
+			if len(a.buckets) >= histogramMaxBuckets {
+				return
+			}
+			b = &HistogramBucket{Start: start}
+			a.buckets[start.UnixNano()] = b
+		}
+		b.Count++
+		if entry.Level != "" {
+			if b.Levels == nil {
+				b.Levels = make(map[string]int)
+			}
+			b.Levels[string(entry.Level)]++
+		}
+	}
+}
+
+// numericValue extracts the query's value field as a number.
+func (a *StatsAggregator) numericValue(entry LogEntry) (float64, bool) {
+	if a.valueKey == nil {
+		return 0, false
+	}
+	s, ok := a.valueKey.fieldValue(entry)
+	if !ok {
+		return 0, false
+	}
+	v, err := parseNumber(s)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
+
+// Result returns the aggregate over the entries added so far.
+func (a *StatsAggregator) Result() StatsResult {
+	result := StatsResult{
+		Total:     a.total,
+		Oldest:    a.oldest,
+		Newest:    a.newest,
+		GroupBy:   a.query.GroupBy,
+		Field:     a.query.Field,
+		Ungrouped: a.ungrouped,
+	}
+
+	if a.valueKey != nil {
+		result.Values = summarizeValues(a.values)
+	}
+
+	if a.groupKey != nil {
+		result.Groups = make([]GroupStats, 0, len(a.groups))
+		for key, g := range a.groups {
+			gs := GroupStats{Key: key, Count: g.count}
+			if a.valueKey != nil {
+				gs.Values = summarizeValues(g.values)
+			}
+			result.Groups = append(result.Groups, gs)
+		}
+		sort.Slice(result.Groups, func(i, j int) bool {
+			if result.Groups[i].Count != result.Groups[j].Count {
+				return result.Groups[i].Count > result.Groups[j].Count
+			}
+			return result.Groups[i].Key < result.Groups[j].Key
+		})
+		if a.query.Top > 0 && len(result.Groups) > a.query.Top {
+			result.OtherGroups = len(result.Groups) - a.query.Top
+			result.Groups = result.Groups[:a.query.Top]
+		}
+	}
+
+	if a.query.Bucket > 0 {
+		result.Bucket = a.query.Bucket.String()
+		result.Histogram = a.histogram()
+	}
+
+	return result
+}
+
+// histogram returns the buckets oldest first, filling gaps with empty buckets
+// so the series can be plotted directly. When the query has a time range the
+// series spans all of it.
+func (a *StatsAggregator) histogram() []HistogramBucket {
+	from, to := a.oldest, a.newest
+	if !a.query.Start.IsZero() {
+		from = a.query.Start
+	}
+	if !a.query.End.IsZero() {
+		to = a.query.End
+	}
+	if from.IsZero() || to.Before(from) {
+		return []HistogramBucket{}
+	}
+
+	bucket := a.query.Bucket
+	first := from.Truncate(bucket)
+	last := to.Truncate(bucket)
+	n := int(last.Sub(first)/bucket) + 1
+	if n > histogramMaxBuckets {
+		n = histogramMaxBuckets
+	}
+
+	result := make([]HistogramBucket, 0, n)
+	for i := 0; i < n; i++ {
+		start := first.Add(time.Duration(i) * bucket)
+		if b, ok := a.buckets[start.UnixNano()]; ok {
+			result = append(result, *b)
+		} else {
+			result = append(result, HistogramBucket{Start: start})
+		}
+	}
+	return result
+}
+
+// summarizeValues computes count, sum, min, max, avg and nearest-rank
+// percentiles. It returns a zero summary for no values.
+func summarizeValues(values []float64) *ValueStats {
+	stats := &ValueStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	for _, v := range sorted {
+		stats.Sum += v
+	}
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	stats.Avg = stats.Sum / float64(len(sorted))
+	stats.P50 = percentile(sorted, 50)
+	stats.P95 = percentile(sorted, 95)
+	stats.P99 = percentile(sorted, 99)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// AutoBucket picks a histogram bucket width that splits the span between
+// start and end into roughly histogramTargetBuckets buckets.
+func AutoBucket(start, end time.Time) time.Duration {
+	span := end.Sub(start)
+	for _, size := range histogramBucketSizes {
+		if span/size <= histogramTargetBuckets {
+			return size
+		}
+	}
+	return histogramBucketSizes[len(histogramBucketSizes)-1]
+}
+
+// ComputeStats aggregates a slice of entries.
+func ComputeStats(entries []LogEntry, query StatsQuery) StatsResult {
+	agg := NewStatsAggregator(query)
+	for _, e := range entries {
+		agg.Add(e)
+	}
+	return agg.Result()
+}
+
+// BufferStats aggregates the viewer's buffered entries that match filter.
+// When the query has a time range, entries are read with GetRange so
+// persisted entries older than the in-memory ring are included. A negative
+// query bucket picks one automatically from the matched time span.
+func (v *Viewer) BufferStats(filter *Filter, query StatsQuery) StatsResult {
+	var entries []LogEntry
+	if !query.Start.IsZero() || !query.End.IsZero() {
+		end := query.End
+		if end.IsZero() {
+			end = time.Now()
+		}
+		entries = v.buffer.GetRange(query.Start, end, 0)
+	} else {
+		entries = v.buffer.Get(0)
+	}
+
+	if filter != nil && !filter.IsEmpty() {
+		matched := entries[:0:0]
+		for _, e := range entries {
+			if filter.Match(e) {
+				matched = append(matched, e)
+			}
+		}
+		entries = matched
+	}
+
+	if query.Bucket < 0 {
+		query.Bucket = 0
+		if len(entries) > 0 {
+			from, to := query.Start, query.End
+			if from.IsZero() {
+				from = entries[0].Timestamp
+			}
+			if to.IsZero() {
+				to = entries[len(entries)-1].Timestamp
+			}
+			query.Bucket = AutoBucket(from, to)
+		}
+	}
+
+	return ComputeStats(entries, query)
+}
+
+// HistoricalStats aggregates entries read from the source's history files
+// between start and end. Filtering and grep behave as in
+// StreamHistoricalEntries. A negative query bucket picks one automatically
+// from the time range.
+func (v *Viewer) HistoricalStats(ctx context.Context, start, end time.Time, filter *Filter, grep string, query StatsQuery) (StatsResult, error) {
+	query.Start, query.End = start, end
+	if query.Bucket < 0 {
+		query.Bucket = AutoBucket(start, end)
+	}
+
+	agg := NewStatsAggregator(query)
+	err := v.StreamHistoricalEntries(ctx, start, end, filter, 0, grep, 0, 0, func(e LogEntry) error {
+		agg.Add(e)
+		return nil
+	})
+	if err != nil {
+		return StatsResult{}, err
+	}
+	return agg.Result(), nil
+}
@@ -0,0 +1,224 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// historySource serves a fixed set of lines from ReadRange.
+type historySource struct {
+	replaySource
+	historyLines []string
+}
+
+func (s *historySource) ReadRange(ctx context.Context, start, end time.Time, lineCh chan<- string, grep string, grepBefore, grepAfter int) error {
+	for _, l := range s.historyLines {
+		select {
+		case lineCh <- l:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func statsEntries(base time.Time) []LogEntry {
+	var entries []LogEntry
+	for i := 0; i < 100; i++ {
+		path := "/api/users"
+		if i%4 == 0 {
+			path = "/api/orders"
+		}
+		level := LevelInfo
+		if i%10 == 0 {
+			level = LevelError
+		}
+		fields := map[string]any{
+			"path":     path,
+			"duration": fmt.Sprintf("%dms", i+1),
+		}
+		if i == 99 {
+			delete(fields, "path")
+		}
+		entries = append(entries, LogEntry{
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Level:     level,
+			Message:   "request",
+			Fields:    fields,
+		})
+	}
+	return entries
+}
+
+func TestComputeStatsGroupsAndPercentiles(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	result := ComputeStats(statsEntries(base), StatsQuery{GroupBy: "path", Field: "duration"})
+
+	if result.Total != 100 {
+		t.Errorf("Total = %d, want 100", result.Total)
+	}
+	if result.Ungrouped != 1 {
+		t.Errorf("Ungrouped = %d, want 1", result.Ungrouped)
+	}
+	if len(result.Groups) != 2 {
+		t.Fatalf("len(Groups) = %d, want 2", len(result.Groups))
+	}
+	if result.Groups[0].Key != "/api/users" || result.Groups[0].Count != 74 {
+		t.Errorf("Groups[0] = %s/%d, want /api/users/74", result.Groups[0].Key, result.Groups[0].Count)
+	}
+	if result.Groups[1].Key != "/api/orders" || result.Groups[1].Count != 25 {
+		t.Errorf("Groups[1] = %s/%d, want /api/orders/25", result.Groups[1].Key, result.Groups[1].Count)
+	}
+
+	v := result.Values
+	if v == nil {
+		t.Fatal("Values is nil")
+	}
+	if v.Count != 100 || v.Min != 1 || v.Max != 100 {
+		t.Errorf("Values count/min/max = %d/%v/%v, want 100/1/100", v.Count, v.Min, v.Max)
+	}
+	if v.Avg != 50.5 {
+		t.Errorf("Avg = %v, want 50.5", v.Avg)
+	}
+	if v.P50 != 50 || v.P95 != 95 || v.P99 != 99 {
+		t.Errorf("percentiles = %v/%v/%v, want 50/95/99", v.P50, v.P95, v.P99)
+	}
+	if result.Groups[1].Values == nil || result.Groups[1].Values.Min != 1 {
+		t.Errorf("per-group values missing or wrong: %+v", result.Groups[1].Values)
+	}
+}
+
+func TestComputeStatsTopAndDurations(t *testing.T) {
+	var entries []LogEntry
+	for i, path := range []string{"/a", "/a", "/a", "/b", "/b", "/c"} {
+		entries = append(entries, LogEntry{
+			Timestamp: time.Now(),
+			Fields:    map[string]any{"path": path, "latency": []string{"1s", "500ms", "2m", "250", "abc", "1.5s"}[i]},
+		})
+	}
+
+	result := ComputeStats(entries, StatsQuery{GroupBy: "path", Field: "latency", Top: 2})
+	if len(result.Groups) != 2 || result.OtherGroups != 1 {
+		t.Errorf("groups = %d, other = %d; want 2, 1", len(result.Groups), result.OtherGroups)
+	}
+	// Durations normalize to milliseconds; unparseable values are skipped
+	if result.Values.Count != 5 {
+		t.Errorf("Values.Count = %d, want 5", result.Values.Count)
+	}
+	if result.Values.Max != 120000 || result.Values.Min != 250 {
+		t.Errorf("min/max = %v/%v, want 250/120000", result.Values.Min, result.Values.Max)
+	}
+}
+
+func TestComputeStatsHistogram(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	entries := []LogEntry{
+		{Timestamp: base.Add(5 * time.Second), Level: LevelError},
+		{Timestamp: base.Add(10 * time.Second), Level: LevelInfo},
+		{Timestamp: base.Add(3*time.Minute + time.Second), Level: LevelError},
+	}
+
+	result := ComputeStats(entries, StatsQuery{Bucket: time.Minute})
+	if result.Bucket != "1m0s" {
+		t.Errorf("Bucket = %q, want 1m0s", result.Bucket)
+	}
+	if len(result.Histogram) != 4 {
+		t.Fatalf("len(Histogram) = %d, want 4 (gaps filled)", len(result.Histogram))
+	}
+	first := result.Histogram[0]
+	if !first.Start.Equal(base) || first.Count != 2 || first.Levels["error"] != 1 || first.Levels["info"] != 1 {
+		t.Errorf("Histogram[0] = %+v", first)
+	}
+	if result.Histogram[1].Count != 0 || result.Histogram[2].Count != 0 {
+		t.Errorf("gap buckets not empty: %+v %+v", result.Histogram[1], result.Histogram[2])
+	}
+	if result.Histogram[3].Count != 1 {
+		t.Errorf("Histogram[3].Count = %d, want 1", result.Histogram[3].Count)
+	}
+
+	// An explicit range spans the whole window and excludes entries outside it
+	ranged := ComputeStats(entries, StatsQuery{Bucket: time.Minute, Start: base, End: base.Add(10*time.Minute - time.Second)})
+	if len(ranged.Histogram) != 10 {
+		t.Errorf("len(ranged Histogram) = %d, want 10", len(ranged.Histogram))
+	}
+	limited := ComputeStats(entries, StatsQuery{Start: base.Add(time.Minute)})
+	if limited.Total != 1 {
+		t.Errorf("Total with Start = %d, want 1", limited.Total)
+	}
+}
+
+func TestAutoBucket(t *testing.T) {
+	base := time.Now()
+	tests := []struct {
+		span time.Duration
+		want time.Duration
+	}{
+		{30 * time.Second, time.Second},
+		{time.Hour, time.Minute},
+		{3 * time.Hour, 5 * time.Minute},
+		{24 * time.Hour, 30 * time.Minute},
+		{365 * 24 * time.Hour, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := AutoBucket(base, base.Add(tt.span)); got != tt.want {
+			t.Errorf("AutoBucket(%v) = %v, want %v", tt.span, got, tt.want)
+		}
+	}
+}
+
+func TestViewerStats(t *testing.T) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	entries := statsEntries(base)
+
+	source := &historySource{}
+	for _, e := range entries {
+		source.historyLines = append(source.historyLines, fmt.Sprintf(`{"time":%q,"level":%q,"msg":"request","path":"%v","duration":"%v"}`,
+			e.Timestamp.Format(time.RFC3339), e.Level, e.Fields["path"], e.Fields["duration"]))
+	}
+
+	cfg := config.LogViewerConfig{
+		Name:   "api",
+		Parser: config.LogParserConfig{Type: "json", Timestamp: "time", Level: "level", Message: "msg"},
+		Buffer: config.LogBufferConfig{MaxEntries: 1000},
+	}
+	viewer, err := NewViewerWithSource(cfg, source)
+	if err != nil {
+		t.Fatalf("NewViewerWithSource failed: %v", err)
+	}
+	for _, e := range entries {
+		viewer.buffer.Add(e)
+	}
+
+	filter, err := ParseFilter("level:error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffered := viewer.BufferStats(filter, StatsQuery{GroupBy: "path", Bucket: -1})
+	if buffered.Total != 10 {
+		t.Errorf("buffer Total = %d, want 10", buffered.Total)
+	}
+	if buffered.Bucket != "5s" {
+		t.Errorf("buffer auto bucket = %q, want 5s", buffered.Bucket)
+	}
+
+	history, err := viewer.HistoricalStats(context.Background(), base, base.Add(time.Hour), filter, "", StatsQuery{GroupBy: "path", Bucket: -1})
+	if err != nil {
+		t.Fatalf("HistoricalStats failed: %v", err)
+	}
+	if history.Total != 10 {
+		t.Errorf("history Total = %d, want 10", history.Total)
+	}
+	if history.Bucket != "1m0s" {
+		t.Errorf("history auto bucket = %q, want 1m0s", history.Bucket)
+	}
+	if len(history.Groups) == 0 || history.Groups[0].Key != "/api/orders" {
+		t.Errorf("history groups = %+v", history.Groups)
+	}
+}
@@ -0,0 +1,360 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStoreSegmentSize = 10000
+	storeSegmentPrefix      = "seg-"
+	storeSegmentSuffix      = ".jsonl"
+	maxStoredLineBytes      = 4 << 20
+)
+
+// EntryStore is an append-only on-disk log of entries. Entries are written
+// as JSON lines to fixed-size segment files named by their first sequence
+// number; the oldest segments are deleted once the store exceeds maxEntries.
+// Sequence numbers are stored with each entry so they survive restarts.
+type EntryStore struct {
+	mu          sync.Mutex
+	dir         string
+	maxEntries  int
+	segmentSize int
+	segments    []*storeSegment // Oldest first
+	current     *os.File        // Append handle for the newest segment (opened lazily)
+	total       int
+	lastSeq     uint64
+	closed      bool
+}
+
+// storeSegment describes one segment file.
+type storeSegment struct {
+	name     string
+	firstSeq uint64
+	lastSeq  uint64
+	oldest   time.Time
+	newest   time.Time
+	count    int
+}
+
+// OpenEntryStore opens (or creates) an entry store in dir that retains up
+// to maxEntries entries.
+func OpenEntryStore(dir string, maxEntries int) (*EntryStore, error) {
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("entry store max entries must be positive")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create log store directory: %w", err)
+	}
+
+	segmentSize := defaultStoreSegmentSize
+	if maxEntries/4 < segmentSize {
+		// Keep at least a few segments so trimming doesn't discard most of the store
+		segmentSize = maxEntries / 4
+		if segmentSize < 1 {
+			segmentSize = 1
+		}
+	}
+
+	s := &EntryStore{
+		dir:         dir,
+		maxEntries:  maxEntries,
+		segmentSize: segmentSize,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load scans existing segment files.
+func (s *EntryStore) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read log store directory: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, storeSegmentPrefix) || !strings.HasSuffix(name, storeSegmentSuffix) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, storeSegmentPrefix), storeSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seg := &storeSegment{name: name, firstSeq: first}
+		if err := s.scanSegment(seg, func(entry LogEntry) {
+			seg.count++
+			seg.note(entry)
+		}); err != nil {
+			return err
+		}
+		if seg.count == 0 {
+			os.Remove(filepath.Join(s.dir, name))
+			continue
+		}
+		s.segments = append(s.segments, seg)
+		s.total += seg.count
+		if seg.lastSeq > s.lastSeq {
+			s.lastSeq = seg.lastSeq
+		}
+	}
+	sort.Slice(s.segments, func(i, j int) bool {
+		return s.segments[i].firstSeq < s.segments[j].firstSeq
+	})
+	return nil
+}
+
+// note updates the segment's sequence and time bounds for an entry.
+func (seg *storeSegment) note(entry LogEntry) {
+	if entry.Sequence > seg.lastSeq {
+		seg.lastSeq = entry.Sequence
+	}
+	if seg.oldest.IsZero() || entry.Timestamp.Before(seg.oldest) {
+		seg.oldest = entry.Timestamp
+	}
+	if entry.Timestamp.After(seg.newest) {
+		seg.newest = entry.Timestamp
+	}
+}
+
+// Append writes entries to the store. Entries must already carry their
+// sequence numbers.
+func (s *EntryStore) Append(entries ...LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("log store closed")
+	}
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		var seg *storeSegment
+		if n := len(s.segments); n > 0 {
+			seg = s.segments[n-1]
+		}
+		if seg == nil || seg.count >= s.segmentSize {
+			s.closeCurrent()
+			seg = &storeSegment{
+				name:     fmt.Sprintf("%s%020d%s", storeSegmentPrefix, entry.Sequence, storeSegmentSuffix),
+				firstSeq: entry.Sequence,
+			}
+			s.segments = append(s.segments, seg)
+		}
+		if s.current == nil {
+			f, err := os.OpenFile(filepath.Join(s.dir, seg.name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return err
+			}
+			s.current = f
+		}
+		if _, err := s.current.Write(line); err != nil {
+			return err
+		}
+		seg.count++
+		seg.note(entry)
+		s.total++
+		if entry.Sequence > s.lastSeq {
+			s.lastSeq = entry.Sequence
+		}
+	}
+
+	return s.trim()
+}
+
+// trim deletes the oldest segments while the store holds more than
+// maxEntries without them. Caller must hold s.mu.
+func (s *EntryStore) trim() error {
+	for len(s.segments) > 1 && s.total-s.segments[0].count >= s.maxEntries {
+		oldest := s.segments[0]
+		if err := os.Remove(filepath.Join(s.dir, oldest.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.total -= oldest.count
+		s.segments = s.segments[1:]
+	}
+	return nil
+}
+
+// Reset deletes all stored entries. The last sequence number is kept so
+// later appends continue from it.
+func (s *EntryStore) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closeCurrent()
+	for _, seg := range s.segments {
+		if err := os.Remove(filepath.Join(s.dir, seg.name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	s.segments = nil
+	s.total = 0
+	return nil
+}
+
+// LastSequence returns the highest stored sequence number.
+func (s *EntryStore) LastSequence() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastSeq
+}
+
+// Size returns the number of stored entries.
+func (s *EntryStore) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Recent returns the newest n entries in chronological order.
+func (s *EntryStore) Recent(n int) ([]LogEntry, error) {
+	return s.Before(^uint64(0), n)
+}
+
+// Before returns up to limit of the newest entries with sequence less than
+// beforeSeq, in chronological order. A limit of 0 means no limit.
+func (s *EntryStore) Before(beforeSeq uint64, limit int) ([]LogEntry, error) {
+	segments := s.snapshot()
+
+	var reversed []LogEntry
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		if seg.firstSeq >= beforeSeq {
+			continue
+		}
+		var segEntries []LogEntry
+		if err := s.scanSegment(&seg, func(entry LogEntry) {
+			if entry.Sequence < beforeSeq {
+				segEntries = append(segEntries, entry)
+			}
+		}); err != nil {
+			return nil, err
+		}
+		for j := len(segEntries) - 1; j >= 0; j-- {
+			reversed = append(reversed, segEntries[j])
+			if limit > 0 && len(reversed) >= limit {
+				return reverseEntries(reversed), nil
+			}
+		}
+	}
+	return reverseEntries(reversed), nil
+}
+
+// Range returns entries with timestamps in [start, end], in chronological
+// order. A limit of 0 means no limit.
+func (s *EntryStore) Range(start, end time.Time, limit int) ([]LogEntry, error) {
+	segments := s.snapshot()
+
+	var result []LogEntry
+	for _, seg := range segments {
+		if seg.newest.Before(start) || seg.oldest.After(end) {
+			continue
+		}
+		done := false
+		if err := s.scanSegment(&seg, func(entry LogEntry) {
+			if done || entry.Timestamp.Before(start) || entry.Timestamp.After(end) {
+				return
+			}
+			result = append(result, entry)
+			if limit > 0 && len(result) >= limit {
+				done = true
+			}
+		}); err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Close releases the append handle. Later appends fail; reads still work.
+func (s *EntryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.closeCurrent()
+	return nil
+}
+
+// snapshot copies the segment metadata so reads can proceed without the lock.
+func (s *EntryStore) snapshot() []storeSegment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	segments := make([]storeSegment, len(s.segments))
+	for i, seg := range s.segments {
+		segments[i] = *seg
+	}
+	return segments
+}
+
+// scanSegment decodes each entry in a segment, skipping lines that fail to
+// decode (e.g. a partial write from a crash).
+func (s *EntryStore) scanSegment(seg *storeSegment, fn func(LogEntry)) error {
+	f, err := os.Open(filepath.Join(s.dir, seg.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxStoredLineBytes)
+	for scanner.Scan() {
+		var entry LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		fn(entry)
+	}
+	return scanner.Err()
+}
+
+// closeCurrent closes the append handle; the next Append reopens it.
+func (s *EntryStore) closeCurrent() {
+	if s.current != nil {
+		s.current.Close()
+		s.current = nil
+	}
+}
+
+// reverseEntries reverses entries in place and returns them.
+func reverseEntries(entries []LogEntry) []LogEntry {
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries
+}
+
+// StoreDirName converts a viewer or service name into a safe directory name.
+func StoreDirName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,232 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T, dir string, maxEntries int) *EntryStore {
+	t.Helper()
+	store, err := OpenEntryStore(dir, maxEntries)
+	if err != nil {
+		t.Fatalf("OpenEntryStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func appendSeq(t *testing.T, store *EntryStore, base time.Time, from, to int) {
+	t.Helper()
+	for i := from; i <= to; i++ {
+		entry := LogEntry{
+			Sequence:  uint64(i),
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Raw:       fmt.Sprintf("line %d", i),
+		}
+		if err := store.Append(entry); err != nil {
+			t.Fatalf("Append(%d) failed: %v", i, err)
+		}
+	}
+}
+
+func sequences(entries []LogEntry) []uint64 {
+	seqs := make([]uint64, len(entries))
+	for i, e := range entries {
+		seqs[i] = e.Sequence
+	}
+	return seqs
+}
+
+func TestEntryStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+
+	store := openTestStore(t, dir, 100)
+	appendSeq(t, store, base, 1, 10)
+	store.Close()
+
+	reopened := openTestStore(t, dir, 100)
+	if got := reopened.LastSequence(); got != 10 {
+		t.Errorf("LastSequence() = %d, want 10", got)
+	}
+	if got := reopened.Size(); got != 10 {
+		t.Errorf("Size() = %d, want 10", got)
+	}
+
+	recent, err := reopened.Recent(3)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if fmt.Sprint(sequences(recent)) != "[8 9 10]" {
+		t.Errorf("Recent(3) = %v, want [8 9 10]", sequences(recent))
+	}
+	if recent[2].Raw != "line 10" {
+		t.Errorf("Raw = %q, want %q", recent[2].Raw, "line 10")
+	}
+}
+
+func TestEntryStoreBeforeAndRange(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), 100)
+	base := time.Now().Add(-time.Hour)
+	appendSeq(t, store, base, 1, 50)
+
+	before, err := store.Before(20, 5)
+	if err != nil {
+		t.Fatalf("Before failed: %v", err)
+	}
+	if fmt.Sprint(sequences(before)) != "[15 16 17 18 19]" {
+		t.Errorf("Before(20, 5) = %v", sequences(before))
+	}
+
+	all, err := store.Before(4, 0)
+	if err != nil {
+		t.Fatalf("Before failed: %v", err)
+	}
+	if fmt.Sprint(sequences(all)) != "[1 2 3]" {
+		t.Errorf("Before(4, 0) = %v", sequences(all))
+	}
+
+	ranged, err := store.Range(base.Add(10*time.Second), base.Add(14*time.Second), 0)
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if fmt.Sprint(sequences(ranged)) != "[10 11 12 13 14]" {
+		t.Errorf("Range = %v", sequences(ranged))
+	}
+
+	limited, err := store.Range(base, base.Add(time.Hour), 2)
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if fmt.Sprint(sequences(limited)) != "[1 2]" {
+		t.Errorf("limited Range = %v", sequences(limited))
+	}
+}
+
+func TestEntryStoreTrimsOldestSegments(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestStore(t, dir, 20) // segment size 5
+	appendSeq(t, store, time.Now(), 1, 42)
+
+	if got := store.Size(); got < 20 || got > 25 {
+		t.Errorf("Size() = %d, want between 20 and 25", got)
+	}
+	recent, err := store.Recent(0)
+	if err != nil {
+		t.Fatalf("Recent failed: %v", err)
+	}
+	if recent[len(recent)-1].Sequence != 42 {
+		t.Errorf("newest = %d, want 42", recent[len(recent)-1].Sequence)
+	}
+	if recent[0].Sequence <= 1 {
+		t.Errorf("oldest = %d, want oldest entries trimmed", recent[0].Sequence)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "seg-*.jsonl"))
+	if len(files) > 5 {
+		t.Errorf("%d segment files remain, want at most 5", len(files))
+	}
+}
+
+func TestEntryStoreSkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"sequence":1,"timestamp":"2026-01-01T00:00:00Z","raw":"ok"}` + "\n" + `{"sequence":2,"ra`
+	if err := os.WriteFile(filepath.Join(dir, "seg-00000000000000000001.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := openTestStore(t, dir, 100)
+	if got := store.Size(); got != 1 {
+		t.Errorf("Size() = %d, want 1", got)
+	}
+}
+
+func TestEntryStoreResetKeepsSequence(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), 100)
+	appendSeq(t, store, time.Now(), 1, 5)
+
+	if err := store.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if got := store.Size(); got != 0 {
+		t.Errorf("Size() after Reset = %d, want 0", got)
+	}
+	if got := store.LastSequence(); got != 5 {
+		t.Errorf("LastSequence() after Reset = %d, want 5", got)
+	}
+}
+
+func TestEntryStoreAppendAfterClose(t *testing.T) {
+	store := openTestStore(t, t.TempDir(), 100)
+	store.Close()
+	if err := store.Append(LogEntry{Sequence: 1}); err == nil {
+		t.Error("Append after Close should fail")
+	}
+}
+
+func TestStoreDirName(t *testing.T) {
+	tests := map[string]string{
+		"nginx":         "nginx",
+		"svc:api":       "svc_api",
+		"../etc/passwd": "___etc_passwd",
+		"a b.c":         "a_b_c",
+	}
+	for in, want := range tests {
+		if got := StoreDirName(in); got != want {
+			t.Errorf("StoreDirName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBufferPersistence(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+
+	store := openTestStore(t, dir, 1000)
+	buf := NewBuffer(10)
+	if err := buf.AttachStore(store); err != nil {
+		t.Fatalf("AttachStore failed: %v", err)
+	}
+	for i := 0; i < 30; i++ {
+		buf.Add(LogEntry{Timestamp: base.Add(time.Duration(i) * time.Second), Raw: fmt.Sprintf("line %d", i)})
+	}
+	store.Close()
+
+	// Reload: newest entries fill the ring and sequences continue
+	reopened := openTestStore(t, dir, 1000)
+	restored := NewBuffer(10)
+	if err := restored.AttachStore(reopened); err != nil {
+		t.Fatalf("AttachStore failed: %v", err)
+	}
+	if got := restored.Size(); got != 10 {
+		t.Errorf("Size() = %d, want 10", got)
+	}
+	if got := restored.CurrentSequence(); got != 30 {
+		t.Errorf("CurrentSequence() = %d, want 30", got)
+	}
+	if next := restored.Add(LogEntry{Timestamp: base.Add(time.Minute)}); next.Sequence != 31 {
+		t.Errorf("next sequence = %d, want 31", next.Sequence)
+	}
+
+	// Scrollback past the ring is served from disk
+	before := restored.GetBefore(25, 8)
+	if fmt.Sprint(sequences(before)) != "[17 18 19 20 21 22 23 24]" {
+		t.Errorf("GetBefore(25, 8) = %v", sequences(before))
+	}
+	before = restored.GetBefore(3, 0)
+	if fmt.Sprint(sequences(before)) != "[1 2]" {
+		t.Errorf("GetBefore(3, 0) = %v", sequences(before))
+	}
+
+	// A range that starts before the ring comes from disk
+	ranged := restored.GetRange(base.Add(2*time.Second), base.Add(5*time.Second), 0)
+	if fmt.Sprint(sequences(ranged)) != "[3 4 5 6]" {
+		t.Errorf("GetRange = %v", sequences(ranged))
+	}
+}
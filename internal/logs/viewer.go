@@ -8,6 +8,7 @@ import (
 	"errors"
 	"log"
 	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,14 +18,17 @@ import (
 // Viewer coordinates a log source, parser, and buffer.
 // It streams entries to subscribers and manages the lifecycle.
 type Viewer struct {
-	name    string
-	cfg     config.LogViewerConfig
-	source  LogSource
-	parser  LogParser
-	deriver *Deriver
-	buffer  *Buffer
+	name      string
+	cfg       config.LogViewerConfig
+	source    LogSource
+	parser    LogParser
+	multiline *Multiline // Nil unless parser.multiline is set
+	deriver   *Deriver
+	buffer    *Buffer
+	store     *EntryStore // Non-nil when buffer.persist is enabled
 
 	mu            sync.RWMutex
+	resume        map[string]int // Raw lines already stored, skipped while a restarted source replays its backlog
 	subscribers   map[chan<- LogEntry]struct{}
 	running       bool
 	cancel        context.CancelFunc
@@ -35,6 +39,10 @@ type Viewer struct {
 	rateBucketSec int64 // unix second of the newest bucket
 }
 
+// resumeWindow is how many of the newest persisted entries are compared
+// against a restarted source's replayed backlog.
+const resumeWindow = 5000
+
 // rateWindowSecs is the sliding window (in seconds) over which the ingest
 // rate is measured.
 const rateWindowSecs = 10
@@ -46,6 +54,24 @@ func NewViewer(cfg config.LogViewerConfig) (*Viewer, error) {
 		return nil, err
 	}
 
+	// Sources with a fixed wire format default to its parser
+	if cfg.Parser.Type == "" {
+		switch SourceType(cfg.Source.Type) {
+		case SourceTypeJournald:
+			cfg.Parser.Type = string(ParserTypeJournald)
+		case SourceTypeSyslogListen:
+			cfg.Parser.Type = string(ParserTypeSyslog)
+		case SourceTypeOTLP:
+			cfg.Parser = config.LogParserConfig{
+				Type:      string(ParserTypeJSON),
+				Timestamp: "time",
+				Level:     "level",
+				Message:   "message",
+				ID:        "trace_id",
+			}
+		}
+	}
+
 	return NewViewerWithSource(cfg, source)
 }
 
@@ -57,6 +83,10 @@ func NewViewerWithSource(cfg config.LogViewerConfig, source LogSource) (*Viewer,
 	if err != nil {
 		return nil, err
 	}
+	multiline, err := NewMultiline(cfg.Parser.Multiline)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create deriver for computed fields
 	var deriver *Deriver
@@ -74,6 +104,7 @@ func NewViewerWithSource(cfg config.LogViewerConfig, source LogSource) (*Viewer,
 		cfg:         cfg,
 		source:      source,
 		parser:      parser,
+		multiline:   multiline,
 		deriver:     deriver,
 		buffer:      NewBuffer(maxEntries),
 		subscribers: make(map[chan<- LogEntry]struct{}),
@@ -90,6 +121,35 @@ func (v *Viewer) Config() config.LogViewerConfig {
 	return v.cfg
 }
 
+// EnablePersistence backs the viewer's buffer with an on-disk store in dir
+// that retains up to maxEntries entries. Entries stored by a previous run are
+// reloaded with their sequence numbers. Call before Start.
+func (v *Viewer) EnablePersistence(dir string, maxEntries int) error {
+	store, err := OpenEntryStore(dir, maxEntries)
+	if err != nil {
+		return err
+	}
+	if err := v.buffer.AttachStore(store); err != nil {
+		store.Close()
+		return err
+	}
+	v.store = store
+	return nil
+}
+
+// IsPersistent reports whether the viewer's buffer is backed by disk.
+func (v *Viewer) IsPersistent() bool {
+	return v.store != nil
+}
+
+// ClosePersistence releases the on-disk store, if any.
+func (v *Viewer) ClosePersistence() error {
+	if v.store == nil {
+		return nil
+	}
+	return v.store.Close()
+}
+
 // Start begins streaming logs from the source.
 func (v *Viewer) Start(ctx context.Context) error {
 	v.mu.Lock()
@@ -112,8 +172,16 @@ func (v *Viewer) Start(ctx context.Context) error {
 	// byte-offset file reader. Start each run with a clean buffer; Clear
 	// preserves the sequence counter, and anything discarded is still
 	// readable from the file via the backward/history readers.
+	//
+	// Persisted viewers keep their history instead; the replayed backlog is
+	// matched against the newest stored lines and skipped until the first
+	// line that isn't already stored.
 	if cs, ok := v.source.(ContinuousSource); !ok || !cs.ContinuousStart() {
-		v.buffer.Clear()
+		if v.store != nil {
+			v.beginResume()
+		} else {
+			v.buffer.Clear()
+		}
 	}
 
 	lineCh := make(chan string, 1000)
@@ -148,6 +216,13 @@ func (v *Viewer) Stop() error {
 	return v.source.Stop()
 }
 
+// Resident reports whether the viewer's source must run continuously
+// rather than on demand. See ResidentSource.
+func (v *Viewer) Resident() bool {
+	rs, ok := v.source.(ResidentSource)
+	return ok && rs.Resident()
+}
+
 // IsRunning returns whether the viewer is running.
 func (v *Viewer) IsRunning() bool {
 	v.mu.RLock()
@@ -342,7 +417,29 @@ func (v *Viewer) ReadEntriesBackward(ctx context.Context, cursor BackwardCursor,
 	cur := cursor
 	var entries []LogEntry
 	skippedCompressed := false
-	for len(entries) < limit && scanned < maxLinesScanned {
+	// Continuation lines read before their entry's first line (which is
+	// older, so in a later batch). Reading continues until it's found.
+	var carry []string
+	addEntry := func(raw string) {
+		e := v.parser.Parse(raw)
+		e.Source = v.name
+		if v.deriver != nil {
+			v.deriver.Apply(&e)
+		}
+		if filter != nil && !filter.Match(e) {
+			return
+		}
+		entries = append(entries, e)
+	}
+	// History ran out (or the scan budget did) mid-entry; keep the
+	// orphaned lines as an entry of their own.
+	flushCarry := func() {
+		if len(carry) > 0 {
+			addEntry(strings.Join(carry, "\n"))
+			carry = nil
+		}
+	}
+	for (len(entries) < limit || len(carry) > 0) && scanned < maxLinesScanned {
 		want := limit - len(entries)
 		// With a filter, lines may be rejected at a high rate; ask for a
 		// larger raw batch to amortize per-call overhead. Without a
@@ -350,6 +447,11 @@ func (v *Viewer) ReadEntriesBackward(ctx context.Context, cursor BackwardCursor,
 		if filter != nil && want < 100 {
 			want = 100
 		}
+		// Past the limit we only need the first line of the carried
+		// entry; read one line at a time so the cursor stops right there.
+		if want <= 0 {
+			want = 1
+		}
 		res, err := reader.ReadBackward(ctx, cur, want)
 		if err != nil {
 			log.Printf("logs[%s]: ReadBackward error at cursor %+v: %v", v.name, cur, err)
@@ -360,24 +462,25 @@ func (v *Viewer) ReadEntriesBackward(ctx context.Context, cursor BackwardCursor,
 		if res.SkippedCompressed {
 			skippedCompressed = true
 		}
+		texts := res.Lines
+		completesCarry := len(carry) > 0
+		if v.multiline != nil {
+			texts, carry = v.multiline.Group(res.Lines, carry)
+			completesCarry = completesCarry && len(texts) > 0
+		}
 		// Parse + filter. Stop appending once we've reached `limit` even
 		// if there are more lines in this batch (only happens for
 		// filtered reads where we asked for more than `limit` raw lines).
-		for _, raw := range res.Lines {
-			if len(entries) >= limit {
-				break
-			}
-			e := v.parser.Parse(raw)
-			e.Source = v.name
-			if v.deriver != nil {
-				v.deriver.Apply(&e)
-			}
-			if filter != nil && !filter.Match(e) {
+		// The batch's last entry is kept regardless when it completes
+		// lines carried from the previous batch.
+		for i, raw := range texts {
+			if len(entries) >= limit && !(completesCarry && i == len(texts)-1) {
 				continue
 			}
-			entries = append(entries, e)
+			addEntry(raw)
 		}
 		if res.Done {
+			flushCarry()
 			return entries, cur, true, skippedCompressed, nil
 		}
 		if len(res.Lines) == 0 {
@@ -386,6 +489,7 @@ func (v *Viewer) ReadEntriesBackward(ctx context.Context, cursor BackwardCursor,
 			break
 		}
 	}
+	flushCarry()
 	return entries, cur, false, skippedCompressed, nil
 }
 
@@ -449,17 +553,17 @@ func (v *Viewer) StreamHistoricalEntries(ctx context.Context, start, end time.Ti
 	emitted := 0
 	linesReceived := 0
 	var fnErr error
-	for line := range lineCh {
-		linesReceived++
-
+	// emit parses and delivers one assembled entry, returning false once
+	// the stream should stop.
+	emit := func(text string) bool {
 		// Client-side grep fallback for sources that don't filter
 		// themselves. -B/-A context lines are not reconstructed here; that
 		// stays an SSH-only feature for now.
-		if grepRe != nil && !grepRe.MatchString(line) {
-			continue
+		if grepRe != nil && !grepRe.MatchString(text) {
+			return true
 		}
 
-		entry := v.parser.Parse(line)
+		entry := v.parser.Parse(text)
 		entry.Source = v.name
 
 		if v.deriver != nil {
@@ -467,24 +571,38 @@ func (v *Viewer) StreamHistoricalEntries(ctx context.Context, start, end time.Ti
 		}
 
 		if entry.Timestamp.Before(start) || entry.Timestamp.After(end) {
-			continue
+			return true
 		}
 
 		if filter != nil && !filter.Match(entry) {
-			continue
+			return true
 		}
 
 		if err := fn(entry); err != nil {
 			fnErr = err
-			break
+			return false
 		}
 		emitted++
 		if limit > 0 && emitted >= limit {
 			log.Printf("StreamHistoricalEntries: limit %d reached after %d lines", limit, linesReceived)
 			fnErr = errStreamLimitReached
+			return false
+		}
+		return true
+	}
+
+	asm := NewLineAssembler(v.multiline)
+	for line := range lineCh {
+		linesReceived++
+		if text, ok := asm.Add(line); ok && !emit(text) {
 			break
 		}
 	}
+	if fnErr == nil {
+		if text, ok := asm.Flush(); ok {
+			emit(text)
+		}
+	}
 
 	// Drain so the producer goroutine can exit
 	if fnErr != nil {
@@ -516,6 +634,38 @@ func (v *Viewer) GetHistoricalEntries(ctx context.Context, start, end time.Time,
 	return entries, err
 }
 
+// LookupTrace returns the entries recorded for the given trace IDs within
+// [start, end], read from the source's trace index. ok is false when the
+// source doesn't implement TraceIndex; callers should fall back to grep.
+func (v *Viewer) LookupTrace(traceIDs []string, start, end time.Time, filter *Filter) (entries []LogEntry, ok bool) {
+	index, ok := v.source.(TraceIndex)
+	if !ok {
+		return nil, false
+	}
+
+	for _, id := range traceIDs {
+		for _, line := range index.TraceLines(id) {
+			entry := v.parser.Parse(line)
+			entry.Source = v.name
+
+			if v.deriver != nil {
+				v.deriver.Apply(&entry)
+			}
+
+			if entry.Timestamp.Before(start) || entry.Timestamp.After(end) {
+				continue
+			}
+
+			if filter != nil && !filter.Match(entry) {
+				continue
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+	return entries, true
+}
+
 // ListRotatedFiles returns available rotated log files.
 func (v *Viewer) ListRotatedFiles(ctx context.Context) ([]RotatedFile, error) {
 	return v.source.ListRotatedFiles(ctx)
@@ -527,22 +677,78 @@ func (v *Viewer) Errors() <-chan error {
 }
 
 // processLines processes incoming log lines.
+// Continuation lines are folded into the entry before them; a pending entry
+// is emitted once the next entry starts or no line arrives for the
+// multiline timeout.
 func (v *Viewer) processLines(ctx context.Context, lineCh <-chan string) {
+	asm := NewLineAssembler(v.multiline)
+	flush := time.NewTimer(time.Hour)
+	flush.Stop()
+	defer flush.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-flush.C:
+			if text, ok := asm.Flush(); ok {
+				v.processLine(text)
+			}
 		case line, ok := <-lineCh:
 			if !ok {
+				if text, ok := asm.Flush(); ok {
+					v.processLine(text)
+				}
 				return
 			}
-			v.processLine(line)
+			if text, ok := asm.Add(line); ok {
+				v.processLine(text)
+			}
+			if asm.Pending() {
+				flush.Reset(v.multiline.Timeout())
+			}
 		}
 	}
 }
 
+// beginResume records the newest buffered lines so a replayed backlog can
+// be skipped.
+func (v *Viewer) beginResume() {
+	recent := v.buffer.Get(resumeWindow)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(recent) == 0 {
+		v.resume = nil
+		return
+	}
+	v.resume = make(map[string]int, len(recent))
+	for _, entry := range recent {
+		v.resume[entry.Raw]++
+	}
+}
+
+// skipReplayed reports whether line is part of a replayed backlog that is
+// already in the buffer. The first unseen line ends the replay.
+func (v *Viewer) skipReplayed(line string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.resume == nil {
+		return false
+	}
+	if v.resume[line] > 0 {
+		v.resume[line]--
+		return true
+	}
+	v.resume = nil
+	return false
+}
+
 // processLine parses a line and broadcasts to subscribers.
 func (v *Viewer) processLine(line string) {
+	if v.skipReplayed(line) {
+		return
+	}
+
 	entry := v.parser.Parse(line)
 	entry.Source = v.name
 
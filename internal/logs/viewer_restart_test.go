@@ -262,3 +262,64 @@ func TestViewerRestartKeepsBufferForContinuousSource(t *testing.T) {
 		t.Fatalf("final Stop failed: %v", err)
 	}
 }
+
+// TestViewerPersistedRestartSkipsReplayedBacklog: a persisted viewer keeps
+// its buffer across a full restart, and the replayed tail backlog that is
+// already on disk is skipped rather than stored again.
+func TestViewerPersistedRestartSkipsReplayedBacklog(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.LogViewerConfig{
+		Name:   "persisted-restart-test",
+		Buffer: config.LogBufferConfig{MaxEntries: 100, Persist: true},
+	}
+	ctx := context.Background()
+
+	first, err := NewViewerWithSource(cfg, &replaySource{lines: []string{"a", "b", "c"}})
+	if err != nil {
+		t.Fatalf("NewViewerWithSource failed: %v", err)
+	}
+	if err := first.EnablePersistence(dir, 1000); err != nil {
+		t.Fatalf("EnablePersistence failed: %v", err)
+	}
+	if err := first.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	waitForCondition(t, "first backlog buffered", func() bool {
+		return first.buffer.Size() == 3
+	})
+	first.Stop()
+	first.ClosePersistence()
+
+	// New process: the tail replays the last two lines plus one new line
+	second, err := NewViewerWithSource(cfg, &replaySource{lines: []string{"b", "c", "d"}})
+	if err != nil {
+		t.Fatalf("NewViewerWithSource failed: %v", err)
+	}
+	if err := second.EnablePersistence(dir, 1000); err != nil {
+		t.Fatalf("EnablePersistence failed: %v", err)
+	}
+	defer second.ClosePersistence()
+	if got := second.buffer.Size(); got != 3 {
+		t.Fatalf("restored %d entries, want 3", got)
+	}
+	if err := second.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer second.Stop()
+	waitForCondition(t, "new line buffered", func() bool {
+		return second.buffer.Size() == 4
+	})
+
+	time.Sleep(100 * time.Millisecond)
+	entries := second.buffer.Get(0)
+	var raws []string
+	for _, e := range entries {
+		raws = append(raws, e.Raw)
+	}
+	if fmt.Sprint(raws) != "[a b c d]" {
+		t.Fatalf("buffer = %v, want [a b c d]", raws)
+	}
+	if fmt.Sprint(sequences(entries)) != "[1 2 3 4]" {
+		t.Errorf("sequences = %v, want [1 2 3 4]", sequences(entries))
+	}
+}
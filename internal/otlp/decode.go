@@ -0,0 +1,941 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Content types accepted by the receiver.
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+// isJSON reports whether a Content-Type header selects the JSON encoding.
+func isJSON(contentType string) bool {
+	return strings.HasPrefix(strings.TrimSpace(strings.ToLower(contentType)), ContentTypeJSON)
+}
+
+// DecodeLogs decodes an ExportLogsServiceRequest in the encoding selected by
+// contentType (JSON for application/json, protobuf otherwise).
+func DecodeLogs(data []byte, contentType string) ([]LogRecord, error) {
+	if isJSON(contentType) {
+		return decodeLogsJSON(data)
+	}
+	return decodeLogsProto(data)
+}
+
+// DecodeTraces decodes an ExportTraceServiceRequest in the encoding selected
+// by contentType (JSON for application/json, protobuf otherwise).
+func DecodeTraces(data []byte, contentType string) ([]Span, error) {
+	if isJSON(contentType) {
+		return decodeTracesJSON(data)
+	}
+	return decodeTracesProto(data)
+}
+
+// unixNano converts OTLP nanoseconds since the epoch; 0 means unset.
+func unixNano(n uint64) time.Time {
+	if n == 0 || n > math.MaxInt64 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(n))
+}
+
+// idString hex-encodes a trace or span ID. All-zero IDs are invalid in
+// OTLP and treated as unset.
+func idString(b []byte) string {
+	for _, c := range b {
+		if c != 0 {
+			return hex.EncodeToString(b)
+		}
+	}
+	return ""
+}
+
+// --- protobuf ---
+
+func decodeLogsProto(data []byte) ([]LogRecord, error) {
+	var records []LogRecord
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return records, err
+		}
+		if field != 1 { // resource_logs
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := r.message(wire)
+		if err != nil {
+			return nil, err
+		}
+		if records, err = decodeResourceLogs(b, records); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func decodeResourceLogs(data []byte, records []LogRecord) ([]LogRecord, error) {
+	var resource map[string]any
+	var scopes [][]byte
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1: // resource
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			if resource, err = decodeResource(b); err != nil {
+				return nil, err
+			}
+		case 2: // scope_logs
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, b)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, scopeData := range scopes {
+		var scope string
+		var logs [][]byte
+		r := protoReader{buf: scopeData}
+		for {
+			field, wire, ok, err := r.next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			switch field {
+			case 1: // scope
+				b, err := r.message(wire)
+				if err != nil {
+					return nil, err
+				}
+				if scope, err = decodeScopeName(b); err != nil {
+					return nil, err
+				}
+			case 2: // log_records
+				b, err := r.message(wire)
+				if err != nil {
+					return nil, err
+				}
+				logs = append(logs, b)
+			default:
+				if err := r.skip(wire); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, b := range logs {
+			rec, err := decodeLogRecord(b)
+			if err != nil {
+				return nil, err
+			}
+			rec.Resource = resource
+			rec.Scope = scope
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+func decodeLogRecord(data []byte) (LogRecord, error) {
+	var rec LogRecord
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return rec, err
+		}
+		if !ok {
+			return rec, nil
+		}
+		switch field {
+		case 1, 11: // time_unix_nano, observed_time_unix_nano
+			v, err := r.uint(wire)
+			if err != nil {
+				return rec, err
+			}
+			if field == 1 {
+				rec.Time = unixNano(v)
+			} else {
+				rec.ObservedTime = unixNano(v)
+			}
+		case 2: // severity_number
+			v, err := r.uint(wire)
+			if err != nil {
+				return rec, err
+			}
+			rec.SeverityNumber = int(v)
+		case 3, 12, 9, 10: // severity_text, event_name, trace_id, span_id
+			b, err := r.message(wire)
+			if err != nil {
+				return rec, err
+			}
+			switch field {
+			case 3:
+				rec.SeverityText = string(b)
+			case 12:
+				rec.EventName = string(b)
+			case 9:
+				rec.TraceID = idString(b)
+			case 10:
+				rec.SpanID = idString(b)
+			}
+		case 5: // body
+			b, err := r.message(wire)
+			if err != nil {
+				return rec, err
+			}
+			if rec.Body, err = decodeAnyValue(b); err != nil {
+				return rec, err
+			}
+		case 6: // attributes
+			b, err := r.message(wire)
+			if err != nil {
+				return rec, err
+			}
+			if rec.Attributes == nil {
+				rec.Attributes = make(map[string]any)
+			}
+			if err := decodeKeyValue(b, rec.Attributes); err != nil {
+				return rec, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return rec, err
+			}
+		}
+	}
+}
+
+func decodeTracesProto(data []byte) ([]Span, error) {
+	var spans []Span
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return spans, err
+		}
+		if field != 1 { // resource_spans
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := r.message(wire)
+		if err != nil {
+			return nil, err
+		}
+		if spans, err = decodeResourceSpans(b, spans); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func decodeResourceSpans(data []byte, spans []Span) ([]Span, error) {
+	var resource map[string]any
+	var scopes [][]byte
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1: // resource
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			if resource, err = decodeResource(b); err != nil {
+				return nil, err
+			}
+		case 2: // scope_spans
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, b)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, scopeData := range scopes {
+		var scope string
+		var raw [][]byte
+		r := protoReader{buf: scopeData}
+		for {
+			field, wire, ok, err := r.next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			switch field {
+			case 1: // scope
+				b, err := r.message(wire)
+				if err != nil {
+					return nil, err
+				}
+				if scope, err = decodeScopeName(b); err != nil {
+					return nil, err
+				}
+			case 2: // spans
+				b, err := r.message(wire)
+				if err != nil {
+					return nil, err
+				}
+				raw = append(raw, b)
+			default:
+				if err := r.skip(wire); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, b := range raw {
+			span, err := decodeSpan(b)
+			if err != nil {
+				return nil, err
+			}
+			span.Resource = resource
+			span.Scope = scope
+			spans = append(spans, span)
+		}
+	}
+	return spans, nil
+}
+
+func decodeSpan(data []byte) (Span, error) {
+	var span Span
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return span, err
+		}
+		if !ok {
+			return span, nil
+		}
+		switch field {
+		case 1, 2, 4, 5: // trace_id, span_id, parent_span_id, name
+			b, err := r.message(wire)
+			if err != nil {
+				return span, err
+			}
+			switch field {
+			case 1:
+				span.TraceID = idString(b)
+			case 2:
+				span.SpanID = idString(b)
+			case 4:
+				span.ParentSpanID = idString(b)
+			case 5:
+				span.Name = string(b)
+			}
+		case 6, 7, 8: // kind, start_time_unix_nano, end_time_unix_nano
+			v, err := r.uint(wire)
+			if err != nil {
+				return span, err
+			}
+			switch field {
+			case 6:
+				span.Kind = int(v)
+			case 7:
+				span.Start = unixNano(v)
+			case 8:
+				span.End = unixNano(v)
+			}
+		case 9: // attributes
+			b, err := r.message(wire)
+			if err != nil {
+				return span, err
+			}
+			if span.Attributes == nil {
+				span.Attributes = make(map[string]any)
+			}
+			if err := decodeKeyValue(b, span.Attributes); err != nil {
+				return span, err
+			}
+		case 11: // events
+			b, err := r.message(wire)
+			if err != nil {
+				return span, err
+			}
+			ev, err := decodeSpanEvent(b)
+			if err != nil {
+				return span, err
+			}
+			span.Events = append(span.Events, ev)
+		case 15: // status
+			b, err := r.message(wire)
+			if err != nil {
+				return span, err
+			}
+			if err := decodeStatus(b, &span); err != nil {
+				return span, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return span, err
+			}
+		}
+	}
+}
+
+func decodeSpanEvent(data []byte) (SpanEvent, error) {
+	var ev SpanEvent
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return ev, err
+		}
+		if !ok {
+			return ev, nil
+		}
+		switch field {
+		case 1: // time_unix_nano
+			v, err := r.uint(wire)
+			if err != nil {
+				return ev, err
+			}
+			ev.Time = unixNano(v)
+		case 2: // name
+			b, err := r.message(wire)
+			if err != nil {
+				return ev, err
+			}
+			ev.Name = string(b)
+		case 3: // attributes
+			b, err := r.message(wire)
+			if err != nil {
+				return ev, err
+			}
+			if ev.Attributes == nil {
+				ev.Attributes = make(map[string]any)
+			}
+			if err := decodeKeyValue(b, ev.Attributes); err != nil {
+				return ev, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return ev, err
+			}
+		}
+	}
+}
+
+func decodeStatus(data []byte, span *Span) error {
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		switch field {
+		case 2: // message
+			b, err := r.message(wire)
+			if err != nil {
+				return err
+			}
+			span.StatusMessage = string(b)
+		case 3: // code
+			v, err := r.uint(wire)
+			if err != nil {
+				return err
+			}
+			span.StatusCode = int(v)
+		default:
+			if err := r.skip(wire); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// decodeResource returns a Resource's attributes.
+func decodeResource(data []byte) (map[string]any, error) {
+	attrs := make(map[string]any)
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return attrs, nil
+		}
+		if field != 1 { // attributes
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		b, err := r.message(wire)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeKeyValue(b, attrs); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// decodeScopeName returns an InstrumentationScope's name.
+func decodeScopeName(data []byte) (string, error) {
+	var name string
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return name, err
+		}
+		if field != 1 {
+			if err := r.skip(wire); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b, err := r.message(wire)
+		if err != nil {
+			return "", err
+		}
+		name = string(b)
+	}
+}
+
+// decodeKeyValue decodes a KeyValue into dst.
+func decodeKeyValue(data []byte, dst map[string]any) error {
+	var key string
+	var value any
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch field {
+		case 1:
+			b, err := r.message(wire)
+			if err != nil {
+				return err
+			}
+			key = string(b)
+		case 2:
+			b, err := r.message(wire)
+			if err != nil {
+				return err
+			}
+			if value, err = decodeAnyValue(b); err != nil {
+				return err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return err
+			}
+		}
+	}
+	if key != "" {
+		dst[key] = value
+	}
+	return nil
+}
+
+// decodeAnyValue decodes an AnyValue into a Go value.
+func decodeAnyValue(data []byte) (any, error) {
+	var value any
+	r := protoReader{buf: data}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return value, err
+		}
+		switch field {
+		case 1: // string_value
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			value = string(b)
+		case 2: // bool_value
+			v, err := r.uint(wire)
+			if err != nil {
+				return nil, err
+			}
+			value = v != 0
+		case 3: // int_value
+			v, err := r.uint(wire)
+			if err != nil {
+				return nil, err
+			}
+			value = int64(v)
+		case 4: // double_value
+			v, err := r.uint(wire)
+			if err != nil {
+				return nil, err
+			}
+			value = math.Float64frombits(v)
+		case 5: // array_value
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			arr := []any{}
+			ar := protoReader{buf: b}
+			for {
+				f, w, ok, err := ar.next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					break
+				}
+				if f != 1 {
+					if err := ar.skip(w); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				vb, err := ar.message(w)
+				if err != nil {
+					return nil, err
+				}
+				v, err := decodeAnyValue(vb)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			}
+			value = arr
+		case 6: // kvlist_value
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			kv := make(map[string]any)
+			kr := protoReader{buf: b}
+			for {
+				f, w, ok, err := kr.next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					break
+				}
+				if f != 1 {
+					if err := kr.skip(w); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				vb, err := kr.message(w)
+				if err != nil {
+					return nil, err
+				}
+				if err := decodeKeyValue(vb, kv); err != nil {
+					return nil, err
+				}
+			}
+			value = kv
+		case 7: // bytes_value
+			b, err := r.message(wire)
+			if err != nil {
+				return nil, err
+			}
+			value = append([]byte(nil), b...)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// --- JSON ---
+
+// The OTLP/JSON mapping uses lowerCamelCase field names, hex-encoded trace
+// and span IDs, and decimal strings (or numbers) for 64-bit integers.
+
+type jsonKeyValue struct {
+	Key   string        `json:"key"`
+	Value *jsonAnyValue `json:"value"`
+}
+
+type jsonAnyValue struct {
+	StringValue *string         `json:"stringValue"`
+	BoolValue   *bool           `json:"boolValue"`
+	IntValue    json.RawMessage `json:"intValue"`
+	DoubleValue *float64        `json:"doubleValue"`
+	ArrayValue  *struct {
+		Values []jsonAnyValue `json:"values"`
+	} `json:"arrayValue"`
+	KvlistValue *struct {
+		Values []jsonKeyValue `json:"values"`
+	} `json:"kvlistValue"`
+	BytesValue *string `json:"bytesValue"`
+}
+
+type jsonResource struct {
+	Attributes []jsonKeyValue `json:"attributes"`
+}
+
+type jsonScope struct {
+	Name string `json:"name"`
+}
+
+type jsonLogsRequest struct {
+	ResourceLogs []struct {
+		Resource  jsonResource `json:"resource"`
+		ScopeLogs []struct {
+			Scope      jsonScope `json:"scope"`
+			LogRecords []struct {
+				TimeUnixNano         json.RawMessage `json:"timeUnixNano"`
+				ObservedTimeUnixNano json.RawMessage `json:"observedTimeUnixNano"`
+				SeverityNumber       int             `json:"severityNumber"`
+				SeverityText         string          `json:"severityText"`
+				Body                 *jsonAnyValue   `json:"body"`
+				Attributes           []jsonKeyValue  `json:"attributes"`
+				TraceID              string          `json:"traceId"`
+				SpanID               string          `json:"spanId"`
+				EventName            string          `json:"eventName"`
+			} `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+type jsonTracesRequest struct {
+	ResourceSpans []struct {
+		Resource   jsonResource `json:"resource"`
+		ScopeSpans []struct {
+			Scope jsonScope `json:"scope"`
+			Spans []struct {
+				TraceID           string          `json:"traceId"`
+				SpanID            string          `json:"spanId"`
+				ParentSpanID      string          `json:"parentSpanId"`
+				Name              string          `json:"name"`
+				Kind              int             `json:"kind"`
+				StartTimeUnixNano json.RawMessage `json:"startTimeUnixNano"`
+				EndTimeUnixNano   json.RawMessage `json:"endTimeUnixNano"`
+				Attributes        []jsonKeyValue  `json:"attributes"`
+				Events            []struct {
+					TimeUnixNano json.RawMessage `json:"timeUnixNano"`
+					Name         string          `json:"name"`
+					Attributes   []jsonKeyValue  `json:"attributes"`
+				} `json:"events"`
+				Status struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"status"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func decodeLogsJSON(data []byte) ([]LogRecord, error) {
+	var req jsonLogsRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	var records []LogRecord
+	for _, rl := range req.ResourceLogs {
+		resource := jsonAttributes(rl.Resource.Attributes)
+		for _, sl := range rl.ScopeLogs {
+			for _, lr := range sl.LogRecords {
+				ts, err := jsonUnixNano(lr.TimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+				observed, err := jsonUnixNano(lr.ObservedTimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+				rec := LogRecord{
+					Time:           ts,
+					ObservedTime:   observed,
+					SeverityNumber: lr.SeverityNumber,
+					SeverityText:   lr.SeverityText,
+					TraceID:        jsonID(lr.TraceID),
+					SpanID:         jsonID(lr.SpanID),
+					EventName:      lr.EventName,
+					Resource:       resource,
+					Scope:          sl.Scope.Name,
+				}
+				if lr.Body != nil {
+					rec.Body = lr.Body.value()
+				}
+				if len(lr.Attributes) > 0 {
+					rec.Attributes = jsonAttributes(lr.Attributes)
+				}
+				records = append(records, rec)
+			}
+		}
+	}
+	return records, nil
+}
+
+func decodeTracesJSON(data []byte) ([]Span, error) {
+	var req jsonTracesRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	var spans []Span
+	for _, rs := range req.ResourceSpans {
+		resource := jsonAttributes(rs.Resource.Attributes)
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				start, err := jsonUnixNano(s.StartTimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+				end, err := jsonUnixNano(s.EndTimeUnixNano)
+				if err != nil {
+					return nil, err
+				}
+				span := Span{
+					TraceID:       jsonID(s.TraceID),
+					SpanID:        jsonID(s.SpanID),
+					ParentSpanID:  jsonID(s.ParentSpanID),
+					Name:          s.Name,
+					Kind:          s.Kind,
+					Start:         start,
+					End:           end,
+					StatusCode:    s.Status.Code,
+					StatusMessage: s.Status.Message,
+					Resource:      resource,
+					Scope:         ss.Scope.Name,
+				}
+				if len(s.Attributes) > 0 {
+					span.Attributes = jsonAttributes(s.Attributes)
+				}
+				for _, e := range s.Events {
+					t, err := jsonUnixNano(e.TimeUnixNano)
+					if err != nil {
+						return nil, err
+					}
+					ev := SpanEvent{Time: t, Name: e.Name}
+					if len(e.Attributes) > 0 {
+						ev.Attributes = jsonAttributes(e.Attributes)
+					}
+					span.Events = append(span.Events, ev)
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans, nil
+}
+
+func jsonAttributes(kvs []jsonKeyValue) map[string]any {
+	attrs := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		if kv.Key == "" {
+			continue
+		}
+		var v any
+		if kv.Value != nil {
+			v = kv.Value.value()
+		}
+		attrs[kv.Key] = v
+	}
+	return attrs
+}
+
+func (v *jsonAnyValue) value() any {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	case len(v.IntValue) > 0:
+		n, err := strconv.ParseInt(strings.Trim(string(v.IntValue), `"`), 10, 64)
+		if err != nil {
+			return nil
+		}
+		return n
+	case v.DoubleValue != nil:
+		return *v.DoubleValue
+	case v.ArrayValue != nil:
+		arr := make([]any, len(v.ArrayValue.Values))
+		for i := range v.ArrayValue.Values {
+			arr[i] = v.ArrayValue.Values[i].value()
+		}
+		return arr
+	case v.KvlistValue != nil:
+		return jsonAttributes(v.KvlistValue.Values)
+	case v.BytesValue != nil:
+		b, err := base64.StdEncoding.DecodeString(*v.BytesValue)
+		if err != nil {
+			return nil
+		}
+		return b
+	}
+	return nil
+}
+
+// jsonUnixNano parses a timestamp encoded as a decimal string or number.
+func jsonUnixNano(raw json.RawMessage) (time.Time, error) {
+	s := strings.Trim(string(raw), `"`)
+	if s == "" || s == "null" {
+		return time.Time{}, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", raw)
+	}
+	return unixNano(n), nil
+}
+
+// jsonID normalizes a JSON-encoded trace or span ID. The spec mandates
+// hex, but some exporters send the protobuf JSON default of base64.
+func jsonID(s string) string {
+	if s == "" {
+		return ""
+	}
+	if b, err := hex.DecodeString(s); err == nil {
+		return idString(b)
+	}
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && (len(b) == 16 || len(b) == 8) {
+		return idString(b)
+	}
+	return strings.ToLower(s)
+}
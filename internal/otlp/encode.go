@@ -0,0 +1,142 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// EncodeLogs encodes records as a protobuf ExportLogsServiceRequest.
+// Each record is written under its own resource and scope.
+func EncodeLogs(records []LogRecord) []byte {
+	var w protoWriter
+	for _, rec := range records {
+		w.message(1, func(rl *protoWriter) { // resource_logs
+			rl.message(1, func(res *protoWriter) { encodeAttributes(res, 1, rec.Resource) })
+			rl.message(2, func(sl *protoWriter) { // scope_logs
+				sl.message(1, func(sc *protoWriter) { sc.string(1, rec.Scope) })
+				sl.message(2, func(lr *protoWriter) { // log_records
+					lr.fixed64(1, nanos(rec.Time))
+					lr.varint(2, uint64(rec.SeverityNumber))
+					lr.string(3, rec.SeverityText)
+					if rec.Body != nil {
+						lr.message(5, func(v *protoWriter) { encodeAnyValue(v, rec.Body) })
+					}
+					encodeAttributes(lr, 6, rec.Attributes)
+					lr.bytes(9, idBytes(rec.TraceID))
+					lr.bytes(10, idBytes(rec.SpanID))
+					lr.fixed64(11, nanos(rec.ObservedTime))
+					lr.string(12, rec.EventName)
+				})
+			})
+		})
+	}
+	return w.buf
+}
+
+// EncodeTraces encodes spans as a protobuf ExportTraceServiceRequest.
+// Each span is written under its own resource and scope.
+func EncodeTraces(spans []Span) []byte {
+	var w protoWriter
+	for _, span := range spans {
+		w.message(1, func(rs *protoWriter) { // resource_spans
+			rs.message(1, func(res *protoWriter) { encodeAttributes(res, 1, span.Resource) })
+			rs.message(2, func(ss *protoWriter) { // scope_spans
+				ss.message(1, func(sc *protoWriter) { sc.string(1, span.Scope) })
+				ss.message(2, func(s *protoWriter) { // spans
+					s.bytes(1, idBytes(span.TraceID))
+					s.bytes(2, idBytes(span.SpanID))
+					s.bytes(4, idBytes(span.ParentSpanID))
+					s.string(5, span.Name)
+					s.varint(6, uint64(span.Kind))
+					s.fixed64(7, nanos(span.Start))
+					s.fixed64(8, nanos(span.End))
+					encodeAttributes(s, 9, span.Attributes)
+					for _, ev := range span.Events {
+						s.message(11, func(e *protoWriter) {
+							e.fixed64(1, nanos(ev.Time))
+							e.string(2, ev.Name)
+							encodeAttributes(e, 3, ev.Attributes)
+						})
+					}
+					if span.StatusCode != StatusCodeUnset || span.StatusMessage != "" {
+						s.message(15, func(st *protoWriter) {
+							st.string(2, span.StatusMessage)
+							st.varint(3, uint64(span.StatusCode))
+						})
+					}
+				})
+			})
+		})
+	}
+	return w.buf
+}
+
+func nanos(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func idBytes(id string) []byte {
+	b, _ := hex.DecodeString(id)
+	return b
+}
+
+// encodeAttributes writes attrs as repeated KeyValue fields, sorted by key
+// so the encoding is deterministic.
+func encodeAttributes(w *protoWriter, field int, attrs map[string]any) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w.message(field, func(kv *protoWriter) {
+			kv.string(1, k)
+			kv.message(2, func(v *protoWriter) { encodeAnyValue(v, attrs[k]) })
+		})
+	}
+}
+
+// encodeAnyValue writes v as the fields of an AnyValue.
+func encodeAnyValue(w *protoWriter, v any) {
+	switch v := v.(type) {
+	case nil:
+	case string:
+		w.tag(1, wireBytes)
+		w.bytes0(v)
+	case bool:
+		w.tag(2, wireVarint)
+		if v {
+			w.buf = append(w.buf, 1)
+		} else {
+			w.buf = append(w.buf, 0)
+		}
+	case int:
+		w.intValue(int64(v))
+	case int64:
+		w.intValue(v)
+	case float64:
+		w.double(4, v)
+	case []byte:
+		w.tag(7, wireBytes)
+		w.bytes0(string(v))
+	case []any:
+		w.message(5, func(arr *protoWriter) {
+			for _, e := range v {
+				arr.message(1, func(ev *protoWriter) { encodeAnyValue(ev, e) })
+			}
+		})
+	case map[string]any:
+		w.message(6, func(kvl *protoWriter) { encodeAttributes(kvl, 1, v) })
+	default:
+		w.tag(1, wireBytes)
+		w.bytes0(fmt.Sprint(v))
+	}
+}
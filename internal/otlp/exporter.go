@@ -0,0 +1,61 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Exporter sends logs and spans to an OTLP/HTTP endpoint using the protobuf
+// encoding. It exists so the receiver can be exercised without an external
+// SDK or collector.
+type Exporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewExporter creates an exporter for an OTLP/HTTP base URL such as
+// "http://localhost:4318".
+func NewExporter(endpoint string) *Exporter {
+	return &Exporter{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ExportLogs sends log records.
+func (e *Exporter) ExportLogs(ctx context.Context, records []LogRecord) error {
+	return e.post(ctx, PathLogs, EncodeLogs(records))
+}
+
+// ExportSpans sends spans.
+func (e *Exporter) ExportSpans(ctx context.Context, spans []Span) error {
+	return e.post(ctx, PathTraces, EncodeTraces(spans))
+}
+
+func (e *Exporter) post(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s: %s", path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTraceID = "5b8efff798038103d269b633813fc60c"
+	testSpanID  = "eee19b7ec3c1b174"
+	testParent  = "eee19b7ec3c1b173"
+)
+
+func testTime() time.Time {
+	return time.Date(2026, 1, 15, 10, 30, 0, 123456789, time.UTC)
+}
+
+func TestLogsRoundTrip(t *testing.T) {
+	in := []LogRecord{{
+		Time:           testTime(),
+		SeverityNumber: 17,
+		SeverityText:   "ERROR",
+		Body:           "payment failed",
+		Attributes: map[string]any{
+			"http.status": int64(502),
+			"retry":       true,
+			"ratio":       0.5,
+			"tags":        []any{"a", "b"},
+			"user":        map[string]any{"id": "u-1"},
+			"empty":       "",
+		},
+		TraceID:  testTraceID,
+		SpanID:   testSpanID,
+		Resource: map[string]any{"service.name": "billing"},
+		Scope:    "billing/http",
+	}}
+
+	out, err := DecodeLogs(EncodeLogs(in), ContentTypeProtobuf)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	rec := out[0]
+	assert.True(t, rec.Time.Equal(testTime()))
+	assert.Equal(t, 17, rec.SeverityNumber)
+	assert.Equal(t, "ERROR", rec.SeverityText)
+	assert.Equal(t, "payment failed", rec.Body)
+	assert.Equal(t, in[0].Attributes, rec.Attributes)
+	assert.Equal(t, testTraceID, rec.TraceID)
+	assert.Equal(t, testSpanID, rec.SpanID)
+	assert.Equal(t, "billing", ServiceName(rec.Resource))
+	assert.Equal(t, "billing/http", rec.Scope)
+}
+
+func TestTracesRoundTrip(t *testing.T) {
+	in := []Span{{
+		TraceID:       testTraceID,
+		SpanID:        testSpanID,
+		ParentSpanID:  testParent,
+		Name:          "GET /checkout",
+		Kind:          SpanKindServer,
+		Start:         testTime(),
+		End:           testTime().Add(25 * time.Millisecond),
+		Attributes:    map[string]any{"http.route": "/checkout"},
+		Events:        []SpanEvent{{Time: testTime().Add(time.Millisecond), Name: "exception", Attributes: map[string]any{"exception.message": "boom"}}},
+		StatusCode:    StatusCodeError,
+		StatusMessage: "upstream timeout",
+		Resource:      map[string]any{"service.name": "web"},
+	}}
+
+	out, err := DecodeTraces(EncodeTraces(in), "")
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	span := out[0]
+	assert.Equal(t, testTraceID, span.TraceID)
+	assert.Equal(t, testSpanID, span.SpanID)
+	assert.Equal(t, testParent, span.ParentSpanID)
+	assert.Equal(t, "GET /checkout", span.Name)
+	assert.Equal(t, SpanKindServer, span.Kind)
+	assert.Equal(t, 25*time.Millisecond, span.End.Sub(span.Start))
+	assert.Equal(t, "/checkout", span.Attributes["http.route"])
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+	assert.Equal(t, "boom", span.Events[0].Attributes["exception.message"])
+	assert.Equal(t, StatusCodeError, span.StatusCode)
+	assert.Equal(t, "upstream timeout", span.StatusMessage)
+	assert.Equal(t, "web", ServiceName(span.Resource))
+}
+
+func TestDecodeJSON(t *testing.T) {
+	logs := `{"resourceLogs":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"api"}}]},
+		"scopeLogs":[{"scope":{"name":"api/db"},"logRecords":[{"timeUnixNano":"1768473000000000000","severityNumber":13,
+		"body":{"stringValue":"slow query"},"attributes":[{"key":"rows","value":{"intValue":"42"}}],
+		"traceId":"5b8efff798038103d269b633813fc60c","spanId":"eee19b7ec3c1b174"}]}]}]}`
+	records, err := DecodeLogs([]byte(logs), "application/json; charset=utf-8")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "slow query", records[0].Body)
+	assert.Equal(t, int64(42), records[0].Attributes["rows"])
+	assert.Equal(t, testTraceID, records[0].TraceID)
+	assert.Equal(t, "api", ServiceName(records[0].Resource))
+	assert.Equal(t, "api/db", records[0].Scope)
+	assert.Equal(t, int64(1768473000), records[0].Time.Unix())
+
+	traces := `{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"W47/95gDgQPSabYzgT/GDA==","spanId":"eee19b7ec3c1b174",
+		"name":"query","kind":3,"startTimeUnixNano":1768473000000000000,"endTimeUnixNano":"1768473000500000000","status":{"code":1}}]}]}]}`
+	spans, err := DecodeTraces([]byte(traces), ContentTypeJSON)
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	assert.Equal(t, testTraceID, spans[0].TraceID, "base64 IDs are normalized to hex")
+	assert.Equal(t, 500*time.Millisecond, spans[0].End.Sub(spans[0].Start))
+	assert.Equal(t, "client", SpanKindName(spans[0].Kind))
+	assert.Equal(t, "ok", StatusCodeName(spans[0].StatusCode))
+}
+
+func TestDecodeRejectsTruncated(t *testing.T) {
+	data := EncodeLogs([]LogRecord{{Body: "hello", TraceID: testTraceID}})
+	_, err := DecodeLogs(data[:len(data)-3], ContentTypeProtobuf)
+	assert.Error(t, err)
+}
+
+func TestSeverityLevel(t *testing.T) {
+	assert.Equal(t, "error", SeverityLevel(17, "whatever"))
+	assert.Equal(t, "warn", SeverityLevel(13, ""))
+	assert.Equal(t, "info", SeverityLevel(9, ""))
+	assert.Equal(t, "fatal", SeverityLevel(24, ""))
+	assert.Equal(t, "notice", SeverityLevel(0, "NOTICE"))
+	assert.Equal(t, "", SeverityLevel(0, ""))
+}
+
+// collector records what a Receiver delivers.
+type collector struct {
+	mu      sync.Mutex
+	records []LogRecord
+	spans   []Span
+}
+
+func (c *collector) receiver() *Receiver {
+	return NewReceiver(
+		func(r []LogRecord) { c.mu.Lock(); c.records = append(c.records, r...); c.mu.Unlock() },
+		func(s []Span) { c.mu.Lock(); c.spans = append(c.spans, s...); c.mu.Unlock() },
+	)
+}
+
+func TestReceiverHTTP(t *testing.T) {
+	var c collector
+	srv := httptest.NewServer(c.receiver())
+	defer srv.Close()
+
+	exp := NewExporter(srv.URL)
+	ctx := context.Background()
+	require.NoError(t, exp.ExportLogs(ctx, []LogRecord{{Body: "one", TraceID: testTraceID}}))
+	require.NoError(t, exp.ExportSpans(ctx, []Span{{TraceID: testTraceID, SpanID: testSpanID, Name: "root"}}))
+
+	// JSON, gzip-compressed
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(`{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"body":{"stringValue":"two"}}]}]}]}`))
+	zw.Close()
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+PathLogs, &gz)
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "{}", string(body))
+
+	// Malformed protobuf
+	resp, err = http.Post(srv.URL+PathTraces, ContentTypeProtobuf, strings.NewReader("\x0a\xff"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(srv.URL+"/v1/metrics", ContentTypeProtobuf, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.records, 2)
+	assert.Equal(t, "one", c.records[0].Body)
+	assert.Equal(t, "two", c.records[1].Body)
+	require.Len(t, c.spans, 1)
+	assert.Equal(t, "root", c.spans[0].Name)
+}
+
+func TestReceiverGRPC(t *testing.T) {
+	var c collector
+	srv := httptest.NewUnstartedServer(c.receiver())
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: transport}
+
+	call := func(method string, msg []byte) *http.Response {
+		frame := make([]byte, 5, 5+len(msg))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+		frame = append(frame, msg...)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+method, bytes.NewReader(frame))
+		req.Header.Set("Content-Type", "application/grpc")
+		req.Header.Set("TE", "trailers")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp
+	}
+
+	resp := call(grpcTracesMethod, EncodeTraces([]Span{{TraceID: testTraceID, SpanID: testSpanID, Name: "grpc-span"}}))
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+
+	resp = call("/opentelemetry.proto.collector.metrics.v1.MetricsService/Export", nil)
+	assert.Equal(t, "12", resp.Trailer.Get("Grpc-Status"))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.Len(t, c.spans, 1)
+	assert.Equal(t, "grpc-span", c.spans[0].Name)
+}
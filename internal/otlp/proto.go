@@ -0,0 +1,189 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// protoReader iterates over the fields of one protobuf message.
+type protoReader struct {
+	buf []byte
+}
+
+// next returns the next field's number and wire type. ok is false at the
+// end of the message.
+func (r *protoReader) next() (field int, wire int, ok bool, err error) {
+	if len(r.buf) == 0 {
+		return 0, 0, false, nil
+	}
+	tag, err := r.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	field = int(tag >> 3)
+	if field == 0 {
+		return 0, 0, false, fmt.Errorf("invalid protobuf field number 0")
+	}
+	return field, int(tag & 7), true, nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.buf = r.buf[n:]
+	return v, nil
+}
+
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.buf) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(r.buf)
+	r.buf = r.buf[8:]
+	return v, nil
+}
+
+func (r *protoReader) fixed32() (uint32, error) {
+	if len(r.buf) < 4 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint32(r.buf)
+	r.buf = r.buf[4:]
+	return v, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)) {
+		return nil, errTruncated
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b, nil
+}
+
+// skip discards a field of the given wire type.
+func (r *protoReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed64()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		_, err = r.fixed32()
+	default:
+		err = fmt.Errorf("unsupported protobuf wire type %d", wire)
+	}
+	return err
+}
+
+// uint reads an integer field encoded as a varint or fixed-width value.
+func (r *protoReader) uint(wire int) (uint64, error) {
+	switch wire {
+	case wireVarint:
+		return r.varint()
+	case wireFixed64:
+		return r.fixed64()
+	case wireFixed32:
+		v, err := r.fixed32()
+		return uint64(v), err
+	default:
+		return 0, fmt.Errorf("unexpected wire type %d for integer field", wire)
+	}
+}
+
+// message reads a length-delimited field.
+func (r *protoReader) message(wire int) ([]byte, error) {
+	if wire != wireBytes {
+		return nil, fmt.Errorf("unexpected wire type %d for message field", wire)
+	}
+	return r.bytes()
+}
+
+// protoWriter appends protobuf fields to a buffer.
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) tag(field, wire int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (w *protoWriter) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, wireVarint)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *protoWriter) fixed64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, v)
+}
+
+func (w *protoWriter) double(field int, v float64) {
+	w.tag(field, wireFixed64)
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(v))
+}
+
+func (w *protoWriter) bytes(field int, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	w.tag(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *protoWriter) string(field int, s string) {
+	w.bytes(field, []byte(s))
+}
+
+// bytes0 writes a length-prefixed value after a tag, including an empty
+// one (a oneof set to "" must still be present).
+func (w *protoWriter) bytes0(s string) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *protoWriter) intValue(v int64) {
+	w.tag(3, wireVarint)
+	w.buf = binary.AppendUvarint(w.buf, uint64(v))
+}
+
+// message appends a nested message built by fn. Empty messages are still
+// written so repeated fields keep their element count.
+func (w *protoWriter) message(field int, fn func(*protoWriter)) {
+	var inner protoWriter
+	fn(&inner)
+	w.tag(field, wireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(inner.buf)))
+	w.buf = append(w.buf, inner.buf...)
+}
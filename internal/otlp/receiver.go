@@ -0,0 +1,191 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package otlp
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRequestSize caps a decoded export request.
+const maxRequestSize = 16 * 1024 * 1024
+
+// OTLP/HTTP paths and the gRPC methods served on the same port.
+const (
+	PathLogs   = "/v1/logs"
+	PathTraces = "/v1/traces"
+
+	grpcLogsMethod   = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+	grpcTracesMethod = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+)
+
+// gRPC status codes used in responses.
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcUnimplemented   = 12
+)
+
+// Receiver is an http.Handler that accepts OTLP exports. OTLP/HTTP requests
+// (protobuf or JSON, optionally gzip-compressed) are served on /v1/logs and
+// /v1/traces; OTLP/gRPC requests are recognized by their content type and
+// require the server to accept unencrypted HTTP/2.
+type Receiver struct {
+	onLogs  func([]LogRecord)
+	onSpans func([]Span)
+}
+
+// NewReceiver creates a receiver that passes decoded records to onLogs and
+// decoded spans to onSpans. Either may be nil to reject that signal.
+func NewReceiver(onLogs func([]LogRecord), onSpans func([]Span)) *Receiver {
+	return &Receiver{onLogs: onLogs, onSpans: onSpans}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		h.serveGRPC(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	body, err := readBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Path {
+	case PathLogs:
+		if h.onLogs == nil {
+			http.NotFound(w, r)
+			return
+		}
+		records, err := DecodeLogs(body, contentType)
+		if err != nil {
+			http.Error(w, "decoding logs: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.onLogs(records)
+	case PathTraces:
+		if h.onSpans == nil {
+			http.NotFound(w, r)
+			return
+		}
+		spans, err := DecodeTraces(body, contentType)
+		if err != nil {
+			http.Error(w, "decoding traces: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.onSpans(spans)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	// An empty Export*ServiceResponse means full success
+	if isJSON(contentType) {
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write([]byte("{}"))
+		return
+	}
+	w.Header().Set("Content-Type", ContentTypeProtobuf)
+	w.WriteHeader(http.StatusOK)
+}
+
+// readBody reads the request body, decompressing gzip if requested.
+func readBody(r *http.Request) ([]byte, error) {
+	var reader io.Reader = r.Body
+	switch r.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", r.Header.Get("Content-Encoding"))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, maxRequestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	if len(body) > maxRequestSize {
+		return nil, fmt.Errorf("request exceeds %d bytes", maxRequestSize)
+	}
+	return body, nil
+}
+
+// serveGRPC handles a unary OTLP/gRPC Export call. The request body is a
+// single length-prefixed message; the status is reported in trailers.
+func (h *Receiver) serveGRPC(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+
+	status, msg := h.handleGRPC(r)
+	if status == grpcOK {
+		// Empty response message: uncompressed flag plus zero length
+		w.Write([]byte{0, 0, 0, 0, 0})
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.Header().Set("Grpc-Status", fmt.Sprint(status))
+	if msg != "" {
+		w.Header().Set("Grpc-Message", msg)
+	}
+}
+
+func (h *Receiver) handleGRPC(r *http.Request) (int, string) {
+	var header [5]byte
+	if _, err := io.ReadFull(r.Body, header[:]); err != nil {
+		return grpcInvalidArgument, "missing message"
+	}
+	if header[0] != 0 {
+		return grpcUnimplemented, "compressed messages are not supported"
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxRequestSize {
+		return grpcInvalidArgument, "message too large"
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r.Body, body); err != nil {
+		return grpcInvalidArgument, "truncated message"
+	}
+
+	switch r.URL.Path {
+	case grpcLogsMethod:
+		if h.onLogs == nil {
+			return grpcUnimplemented, "logs are not accepted"
+		}
+		records, err := decodeLogsProto(body)
+		if err != nil {
+			return grpcInvalidArgument, err.Error()
+		}
+		h.onLogs(records)
+	case grpcTracesMethod:
+		if h.onSpans == nil {
+			return grpcUnimplemented, "traces are not accepted"
+		}
+		spans, err := decodeTracesProto(body)
+		if err != nil {
+			return grpcInvalidArgument, err.Error()
+		}
+		h.onSpans(spans)
+	default:
+		return grpcUnimplemented, "unknown method " + r.URL.Path
+	}
+	return grpcOK, ""
+}
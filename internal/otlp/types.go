@@ -0,0 +1,127 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otlp implements a minimal OpenTelemetry Protocol receiver and
+// exporter for logs and traces. Requests are decoded from OTLP/HTTP
+// (protobuf or JSON) or OTLP/gRPC without depending on the protobuf
+// runtime; only the fields Trellis uses are decoded.
+package otlp
+
+import (
+	"strings"
+	"time"
+)
+
+// LogRecord is a decoded OTLP log record together with the resource and
+// instrumentation scope it was reported under.
+type LogRecord struct {
+	Time           time.Time      // When the event occurred (zero if unset)
+	ObservedTime   time.Time      // When the SDK observed the event
+	SeverityNumber int            // 1-24; 0 if unset
+	SeverityText   string         // As reported, e.g. "ERROR"
+	Body           any            // string, bool, int64, float64, []byte, []any or map[string]any
+	Attributes     map[string]any // Record attributes
+	TraceID        string         // Lowercase hex; empty if unset
+	SpanID         string         // Lowercase hex; empty if unset
+	EventName      string
+	Resource       map[string]any // Resource attributes (service.name etc.)
+	Scope          string         // Instrumentation scope name
+}
+
+// Span is a decoded OTLP span together with its resource and scope.
+type Span struct {
+	TraceID       string // Lowercase hex
+	SpanID        string // Lowercase hex
+	ParentSpanID  string // Lowercase hex; empty for root spans
+	Name          string
+	Kind          int // SpanKind* constant
+	Start         time.Time
+	End           time.Time
+	Attributes    map[string]any
+	Events        []SpanEvent
+	StatusCode    int // StatusCode* constant
+	StatusMessage string
+	Resource      map[string]any
+	Scope         string
+}
+
+// SpanEvent is a timestamped event recorded on a span.
+type SpanEvent struct {
+	Time       time.Time
+	Name       string
+	Attributes map[string]any
+}
+
+// Span kinds.
+const (
+	SpanKindUnspecified = 0
+	SpanKindInternal    = 1
+	SpanKindServer      = 2
+	SpanKindClient      = 3
+	SpanKindProducer    = 4
+	SpanKindConsumer    = 5
+)
+
+// Span status codes.
+const (
+	StatusCodeUnset = 0
+	StatusCodeOK    = 1
+	StatusCodeError = 2
+)
+
+// ServiceName returns the service.name resource attribute.
+func ServiceName(resource map[string]any) string {
+	s, _ := resource["service.name"].(string)
+	return s
+}
+
+// SpanKindName returns the lowercase name of a span kind.
+func SpanKindName(kind int) string {
+	switch kind {
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	default:
+		return "unspecified"
+	}
+}
+
+// StatusCodeName returns the lowercase name of a span status code.
+func StatusCodeName(code int) string {
+	switch code {
+	case StatusCodeOK:
+		return "ok"
+	case StatusCodeError:
+		return "error"
+	default:
+		return "unset"
+	}
+}
+
+// SeverityLevel maps an OTLP severity to a log level name (trace, debug,
+// info, warn, error, fatal). The severity number wins; the text is used
+// when no number was reported. Returns "" if neither is set.
+func SeverityLevel(number int, text string) string {
+	switch {
+	case number >= 21:
+		return "fatal"
+	case number >= 17:
+		return "error"
+	case number >= 13:
+		return "warn"
+	case number >= 9:
+		return "info"
+	case number >= 5:
+		return "debug"
+	case number >= 1:
+		return "trace"
+	}
+	return strings.ToLower(text)
+}
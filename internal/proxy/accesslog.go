@@ -0,0 +1,114 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTraceHeaders are checked in order for a request's trace ID when the
+// listener doesn't name a trace header.
+var defaultTraceHeaders = []string{"Traceparent", "X-Request-Id", "X-Trace-Id", "X-Correlation-Id"}
+
+// AccessLogEntry describes one request served by a proxy listener.
+type AccessLogEntry struct {
+	Time      time.Time
+	Listener  string
+	Method    string
+	URL       string // Path and query
+	Host      string
+	Client    string // Client address
+	Status    int    // 0 if an injected fault aborted the connection
+	Bytes     int64  // Response body size
+	Duration  time.Duration
+	Route     int    // Index of the matched route in the listener's routes, -1 if none matched
+	RouteName string // Matched route's matchers, "*" for a catch-all
+	Upstream  string // Empty if no upstream was reached
+	Worktree  string
+	TraceID   string // From the trace header of the request, or else the response
+	Error     string
+	Fault     string
+	Mock      string
+	ReplayOf  uint64
+}
+
+// SetAccessLog sets the function receiving every listener's access log
+// entries. Listeners with access_log.disabled set don't log. It is called
+// before Start; entries are passed synchronously from the request path.
+func (m *Manager) SetAccessLog(fn func(AccessLogEntry)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accessLog = fn
+	for _, l := range m.listeners {
+		if !l.accessLogDisabled {
+			l.accessLog = fn
+		}
+	}
+}
+
+// logAccess passes a served request to the access log, if any.
+func (l *Listener) logAccess(c *CapturedRequest, route int) {
+	if l.accessLog == nil {
+		return
+	}
+	l.accessLog(AccessLogEntry{
+		Time:      c.Time,
+		Listener:  l.addr,
+		Method:    c.Method,
+		URL:       c.URL,
+		Host:      c.Host,
+		Client:    c.RemoteAddr,
+		Status:    c.Status,
+		Bytes:     c.ResponseSize,
+		Duration:  time.Duration(c.DurationMS * float64(time.Millisecond)),
+		Route:     route,
+		RouteName: c.Route,
+		Upstream:  c.Upstream,
+		Worktree:  c.Worktree,
+		TraceID:   traceID(l.traceHeader, c.RequestHeaders, c.ResponseHeaders),
+		Error:     c.Error,
+		Fault:     c.Fault,
+		Mock:      c.Mock,
+		ReplayOf:  c.ReplayOf,
+	})
+}
+
+// traceID returns the trace ID carried by the named header, or by the first
+// default trace header present, looking at the request before the response.
+// A W3C traceparent value yields its trace-id part.
+func traceID(header string, headers ...http.Header) string {
+	names := defaultTraceHeaders
+	if header != "" {
+		names = []string{header}
+	}
+	for _, h := range headers {
+		for _, name := range names {
+			value := strings.TrimSpace(h.Get(name))
+			if value == "" {
+				continue
+			}
+			if strings.EqualFold(name, "traceparent") {
+				// version-traceid-parentid-flags
+				if parts := strings.Split(value, "-"); len(parts) >= 4 && len(parts[1]) == 32 {
+					return strings.ToLower(parts[1])
+				}
+				continue
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// routeIndex returns the index of rt in the listener's routes, or -1.
+func (l *Listener) routeIndex(rt *route) int {
+	for i := range l.routes {
+		if &l.routes[i] == rt {
+			return i
+		}
+	}
+	return -1
+}
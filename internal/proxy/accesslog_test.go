@@ -0,0 +1,106 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestAccessLog(t *testing.T) {
+	upstream := echoUpstream(t)
+	m, err := NewManager([]config.ProxyListenerConfig{
+		{
+			Listen: ":0",
+			Routes: []config.ProxyRouteConfig{
+				{PathRegexp: "^/api/", Upstream: upstream.URL},
+				{PathRegexp: "^/stub", Mock: config.ProxyMockConfig{Responses: []config.ProxyMockResponse{{Status: 201, Body: "stubbed"}}}},
+				{Upstream: upstream.URL},
+			},
+		},
+		{
+			Listen:    ":1",
+			Routes:    []config.ProxyRouteConfig{{Upstream: upstream.URL}},
+			AccessLog: config.ProxyAccessLogConfig{Disabled: true},
+		},
+	})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var entries []AccessLogEntry
+	m.SetAccessLog(func(e AccessLogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, e)
+	})
+
+	req := httptest.NewRequest("POST", "http://app.test/api/orders?dry=1", strings.NewReader("{}"))
+	req.Header.Set("Traceparent", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	m.listeners[0].serveHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	send(m, "GET", "http://app.test/stub", "")
+	send(m, "GET", "http://app.test/missing", "")
+	m.listeners[1].serveHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	require.Len(t, entries, 3)
+	e := entries[0]
+	assert.Equal(t, ":0", e.Listener)
+	assert.Equal(t, "POST", e.Method)
+	assert.Equal(t, "/api/orders?dry=1", e.URL)
+	assert.Equal(t, "app.test", e.Host)
+	assert.Equal(t, 200, e.Status)
+	assert.Equal(t, int64(len("POST /api/orders?dry=1 {}")), e.Bytes)
+	assert.Equal(t, 0, e.Route)
+	assert.Equal(t, "path=^/api/", e.RouteName)
+	assert.Equal(t, strings.TrimPrefix(upstream.URL, "http://"), e.Upstream)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", e.TraceID)
+	assert.NotEmpty(t, e.Client)
+	assert.Positive(t, e.Duration)
+
+	assert.Equal(t, 1, entries[1].Route)
+	assert.Equal(t, 201, entries[1].Status)
+	assert.Empty(t, entries[1].Upstream)
+	assert.Equal(t, "stub 0", entries[1].Mock)
+
+	assert.Equal(t, 2, entries[2].Route)
+	assert.Equal(t, 404, entries[2].Status)
+	assert.Empty(t, entries[2].TraceID)
+
+	// Capture isn't enabled, so nothing was kept
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
+
+func TestTraceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		req      http.Header
+		resp     http.Header
+		expected string
+	}{
+		{"none", "", http.Header{}, http.Header{}, ""},
+		{"traceparent", "", http.Header{"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}}, nil, "0af7651916cd43dd8448eb211c80319c"},
+		{"malformed traceparent", "", http.Header{"Traceparent": {"junk"}, "X-Request-Id": {"req-1"}}, nil, "req-1"},
+		{"request before response", "", http.Header{"X-Trace-Id": {"a"}}, http.Header{"X-Request-Id": {"b"}}, "a"},
+		{"response", "", http.Header{}, http.Header{"X-Request-Id": {"b"}}, "b"},
+		{"configured header", "X-Amzn-Trace-Id", http.Header{"X-Request-Id": {"a"}, "X-Amzn-Trace-Id": {"Root=1-abc"}}, nil, "Root=1-abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, traceID(tt.header, tt.req, tt.resp))
+		})
+	}
+}
@@ -0,0 +1,593 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// Capture defaults.
+const (
+	defaultCaptureMaxRequests  = 1000
+	defaultCaptureMaxBodyBytes = 64 * 1024
+)
+
+// ErrCaptureNotFound is returned when a captured request ID is unknown or
+// has been evicted from its listener's ring.
+var ErrCaptureNotFound = errors.New("captured request not found")
+
+// CapturedBody is a request or response body as recorded by the proxy.
+type CapturedBody struct {
+	Data      string `json:"data,omitempty"`      // Body text, base64-encoded when Base64 is set
+	Base64    bool   `json:"base64,omitempty"`    // Data is base64 (the body is not valid UTF-8)
+	Encoding  string `json:"encoding,omitempty"`  // Content-Encoding that was decoded for Data (e.g. "gzip")
+	Size      int64  `json:"size"`                // Full body size in bytes, as sent on the wire
+	Truncated bool   `json:"truncated,omitempty"` // Data holds only the first max_body_bytes
+}
+
+// Bytes returns the captured body data.
+func (b CapturedBody) Bytes() ([]byte, error) {
+	if b.Base64 {
+		return base64.StdEncoding.DecodeString(b.Data)
+	}
+	return []byte(b.Data), nil
+}
+
+// CaptureSummary describes a captured request without its headers and bodies.
+type CaptureSummary struct {
+	ID           uint64    `json:"id"`
+	Listener     string    `json:"listener"`
+	Time         time.Time `json:"time"`
+	Method       string    `json:"method"`
+	URL          string    `json:"url"` // Path and query
+	Host         string    `json:"host"`
+	RemoteAddr   string    `json:"remote_addr"`
+	Route        string    `json:"route"` // Matched route's matchers (e.g. "hosts=api.* path=^/v1/"), "*" for a catch-all, empty if no route matched
+	Upstream     string    `json:"upstream,omitempty"`
+	Worktree     string    `json:"worktree,omitempty"` // Worktree the request was routed to, on listeners that route by worktree
+	Status       int       `json:"status"`
+	DurationMS   float64   `json:"duration_ms"`
+	RequestSize  int64     `json:"request_size"`
+	ResponseSize int64     `json:"response_size"`
+	Error        string    `json:"error,omitempty"`
+	Fault        string    `json:"fault,omitempty"`     // Faults injected into the request (e.g. "latency=250ms, error=503")
+	Mock         string    `json:"mock,omitempty"`      // Stub or fixture that answered the request, or the fixture it was recorded to
+	ReplayOf     uint64    `json:"replay_of,omitempty"` // ID of the captured request this one replayed
+}
+
+// CapturedRequest is a request/response pair recorded by a capturing listener.
+type CapturedRequest struct {
+	CaptureSummary
+	RequestHeaders  http.Header  `json:"request_headers"`
+	RequestBody     CapturedBody `json:"request_body"`
+	ResponseHeaders http.Header  `json:"response_headers"`
+	ResponseBody    CapturedBody `json:"response_body"`
+
+	aborted bool // Connection was closed by an injected fault
+}
+
+// CaptureFilter selects captured requests. Zero fields match everything.
+type CaptureFilter struct {
+	Listener    string        // Exact listener address
+	Method      string        // Case-insensitive method
+	Status      string        // Exact code ("404") or class ("5xx")
+	Path        string        // Substring of the path and query
+	Upstream    string        // Substring of the upstream host
+	MinDuration time.Duration // Only requests at least this slow
+	Limit       int           // Most results to return, newest first (0 = all)
+}
+
+// Validate checks the filter's status syntax.
+func (f CaptureFilter) Validate() error {
+	if f.Status == "" {
+		return nil
+	}
+	if len(f.Status) == 3 && f.Status[1:] == "xx" && f.Status[0] >= '1' && f.Status[0] <= '5' {
+		return nil
+	}
+	if code, err := strconv.Atoi(f.Status); err == nil && code >= 100 && code <= 599 {
+		return nil
+	}
+	return fmt.Errorf("invalid status filter %q (use a code like 404 or a class like 5xx)", f.Status)
+}
+
+func (f CaptureFilter) match(c *CapturedRequest) bool {
+	if f.Listener != "" && c.Listener != f.Listener {
+		return false
+	}
+	if f.Method != "" && !strings.EqualFold(c.Method, f.Method) {
+		return false
+	}
+	if f.Status != "" {
+		if strings.HasSuffix(f.Status, "xx") {
+			if c.Status/100 != int(f.Status[0]-'0') {
+				return false
+			}
+		} else if strconv.Itoa(c.Status) != f.Status {
+			return false
+		}
+	}
+	if f.Path != "" && !strings.Contains(c.URL, f.Path) {
+		return false
+	}
+	if f.Upstream != "" && !strings.Contains(c.Upstream, f.Upstream) {
+		return false
+	}
+	if f.MinDuration > 0 && c.DurationMS < float64(f.MinDuration)/float64(time.Millisecond) {
+		return false
+	}
+	return true
+}
+
+// captureRing is a bounded ring of captured requests for one listener.
+type captureRing struct {
+	mu      sync.Mutex
+	items   []*CapturedRequest
+	head    int
+	size    int
+	maxBody int
+}
+
+func newCaptureRing(cfg config.ProxyCaptureConfig) *captureRing {
+	maxRequests := cfg.MaxRequests
+	if maxRequests <= 0 {
+		maxRequests = defaultCaptureMaxRequests
+	}
+	maxBody := cfg.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = defaultCaptureMaxBodyBytes
+	}
+	return &captureRing{
+		items:   make([]*CapturedRequest, maxRequests),
+		maxBody: maxBody,
+	}
+}
+
+func (r *captureRing) add(c *CapturedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[r.head] = c
+	r.head = (r.head + 1) % len(r.items)
+	if r.size < len(r.items) {
+		r.size++
+	}
+}
+
+// all returns the ring's requests, oldest first.
+func (r *captureRing) all() []*CapturedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*CapturedRequest, 0, r.size)
+	start := (r.head - r.size + len(r.items)) % len(r.items)
+	for i := 0; i < r.size; i++ {
+		out = append(out, r.items[(start+i)%len(r.items)])
+	}
+	return out
+}
+
+func (r *captureRing) clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.items)
+	r.head = 0
+	r.size = 0
+}
+
+// bodyCapture records the first max bytes written to it and counts the rest.
+// The transport can still be reading a request body when the response is
+// done, so it is locked.
+type bodyCapture struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	max  int
+	size int64
+}
+
+func (b *bodyCapture) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.size += int64(len(p))
+	if room := b.max - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// body converts the recording into a CapturedBody. A complete gzip body is
+// decompressed so it can be read; contentEncoding is the body's
+// Content-Encoding header.
+func (b *bodyCapture) body(contentEncoding string) CapturedBody {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data := b.buf.Bytes()
+	out := CapturedBody{Size: b.size, Truncated: b.size > int64(len(data))}
+	if strings.EqualFold(contentEncoding, "gzip") && !out.Truncated && len(data) > 0 {
+		if zr, err := gzip.NewReader(bytes.NewReader(data)); err == nil {
+			decoded, err := io.ReadAll(io.LimitReader(zr, int64(b.max)+1))
+			if err == nil || errors.Is(err, io.ErrUnexpectedEOF) {
+				out.Encoding = "gzip"
+				if len(decoded) > b.max {
+					decoded = decoded[:b.max]
+					out.Truncated = true
+				}
+				data = decoded
+			}
+		}
+	}
+	if utf8.Valid(data) {
+		out.Data = string(data)
+	} else {
+		out.Data = base64.StdEncoding.EncodeToString(data)
+		out.Base64 = true
+	}
+	return out
+}
+
+// teeBody records a request body as the reverse proxy reads it.
+type teeBody struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+func (t *teeBody) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	t.capture.Write(p[:n])
+	return n, err
+}
+
+// captureWriter records the response status, headers and body on their way
+// to the client.
+type captureWriter struct {
+	http.ResponseWriter
+	status  int
+	headers http.Header
+	body    *bodyCapture
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.headers == nil {
+		cw.status = code
+		cw.headers = cw.ResponseWriter.Header().Clone()
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	if cw.headers == nil {
+		cw.WriteHeader(http.StatusOK)
+	}
+	cw.body.Write(p)
+	return cw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseWriter optional interfaces (Flusher, Hijacker) pass through.
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// captureKey is the request context key holding a capturing request's
+// record, so the proxy can note errors and faults on it.
+type captureKey struct{}
+
+// recordCaptureError notes a proxy error on the request's capture, if any.
+func recordCaptureError(req *http.Request, err error) {
+	if c, ok := req.Context().Value(captureKey{}).(*CapturedRequest); ok {
+		c.Error = err.Error()
+	}
+}
+
+// recordCaptureFault notes an injected fault on the request's capture, if
+// any. An aborted request is recorded without a status.
+func recordCaptureFault(req *http.Request, tag string, aborted bool) {
+	if c, ok := req.Context().Value(captureKey{}).(*CapturedRequest); ok {
+		if c.Fault != "" {
+			c.Fault += ", "
+		}
+		c.Fault += tag
+		c.aborted = c.aborted || aborted
+	}
+}
+
+// recordCaptureMock notes the mock response source on the request's capture,
+// if any.
+func recordCaptureMock(req *http.Request, source string) {
+	if c, ok := req.Context().Value(captureKey{}).(*CapturedRequest); ok {
+		c.Mock = source
+	}
+}
+
+// serveCaptured proxies r like serveRoute, or serveWebSocket for upgrades,
+// and records the exchange in the listener's capture ring and access log.
+// Bodies are only kept when capture is enabled. An upgrade is recorded when
+// its tunnel closes, with status 101 unless the proxy answered it.
+func (l *Listener) serveCaptured(w http.ResponseWriter, r *http.Request, replayOf uint64) *CapturedRequest {
+	ring := l.capture
+	c := &CapturedRequest{
+		CaptureSummary: CaptureSummary{
+			Listener:   l.addr,
+			Time:       time.Now(),
+			Method:     r.Method,
+			URL:        r.URL.RequestURI(),
+			Host:       r.Host,
+			RemoteAddr: r.RemoteAddr,
+			ReplayOf:   replayOf,
+		},
+		RequestHeaders: r.Header.Clone(),
+	}
+	maxBody := 0
+	if ring != nil {
+		c.ID = l.nextID()
+		maxBody = ring.maxBody
+	}
+
+	reqBody := &bodyCapture{max: maxBody}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = &teeBody{ReadCloser: r.Body, capture: reqBody}
+	}
+	cw := &captureWriter{ResponseWriter: w, status: http.StatusOK, body: &bodyCapture{max: maxBody}}
+	r = r.WithContext(context.WithValue(r.Context(), captureKey{}, c))
+
+	var rt *route
+	var t *target
+	if isWebSocket(r) {
+		cw.status = http.StatusSwitchingProtocols
+		rt, t = l.serveWebSocket(cw, r)
+	} else {
+		rt, t = l.serveRoute(cw, r)
+	}
+
+	c.DurationMS = float64(time.Since(c.Time).Microseconds()) / 1000
+	c.Status = cw.status
+	if c.aborted {
+		c.Status = 0
+	}
+	if rt != nil {
+		c.Route = rt.name()
+	}
+	if t != nil {
+		// Stub and fixture responses didn't reach the upstream
+		if t.upstream != nil && (c.Mock == "" || strings.HasPrefix(c.Mock, "recorded ")) {
+			c.Upstream = t.upstream.Host
+		}
+		c.Worktree = t.worktree
+	}
+	c.RequestBody = reqBody.body(r.Header.Get("Content-Encoding"))
+	c.RequestSize = c.RequestBody.Size
+	c.ResponseHeaders = cw.headers
+	if c.ResponseHeaders == nil {
+		c.ResponseHeaders = cw.Header().Clone()
+	}
+	c.ResponseBody = cw.body.body(c.ResponseHeaders.Get("Content-Encoding"))
+	c.ResponseSize = c.ResponseBody.Size
+
+	if ring != nil {
+		ring.add(c)
+	}
+	l.logAccess(c, l.routeIndex(rt))
+	return c
+}
+
+// ReplayOptions edits a captured request before it is replayed. Zero fields
+// keep the captured value.
+type ReplayOptions struct {
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url,omitempty"`     // Path and query
+	Headers map[string]string `json:"headers,omitempty"` // Headers to set; an empty value removes the header
+	Body    *string           `json:"body,omitempty"`    // Replacement request body
+
+	// Worktree sends the replay to this worktree's upstreams, on listeners
+	// that route by worktree
+	Worktree string `json:"worktree,omitempty"`
+}
+
+// replayWriter is the response writer for a replayed request. The capture
+// wrapping it records the response; nothing else needs it.
+type replayWriter struct {
+	header http.Header
+}
+
+func (rw *replayWriter) Header() http.Header         { return rw.header }
+func (rw *replayWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (rw *replayWriter) WriteHeader(int)             {}
+
+// replay sends a copy of c, edited by opts, through the listener's current
+// routes and captures the result.
+func (l *Listener) replay(ctx context.Context, c *CapturedRequest, opts ReplayOptions) (*CapturedRequest, error) {
+	method := c.Method
+	if opts.Method != "" {
+		method = strings.ToUpper(opts.Method)
+	}
+	target := c.URL
+	if opts.URL != "" {
+		target = opts.URL
+	}
+	u, err := url.ParseRequestURI(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", target, err)
+	}
+
+	var body []byte
+	if opts.Body != nil {
+		body = []byte(*opts.Body)
+	} else {
+		if c.RequestBody.Truncated {
+			return nil, fmt.Errorf("captured request body was truncated at %d bytes; supply a body to replay it", len(c.RequestBody.Data))
+		}
+		if body, err = c.RequestBody.Bytes(); err != nil {
+			return nil, fmt.Errorf("decode captured body: %w", err)
+		}
+	}
+
+	header := c.RequestHeaders.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if opts.Body != nil || c.RequestBody.Encoding != "" {
+		// The body is sent as given or as decoded, not re-encoded
+		header.Del("Content-Encoding")
+	}
+	for name, value := range opts.Headers {
+		if value == "" {
+			header.Del(name)
+		} else {
+			header.Set(name, value)
+		}
+	}
+	// The body is replayed as captured, already decoded
+	header.Del("Content-Length")
+	if opts.Worktree != "" {
+		if l.worktrees == nil {
+			return nil, fmt.Errorf("listener %s does not route by worktree", l.addr)
+		}
+		if l.worktrees.source == nil {
+			return nil, errors.New("worktree routing is not available")
+		}
+		wt, err := l.worktrees.lookup(opts.Worktree)
+		if err != nil {
+			return nil, err
+		}
+		header.Set(l.worktrees.header, wt.Name)
+	}
+
+	req := (&http.Request{
+		Method:        method,
+		URL:           u,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Host:          c.Host,
+		RemoteAddr:    "replay",
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+	}).WithContext(ctx)
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+
+	return l.serveCaptured(&replayWriter{header: make(http.Header)}, req, c.ID), nil
+}
+
+// ListenerInfo describes a proxy listener.
+type ListenerInfo struct {
+	Listen    string `json:"listen"`
+	TLS       bool   `json:"tls"`
+	Capturing bool   `json:"capturing"`
+	Worktrees bool   `json:"worktrees"` // Routes each request to a selected worktree
+}
+
+// Listeners returns the configured listeners in config order.
+func (m *Manager) Listeners() []ListenerInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ListenerInfo, len(m.listeners))
+	for i, l := range m.listeners {
+		out[i] = ListenerInfo{Listen: l.addr, TLS: l.tls, Capturing: l.capture != nil, Worktrees: l.worktrees != nil}
+	}
+	return out
+}
+
+// Capturing reports whether any listener records traffic.
+func (m *Manager) Capturing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range m.listeners {
+		if l.capture != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// captured returns every captured request across listeners, newest first.
+func (m *Manager) captured() []*CapturedRequest {
+	m.mu.Lock()
+	var all []*CapturedRequest
+	for _, l := range m.listeners {
+		if l.capture != nil {
+			all = append(all, l.capture.all()...)
+		}
+	}
+	m.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+	return all
+}
+
+// Requests returns summaries of captured requests matching f, newest first.
+func (m *Manager) Requests(f CaptureFilter) ([]CaptureSummary, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	out := []CaptureSummary{}
+	for _, c := range m.captured() {
+		if !f.match(c) {
+			continue
+		}
+		out = append(out, c.CaptureSummary)
+		if f.Limit > 0 && len(out) >= f.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Request returns a captured request with its headers and bodies.
+func (m *Manager) Request(id uint64) (*CapturedRequest, error) {
+	for _, c := range m.captured() {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, ErrCaptureNotFound
+}
+
+// ClearRequests drops every captured request.
+func (m *Manager) ClearRequests() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range m.listeners {
+		if l.capture != nil {
+			l.capture.clear()
+		}
+	}
+}
+
+// Replay resends a captured request, optionally edited, through the routes
+// of the listener that captured it, so it reaches whatever upstream those
+// routes point at now. The replayed exchange is captured too and returned.
+func (m *Manager) Replay(ctx context.Context, id uint64, opts ReplayOptions) (*CapturedRequest, error) {
+	c, err := m.Request(id)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	var listener *Listener
+	for _, l := range m.listeners {
+		if l.addr == c.Listener && l.capture != nil {
+			listener = l
+			break
+		}
+	}
+	m.mu.Unlock()
+	if listener == nil {
+		return nil, ErrCaptureNotFound
+	}
+	return listener.replay(ctx, c, opts)
+}
@@ -0,0 +1,261 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// echoUpstream answers every request with its method, path and body.
+func echoUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Echo-Auth", r.Header.Get("Authorization"))
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func captureManager(t *testing.T, upstream string, capture config.ProxyCaptureConfig) *Manager {
+	t.Helper()
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen:  ":0",
+		Routes:  []config.ProxyRouteConfig{{PathRegexp: "^/api/", Upstream: upstream}, {Upstream: upstream}},
+		Capture: capture,
+	}})
+	require.NoError(t, err)
+	return m
+}
+
+func send(m *Manager, method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer abc")
+	m.listeners[0].serveHTTP(rec, req)
+	return rec
+}
+
+func TestCapture_RecordsExchange(t *testing.T) {
+	upstream := echoUpstream(t)
+	m := captureManager(t, upstream.URL, config.ProxyCaptureConfig{Enabled: true})
+	assert.True(t, m.Capturing())
+
+	rec := send(m, "POST", "http://app.test/api/orders?dry=1", `{"qty":2}`)
+	assert.Equal(t, `POST /api/orders?dry=1 {"qty":2}`, rec.Body.String())
+	send(m, "GET", "http://app.test/missing", "")
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "/missing", summaries[0].URL)
+	assert.Equal(t, http.StatusNotFound, summaries[0].Status)
+	assert.Equal(t, "*", summaries[0].Route)
+
+	c, err := m.Request(summaries[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", c.Method)
+	assert.Equal(t, "app.test", c.Host)
+	assert.Equal(t, "path=^/api/", c.Route)
+	assert.Equal(t, strings.TrimPrefix(upstream.URL, "http://"), c.Upstream)
+	assert.Equal(t, http.StatusOK, c.Status)
+	assert.Equal(t, "Bearer abc", c.RequestHeaders.Get("Authorization"))
+	assert.Equal(t, `{"qty":2}`, c.RequestBody.Data)
+	assert.Equal(t, int64(9), c.RequestSize)
+	assert.Equal(t, "Bearer abc", c.ResponseHeaders.Get("X-Echo-Auth"))
+	assert.Equal(t, rec.Body.String(), c.ResponseBody.Data)
+
+	_, err = m.Request(999)
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+
+	m.ClearRequests()
+	summaries, err = m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
+
+func TestCapture_Disabled(t *testing.T) {
+	m := captureManager(t, echoUpstream(t).URL, config.ProxyCaptureConfig{})
+	assert.False(t, m.Capturing())
+
+	rec := send(m, "GET", "http://app.test/", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
+
+func TestCapture_RingAndBodyLimits(t *testing.T) {
+	m := captureManager(t, echoUpstream(t).URL, config.ProxyCaptureConfig{Enabled: true, MaxRequests: 2, MaxBodyBytes: 4})
+
+	send(m, "POST", "http://app.test/one", "abcdefgh")
+	send(m, "POST", "http://app.test/two", "ab")
+	send(m, "POST", "http://app.test/three", "ab")
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "/three", summaries[0].URL)
+	assert.Equal(t, "/two", summaries[1].URL)
+
+	c, err := m.Request(summaries[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ab", c.RequestBody.Data)
+	assert.False(t, c.RequestBody.Truncated)
+	assert.Equal(t, "POST", c.ResponseBody.Data)
+	assert.True(t, c.ResponseBody.Truncated)
+	assert.Equal(t, int64(len("POST /two ab")), c.ResponseBody.Size)
+}
+
+func TestCapture_DecodesGzipAndBinary(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/binary" {
+			w.Write([]byte{0xff, 0xfe, 0x00})
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		io.WriteString(zw, `{"ok":true}`)
+		zw.Close()
+	}))
+	defer upstream.Close()
+	m := captureManager(t, upstream.URL, config.ProxyCaptureConfig{Enabled: true})
+
+	// A client that accepts gzip gets the compressed body passed through
+	req := httptest.NewRequest("GET", "http://app.test/gzip", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	m.listeners[0].serveHTTP(httptest.NewRecorder(), req)
+	send(m, "GET", "http://app.test/binary", "")
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+
+	binary, err := m.Request(summaries[0].ID)
+	require.NoError(t, err)
+	assert.True(t, binary.ResponseBody.Base64)
+	data, err := binary.ResponseBody.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xfe, 0x00}, data)
+
+	gz, err := m.Request(summaries[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", gz.ResponseBody.Encoding)
+	assert.Equal(t, `{"ok":true}`, gz.ResponseBody.Data)
+}
+
+func TestCapture_UpstreamError(t *testing.T) {
+	// Nothing listens on the upstream
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+	m := captureManager(t, upstream.URL, config.ProxyCaptureConfig{Enabled: true})
+
+	rec := send(m, "GET", "http://app.test/", "")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+
+	summaries, err := m.Requests(CaptureFilter{Status: "5xx"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Contains(t, summaries[0].Error, "connection refused")
+}
+
+func TestCaptureFilter(t *testing.T) {
+	m := captureManager(t, echoUpstream(t).URL, config.ProxyCaptureConfig{Enabled: true})
+	send(m, "GET", "http://app.test/api/users", "")
+	send(m, "POST", "http://app.test/api/users", "x")
+	send(m, "GET", "http://app.test/missing", "")
+
+	tests := []struct {
+		filter CaptureFilter
+		want   int
+	}{
+		{CaptureFilter{}, 3},
+		{CaptureFilter{Method: "post"}, 1},
+		{CaptureFilter{Status: "404"}, 1},
+		{CaptureFilter{Status: "2xx"}, 2},
+		{CaptureFilter{Path: "/api/"}, 2},
+		{CaptureFilter{Listener: ":0"}, 3},
+		{CaptureFilter{Listener: ":1"}, 0},
+		{CaptureFilter{MinDuration: time.Hour}, 0},
+		{CaptureFilter{Limit: 2}, 2},
+	}
+	for _, tt := range tests {
+		summaries, err := m.Requests(tt.filter)
+		require.NoError(t, err)
+		assert.Len(t, summaries, tt.want, "%+v", tt.filter)
+	}
+
+	for _, status := range []string{"abc", "6xx", "99", "xx"} {
+		_, err := m.Requests(CaptureFilter{Status: status})
+		assert.Error(t, err, status)
+	}
+}
+
+func TestCapture_Replay(t *testing.T) {
+	m := captureManager(t, echoUpstream(t).URL, config.ProxyCaptureConfig{Enabled: true})
+	send(m, "POST", "http://app.test/api/orders", `{"qty":2}`)
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	original := summaries[0].ID
+
+	// Replayed as captured
+	replayed, err := m.Replay(context.Background(), original, ReplayOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, original, replayed.ReplayOf)
+	assert.NotEqual(t, original, replayed.ID)
+	assert.Equal(t, `POST /api/orders {"qty":2}`, replayed.ResponseBody.Data)
+	assert.Equal(t, "Bearer abc", replayed.ResponseHeaders.Get("X-Echo-Auth"))
+
+	// Replayed with edits
+	body := `{"qty":3}`
+	edited, err := m.Replay(context.Background(), original, ReplayOptions{
+		Method:  "put",
+		URL:     "/api/orders/7?force=1",
+		Headers: map[string]string{"Authorization": ""},
+		Body:    &body,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `PUT /api/orders/7?force=1 {"qty":3}`, edited.ResponseBody.Data)
+	assert.Empty(t, edited.ResponseHeaders.Get("X-Echo-Auth"))
+	assert.Equal(t, "path=^/api/", edited.Route)
+
+	summaries, err = m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	assert.Len(t, summaries, 3)
+
+	_, err = m.Replay(context.Background(), 999, ReplayOptions{})
+	assert.ErrorIs(t, err, ErrCaptureNotFound)
+}
+
+func TestCapture_ReplayTruncatedBody(t *testing.T) {
+	m := captureManager(t, echoUpstream(t).URL, config.ProxyCaptureConfig{Enabled: true, MaxBodyBytes: 4})
+	send(m, "POST", "http://app.test/upload", string(bytes.Repeat([]byte("x"), 10)))
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+
+	_, err = m.Replay(context.Background(), summaries[0].ID, ReplayOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "truncated")
+
+	body := "short"
+	_, err = m.Replay(context.Background(), summaries[0].ID, ReplayOptions{Body: &body})
+	assert.NoError(t, err)
+}
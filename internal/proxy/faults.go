@@ -0,0 +1,363 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// faultHeader lists the faults injected into a response, so clients and
+// captures can tell them from real failures.
+const faultHeader = "X-Trellis-Fault"
+
+const (
+	defaultFaultStatus        = http.StatusServiceUnavailable
+	defaultWebSocketDropAfter = 10 * time.Second
+)
+
+// ErrRouteNotFound is returned when a fault update names no route.
+var ErrRouteNotFound = errors.New("proxy route not found")
+
+// RouteFaults describes the fault injection settings of one route.
+type RouteFaults struct {
+	Listener string                  `json:"listener"`
+	Route    int                     `json:"route"` // Index in the listener's routes
+	Name     string                  `json:"name"`  // Route matchers summary
+	Faults   config.ProxyFaultConfig `json:"faults"`
+}
+
+// routeFaults holds a route's current fault settings, which can be replaced
+// while requests are in flight.
+type routeFaults struct {
+	mu       sync.RWMutex
+	cfg      config.ProxyFaultConfig
+	injector *faultInjector // nil unless enabled
+}
+
+func newRouteFaults(cfg config.ProxyFaultConfig) (*routeFaults, error) {
+	rf := &routeFaults{}
+	if err := rf.set(cfg); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *routeFaults) set(cfg config.ProxyFaultConfig) error {
+	inj, err := newFaultInjector(cfg)
+	if err != nil {
+		return err
+	}
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	rf.cfg = cfg
+	rf.injector = inj
+	return nil
+}
+
+func (rf *routeFaults) config() config.ProxyFaultConfig {
+	rf.mu.RLock()
+	defer rf.mu.RUnlock()
+	return rf.cfg
+}
+
+// active returns the injector, or nil if faults are off.
+func (rf *routeFaults) active() *faultInjector {
+	rf.mu.RLock()
+	defer rf.mu.RUnlock()
+	return rf.injector
+}
+
+// faultInjector applies compiled fault settings.
+type faultInjector struct {
+	latency, latencyMax time.Duration
+	errorRate           float64
+	errorStatus         int
+	abortRate           float64
+	bandwidth           int
+	wsDropRate          float64
+	wsDropAfter         time.Duration
+	random              func() float64 // In [0, 1)
+}
+
+// newFaultInjector validates and compiles fault settings. It returns nil if
+// faults are disabled.
+func newFaultInjector(cfg config.ProxyFaultConfig) (*faultInjector, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	f := &faultInjector{
+		errorRate:   cfg.ErrorRate,
+		errorStatus: cfg.ErrorStatus,
+		abortRate:   cfg.AbortRate,
+		bandwidth:   cfg.Bandwidth,
+		wsDropRate:  cfg.WebSocketDropRate,
+		wsDropAfter: defaultWebSocketDropAfter,
+		random:      rand.Float64,
+	}
+	if f.errorStatus == 0 {
+		f.errorStatus = defaultFaultStatus
+	}
+	// Durations were checked by Validate
+	f.latency, _ = time.ParseDuration(cfg.Latency)
+	f.latencyMax, _ = time.ParseDuration(cfg.LatencyMax)
+	if cfg.WebSocketDropAfter != "" {
+		f.wsDropAfter, _ = time.ParseDuration(cfg.WebSocketDropAfter)
+	}
+	return f, nil
+}
+
+// roll reports whether an event with the given percentage happens.
+func (f *faultInjector) roll(rate float64) bool {
+	return rate > 0 && f.random()*100 < rate
+}
+
+// delay returns the latency to add to a request.
+func (f *faultInjector) delay() time.Duration {
+	if f.latencyMax > f.latency {
+		return f.latency + time.Duration(f.random()*float64(f.latencyMax-f.latency))
+	}
+	return f.latency
+}
+
+// failure picks whether a request is aborted or answered with an error.
+// Both come from one roll, so their rates add up.
+func (f *faultInjector) failure() (abort, fail bool) {
+	if f.abortRate <= 0 && f.errorRate <= 0 {
+		return false, false
+	}
+	p := f.random() * 100
+	return p < f.abortRate, p >= f.abortRate && p < f.abortRate+f.errorRate
+}
+
+// sleep waits for d or until ctx is done, reporting whether it waited.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// inject applies latency, failures and throttling to an HTTP request. It
+// returns the writer to proxy the response through, or false if the fault
+// already finished the request.
+func (f *faultInjector) inject(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, bool) {
+	if d := f.delay(); d > 0 {
+		tag := "latency=" + d.Round(time.Millisecond).String()
+		w.Header().Add(faultHeader, tag)
+		recordCaptureFault(r, tag, false)
+		if !sleep(r.Context(), d) {
+			return w, false
+		}
+	}
+
+	abort, fail := f.failure()
+	if abort {
+		recordCaptureFault(r, "abort", true)
+		if abortConnection(w) {
+			return w, false
+		}
+		// Connections that can't be hijacked (HTTP/2, replays) get a 502
+		w.Header().Add(faultHeader, "abort")
+		http.Error(w, "Injected fault: connection aborted", http.StatusBadGateway)
+		return w, false
+	}
+	if fail {
+		tag := "error=" + strconv.Itoa(f.errorStatus)
+		w.Header().Add(faultHeader, tag)
+		recordCaptureFault(r, tag, false)
+		http.Error(w, "Injected fault: "+strconv.Itoa(f.errorStatus)+" "+http.StatusText(f.errorStatus), f.errorStatus)
+		return w, false
+	}
+
+	if f.bandwidth > 0 {
+		tag := "bandwidth=" + strconv.Itoa(f.bandwidth) + "B/s"
+		w.Header().Add(faultHeader, tag)
+		recordCaptureFault(r, tag, false)
+		w = &throttledWriter{ResponseWriter: w, ctx: r.Context(), rate: f.bandwidth}
+	}
+	return w, true
+}
+
+// abortConnection closes the client connection without a response.
+func abortConnection(w http.ResponseWriter) bool {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return false
+	}
+	if tc, ok := conn.(*net.TCPConn); ok {
+		// Reset rather than close cleanly, like a crashed backend
+		tc.SetLinger(0)
+	}
+	conn.Close()
+	return true
+}
+
+// throttledWriter limits the response to rate bytes per second.
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx  context.Context
+	rate int
+}
+
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	chunk := max(tw.rate/10, 1) // Roughly ten writes a second
+	written := 0
+	for written < len(p) {
+		n, err := tw.ResponseWriter.Write(p[written:min(written+chunk, len(p))])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		http.NewResponseController(tw.ResponseWriter).Flush()
+		if !sleep(tw.ctx, time.Duration(n)*time.Second/time.Duration(tw.rate)) {
+			return written, tw.ctx.Err()
+		}
+	}
+	return written, nil
+}
+
+// Unwrap lets http.ResponseWriter optional interfaces (Flusher, Hijacker) pass through.
+func (tw *throttledWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// injectWebSocket applies latency and failures to a WebSocket upgrade. It
+// returns how long to keep the tunnel open before dropping it (0 to keep it
+// open), or false if the fault already finished the request.
+func (f *faultInjector) injectWebSocket(w http.ResponseWriter, r *http.Request) (time.Duration, bool) {
+	if d := f.delay(); d > 0 {
+		tag := "latency=" + d.Round(time.Millisecond).String()
+		w.Header().Add(faultHeader, tag)
+		recordCaptureFault(r, tag, false)
+		if !sleep(r.Context(), d) {
+			return 0, false
+		}
+	}
+	abort, fail := f.failure()
+	if abort {
+		log.Printf("Proxy fault: aborted WebSocket %s", r.URL.Path)
+		recordCaptureFault(r, "abort", true)
+		if !abortConnection(w) {
+			w.Header().Add(faultHeader, "abort")
+			http.Error(w, "Injected fault: connection aborted", http.StatusBadGateway)
+		}
+		return 0, false
+	}
+	if fail {
+		log.Printf("Proxy fault: WebSocket %s answered with %d", r.URL.Path, f.errorStatus)
+		tag := "error=" + strconv.Itoa(f.errorStatus)
+		w.Header().Add(faultHeader, tag)
+		recordCaptureFault(r, tag, false)
+		http.Error(w, "Injected fault: "+strconv.Itoa(f.errorStatus)+" "+http.StatusText(f.errorStatus), f.errorStatus)
+		return 0, false
+	}
+	if f.roll(f.wsDropRate) {
+		recordCaptureFault(r, "drop="+f.wsDropAfter.String(), false)
+		return f.wsDropAfter, true
+	}
+	return 0, true
+}
+
+// Faults returns the fault settings of every route, in config order.
+func (m *Manager) Faults() []RouteFaults {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := []RouteFaults{}
+	for _, l := range m.listeners {
+		for i := range l.routes {
+			out = append(out, RouteFaults{
+				Listener: l.addr,
+				Route:    i,
+				Name:     l.routes[i].name(),
+				Faults:   l.routes[i].faults.config(),
+			})
+		}
+	}
+	return out
+}
+
+// SetFaults replaces a route's fault settings. The change applies to the
+// next request and is not saved to the config file.
+func (m *Manager) SetFaults(listener string, route int, cfg config.ProxyFaultConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range m.listeners {
+		if l.addr != listener {
+			continue
+		}
+		if route < 0 || route >= len(l.routes) {
+			break
+		}
+		if err := l.routes[route].faults.set(cfg); err != nil {
+			return err
+		}
+		log.Printf("Proxy faults on %s route %d (%s): %s", listener, route, l.routes[route].name(), describeFaults(cfg))
+		return nil
+	}
+	return fmt.Errorf("%w: %s route %d", ErrRouteNotFound, listener, route)
+}
+
+// ClearFaults turns fault injection off on every route, keeping the settings
+// so they can be turned back on.
+func (m *Manager) ClearFaults() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, l := range m.listeners {
+		for i := range l.routes {
+			cfg := l.routes[i].faults.config()
+			cfg.Enabled = false
+			l.routes[i].faults.set(cfg)
+		}
+	}
+}
+
+// describeFaults summarizes fault settings for the log.
+func describeFaults(cfg config.ProxyFaultConfig) string {
+	if !cfg.Enabled {
+		return "off"
+	}
+	var parts []string
+	if cfg.Latency != "" || cfg.LatencyMax != "" {
+		latency := cfg.Latency
+		if cfg.LatencyMax != "" {
+			latency += "-" + cfg.LatencyMax
+		}
+		parts = append(parts, "latency="+latency)
+	}
+	if cfg.ErrorRate > 0 {
+		parts = append(parts, fmt.Sprintf("error=%g%%", cfg.ErrorRate))
+	}
+	if cfg.AbortRate > 0 {
+		parts = append(parts, fmt.Sprintf("abort=%g%%", cfg.AbortRate))
+	}
+	if cfg.Bandwidth > 0 {
+		parts = append(parts, fmt.Sprintf("bandwidth=%dB/s", cfg.Bandwidth))
+	}
+	if cfg.WebSocketDropRate > 0 {
+		parts = append(parts, fmt.Sprintf("websocket_drop=%g%%", cfg.WebSocketDropRate))
+	}
+	if len(parts) == 0 {
+		return "on, nothing set"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,194 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// faultManager returns a capturing manager whose first route (/api/) has the
+// given faults and always rolls p.
+func faultManager(t *testing.T, faults config.ProxyFaultConfig, p float64) *Manager {
+	t.Helper()
+	upstream := echoUpstream(t)
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen: ":0",
+		Routes: []config.ProxyRouteConfig{
+			{PathRegexp: "^/api/", Upstream: upstream.URL, Faults: faults},
+			{Upstream: upstream.URL},
+		},
+		Capture: config.ProxyCaptureConfig{Enabled: true},
+	}})
+	require.NoError(t, err)
+	if f := m.listeners[0].routes[0].faults.active(); f != nil {
+		f.random = func() float64 { return p }
+	}
+	return m
+}
+
+func TestFaults_Latency(t *testing.T) {
+	m := faultManager(t, config.ProxyFaultConfig{Enabled: true, Latency: "50ms", LatencyMax: "150ms"}, 0.5)
+
+	start := time.Now()
+	rec := send(m, "GET", "/api/x", "")
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "latency=100ms", rec.Header().Get(faultHeader))
+
+	// Other routes are untouched
+	rec = send(m, "GET", "/other", "")
+	assert.Empty(t, rec.Header().Get(faultHeader))
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Empty(t, summaries[0].Fault)
+	assert.Equal(t, "latency=100ms", summaries[1].Fault)
+}
+
+func TestFaults_Error(t *testing.T) {
+	m := faultManager(t, config.ProxyFaultConfig{Enabled: true, ErrorRate: 50, ErrorStatus: 502, AbortRate: 10}, 0.3)
+
+	rec := send(m, "GET", "/api/x", "")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Injected fault")
+	assert.Equal(t, "error=502", rec.Header().Get(faultHeader))
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 502, summaries[0].Status)
+	assert.Equal(t, "error=502", summaries[0].Fault)
+
+	// Rolls past both rates pass through
+	m.listeners[0].routes[0].faults.active().random = func() float64 { return 0.7 }
+	rec = send(m, "GET", "/api/x", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(faultHeader))
+}
+
+func TestFaults_Abort(t *testing.T) {
+	m := faultManager(t, config.ProxyFaultConfig{Enabled: true, AbortRate: 100}, 0)
+	srv := httptest.NewServer(http.HandlerFunc(m.listeners[0].serveHTTP))
+	defer srv.Close()
+
+	_, err := http.Get(srv.URL + "/api/x")
+	assert.Error(t, err)
+
+	resp, err := http.Get(srv.URL + "/other")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, 0, summaries[1].Status)
+	assert.Equal(t, "abort", summaries[1].Fault)
+}
+
+func TestFaults_WebSocket(t *testing.T) {
+	upgrade := func(srv *httptest.Server, path string) {
+		t.Helper()
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: app.test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n", path)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		io.Copy(io.Discard, conn) // Until the proxy closes the connection
+	}
+
+	// Faults on upgrades are tagged in the capture like those on requests
+	m := faultManager(t, config.ProxyFaultConfig{Enabled: true, ErrorRate: 100, ErrorStatus: 503}, 0)
+	req := httptest.NewRequest("GET", "/api/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	rec := httptest.NewRecorder()
+	m.listeners[0].serveHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "error=503", rec.Header().Get(faultHeader))
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, summaries[0].Status)
+	assert.Equal(t, "error=503", summaries[0].Fault)
+
+	m = faultManager(t, config.ProxyFaultConfig{Enabled: true, AbortRate: 100}, 0)
+	srv := httptest.NewServer(http.HandlerFunc(m.listeners[0].serveHTTP))
+	defer srv.Close()
+	upgrade(srv, "/api/ws")
+
+	drop := faultManager(t, config.ProxyFaultConfig{Enabled: true, WebSocketDropRate: 100, WebSocketDropAfter: "50ms"}, 0)
+	dropSrv := httptest.NewServer(http.HandlerFunc(drop.listeners[0].serveHTTP))
+	defer dropSrv.Close()
+	upgrade(dropSrv, "/api/ws")
+
+	summaries, err = m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 0, summaries[0].Status)
+	assert.Equal(t, "abort", summaries[0].Fault)
+
+	summaries, err = drop.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, http.StatusSwitchingProtocols, summaries[0].Status)
+	assert.Equal(t, "drop=50ms", summaries[0].Fault)
+	assert.NotEmpty(t, summaries[0].Upstream)
+}
+
+func TestFaults_Bandwidth(t *testing.T) {
+	m := faultManager(t, config.ProxyFaultConfig{Enabled: true, Bandwidth: 100}, 0)
+
+	start := time.Now()
+	rec := send(m, "POST", "/api/x", "0123456789012345678901234567890123456789")
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, "POST /api/x 0123456789012345678901234567890123456789", rec.Body.String())
+	assert.Equal(t, "bandwidth=100B/s", rec.Header().Get(faultHeader))
+}
+
+func TestManager_SetFaults(t *testing.T) {
+	m := faultManager(t, config.ProxyFaultConfig{}, 0)
+
+	faults := m.Faults()
+	require.Len(t, faults, 2)
+	assert.Equal(t, ":0", faults[0].Listener)
+	assert.Equal(t, 0, faults[0].Route)
+	assert.Equal(t, "path=^/api/", faults[0].Name)
+	assert.False(t, faults[0].Faults.Enabled)
+	assert.Equal(t, "*", faults[1].Name)
+
+	// Changes apply to the next request
+	require.NoError(t, m.SetFaults(":0", 1, config.ProxyFaultConfig{Enabled: true, ErrorRate: 100}))
+	assert.Equal(t, http.StatusServiceUnavailable, send(m, "GET", "/other", "").Code)
+	assert.Equal(t, http.StatusOK, send(m, "GET", "/api/x", "").Code)
+	assert.True(t, m.Faults()[1].Faults.Enabled)
+
+	// Clearing keeps the settings but turns them off
+	m.ClearFaults()
+	assert.Equal(t, http.StatusOK, send(m, "GET", "/other", "").Code)
+	faults = m.Faults()
+	assert.False(t, faults[1].Faults.Enabled)
+	assert.Equal(t, 100.0, faults[1].Faults.ErrorRate)
+
+	err := m.SetFaults(":0", 2, config.ProxyFaultConfig{Enabled: true})
+	assert.ErrorIs(t, err, ErrRouteNotFound)
+	err = m.SetFaults(":9", 0, config.ProxyFaultConfig{Enabled: true})
+	assert.ErrorIs(t, err, ErrRouteNotFound)
+
+	err = m.SetFaults(":0", 0, config.ProxyFaultConfig{Enabled: true, ErrorRate: 150})
+	assert.ErrorContains(t, err, "error_rate")
+	assert.False(t, m.Faults()[0].Faults.Enabled)
+}
@@ -0,0 +1,449 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// mockHeader names the stub or fixture that answered a request, so clients
+// and captures can tell mock responses from the upstream's.
+const mockHeader = "X-Trellis-Mock"
+
+// maxMockBody bounds the request and response bodies the mock reads to match
+// and record. Larger exchanges are proxied without being recorded.
+const maxMockBody = 10 << 20
+
+// mockResponder answers requests with stub responses and recorded fixtures,
+// falling back to the upstream when allowed.
+type mockResponder struct {
+	stubs    []mockStub
+	fixtures string // Fixture directory, empty if none
+	record   bool   // Save upstream responses as fixtures instead of serving them
+	upstream http.Handler
+	actions  *routeActions
+}
+
+// mockStub is a compiled canned response.
+type mockStub struct {
+	method      string
+	path        *regexp.Regexp // nil matches any path
+	requestBody *regexp.Regexp // nil matches any body
+	status      int
+	headers     map[string]string
+	body        *template.Template // nil when serving a file
+	file        string
+}
+
+// mockRequest is the data stub body templates see.
+type mockRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Params map[string]string // Named groups of the stub's path regex
+	Body   string
+	JSON   any // Request body parsed as JSON, nil if it isn't
+}
+
+var mockFuncs = template.FuncMap{
+	// json encodes a value, e.g. {"echo": {{json .Body}}}
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"now": func() string { return time.Now().UTC().Format(time.RFC3339) },
+}
+
+// newMockResponder compiles a route's mock config. upstream may be nil if
+// the mock answers every request.
+func newMockResponder(cfg config.ProxyMockConfig, upstream http.Handler, actions *routeActions) (*mockResponder, error) {
+	m := &mockResponder{
+		fixtures: expandPath(cfg.Fixtures),
+		record:   cfg.Mode == "record",
+		actions:  actions,
+	}
+	if cfg.Fallback || m.record {
+		m.upstream = upstream
+	}
+	for i, resp := range cfg.Responses {
+		s := mockStub{
+			method:  strings.ToUpper(resp.Method),
+			status:  resp.Status,
+			headers: resp.Headers,
+		}
+		if s.status == 0 {
+			s.status = http.StatusOK
+		}
+		var err error
+		if resp.Path != "" {
+			if s.path, err = regexp.Compile(resp.Path); err != nil {
+				return nil, fmt.Errorf("responses[%d]: invalid path regex %q: %w", i, resp.Path, err)
+			}
+		}
+		if resp.RequestBody != "" {
+			if s.requestBody, err = regexp.Compile(resp.RequestBody); err != nil {
+				return nil, fmt.Errorf("responses[%d]: invalid request_body regex %q: %w", i, resp.RequestBody, err)
+			}
+		}
+		if resp.File != "" {
+			s.file = expandPath(resp.File)
+		} else if s.body, err = template.New("body").Funcs(mockFuncs).Parse(resp.Body); err != nil {
+			return nil, fmt.Errorf("responses[%d]: invalid body template: %w", i, err)
+		}
+		m.stubs = append(m.stubs, s)
+	}
+	return m, nil
+}
+
+// ServeHTTP answers r from the first matching stub, then from its fixture,
+// then from the upstream if allowed.
+func (m *mockResponder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, complete, err := readMockBody(r)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	for i := range m.stubs {
+		if m.stubs[i].matches(r, body, complete) {
+			m.serveStub(w, r, i, body)
+			return
+		}
+	}
+
+	name := ""
+	if m.fixtures != "" && complete {
+		name = fixtureName(r.Method, r.URL.RequestURI(), body)
+	}
+	if m.record && m.upstream != nil {
+		m.recordFixture(w, r, name, body)
+		return
+	}
+	if name != "" {
+		f, err := loadFixture(filepath.Join(m.fixtures, name))
+		if err == nil {
+			m.serveFixture(w, r, name, f)
+			return
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Proxy mock: %v", err)
+		}
+	}
+	if m.upstream != nil {
+		m.upstream.ServeHTTP(w, r)
+		return
+	}
+
+	msg := "No mock response for " + r.Method + " " + r.URL.RequestURI()
+	if name != "" {
+		msg += " (no fixture " + name + ")"
+	}
+	http.Error(w, msg, http.StatusBadGateway)
+}
+
+// readMockBody reads the request body for matching, leaving r.Body
+// readable. complete is false if the body was too large to read whole.
+func readMockBody(r *http.Request) (body []byte, complete bool, err error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true, nil
+	}
+	body, err = io.ReadAll(io.LimitReader(r.Body, maxMockBody+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(body) > maxMockBody {
+		r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return nil, false, nil
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true, nil
+}
+
+// readCloser reads from one reader and closes another.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (s *mockStub) matches(r *http.Request, body []byte, complete bool) bool {
+	if s.method != "" && s.method != r.Method {
+		return false
+	}
+	if s.path != nil && !s.path.MatchString(r.URL.Path) {
+		return false
+	}
+	if s.requestBody != nil && (!complete || !s.requestBody.Match(body)) {
+		return false
+	}
+	return true
+}
+
+func (m *mockResponder) serveStub(w http.ResponseWriter, r *http.Request, i int, body []byte) {
+	s := &m.stubs[i]
+	var out []byte
+	contentType := ""
+	if s.file != "" {
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			log.Printf("Proxy mock: %v", err)
+			http.Error(w, "Mock response file not readable", http.StatusInternalServerError)
+			return
+		}
+		out = data
+		contentType = mime.TypeByExtension(filepath.Ext(s.file))
+	} else {
+		data := mockRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header,
+			Params: map[string]string{},
+			Body:   string(body),
+		}
+		if s.path != nil {
+			match := s.path.FindStringSubmatch(r.URL.Path)
+			for j, group := range s.path.SubexpNames() {
+				if group != "" && j < len(match) {
+					data.Params[group] = match[j]
+				}
+			}
+		}
+		json.Unmarshal(body, &data.JSON)
+		var buf bytes.Buffer
+		if err := s.body.Execute(&buf, data); err != nil {
+			http.Error(w, "Mock response template failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out = buf.Bytes()
+		if trimmed := bytes.TrimSpace(out); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+			contentType = "application/json"
+		}
+	}
+
+	h := w.Header()
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	for name, value := range s.headers {
+		h.Set(name, value)
+	}
+	m.writeResponse(w, r, "stub "+strconv.Itoa(i), s.status, out)
+}
+
+// writeResponse sends a mock response, tagged with its source and with the
+// route's response header actions applied.
+func (m *mockResponder) writeResponse(w http.ResponseWriter, r *http.Request, source string, status int, body []byte) {
+	h := w.Header()
+	h.Set(mockHeader, source)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	m.actions.applyResponseHeaders(h)
+	recordCaptureMock(r, source)
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// fixture is a recorded upstream response, stored as JSON.
+type fixture struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`                    // Path and query
+	RequestBody string      `json:"request_body,omitempty"` // For reference; matching uses the file name
+	Status      int         `json:"status"`
+	Headers     http.Header `json:"headers"`
+	Body        string      `json:"body"`
+	Base64      bool        `json:"base64,omitempty"` // Body is base64 (binary or compressed)
+	Recorded    time.Time   `json:"recorded"`
+}
+
+var fixtureNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixtureName returns the file a request's fixture is stored in. The hash
+// covers the method, path, query and body; the rest keeps it readable.
+func fixtureName(method, uri string, body []byte) string {
+	sum := sha256.New()
+	io.WriteString(sum, method+"\n"+uri+"\n")
+	sum.Write(body)
+
+	path, _, _ := strings.Cut(uri, "?")
+	slug := strings.Trim(fixtureNameUnsafe.ReplaceAllString(path, "-"), "-.")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	if slug == "" {
+		slug = "root"
+	}
+	return method + "_" + slug + "_" + hex.EncodeToString(sum.Sum(nil)[:6]) + ".json"
+}
+
+func loadFixture(path string) (*fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+func (m *mockResponder) serveFixture(w http.ResponseWriter, r *http.Request, name string, f *fixture) {
+	body := []byte(f.Body)
+	if f.Base64 {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(f.Body); err != nil {
+			http.Error(w, "Invalid fixture body: "+name, http.StatusInternalServerError)
+			return
+		}
+	}
+	h := w.Header()
+	for k, v := range f.Headers {
+		h[k] = append([]string(nil), v...)
+	}
+	m.writeResponse(w, r, "fixture "+name, f.Status, body)
+}
+
+// recordFixture proxies r to the upstream and saves the response under name,
+// unless the exchange can't be replayed (too large, or failed).
+func (m *mockResponder) recordFixture(w http.ResponseWriter, r *http.Request, name string, reqBody []byte) {
+	if name == "" {
+		m.upstream.ServeHTTP(w, r)
+		return
+	}
+	state := &fixtureState{}
+	r = r.WithContext(context.WithValue(r.Context(), fixtureKey{}, state))
+	fw := &fixtureWriter{ResponseWriter: w}
+
+	m.upstream.ServeHTTP(fw, r)
+
+	if state.failed || fw.overflow || fw.status == 0 || r.Context().Err() != nil {
+		return
+	}
+	f := fixture{
+		Method:   r.Method,
+		URL:      r.URL.RequestURI(),
+		Status:   fw.status,
+		Headers:  fw.header,
+		Recorded: time.Now(),
+	}
+	f.Headers.Del("Content-Length")
+	f.Headers.Del(faultHeader)
+	f.Headers.Del("Date")
+	if utf8.Valid(reqBody) {
+		f.RequestBody = string(reqBody)
+	}
+	if body := fw.body.Bytes(); utf8.Valid(body) && f.Headers.Get("Content-Encoding") == "" {
+		f.Body = string(body)
+	} else {
+		f.Body = base64.StdEncoding.EncodeToString(body)
+		f.Base64 = true
+	}
+	if err := saveFixture(filepath.Join(m.fixtures, name), &f); err != nil {
+		log.Printf("Proxy mock: %v", err)
+		return
+	}
+	recordCaptureMock(r, "recorded "+name)
+}
+
+// fixtureKey is the request context key holding the state of a request
+// being recorded as a fixture.
+type fixtureKey struct{}
+
+type fixtureState struct {
+	failed bool // The upstream didn't answer
+}
+
+// recordFixtureError notes that a request being recorded got no upstream
+// response, so its error page isn't saved as a fixture.
+func recordFixtureError(req *http.Request) {
+	if s, ok := req.Context().Value(fixtureKey{}).(*fixtureState); ok {
+		s.failed = true
+	}
+}
+
+func saveFixture(path string, f *fixture) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create fixture directory: %w", err)
+	}
+	// Write atomically so a concurrent replay never reads half a fixture
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("save fixture: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save fixture: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save fixture: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("save fixture: %w", err)
+	}
+	return nil
+}
+
+// fixtureWriter copies a proxied response so it can be saved as a fixture.
+type fixtureWriter struct {
+	http.ResponseWriter
+	status   int
+	header   http.Header // Snapshot at WriteHeader
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (fw *fixtureWriter) WriteHeader(code int) {
+	if fw.status == 0 && code >= 200 {
+		fw.status = code
+		fw.header = fw.ResponseWriter.Header().Clone()
+	}
+	fw.ResponseWriter.WriteHeader(code)
+}
+
+func (fw *fixtureWriter) Write(p []byte) (int, error) {
+	if fw.status == 0 {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if fw.body.Len()+len(p) > maxMockBody {
+		fw.overflow = true
+	} else if !fw.overflow {
+		fw.body.Write(p)
+	}
+	return fw.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseWriter optional interfaces (Flusher, Hijacker) pass through.
+func (fw *fixtureWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
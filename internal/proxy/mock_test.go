@@ -0,0 +1,151 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func mockManager(t *testing.T, route config.ProxyRouteConfig) *Manager {
+	t.Helper()
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen:  ":0",
+		Routes:  []config.ProxyRouteConfig{route},
+		Capture: config.ProxyCaptureConfig{Enabled: true},
+	}})
+	require.NoError(t, err)
+	return m
+}
+
+func TestMock_Stubs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.svg"), []byte("<svg/>"), 0o644))
+
+	m := mockManager(t, config.ProxyRouteConfig{
+		Mock: config.ProxyMockConfig{Responses: []config.ProxyMockResponse{
+			{Method: "get", Path: "^/api/users/(?P<id>[0-9]+)$", Body: `{"id": {{.Params.id}}, "q": {{json (.Query.Get "q")}}}`},
+			{Method: "POST", Path: "^/api/orders$", RequestBody: `"qty":\s*0`, Status: 422, Body: `{"error": "qty"}`},
+			{Method: "POST", Path: "^/api/orders$", Status: 201, Headers: map[string]string{"Location": "/api/orders/1"}, Body: `{"qty": {{.JSON.qty}}}`},
+			{Path: "^/logo.svg$", File: filepath.Join(dir, "logo.svg")},
+		}},
+	})
+
+	tests := []struct {
+		method, target, body string
+		status               int
+		want                 string
+		source               string
+	}{
+		{"GET", "/api/users/42?q=a%22b", "", 200, `{"id": 42, "q": "a\"b"}`, "stub 0"},
+		{"POST", "/api/orders", `{"qty": 0}`, 422, `{"error": "qty"}`, "stub 1"},
+		{"POST", "/api/orders", `{"qty": 3}`, 201, `{"qty": 3}`, "stub 2"},
+		{"GET", "/logo.svg", "", 200, "<svg/>", "stub 3"},
+		{"GET", "/api/users/x", "", 502, "No mock response for GET /api/users/x", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec := send(m, tt.method, tt.target, tt.body)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.want, strings.TrimSpace(rec.Body.String()))
+			assert.Equal(t, tt.source, rec.Header().Get(mockHeader))
+		})
+	}
+
+	rec := send(m, "GET", "/api/users/1", "")
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	rec = send(m, "POST", "/api/orders", `{"qty": 1}`)
+	assert.Equal(t, "/api/orders/1", rec.Header().Get("Location"))
+	rec = send(m, "GET", "/logo.svg", "")
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+
+	summaries, err := m.Requests(CaptureFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "stub 3", summaries[0].Mock)
+	assert.Empty(t, summaries[0].Upstream)
+}
+
+func TestMock_RecordAndReplay(t *testing.T) {
+	upstream := echoUpstream(t)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	recorder := mockManager(t, config.ProxyRouteConfig{
+		Upstream: upstream.URL,
+		Mock:     config.ProxyMockConfig{Fixtures: dir, Mode: "record"},
+	})
+	rec := send(recorder, "POST", "/api/orders?dry=1", `{"qty":2}`)
+	assert.Equal(t, `POST /api/orders?dry=1 {"qty":2}`, rec.Body.String())
+	send(recorder, "GET", "/missing", "")
+
+	summaries, err := recorder.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "recorded "+fixtureName("POST", "/api/orders?dry=1", []byte(`{"qty":2}`)), summaries[1].Mock)
+	assert.NotEmpty(t, summaries[1].Upstream)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Failed upstream requests aren't recorded
+	down := mockManager(t, config.ProxyRouteConfig{
+		Upstream: "127.0.0.1:1",
+		Mock:     config.ProxyMockConfig{Fixtures: dir, Mode: "record"},
+	})
+	assert.Equal(t, http.StatusBadGateway, send(down, "GET", "/down", "").Code)
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	// Replay without an upstream matches method, path, query and body
+	replayer := mockManager(t, config.ProxyRouteConfig{
+		Mock: config.ProxyMockConfig{Fixtures: dir},
+	})
+	rec = send(replayer, "POST", "/api/orders?dry=1", `{"qty":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `POST /api/orders?dry=1 {"qty":2}`, rec.Body.String())
+	assert.Equal(t, "Bearer abc", rec.Header().Get("X-Echo-Auth"))
+	assert.Contains(t, rec.Header().Get(mockHeader), "fixture POST_api-orders_")
+
+	rec = send(replayer, "GET", "/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = send(replayer, "POST", "/api/orders?dry=1", `{"qty":3}`)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "no fixture POST_api-orders_")
+
+	// With fallback, misses go to the upstream
+	fallback := mockManager(t, config.ProxyRouteConfig{
+		Upstream: upstream.URL,
+		Mock:     config.ProxyMockConfig{Fixtures: dir, Fallback: true},
+	})
+	rec = send(fallback, "POST", "/api/orders?dry=1", `{"qty":3}`)
+	assert.Equal(t, `POST /api/orders?dry=1 {"qty":3}`, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(mockHeader))
+}
+
+func TestMock_Invalid(t *testing.T) {
+	_, err := newRoute(config.ProxyRouteConfig{Mock: config.ProxyMockConfig{Responses: []config.ProxyMockResponse{{Body: "{{.Nope"}}}}, false)
+	assert.ErrorContains(t, err, "invalid body template")
+
+	_, err = newRoute(config.ProxyRouteConfig{}, false)
+	assert.ErrorContains(t, err, "upstream is required")
+
+	// Mocked routes have no upstream to tunnel WebSockets to
+	m := mockManager(t, config.ProxyRouteConfig{Mock: config.ProxyMockConfig{Responses: []config.ProxyMockResponse{{Body: "ok"}}}})
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	rec := httptest.NewRecorder()
+	m.listeners[0].serveHTTP(rec, req)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
@@ -1,7 +1,8 @@
 // Copyright © 2026 Groups.io, Inc.
 // SPDX-License-Identifier: Apache-2.0
 
-// Package proxy implements reverse proxy listeners with path-based routing.
+// Package proxy implements reverse proxy listeners with path, host and
+// header based routing.
 package proxy
 
 import (
@@ -16,9 +17,11 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tailscale/tscert"
@@ -29,20 +32,47 @@ import (
 type Manager struct {
 	listeners []*Listener
 	mu        sync.Mutex
+	captureID atomic.Uint64 // Last captured request ID, shared by all listeners
+	started   bool
+
+	// Kept so listeners added by Update get them too
+	accessLog      func(AccessLogEntry)
+	worktreeSource WorktreeSource
 }
 
 // Listener represents a single proxy listener with routes.
 type Listener struct {
-	addr   string
-	server *http.Server
-	routes []route
+	cfg       config.ProxyListenerConfig
+	addr      string
+	tls       bool
+	server    *http.Server
+	routes    []route
+	capture   *captureRing     // nil unless capture is enabled
+	worktrees *worktreeRouting // nil unless worktree routing is enabled
+	nextID    func() uint64
+
+	accessLog         func(AccessLogEntry) // nil until set, or if the access log is disabled
+	accessLogDisabled bool
+	traceHeader       string
 }
 
 // route is a compiled proxy route.
 type route struct {
-	pattern  *regexp.Regexp // nil means catch-all
-	upstream *url.URL
-	proxy    *httputil.ReverseProxy
+	pattern   *regexp.Regexp // nil matches any path
+	matchers  routeMatchers
+	desc      string           // Matchers summary, "*" for a catch-all
+	target    *target          // nil when upstreams are expanded per worktree
+	worktrees *worktreeTargets // nil unless the listener routes by worktree
+	faults    *routeFaults
+}
+
+// target is an upstream a route proxies to.
+type target struct {
+	worktree string                 // Worktree the upstream was expanded for, empty if not per worktree
+	upstream *url.URL               // nil if the route's mock answers every request
+	proxy    *httputil.ReverseProxy // nil without an upstream
+	handler  http.Handler           // The mock responder, or proxy
+	actions  *routeActions
 }
 
 // NewManager creates a new proxy manager from config.
@@ -56,6 +86,7 @@ func NewManager(configs []config.ProxyListenerConfig) (*Manager, error) {
 		if err != nil {
 			return nil, fmt.Errorf("proxy[%d]: %w", i, err)
 		}
+		listener.nextID = func() uint64 { return m.captureID.Add(1) }
 		m.listeners = append(m.listeners, listener)
 	}
 
@@ -64,11 +95,21 @@ func NewManager(configs []config.ProxyListenerConfig) (*Manager, error) {
 
 func newListener(cfg config.ProxyListenerConfig) (*Listener, error) {
 	l := &Listener{
-		addr: cfg.Listen,
+		cfg:               cfg,
+		addr:              cfg.Listen,
+		accessLogDisabled: cfg.AccessLog.Disabled,
+		traceHeader:       cfg.AccessLog.TraceHeader,
+	}
+	if cfg.Capture.Enabled {
+		l.capture = newCaptureRing(cfg.Capture)
+	}
+
+	if cfg.Worktrees.Enabled {
+		l.worktrees = newWorktreeRouting(cfg.Worktrees)
 	}
 
 	for j, routeCfg := range cfg.Routes {
-		r, err := newRoute(routeCfg)
+		r, err := newRoute(routeCfg, cfg.Worktrees.Enabled)
 		if err != nil {
 			return nil, fmt.Errorf("routes[%d]: %w", j, err)
 		}
@@ -102,11 +143,14 @@ func newListener(cfg config.ProxyListenerConfig) (*Listener, error) {
 			Certificates: []tls.Certificate{cert},
 		}
 	}
+	l.tls = l.server.TLSConfig != nil
 
 	return l, nil
 }
 
-func newRoute(cfg config.ProxyRouteConfig) (route, error) {
+// newRoute compiles a route. When perWorktree is set, the upstream and
+// header values are templates expanded for each worktree as it is selected.
+func newRoute(cfg config.ProxyRouteConfig, perWorktree bool) (route, error) {
 	r := route{}
 
 	// Compile path regex if specified
@@ -118,6 +162,70 @@ func newRoute(cfg config.ProxyRouteConfig) (route, error) {
 		r.pattern = re
 	}
 
+	matchers, err := newRouteMatchers(cfg)
+	if err != nil {
+		return r, err
+	}
+	r.matchers = matchers
+	if r.faults, err = newRouteFaults(cfg.Faults); err != nil {
+		return r, fmt.Errorf("faults: %w", err)
+	}
+
+	desc := matchers.describe(cfg)
+	if r.pattern != nil {
+		desc = append(desc, "path="+r.pattern.String())
+	}
+	r.desc = "*"
+	if len(desc) > 0 {
+		r.desc = strings.Join(desc, " ")
+	}
+
+	if perWorktree {
+		if r.worktrees, err = newWorktreeTargets(cfg); err != nil {
+			return r, err
+		}
+		return r, nil
+	}
+	if r.target, err = newTarget(cfg, ""); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// newTarget parses the route's upstream and sets up its reverse proxy, with
+// the route's mock responder in front of it if it has one.
+func newTarget(cfg config.ProxyRouteConfig, worktree string) (*target, error) {
+	actions, err := newRouteActions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t := &target{worktree: worktree, actions: actions}
+	if cfg.Upstream != "" {
+		if t.upstream, t.proxy, err = newReverseProxy(cfg, actions); err != nil {
+			return nil, err
+		}
+		t.handler = t.proxy
+	}
+	if cfg.Mock.Configured() {
+		var upstream http.Handler
+		if t.proxy != nil {
+			upstream = t.proxy
+		}
+		mock, err := newMockResponder(cfg.Mock, upstream, actions)
+		if err != nil {
+			return nil, fmt.Errorf("mock: %w", err)
+		}
+		t.handler = mock
+	}
+	if t.handler == nil {
+		return nil, errors.New("upstream is required")
+	}
+	return t, nil
+}
+
+// newReverseProxy parses the route's upstream and sets up a reverse proxy to
+// it that applies the route's actions.
+func newReverseProxy(cfg config.ProxyRouteConfig, actions *routeActions) (*url.URL, *httputil.ReverseProxy, error) {
 	// Parse upstream address
 	upstream := cfg.Upstream
 	if !strings.Contains(upstream, "://") {
@@ -125,9 +233,8 @@ func newRoute(cfg config.ProxyRouteConfig) (route, error) {
 	}
 	u, err := url.Parse(upstream)
 	if err != nil {
-		return r, fmt.Errorf("invalid upstream %q: %w", cfg.Upstream, err)
+		return nil, nil, fmt.Errorf("invalid upstream %q: %w", cfg.Upstream, err)
 	}
-	r.upstream = u
 
 	// Create reverse proxy with a transport configured for proxying
 	proxy := httputil.NewSingleHostReverseProxy(u)
@@ -143,10 +250,12 @@ func newRoute(cfg config.ProxyRouteConfig) (route, error) {
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
-	// Custom director to preserve original Host and add forwarding headers
+	// Custom director to rewrite the path, preserve original Host, add
+	// forwarding headers and apply the route's request header actions
 	originalDirector := proxy.Director
 	proxy.Director = func(req *http.Request) {
 		origHost := req.Host
+		actions.rewritePath(req)
 		originalDirector(req)
 		// Preserve the original client Host header (like Caddy/Nginx default behavior)
 		// The default director sets req.Host to the upstream, but backends typically
@@ -163,9 +272,19 @@ func newRoute(cfg config.ProxyRouteConfig) (route, error) {
 				req.Header.Set("X-Forwarded-Proto", "http")
 			}
 		}
+		actions.applyRequestHeaders(req)
+	}
+
+	if len(actions.responseSet) > 0 || len(actions.responseRemove) > 0 {
+		proxy.ModifyResponse = func(resp *http.Response) error {
+			actions.applyResponseHeaders(resp.Header)
+			return nil
+		}
 	}
 
 	proxy.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		recordCaptureError(req, err)
+		recordFixtureError(req)
 		// Client disconnected — not a proxy error, don't log or write a response
 		if errors.Is(err, context.Canceled) {
 			return
@@ -174,8 +293,7 @@ func newRoute(cfg config.ProxyRouteConfig) (route, error) {
 		http.Error(w, "Bad Gateway", http.StatusBadGateway)
 	}
 
-	r.proxy = proxy
-	return r, nil
+	return u, proxy, nil
 }
 
 // statusRecorder wraps http.ResponseWriter to capture the status code.
@@ -194,46 +312,140 @@ func (sr *statusRecorder) Unwrap() http.ResponseWriter {
 	return sr.ResponseWriter
 }
 
+// host returns the upstream host, or "mock" for a route without one.
+func (t *target) host() string {
+	if t.upstream == nil {
+		return "mock"
+	}
+	return t.upstream.Host
+}
+
+// name identifies the route in captured requests.
+func (rt *route) name() string {
+	return rt.desc
+}
+
+// matches reports whether the request satisfies the route's path regex and
+// every host, method, header and query matcher.
+func (rt *route) matches(r *http.Request) bool {
+	if rt.pattern != nil && !rt.pattern.MatchString(r.URL.Path) {
+		return false
+	}
+	return rt.matchers.match(r)
+}
+
+// match returns the first route matching the request, or nil.
+func (l *Listener) match(r *http.Request) *route {
+	for i := range l.routes {
+		if l.routes[i].matches(r) {
+			return &l.routes[i]
+		}
+	}
+	return nil
+}
+
 // serveHTTP routes the request to the first matching route.
 func (l *Listener) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if l.worktrees != nil && r.URL.Path == worktreeSelectPath {
+		l.worktrees.serveSelect(w, r)
+		return
+	}
+
+	if l.capture != nil || l.accessLog != nil {
+		l.serveCaptured(w, r, 0)
+		return
+	}
+
 	// Check for WebSocket upgrade
 	if isWebSocket(r) {
 		l.serveWebSocket(w, r)
 		return
 	}
+	l.serveRoute(w, r)
+}
+
+// serveRoute proxies the request to the first matching route and returns
+// that route and the target it was sent to. Either is nil if no route
+// matched or no target could be resolved.
+func (l *Listener) serveRoute(w http.ResponseWriter, r *http.Request) (*route, *target) {
+	route, t, ok := l.resolve(w, r)
+	if !ok {
+		return route, nil
+	}
 
 	start := time.Now()
-	for _, route := range l.routes {
-		if route.pattern == nil || route.pattern.MatchString(r.URL.Path) {
-			rec := &statusRecorder{ResponseWriter: w, statusCode: 200}
-			route.proxy.ServeHTTP(rec, r)
-			elapsed := time.Since(start)
-			// Log slow requests and server errors, but not client cancellations
-			if (elapsed >= 5*time.Second || rec.statusCode >= 500) && r.Context().Err() == nil {
-				log.Printf("Proxy: %s %s -> %s [%d] (%s)", r.Method, r.URL.Path, route.upstream.Host, rec.statusCode, elapsed.Round(time.Millisecond))
-			}
-			return
+	rec := &statusRecorder{ResponseWriter: w, statusCode: 200}
+	if f := route.faults.active(); f != nil {
+		fw, proceed := f.inject(rec, r)
+		if !proceed {
+			return route, t
+		}
+		t.handler.ServeHTTP(fw, r)
+	} else {
+		t.handler.ServeHTTP(rec, r)
+	}
+	elapsed := time.Since(start)
+	// Log slow requests and server errors, but not client cancellations
+	if (elapsed >= 5*time.Second || rec.statusCode >= 500) && r.Context().Err() == nil {
+		var note string
+		if faults := rec.Header().Values(faultHeader); len(faults) > 0 {
+			note = " [injected " + strings.Join(faults, ", ") + "]"
+		}
+		if source := rec.Header().Get(mockHeader); source != "" {
+			note += " [mock " + source + "]"
 		}
+		log.Printf("Proxy: %s %s -> %s [%d] (%s)%s", r.Method, r.URL.Path, t.host(), rec.statusCode, elapsed.Round(time.Millisecond), note)
+	}
+	return route, t
+}
+
+// resolve finds the route and target for the request, writing an error
+// response and returning false if there is none.
+func (l *Listener) resolve(w http.ResponseWriter, r *http.Request) (*route, *target, bool) {
+	route := l.match(r)
+	if route == nil {
+		// No route matched (shouldn't happen if config has a catch-all)
+		http.Error(w, "No matching route", http.StatusBadGateway)
+		return nil, nil, false
+	}
+	if route.target != nil {
+		return route, route.target, true
 	}
 
-	// No route matched (shouldn't happen if config has a catch-all)
-	http.Error(w, "No matching route", http.StatusBadGateway)
+	wt, err := l.worktrees.selectWorktree(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return route, nil, false
+	}
+	t, err := route.worktrees.forWorktree(wt)
+	if err != nil {
+		log.Printf("Proxy: worktree %s: %v", wt.Name, err)
+		http.Error(w, "Bad Gateway", http.StatusBadGateway)
+		return route, nil, false
+	}
+	return route, t, true
 }
 
 // serveWebSocket handles WebSocket upgrade requests by tunneling the
-// connection to the matched upstream.
-func (l *Listener) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+// connection to the matched upstream. It returns the matched route and
+// target, like serveRoute, once the tunnel closes.
+func (l *Listener) serveWebSocket(w http.ResponseWriter, r *http.Request) (*route, *target) {
 	// Find matching route
-	var target *url.URL
-	for _, route := range l.routes {
-		if route.pattern == nil || route.pattern.MatchString(r.URL.Path) {
-			target = route.upstream
-			break
-		}
+	route, t, ok := l.resolve(w, r)
+	if !ok {
+		return route, nil
 	}
+	target := t.upstream
 	if target == nil {
-		http.Error(w, "No matching route", http.StatusBadGateway)
-		return
+		http.Error(w, "WebSocket upgrades need an upstream; this route is mocked", http.StatusBadGateway)
+		return route, t
+	}
+
+	var dropAfter time.Duration
+	if f := route.faults.active(); f != nil {
+		if dropAfter, ok = f.injectWebSocket(w, r); !ok {
+			return route, t
+		}
 	}
 
 	// Dial upstream
@@ -242,30 +454,45 @@ func (l *Listener) serveWebSocket(w http.ResponseWriter, r *http.Request) {
 	upstreamConn, err := dialer.Dial("tcp", targetAddr)
 	if err != nil {
 		log.Printf("WebSocket proxy: failed to connect to %s: %v", targetAddr, err)
+		recordCaptureError(r, err)
 		http.Error(w, "Bad Gateway", http.StatusBadGateway)
-		return
+		return route, t
 	}
 
-	// Hijack the client connection
-	hijacker, ok := w.(http.Hijacker)
-	if !ok {
+	// Hijack the client connection, through any capturing writer
+	clientConn, clientBuf, err := http.NewResponseController(w).Hijack()
+	if errors.Is(err, http.ErrNotSupported) {
 		upstreamConn.Close()
 		http.Error(w, "WebSocket hijack not supported", http.StatusInternalServerError)
-		return
+		return route, t
 	}
-	clientConn, clientBuf, err := hijacker.Hijack()
 	if err != nil {
 		upstreamConn.Close()
 		log.Printf("WebSocket proxy: hijack failed: %v", err)
-		return
+		recordCaptureError(r, err)
+		return route, t
 	}
 
-	// Write the original HTTP request to the upstream connection (preserving Upgrade headers)
-	if err := r.Write(upstreamConn); err != nil {
+	// Write the request to the upstream connection (preserving Upgrade headers),
+	// with the route's path rewrite and request header actions applied
+	out := r.Clone(r.Context())
+	t.actions.rewritePath(out)
+	t.actions.applyRequestHeaders(out)
+	if err := out.Write(upstreamConn); err != nil {
 		clientConn.Close()
 		upstreamConn.Close()
 		log.Printf("WebSocket proxy: failed to write request to upstream: %v", err)
-		return
+		recordCaptureError(r, err)
+		return route, t
+	}
+
+	if dropAfter > 0 {
+		drop := time.AfterFunc(dropAfter, func() {
+			log.Printf("Proxy fault: dropped WebSocket %s after %s", r.URL.Path, dropAfter)
+			clientConn.Close()
+			upstreamConn.Close()
+		})
+		defer drop.Stop()
 	}
 
 	// Bidirectional copy — when one direction closes, shut down the other
@@ -305,6 +532,7 @@ func (l *Listener) serveWebSocket(w http.ResponseWriter, r *http.Request) {
 	wg.Wait()
 	clientConn.Close()
 	upstreamConn.Close()
+	return route, t
 }
 
 // isWebSocket returns true if the request is a WebSocket upgrade request.
@@ -318,31 +546,98 @@ func (m *Manager) Start(ctx context.Context) error {
 	defer m.mu.Unlock()
 
 	for _, l := range m.listeners {
-		listener := l // capture for goroutine
-		go func() {
-			var err error
-			if listener.server.TLSConfig != nil {
-				log.Printf("Proxy listener starting on %s (TLS)", listener.addr)
-				// TLS certs are already loaded in TLSConfig, use empty paths
-				err = listener.server.ListenAndServeTLS("", "")
-			} else {
-				log.Printf("Proxy listener starting on %s", listener.addr)
-				err = listener.server.ListenAndServe()
-			}
-			if err != nil && err != http.ErrServerClosed {
-				log.Printf("Proxy listener %s error: %v", listener.addr, err)
-			}
-		}()
+		l.start()
 	}
+	m.started = true
 
 	return nil
 }
 
+// start serves the listener in its own goroutine.
+func (l *Listener) start() {
+	go func() {
+		var err error
+		if l.server.TLSConfig != nil {
+			log.Printf("Proxy listener starting on %s (TLS)", l.addr)
+			// TLS certs are already loaded in TLSConfig, use empty paths
+			err = l.server.ListenAndServeTLS("", "")
+		} else {
+			log.Printf("Proxy listener starting on %s", l.addr)
+			err = l.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Printf("Proxy listener %s error: %v", l.addr, err)
+		}
+	}()
+}
+
+// Update applies a new proxy config. Listeners whose config is unchanged
+// keep running untouched; removed and changed listeners are shut down, and
+// changed and added ones are started from the new config. Changed listeners
+// lose their captured requests and runtime faults. If any listener fails to
+// compile, nothing is changed.
+func (m *Manager) Update(ctx context.Context, configs []config.ProxyListenerConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := make(map[string]*Listener, len(m.listeners))
+	for _, l := range m.listeners {
+		current[l.addr] = l
+	}
+
+	listeners := make([]*Listener, 0, len(configs))
+	var added []*Listener
+	keep := make(map[*Listener]bool)
+	for i, cfg := range configs {
+		if l, ok := current[cfg.Listen]; ok && reflect.DeepEqual(l.cfg, cfg) {
+			keep[l] = true
+			listeners = append(listeners, l)
+			continue
+		}
+		l, err := newListener(cfg)
+		if err != nil {
+			return fmt.Errorf("proxy[%d]: %w", i, err)
+		}
+		l.nextID = func() uint64 { return m.captureID.Add(1) }
+		if !l.accessLogDisabled {
+			l.accessLog = m.accessLog
+		}
+		if l.worktrees != nil {
+			l.worktrees.source = m.worktreeSource
+		}
+		listeners = append(listeners, l)
+		added = append(added, l)
+	}
+
+	var firstErr error
+	for _, l := range m.listeners {
+		if keep[l] || !m.started {
+			continue
+		}
+		log.Printf("Proxy listener stopping on %s", l.addr)
+		if err := l.server.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down proxy listener %s: %v", l.addr, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	m.listeners = listeners
+	if m.started {
+		for _, l := range added {
+			l.start()
+		}
+	}
+	return firstErr
+}
+
 // Shutdown gracefully shuts down all proxy listeners.
 func (m *Manager) Shutdown(ctx context.Context) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	m.started = false
 	var firstErr error
 	for _, l := range m.listeners {
 		if err := l.server.Shutdown(ctx); err != nil {
@@ -0,0 +1,101 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// freeAddr returns a loopback address nothing is listening on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+// get fetches url, retrying briefly while the listener starts.
+func get(t *testing.T, url string) string {
+	t.Helper()
+	var lastErr error
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get(url)
+		if err == nil {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return string(body)
+		}
+		lastErr = err
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("GET %s: %v", url, lastErr)
+	return ""
+}
+
+func stub(body string) []config.ProxyRouteConfig {
+	return []config.ProxyRouteConfig{{Mock: config.ProxyMockConfig{Responses: []config.ProxyMockResponse{{Body: body}}}}}
+}
+
+func TestManager_Update(t *testing.T) {
+	a, b, c := freeAddr(t), freeAddr(t), freeAddr(t)
+	m, err := NewManager([]config.ProxyListenerConfig{
+		{Listen: a, Routes: stub("a1")},
+		{Listen: b, Routes: stub("b1")},
+	})
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var logged []string
+	m.SetAccessLog(func(e AccessLogEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		logged = append(logged, e.Listener)
+	})
+	require.NoError(t, m.Start(context.Background()))
+	defer m.Shutdown(context.Background())
+
+	assert.Equal(t, "a1", get(t, "http://"+a+"/"))
+	assert.Equal(t, "b1", get(t, "http://"+b+"/"))
+	unchanged := m.listeners[0]
+
+	// A bad route leaves every listener as it was
+	err = m.Update(context.Background(), []config.ProxyListenerConfig{
+		{Listen: a, Routes: stub("a1")},
+		{Listen: b, Routes: []config.ProxyRouteConfig{{PathRegexp: "(", Upstream: "localhost:1"}}},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, "b1", get(t, "http://"+b+"/"))
+
+	require.NoError(t, m.Update(context.Background(), []config.ProxyListenerConfig{
+		{Listen: a, Routes: stub("a1")},
+		{Listen: b, Routes: stub("b2")},
+		{Listen: c, Routes: stub("c1")},
+	}))
+	assert.Same(t, unchanged, m.listeners[0])
+	assert.Equal(t, "a1", get(t, "http://"+a+"/"))
+	assert.Equal(t, "b2", get(t, "http://"+b+"/"))
+	assert.Equal(t, "c1", get(t, "http://"+c+"/"))
+	mu.Lock()
+	assert.Contains(t, logged, c, "added listeners keep the access log")
+	mu.Unlock()
+
+	require.NoError(t, m.Update(context.Background(), []config.ProxyListenerConfig{
+		{Listen: a, Routes: stub("a1")},
+	}))
+	assert.Len(t, m.Listeners(), 1)
+	_, err = http.Get("http://" + b + "/")
+	assert.Error(t, err, "removed listener still serving")
+}
@@ -0,0 +1,248 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// valueMatcher matches a named header or query parameter. A nil pattern
+// only requires the value to be present.
+type valueMatcher struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// routeMatchers are the conditions beyond path_regexp that select a route.
+type routeMatchers struct {
+	hosts   []*regexp.Regexp
+	methods []string
+	headers []valueMatcher
+	query   []valueMatcher
+}
+
+// routeActions change the request before it is proxied and the response
+// before it is returned.
+type routeActions struct {
+	stripPrefix    string
+	rewrite        *regexp.Regexp
+	rewriteTo      string
+	requestSet     map[string]string
+	requestRemove  []string
+	responseSet    map[string]string
+	responseRemove []string
+}
+
+// compileHostPattern compiles a host pattern into a case-insensitive regexp.
+// A leading or trailing "*" label matches one or more labels.
+func compileHostPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "*" {
+		return regexp.MustCompile(`.`), nil
+	}
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.ReplaceAll(expr, `\*`, `[^:]+`)
+	return regexp.Compile("^" + expr + "$")
+}
+
+// compileValueMatchers compiles a name -> regex map, sorted by name so
+// route descriptions are stable.
+func compileValueMatchers(field string, cfg map[string]string) ([]valueMatcher, error) {
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []valueMatcher
+	for _, name := range names {
+		m := valueMatcher{name: name}
+		if cfg[name] != "" {
+			re, err := regexp.Compile(cfg[name])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s regex for %q: %w", field, name, err)
+			}
+			m.pattern = re
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func newRouteMatchers(cfg config.ProxyRouteConfig) (routeMatchers, error) {
+	var m routeMatchers
+	for _, host := range cfg.Hosts {
+		re, err := compileHostPattern(host)
+		if err != nil {
+			return m, fmt.Errorf("invalid host %q: %w", host, err)
+		}
+		m.hosts = append(m.hosts, re)
+	}
+	for _, method := range cfg.Methods {
+		m.methods = append(m.methods, strings.ToUpper(method))
+	}
+	var err error
+	if m.headers, err = compileValueMatchers("header", cfg.Headers); err != nil {
+		return m, err
+	}
+	if m.query, err = compileValueMatchers("query", cfg.Query); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+func newRouteActions(cfg config.ProxyRouteConfig) (*routeActions, error) {
+	a := &routeActions{
+		stripPrefix:    strings.TrimSuffix(cfg.StripPrefix, "/"),
+		rewriteTo:      cfg.Rewrite.Replacement,
+		requestSet:     cfg.RequestHeaders.Set,
+		requestRemove:  cfg.RequestHeaders.Remove,
+		responseSet:    cfg.ResponseHeaders.Set,
+		responseRemove: cfg.ResponseHeaders.Remove,
+	}
+	if cfg.Rewrite.Pattern != "" {
+		re, err := regexp.Compile(cfg.Rewrite.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rewrite pattern %q: %w", cfg.Rewrite.Pattern, err)
+		}
+		a.rewrite = re
+	}
+	return a, nil
+}
+
+// requestHost returns the request's host name, lowercased and without a port.
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// match reports whether r satisfies every matcher.
+func (m *routeMatchers) match(r *http.Request) bool {
+	if len(m.hosts) > 0 {
+		host := requestHost(r)
+		matched := false
+		for _, re := range m.hosts {
+			if re.MatchString(host) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(m.methods) > 0 {
+		matched := false
+		for _, method := range m.methods {
+			if r.Method == method {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, h := range m.headers {
+		values, ok := r.Header[http.CanonicalHeaderKey(h.name)]
+		if !ok || !anyMatch(h.pattern, values) {
+			return false
+		}
+	}
+	if len(m.query) > 0 {
+		query := r.URL.Query()
+		for _, q := range m.query {
+			values, ok := query[q.name]
+			if !ok || !anyMatch(q.pattern, values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// anyMatch reports whether any value matches pattern; a nil pattern
+// matches anything.
+func anyMatch(pattern *regexp.Regexp, values []string) bool {
+	if pattern == nil {
+		return true
+	}
+	for _, v := range values {
+		if pattern.MatchString(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe summarizes the matchers, e.g. `hosts=api.* methods=GET,POST`.
+func (m *routeMatchers) describe(cfg config.ProxyRouteConfig) []string {
+	var parts []string
+	if len(cfg.Hosts) > 0 {
+		parts = append(parts, "hosts="+strings.Join(cfg.Hosts, ","))
+	}
+	if len(m.methods) > 0 {
+		parts = append(parts, "methods="+strings.Join(m.methods, ","))
+	}
+	for _, h := range m.headers {
+		parts = append(parts, "header:"+h.name+"="+cfg.Headers[h.name])
+	}
+	for _, q := range m.query {
+		parts = append(parts, "query:"+q.name+"="+cfg.Query[q.name])
+	}
+	return parts
+}
+
+// rewritePath applies strip_prefix and then the rewrite rule to req's path.
+func (a *routeActions) rewritePath(req *http.Request) {
+	if a.stripPrefix == "" && a.rewrite == nil {
+		return
+	}
+	path := req.URL.Path
+	if a.stripPrefix != "" && (path == a.stripPrefix || strings.HasPrefix(path, a.stripPrefix+"/")) {
+		path = strings.TrimPrefix(path, a.stripPrefix)
+	}
+	if a.rewrite != nil {
+		path = a.rewrite.ReplaceAllString(path, a.rewriteTo)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req.URL.Path = path
+	req.URL.RawPath = ""
+}
+
+// applyRequestHeaders removes and then sets the configured request headers.
+// Setting Host changes the Host sent upstream.
+func (a *routeActions) applyRequestHeaders(req *http.Request) {
+	for _, name := range a.requestRemove {
+		req.Header.Del(name)
+	}
+	for name, value := range a.requestSet {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+}
+
+// applyResponseHeaders removes and then sets the configured response headers.
+func (a *routeActions) applyResponseHeaders(h http.Header) {
+	for _, name := range a.responseRemove {
+		h.Del(name)
+	}
+	for name, value := range a.responseSet {
+		h.Set(name, value)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// namedUpstream answers every request with its name, the Host it received,
+// the request URI and the X-Env header.
+func namedUpstream(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "upstream")
+		w.Header().Set("X-Internal", "secret")
+		io.WriteString(w, name+" "+r.Host+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Env")+" "+r.Header.Get("Cookie"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRouteMatchers(t *testing.T) {
+	api := namedUpstream(t, "api")
+	admin := namedUpstream(t, "admin")
+	canary := namedUpstream(t, "canary")
+	web := namedUpstream(t, "web")
+
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen: ":0",
+		Routes: []config.ProxyRouteConfig{
+			{Hosts: []string{"api.*"}, Headers: map[string]string{"X-Canary": "^(1|true)$"}, Upstream: canary.URL},
+			{Hosts: []string{"api.*"}, Upstream: api.URL},
+			{Hosts: []string{"*.admin.test"}, Methods: []string{"get"}, Upstream: admin.URL},
+			{PathRegexp: "^/debug", Query: map[string]string{"canary": ""}, Upstream: canary.URL},
+			{Upstream: web.URL},
+		},
+	}})
+	require.NoError(t, err)
+	l := m.listeners[0]
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		headers map[string]string
+		want    string
+		route   string
+	}{
+		{"host wildcard", "GET", "http://api.example.com:8443/v1/users", nil, "api", "hosts=api.*"},
+		{"host is case-insensitive", "GET", "http://API.Example.COM/v1", nil, "api", "hosts=api.*"},
+		{"header matcher", "GET", "http://api.example.com/v1", map[string]string{"X-Canary": "true"}, "canary", "hosts=api.* header:X-Canary=^(1|true)$"},
+		{"header value mismatch", "GET", "http://api.example.com/v1", map[string]string{"X-Canary": "no"}, "api", "hosts=api.*"},
+		{"leading wildcard", "GET", "http://eu.admin.test/", nil, "admin", "hosts=*.admin.test methods=GET"},
+		{"method mismatch", "POST", "http://eu.admin.test/", nil, "web", "*"},
+		{"bare domain misses leading wildcard", "GET", "http://admin.test/", nil, "web", "*"},
+		{"query presence", "GET", "http://example.com/debug?canary", nil, "canary", "query:canary= path=^/debug"},
+		{"query missing", "GET", "http://example.com/debug", nil, "web", "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			route := l.match(req)
+			require.NotNil(t, route)
+			assert.Equal(t, tt.route, route.name())
+
+			rec := httptest.NewRecorder()
+			l.serveHTTP(rec, req)
+			assert.Regexp(t, "^"+tt.want+" ", rec.Body.String())
+		})
+	}
+}
+
+func TestRouteActions(t *testing.T) {
+	upstream := namedUpstream(t, "app")
+
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen: ":0",
+		Routes: []config.ProxyRouteConfig{
+			{
+				PathRegexp:  "^/app(/|$)",
+				StripPrefix: "/app/",
+				Rewrite:     config.ProxyRewriteConfig{Pattern: "^/v1/(.*)$", Replacement: "/api/$1"},
+				RequestHeaders: config.ProxyHeaderActions{
+					Set:    map[string]string{"X-Env": "dev", "Host": "internal.test"},
+					Remove: []string{"Cookie"},
+				},
+				ResponseHeaders: config.ProxyHeaderActions{
+					Set:    map[string]string{"Server": "trellis"},
+					Remove: []string{"X-Internal"},
+				},
+				Upstream: upstream.URL,
+			},
+			{Upstream: upstream.URL},
+		},
+	}})
+	require.NoError(t, err)
+	l := m.listeners[0]
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"http://example.com/app/v1/users?page=2", "app internal.test /api/users?page=2 dev "},
+		{"http://example.com/app/static/x.css", "app internal.test /static/x.css dev "},
+		{"http://example.com/app", "app internal.test / dev "},
+		{"http://example.com/application", "app example.com /application  session=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			req.Header.Set("Cookie", "session=1")
+			rec := httptest.NewRecorder()
+			l.serveHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "http://example.com/app/v1/x", nil))
+	assert.Equal(t, "trellis", rec.Header().Get("Server"))
+	assert.Empty(t, rec.Header().Get("X-Internal"))
+
+	rec = httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "http://example.com/other", nil))
+	assert.Equal(t, "upstream", rec.Header().Get("Server"))
+	assert.Equal(t, "secret", rec.Header().Get("X-Internal"))
+}
+
+func TestNewRoute_InvalidMatchers(t *testing.T) {
+	_, err := newRoute(config.ProxyRouteConfig{Upstream: "localhost:1", Headers: map[string]string{"X-A": "("}}, false)
+	assert.ErrorContains(t, err, "invalid header regex")
+
+	_, err = newRoute(config.ProxyRouteConfig{Upstream: "localhost:1", Query: map[string]string{"q": "["}}, false)
+	assert.ErrorContains(t, err, "invalid query regex")
+
+	_, err = newRoute(config.ProxyRouteConfig{Upstream: "localhost:1", Rewrite: config.ProxyRewriteConfig{Pattern: "("}}, false)
+	assert.ErrorContains(t, err, "invalid rewrite pattern")
+}
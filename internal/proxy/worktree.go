@@ -0,0 +1,217 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+const (
+	defaultWorktreeHeader = "X-Trellis-Worktree"
+	defaultWorktreeCookie = "trellis_worktree"
+
+	// worktreeSelectPath sets or clears the worktree cookie on listeners
+	// that route by worktree: /.trellis/worktree?name=feature-x&redirect=/app
+	worktreeSelectPath = "/.trellis/worktree"
+)
+
+// WorktreeSource provides the worktrees a listener that routes by worktree
+// can select between.
+type WorktreeSource interface {
+	// Worktrees returns template data for every worktree.
+	Worktrees() []config.WorktreeTemplateData
+	// Active returns the active worktree, used when a request selects none.
+	Active() (config.WorktreeTemplateData, bool)
+}
+
+// SetWorktreeSource sets where listeners that route by worktree look up
+// worktrees. It must be called before Start.
+func (m *Manager) SetWorktreeSource(source WorktreeSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.worktreeSource = source
+	for _, l := range m.listeners {
+		if l.worktrees != nil {
+			l.worktrees.source = source
+		}
+	}
+}
+
+// worktreeRouting selects the worktree each request is routed to.
+type worktreeRouting struct {
+	header    string
+	cookie    string
+	subdomain bool
+	source    WorktreeSource
+}
+
+func newWorktreeRouting(cfg config.ProxyWorktreesConfig) *worktreeRouting {
+	wr := &worktreeRouting{
+		header:    cfg.Header,
+		cookie:    cfg.Cookie,
+		subdomain: cfg.Subdomain,
+	}
+	if wr.header == "" {
+		wr.header = defaultWorktreeHeader
+	}
+	if wr.cookie == "" {
+		wr.cookie = defaultWorktreeCookie
+	}
+	return wr
+}
+
+// selectWorktree returns the worktree named by the request's header, then
+// its cookie, then its subdomain, falling back to the active worktree. A
+// header or cookie naming an unknown worktree is an error; a subdomain that
+// names none is ignored.
+func (wr *worktreeRouting) selectWorktree(r *http.Request) (config.WorktreeTemplateData, error) {
+	if wr.source == nil {
+		return config.WorktreeTemplateData{}, errors.New("worktree routing is not available")
+	}
+	if name := r.Header.Get(wr.header); name != "" {
+		return wr.lookup(name)
+	}
+	if c, err := r.Cookie(wr.cookie); err == nil && c.Value != "" {
+		wt, err := wr.lookup(c.Value)
+		if err != nil {
+			return wt, fmt.Errorf("%w; clear the selection at %s", err, worktreeSelectPath)
+		}
+		return wt, nil
+	}
+	if wr.subdomain {
+		host := requestHost(r)
+		if i := strings.IndexByte(host, '.'); i > 0 {
+			if wt, ok := findWorktree(wr.source.Worktrees(), host[:i]); ok {
+				return wt, nil
+			}
+		}
+	}
+	if wt, ok := wr.source.Active(); ok {
+		return wt, nil
+	}
+	return config.WorktreeTemplateData{}, errors.New("no worktree selected and none is active")
+}
+
+func (wr *worktreeRouting) lookup(name string) (config.WorktreeTemplateData, error) {
+	if wt, ok := findWorktree(wr.source.Worktrees(), name); ok {
+		return wt, nil
+	}
+	return config.WorktreeTemplateData{}, fmt.Errorf("unknown worktree %q", name)
+}
+
+// findWorktree finds the worktree called name. Host labels are lowercase,
+// so the name also matches case-insensitively and as a slug.
+func findWorktree(worktrees []config.WorktreeTemplateData, name string) (config.WorktreeTemplateData, bool) {
+	for _, wt := range worktrees {
+		if wt.Name == name {
+			return wt, true
+		}
+	}
+	for _, wt := range worktrees {
+		if strings.EqualFold(wt.Name, name) || config.Slugify(wt.Name) == strings.ToLower(name) {
+			return wt, true
+		}
+	}
+	return config.WorktreeTemplateData{}, false
+}
+
+// serveSelect sets the worktree cookie from the name parameter, or clears it
+// when name is empty, and redirects to the redirect parameter or "/".
+func (wr *worktreeRouting) serveSelect(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	cookie := &http.Cookie{
+		Name:     wr.cookie,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if name := q.Get("name"); name != "" {
+		if wr.source == nil {
+			http.Error(w, "worktree routing is not available", http.StatusServiceUnavailable)
+			return
+		}
+		wt, ok := findWorktree(wr.source.Worktrees(), name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown worktree %q", name), http.StatusNotFound)
+			return
+		}
+		cookie.Value = wt.Name
+	} else {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+
+	// Only redirect within this listener
+	redirect := q.Get("redirect")
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		redirect = "/"
+	}
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+// worktreeTargets expands a route's upstream and header templates for each
+// worktree requests select, caching the resulting targets.
+type worktreeTargets struct {
+	cfg      config.ProxyRouteConfig // Unexpanded
+	expander *config.TemplateExpander
+
+	mu    sync.Mutex
+	cache map[string]worktreeTarget // By worktree name
+}
+
+type worktreeTarget struct {
+	data   config.WorktreeTemplateData // Data the target was expanded with
+	target *target
+}
+
+func newWorktreeTargets(cfg config.ProxyRouteConfig) (*worktreeTargets, error) {
+	wt := &worktreeTargets{
+		cfg:      cfg,
+		expander: config.NewTemplateExpander(),
+		cache:    make(map[string]worktreeTarget),
+	}
+	// Fail fast on templates or actions that can never work
+	expanded, err := wt.expander.ExpandProxyRoute(cfg, &config.TemplateContext{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	if _, err := newRouteActions(expanded); err != nil {
+		return nil, err
+	}
+	return wt, nil
+}
+
+// forWorktree returns the target for a worktree, expanding the route for it
+// on first use and again whenever the worktree's data changes.
+func (wt *worktreeTargets) forWorktree(data config.WorktreeTemplateData) (*target, error) {
+	wt.mu.Lock()
+	defer wt.mu.Unlock()
+
+	old, ok := wt.cache[data.Name]
+	if ok && old.data == data {
+		return old.target, nil
+	}
+
+	expanded, err := wt.expander.ExpandProxyRoute(wt.cfg, &config.TemplateContext{Worktree: data})
+	if err != nil {
+		return nil, fmt.Errorf("expand route: %w", err)
+	}
+	t, err := newTarget(expanded, data.Name)
+	if err != nil {
+		return nil, err
+	}
+	if ok && old.target.proxy != nil {
+		if tr, isTransport := old.target.proxy.Transport.(*http.Transport); isTransport {
+			tr.CloseIdleConnections()
+		}
+	}
+	wt.cache[data.Name] = worktreeTarget{data: data, target: t}
+	return t, nil
+}
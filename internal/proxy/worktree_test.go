@@ -0,0 +1,219 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+// fakeWorktrees is a WorktreeSource whose worktrees keep the address of
+// their upstream in Root.
+type fakeWorktrees struct {
+	mu     sync.Mutex
+	list   []config.WorktreeTemplateData
+	active string
+}
+
+func (f *fakeWorktrees) Worktrees() []config.WorktreeTemplateData {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]config.WorktreeTemplateData(nil), f.list...)
+}
+
+func (f *fakeWorktrees) Active() (config.WorktreeTemplateData, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, wt := range f.list {
+		if wt.Name == f.active {
+			return wt, true
+		}
+	}
+	return config.WorktreeTemplateData{}, false
+}
+
+func worktreeManager(t *testing.T, source *fakeWorktrees) *Manager {
+	t.Helper()
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen: ":0",
+		Routes: []config.ProxyRouteConfig{{
+			Upstream:       "{{.Worktree.Root}}",
+			RequestHeaders: config.ProxyHeaderActions{Set: map[string]string{"X-Env": "{{.Worktree.Name}}"}},
+		}},
+		Capture:   config.ProxyCaptureConfig{Enabled: true},
+		Worktrees: config.ProxyWorktreesConfig{Enabled: true, Subdomain: true},
+	}})
+	require.NoError(t, err)
+	m.SetWorktreeSource(source)
+	return m
+}
+
+func TestWorktreeRouting_SelectsUpstream(t *testing.T) {
+	mainUp := namedUpstream(t, "main")
+	featureUp := namedUpstream(t, "feature")
+	source := &fakeWorktrees{
+		list: []config.WorktreeTemplateData{
+			{Name: "main", Root: mainUp.URL},
+			{Name: "Feature_X", Root: featureUp.URL},
+		},
+		active: "main",
+	}
+	m := worktreeManager(t, source)
+	l := m.listeners[0]
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		cookie string
+		status int
+		want   string
+	}{
+		{"active by default", "http://localhost/", "", "", http.StatusOK, "main"},
+		{"header", "http://localhost/", "Feature_X", "", http.StatusOK, "feature"},
+		{"header over cookie", "http://localhost/", "main", "Feature_X", http.StatusOK, "main"},
+		{"cookie", "http://localhost/", "", "Feature_X", http.StatusOK, "feature"},
+		{"subdomain slug", "http://feature-x.localhost:8443/", "", "", http.StatusOK, "feature"},
+		{"unknown subdomain falls back", "http://api.localhost/", "", "", http.StatusOK, "main"},
+		{"unknown header", "http://localhost/", "gone", "", http.StatusBadGateway, "unknown worktree"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Trellis-Worktree", tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "trellis_worktree", Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			l.serveHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.want)
+		})
+	}
+
+	// Header values are expanded for the selected worktree, and the
+	// capture records which worktree served the request
+	req := httptest.NewRequest("GET", "http://localhost/x", nil)
+	req.Header.Set("X-Trellis-Worktree", "feature_x")
+	rec := httptest.NewRecorder()
+	l.serveHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), " /x Feature_X ")
+	summaries, err := m.Requests(CaptureFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "Feature_X", summaries[0].Worktree)
+	assert.Equal(t, strings.TrimPrefix(featureUp.URL, "http://"), summaries[0].Upstream)
+
+	// Switching the active worktree and changing a worktree's data take
+	// effect on the next request
+	source.mu.Lock()
+	source.active = "Feature_X"
+	source.list[0].Root = featureUp.URL
+	source.mu.Unlock()
+	rec = httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "http://localhost/", nil))
+	assert.Contains(t, rec.Body.String(), "feature localhost / Feature_X")
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "http://localhost/", nil)
+	req.Header.Set("X-Trellis-Worktree", "main")
+	l.serveHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), "feature localhost / main")
+}
+
+func TestWorktreeRouting_NoSource(t *testing.T) {
+	m, err := NewManager([]config.ProxyListenerConfig{{
+		Listen:    ":0",
+		Routes:    []config.ProxyRouteConfig{{Upstream: "localhost:{{.Worktree.Name}}"}},
+		Worktrees: config.ProxyWorktreesConfig{Enabled: true},
+	}})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	m.listeners[0].serveHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not available")
+
+	_, err = NewManager([]config.ProxyListenerConfig{{
+		Listen:    ":0",
+		Routes:    []config.ProxyRouteConfig{{Upstream: "localhost:{{.Worktree.Port}}"}},
+		Worktrees: config.ProxyWorktreesConfig{Enabled: true},
+	}})
+	assert.ErrorContains(t, err, "invalid template")
+}
+
+func TestWorktreeRouting_SelectEndpoint(t *testing.T) {
+	source := &fakeWorktrees{
+		list:   []config.WorktreeTemplateData{{Name: "main"}, {Name: "feature-x"}},
+		active: "main",
+	}
+	l := worktreeManager(t, source).listeners[0]
+
+	rec := httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "/.trellis/worktree?name=Feature-X&redirect=/app?a=1", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/app?a=1", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "trellis_worktree", cookies[0].Name)
+	assert.Equal(t, "feature-x", cookies[0].Value)
+
+	rec = httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "/.trellis/worktree?redirect=//evil.example", nil))
+	assert.Equal(t, "/", rec.Header().Get("Location"))
+	cookies = rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+
+	rec = httptest.NewRecorder()
+	l.serveHTTP(rec, httptest.NewRequest("GET", "/.trellis/worktree?name=gone", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWorktreeRouting_Replay(t *testing.T) {
+	mainUp := namedUpstream(t, "main")
+	featureUp := namedUpstream(t, "feature")
+	source := &fakeWorktrees{
+		list: []config.WorktreeTemplateData{
+			{Name: "main", Root: mainUp.URL},
+			{Name: "feature-x", Root: featureUp.URL},
+		},
+		active: "main",
+	}
+	m := worktreeManager(t, source)
+
+	rec := httptest.NewRecorder()
+	m.listeners[0].serveHTTP(rec, httptest.NewRequest("GET", "http://localhost/compare", nil))
+	summaries, err := m.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "main", summaries[0].Worktree)
+
+	replayed, err := m.Replay(context.Background(), summaries[0].ID, ReplayOptions{Worktree: "feature-x"})
+	require.NoError(t, err)
+	assert.Equal(t, "feature-x", replayed.Worktree)
+	assert.Equal(t, "feature-x", replayed.RequestHeaders.Get("X-Trellis-Worktree"))
+	assert.Equal(t, "feature localhost /compare feature-x ", replayed.ResponseBody.Data)
+
+	_, err = m.Replay(context.Background(), summaries[0].ID, ReplayOptions{Worktree: "gone"})
+	assert.ErrorContains(t, err, `unknown worktree "gone"`)
+
+	// Listeners that don't route by worktree reject the option
+	plain := captureManager(t, mainUp.URL, config.ProxyCaptureConfig{Enabled: true})
+	send(plain, "GET", "/x", "")
+	summaries, err = plain.Requests(CaptureFilter{})
+	require.NoError(t, err)
+	_, err = plain.Replay(context.Background(), summaries[0].ID, ReplayOptions{Worktree: "main"})
+	assert.ErrorContains(t, err, "does not route by worktree")
+}
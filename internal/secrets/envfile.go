@@ -0,0 +1,136 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// EnvVar is a variable read from an env file.
+type EnvVar struct {
+	Key   string
+	Value string
+}
+
+// envKeyPattern is what an env file variable name may contain.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ReadEnvFile reads the variables of the dotenv file at path.
+func ReadEnvFile(path string) ([]EnvVar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("env file: %w", err)
+	}
+	defer f.Close()
+
+	vars, err := ParseEnvFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("env file %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// ParseEnvFile parses dotenv syntax: KEY=value lines, optionally prefixed
+// with "export", with # comments. Values may be single quoted (literal),
+// double quoted (with \n, \t, \" and \\ escapes, spanning lines) or bare,
+// where a " #" starts a comment. Variables are not interpolated. The
+// variables are returned in file order.
+func ParseEnvFile(r io.Reader) ([]EnvVar, error) {
+	var vars []EnvVar
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNum)
+		}
+		value = strings.TrimLeft(value, " \t")
+		start := lineNum
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			// Double quoted values continue until the closing quote
+			raw := value[1:]
+			for {
+				end := closingQuote(raw)
+				if end >= 0 {
+					value = unescape(raw[:end])
+					break
+				}
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+				}
+				lineNum++
+				raw += "\n" + scanner.Text()
+			}
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated quoted value", start)
+			}
+			value = value[1 : end+1]
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			value = strings.TrimSpace(value)
+		}
+		vars = append(vars, EnvVar{Key: key, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote in s,
+// or -1 if there isn't one.
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape expands the escapes of a double quoted value.
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	vars, err := ParseEnvFile(strings.NewReader(`
+# comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = trimmed  # trailing comment
+HASH=a#b
+SINGLE='literal $HOME \n # kept'
+DOUBLE="line1\nline2 \"quoted\""
+MULTI="first
+second"
+EMPTY=
+REF={{ secret "stripe_key" }}
+`))
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	var keys []string
+	for _, v := range vars {
+		got[v.Key] = v.Value
+		keys = append(keys, v.Key)
+	}
+	assert.Equal(t, []string{"PLAIN", "EXPORTED", "SPACED", "HASH", "SINGLE", "DOUBLE", "MULTI", "EMPTY", "REF"}, keys)
+	assert.Equal(t, "value", got["PLAIN"])
+	assert.Equal(t, "yes", got["EXPORTED"])
+	assert.Equal(t, "trimmed", got["SPACED"])
+	assert.Equal(t, "a#b", got["HASH"])
+	assert.Equal(t, `literal $HOME \n # kept`, got["SINGLE"])
+	assert.Equal(t, "line1\nline2 \"quoted\"", got["DOUBLE"])
+	assert.Equal(t, "first\nsecond", got["MULTI"])
+	assert.Equal(t, "", got["EMPTY"])
+	assert.Equal(t, `{{ secret "stripe_key" }}`, got["REF"])
+}
+
+func TestParseEnvFile_Errors(t *testing.T) {
+	for _, input := range []string{
+		"NOEQUALS",
+		"1BAD=x",
+		`OPEN="never closed`,
+		"OPEN='never closed",
+	} {
+		_, err := ParseEnvFile(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
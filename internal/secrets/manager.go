@@ -0,0 +1,308 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// commandCacheTTL is how long a value read from the secrets command is
+	// reused, so starting a group of services runs it once per secret.
+	commandCacheTTL = 5 * time.Minute
+
+	// commandTimeout bounds a run of the secrets command.
+	commandTimeout = 30 * time.Second
+
+	// minRedactLength is the shortest value redacted from output; shorter
+	// values would mangle unrelated text.
+	minRedactLength = 4
+)
+
+// Secret sources, as reported by List.
+const (
+	SourceStore   = "store"
+	SourceCommand = "command"
+)
+
+// Info describes a secret without its value.
+type Info struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// Manager resolves secrets, first from the project's store and then from
+// the secrets command, and remembers the values it has seen so they can be
+// redacted. A nil Manager redacts nothing and fails to resolve any
+// reference, which leaves environments without secrets working.
+type Manager struct {
+	store *Store
+
+	mu       sync.RWMutex
+	command  []string               // {name} in an argument is replaced with the secret name
+	cache    map[string]cachedValue // values read from the command
+	known    map[string]string      // secret value -> name, for redaction
+	replacer *strings.Replacer
+}
+
+type cachedValue struct {
+	value   string
+	expires time.Time
+}
+
+// NewManager creates a manager for store and the secrets command. The
+// values already in the store are redacted from the start.
+func NewManager(store *Store, command []string) *Manager {
+	m := &Manager{
+		store:   store,
+		command: command,
+		cache:   make(map[string]cachedValue),
+		known:   make(map[string]string),
+	}
+	if all, err := store.All(); err == nil {
+		for name, value := range all {
+			m.remember(name, value)
+		}
+	}
+	return m
+}
+
+// SetCommand replaces the secrets command and drops the values read with
+// the old one.
+func (m *Manager) SetCommand(command []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.command = command
+	m.cache = make(map[string]cachedValue)
+}
+
+// Get returns the value of the named secret.
+func (m *Manager) Get(ctx context.Context, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if m == nil {
+		return "", fmt.Errorf("secret %q: secrets are not available", name)
+	}
+
+	value, ok, err := m.store.Get(name)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		m.remember(name, value)
+		return value, nil
+	}
+
+	m.mu.RLock()
+	command := m.command
+	cached, cachedOK := m.cache[name]
+	m.mu.RUnlock()
+	if len(command) == 0 {
+		return "", fmt.Errorf("%w: %s (set it with trellis-ctl secret set %s)", ErrNotFound, name, name)
+	}
+	if cachedOK && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	value, err = runCommand(ctx, command, name)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	m.cache[name] = cachedValue{value: value, expires: time.Now().Add(commandCacheTTL)}
+	m.mu.Unlock()
+	m.remember(name, value)
+	return value, nil
+}
+
+// runCommand reads the named secret from the secrets command.
+func runCommand(ctx context.Context, command []string, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = strings.ReplaceAll(arg, "{name}", name)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret %q: %s: %v: %s", name, args[0], err, msg)
+		}
+		return "", fmt.Errorf("secret %q: %s: %v", name, args[0], err)
+	}
+	value := strings.TrimRight(string(out), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("secret %q: %s printed nothing", name, args[0])
+	}
+	return value, nil
+}
+
+// Resolve replaces the secret references in s with the secrets' values.
+func (m *Manager) Resolve(ctx context.Context, s string) (string, error) {
+	if !HasRef(s) {
+		return s, nil
+	}
+	var firstErr error
+	resolved := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if firstErr != nil {
+			return ref
+		}
+		value, err := m.Get(ctx, refPattern.FindStringSubmatch(ref)[1])
+		if err != nil {
+			firstErr = err
+			return ref
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	if HasRef(resolved) {
+		return "", fmt.Errorf("malformed secret reference in %q", s)
+	}
+	return resolved, nil
+}
+
+// Environ returns the environment for a process: trellis's own, then the
+// variables of envFile, then env, with secret references resolved. A
+// relative envFile is relative to dir.
+func (m *Manager) Environ(ctx context.Context, envFile, dir string, env map[string]string) ([]string, error) {
+	out := os.Environ()
+
+	if envFile != "" {
+		path := envFile
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		vars, err := ReadEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vars {
+			value, err := m.Resolve(ctx, v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("env %s: %w", v.Key, err)
+			}
+			out = append(out, v.Key+"="+value)
+		}
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, err := m.Resolve(ctx, env[k])
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %w", k, err)
+		}
+		out = append(out, k+"="+value)
+	}
+	return out, nil
+}
+
+// Redact replaces the secret values in s with [secret:name].
+func (m *Manager) Redact(s string) string {
+	if m == nil {
+		return s
+	}
+	m.mu.RLock()
+	replacer := m.replacer
+	m.mu.RUnlock()
+	if replacer == nil {
+		return s
+	}
+	return replacer.Replace(s)
+}
+
+// remember adds value to the values redacted from output.
+func (m *Manager) remember(name, value string) {
+	if len(value) < minRedactLength {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.known[value] == name {
+		return
+	}
+	m.known[value] = name
+
+	// Longer values first, so a secret containing another is replaced whole
+	values := make([]string, 0, len(m.known))
+	for v := range m.known {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	pairs := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		pairs = append(pairs, v, "[secret:"+m.known[v]+"]")
+	}
+	m.replacer = strings.NewReplacer(pairs...)
+}
+
+// Set stores a secret in the project's store.
+func (m *Manager) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if value == "" {
+		return errors.New("secret value is empty")
+	}
+	if err := m.store.Set(name, value); err != nil {
+		return err
+	}
+	m.remember(name, value)
+	return nil
+}
+
+// Delete removes a secret from the project's store. Its value is still
+// redacted until trellis restarts.
+func (m *Manager) Delete(name string) error {
+	return m.store.Delete(name)
+}
+
+// List returns the secrets in the store and those read from the secrets
+// command so far, sorted by name.
+func (m *Manager) List() ([]Info, error) {
+	all, err := m.store.All()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(all))
+	for name := range all {
+		infos = append(infos, Info{Name: name, Source: SourceStore})
+	}
+	m.mu.RLock()
+	for name := range m.cache {
+		if _, ok := all[name]; !ok {
+			infos = append(infos, Info{Name: name, Source: SourceCommand})
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos, nil
+}
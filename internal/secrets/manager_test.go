@@ -0,0 +1,88 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRef(t *testing.T) {
+	ref := Ref("stripe_key")
+	assert.Equal(t, `{{ secret "stripe_key" }}`, ref)
+	assert.True(t, HasRef(ref))
+	assert.True(t, HasRef(`x {{secret "a"}} y`))
+	assert.False(t, HasRef("{{ .Worktree.Root }}"))
+
+	assert.NoError(t, ValidateName("aws/prod.key-1"))
+	assert.Error(t, ValidateName(""))
+	assert.Error(t, ValidateName("has space"))
+}
+
+func TestManager_ResolveAndRedact(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(t.TempDir(), "app")
+	require.NoError(t, store.Set("stored", "from-the-store"))
+
+	// The command prints the name it was asked for
+	m := NewManager(store, []string{"echo", "cmd-{name}"})
+	assert.Equal(t, "[secret:stored] ok", m.Redact("from-the-store ok"), "store values are redacted from the start")
+
+	got, err := m.Resolve(ctx, `a={{ secret "stored" }} b={{ secret "other" }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "a=from-the-store b=cmd-other", got)
+	assert.Equal(t, "token [secret:other]", m.Redact("token cmd-other"))
+
+	infos, err := m.List()
+	require.NoError(t, err)
+	assert.Equal(t, []Info{{Name: "other", Source: SourceCommand}, {Name: "stored", Source: SourceStore}}, infos)
+
+	// Without a command, unknown secrets are errors
+	m.SetCommand(nil)
+	_, err = m.Resolve(ctx, `{{ secret "other" }}`)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// A failing command reports its error output
+	m.SetCommand([]string{"sh", "-c", "echo no such item >&2; exit 1"})
+	_, err = m.Get(ctx, "other")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no such item")
+
+	// Short values aren't redacted
+	require.NoError(t, m.Set("pin", "123"))
+	assert.Equal(t, "123", m.Redact("123"))
+}
+
+func TestManager_Environ(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("FROM_FILE=1\nOVERRIDDEN=file\nKEY={{ secret \"api\" }}\n"), 0644))
+
+	m := NewManager(NewStore(t.TempDir(), "app"), nil)
+	require.NoError(t, m.Set("api", "secret-value"))
+
+	env, err := m.Environ(ctx, ".env", dir, map[string]string{"OVERRIDDEN": "env"})
+	require.NoError(t, err)
+	assert.Contains(t, env, "FROM_FILE=1")
+	assert.Contains(t, env, "KEY=secret-value")
+	// The env map comes after the file, so it wins
+	assert.Equal(t, "OVERRIDDEN=env", env[len(env)-1])
+
+	_, err = m.Environ(ctx, "missing.env", dir, nil)
+	assert.Error(t, err)
+
+	// Without a manager, literal environments still work
+	var none *Manager
+	env, err = none.Environ(ctx, "", "", map[string]string{"A": "b"})
+	require.NoError(t, err)
+	assert.Contains(t, env, "A=b")
+	_, err = none.Environ(ctx, "", "", map[string]string{"A": Ref("api")})
+	assert.Error(t, err)
+	assert.Equal(t, "secret-value", none.Redact("secret-value"))
+}
@@ -0,0 +1,47 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package secrets resolves the secrets referenced from service and workflow
+// environments, from a local encrypted store or an external command, and
+// redacts their values from output.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrNotFound is returned for secrets that aren't set.
+var ErrNotFound = errors.New("secret not found")
+
+// refPattern matches a secret reference as left in config values by the
+// "secret" template function.
+var refPattern = regexp.MustCompile(`\{\{-?\s*secret\s+"([^"]*)"\s*-?\}\}`)
+
+// anyRefPattern matches anything that looks like the start of a secret
+// reference, however it is quoted.
+var anyRefPattern = regexp.MustCompile(`\{\{-?\s*secret\s`)
+
+// namePattern is what a secret name may contain.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-/]+$`)
+
+// Ref returns the reference to the named secret. Config templates expand
+// {{ secret "name" }} to this, so the value is only looked up when a process
+// is started with it.
+func Ref(name string) string {
+	return fmt.Sprintf(`{{ secret %q }}`, name)
+}
+
+// HasRef reports whether s contains a secret reference.
+func HasRef(s string) bool {
+	return anyRefPattern.MatchString(s)
+}
+
+// ValidateName checks that name can be used as a secret name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.', '-' and '/'", name)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// keySize is the size of the store key: AES-256.
+const keySize = 32
+
+// unsafeFileChars matches characters not used in store file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DefaultDir returns the directory secret stores are kept in by default,
+// trellis/secrets under the user config directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trellis", "secrets"), nil
+}
+
+// Store is the local secret store of a project: a JSON object of secret
+// names to values, encrypted with AES-GCM. The key is kept in a separate
+// file, readable only by the user and shared by the projects in the
+// directory, so the store can't be read from a copy of the store alone.
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	keyPath string
+}
+
+// NewStore returns the store of project in dir. Nothing is created until a
+// secret is set.
+func NewStore(dir, project string) *Store {
+	name := unsafeFileChars.ReplaceAllString(project, "_")
+	if name == "" {
+		name = "default"
+	}
+	return &Store{
+		path:    filepath.Join(dir, name+".enc"),
+		keyPath: filepath.Join(dir, "key"),
+	}
+}
+
+// Path returns the path of the store file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Get returns the value of the named secret and whether it is set.
+func (s *Store) Get(name string) (string, bool, error) {
+	all, err := s.All()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := all[name]
+	return value, ok, nil
+}
+
+// All returns every secret in the store.
+func (s *Store) All() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Set sets the value of the named secret, creating the store and its key
+// if needed.
+func (s *Store) Set(name, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	all[name] = value
+	return s.write(all)
+}
+
+// Delete removes the named secret. It returns ErrNotFound if it isn't set.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(all, name)
+	return s.write(all)
+}
+
+// read decrypts the store. A store that doesn't exist yet is empty.
+// Caller must hold s.mu.
+func (s *Store) read() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read secret store: %w", err)
+	}
+
+	aead, err := s.cipher(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("secret store %s is corrupt", s.path)
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt secret store %s: %w", s.path, err)
+	}
+
+	all := make(map[string]string)
+	if err := json.Unmarshal(plain, &all); err != nil {
+		return nil, fmt.Errorf("secret store %s is corrupt: %w", s.path, err)
+	}
+	return all, nil
+}
+
+// write encrypts all to the store, replacing it atomically. Caller must
+// hold s.mu.
+func (s *Store) write(all map[string]string) error {
+	aead, err := s.cipher(true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := aead.Seal(nonce, nonce, plain, nil)
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write secret store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write secret store: %w", err)
+	}
+	return nil
+}
+
+// cipher returns the AEAD for the store key, generating the key if create
+// is set and there isn't one.
+func (s *Store) cipher(create bool) (cipher.AEAD, error) {
+	key, err := os.ReadFile(s.keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if !create {
+			return nil, fmt.Errorf("secret store %s exists but its key %s is missing", s.path, s.keyPath)
+		}
+		key, err = s.createKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read secret key: %w", err)
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("secret key %s is corrupt", s.keyPath)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// createKey generates the store key. The file is created exclusively, so
+// two processes creating it at once end up with the same key.
+func (s *Store) createKey() ([]byte, error) {
+	if err := os.MkdirAll(filepath.Dir(s.keyPath), 0700); err != nil {
+		return nil, err
+	}
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.keyPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return os.ReadFile(s.keyPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(key); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
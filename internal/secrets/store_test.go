@@ -0,0 +1,63 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_SetGetDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	store := NewStore(dir, "my app")
+	assert.Equal(t, filepath.Join(dir, "my_app.enc"), store.Path())
+
+	// A store that doesn't exist yet is empty
+	all, err := store.All()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	require.NoError(t, store.Set("stripe_key", "sk_test_123"))
+	value, ok, err := store.Get("stripe_key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "sk_test_123", value)
+
+	// The store is encrypted and the key private
+	data, err := os.ReadFile(store.Path())
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(data, []byte("sk_test_123")))
+	info, err := os.Stat(filepath.Join(dir, "key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Another store in the directory shares the key
+	other := NewStore(dir, "other")
+	require.NoError(t, other.Set("token", "abcd"))
+	value, _, err = store.Get("stripe_key")
+	require.NoError(t, err)
+	assert.Equal(t, "sk_test_123", value)
+
+	require.NoError(t, store.Delete("stripe_key"))
+	_, ok, err = store.Get("stripe_key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.ErrorIs(t, store.Delete("stripe_key"), ErrNotFound)
+}
+
+func TestStore_MissingKey(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, "app")
+	require.NoError(t, store.Set("a", "value"))
+	require.NoError(t, os.Remove(filepath.Join(dir, "key")))
+
+	_, err := store.All()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "key")
+}
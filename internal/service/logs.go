@@ -4,8 +4,10 @@
 package service
 
 import (
+	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/wingedpig/trellis/internal/config"
 	"github.com/wingedpig/trellis/internal/logs"
@@ -19,6 +21,7 @@ type LogBuffer struct {
 	mu          sync.RWMutex
 	lines       []string
 	entries     []*logs.LogEntry // Parsed entries (parallel to lines)
+	times       []time.Time      // When each line was written (parallel to lines)
 	capacity    int
 	size        int
 	head        int // next write position
@@ -29,13 +32,27 @@ type LogBuffer struct {
 	// Parser and deriver for structured logging
 	parser  logs.LogParser
 	deriver *logs.Deriver
+
+	// Multiline folding: continuation lines keep their own slot in lines
+	// but a nil entry, and the entry at foldIdx is reparsed from fold.
+	multiline *logs.Multiline
+	fold      []string
+	foldIdx   int
+	lastWrite time.Time
+
+	store *logs.EntryStore // On-disk copy of written lines (nil unless logging.persist is set)
 }
 
+// persistMultiplier is how many buffers' worth of lines a persisted service
+// log keeps on disk.
+const persistMultiplier = 10
+
 // LogLine represents a single log line with sequence number.
 type LogLine struct {
-	Line     string
-	Sequence int64
-	Entry    *logs.LogEntry `json:"entry,omitempty"` // Parsed entry (if parser configured)
+	Line         string
+	Sequence     int64
+	Entry        *logs.LogEntry `json:"entry,omitempty"`        // Parsed entry (if parser configured)
+	Continuation bool           `json:"continuation,omitempty"` // Line was folded into the previous entry; Entry is that entry, updated
 }
 
 // SetParser configures the parser and deriver for this buffer.
@@ -47,6 +64,7 @@ func (b *LogBuffer) SetParser(parserCfg config.LogParserConfig, deriveCfg map[st
 		parser, err := logs.NewParser(parserCfg)
 		if err == nil {
 			b.parser = parser
+			b.multiline, _ = logs.NewMultiline(parserCfg.Multiline)
 		}
 	}
 
@@ -63,41 +81,120 @@ func NewLogBuffer(capacity int) *LogBuffer {
 	return &LogBuffer{
 		lines:       make([]string, capacity),
 		entries:     make([]*logs.LogEntry, capacity),
+		times:       make([]time.Time, capacity),
 		capacity:    capacity,
 		subscribers: make(map[chan LogLine]struct{}),
 	}
 }
 
-// Write adds a single line to the buffer and notifies subscribers.
-func (b *LogBuffer) Write(line string) {
+// EnablePersistence keeps the buffer's lines in an on-disk store in dir.
+// Lines stored by a previous run are reloaded (and reparsed if a parser is
+// configured), and sequence numbers continue from the last stored line.
+// Call after SetParser.
+func (b *LogBuffer) EnablePersistence(dir string) error {
+	store, err := logs.OpenEntryStore(dir, b.capacity*persistMultiplier)
+	if err != nil {
+		return err
+	}
+	recent, err := store.Recent(b.capacity)
+	if err != nil {
+		store.Close()
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, stored := range recent {
+		b.placeLocked(stored.Raw, stored.Timestamp)
+	}
+	if last := int64(store.LastSequence()); last > b.sequence {
+		b.sequence = last
+	}
+	b.store = store
+	return nil
+}
+
+// ClosePersistence releases the on-disk store, if any.
+func (b *LogBuffer) ClosePersistence() {
 	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.store != nil {
+		b.store.Close()
+		b.store = nil
+	}
+}
 
-	// Parse the line if parser is configured
-	var entry *logs.LogEntry
-	if b.parser != nil {
-		parsed := b.parser.Parse(line)
-		entry = &parsed
-		// Apply derive operations
-		if b.deriver != nil {
-			b.deriver.Apply(entry)
+// parseLocked parses a line with the configured parser and deriver, returning
+// nil if no parser is configured. Caller must hold b.mu.
+func (b *LogBuffer) parseLocked(line string) *logs.LogEntry {
+	if b.parser == nil {
+		return nil
+	}
+	parsed := b.parser.Parse(line)
+	if b.deriver != nil {
+		b.deriver.Apply(&parsed)
+	}
+	return &parsed
+}
+
+// placeLocked stores a line at the head of the ring and returns its parsed
+// entry. A line that continues the previous entry (per the parser's
+// multiline config) is folded into it instead: the returned entry is the
+// reparsed previous entry and continued is true. Caller must hold b.mu.
+func (b *LogBuffer) placeLocked(line string, now time.Time) (entry *logs.LogEntry, continued bool) {
+	if b.multiline != nil {
+		// A fold never outlives the ring, so foldIdx still holds its head line
+		if len(b.fold) > 0 && len(b.fold) < b.multiline.MaxLines() && len(b.fold) < b.capacity &&
+			now.Sub(b.lastWrite) < b.multiline.Timeout() && b.multiline.Continues(line) {
+			b.fold = append(b.fold, line)
+			entry = b.parseLocked(strings.Join(b.fold, "\n"))
+			b.entries[b.foldIdx] = entry
+			continued = true
+		} else {
+			b.fold = append(b.fold[:0], line)
+			b.foldIdx = b.head
 		}
+		b.lastWrite = now
 	}
 
 	b.lines[b.head] = line
-	b.entries[b.head] = entry
+	b.times[b.head] = now
+	if continued {
+		b.entries[b.head] = nil
+	} else {
+		entry = b.parseLocked(line)
+		b.entries[b.head] = entry
+	}
 	b.head = (b.head + 1) % b.capacity
 	if b.size < b.capacity {
 		b.size++
 	}
+	return entry, continued
+}
+
+// Write adds a single line to the buffer and notifies subscribers.
+func (b *LogBuffer) Write(line string) {
+	b.mu.Lock()
+
+	now := time.Now()
+	entry, continued := b.placeLocked(line, now)
 	b.sequence++
 	seq := b.sequence
+
+	if b.store != nil {
+		stored := logs.LogEntry{Raw: line, Sequence: uint64(seq), Timestamp: now}
+		if err := b.store.Append(stored); err != nil {
+			log.Printf("service logs: failed to persist line: %v", err)
+		}
+	}
 	b.mu.Unlock()
 
 	// Notify subscribers (non-blocking)
 	b.subMu.RLock()
 	for ch := range b.subscribers {
 		select {
-		case ch <- LogLine{Line: line, Sequence: seq, Entry: entry}:
+		case ch <- LogLine{Line: line, Sequence: seq, Entry: entry, Continuation: continued}:
 		default:
 			// Channel full, skip (subscriber too slow)
 		}
@@ -204,8 +301,9 @@ func (b *LogBuffer) All() []string {
 	return b.Lines(b.size)
 }
 
-// Entries returns the last n parsed entries from the buffer.
+// Entries returns the parsed entries for the last n lines in the buffer.
 // Returns nil entries for lines that weren't parsed (no parser configured).
+// Continuation lines folded into an earlier entry are omitted.
 func (b *LogBuffer) Entries(n int) []*logs.LogEntry {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -218,14 +316,123 @@ func (b *LogBuffer) Entries(n int) []*logs.LogEntry {
 		n = b.size
 	}
 
-	result := make([]*logs.LogEntry, n)
+	result := make([]*logs.LogEntry, 0, n)
 	start := (b.head - n + b.capacity) % b.capacity
 
 	for i := 0; i < n; i++ {
 		idx := (start + i) % b.capacity
-		result[i] = b.entries[idx]
+		if b.entries[idx] == nil && b.multiline != nil {
+			continue
+		}
+		result = append(result, b.entries[idx])
+	}
+
+	return result
+}
+
+// Before returns up to n lines (all if n <= 0) written before sequence
+// number seq, oldest first. Once the in-memory ring runs out, older lines
+// are read from the on-disk store, if persistence is enabled. Entries of
+// continuation lines folded into an earlier entry are nil.
+func (b *LogBuffer) Before(seq int64, n int) []LogLine {
+	b.mu.RLock()
+	var reversed []LogLine
+	oldest := b.sequence - int64(b.size) + 1 // Sequence of the oldest line in the ring
+	for i := b.size - 1; i >= 0; i-- {
+		lineSeq := oldest + int64(i)
+		if lineSeq >= seq {
+			continue
+		}
+		if n > 0 && len(reversed) >= n {
+			break
+		}
+		reversed = append(reversed, b.lineLocked(i, lineSeq))
+	}
+	store, size := b.store, b.size
+	b.mu.RUnlock()
+
+	result := make([]LogLine, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		result = append(result, reversed[i])
+	}
+	if store == nil || (n > 0 && len(result) >= n) {
+		return result
 	}
 
+	// Continue below whichever is lower: the cursor or the oldest line in memory
+	diskBefore := seq
+	if size > 0 && oldest < diskBefore {
+		diskBefore = oldest
+	}
+	if diskBefore <= 1 {
+		return result
+	}
+	remaining := 0
+	if n > 0 {
+		remaining = n - len(result)
+	}
+	older, err := store.Before(uint64(diskBefore), remaining)
+	if err != nil {
+		log.Printf("service logs: failed to read persisted lines: %v", err)
+		return result
+	}
+	return append(b.storedLines(older), result...)
+}
+
+// Range returns up to n lines (all if n <= 0) written between start and
+// end inclusive, oldest first. When persistence is enabled and the range
+// starts before the in-memory window, the whole range is read from disk,
+// which holds everything the ring does.
+func (b *LogBuffer) Range(start, end time.Time, n int) []LogLine {
+	b.mu.RLock()
+	oldestIdx := (b.head - b.size + b.capacity) % b.capacity
+	if store := b.store; store != nil && (b.size == 0 || start.Before(b.times[oldestIdx])) {
+		b.mu.RUnlock()
+		stored, err := store.Range(start, end, n)
+		if err != nil {
+			log.Printf("service logs: failed to read persisted lines: %v", err)
+		}
+		return b.storedLines(stored)
+	}
+	defer b.mu.RUnlock()
+
+	var result []LogLine
+	oldest := b.sequence - int64(b.size) + 1
+	for i := 0; i < b.size; i++ {
+		idx := (oldestIdx + i) % b.capacity
+		if b.times[idx].Before(start) || b.times[idx].After(end) {
+			continue
+		}
+		result = append(result, b.lineLocked(i, oldest+int64(i)))
+		if n > 0 && len(result) >= n {
+			break
+		}
+	}
+	return result
+}
+
+// lineLocked returns the i-th oldest line in the ring, whose sequence number
+// is seq. Caller must hold b.mu.
+func (b *LogBuffer) lineLocked(i int, seq int64) LogLine {
+	idx := (b.head - b.size + i + b.capacity) % b.capacity
+	entry := b.entries[idx]
+	return LogLine{
+		Line:         b.lines[idx],
+		Sequence:     seq,
+		Entry:        entry,
+		Continuation: entry == nil && b.multiline != nil,
+	}
+}
+
+// storedLines converts lines read from the on-disk store, parsing each with
+// the configured parser. Multiline entries aren't refolded.
+func (b *LogBuffer) storedLines(stored []logs.LogEntry) []LogLine {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	result := make([]LogLine, len(stored))
+	for i, e := range stored {
+		result[i] = LogLine{Line: e.Raw, Sequence: int64(e.Sequence), Entry: b.parseLocked(e.Raw)}
+	}
 	return result
 }
 
@@ -243,10 +450,18 @@ func (b *LogBuffer) Clear() {
 
 	b.size = 0
 	b.head = 0
+	b.fold = b.fold[:0]
 	// Clear the slices to allow GC
 	for i := range b.lines {
 		b.lines[i] = ""
 		b.entries[i] = nil
+		b.times[i] = time.Time{}
+	}
+
+	if b.store != nil {
+		if err := b.store.Reset(); err != nil {
+			log.Printf("service logs: failed to clear persisted lines: %v", err)
+		}
 	}
 }
 
@@ -4,11 +4,15 @@
 package service
 
 import (
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/config"
 )
 
 func TestLogBuffer_Write(t *testing.T) {
@@ -220,3 +224,132 @@ func TestLogBuffer_TrailingNewline(t *testing.T) {
 	assert.Equal(t, "line 1", lines[0])
 	assert.Equal(t, "line 2", lines[1])
 }
+
+func TestLogBuffer_Persistence(t *testing.T) {
+	dir := t.TempDir()
+
+	buffer := NewLogBuffer(3)
+	require.NoError(t, buffer.EnablePersistence(dir))
+	for _, line := range []string{`{"msg":"one"}`, `{"msg":"two"}`, `{"msg":"three"}`, `{"msg":"four"}`} {
+		buffer.Write(line)
+	}
+	buffer.ClosePersistence()
+
+	// A new buffer (e.g. after a trellis restart) reloads the newest lines,
+	// reparses them, and continues the sequence
+	restored := NewLogBuffer(3)
+	restored.SetParser(config.LogParserConfig{Type: "json"}, nil)
+	require.NoError(t, restored.EnablePersistence(dir))
+	defer restored.ClosePersistence()
+
+	assert.Equal(t, []string{`{"msg":"two"}`, `{"msg":"three"}`, `{"msg":"four"}`}, restored.All())
+	assert.Equal(t, int64(4), restored.Sequence())
+	entries := restored.Entries(1)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0])
+	assert.Equal(t, "four", entries[0].Message)
+
+	restored.Write(`{"msg":"five"}`)
+	assert.Equal(t, int64(5), restored.Sequence())
+}
+
+func TestLogBuffer_ReadsOlderLinesFromDisk(t *testing.T) {
+	dir := t.TempDir()
+
+	buffer := NewLogBuffer(3)
+	require.NoError(t, buffer.EnablePersistence(dir))
+	start := time.Now()
+	for i := 1; i <= 8; i++ {
+		buffer.Write(fmt.Sprintf(`{"msg":"line %d"}`, i))
+	}
+	buffer.ClosePersistence()
+
+	// Writes after closing only reach the ring
+	buffer.Write(`{"msg":"unpersisted"}`)
+
+	restored := NewLogBuffer(3)
+	restored.SetParser(config.LogParserConfig{Type: "json"}, nil)
+	require.NoError(t, restored.EnablePersistence(dir))
+	defer restored.ClosePersistence()
+
+	lineTexts := func(lines []LogLine) []string {
+		var result []string
+		for _, l := range lines {
+			result = append(result, l.Line)
+		}
+		return result
+	}
+
+	// The ring holds lines 6-8; paging back continues into the store
+	older := restored.Before(7, 4)
+	assert.Equal(t, []string{`{"msg":"line 3"}`, `{"msg":"line 4"}`, `{"msg":"line 5"}`, `{"msg":"line 6"}`}, lineTexts(older))
+	assert.Equal(t, int64(3), older[0].Sequence)
+	require.NotNil(t, older[0].Entry)
+	assert.Equal(t, "line 3", older[0].Entry.Message)
+	assert.Len(t, restored.Before(restored.Sequence()+1, 0), 8)
+	assert.Empty(t, restored.Before(1, 0))
+
+	// A range starting before the ring is served from the store
+	all := restored.Range(start, time.Now(), 0)
+	require.Len(t, all, 8)
+	assert.Equal(t, `{"msg":"line 1"}`, all[0].Line)
+	assert.Equal(t, `{"msg":"line 8"}`, all[7].Line)
+	assert.Len(t, restored.Range(start, time.Now(), 2), 2)
+}
+
+func TestLogBuffer_ClearRemovesPersistedLines(t *testing.T) {
+	dir := t.TempDir()
+
+	buffer := NewLogBuffer(10)
+	require.NoError(t, buffer.EnablePersistence(dir))
+	buffer.Write("old line")
+	buffer.Clear()
+	buffer.Write("new line")
+	buffer.ClosePersistence()
+
+	restored := NewLogBuffer(10)
+	require.NoError(t, restored.EnablePersistence(dir))
+	defer restored.ClosePersistence()
+	assert.Equal(t, []string{"new line"}, restored.All())
+	assert.Equal(t, int64(2), restored.Sequence())
+}
+
+func TestLogBuffer_MultilineFolding(t *testing.T) {
+	buffer := NewLogBuffer(10)
+	buffer.SetParser(config.LogParserConfig{
+		Type:      "logfmt",
+		Message:   "msg",
+		Multiline: &config.MultilineConfig{Start: `^msg=`, Timeout: "1m"},
+	}, nil)
+
+	ch := buffer.Subscribe()
+	defer buffer.Unsubscribe(ch)
+
+	buffer.Write(`msg=failed`)
+	buffer.Write(`panic: boom`)
+	buffer.Write(`  main.go:10`)
+	buffer.Write(`msg=next`)
+
+	// Raw lines are kept as written
+	assert.Equal(t, 4, buffer.Size())
+
+	entries := buffer.Entries(10)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "failed", entries[0].Message)
+	assert.Equal(t, "panic: boom\n  main.go:10", entries[0].Fields["stack"])
+	assert.Equal(t, "next", entries[1].Message)
+
+	var continued []bool
+	for i := 0; i < 4; i++ {
+		line := <-ch
+		continued = append(continued, line.Continuation)
+		require.NotNil(t, line.Entry)
+	}
+	assert.Equal(t, []bool{false, true, true, false}, continued)
+
+	buffer.Clear()
+	buffer.Write(`  orphan`)
+	entries = buffer.Entries(10)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "  orphan", entries[0].Raw)
+}
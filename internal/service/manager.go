@@ -7,12 +7,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/wingedpig/trellis/internal/config"
 	"github.com/wingedpig/trellis/internal/events"
 	"github.com/wingedpig/trellis/internal/logs"
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // ServiceManager manages multiple services.
@@ -21,6 +24,8 @@ type ServiceManager struct {
 	services map[string]*managedService
 	bus      events.EventBus
 	analyzer *CrashAnalyzer
+	logDir   string // Directory for persisted service logs ("" = disabled)
+	secrets  *secrets.Manager
 }
 
 type managedService struct {
@@ -51,6 +56,53 @@ func NewManager(configs []config.ServiceConfig, bus events.EventBus, _ interface
 	return mgr
 }
 
+// SetLogDir enables on-disk log buffers for services with logging.persist,
+// each in its own subdirectory of dir. Processes created later (e.g. by
+// UpdateConfigs) are persisted too.
+func (m *ServiceManager) SetLogDir(dir string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.logDir = dir
+	for _, svc := range m.services {
+		m.enableLogPersistence(svc.config, svc.process)
+	}
+}
+
+// SetSecrets sets the manager that resolves secrets in service environments
+// and redacts them from service output, for current and later processes.
+func (m *ServiceManager) SetSecrets(sm *secrets.Manager) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.secrets = sm
+	for _, svc := range m.services {
+		svc.process.SetSecrets(sm)
+	}
+}
+
+// newProcess creates a process for cfg with log persistence and secrets
+// applied. Caller must hold m.mu.
+func (m *ServiceManager) newProcess(cfg config.ServiceConfig) *Process {
+	proc := NewProcess(cfg, m.bus)
+	proc.SetSecrets(m.secrets)
+	m.enableLogPersistence(cfg, proc)
+	return proc
+}
+
+// enableLogPersistence attaches the on-disk log store if the service asks
+// for one. Failures are logged and the service keeps an in-memory buffer.
+// Caller must hold m.mu.
+func (m *ServiceManager) enableLogPersistence(cfg config.ServiceConfig, proc *Process) {
+	if m.logDir == "" || !cfg.Logging.Persist {
+		return
+	}
+	dir := filepath.Join(m.logDir, logs.StoreDirName(cfg.Name))
+	if err := proc.EnableLogPersistence(dir); err != nil {
+		log.Printf("Service %s: log persistence disabled: %v", cfg.Name, err)
+	}
+}
+
 // Start starts a service by name.
 func (m *ServiceManager) Start(ctx context.Context, name string) error {
 	return m.startInternal(ctx, name, make(map[string]bool), true)
@@ -73,8 +125,8 @@ func (m *ServiceManager) startInternal(ctx context.Context, name string, visitin
 		return fmt.Errorf("service %q not found", name)
 	}
 
-	// If already running, return success (idempotent)
-	if svc.process.Status().State == StatusRunning {
+	// If already running or waiting on its readiness check, return success (idempotent)
+	if isActive(svc.process.Status().State) {
 		m.mu.Unlock()
 		return nil
 	}
@@ -106,6 +158,7 @@ func (m *ServiceManager) startInternal(ctx context.Context, name string, visitin
 	m.mu.Unlock()
 
 	// Start dependencies first (with cycle detection)
+	var depProcs []*Process
 	for _, dep := range deps {
 		// Look up the dependency config to check if it's enabled
 		m.mu.Lock()
@@ -125,6 +178,19 @@ func (m *ServiceManager) startInternal(ctx context.Context, name string, visitin
 		if err := m.startInternal(ctx, dep, visiting, emitEvent); err != nil {
 			return fmt.Errorf("failed to start dependency %q: %w", dep, err)
 		}
+
+		m.mu.RLock()
+		depProcs = append(depProcs, depSvc.process)
+		m.mu.RUnlock()
+	}
+
+	// Wait for dependencies to pass their readiness checks, so depends_on
+	// guarantees upstreams are serving rather than merely forked. Services
+	// without a readiness check are ready as soon as they start.
+	for _, depProc := range depProcs {
+		if err := depProc.WaitReady(ctx); err != nil {
+			return fmt.Errorf("dependency %q not ready: %w", depProc.cfg.Name, err)
+		}
 	}
 
 	// Set up exit handler for restart policy
@@ -155,6 +221,12 @@ func (m *ServiceManager) startInternal(ctx context.Context, name string, visitin
 	return nil
 }
 
+// isActive reports whether a process is up, including one still waiting on
+// its readiness check.
+func isActive(state ProcessState) bool {
+	return state == StatusRunning || state == StatusStarting
+}
+
 // stoppingTracker tracks services being stopped with thread-safe access.
 // Used to prevent duplicate stops when StopAll runs parallel goroutines
 // that may recursively stop the same dependent services.
@@ -213,7 +285,7 @@ func (m *ServiceManager) stopInternal(ctx context.Context, name string, tracker
 	m.mu.RLock()
 	for svcName, s := range m.services {
 		for _, dep := range s.config.DependsOn {
-			if dep == name && s.process.Status().State == StatusRunning {
+			if dep == name && isActive(s.process.Status().State) {
 				m.mu.RUnlock()
 				if err := m.stopInternal(ctx, svcName, tracker); err != nil {
 					dependentErrors = append(dependentErrors, fmt.Errorf("dependent %s: %w", svcName, err))
@@ -231,7 +303,7 @@ func (m *ServiceManager) stopInternal(ctx context.Context, name string, tracker
 	}
 
 	// Check if service was running before stop (to avoid duplicate events)
-	wasRunning := svc.process.Status().State == StatusRunning
+	wasRunning := isActive(svc.process.Status().State)
 
 	err := svc.process.Stop(ctx)
 
@@ -260,7 +332,7 @@ func (m *ServiceManager) Restart(ctx context.Context, name string, trigger Resta
 	m.mu.Unlock()
 
 	// Stop if running
-	if svc.process.Status().State == StatusRunning {
+	if isActive(svc.process.Status().State) {
 		if err := m.Stop(ctx, name); err != nil {
 			return err
 		}
@@ -295,13 +367,15 @@ func (m *ServiceManager) Restart(ctx context.Context, name string, trigger Resta
 // Status returns the status of a service.
 func (m *ServiceManager) Status(name string) (ServiceStatus, error) {
 	m.mu.RLock()
-	svc, ok := m.services[name]
-	m.mu.RUnlock()
+	defer m.mu.RUnlock()
 
+	svc, ok := m.services[name]
 	if !ok {
 		return ServiceStatus{}, fmt.Errorf("service %q not found", name)
 	}
 
+	// handleExit updates restartCount under m.mu, possibly from a liveness
+	// probe restarting the service
 	status := svc.process.Status()
 	status.RestartCount = svc.restartCount
 	return status, nil
@@ -346,6 +420,34 @@ func (m *ServiceManager) ParsedLogs(name string, lines int) ([]*logs.LogEntry, e
 	return svc.process.ParsedLogs(lines), nil
 }
 
+// LogsBefore returns up to lines log lines of a service written before
+// sequence number seq, reading persisted lines once its buffer runs out.
+func (m *ServiceManager) LogsBefore(name string, seq int64, lines int) ([]LogLine, error) {
+	m.mu.RLock()
+	svc, ok := m.services[name]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+
+	return svc.process.LogsBefore(seq, lines), nil
+}
+
+// LogsRange returns up to lines log lines of a service written between
+// start and end, reading persisted lines if the range predates its buffer.
+func (m *ServiceManager) LogsRange(name string, start, end time.Time, lines int) ([]LogLine, error) {
+	m.mu.RLock()
+	svc, ok := m.services[name]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+
+	return svc.process.LogsRange(start, end, lines), nil
+}
+
 // HasParser returns true if the service has a log parser configured.
 func (m *ServiceManager) HasParser(name string) bool {
 	m.mu.RLock()
@@ -508,7 +610,7 @@ func (m *ServiceManager) StopAll(ctx context.Context) error {
 	m.mu.RLock()
 	names := make([]string, 0, len(m.services))
 	for name, svc := range m.services {
-		if svc.process.Status().State == StatusRunning {
+		if isActive(svc.process.Status().State) {
 			names = append(names, name)
 		}
 	}
@@ -549,7 +651,7 @@ func (m *ServiceManager) StopWatched(ctx context.Context) error {
 	m.mu.RLock()
 	names := make([]string, 0, len(m.services))
 	for name, svc := range m.services {
-		if svc.process.Status().State == StatusRunning && svc.config.IsWatching() {
+		if isActive(svc.process.Status().State) && svc.config.IsWatching() {
 			names = append(names, name)
 		}
 	}
@@ -663,9 +765,9 @@ func (m *ServiceManager) StartWatched(ctx context.Context) error {
 // GetService returns service info by name.
 func (m *ServiceManager) GetService(name string) (ServiceInfo, bool) {
 	m.mu.RLock()
-	svc, ok := m.services[name]
-	m.mu.RUnlock()
+	defer m.mu.RUnlock()
 
+	svc, ok := m.services[name]
 	if !ok {
 		return ServiceInfo{}, false
 	}
@@ -799,8 +901,10 @@ func (m *ServiceManager) handleExit(name string, exitCode int, proc *Process, po
 }
 
 // UpdateConfigs updates the service configurations.
-// This is used when switching worktrees to update paths.
-// Services should be stopped before calling this.
+// This is used when switching worktrees to update paths, and when the config
+// file is reloaded. Services whose config is unchanged keep their process,
+// logs and state, so they may be left running; any other service should be
+// stopped before calling this.
 func (m *ServiceManager) UpdateConfigs(configs []config.ServiceConfig) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -816,11 +920,15 @@ func (m *ServiceManager) UpdateConfigs(configs []config.ServiceConfig) {
 	// Update existing services and add new ones
 	for name, cfg := range newConfigs {
 		if svc, ok := m.services[name]; ok {
+			if reflect.DeepEqual(svc.config, cfg) {
+				continue
+			}
 			// Update existing service config and create new process
 			log.Printf("Updating service %s: old workdir=%s, new workdir=%s", name, svc.config.WorkDir, cfg.WorkDir)
 			svc.process.CloseLogSubscribers() // Close orphaned subscribers before replacing
+			svc.process.CloseLogStore()
 			svc.config = cfg
-			svc.process = NewProcess(cfg, m.bus)
+			svc.process = m.newProcess(cfg)
 			svc.restartCount = 0 // Reset restart count for new worktree
 			// Refresh enabled flag from new config
 			svc.enabled = cfg.Disabled == nil || !*cfg.Disabled
@@ -832,15 +940,16 @@ func (m *ServiceManager) UpdateConfigs(configs []config.ServiceConfig) {
 			}
 			m.services[name] = &managedService{
 				config:  cfg,
-				process: NewProcess(cfg, m.bus),
+				process: m.newProcess(cfg),
 				enabled: enabled,
 			}
 		}
 	}
 
 	// Remove services that are no longer in config
-	for name := range m.services {
+	for name, svc := range m.services {
 		if _, ok := newConfigs[name]; !ok {
+			svc.process.CloseLogStore()
 			delete(m.services, name)
 		}
 	}
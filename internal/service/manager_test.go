@@ -598,3 +598,100 @@ func TestManager_LogSize_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
+
+func TestManager_DependsOnWaitsForReadiness(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	var readyCount atomic.Int32
+	bus.Subscribe(events.EventServiceReady, func(ctx context.Context, e events.Event) error {
+		readyCount.Add(1)
+		return nil
+	})
+
+	services := []config.ServiceConfig{
+		{
+			Name:      "db",
+			Command:   []string{"sh", "-c", "sleep 0.3; echo ready; sleep 60"},
+			WorkDir:   "/tmp",
+			Readiness: &config.ProbeConfig{LogLine: "^ready$", Timeout: "5s"},
+		},
+		{Name: "api", Command: []string{"sleep", "60"}, WorkDir: "/tmp", DependsOn: []string{"db"}},
+	}
+
+	mgr := NewManager(services, bus, nil)
+	defer mgr.StopAll(context.Background())
+
+	start := time.Now()
+	require.NoError(t, mgr.Start(context.Background(), "api"))
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond, "api should wait for db readiness")
+
+	dbStatus, _ := mgr.Status("db")
+	apiStatus, _ := mgr.Status("api")
+	assert.Equal(t, StatusRunning, dbStatus.State)
+	assert.Equal(t, StatusRunning, apiStatus.State)
+	assert.Equal(t, int32(1), readyCount.Load())
+}
+
+func TestManager_LivenessFailureRestarts(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	var unhealthyCount atomic.Int32
+	bus.Subscribe(events.EventServiceUnhealthy, func(ctx context.Context, e events.Event) error {
+		unhealthyCount.Add(1)
+		return nil
+	})
+
+	services := []config.ServiceConfig{
+		{
+			Name:          "test-service",
+			Command:       []string{"sleep", "60"},
+			WorkDir:       "/tmp",
+			RestartPolicy: "on-failure",
+			RestartDelay:  "10ms",
+			MaxRestarts:   1,
+			Liveness:      &config.ProbeConfig{Exec: []string{"false"}, Interval: "20ms", Retries: 2},
+		},
+	}
+
+	mgr := NewManager(services, bus, nil)
+	defer mgr.StopAll(context.Background())
+
+	require.NoError(t, mgr.Start(context.Background(), "test-service"))
+
+	require.Eventually(t, func() bool {
+		status, _ := mgr.Status("test-service")
+		return status.RestartCount >= 1
+	}, 3*time.Second, 20*time.Millisecond)
+	assert.GreaterOrEqual(t, unhealthyCount.Load(), int32(1))
+}
+
+func TestManager_UpdateConfigs_KeepsUnchanged(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	api := config.ServiceConfig{Name: "api", Command: []string{"sleep", "60"}, WorkDir: "/tmp"}
+	worker := config.ServiceConfig{Name: "worker", Command: []string{"sleep", "60"}, WorkDir: "/tmp"}
+	mgr := NewManager([]config.ServiceConfig{api, worker}, bus, nil)
+	defer mgr.StopAll(context.Background())
+
+	require.NoError(t, mgr.Start(context.Background(), "api"))
+	time.Sleep(100 * time.Millisecond)
+	before, err := mgr.Status("api")
+	require.NoError(t, err)
+
+	// worker changes and web is added; api is untouched and keeps running
+	worker.Args = []string{"-v"}
+	web := config.ServiceConfig{Name: "web", Command: []string{"sleep", "60"}, WorkDir: "/tmp"}
+	mgr.UpdateConfigs([]config.ServiceConfig{api, worker, web})
+
+	after, err := mgr.Status("api")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, after.State)
+	assert.Equal(t, before.PID, after.PID)
+
+	_, ok := mgr.GetService("web")
+	assert.True(t, ok)
+	assert.Len(t, mgr.List(), 3)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrNoWorktreeServices is returned for a worktree whose services aren't
+// running in the pool.
+var ErrNoWorktreeServices = errors.New("worktree has no services running")
+
+// Pool runs the services of worktrees other than the active one, each
+// worktree's in a manager of its own, so several branches can be up at
+// once. The active worktree's services stay with the main manager.
+type Pool struct {
+	mu       sync.Mutex
+	build    func(worktree string) (*ServiceManager, error)
+	managers map[string]*ServiceManager
+}
+
+// NewPool creates a pool that calls build to create the manager for a
+// worktree's services the first time they're started.
+func NewPool(build func(worktree string) (*ServiceManager, error)) *Pool {
+	return &Pool{
+		build:    build,
+		managers: make(map[string]*ServiceManager),
+	}
+}
+
+// Start starts the enabled services of the named worktree, creating its
+// manager if it doesn't have one.
+func (p *Pool) Start(ctx context.Context, worktree string) error {
+	p.mu.Lock()
+	m, ok := p.managers[worktree]
+	if !ok {
+		var err error
+		m, err = p.build(worktree)
+		if err != nil {
+			p.mu.Unlock()
+			return err
+		}
+		p.managers[worktree] = m
+	}
+	p.mu.Unlock()
+
+	return m.StartAll(ctx)
+}
+
+// Stop stops the services of the named worktree and removes its manager.
+func (p *Pool) Stop(ctx context.Context, worktree string) error {
+	p.mu.Lock()
+	m, ok := p.managers[worktree]
+	delete(p.managers, worktree)
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoWorktreeServices, worktree)
+	}
+	return m.StopAll(ctx)
+}
+
+// Get returns the manager of the named worktree's services.
+func (p *Pool) Get(worktree string) (Manager, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m, ok := p.managers[worktree]
+	return m, ok
+}
+
+// Worktrees returns the names of the worktrees with services in the pool.
+func (p *Pool) Worktrees() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.managers))
+	for name := range p.managers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StopAll stops the services of every worktree in the pool.
+func (p *Pool) StopAll(ctx context.Context) error {
+	var errs []error
+	for _, name := range p.Worktrees() {
+		if err := p.Stop(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
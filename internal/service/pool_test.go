@@ -0,0 +1,61 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/events"
+)
+
+func TestPool(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+	ctx := context.Background()
+
+	var built []string
+	pool := NewPool(func(worktree string) (*ServiceManager, error) {
+		if worktree == "broken" {
+			return nil, errors.New("no such worktree")
+		}
+		built = append(built, worktree)
+		return NewManager([]config.ServiceConfig{
+			{Name: "api", Command: []string{"sleep", "60"}, WorkDir: "/tmp"},
+		}, events.WithWorktree(bus, worktree), nil), nil
+	})
+	defer pool.StopAll(ctx)
+
+	started, err := bus.Subscribe(events.EventServiceStarted, func(ctx context.Context, e events.Event) error {
+		assert.Equal(t, "feature", e.Worktree)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, pool.Start(ctx, "feature"))
+	require.NoError(t, bus.Unsubscribe(started))
+
+	m, ok := pool.Get("feature")
+	require.True(t, ok)
+	status, err := m.Status("api")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, status.State)
+
+	// Starting again reuses the manager
+	require.NoError(t, pool.Start(ctx, "feature"))
+	assert.Equal(t, []string{"feature"}, built)
+
+	assert.Error(t, pool.Start(ctx, "broken"))
+	assert.Equal(t, []string{"feature"}, pool.Worktrees())
+
+	require.NoError(t, pool.Stop(ctx, "feature"))
+	status, _ = m.Status("api")
+	assert.Equal(t, StatusStopped, status.State)
+	_, ok = pool.Get("feature")
+	assert.False(t, ok)
+	assert.ErrorIs(t, pool.Stop(ctx, "feature"), ErrNoWorktreeServices)
+}
@@ -0,0 +1,195 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+const (
+	defaultProbeInterval    = time.Second
+	defaultProbeTimeout     = time.Second
+	defaultReadinessTries   = 30
+	defaultLivenessFailures = 3
+)
+
+// probe is a compiled readiness or liveness check.
+type probe struct {
+	kind     string // "http", "tcp", "log_line", "exec"
+	target   string
+	exec     []string
+	logMatch *regexp.Regexp
+	interval time.Duration
+	timeout  time.Duration
+	retries  int
+	workDir  string
+	env      []string
+}
+
+// newProbe compiles a probe config. defaultRetries applies when retries is unset.
+func newProbe(cfg *config.ProbeConfig, defaultRetries int) (*probe, error) {
+	p := &probe{
+		kind:     cfg.Type(),
+		interval: config.ParseDuration(cfg.Interval, defaultProbeInterval),
+		timeout:  config.ParseDuration(cfg.Timeout, defaultProbeTimeout),
+		retries:  cfg.Retries,
+	}
+	if p.retries <= 0 {
+		p.retries = defaultRetries
+	}
+
+	switch p.kind {
+	case "http":
+		p.target = cfg.HTTP
+	case "tcp":
+		p.target = cfg.TCP
+	case "exec":
+		p.exec = cfg.Exec
+	case "log_line":
+		re, err := regexp.Compile(cfg.LogLine)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log_line regex: %w", err)
+		}
+		p.logMatch = re
+		// A log line probe waits once rather than polling, so its timeout
+		// covers the whole startup window unless set explicitly.
+		if cfg.Timeout == "" {
+			p.timeout = p.interval * time.Duration(p.retries)
+		}
+	default:
+		return nil, fmt.Errorf("probe must specify one of http, tcp, log_line, or exec")
+	}
+
+	return p, nil
+}
+
+// check runs a single polled attempt (http, tcp, exec).
+func (p *probe) check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	switch p.kind {
+	case "http":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("GET %s returned %d", p.target, resp.StatusCode)
+		}
+		return nil
+	case "tcp":
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", p.target)
+		if err != nil {
+			return err
+		}
+		conn.Close()
+		return nil
+	case "exec":
+		cmd := exec.CommandContext(ctx, p.exec[0], p.exec[1:]...)
+		cmd.Dir = p.workDir
+		cmd.Env = p.env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if len(out) > 0 {
+				return fmt.Errorf("%w: %s", err, truncateProbeOutput(out))
+			}
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("probe type %q cannot be polled", p.kind)
+	}
+}
+
+// waitReady blocks until the probe passes, returning an error once retries
+// are exhausted or ctx is cancelled. Log line probes read from lines, which
+// must be subscribed before the process starts so early output isn't missed.
+func (p *probe) waitReady(ctx context.Context, lines <-chan LogLine) error {
+	if p.kind == "log_line" {
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					return fmt.Errorf("log stream closed before a line matched %q", p.logMatch.String())
+				}
+				if p.logMatch.MatchString(line.Line) {
+					return nil
+				}
+			case <-timer.C:
+				return fmt.Errorf("no log line matched %q within %s", p.logMatch.String(), p.timeout)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < p.retries; attempt++ {
+		if lastErr = p.check(ctx); lastErr == nil {
+			return nil
+		}
+		select {
+		case <-time.After(p.interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("%d attempts failed, last error: %v", p.retries, lastErr)
+}
+
+// watch polls the probe until ctx is cancelled, calling onFail once when
+// consecutive failures reach the retry threshold.
+func (p *probe) watch(ctx context.Context, onFail func(failures int, err error)) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := p.check(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		if failures >= p.retries {
+			onFail(failures, err)
+			return
+		}
+	}
+}
+
+// truncateProbeOutput trims exec probe output for inclusion in error messages.
+func truncateProbeOutput(out []byte) string {
+	const max = 200
+	s := string(out)
+	if len(s) > max {
+		s = s[:max] + "..."
+	}
+	return s
+}
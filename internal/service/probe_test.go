@@ -0,0 +1,136 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wingedpig/trellis/internal/config"
+)
+
+func TestProbe_HTTP(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	pr, err := newProbe(&config.ProbeConfig{HTTP: srv.URL}, 1)
+	require.NoError(t, err)
+
+	assert.Error(t, pr.check(context.Background()))
+	status = http.StatusOK
+	assert.NoError(t, pr.check(context.Background()))
+}
+
+func TestProbe_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	pr, err := newProbe(&config.ProbeConfig{TCP: addr}, 1)
+	require.NoError(t, err)
+	assert.NoError(t, pr.check(context.Background()))
+
+	ln.Close()
+	assert.Error(t, pr.check(context.Background()))
+}
+
+func TestProbe_Exec(t *testing.T) {
+	pr, err := newProbe(&config.ProbeConfig{Exec: []string{"true"}}, 1)
+	require.NoError(t, err)
+	assert.NoError(t, pr.check(context.Background()))
+
+	pr, err = newProbe(&config.ProbeConfig{Exec: []string{"sh", "-c", "echo nope; exit 1"}}, 1)
+	require.NoError(t, err)
+	err = pr.check(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "nope")
+}
+
+func TestProbe_WaitReady_RetriesExhausted(t *testing.T) {
+	pr, err := newProbe(&config.ProbeConfig{Exec: []string{"false"}, Interval: "10ms", Retries: 3}, 30)
+	require.NoError(t, err)
+
+	err = pr.waitReady(context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "3 attempts failed")
+}
+
+func TestProbe_InvalidConfig(t *testing.T) {
+	_, err := newProbe(&config.ProbeConfig{}, 1)
+	assert.Error(t, err)
+
+	_, err = newProbe(&config.ProbeConfig{LogLine: "("}, 1)
+	assert.Error(t, err)
+}
+
+func TestProcess_Readiness_LogLine(t *testing.T) {
+	cfg := config.ServiceConfig{
+		Name:      "test-service",
+		Command:   []string{"sh", "-c", "sleep 0.2; echo listening on :8080; sleep 60"},
+		WorkDir:   "/tmp",
+		Readiness: &config.ProbeConfig{LogLine: `listening on`, Timeout: "5s"},
+	}
+
+	proc := NewProcess(cfg, nil)
+	defer proc.Stop(context.Background())
+
+	require.NoError(t, proc.Start(context.Background()))
+	assert.Equal(t, StatusStarting, proc.Status().State)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	require.NoError(t, proc.WaitReady(ctx))
+	assert.Equal(t, StatusRunning, proc.Status().State)
+}
+
+func TestProcess_Readiness_FailureKillsProcess(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	cfg := config.ServiceConfig{
+		Name:      "test-service",
+		Command:   []string{"sleep", "60"},
+		WorkDir:   "/tmp",
+		Readiness: &config.ProbeConfig{Exec: []string{"false"}, Interval: "10ms", Retries: 2},
+	}
+
+	proc := NewProcess(cfg, bus)
+	defer proc.Stop(context.Background())
+
+	require.NoError(t, proc.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	err := proc.WaitReady(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited before becoming ready")
+
+	require.Eventually(t, func() bool {
+		return proc.Status().State == StatusCrashed
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestProcess_NoReadiness_ReadyImmediately(t *testing.T) {
+	cfg := config.ServiceConfig{
+		Name:    "test-service",
+		Command: []string{"sleep", "60"},
+		WorkDir: "/tmp",
+	}
+
+	proc := NewProcess(cfg, nil)
+	defer proc.Stop(context.Background())
+
+	require.NoError(t, proc.Start(context.Background()))
+	assert.Equal(t, StatusRunning, proc.Status().State)
+	assert.NoError(t, proc.WaitReady(context.Background()))
+}
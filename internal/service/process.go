@@ -19,6 +19,7 @@ import (
 	"github.com/wingedpig/trellis/internal/config"
 	"github.com/wingedpig/trellis/internal/events"
 	"github.com/wingedpig/trellis/internal/logs"
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 const (
@@ -41,10 +42,13 @@ type Process struct {
 	logs          *LogBuffer
 	stopRequested bool
 	parentCtx     context.Context
+	startErr      string           // why the last start failed, "" if it didn't
+	secrets       *secrets.Manager // resolves env secrets and redacts output
 
 	onExit    func(int)
 	cancelFn  context.CancelFunc
 	waitDone  chan struct{}
+	ready     chan struct{} // closed once the readiness check passes (immediately if none)
 	isRunning bool
 }
 
@@ -70,6 +74,14 @@ func NewProcess(cfg config.ServiceConfig, bus events.EventBus) *Process {
 	}
 }
 
+// SetSecrets sets the manager that resolves the secrets referenced from the
+// service's environment and redacts their values from its output.
+func (p *Process) SetSecrets(m *secrets.Manager) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secrets = m
+}
+
 // Start starts the process.
 func (p *Process) Start(ctx context.Context) error {
 	p.mu.Lock()
@@ -79,6 +91,16 @@ func (p *Process) Start(ctx context.Context) error {
 		return fmt.Errorf("process already running")
 	}
 
+	err := p.start(ctx)
+	p.startErr = ""
+	if err != nil {
+		p.startErr = err.Error()
+	}
+	return err
+}
+
+// start does the work of Start. Caller must hold p.mu.
+func (p *Process) start(ctx context.Context) error {
 	cmdArgs := p.cfg.GetCommand()
 	if len(cmdArgs) == 0 {
 		err := fmt.Errorf("service %s: empty command", p.cfg.Name)
@@ -91,6 +113,40 @@ func (p *Process) Start(ctx context.Context) error {
 		cmdArgs = append(cmdArgs, p.cfg.Args...)
 	}
 
+	// Compile health checks up front so a bad probe fails the start
+	var readiness, liveness *probe
+	if p.cfg.Readiness != nil {
+		pr, err := newProbe(p.cfg.Readiness, defaultReadinessTries)
+		if err != nil {
+			err = fmt.Errorf("service %s: readiness: %w", p.cfg.Name, err)
+			p.logs.Write(fmt.Sprintf("[trellis] Error: %v", err))
+			return err
+		}
+		readiness = pr
+	}
+	if p.cfg.Liveness != nil {
+		pr, err := newProbe(p.cfg.Liveness, defaultLivenessFailures)
+		if err != nil {
+			err = fmt.Errorf("service %s: liveness: %w", p.cfg.Name, err)
+			p.logs.Write(fmt.Sprintf("[trellis] Error: %v", err))
+			return err
+		}
+		if pr.kind == "log_line" {
+			err := fmt.Errorf("service %s: liveness: log_line probes are only supported for readiness", p.cfg.Name)
+			p.logs.Write(fmt.Sprintf("[trellis] Error: %v", err))
+			return err
+		}
+		liveness = pr
+	}
+
+	// Build the environment, reading the env file and resolving secrets
+	env, err := p.secrets.Environ(ctx, p.cfg.EnvFile, p.cfg.WorkDir, p.cfg.Env)
+	if err != nil {
+		err = fmt.Errorf("service %s: %w", p.cfg.Name, err)
+		p.logs.Write(fmt.Sprintf("[trellis] Error: %v", err))
+		return err
+	}
+
 	// Create a cancellable context
 	runCtx, cancel := context.WithCancel(ctx)
 	p.cancelFn = cancel
@@ -103,10 +159,14 @@ func (p *Process) Start(ctx context.Context) error {
 	// Create a new process group so we can kill child processes too
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
-	// Set environment
-	cmd.Env = os.Environ()
-	for k, v := range p.cfg.Env {
-		cmd.Env = append(cmd.Env, k+"="+v)
+	cmd.Env = env
+
+	// Exec probes run alongside the service with the same directory and env
+	for _, pr := range []*probe{readiness, liveness} {
+		if pr != nil {
+			pr.workDir = p.cfg.WorkDir
+			pr.env = cmd.Env
+		}
 	}
 
 	// Capture stdout and stderr
@@ -125,10 +185,20 @@ func (p *Process) Start(ctx context.Context) error {
 	// Log the command being started
 	p.logs.Write(fmt.Sprintf("[trellis] Starting: %v (workdir: %s)", cmdArgs, p.cfg.WorkDir))
 
+	// Subscribe before starting so a log_line readiness probe can't miss
+	// output written before the probe goroutine gets going
+	var readyLines chan LogLine
+	if readiness != nil && readiness.kind == "log_line" {
+		readyLines = p.logs.Subscribe()
+	}
+
 	// Start the process
 	p.state = StatusStarting
 	if err := cmd.Start(); err != nil {
 		p.state = StatusStopped
+		if readyLines != nil {
+			p.logs.Unsubscribe(readyLines)
+		}
 		p.logs.Write(fmt.Sprintf("[trellis] Failed to start: %v", err))
 		return fmt.Errorf("start process: %w", err)
 	}
@@ -138,8 +208,21 @@ func (p *Process) Start(ctx context.Context) error {
 	p.startedAt = time.Now()
 	p.exitCode = 0
 	p.isRunning = true
-	p.state = StatusRunning
 	p.waitDone = make(chan struct{})
+	p.ready = make(chan struct{})
+
+	// Without a readiness check the process counts as running as soon as it
+	// has forked; otherwise it stays starting until the check passes.
+	if readiness == nil {
+		p.state = StatusRunning
+		close(p.ready)
+		if liveness != nil {
+			go p.runLiveness(runCtx, cmd, liveness)
+		}
+	} else {
+		p.logs.Write(fmt.Sprintf("[trellis] Waiting for %s readiness check", readiness.kind))
+		go p.awaitReadiness(runCtx, cmd, readiness, readyLines, liveness)
+	}
 
 	// Capture output in background
 	var readersDone sync.WaitGroup
@@ -259,7 +342,122 @@ func (p *Process) Status() ServiceStatus {
 		ExitCode:  p.exitCode,
 		StartedAt: p.startedAt,
 		StoppedAt: p.stoppedAt,
+		Error:     p.secrets.Redact(p.startErr),
+	}
+}
+
+// WaitReady blocks until the current run passes its readiness check. It
+// returns nil immediately for services without a readiness check, and an
+// error if the process exits (including being killed for failing the check)
+// before becoming ready or if ctx is cancelled.
+func (p *Process) WaitReady(ctx context.Context) error {
+	p.mu.RLock()
+	ready := p.ready
+	waitDone := p.waitDone
+	p.mu.RUnlock()
+
+	if ready == nil {
+		return nil // never started
+	}
+
+	// Prefer ready when both have fired: a process that became ready and
+	// then exited normally shouldn't be reported as never ready.
+	select {
+	case <-ready:
+		return nil
+	default:
 	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-waitDone:
+		return fmt.Errorf("service %s exited before becoming ready", p.cfg.Name)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// awaitReadiness runs the readiness probe for cmd, moving the process from
+// starting to running when it passes. On failure the process is killed so the
+// exit flows through the normal crash/restart-policy path.
+func (p *Process) awaitReadiness(ctx context.Context, cmd *exec.Cmd, pr *probe, lines chan LogLine, liveness *probe) {
+	started := time.Now()
+	err := pr.waitReady(ctx, lines)
+	if lines != nil {
+		p.logs.Unsubscribe(lines)
+	}
+	if ctx.Err() != nil {
+		return // process exited while we were waiting
+	}
+	if err != nil {
+		p.markUnhealthy(cmd, "readiness", err)
+		return
+	}
+
+	p.mu.Lock()
+	if p.cmd != cmd {
+		p.mu.Unlock()
+		return
+	}
+	if p.state == StatusStarting {
+		p.state = StatusRunning
+	}
+	ready := p.ready
+	pid := p.pid
+	p.mu.Unlock()
+	close(ready)
+
+	elapsed := time.Since(started).Round(time.Millisecond)
+	p.logs.Write(fmt.Sprintf("[trellis] Ready after %s", elapsed))
+	if p.bus != nil {
+		p.bus.Publish(context.Background(), events.Event{
+			Type: events.EventServiceReady,
+			Payload: map[string]interface{}{
+				"service":    p.cfg.Name,
+				"pid":        pid,
+				"check":      pr.kind,
+				"durationMs": elapsed.Milliseconds(),
+			},
+		})
+	}
+
+	if liveness != nil {
+		p.runLiveness(ctx, cmd, liveness)
+	}
+}
+
+// runLiveness polls the liveness probe until the process exits, killing it
+// once consecutive failures reach the probe's retry threshold.
+func (p *Process) runLiveness(ctx context.Context, cmd *exec.Cmd, pr *probe) {
+	pr.watch(ctx, func(failures int, err error) {
+		p.markUnhealthy(cmd, "liveness", fmt.Errorf("%d consecutive failures, last error: %v", failures, err))
+	})
+}
+
+// markUnhealthy publishes service.unhealthy and kills cmd's process group.
+// The kill is not a requested stop, so waitForExit treats it as a crash and
+// the manager's restart policy decides what happens next.
+func (p *Process) markUnhealthy(cmd *exec.Cmd, check string, err error) {
+	p.mu.RLock()
+	current := p.cmd == cmd && !p.stopRequested
+	p.mu.RUnlock()
+	if !current {
+		return
+	}
+
+	p.logs.Write(fmt.Sprintf("[trellis] %s check failed: %v; killing process", check, err))
+	if p.bus != nil {
+		p.bus.Publish(context.Background(), events.Event{
+			Type: events.EventServiceUnhealthy,
+			Payload: map[string]interface{}{
+				"service": p.cfg.Name,
+				"check":   check,
+				"error":   err.Error(),
+			},
+		})
+	}
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
 }
 
 // Logs returns the last n lines of output.
@@ -272,6 +470,16 @@ func (p *Process) ParsedLogs(n int) []*logs.LogEntry {
 	return p.logs.Entries(n)
 }
 
+// LogsBefore returns up to n log lines written before sequence number seq.
+func (p *Process) LogsBefore(seq int64, n int) []LogLine {
+	return p.logs.Before(seq, n)
+}
+
+// LogsRange returns up to n log lines written between start and end.
+func (p *Process) LogsRange(start, end time.Time, n int) []LogLine {
+	return p.logs.Range(start, end, n)
+}
+
 // HasParser returns true if a log parser is configured.
 func (p *Process) HasParser() bool {
 	return p.logs.HasParser()
@@ -303,6 +511,16 @@ func (p *Process) CloseLogSubscribers() {
 	p.logs.CloseAllSubscribers()
 }
 
+// EnableLogPersistence keeps the process's log buffer on disk in dir.
+func (p *Process) EnableLogPersistence(dir string) error {
+	return p.logs.EnablePersistence(dir)
+}
+
+// CloseLogStore releases the on-disk log store, if any.
+func (p *Process) CloseLogStore() {
+	p.logs.ClosePersistence()
+}
+
 // LogSequence returns the current log sequence number.
 func (p *Process) LogSequence() int64 {
 	return p.logs.Sequence()
@@ -330,7 +548,7 @@ func (p *Process) captureOutput(r io.Reader) {
 			if len(line) > maxLineLen {
 				line = line[:maxLineLen] + "... [truncated]"
 			}
-			p.logs.Write(line)
+			p.logs.Write(p.redact(line))
 		}
 		if err != nil {
 			if err != io.EOF {
@@ -341,6 +559,14 @@ func (p *Process) captureOutput(r io.Reader) {
 	}
 }
 
+// redact removes secret values from a line of output.
+func (p *Process) redact(line string) string {
+	p.mu.RLock()
+	m := p.secrets
+	p.mu.RUnlock()
+	return m.Redact(line)
+}
+
 func (p *Process) waitForExit(readersDone *sync.WaitGroup) {
 	cmd := p.cmd
 	// Drain stdout/stderr before Wait: Wait closes the pipes, which would
@@ -5,6 +5,8 @@ package service
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wingedpig/trellis/internal/config"
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 func TestProcess_Start(t *testing.T) {
@@ -256,6 +259,45 @@ func TestProcess_Environment(t *testing.T) {
 	assert.True(t, found, "Environment variable not found in output")
 }
 
+func TestProcess_EnvFileAndSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("FROM_FILE=file_value\nAPI_KEY={{ secret \"api_key\" }}\n"), 0644))
+
+	sm := secrets.NewManager(secrets.NewStore(t.TempDir(), "test"), nil)
+	require.NoError(t, sm.Set("api_key", "sk_live_abcdef"))
+
+	cfg := config.ServiceConfig{
+		Name:    "test-service",
+		Command: []string{"sh", "-c", "echo $FROM_FILE; echo key=$API_KEY; echo $TOKEN"},
+		WorkDir: dir,
+		EnvFile: ".env",
+		Env:     map[string]string{"TOKEN": `{{ secret "api_key" }}`},
+	}
+	proc := NewProcess(cfg, nil)
+	proc.SetSecrets(sm)
+	require.NoError(t, proc.Start(context.Background()))
+	require.Eventually(t, func() bool {
+		return proc.Status().State == StatusStopped
+	}, 2*time.Second, 20*time.Millisecond)
+
+	lines := proc.Logs(10)
+	assert.Contains(t, lines, "file_value")
+	assert.Contains(t, lines, "key=[secret:api_key]", "secret values are redacted from output")
+	assert.Contains(t, lines, "[secret:api_key]")
+	for _, line := range lines {
+		assert.NotContains(t, line, "sk_live_abcdef")
+	}
+
+	// An unresolvable secret fails the start and shows in the status
+	cfg.Env = map[string]string{"TOKEN": `{{ secret "missing" }}`}
+	proc = NewProcess(cfg, nil)
+	proc.SetSecrets(sm)
+	err := proc.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+	assert.Equal(t, err.Error(), proc.Status().Error)
+}
+
 func TestProcess_WorkDir(t *testing.T) {
 	cfg := config.ServiceConfig{
 		Name:    "test-service",
@@ -107,6 +107,8 @@ type Manager interface {
 	LogSize(name string) (int, error)                            // Get number of lines in log buffer
 	ParsedLogs(name string, lines int) ([]*logs.LogEntry, error) // Get parsed log entries
 	HasParser(name string) bool                                  // Check if service has log parser
+	LogsBefore(name string, seq int64, lines int) ([]LogLine, error)           // Lines before a sequence, read from disk once the buffer runs out
+	LogsRange(name string, start, end time.Time, lines int) ([]LogLine, error) // Lines written in a time range, read from disk if persisted
 	ClearLogs(name string) error
 	SubscribeLogs(name string) (chan LogLine, error)   // Subscribe to live log updates
 	UnsubscribeLogs(name string, ch chan LogLine)      // Unsubscribe from log updates
@@ -160,6 +160,10 @@ func (m *Manager) UpdateConfigs(cfg *config.Config) {
 // Execute starts a distributed trace search across all log viewers in a group.
 // The search runs asynchronously and updates the report when complete.
 func (m *Manager) Execute(ctx context.Context, req TraceRequest) (*ExecuteResult, error) {
+	if _, err := logs.ParseFilter(req.Filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
 	// Find the trace group and get storage reference while holding lock
 	m.mu.RLock()
 	group, err := m.findGroupLocked(req.Group)
@@ -276,7 +280,11 @@ func (m *Manager) executeAsync(ctx context.Context, cancel context.CancelFunc, r
 	})
 
 	// Execute Pass 1: search for the trace pattern
-	results, err := m.searchParallel(ctx, viewerNames, req.TraceID, req)
+	var pass1IDs []string
+	if regexEscape(req.TraceID) == req.TraceID {
+		pass1IDs = []string{req.TraceID}
+	}
+	results, err := m.searchParallel(ctx, viewerNames, req.TraceID, pass1IDs, req)
 	if err != nil {
 		m.updateReportFailed(reportName, req, createdAt, err)
 		return
@@ -335,7 +343,7 @@ func (m *Manager) executeAsync(ctx context.Context, cancel context.CancelFunc, r
 				})
 
 				// Execute Pass 2: search for this batch of IDs
-				pass2Results, err := m.searchParallel(ctx, viewerNames, idPattern, req)
+				pass2Results, err := m.searchParallel(ctx, viewerNames, idPattern, batchIDs, req)
 				if err != nil {
 					if ctx.Err() != nil {
 						m.updateReportFailed(reportName, req, createdAt, fmt.Errorf("trace cancelled: %w", ctx.Err()))
@@ -451,12 +459,24 @@ func (m *Manager) updateReportFailed(reportName string, req TraceRequest, create
 }
 
 // searchParallel searches all log viewers in parallel for the given pattern.
-func (m *Manager) searchParallel(ctx context.Context, viewerNames []string, pattern string, req TraceRequest) (map[string][]logs.LogEntry, error) {
+// Viewers whose source indexes trace IDs are first asked for the exact ids
+// (nil when pattern isn't a literal ID) and only grepped if that finds
+// nothing.
+func (m *Manager) searchParallel(ctx context.Context, viewerNames []string, pattern string, ids []string, req TraceRequest) (map[string][]logs.LogEntry, error) {
 	g, ctx := errgroup.WithContext(ctx)
 
 	var mu sync.Mutex
 	results := make(map[string][]logs.LogEntry)
 
+	// Validated by Execute
+	filter, err := logs.ParseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.IsEmpty() {
+		filter = nil
+	}
+
 	for _, viewerName := range viewerNames {
 		viewerName := viewerName // capture loop variable
 		g.Go(func() error {
@@ -465,13 +485,23 @@ func (m *Manager) searchParallel(ctx context.Context, viewerNames []string, patt
 				return fmt.Errorf("log viewer %s: %w", viewerName, err)
 			}
 
+			if len(ids) > 0 {
+				if entries, ok := viewer.LookupTrace(ids, req.Start, req.End, filter); ok && len(entries) > 0 {
+					log.Printf("Trace: %s trace index returned %d entries", viewerName, len(entries))
+					mu.Lock()
+					results[viewerName] = entries
+					mu.Unlock()
+					return nil
+				}
+			}
+
 			log.Printf("Trace: searching %s for %q", viewerName, pattern)
 			entries, err := viewer.GetHistoricalEntries(
 				ctx,
 				req.Start,
 				req.End,
-				nil, // no filter
-				0,   // no limit
+				filter,
+				0, // no limit
 				pattern,
 				0, // no context before
 				0, // no context after
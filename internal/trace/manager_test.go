@@ -526,3 +526,21 @@ func TestManager_LogViewerConfigLoaded(t *testing.T) {
 	assert.Equal(t, "correlation_id", mgr.logViewerConfig["db-logs"].Parser.ID)
 	assert.Equal(t, "", mgr.logViewerConfig["nginx-logs"].Parser.ID)
 }
+
+func TestManager_ExecuteRejectsInvalidFilter(t *testing.T) {
+	cfg := &config.Config{
+		Trace:       config.TraceConfig{ReportsDir: t.TempDir()},
+		TraceGroups: []config.TraceGroupConfig{{Name: "web", LogViewers: []string{"nginx"}}},
+	}
+	mgr, err := NewManager(nil, cfg, nil)
+	require.NoError(t, err)
+
+	_, err = mgr.Execute(context.Background(), TraceRequest{
+		TraceID: "abc",
+		Group:   "web",
+		Filter:  "(level:error OR",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid filter")
+	assert.Contains(t, err.Error(), "column")
+}
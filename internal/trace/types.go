@@ -17,6 +17,7 @@ type TraceRequest struct {
 	Start      time.Time `json:"start"`        // Start of time range
 	End        time.Time `json:"end"`          // End of time range
 	ExpandByID bool      `json:"expand_by_id"` // Whether to perform ID expansion (two-pass search)
+	Filter     string    `json:"filter"`       // Log filter expression applied to matches (optional)
 }
 
 // TraceReport represents the results of a trace execution.
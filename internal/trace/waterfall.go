@@ -0,0 +1,133 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"sort"
+	"time"
+)
+
+// WaterfallSpan is one row of a span waterfall. Offset and width are
+// percentages of the whole trace so the view can position bars directly.
+type WaterfallSpan struct {
+	SpanID     string
+	ParentID   string
+	Name       string
+	Service    string
+	Kind       string
+	Status     string
+	Source     string
+	Start      time.Time
+	Duration   time.Duration
+	Depth      int
+	OffsetPct  float64
+	WidthPct   float64
+	HasProblem bool // span status is error
+}
+
+// BuildWaterfall collects the OTLP span entries (fields.type == "span") in
+// a trace and orders them depth-first, children under their parent and
+// siblings by start time. Spans whose parent wasn't captured are treated as
+// roots. Returns nil when the trace has no spans.
+func BuildWaterfall(entries []TraceEntry) []WaterfallSpan {
+	var spans []WaterfallSpan
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if fieldString(entry.Fields, "type") != "span" {
+			continue
+		}
+		id := fieldString(entry.Fields, "span_id")
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		span := WaterfallSpan{
+			SpanID:   id,
+			ParentID: fieldString(entry.Fields, "parent_span_id"),
+			Name:     entry.Message,
+			Service:  fieldString(entry.Fields, "service"),
+			Kind:     fieldString(entry.Fields, "span_kind"),
+			Status:   fieldString(entry.Fields, "status"),
+			Source:   entry.Source,
+			Start:    entry.Timestamp,
+		}
+		if ms, ok := entry.Fields["duration_ms"].(float64); ok && ms > 0 {
+			span.Duration = time.Duration(ms * float64(time.Millisecond))
+		}
+		span.HasProblem = span.Status == "error"
+		spans = append(spans, span)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	children := make(map[string][]int)
+	var roots []int
+	for i, span := range spans {
+		if span.ParentID != "" && seen[span.ParentID] && span.ParentID != span.SpanID {
+			children[span.ParentID] = append(children[span.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	byStart := func(idx []int) {
+		sort.SliceStable(idx, func(a, b int) bool { return spans[idx[a]].Start.Before(spans[idx[b]].Start) })
+	}
+
+	traceStart := spans[0].Start
+	traceEnd := spans[0].Start.Add(spans[0].Duration)
+	for _, span := range spans[1:] {
+		if span.Start.Before(traceStart) {
+			traceStart = span.Start
+		}
+		if end := span.Start.Add(span.Duration); end.After(traceEnd) {
+			traceEnd = end
+		}
+	}
+	total := traceEnd.Sub(traceStart)
+
+	result := make([]WaterfallSpan, 0, len(spans))
+	visited := make(map[int]bool)
+	var walk func(idx []int, depth int)
+	walk = func(idx []int, depth int) {
+		byStart(idx)
+		for _, i := range idx {
+			// Guard against parent cycles in malformed data
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+
+			span := spans[i]
+			span.Depth = depth
+			if total > 0 {
+				span.OffsetPct = float64(span.Start.Sub(traceStart)) / float64(total) * 100
+				span.WidthPct = float64(span.Duration) / float64(total) * 100
+			} else {
+				span.WidthPct = 100
+			}
+			result = append(result, span)
+			walk(children[span.SpanID], depth+1)
+		}
+	}
+	walk(roots, 0)
+
+	// Spans caught in a cycle never reach a root; list them at the top level
+	var orphans []int
+	for i := range spans {
+		if !visited[i] {
+			orphans = append(orphans, i)
+		}
+	}
+	walk(orphans, 0)
+
+	return result
+}
+
+// fieldString returns a string field value, or "" if missing or not a string.
+func fieldString(fields map[string]any, key string) string {
+	s, _ := fields[key].(string)
+	return s
+}
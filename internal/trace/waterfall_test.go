@@ -0,0 +1,71 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spanEntry(start time.Time, id, parent, name string, ms float64, status string) TraceEntry {
+	fields := map[string]any{
+		"type":        "span",
+		"span_id":     id,
+		"duration_ms": ms,
+		"status":      status,
+		"service":     "web",
+	}
+	if parent != "" {
+		fields["parent_span_id"] = parent
+	}
+	return TraceEntry{Timestamp: start, Source: "otel", Message: name, Fields: fields}
+}
+
+func TestBuildWaterfall(t *testing.T) {
+	t0 := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	entries := []TraceEntry{
+		{Timestamp: t0, Message: "a log line", Fields: map[string]any{"type": "log", "span_id": "c"}},
+		spanEntry(t0.Add(60*time.Millisecond), "c", "a", "db.query", 20, "unset"),
+		spanEntry(t0.Add(10*time.Millisecond), "b", "a", "auth", 30, "ok"),
+		spanEntry(t0, "a", "", "GET /checkout", 100, "error"),
+		spanEntry(t0.Add(20*time.Millisecond), "d", "b", "cache.get", 5, "unset"),
+		spanEntry(t0.Add(50*time.Millisecond), "e", "missing", "orphan", 10, "unset"),
+	}
+
+	spans := BuildWaterfall(entries)
+	require.Len(t, spans, 5)
+
+	var order []string
+	var depths []int
+	for _, s := range spans {
+		order = append(order, s.SpanID)
+		depths = append(depths, s.Depth)
+	}
+	assert.Equal(t, []string{"a", "b", "d", "c", "e"}, order)
+	assert.Equal(t, []int{0, 1, 2, 1, 0}, depths)
+
+	assert.Equal(t, "GET /checkout", spans[0].Name)
+	assert.True(t, spans[0].HasProblem)
+	assert.Equal(t, 100*time.Millisecond, spans[0].Duration)
+	assert.InDelta(t, 0, spans[0].OffsetPct, 0.001)
+	assert.InDelta(t, 100, spans[0].WidthPct, 0.001)
+	assert.InDelta(t, 60, spans[3].OffsetPct, 0.001)
+	assert.InDelta(t, 20, spans[3].WidthPct, 0.001)
+}
+
+func TestBuildWaterfallNoSpans(t *testing.T) {
+	assert.Nil(t, BuildWaterfall([]TraceEntry{{Message: "plain", Fields: map[string]any{"level": "info"}}}))
+}
+
+func TestBuildWaterfallCycle(t *testing.T) {
+	t0 := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	spans := BuildWaterfall([]TraceEntry{
+		spanEntry(t0, "a", "b", "one", 1, "unset"),
+		spanEntry(t0, "b", "a", "two", 1, "unset"),
+	})
+	assert.Len(t, spans, 2)
+}
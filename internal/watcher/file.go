@@ -0,0 +1,152 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher calls a function when a file changes. Like BinaryWatcher it
+// watches the parent directory, so editors that save by writing a temp file
+// and renaming it over the original keep being seen, and files that don't
+// exist yet are seen when they are created. Bursts of events from one save,
+// or from saves to several of the watched files, are debounced into a
+// single call.
+type FileWatcher struct {
+	path      string
+	onChange  func()
+	watcher   *fsnotify.Watcher
+	mu        sync.Mutex
+	files     map[string]bool // Watched files, including path
+	dirs      map[string]bool // Directories added to the fsnotify watcher
+	debouncer *Debouncer
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewFileWatcher starts watching path, calling onChange after it is written
+// or replaced and no further changes arrive for the debounce duration.
+func NewFileWatcher(path string, debounce time.Duration, onChange func()) (*FileWatcher, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+	if err := fsWatcher.Add(filepath.Dir(absPath)); err != nil {
+		fsWatcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", absPath, err)
+	}
+
+	w := &FileWatcher{
+		path:      absPath,
+		onChange:  onChange,
+		watcher:   fsWatcher,
+		files:     map[string]bool{absPath: true},
+		dirs:      map[string]bool{filepath.Dir(absPath): true},
+		debouncer: NewDebouncer(debounce),
+		closeCh:   make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go w.processEvents()
+
+	return w, nil
+}
+
+// Path returns the absolute path being watched.
+func (w *FileWatcher) Path() string {
+	return w.path
+}
+
+// SetPaths replaces the files watched in addition to the one the watcher was
+// created with. Files in directories that don't exist are skipped.
+func (w *FileWatcher) SetPaths(paths []string) error {
+	files := map[string]bool{w.path: true}
+	dirs := map[string]bool{filepath.Dir(w.path): true}
+	var firstErr error
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			abs = p
+		}
+		dir := filepath.Dir(abs)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		files[abs] = true
+		dirs[dir] = true
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			delete(dirs, dir)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to watch %s: %w", dir, err)
+			}
+		}
+	}
+	for dir := range w.dirs {
+		if !dirs[dir] {
+			w.watcher.Remove(dir)
+		}
+	}
+	w.files, w.dirs = files, dirs
+	return firstErr
+}
+
+// Close stops the watcher. A pending change is dropped.
+func (w *FileWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+		w.debouncer.Stop()
+		w.watcher.Close()
+		w.wg.Wait()
+	})
+	return nil
+}
+
+func (w *FileWatcher) processEvents() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.closeCh:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// Writes and creates (including a rename onto the path) only;
+			// a remove is usually followed by the create of a replacement
+			w.mu.Lock()
+			watched := w.files[event.Name]
+			w.mu.Unlock()
+			if watched && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create)) {
+				w.debouncer.Debounce(w.path, w.onChange)
+			}
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
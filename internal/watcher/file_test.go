@@ -0,0 +1,111 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "trellis.hjson")
+	tempFile := filepath.Join(tmpDir, "trellis.hjson.swp")
+	os.WriteFile(configFile, []byte("v1"), 0644)
+
+	changeCh := make(chan struct{}, 4)
+	w, err := NewFileWatcher(configFile, 50*time.Millisecond, func() {
+		changeCh <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer w.Close()
+
+	waitChange := func(what string) {
+		t.Helper()
+		select {
+		case <-changeCh:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for change after %s", what)
+		}
+	}
+
+	// Other files in the directory are ignored
+	os.WriteFile(filepath.Join(tmpDir, "other.hjson"), []byte("x"), 0644)
+
+	// Several writes in a burst yield one call
+	os.WriteFile(configFile, []byte("v2"), 0644)
+	os.WriteFile(configFile, []byte("v3"), 0644)
+	waitChange("write")
+
+	// Editors saving via temp file + rename are seen, repeatedly
+	for _, content := range []string{"v4", "v5"} {
+		os.WriteFile(tempFile, []byte(content), 0644)
+		os.Rename(tempFile, configFile)
+		waitChange("rename")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	select {
+	case <-changeCh:
+		t.Error("unexpected extra change")
+	default:
+	}
+}
+
+func TestFileWatcher_SetPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tmpDir := t.TempDir()
+	otherDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "trellis.hjson")
+	localFile := filepath.Join(tmpDir, "trellis.local.hjson")
+	overlayFile := filepath.Join(otherDir, "trellis.worktree.hjson")
+	os.WriteFile(configFile, []byte("v1"), 0644)
+
+	changeCh := make(chan struct{}, 4)
+	w, err := NewFileWatcher(configFile, 50*time.Millisecond, func() {
+		changeCh <- struct{}{}
+	})
+	require.NoError(t, err)
+	defer w.Close()
+	require.NoError(t, w.SetPaths([]string{localFile, overlayFile, filepath.Join(tmpDir, "missing", "x.hjson")}))
+
+	waitChange := func(what string) {
+		t.Helper()
+		select {
+		case <-changeCh:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for change after %s", what)
+		}
+	}
+
+	// Files that don't exist yet are seen when created
+	os.WriteFile(localFile, []byte("x"), 0644)
+	waitChange("create")
+	os.WriteFile(overlayFile, []byte("x"), 0644)
+	waitChange("write in another directory")
+
+	// Dropped files are no longer watched; the config file always is
+	require.NoError(t, w.SetPaths(nil))
+	os.WriteFile(overlayFile, []byte("y"), 0644)
+	os.WriteFile(localFile, []byte("y"), 0644)
+	time.Sleep(150 * time.Millisecond)
+	select {
+	case <-changeCh:
+		t.Error("change for a dropped file")
+	default:
+	}
+	os.WriteFile(configFile, []byte("v2"), 0644)
+	waitChange("config write")
+}
@@ -0,0 +1,38 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/app/app.go", true},
+		{"*.go", "main.go.orig", false},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "tools/go.mod", true},
+		{"internal/*.go", "internal/a.go", true},
+		{"internal/*.go", "internal/app/a.go", false},
+		{"internal/**/*.go", "internal/a.go", true},
+		{"internal/**/*.go", "internal/app/sub/a.go", true},
+		{"internal/**", "internal", true},
+		{"internal/**", "internal/app/a.go", true},
+		{"./cmd/**", "cmd/trellis/main.go", true},
+		{"**/testdata/**", "pkg/testdata/x.json", true},
+		{"**/testdata/**", "pkg/data/x.json", false},
+		{"views/*.qtpl", "views/base.qtpl", true},
+		{"views/*.qtpl", "other/views/base.qtpl", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchGlob(tt.pattern, tt.name), "%s ~ %s", tt.pattern, tt.name)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TreeWatcher calls a function with the files changed under a directory
+// tree. fsnotify doesn't watch recursively, so every directory of the tree
+// is watched, including directories created later. Bursts of changes are
+// debounced into a single call listing every file changed, created, removed
+// or renamed, as slash-separated paths relative to the root.
+type TreeWatcher struct {
+	root      string
+	ignore    func(rel string, dir bool) bool
+	onChange  func(files []string)
+	watcher   *fsnotify.Watcher
+	mu        sync.Mutex
+	changed   map[string]bool // Files changed since the last call
+	debouncer *Debouncer
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewTreeWatcher starts watching the tree under root. ignore is called with
+// the path of each directory and changed file relative to root; ignored
+// directories are not watched and ignored files don't count as changes.
+func NewTreeWatcher(root string, debounce time.Duration, ignore func(rel string, dir bool) bool, onChange func(files []string)) (*TreeWatcher, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fsnotify watcher: %w", err)
+	}
+
+	w := &TreeWatcher{
+		root:      absRoot,
+		ignore:    ignore,
+		onChange:  onChange,
+		watcher:   fsWatcher,
+		changed:   make(map[string]bool),
+		debouncer: NewDebouncer(debounce),
+		closeCh:   make(chan struct{}),
+	}
+	if err := w.addTree(absRoot); err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.processEvents()
+
+	return w, nil
+}
+
+// Root returns the absolute path of the watched tree.
+func (w *TreeWatcher) Root() string {
+	return w.root
+}
+
+// Close stops the watcher. Pending changes are dropped.
+func (w *TreeWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+		w.debouncer.Stop()
+		w.watcher.Close()
+		w.wg.Wait()
+	})
+	return nil
+}
+
+// addTree watches dir and the directories under it that aren't ignored.
+func (w *TreeWatcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return fmt.Errorf("failed to watch %s: %w", path, err)
+			}
+			return nil // Vanished or unreadable; skip it
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && w.ignore != nil && w.ignore(w.rel(path), true) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// rel returns path relative to the root, slash-separated.
+func (w *TreeWatcher) rel(path string) string {
+	rel, err := filepath.Rel(w.root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (w *TreeWatcher) processEvents() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.closeCh:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			rel := w.rel(event.Name)
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if w.ignore == nil || !w.ignore(rel, true) {
+						w.addTree(event.Name)
+					}
+					continue
+				}
+			}
+			if w.ignore != nil && w.ignore(rel, false) {
+				continue
+			}
+			w.mu.Lock()
+			w.changed[rel] = true
+			w.mu.Unlock()
+			w.debouncer.Debounce(w.root, w.flush)
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// flush calls onChange with the files changed since the last call.
+func (w *TreeWatcher) flush() {
+	w.mu.Lock()
+	files := make([]string, 0, len(w.changed))
+	for f := range w.changed {
+		files = append(files, f)
+	}
+	w.changed = make(map[string]bool)
+	w.mu.Unlock()
+
+	if len(files) == 0 {
+		return
+	}
+	sort.Strings(files)
+	w.onChange(files)
+}
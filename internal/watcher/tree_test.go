@@ -0,0 +1,72 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeWatcher_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "pkg"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "lib"), 0755))
+
+	ignore := func(rel string, dir bool) bool {
+		if dir {
+			return rel == "vendor"
+		}
+		return !MatchGlob("*.go", rel)
+	}
+	changeCh := make(chan []string, 4)
+	w, err := NewTreeWatcher(root, 50*time.Millisecond, ignore, func(files []string) {
+		changeCh <- files
+	})
+	require.NoError(t, err)
+	defer w.Close()
+
+	waitChange := func(what string) []string {
+		t.Helper()
+		select {
+		case files := <-changeCh:
+			return files
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for change after %s", what)
+			return nil
+		}
+	}
+
+	// A burst of changes across directories yields one call
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte("package pkg"), 0644)
+	os.WriteFile(filepath.Join(root, "README.md"), []byte("ignored"), 0644)
+	os.WriteFile(filepath.Join(root, "vendor", "lib", "lib.go"), []byte("ignored"), 0644)
+	assert.Equal(t, []string{"main.go", "pkg/a.go"}, waitChange("writes"))
+
+	// Directories created later are watched too
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "cmd", "tool"), 0755))
+	time.Sleep(100 * time.Millisecond)
+	os.WriteFile(filepath.Join(root, "cmd", "tool", "main.go"), []byte("package main"), 0644)
+	assert.Equal(t, []string{"cmd/tool/main.go"}, waitChange("write in new directory"))
+
+	// Removals count as changes
+	os.Remove(filepath.Join(root, "pkg", "a.go"))
+	assert.Equal(t, []string{"pkg/a.go"}, waitChange("remove"))
+
+	time.Sleep(150 * time.Millisecond)
+	select {
+	case files := <-changeCh:
+		t.Errorf("unexpected change: %v", files)
+	default:
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Artifact is a file kept from a run. Artifacts are stored in the history
+// directory under <run-id>.artifacts, at their path in the working
+// directory.
+type Artifact struct {
+	Path string // Slash-separated, relative to the working directory
+	Size int64
+	Step string // Path of the step that produced it, e.g. "build" or "ci/test"; empty for the workflow's own
+}
+
+// CollectArtifacts keeps the files in dir matching patterns that were
+// written since the run or step started, so files left by earlier runs
+// aren't taken for this one's. A pattern matching a directory keeps the
+// files under it. Patterns are globs relative to dir; matches outside it
+// are ignored.
+func (h *History) CollectArtifacts(runID, dir string, patterns []string, since time.Time, step string) ([]Artifact, error) {
+	if err := validRunID(runID); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || !validArtifactPath(filepath.ToSlash(rel)) || seen[rel] {
+			return
+		}
+		seen[rel] = true
+		files = append(files, rel)
+	}
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, match := range matches {
+			rel, err := filepath.Rel(dir, match)
+			if err != nil || !validArtifactPath(filepath.ToSlash(rel)) {
+				continue
+			}
+			info, err := os.Stat(match)
+			if err != nil {
+				continue
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			filepath.WalkDir(match, func(file string, d fs.DirEntry, err error) error {
+				if err == nil && d.Type().IsRegular() {
+					add(file)
+				}
+				return nil
+			})
+		}
+	}
+	sort.Strings(files)
+
+	var artifacts []Artifact
+	for _, rel := range files {
+		src := filepath.Join(dir, rel)
+		info, err := os.Stat(src)
+		// Modification times may be truncated to the second
+		if err != nil || info.ModTime().Before(since.Truncate(time.Second)) {
+			continue
+		}
+		dst := filepath.Join(h.artifactsDir(runID), rel)
+		size, err := copyFile(src, dst)
+		if err != nil {
+			return artifacts, fmt.Errorf("failed to keep artifact %s: %w", filepath.ToSlash(rel), err)
+		}
+		artifacts = append(artifacts, Artifact{Path: filepath.ToSlash(rel), Size: size, Step: step})
+	}
+	return artifacts, nil
+}
+
+// RestoreArtifacts copies the artifacts of run fromID that step, or the
+// steps under it, produced into dir and keeps them with run toID, as
+// though that run had produced them. An empty step restores every
+// artifact.
+func (h *History) RestoreArtifacts(fromID, toID, step, dir string, artifacts []Artifact) ([]Artifact, error) {
+	if err := validRunID(fromID); err != nil {
+		return nil, err
+	}
+	if err := validRunID(toID); err != nil {
+		return nil, err
+	}
+
+	var restored []Artifact
+	for _, a := range artifacts {
+		if step != "" && a.Step != step && !strings.HasPrefix(a.Step, step+"/") {
+			continue
+		}
+		if !validArtifactPath(a.Path) {
+			continue
+		}
+		src := filepath.Join(h.artifactsDir(fromID), filepath.FromSlash(a.Path))
+		if _, err := copyFile(src, filepath.Join(dir, filepath.FromSlash(a.Path))); err != nil {
+			return restored, fmt.Errorf("failed to restore artifact %s: %w", a.Path, err)
+		}
+		if _, err := copyFile(src, filepath.Join(h.artifactsDir(toID), filepath.FromSlash(a.Path))); err != nil {
+			return restored, fmt.Errorf("failed to keep artifact %s: %w", a.Path, err)
+		}
+		restored = append(restored, a)
+	}
+	return restored, nil
+}
+
+// ArtifactFile returns the file an artifact of a run is stored in.
+func (h *History) ArtifactFile(runID, artifactPath string) (string, error) {
+	if err := validRunID(runID); err != nil {
+		return "", err
+	}
+	if !validArtifactPath(artifactPath) {
+		return "", fmt.Errorf("invalid artifact path: %q", artifactPath)
+	}
+	file := filepath.Join(h.artifactsDir(runID), filepath.FromSlash(artifactPath))
+	info, err := os.Stat(file)
+	if err != nil || info.IsDir() {
+		return "", fmt.Errorf("artifact %q of run %q not found", artifactPath, runID)
+	}
+	return file, nil
+}
+
+func (h *History) artifactsDir(runID string) string {
+	return filepath.Join(h.dir, runID+".artifacts")
+}
+
+// addArtifacts returns artifacts with added, which replace any artifact at
+// the same path.
+func addArtifacts(artifacts, added []Artifact) []Artifact {
+	for _, a := range added {
+		replaced := false
+		for i := range artifacts {
+			if artifacts[i].Path == a.Path {
+				artifacts[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			artifacts = append(artifacts, a)
+		}
+	}
+	return artifacts
+}
+
+// validArtifactPath guards an artifact path joined to a directory: it must
+// be relative, slash-separated and stay inside the directory.
+func validArtifactPath(p string) bool {
+	return p != "" && p != "." && p == path.Clean(p) && !path.IsAbs(p) && p != ".." &&
+		!strings.HasPrefix(p, "../") && !strings.Contains(p, `\`)
+}
+
+// copyFile copies src to dst with its permissions, so binaries stay
+// executable, creating dst's directory. It returns the number of bytes
+// copied.
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err == nil {
+		err = out.Chmod(info.Mode().Perm())
+	}
+	if err != nil {
+		out.Close()
+		return n, err
+	}
+	return n, out.Close()
+}
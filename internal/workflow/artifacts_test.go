@@ -0,0 +1,160 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a file under dir, creating its directory.
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestHistory_CollectArtifacts(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	work := t.TempDir()
+
+	writeFile(t, work, "stale.out", "from an earlier run")
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(work, "stale.out"), old, old))
+	start := time.Now()
+	writeFile(t, work, "coverage.out", "mode: set\n")
+	writeFile(t, work, "dist/app.js", "app")
+	writeFile(t, work, "dist/css/app.css", "css")
+	writeFile(t, work, "main.go", "package main")
+
+	artifacts, err := h.CollectArtifacts("build-1", work, []string{"*.out", "dist", "../*"}, start, "")
+	require.NoError(t, err)
+	assert.Equal(t, []Artifact{
+		{Path: "coverage.out", Size: 10},
+		{Path: "dist/app.js", Size: 3},
+		{Path: "dist/css/app.css", Size: 3},
+	}, artifacts)
+
+	file, err := h.ArtifactFile("build-1", "dist/css/app.css")
+	require.NoError(t, err)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "css", string(data))
+
+	_, err = h.ArtifactFile("build-1", "stale.out")
+	assert.Error(t, err)
+	_, err = h.ArtifactFile("build-1", "../build-1.json")
+	assert.Error(t, err)
+	_, err = h.ArtifactFile("../x", "coverage.out")
+	assert.Error(t, err)
+}
+
+func TestHistory_RestoreArtifacts(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	work := t.TempDir()
+	start := time.Now()
+	writeFile(t, work, "bin/app", "binary")
+	require.NoError(t, os.Chmod(filepath.Join(work, "bin/app"), 0755))
+	writeFile(t, work, "report.xml", "<testsuite/>")
+
+	built, err := h.CollectArtifacts("ci-1", work, []string{"bin/*"}, start, "build")
+	require.NoError(t, err)
+	tested, err := h.CollectArtifacts("ci-1", work, []string{"report.xml"}, start, "test/unit")
+	require.NoError(t, err)
+	artifacts := append(built, tested...)
+
+	// Only the step's artifacts come back, executable as they were
+	restoreTo := t.TempDir()
+	restored, err := h.RestoreArtifacts("ci-1", "ci-2", "build", restoreTo, artifacts)
+	require.NoError(t, err)
+	assert.Equal(t, built, restored)
+	info, err := os.Stat(filepath.Join(restoreTo, "bin/app"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	assert.NoFileExists(t, filepath.Join(restoreTo, "report.xml"))
+
+	// Steps nested under a step come with it, and the restoring run keeps them
+	restored, err = h.RestoreArtifacts("ci-1", "ci-2", "test", restoreTo, artifacts)
+	require.NoError(t, err)
+	assert.Equal(t, tested, restored)
+	_, err = h.ArtifactFile("ci-2", "report.xml")
+	assert.NoError(t, err)
+}
+
+func TestHistory_ArtifactsPruned(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, 0, 1)
+	require.NoError(t, err)
+	work := t.TempDir()
+
+	start := time.Now().Add(-time.Minute)
+	writeFile(t, work, "out.txt", "1")
+	_, err = h.CollectArtifacts("test-1", work, []string{"out.txt"}, start, "")
+	require.NoError(t, err)
+	require.NoError(t, h.Record(testRun(1, start, "abc")))
+	require.NoError(t, h.Record(testRun(2, start.Add(time.Second), "abc")))
+	assert.NoDirExists(t, filepath.Join(dir, "test-1.artifacts"))
+
+	// Artifacts of a run that was never recorded go when the history is reopened
+	_, err = h.CollectArtifacts("test-3", work, []string{"out.txt"}, start, "")
+	require.NoError(t, err)
+	require.DirExists(t, filepath.Join(dir, "test-3.artifacts"))
+	_, err = NewHistory(dir, 0, 1)
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(dir, "test-3.artifacts"))
+}
+
+func TestHistory_Cached(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	start := time.Now().Add(-time.Hour)
+
+	older := testRun(1, start, "abc")
+	older.CacheKey = "k1"
+	older.Steps = []StepStatus{
+		{ID: "build", State: StateSuccess, CacheKey: "b1", Output: "built 1"},
+		{ID: "ci", State: StateSuccess, Steps: []StepStatus{
+			{ID: "lint", State: StateSuccess, CacheKey: "l1", Output: "linted"},
+		}},
+	}
+	failed := testRun(2, start.Add(time.Minute), "abc", "pkg.TestA")
+	failed.CacheKey = "k1"
+	failed.Steps = []StepStatus{{ID: "build", State: StateFailed, CacheKey: "b1"}}
+	newer := testRun(3, start.Add(2*time.Minute), "abc")
+	newer.CacheKey = "k2"
+	newer.Steps = []StepStatus{{ID: "build", State: StateSuccess, CacheKey: "b1", Output: "built 3"}}
+	for _, run := range []*WorkflowStatus{older, failed, newer} {
+		require.NoError(t, h.Record(run))
+	}
+
+	// Failed runs and steps don't count
+	run, _ := h.Cached("test", "", "k1")
+	require.NotNil(t, run)
+	assert.Equal(t, "test-1", run.ID)
+	assert.Equal(t, "output of run 1\n", run.Output)
+
+	run, step := h.Cached("test", "build", "b1")
+	require.NotNil(t, step)
+	assert.Equal(t, "test-3", run.ID)
+	assert.Equal(t, "built 3", step.Output)
+
+	run, step = h.Cached("test", "ci/lint", "l1")
+	require.NotNil(t, step)
+	assert.Equal(t, "test-1", run.ID)
+	assert.Equal(t, "linted", step.Output)
+
+	run, step = h.Cached("test", "build", "b2")
+	assert.Nil(t, run)
+	assert.Nil(t, step)
+	run, _ = h.Cached("test", "", "")
+	assert.Nil(t, run)
+}
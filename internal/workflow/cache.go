@@ -0,0 +1,103 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/wingedpig/trellis/internal/watcher"
+)
+
+// cacheKeyVersion is hashed into every key, so changing what keys cover
+// invalidates the ones recorded before.
+const cacheKeyVersion = "1"
+
+// cacheKey returns the key a run or step's results are cached under: a
+// hash of config, the workflow or step being run, the run's inputs, the
+// files in dir matching ck.Files and, with ck.Commit, the commit checked
+// out. It returns "" when the commit counts but is unknown or has
+// uncommitted changes on top, as the key couldn't tell them apart.
+func cacheKey(ck *CacheKey, config any, inputs map[string]any, dir, commit string, dirty bool) (string, error) {
+	if ck.Commit && (commit == "" || dirty) {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "trellis cache key %s\n", cacheKeyVersion)
+	// Maps marshal with sorted keys, so equal configs and inputs hash the same
+	enc := json.NewEncoder(hash)
+	if err := enc.Encode(config); err != nil {
+		return "", fmt.Errorf("failed to hash config: %w", err)
+	}
+	if err := enc.Encode(inputs); err != nil {
+		return "", fmt.Errorf("failed to hash inputs: %w", err)
+	}
+	if ck.Commit {
+		fmt.Fprintf(hash, "commit %s\n", commit)
+	}
+
+	if len(ck.Files) > 0 {
+		err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if d.IsDir() {
+				if name := d.Name(); file != dir && (name == ".git" || name == ".trellis") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || !matchesAny(ck.Files, rel) {
+				return nil
+			}
+			sum, err := fileHash(file)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "file %s %s\n", rel, sum)
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash files: %w", err)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// matchesAny reports whether the slash-separated relative path rel
+// matches one of patterns.
+func matchesAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if watcher.MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileHash returns the hex SHA-256 of a file's contents.
+func fileHash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
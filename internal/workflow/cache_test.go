@@ -0,0 +1,77 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheKey(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n")
+	writeFile(t, dir, "main.go", "package main")
+	writeFile(t, dir, "internal/app/app.go", "package app")
+	writeFile(t, dir, "README.md", "readme")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/main")
+
+	ck := &CacheKey{Files: []string{"*.go", "go.mod"}}
+	step := WorkflowStep{ID: "build", Command: []string{"go", "build", "./..."}}
+	inputs := map[string]any{"tags": "dev"}
+	key := func() string {
+		t.Helper()
+		k, err := cacheKey(ck, step, inputs, dir, "abc", false)
+		require.NoError(t, err)
+		require.Len(t, k, 64)
+		return k
+	}
+
+	base := key()
+	assert.Equal(t, base, key(), "keys are stable")
+
+	writeFile(t, dir, "README.md", "changed")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/other")
+	assert.Equal(t, base, key(), "files not listed don't count")
+
+	writeFile(t, dir, "internal/app/app.go", "package app // changed")
+	changed := key()
+	assert.NotEqual(t, base, changed, "listed files count at any depth")
+
+	inputs["tags"] = "prod"
+	assert.NotEqual(t, changed, key(), "inputs count")
+	inputs["tags"] = "dev"
+	assert.Equal(t, changed, key())
+
+	step.Command = []string{"go", "build", "-race", "./..."}
+	assert.NotEqual(t, changed, key(), "the config counts")
+}
+
+func TestCacheKey_Commit(t *testing.T) {
+	dir := t.TempDir()
+	ck := &CacheKey{Commit: true}
+
+	k1, err := cacheKey(ck, nil, nil, dir, "abc", false)
+	require.NoError(t, err)
+	k2, err := cacheKey(ck, nil, nil, dir, "def", false)
+	require.NoError(t, err)
+	assert.NotEqual(t, k1, k2)
+
+	// Uncommitted changes or no commit at all can't be keyed
+	k, err := cacheKey(ck, nil, nil, dir, "abc", true)
+	require.NoError(t, err)
+	assert.Empty(t, k)
+	k, err = cacheKey(ck, nil, nil, dir, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, k)
+
+	// Without the commit, other commits share the key
+	ck.Commit = false
+	k1, err = cacheKey(ck, nil, nil, dir, "abc", true)
+	require.NoError(t, err)
+	k2, err = cacheKey(ck, nil, nil, dir, "def", false)
+	require.NoError(t, err)
+	assert.Equal(t, k1, k2)
+}
@@ -0,0 +1,298 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxCoverageFileSize caps the artifacts read as coverage files.
+const maxCoverageFileSize = 64 << 20
+
+// Coverage summarizes coverage files: Go cover profiles (go test
+// -coverprofile), LCOV tracefiles and Cobertura XML reports. Go profiles
+// count statements; the others count lines.
+type Coverage struct {
+	Covered int
+	Total   int
+	Files   []FileCoverage // Sorted by path
+}
+
+// FileCoverage is the coverage of one source file.
+type FileCoverage struct {
+	File    string // Relative to the working directory where it's under it
+	Covered int
+	Total   int
+}
+
+// Percent returns the percentage of statements or lines covered.
+func (c Coverage) Percent() float64 {
+	return percent(c.Covered, c.Total)
+}
+
+// Percent returns the percentage of the file's statements or lines covered.
+func (f FileCoverage) Percent() float64 {
+	return percent(f.Covered, f.Total)
+}
+
+func percent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+// ParseCoverage parses a coverage file, returning the coverage of each
+// source file it reports on, or nil if data isn't a coverage file. dir is
+// the working directory the files are made relative to; Go import paths
+// are made relative to it with its go.mod's module path.
+func ParseCoverage(data []byte, dir string) []FileCoverage {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte("mode: ")):
+		return parseGoCoverage(data, dir)
+	case bytes.Contains(head, []byte("<coverage")):
+		return parseCobertura(data, dir)
+	case bytes.Contains(data, []byte("\nSF:")) || bytes.HasPrefix(data, []byte("SF:")):
+		return parseLCOV(data, dir)
+	}
+	return nil
+}
+
+// SummarizeCoverage parses the coverage files among a run's artifacts,
+// stored in artifactsDir, into a summary of the source files they report
+// on. A file in several coverage files counts once, from the first. It
+// returns nil if none of the artifacts is a coverage file.
+func SummarizeCoverage(artifacts []Artifact, artifactsDir, dir string) *Coverage {
+	seen := make(map[string]bool)
+	var c Coverage
+	for _, a := range artifacts {
+		if a.Size == 0 || a.Size > maxCoverageFileSize || !validArtifactPath(a.Path) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(artifactsDir, filepath.FromSlash(a.Path)))
+		if err != nil {
+			continue
+		}
+		for _, f := range ParseCoverage(data, dir) {
+			if seen[f.File] {
+				continue
+			}
+			seen[f.File] = true
+			c.Files = append(c.Files, f)
+			c.Covered += f.Covered
+			c.Total += f.Total
+		}
+	}
+	if len(c.Files) == 0 {
+		return nil
+	}
+	sort.Slice(c.Files, func(i, j int) bool {
+		return c.Files[i].File < c.Files[j].File
+	})
+	return &c
+}
+
+// parseGoCoverage parses a Go cover profile, whose lines after the mode
+// are "file.go:12.34,15.2 3 1": a block's position, statements and count.
+// Profiles merged from several test binaries repeat blocks, which count
+// once, as covered if any run covered them.
+func parseGoCoverage(data []byte, dir string) []FileCoverage {
+	type block struct {
+		stmts   int
+		covered bool
+	}
+	blocks := make(map[string]map[string]*block) // By file, then position
+	var order []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		i := strings.LastIndex(fields[0], ":")
+		if i <= 0 {
+			continue
+		}
+		file, pos := fields[0][:i], fields[0][i+1:]
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if blocks[file] == nil {
+			blocks[file] = make(map[string]*block)
+			order = append(order, file)
+		}
+		b := blocks[file][pos]
+		if b == nil {
+			b = &block{stmts: stmts}
+			blocks[file][pos] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+
+	module := goModulePath(dir)
+	files := make([]FileCoverage, 0, len(order))
+	for _, file := range order {
+		f := FileCoverage{File: file}
+		if rel, ok := strings.CutPrefix(file, module+"/"); ok && module != "" {
+			f.File = rel
+		}
+		for _, b := range blocks[file] {
+			f.Total += b.stmts
+			if b.covered {
+				f.Covered += b.stmts
+			}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// goModulePath returns the module path declared in dir's go.mod, or "" if
+// there is none.
+func goModulePath(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// parseLCOV parses an LCOV tracefile: a record per source file, from its
+// SF: line to end_of_record, with a DA: line per instrumented line, or
+// only the LF: and LH: totals.
+func parseLCOV(data []byte, dir string) []FileCoverage {
+	var files []FileCoverage
+	var cur *FileCoverage
+	var lines map[string]bool // Instrumented lines of cur, and whether they were hit
+	found, hit := 0, 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			cur = &FileCoverage{File: relPath(dir, line[3:])}
+			lines = make(map[string]bool)
+			found, hit = 0, 0
+		case cur == nil:
+		case strings.HasPrefix(line, "DA:"):
+			parts := strings.Split(line[3:], ",")
+			if len(parts) >= 2 {
+				lines[parts[0]] = lines[parts[0]] || (parts[1] != "0" && parts[1] != "-")
+			}
+		case strings.HasPrefix(line, "LF:"):
+			found = atoi(line[3:])
+		case strings.HasPrefix(line, "LH:"):
+			hit = atoi(line[3:])
+		case line == "end_of_record":
+			if len(lines) > 0 {
+				for _, covered := range lines {
+					cur.Total++
+					if covered {
+						cur.Covered++
+					}
+				}
+			} else {
+				cur.Total, cur.Covered = found, hit
+			}
+			files = append(files, *cur)
+			cur = nil
+		}
+	}
+	return files
+}
+
+// coberturaReport is the part of a Cobertura XML report that counts lines.
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number string `xml:"number,attr"`
+				Hits   int    `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura parses a Cobertura XML report, as written by coverage.py,
+// Istanbul, gocover-cobertura and JaCoCo converters. Its classes' file
+// names are relative to its first source.
+func parseCobertura(data []byte, dir string) []FileCoverage {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil
+	}
+	source := ""
+	if len(report.Sources) > 0 {
+		source = strings.TrimSpace(report.Sources[0])
+	}
+
+	lines := make(map[string]map[string]bool) // By file, then line number
+	var order []string
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			file := class.Filename
+			if source != "" && !filepath.IsAbs(file) {
+				file = filepath.Join(source, file)
+			}
+			file = relPath(dir, file)
+			if lines[file] == nil {
+				lines[file] = make(map[string]bool)
+				order = append(order, file)
+			}
+			for _, l := range class.Lines {
+				lines[file][l.Number] = lines[file][l.Number] || l.Hits > 0
+			}
+		}
+	}
+
+	files := make([]FileCoverage, 0, len(order))
+	for _, file := range order {
+		f := FileCoverage{File: file}
+		for _, covered := range lines[file] {
+			f.Total++
+			if covered {
+				f.Covered++
+			}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// relPath returns file relative to dir if it's an absolute path under dir,
+// and file otherwise.
+func relPath(dir, file string) string {
+	if !filepath.IsAbs(file) || dir == "" {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(dir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
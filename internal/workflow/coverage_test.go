@@ -0,0 +1,115 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCoverage_Go(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.25\n")
+
+	// The same block from two test binaries counts once
+	profile := `mode: set
+example.com/app/calc.go:3.24,5.2 2 1
+example.com/app/calc.go:7.24,9.2 1 0
+example.com/app/calc.go:7.24,9.2 1 1
+example.com/app/internal/util/util.go:3.20,6.2 3 0
+other.org/lib/lib.go:1.1,2.2 1 1
+`
+	files := ParseCoverage([]byte(profile), dir)
+	assert.Equal(t, []FileCoverage{
+		{File: "calc.go", Covered: 3, Total: 3},
+		{File: "internal/util/util.go", Covered: 0, Total: 3},
+		{File: "other.org/lib/lib.go", Covered: 1, Total: 1},
+	}, files)
+}
+
+func TestParseCoverage_LCOV(t *testing.T) {
+	dir := t.TempDir()
+	lcov := `TN:
+SF:` + filepath.Join(dir, "src/app.js") + `
+FN:1,main
+DA:1,1
+DA:2,0
+DA:3,5
+LF:3
+LH:2
+end_of_record
+SF:src/totals.js
+LF:10
+LH:4
+end_of_record
+`
+	files := ParseCoverage([]byte(lcov), dir)
+	assert.Equal(t, []FileCoverage{
+		{File: "src/app.js", Covered: 2, Total: 3},
+		{File: "src/totals.js", Covered: 4, Total: 10},
+	}, files)
+}
+
+func TestParseCoverage_Cobertura(t *testing.T) {
+	dir := t.TempDir()
+	report := `<?xml version="1.0" ?>
+<coverage version="7.4" line-rate="0.5">
+	<sources>
+		<source>` + dir + `</source>
+	</sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="calc.py" filename="app/calc.py" line-rate="0.5">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+						<line number="3" hits="2"/>
+						<line number="4" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+	files := ParseCoverage([]byte(report), dir)
+	assert.Equal(t, []FileCoverage{{File: "app/calc.py", Covered: 2, Total: 4}}, files)
+}
+
+func TestParseCoverage_NotCoverage(t *testing.T) {
+	assert.Nil(t, ParseCoverage([]byte("hello\n"), ""))
+	assert.Nil(t, ParseCoverage([]byte("<testsuite/>"), ""))
+}
+
+func TestSummarizeCoverage(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	work := t.TempDir()
+	start := time.Now()
+	writeFile(t, work, "coverage.out", "mode: count\nb.go:1.1,2.2 4 0\na.go:1.1,2.2 2 3\n")
+	writeFile(t, work, "lcov.info", "SF:b.go\nDA:1,1\nend_of_record\nSF:c.js\nDA:1,1\nDA:2,1\nend_of_record\n")
+	writeFile(t, work, "app", "binary")
+
+	artifacts, err := h.CollectArtifacts("test-1", work, []string{"*"}, start, "")
+	require.NoError(t, err)
+
+	// b.go counts from the first coverage file only
+	c := SummarizeCoverage(artifacts, h.artifactsDir("test-1"), work)
+	require.NotNil(t, c)
+	assert.Equal(t, []FileCoverage{
+		{File: "a.go", Covered: 2, Total: 2},
+		{File: "b.go", Covered: 0, Total: 4},
+		{File: "c.js", Covered: 2, Total: 2},
+	}, c.Files)
+	assert.Equal(t, 4, c.Covered)
+	assert.Equal(t, 8, c.Total)
+	assert.InDelta(t, 50.0, c.Percent(), 0.01)
+
+	assert.Nil(t, SummarizeCoverage(artifacts[:1], h.artifactsDir("test-1"), work), "the binary isn't coverage")
+}
@@ -8,24 +8,163 @@ import (
 	"html"
 	"regexp"
 	"strings"
+	"time"
 )
 
 // FormatOutput formats workflow output based on the parser type.
 // For HTML parser, output is passed through unchanged.
 // For go_test_json parser, test results are rendered as a summary.
-// For other parsers, output is HTML-escaped with clickable file links.
+// For go and generic parsers, output is HTML-escaped with clickable file links.
+// For other parsers, the problems and test results parsed are rendered
+// above the output.
 func FormatOutput(output string, parsedLines []ParsedLine, parserName string) string {
-	if parserName == "html" {
+	switch parserName {
+	case "html":
 		return output
-	}
-	if parserName == "go_test_json" {
+	case "go_test_json":
 		// During streaming, parsedLines is nil - don't show raw JSON
 		if len(parsedLines) == 0 {
 			return "<span class=\"text-muted\">Running tests...</span>"
 		}
 		return FormatTestResults(parsedLines, output)
+	case "", "none", "go", "generic":
+		return FormatOutputHTML(output, parsedLines)
+	}
+	return FormatParsedOutput(output, parsedLines)
+}
+
+// FormatParsedOutput formats output with the errors and warnings parsed
+// from it, linked to their files, and its test results, followed by the
+// output itself, collapsed.
+func FormatParsedOutput(output string, parsedLines []ParsedLine) string {
+	if len(parsedLines) == 0 {
+		return FormatOutputHTML(output, nil)
+	}
+
+	var problems, tests []ParsedLine
+	for _, line := range parsedLines {
+		switch line.Type {
+		case "error", "warning":
+			problems = append(problems, line)
+		default:
+			tests = append(tests, line)
+		}
+	}
+
+	var sb strings.Builder
+	if len(problems) > 0 {
+		sb.WriteString("<details open><summary style=\"cursor: pointer;\"><strong>Problems</strong> <span class=\"text-muted\">(")
+		sb.WriteString(fmt.Sprintf("%d", len(problems)))
+		sb.WriteString(")</span></summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
+		for _, p := range problems {
+			if p.Type == "error" {
+				sb.WriteString("<span class=\"text-danger\">✗</span> ")
+			} else {
+				sb.WriteString("<span class=\"text-warning\">!</span> ")
+			}
+			if p.File != "" {
+				sb.WriteString(fileLink(p.File, p.Line, p.Column))
+				sb.WriteString(": ")
+			}
+			sb.WriteString(html.EscapeString(p.Message))
+			sb.WriteString("<br>")
+		}
+		sb.WriteString("</div></details>")
+	}
+	if len(tests) > 0 {
+		if len(problems) > 0 {
+			sb.WriteString("<br>")
+		}
+		sb.WriteString(FormatTestResults(tests, ""))
 	}
-	return FormatOutputHTML(output, parsedLines)
+	if output != "" {
+		sb.WriteString("<br><details><summary style=\"cursor: pointer;\"><strong>Output</strong></summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
+		sb.WriteString(FormatOutputHTML(output, nil))
+		sb.WriteString("</div></details>")
+	}
+	return sb.String()
+}
+
+// fileLink returns a link opening file at line and col, labeled with them.
+func fileLink(file string, line, col int) string {
+	label := file
+	if line > 0 {
+		label += fmt.Sprintf(":%d", line)
+		if col > 0 {
+			label += fmt.Sprintf(":%d", col)
+		}
+	}
+	return fmt.Sprintf("<a href=\"#\" class=\"text-danger\" onclick=\"openFileAtLine('%s', %d, %d); return false;\">%s</a>",
+		html.EscapeString(file), line, col, html.EscapeString(label))
+}
+
+// testLabel returns the name of a test, qualified by its package if any.
+func testLabel(line ParsedLine) string {
+	if line.Package == "" {
+		return line.TestName
+	}
+	return line.Package + "/" + line.TestName
+}
+
+// FormatStepsHTML formats the output of a run composed of steps: a line per
+// step with its result, then each step's formatted output, expanded for
+// steps that didn't succeed.
+func FormatStepsHTML(steps []StepStatus) string {
+	var sb strings.Builder
+	for _, st := range steps {
+		sb.WriteString(formatStepResult(st))
+		sb.WriteString("<br>")
+	}
+	for _, st := range steps {
+		if st.Output == "" && len(st.Steps) == 0 {
+			continue
+		}
+		open := ""
+		if st.State != StateSuccess {
+			open = " open"
+		}
+		sb.WriteString("<br><details" + open + "><summary style=\"cursor: pointer;\">")
+		sb.WriteString(formatStepResult(st))
+		sb.WriteString("</summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
+		switch {
+		case len(st.Steps) > 0:
+			sb.WriteString(FormatStepsHTML(st.Steps))
+		case st.parser == "go_test_json" && len(st.parsed) == 0:
+			// No test ran, so the output is the build failure
+			sb.WriteString(FormatOutputHTML(st.Output, nil))
+		default:
+			sb.WriteString(FormatOutput(st.Output, st.parsed, st.parser))
+		}
+		sb.WriteString("</div></details>")
+	}
+	return sb.String()
+}
+
+// formatStepResult formats a step's name, state, duration and error.
+func formatStepResult(st StepStatus) string {
+	var icon string
+	switch st.State {
+	case StateSuccess:
+		icon = "<span class=\"text-success\">✓</span>"
+	case StateFailed:
+		icon = "<span class=\"text-danger\">✗</span>"
+	case StateSkipped, StateCanceled:
+		icon = "<span class=\"text-muted\">–</span>"
+	default:
+		icon = "<span class=\"text-info\">…</span>"
+	}
+	s := icon + " <strong>" + html.EscapeString(st.Name) + "</strong> <span class=\"text-muted\">" + string(st.State)
+	if !st.StartedAt.IsZero() {
+		s += " in " + st.Duration.Round(100*time.Millisecond).String()
+	}
+	if st.CachedFrom != "" {
+		s += ", restored from " + html.EscapeString(st.CachedFrom)
+	}
+	s += "</span>"
+	if st.Error != "" {
+		s += " <span class=\"text-danger\">" + html.EscapeString(st.Error) + "</span>"
+	}
+	return s
 }
 
 // FormatTestResults formats go test -json parsed results into readable HTML.
@@ -70,8 +209,14 @@ func FormatTestResults(parsedLines []ParsedLine, rawOutput string) string {
 		sb.WriteString(fmt.Sprintf("%d", failed))
 		sb.WriteString(")</span></summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
 		for _, f := range failures {
-			sb.WriteString(fmt.Sprintf("<span class=\"text-danger\">✗</span> %s/%s<br>",
-				html.EscapeString(f.Package), html.EscapeString(f.TestName)))
+			sb.WriteString(fmt.Sprintf("<span class=\"text-danger\">✗</span> %s", html.EscapeString(testLabel(f))))
+			if f.File != "" {
+				sb.WriteString(" " + fileLink(f.File, f.Line, f.Column))
+			}
+			if f.Message != "" && f.RawOutput == "" {
+				sb.WriteString(" - " + html.EscapeString(f.Message))
+			}
+			sb.WriteString("<br>")
 			if f.RawOutput != "" {
 				// Format the test output, making file:line references clickable
 				formattedOutput := formatTestOutput(f.RawOutput)
@@ -94,8 +239,7 @@ func FormatTestResults(parsedLines []ParsedLine, rawOutput string) string {
 		sb.WriteString(")</span></summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
 		for _, line := range parsedLines {
 			if line.Type == "test_pass" {
-				sb.WriteString(fmt.Sprintf("<span class=\"text-success\">✓</span> %s/%s<br>",
-					html.EscapeString(line.Package), html.EscapeString(line.TestName)))
+				sb.WriteString(fmt.Sprintf("<span class=\"text-success\">✓</span> %s<br>", html.EscapeString(testLabel(line))))
 			}
 		}
 		sb.WriteString("</div></details>")
@@ -108,10 +252,13 @@ func FormatTestResults(parsedLines []ParsedLine, rawOutput string) string {
 		sb.WriteString(")</span></summary><div style=\"margin-left: 1em; margin-top: 0.5em;\">")
 		for _, line := range parsedLines {
 			if line.Type == "test_skip" {
-				sb.WriteString(fmt.Sprintf("<span class=\"text-warning\">○</span> %s/%s",
-					html.EscapeString(line.Package), html.EscapeString(line.TestName)))
-				// Extract skip reason from output
-				if reason := extractSkipReason(line.RawOutput); reason != "" {
+				sb.WriteString(fmt.Sprintf("<span class=\"text-warning\">○</span> %s", html.EscapeString(testLabel(line))))
+				// Extract skip reason from output, unless the parser found it
+				reason := line.Message
+				if reason == "" {
+					reason = extractSkipReason(line.RawOutput)
+				}
+				if reason != "" {
 					sb.WriteString(fmt.Sprintf(" - <span class=\"text-muted\">%s</span>", html.EscapeString(reason)))
 				}
 				sb.WriteString("<br>")
@@ -129,7 +276,7 @@ func formatTestOutput(output string) string {
 	var result []string
 
 	// Pattern for file:line:col or file:line references
-	fileLinePattern := regexp.MustCompile(`(\S+\.go):(\d+)(?::(\d+))?`)
+	fileLinePattern := regexp.MustCompile(`([^\s'"(]+\.(?:go|py|rs|js|jsx|mjs|cjs|ts|tsx|java|kt|rb|php|c|h|cc|cpp|hpp|cs|swift)):(\d+)(?::(\d+))?`)
 
 	for _, line := range lines {
 		// Skip empty lines and redundant markers
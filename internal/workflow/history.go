@@ -0,0 +1,509 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// History persists completed workflow runs, so their results outlive the
+// runner's in-memory runs and trellis restarts.
+//
+// Each run is stored as two files in the history directory: <run-id>.json
+// holds the run's status without its output, and <run-id>.output.json its
+// output, parsed lines and coverage. Only the former are read to list runs
+// and build test timelines. The run's artifacts are kept under
+// <run-id>.artifacts.
+type History struct {
+	mu      sync.RWMutex
+	dir     string
+	maxAge  time.Duration
+	maxRuns int
+	runs    map[string][]*WorkflowStatus // Workflow ID to its runs without output, oldest first
+}
+
+// runOutput is the part of a run stored in its output file.
+type runOutput struct {
+	Output      string
+	OutputHTML  string
+	ParsedLines []ParsedLine
+	Steps       []StepStatus // Steps with their output
+	Coverage    *Coverage
+}
+
+// NewHistory opens the run history stored in dir, keeping runs for maxAge
+// and at most maxRuns runs per workflow.
+func NewHistory(dir string, maxAge time.Duration, maxRuns int) (*History, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create workflow history directory: %w", err)
+	}
+
+	h := &History{
+		dir:     dir,
+		maxAge:  maxAge,
+		maxRuns: maxRuns,
+		runs:    make(map[string][]*WorkflowStatus),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow history directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".output.json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		var status WorkflowStatus
+		if err := json.Unmarshal(data, &status); err != nil || status.WorkflowID == "" {
+			continue
+		}
+		h.runs[status.WorkflowID] = append(h.runs[status.WorkflowID], &status)
+	}
+
+	// Drop the artifacts of runs that were never recorded
+	for _, entry := range entries {
+		runID, ok := strings.CutSuffix(entry.Name(), ".artifacts")
+		if !entry.IsDir() || !ok {
+			continue
+		}
+		if _, err := os.Stat(h.runPath(runID)); os.IsNotExist(err) {
+			os.RemoveAll(filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	for id, runs := range h.runs {
+		sort.Slice(runs, func(i, j int) bool {
+			return runs[i].StartedAt.Before(runs[j].StartedAt)
+		})
+		h.prune(id, time.Now())
+	}
+
+	return h, nil
+}
+
+// Record stores a completed run.
+func (h *History) Record(status *WorkflowStatus) error {
+	if status.WorkflowID == "" {
+		return fmt.Errorf("workflow run %q has no workflow ID", status.ID)
+	}
+	if err := validRunID(status.ID); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	output, err := json.Marshal(runOutput{
+		Output:      status.Output,
+		OutputHTML:  status.OutputHTML,
+		ParsedLines: status.ParsedLines,
+		Steps:       status.Steps,
+		Coverage:    status.Coverage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow run output: %w", err)
+	}
+	if err := os.WriteFile(h.outputPath(status.ID), output, 0644); err != nil {
+		return fmt.Errorf("failed to write workflow run output: %w", err)
+	}
+
+	run := withoutOutput(status)
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow run: %w", err)
+	}
+	if err := os.WriteFile(h.runPath(status.ID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write workflow run: %w", err)
+	}
+
+	runs := h.runs[run.WorkflowID]
+	i := sort.Search(len(runs), func(i int) bool {
+		return runs[i].StartedAt.After(run.StartedAt)
+	})
+	runs = append(runs, nil)
+	copy(runs[i+1:], runs[i:])
+	runs[i] = run
+	h.runs[run.WorkflowID] = runs
+
+	h.prune(run.WorkflowID, time.Now())
+	return nil
+}
+
+// Runs returns a workflow's recorded runs without their output, newest
+// first. A non-empty worktree limits them to the runs for that worktree,
+// and a positive limit to that many runs.
+func (h *History) Runs(workflowID, worktree string, limit int) []WorkflowStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	runs := h.runs[workflowID]
+	result := make([]WorkflowStatus, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		if worktree != "" && runs[i].Worktree != worktree {
+			continue
+		}
+		result = append(result, *runs[i])
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// Run returns a recorded run with its output.
+func (h *History) Run(runID string) (*WorkflowStatus, error) {
+	if err := validRunID(runID); err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	data, err := os.ReadFile(h.runPath(runID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("workflow run %q not found", runID)
+		}
+		return nil, fmt.Errorf("failed to read workflow run: %w", err)
+	}
+	var status WorkflowStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow run: %w", err)
+	}
+
+	// A run whose output is missing is still worth showing
+	if data, err := os.ReadFile(h.outputPath(runID)); err == nil {
+		var output runOutput
+		if err := json.Unmarshal(data, &output); err == nil {
+			status.Output = output.Output
+			status.OutputHTML = output.OutputHTML
+			status.ParsedLines = output.ParsedLines
+			status.Coverage = output.Coverage
+			if output.Steps != nil {
+				status.Steps = output.Steps
+			}
+		}
+	}
+	return &status, nil
+}
+
+// Cached returns the newest successful run of a workflow recorded with
+// key, and its output, for the run to restore its results from. With a
+// step, the path of one of the workflow's steps, it's the newest run in
+// which that step succeeded with key, and that step. It returns nil if
+// there is none.
+func (h *History) Cached(workflowID, step, key string) (*WorkflowStatus, *StepStatus) {
+	if key == "" {
+		return nil, nil
+	}
+
+	h.mu.RLock()
+	var runID string
+	runs := h.runs[workflowID]
+	for i := len(runs) - 1; i >= 0 && runID == ""; i-- {
+		run := runs[i]
+		if step == "" {
+			if run.State == StateSuccess && run.CacheKey == key {
+				runID = run.ID
+			}
+		} else if st := findStep(run.Steps, step); st != nil && st.State == StateSuccess && st.CacheKey == key {
+			runID = run.ID
+		}
+	}
+	h.mu.RUnlock()
+	if runID == "" {
+		return nil, nil
+	}
+
+	run, err := h.Run(runID)
+	if err != nil {
+		return nil, nil
+	}
+	if step == "" {
+		return run, nil
+	}
+	return run, findStep(run.Steps, step)
+}
+
+// findStep returns the step of steps at path, the IDs of a step and the
+// steps of the workflows it's nested in separated by "/".
+func findStep(steps []StepStatus, path string) *StepStatus {
+	id, rest, nested := strings.Cut(path, "/")
+	for i := range steps {
+		if steps[i].ID != id {
+			continue
+		}
+		if nested {
+			return findStep(steps[i].Steps, rest)
+		}
+		return &steps[i]
+	}
+	return nil
+}
+
+// TestResult is a test's result in one run.
+type TestResult string
+
+const (
+	TestPassed  TestResult = "pass"
+	TestFailed  TestResult = "fail"
+	TestUnknown TestResult = "unknown" // The run's list of failed tests was capped
+)
+
+// TestTimeline is the pass/fail history of the tests that failed in a
+// workflow's recent runs.
+type TestTimeline struct {
+	Runs  []WorkflowStatus // Runs that reported test results, oldest first, without output
+	Tests []TestHistory    // Tests that failed at least once, most failures first
+}
+
+// TestHistory is one test's results across the runs of a TestTimeline.
+type TestHistory struct {
+	Test     string       // "package.TestName", as in WorkflowSummary.FailedTests
+	Results  []TestResult // One per run of the timeline
+	Failures int
+	Flips    int // Changes between passing and failing from one run to the next
+}
+
+// Tests builds the timeline of the tests that failed in a workflow's last
+// limit runs reporting test results, or all of them if limit isn't
+// positive. Results come from WorkflowSummary.FailedTests, so a test that
+// didn't fail in a run is taken to have passed.
+func (h *History) Tests(workflowID string, limit int) *TestTimeline {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var runs []*WorkflowStatus
+	for _, run := range h.runs[workflowID] {
+		if hasTestResults(run) {
+			runs = append(runs, run)
+		}
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	timeline := &TestTimeline{Runs: make([]WorkflowStatus, len(runs)), Tests: []TestHistory{}}
+	index := make(map[string]int)
+	for i, run := range runs {
+		timeline.Runs[i] = *run
+		for _, test := range run.Summary.FailedTests {
+			if _, ok := index[test]; !ok {
+				index[test] = len(timeline.Tests)
+				timeline.Tests = append(timeline.Tests, TestHistory{Test: test})
+			}
+		}
+	}
+
+	for t := range timeline.Tests {
+		test := &timeline.Tests[t]
+		test.Results = make([]TestResult, len(runs))
+		var last TestResult // Last known result
+		for i, run := range runs {
+			result := testResult(run, test.Test)
+			test.Results[i] = result
+			if result == TestFailed {
+				test.Failures++
+			}
+			if result != TestUnknown {
+				if last != "" && result != last {
+					test.Flips++
+				}
+				last = result
+			}
+		}
+	}
+
+	sort.SliceStable(timeline.Tests, func(i, j int) bool {
+		if timeline.Tests[i].Failures != timeline.Tests[j].Failures {
+			return timeline.Tests[i].Failures > timeline.Tests[j].Failures
+		}
+		return timeline.Tests[i].Test < timeline.Tests[j].Test
+	})
+	return timeline
+}
+
+// FlakyTest is a test that both passed and failed on the same commit.
+type FlakyTest struct {
+	Test        string
+	Commits     []string // Commits it both passed and failed on
+	Passes      int      // Passing runs on those commits
+	Failures    int      // Failing runs on those commits
+	LastFailure string   // ID of the last run it failed in
+}
+
+// Flaky returns the tests of a workflow that flip between passing and
+// failing without a change to the code: runs of the same commit with the
+// same inputs disagree on them. Runs with uncommitted changes don't count,
+// as the code they ran isn't known. Tests failing most often come first.
+func (h *History) Flaky(workflowID string) []FlakyTest {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	type key struct {
+		commit string
+		inputs string
+	}
+	groups := make(map[key][]*WorkflowStatus)
+	var order []key
+	for _, run := range h.runs[workflowID] {
+		if run.Commit == "" || run.Dirty || !hasTestResults(run) {
+			continue
+		}
+		inputs, _ := json.Marshal(run.Inputs) // Map keys are sorted
+		k := key{commit: run.Commit, inputs: string(inputs)}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], run)
+	}
+
+	flaky := make(map[string]*FlakyTest)
+	for _, k := range order {
+		runs := groups[k]
+		if len(runs) < 2 {
+			continue
+		}
+		failed := make(map[string]bool)
+		for _, run := range runs {
+			for _, test := range run.Summary.FailedTests {
+				failed[test] = true
+			}
+		}
+		for test := range failed {
+			var passes, failures int
+			var lastFailure string
+			for _, run := range runs {
+				switch testResult(run, test) {
+				case TestPassed:
+					passes++
+				case TestFailed:
+					failures++
+					lastFailure = run.ID
+				}
+			}
+			if passes == 0 {
+				continue
+			}
+			ft, ok := flaky[test]
+			if !ok {
+				ft = &FlakyTest{Test: test}
+				flaky[test] = ft
+			}
+			ft.Commits = append(ft.Commits, k.commit)
+			ft.Passes += passes
+			ft.Failures += failures
+			ft.LastFailure = lastFailure
+		}
+	}
+
+	result := make([]FlakyTest, 0, len(flaky))
+	for _, ft := range flaky {
+		result = append(result, *ft)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Failures != result[j].Failures {
+			return result[i].Failures > result[j].Failures
+		}
+		return result[i].Test < result[j].Test
+	})
+	return result
+}
+
+// hasTestResults reports whether a run ran tests to completion: canceled
+// runs and runs that failed before any test ran say nothing about tests.
+func hasTestResults(run *WorkflowStatus) bool {
+	if run.State != StateSuccess && run.State != StateFailed {
+		return false
+	}
+	return run.Summary != nil && run.Summary.TestsPassed+run.Summary.TestsFailed > 0
+}
+
+// testResult returns a test's result in a run reporting test results.
+func testResult(run *WorkflowStatus, test string) TestResult {
+	for _, failed := range run.Summary.FailedTests {
+		if failed == test {
+			return TestFailed
+		}
+	}
+	if len(run.Summary.FailedTests) < run.Summary.TestsFailed {
+		return TestUnknown
+	}
+	return TestPassed
+}
+
+// prune removes a workflow's runs older than maxAge and beyond maxRuns.
+// Caller must hold h.mu.
+func (h *History) prune(workflowID string, now time.Time) {
+	runs := h.runs[workflowID]
+	drop := 0
+	if h.maxAge > 0 {
+		cutoff := now.Add(-h.maxAge)
+		for drop < len(runs) && runs[drop].StartedAt.Before(cutoff) {
+			drop++
+		}
+	}
+	if h.maxRuns > 0 && len(runs)-drop > h.maxRuns {
+		drop = len(runs) - h.maxRuns
+	}
+	for _, run := range runs[:drop] {
+		os.Remove(h.runPath(run.ID))
+		os.Remove(h.outputPath(run.ID))
+		os.RemoveAll(h.artifactsDir(run.ID))
+	}
+	if drop == len(runs) {
+		delete(h.runs, workflowID)
+		return
+	}
+	h.runs[workflowID] = append([]*WorkflowStatus(nil), runs[drop:]...)
+}
+
+func (h *History) runPath(runID string) string {
+	return filepath.Join(h.dir, runID+".json")
+}
+
+func (h *History) outputPath(runID string) string {
+	return filepath.Join(h.dir, runID+".output.json")
+}
+
+// withoutOutput returns a copy of status without its output or its steps'.
+func withoutOutput(status *WorkflowStatus) *WorkflowStatus {
+	run := status.clone()
+	run.Output = ""
+	run.OutputHTML = ""
+	run.ParsedLines = nil
+	run.Coverage = nil
+	stripStepOutput(run.Steps)
+	return run
+}
+
+func stripStepOutput(steps []StepStatus) {
+	for i := range steps {
+		steps[i].Output = ""
+		stripStepOutput(steps[i].Steps)
+	}
+}
+
+// validRunID guards a run ID used verbatim as a filename. Run IDs reach the
+// history from route vars; reject anything that isn't a single,
+// non-traversing path component so it can't escape the history directory.
+func validRunID(id string) error {
+	if id == "" || id == "." || id == ".." ||
+		strings.ContainsAny(id, `/\`) || filepath.Base(id) != id {
+		return fmt.Errorf("invalid workflow run id: %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRun returns a completed run of the test workflow started at start,
+// failing the given tests.
+func testRun(n int, start time.Time, commit string, failed ...string) *WorkflowStatus {
+	state := StateSuccess
+	if len(failed) > 0 {
+		state = StateFailed
+	}
+	return &WorkflowStatus{
+		ID:         fmt.Sprintf("test-%d", n),
+		WorkflowID: "test",
+		Name:       "Test",
+		Commit:     commit,
+		State:      state,
+		Success:    len(failed) == 0,
+		StartedAt:  start,
+		FinishedAt: start.Add(time.Second),
+		Output:     fmt.Sprintf("output of run %d\n", n),
+		Summary: &WorkflowSummary{
+			TestsPassed: 10 - len(failed),
+			TestsFailed: len(failed),
+			FailedTests: failed,
+		},
+	}
+}
+
+func TestHistory_RecordAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, 0, 0)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	run := testRun(1, start, "abc", "pkg.TestA")
+	run.Worktree = "feature"
+	run.Inputs = map[string]any{"pkg": "./..."}
+	require.NoError(t, h.Record(run))
+	require.NoError(t, h.Record(testRun(2, start.Add(time.Minute), "abc")))
+
+	// Listing doesn't carry output
+	runs := h.Runs("test", "", 0)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "test-2", runs[0].ID)
+	assert.Empty(t, runs[0].Output)
+
+	assert.Len(t, h.Runs("test", "feature", 0), 1)
+	assert.Len(t, h.Runs("test", "", 1), 1)
+	assert.Empty(t, h.Runs("build", "", 0))
+
+	// Runs survive reopening the history
+	h, err = NewHistory(dir, 0, 0)
+	require.NoError(t, err)
+	got, err := h.Run("test-1")
+	require.NoError(t, err)
+	assert.Equal(t, "output of run 1\n", got.Output)
+	assert.Equal(t, "feature", got.Worktree)
+	assert.Equal(t, "abc", got.Commit)
+	assert.Equal(t, "./...", got.Inputs["pkg"])
+	assert.Equal(t, []string{"pkg.TestA"}, got.Summary.FailedTests)
+	assert.Len(t, h.Runs("test", "", 0), 2)
+
+	_, err = h.Run("test-3")
+	assert.Error(t, err)
+	_, err = h.Run("../test-1")
+	assert.Error(t, err)
+}
+
+func TestHistory_Retention(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, 24*time.Hour, 3)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, h.Record(testRun(0, now.Add(-48*time.Hour), "abc")))
+	assert.Empty(t, h.Runs("test", "", 0), "runs past max age are dropped")
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, h.Record(testRun(i, now.Add(time.Duration(i)*time.Minute), "abc")))
+	}
+	runs := h.Runs("test", "", 0)
+	require.Len(t, runs, 3)
+	assert.Equal(t, "test-4", runs[0].ID)
+	assert.Equal(t, "test-2", runs[2].ID)
+
+	for _, id := range []string{"test-0", "test-1"} {
+		_, err := os.Stat(filepath.Join(dir, id+".json"))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(dir, id+".output.json"))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestHistory_Tests(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	require.NoError(t, h.Record(testRun(1, start, "a", "pkg.TestA")))
+	require.NoError(t, h.Record(testRun(2, start.Add(1*time.Minute), "b")))
+	require.NoError(t, h.Record(testRun(3, start.Add(2*time.Minute), "c", "pkg.TestA", "pkg.TestB")))
+
+	// Canceled runs and runs without test results are left out
+	canceled := testRun(4, start.Add(3*time.Minute), "c", "pkg.TestC")
+	canceled.State = StateCanceled
+	require.NoError(t, h.Record(canceled))
+	noTests := testRun(5, start.Add(4*time.Minute), "c")
+	noTests.Summary = &WorkflowSummary{Errors: 1}
+	require.NoError(t, h.Record(noTests))
+
+	timeline := h.Tests("test", 0)
+	require.Len(t, timeline.Runs, 3)
+	assert.Equal(t, "test-1", timeline.Runs[0].ID)
+	require.Len(t, timeline.Tests, 2)
+	assert.Equal(t, TestHistory{
+		Test:     "pkg.TestA",
+		Results:  []TestResult{TestFailed, TestPassed, TestFailed},
+		Failures: 2,
+		Flips:    2,
+	}, timeline.Tests[0])
+	assert.Equal(t, []TestResult{TestPassed, TestPassed, TestFailed}, timeline.Tests[1].Results)
+
+	timeline = h.Tests("test", 2)
+	require.Len(t, timeline.Runs, 2)
+	assert.Equal(t, "test-2", timeline.Runs[0].ID)
+	assert.Equal(t, []TestResult{TestPassed, TestFailed}, timeline.Tests[0].Results)
+}
+
+func TestHistory_Tests_CappedFailures(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	require.NoError(t, h.Record(testRun(1, start, "a", "pkg.TestA")))
+	capped := testRun(2, start.Add(time.Minute), "a", "pkg.TestB")
+	capped.Summary.TestsFailed = 80 // more failures than FailedTests lists
+	require.NoError(t, h.Record(capped))
+
+	timeline := h.Tests("test", 0)
+	require.Len(t, timeline.Tests, 2)
+	assert.Equal(t, "pkg.TestA", timeline.Tests[0].Test)
+	assert.Equal(t, []TestResult{TestFailed, TestUnknown}, timeline.Tests[0].Results)
+}
+
+func TestHistory_Flaky(t *testing.T) {
+	h, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Minute) }
+
+	// TestA flips on commit a; TestB fails every run of commit a
+	require.NoError(t, h.Record(testRun(1, at(1), "a", "pkg.TestA", "pkg.TestB")))
+	require.NoError(t, h.Record(testRun(2, at(2), "a", "pkg.TestB")))
+	require.NoError(t, h.Record(testRun(3, at(3), "a", "pkg.TestA", "pkg.TestB")))
+
+	// TestC fails then passes, but the code changed in between
+	require.NoError(t, h.Record(testRun(4, at(4), "b", "pkg.TestC")))
+	require.NoError(t, h.Record(testRun(5, at(5), "c")))
+
+	// TestD passes and fails on commit d, but one run had uncommitted changes
+	require.NoError(t, h.Record(testRun(6, at(6), "d", "pkg.TestD")))
+	dirty := testRun(7, at(7), "d")
+	dirty.Dirty = true
+	require.NoError(t, h.Record(dirty))
+
+	// TestE passes and fails on commit e, but with different inputs
+	withInputs := testRun(8, at(8), "e", "pkg.TestE")
+	withInputs.Inputs = map[string]any{"run": "TestE"}
+	require.NoError(t, h.Record(withInputs))
+	require.NoError(t, h.Record(testRun(9, at(9), "e")))
+
+	flaky := h.Flaky("test")
+	require.Len(t, flaky, 1)
+	assert.Equal(t, FlakyTest{
+		Test:        "pkg.TestA",
+		Commits:     []string{"a"},
+		Passes:      1,
+		Failures:    2,
+		LastFailure: "test-3",
+	}, flaky[0])
+}
+
+func TestHistory_StepOutput(t *testing.T) {
+	dir := t.TempDir()
+	h, err := NewHistory(dir, 0, 0)
+	require.NoError(t, err)
+
+	run := testRun(1, time.Now(), "abc")
+	run.Steps = []StepStatus{{
+		ID:     "checks",
+		State:  StateSuccess,
+		Output: "checked\n",
+		Steps:  []StepStatus{{ID: "lint", State: StateSuccess, Output: "linted\n"}},
+	}}
+	require.NoError(t, h.Record(run))
+	assert.Equal(t, "checked\n", run.Steps[0].Output, "recording leaves the run alone")
+
+	// Listing doesn't carry step output
+	runs := h.Runs("test", "", 0)
+	require.Len(t, runs, 1)
+	require.Len(t, runs[0].Steps, 1)
+	assert.Empty(t, runs[0].Steps[0].Output)
+	assert.Empty(t, runs[0].Steps[0].Steps[0].Output)
+
+	got, err := h.Run("test-1")
+	require.NoError(t, err)
+	assert.Equal(t, "checked\n", got.Steps[0].Output)
+	assert.Equal(t, "linted\n", got.Steps[0].Steps[0].Output)
+}
@@ -258,3 +258,9 @@ func (p *HTMLParser) Parse(output string) []ParsedLine {
 func (p *HTMLParser) ParseLine(line string) *ParsedLine {
 	return nil
 }
+
+// atoi returns the number s, or 0 if s isn't one.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
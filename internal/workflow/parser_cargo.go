@@ -0,0 +1,191 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// CargoParser parses Rust build and test output: the JSON diagnostics of
+// cargo --message-format=json and rustc --error-format=json, libtest's JSON
+// events (cargo test -- -Z unstable-options --format json), and libtest's
+// default "test name ... ok" lines.
+type CargoParser struct{}
+
+func (p *CargoParser) Name() string {
+	return "cargo"
+}
+
+// cargoMessage is a line of cargo or rustc JSON output. Cargo wraps
+// compiler diagnostics in a "compiler-message"; rustc writes them bare.
+type cargoMessage struct {
+	Reason      string          `json:"reason"`
+	Message     json.RawMessage `json:"message"` // Diagnostic of a compiler-message
+	MessageType string          `json:"$message_type"`
+
+	// libtest events
+	Type   string `json:"type"`
+	Event  string `json:"event"`
+	Name   string `json:"name"`
+	Stdout string `json:"stdout"`
+}
+
+// rustDiagnostic is a compiler diagnostic.
+type rustDiagnostic struct {
+	Level string `json:"level"`
+	Text  string `json:"message"`
+	Code  *struct {
+		Code string `json:"code"`
+	} `json:"code"`
+	Spans []struct {
+		FileName    string `json:"file_name"`
+		LineStart   int    `json:"line_start"`
+		ColumnStart int    `json:"column_start"`
+		IsPrimary   bool   `json:"is_primary"`
+	} `json:"spans"`
+	Rendered string `json:"rendered"`
+}
+
+var (
+	// cargoTestPattern matches libtest's default output: test tests::add ... ok
+	cargoTestPattern = regexp.MustCompile(`^test (\S+) \.\.\. (ok|FAILED|ignored)`)
+	// cargoFailureHeader matches the header of a failed test's output: ---- tests::add stdout ----
+	cargoFailureHeader = regexp.MustCompile(`^---- (\S+) stdout ----$`)
+	// cargoPanicPattern matches where a test panicked: thread 'tests::add' panicked at src/lib.rs:10:5:
+	cargoPanicPattern = regexp.MustCompile(`panicked at (\S+?):(\d+):(\d+)`)
+	// rustNoisePattern matches the closing diagnostics of a failed build.
+	rustNoisePattern = regexp.MustCompile(`^(aborting due to|\d+ warnings? emitted|For more information about)`)
+)
+
+// Parse parses cargo and rustc output.
+func (p *CargoParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	failures := make(map[string][]string) // Failed test output by name, from the default format
+	var failure string                    // Test whose output is being collected
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "{") {
+			var msg cargoMessage
+			if err := json.Unmarshal([]byte(trimmed), &msg); err == nil {
+				if parsed := msg.parsedLine(trimmed); parsed != nil {
+					result = append(result, *parsed)
+				}
+				continue
+			}
+		}
+
+		if m := cargoFailureHeader.FindStringSubmatch(trimmed); m != nil {
+			failure = m[1]
+			continue
+		}
+		if failure != "" {
+			if trimmed == "" || trimmed == "failures:" || strings.HasPrefix(trimmed, "---- ") {
+				if trimmed != "" {
+					failure = ""
+				}
+				continue
+			}
+			failures[failure] = append(failures[failure], line)
+			continue
+		}
+		if m := cargoTestPattern.FindStringSubmatch(trimmed); m != nil {
+			parsed := ParsedLine{Type: "test_pass", TestName: m[1]}
+			switch m[2] {
+			case "FAILED":
+				parsed.Type = "test_fail"
+			case "ignored":
+				parsed.Type = "test_skip"
+			}
+			result = append(result, parsed)
+		}
+	}
+
+	for i := range result {
+		if out, ok := failures[result[i].TestName]; ok && result[i].Type == "test_fail" {
+			result[i].RawOutput = strings.Join(out, "\n")
+			result[i].locatePanic()
+		}
+	}
+	return result
+}
+
+// parsedLine returns the diagnostic or test result of a JSON line, or nil
+// if there is none.
+func (msg *cargoMessage) parsedLine(line string) *ParsedLine {
+	var diagnostic []byte
+	switch {
+	case msg.Reason == "compiler-message":
+		diagnostic = msg.Message
+	case msg.MessageType == "diagnostic":
+		diagnostic = []byte(line)
+	}
+	if diagnostic != nil {
+		var d rustDiagnostic
+		if err := json.Unmarshal(diagnostic, &d); err != nil {
+			return nil
+		}
+		return d.parsedLine()
+	}
+
+	if msg.Type != "test" {
+		return nil
+	}
+	parsed := &ParsedLine{TestName: msg.Name, RawOutput: msg.Stdout}
+	switch msg.Event {
+	case "ok":
+		parsed.Type = "test_pass"
+	case "failed", "timeout":
+		parsed.Type = "test_fail"
+		parsed.locatePanic()
+	case "ignored":
+		parsed.Type = "test_skip"
+	default:
+		return nil
+	}
+	return parsed
+}
+
+// parsedLine returns an error or warning diagnostic at its primary span,
+// or nil for notes and the summary lines of a failed build.
+func (d *rustDiagnostic) parsedLine() *ParsedLine {
+	typ := d.Level
+	switch {
+	case strings.HasPrefix(typ, "error"):
+		typ = "error"
+	case typ != "warning":
+		return nil
+	}
+	if rustNoisePattern.MatchString(d.Text) {
+		return nil
+	}
+	message := d.Text
+	if d.Code != nil && d.Code.Code != "" {
+		message = "[" + d.Code.Code + "] " + message
+	}
+	parsed := &ParsedLine{Type: typ, Message: message, RawOutput: d.Rendered}
+	for _, span := range d.Spans {
+		if span.IsPrimary {
+			parsed.File = span.FileName
+			parsed.Line = span.LineStart
+			parsed.Column = span.ColumnStart
+			break
+		}
+	}
+	return parsed
+}
+
+// locatePanic sets a failed test's location to where it panicked.
+func (l *ParsedLine) locatePanic() {
+	if m := cargoPanicPattern.FindStringSubmatch(l.RawOutput); m != nil {
+		l.File, l.Line, l.Column = m[1], atoi(m[2]), atoi(m[3])
+	}
+}
+
+// ParseLine is not applicable for cargo as test failures need context.
+func (p *CargoParser) ParseLine(line string) *ParsedLine {
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCargoParser_Parse(t *testing.T) {
+	parser := &CargoParser{}
+	assert.Equal(t, "cargo", parser.Name())
+
+	t.Run("build json", func(t *testing.T) {
+		output := `{"reason":"compiler-artifact","package_id":"serde 1.0.0","target":{"name":"serde"}}
+{"reason":"compiler-message","package_id":"app 0.1.0","message":{"$message_type":"diagnostic","message":"mismatched types","code":{"code":"E0308","explanation":"..."},"level":"error","spans":[{"file_name":"src/lib.rs","line_start":4,"column_start":9,"is_primary":false},{"file_name":"src/main.rs","line_start":12,"column_start":18,"is_primary":true}],"children":[],"rendered":"error[E0308]: mismatched types\n --> src/main.rs:12:18\n"}}
+{"reason":"compiler-message","package_id":"app 0.1.0","message":{"$message_type":"diagnostic","message":"unused variable: ` + "`x`" + `","code":{"code":"unused_variables","explanation":null},"level":"warning","spans":[{"file_name":"src/main.rs","line_start":3,"column_start":9,"is_primary":true}],"children":[],"rendered":"warning: unused variable\n"}}
+{"reason":"compiler-message","package_id":"app 0.1.0","message":{"$message_type":"diagnostic","message":"aborting due to 1 previous error","code":null,"level":"error","spans":[],"children":[],"rendered":"error: aborting due to 1 previous error\n"}}
+{"reason":"build-finished","success":false}
+error: could not compile ` + "`app`" + ` (bin "app") due to 1 previous error
+`
+		assert.Equal(t, []ParsedLine{
+			{Type: "error", File: "src/main.rs", Line: 12, Column: 18, Message: "[E0308] mismatched types", RawOutput: "error[E0308]: mismatched types\n --> src/main.rs:12:18\n"},
+			{Type: "warning", File: "src/main.rs", Line: 3, Column: 9, Message: "[unused_variables] unused variable: `x`", RawOutput: "warning: unused variable\n"},
+		}, parser.Parse(output))
+	})
+
+	t.Run("rustc json", func(t *testing.T) {
+		output := `{"$message_type":"diagnostic","message":"cannot find value ` + "`y`" + ` in this scope","code":{"code":"E0425"},"level":"error","spans":[{"file_name":"main.rs","line_start":2,"column_start":5,"is_primary":true}],"rendered":"error[E0425]\n"}`
+		assert.Equal(t, []ParsedLine{
+			{Type: "error", File: "main.rs", Line: 2, Column: 5, Message: "[E0425] cannot find value `y` in this scope", RawOutput: "error[E0425]\n"},
+		}, parser.Parse(output))
+	})
+
+	t.Run("libtest json", func(t *testing.T) {
+		output := `{ "type": "suite", "event": "started", "test_count": 3 }
+{ "type": "test", "event": "started", "name": "tests::add" }
+{ "type": "test", "name": "tests::add", "event": "ok" }
+{ "type": "test", "name": "tests::sub", "event": "failed", "stdout": "thread 'tests::sub' panicked at src/lib.rs:20:9:\nassertion failed\n" }
+{ "type": "test", "name": "tests::slow", "event": "ignored" }
+{ "type": "suite", "event": "failed", "passed": 1, "failed": 1, "ignored": 1 }
+`
+		assert.Equal(t, []ParsedLine{
+			{Type: "test_pass", TestName: "tests::add"},
+			{Type: "test_fail", TestName: "tests::sub", File: "src/lib.rs", Line: 20, Column: 9, RawOutput: "thread 'tests::sub' panicked at src/lib.rs:20:9:\nassertion failed\n"},
+			{Type: "test_skip", TestName: "tests::slow"},
+		}, parser.Parse(output))
+	})
+
+	t.Run("libtest default", func(t *testing.T) {
+		output := `   Compiling app v0.1.0 (/src/app)
+    Finished test [unoptimized + debuginfo] target(s) in 0.50s
+     Running unittests src/lib.rs (target/debug/deps/app-1234)
+
+running 3 tests
+test tests::add ... ok
+test tests::slow ... ignored
+test tests::sub ... FAILED
+
+failures:
+
+---- tests::sub stdout ----
+thread 'tests::sub' panicked at src/lib.rs:20:9:
+assertion ` + "`left == right`" + ` failed
+  left: 0
+ right: 2
+
+failures:
+    tests::sub
+
+test result: FAILED. 1 passed; 1 failed; 1 ignored; 0 measured; 0 filtered out; finished in 0.00s
+`
+		assert.Equal(t, []ParsedLine{
+			{Type: "test_pass", TestName: "tests::add"},
+			{Type: "test_skip", TestName: "tests::slow"},
+			{Type: "test_fail", TestName: "tests::sub", File: "src/lib.rs", Line: 20, Column: 9,
+				RawOutput: "thread 'tests::sub' panicked at src/lib.rs:20:9:\nassertion `left == right` failed\n  left: 0\n right: 2"},
+		}, parser.Parse(output))
+	})
+}
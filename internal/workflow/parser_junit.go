@@ -0,0 +1,127 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// JUnitParser parses JUnit XML test reports, as written by most test
+// runners (pytest --junitxml, Jest, Gradle, Maven Surefire, ...). It is
+// usually pointed at the report files with the workflow's results, but
+// parses reports in the output too.
+type JUnitParser struct{}
+
+func (p *JUnitParser) Name() string {
+	return "junit"
+}
+
+// junitTestCase is a <testcase> element.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      string        `xml:"line,attr"`
+	Failures  []junitResult `xml:"failure"`
+	Errors    []junitResult `xml:"error"`
+	Skipped   *junitResult  `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+	SystemErr string        `xml:"system-err"`
+}
+
+// junitResult is a <failure>, <error> or <skipped> element.
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Parse parses the <testcase> elements of the reports in output. Reports
+// may be concatenated, and text around them is ignored.
+func (p *JUnitParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	for output != "" {
+		// Each report is decoded separately, so a broken one doesn't hide
+		// those after it
+		start := strings.Index(output, "<")
+		if start < 0 {
+			break
+		}
+		output = output[start:]
+		end := len(output)
+		if next := strings.Index(output[1:], "<?xml"); next >= 0 {
+			end = next + 1
+		}
+		result = append(result, parseJUnitReport(output[:end])...)
+		output = output[end:]
+	}
+	return result
+}
+
+// parseJUnitReport parses the test cases of a report up to the first XML
+// error.
+func parseJUnitReport(report string) []ParsedLine {
+	var result []ParsedLine
+	d := xml.NewDecoder(strings.NewReader(report))
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return result
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var tc junitTestCase
+		if err := d.DecodeElement(&tc, &start); err != nil {
+			return result
+		}
+		result = append(result, tc.parsedLine())
+	}
+}
+
+// parsedLine returns the result of a test case.
+func (tc *junitTestCase) parsedLine() ParsedLine {
+	line, _ := strconv.Atoi(tc.Line)
+	parsed := ParsedLine{
+		Type:     "test_pass",
+		File:     tc.File,
+		Line:     line,
+		Package:  tc.ClassName,
+		TestName: tc.Name,
+	}
+	problems := append(tc.Failures, tc.Errors...)
+	switch {
+	case len(problems) > 0:
+		parsed.Type = "test_fail"
+		parsed.Message = problems[0].Message
+		var raw []string
+		for _, r := range problems {
+			if text := strings.TrimSpace(r.Text); text != "" {
+				raw = append(raw, text)
+			} else if r.Message != "" {
+				raw = append(raw, r.Message)
+			}
+		}
+		for _, out := range []string{tc.SystemOut, tc.SystemErr} {
+			if out = strings.TrimSpace(out); out != "" {
+				raw = append(raw, out)
+			}
+		}
+		parsed.RawOutput = strings.Join(raw, "\n")
+	case tc.Skipped != nil:
+		parsed.Type = "test_skip"
+		parsed.Message = tc.Skipped.Message
+		parsed.RawOutput = strings.TrimSpace(tc.Skipped.Text)
+	}
+	return parsed
+}
+
+// ParseLine is not applicable for XML reports.
+func (p *JUnitParser) ParseLine(line string) *ParsedLine {
+	return nil
+}
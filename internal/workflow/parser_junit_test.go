@@ -0,0 +1,53 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnitParser_Parse(t *testing.T) {
+	parser := &JUnitParser{}
+	assert.Equal(t, "junit", parser.Name())
+
+	report := `<?xml version="1.0" encoding="utf-8"?>
+<testsuites>
+  <testsuite name="pytest" tests="4" failures="1" errors="1" skipped="1">
+    <testcase classname="tests.test_math" name="test_add" file="tests/test_math.py" line="3" time="0.001"/>
+    <testcase classname="tests.test_math" name="test_sub" file="tests/test_math.py" line="7" time="0.002">
+      <failure message="assert 1 == 2">def test_sub():
+&gt;       assert 1 == 2
+E       assert 1 == 2</failure>
+      <system-out>debug output</system-out>
+    </testcase>
+    <testcase classname="tests.test_math" name="test_div" time="0.000">
+      <error message="fixture 'db' not found"/>
+    </testcase>
+    <testcase classname="tests.test_math" name="test_slow">
+      <skipped message="too slow" type="pytest.skip">tests/test_math.py:20: too slow</skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	// A second report, with noise before it
+	output := report + "npm notice\n" + `<?xml version="1.0"?><testsuite name="jest"><testcase classname="App" name="renders"></testcase></testsuite>`
+
+	lines := parser.Parse(output)
+	assert.Equal(t, []ParsedLine{
+		{Type: "test_pass", File: "tests/test_math.py", Line: 3, Package: "tests.test_math", TestName: "test_add"},
+		{Type: "test_fail", File: "tests/test_math.py", Line: 7, Package: "tests.test_math", TestName: "test_sub",
+			Message: "assert 1 == 2", RawOutput: "def test_sub():\n>       assert 1 == 2\nE       assert 1 == 2\ndebug output"},
+		{Type: "test_fail", Package: "tests.test_math", TestName: "test_div", Message: "fixture 'db' not found", RawOutput: "fixture 'db' not found"},
+		{Type: "test_skip", Package: "tests.test_math", TestName: "test_slow", Message: "too slow", RawOutput: "tests/test_math.py:20: too slow"},
+		{Type: "test_pass", Package: "App", TestName: "renders"},
+	}, lines)
+
+	// Broken XML keeps the test cases before the error
+	lines = parser.Parse(`<testsuite><testcase classname="a" name="ok"/><testcase name="broken"><failure>`)
+	assert.Equal(t, []ParsedLine{{Type: "test_pass", Package: "a", TestName: "ok"}}, lines)
+
+	assert.Empty(t, parser.Parse("no reports here"))
+}
@@ -0,0 +1,112 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ansiPattern matches ANSI color escapes, which tools write when forced to.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// ESLintParser parses ESLint's default "stylish" output: a line naming each
+// file, followed by an indented line per problem.
+type ESLintParser struct{}
+
+func (p *ESLintParser) Name() string {
+	return "eslint"
+}
+
+// eslintProblemPattern matches: "  12:5  error  'x' is not defined  no-undef"
+var eslintProblemPattern = regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.+?)(?:\s{2,}(\S+))?$`)
+
+// Parse parses ESLint stylish output.
+func (p *ESLintParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	file := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(ansiPattern.ReplaceAllString(line, ""), " \r")
+		if line == "" {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			file = line
+			continue
+		}
+		m := eslintProblemPattern.FindStringSubmatch(line)
+		if m == nil || file == "" {
+			continue
+		}
+		message := m[4]
+		if m[5] != "" {
+			message += " (" + m[5] + ")"
+		}
+		result = append(result, ParsedLine{
+			Type:    m[3],
+			File:    file,
+			Line:    atoi(m[1]),
+			Column:  atoi(m[2]),
+			Message: message,
+		})
+	}
+	return result
+}
+
+// ParseLine is not applicable for stylish output as problems follow their
+// file's line.
+func (p *ESLintParser) ParseLine(line string) *ParsedLine {
+	return nil
+}
+
+// TSCParser parses TypeScript compiler diagnostics, in both the plain
+// "file(line,col): error TS1234: message" and the --pretty
+// "file:line:col - error TS1234: message" forms.
+type TSCParser struct{}
+
+func (p *TSCParser) Name() string {
+	return "tsc"
+}
+
+var (
+	// tscPattern matches: src/app.ts(10,5): error TS2322: message
+	tscPattern = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): (error|warning) (TS\d+: .*)$`)
+	// tscPrettyPattern matches: src/app.ts:10:5 - error TS2322: message
+	tscPrettyPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+) - (error|warning) (TS\d+: .*)$`)
+	// tscGlobalPattern matches diagnostics without a file: error TS5023: message
+	tscGlobalPattern = regexp.MustCompile(`^(error|warning) (TS\d+: .*)$`)
+)
+
+// Parse parses tsc output.
+func (p *TSCParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	for _, line := range strings.Split(output, "\n") {
+		if parsed := p.ParseLine(line); parsed != nil {
+			result = append(result, *parsed)
+		}
+	}
+	return result
+}
+
+// ParseLine parses a single tsc diagnostic.
+func (p *TSCParser) ParseLine(line string) *ParsedLine {
+	line = strings.TrimSpace(ansiPattern.ReplaceAllString(line, ""))
+	m := tscPattern.FindStringSubmatch(line)
+	if m == nil {
+		m = tscPrettyPattern.FindStringSubmatch(line)
+	}
+	if m != nil {
+		return &ParsedLine{
+			Type:    m[4],
+			File:    m[1],
+			Line:    atoi(m[2]),
+			Column:  atoi(m[3]),
+			Message: m[5],
+		}
+	}
+	if m := tscGlobalPattern.FindStringSubmatch(line); m != nil {
+		return &ParsedLine{Type: m[1], Message: m[2]}
+	}
+	return nil
+}
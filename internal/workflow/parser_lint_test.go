@@ -0,0 +1,59 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestESLintParser_Parse(t *testing.T) {
+	parser := &ESLintParser{}
+	assert.Equal(t, "eslint", parser.Name())
+
+	output := `
+> app@1.0.0 lint
+> eslint src
+
+/home/dev/app/src/index.js
+   3:7   error    'unused' is assigned a value but never used  no-unused-vars
+  10:1   warning  Unexpected console statement                 no-console
+
+/home/dev/app/src/util.js
+  1:1  error  Parsing error: Unexpected token
+
+` + "\x1b[31m\x1b[1m✖ 3 problems (2 errors, 1 warning)\x1b[22m\x1b[39m\n"
+
+	assert.Equal(t, []ParsedLine{
+		{Type: "error", File: "/home/dev/app/src/index.js", Line: 3, Column: 7, Message: "'unused' is assigned a value but never used (no-unused-vars)"},
+		{Type: "warning", File: "/home/dev/app/src/index.js", Line: 10, Column: 1, Message: "Unexpected console statement (no-console)"},
+		{Type: "error", File: "/home/dev/app/src/util.js", Line: 1, Column: 1, Message: "Parsing error: Unexpected token"},
+	}, parser.Parse(output))
+}
+
+func TestTSCParser_Parse(t *testing.T) {
+	parser := &TSCParser{}
+	assert.Equal(t, "tsc", parser.Name())
+
+	output := `src/app.ts(10,5): error TS2322: Type 'string' is not assignable to type 'number'.
+src/util.ts:3:14 - error TS2304: Cannot find name 'foo'.
+
+3 export const x = foo;
+               ~~~
+error TS5023: Unknown compiler option 'strictest'.
+
+Found 3 errors in 2 files.
+`
+	assert.Equal(t, []ParsedLine{
+		{Type: "error", File: "src/app.ts", Line: 10, Column: 5, Message: "TS2322: Type 'string' is not assignable to type 'number'."},
+		{Type: "error", File: "src/util.ts", Line: 3, Column: 14, Message: "TS2304: Cannot find name 'foo'."},
+		{Type: "error", Message: "TS5023: Unknown compiler option 'strictest'."},
+	}, parser.Parse(output))
+
+	// Pretty output is colored
+	assert.Equal(t, &ParsedLine{Type: "error", File: "src/a.ts", Line: 1, Column: 2, Message: "TS1005: ';' expected."},
+		parser.ParseLine("\x1b[96msrc/a.ts\x1b[0m:\x1b[93m1\x1b[0m:\x1b[93m2\x1b[0m - \x1b[91merror\x1b[0m\x1b[90m TS1005: \x1b[0m';' expected."))
+	assert.Nil(t, parser.ParseLine("Found 3 errors in 2 files."))
+}
@@ -0,0 +1,153 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PytestParser parses pytest's terminal output. Passing tests are only
+// listed with -v (or -rA); failures and errors come from the verbose lines,
+// the "short test summary info" and the failure sections, whose traceback
+// becomes the test's output.
+type PytestParser struct{}
+
+func (p *PytestParser) Name() string {
+	return "pytest"
+}
+
+var (
+	// pytestVerbosePattern matches: tests/test_app.py::test_add PASSED [ 50%]
+	pytestVerbosePattern = regexp.MustCompile(`^(\S+?\.py)::(\S+) (PASSED|FAILED|ERROR|SKIPPED|XFAIL|XPASS)\b`)
+	// pytestSummaryPattern matches: FAILED tests/test_app.py::test_add - assert 1 == 2
+	pytestSummaryPattern = regexp.MustCompile(`^(PASSED|FAILED|ERROR|SKIPPED|XFAIL|XPASS) (\S+?\.py)(?:::(\S+))?(?: - (.*))?$`)
+	// pytestSkipPattern matches: SKIPPED [2] tests/test_app.py:12: reason
+	pytestSkipPattern = regexp.MustCompile(`^SKIPPED \[(\d+)\] (\S+?\.py):(\d+): (.*)$`)
+	// pytestSectionPattern matches a failure section header: ____ test_add ____
+	pytestSectionPattern = regexp.MustCompile(`^_{3,} (?:ERROR (?:at \w+ of |collecting ))?(.+?) _{3,}$`)
+	// pytestHeaderPattern matches the headers between parts of the report.
+	pytestHeaderPattern = regexp.MustCompile(`^={3,} .* ={3,}$`)
+	// pytestLocationPattern matches where a failure was raised: tests/test_app.py:12: AssertionError
+	pytestLocationPattern = regexp.MustCompile(`^(\S+?\.py):(\d+): (\w+)`)
+)
+
+// Parse parses pytest output.
+func (p *PytestParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	index := make(map[string]int) // Result index by node ID
+	sections := make(map[string]*pytestSection)
+	var section *pytestSection
+	var skips []ParsedLine // Skips from the summary, which only counts them
+	verboseSkips := false
+
+	add := func(outcome, file, name, message string) {
+		id := file
+		if name != "" {
+			id += "::" + name
+		}
+		parsed := ParsedLine{TestName: id, File: file, Message: message}
+		switch outcome {
+		case "PASSED", "XPASS":
+			parsed.Type = "test_pass"
+		case "FAILED":
+			parsed.Type = "test_fail"
+		case "ERROR":
+			// Errors in fixtures fail the test; collection errors have
+			// no test
+			parsed.Type = "test_fail"
+			if name == "" {
+				parsed = ParsedLine{Type: "error", File: file, Message: message}
+			}
+		default:
+			parsed.Type = "test_skip"
+		}
+		if i, ok := index[id]; ok {
+			// The summary repeats the verbose line, with the message
+			if result[i].Message == "" {
+				result[i].Message = message
+			}
+			return
+		}
+		index[id] = len(result)
+		result = append(result, parsed)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(ansiPattern.ReplaceAllString(line, ""), " \r")
+		if m := pytestSectionPattern.FindStringSubmatch(line); m != nil {
+			section = &pytestSection{}
+			sections[m[1]] = section
+			continue
+		}
+		if pytestHeaderPattern.MatchString(line) {
+			section = nil
+			continue
+		}
+		if section != nil {
+			section.lines = append(section.lines, line)
+			if m := pytestLocationPattern.FindStringSubmatch(line); m != nil {
+				section.file, section.line = m[1], atoi(m[2])
+			}
+			continue
+		}
+
+		if m := pytestVerbosePattern.FindStringSubmatch(line); m != nil {
+			add(m[3], m[1], m[2], "")
+			verboseSkips = verboseSkips || m[3] == "SKIPPED" || m[3] == "XFAIL"
+		} else if m := pytestSkipPattern.FindStringSubmatch(line); m != nil {
+			for i := 0; i < atoi(m[1]); i++ {
+				skips = append(skips, ParsedLine{
+					Type:     "test_skip",
+					File:     m[2],
+					Line:     atoi(m[3]),
+					TestName: m[2] + ":" + m[3],
+					Message:  m[4],
+				})
+			}
+		} else if m := pytestSummaryPattern.FindStringSubmatch(line); m != nil {
+			add(m[1], m[2], m[3], m[4])
+		}
+	}
+
+	// Verbose output names the skipped tests the summary counts
+	if !verboseSkips {
+		result = append(result, skips...)
+	}
+
+	// Attach each failure section to its test, named by the last part of
+	// its node ID with "::" as "."
+	for i := range result {
+		r := &result[i]
+		if r.Type != "test_fail" && r.Type != "error" {
+			continue
+		}
+		name := r.File
+		if _, test, ok := strings.Cut(r.TestName, "::"); ok {
+			name = strings.ReplaceAll(test, "::", ".")
+		}
+		s, ok := sections[name]
+		if !ok {
+			continue
+		}
+		r.RawOutput = strings.TrimSpace(strings.Join(s.lines, "\n"))
+		if s.file != "" {
+			r.File, r.Line = s.file, s.line
+		}
+	}
+	return result
+}
+
+// pytestSection is the report of a failure.
+type pytestSection struct {
+	lines []string
+	file  string // Where the failure was raised
+	line  int
+}
+
+// ParseLine is not applicable for pytest as results are spread over the
+// report.
+func (p *PytestParser) ParseLine(line string) *ParsedLine {
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPytestParser_Parse(t *testing.T) {
+	parser := &PytestParser{}
+	assert.Equal(t, "pytest", parser.Name())
+
+	t.Run("verbose", func(t *testing.T) {
+		output := `============================= test session starts ==============================
+platform linux -- Python 3.12.1, pytest-8.0.0
+collected 5 items
+
+tests/test_math.py::test_add PASSED                                      [ 20%]
+tests/test_math.py::test_sub FAILED                                      [ 40%]
+tests/test_math.py::TestDiv::test_zero SKIPPED (no division)             [ 60%]
+tests/test_math.py::test_param[1-2] PASSED                               [ 80%]
+tests/test_db.py::test_query ERROR                                       [100%]
+
+==================================== ERRORS ====================================
+________________________ ERROR at setup of test_query _________________________
+file /src/tests/test_db.py, line 4
+  def test_query(db):
+E       fixture 'db' not found
+/src/tests/test_db.py:4
+=================================== FAILURES ===================================
+___________________________________ test_sub ___________________________________
+
+    def test_sub():
+>       assert 1 - 1 == 2
+E       assert 0 == 2
+
+tests/test_math.py:7: AssertionError
+=========================== short test summary info ============================
+FAILED tests/test_math.py::test_sub - assert 0 == 2
+ERROR tests/test_db.py::test_query
+============= 1 failed, 2 passed, 1 skipped, 1 error in 0.05s ==============
+`
+		lines := parser.Parse(output)
+		assert.Equal(t, []ParsedLine{
+			{Type: "test_pass", File: "tests/test_math.py", TestName: "tests/test_math.py::test_add"},
+			{Type: "test_fail", File: "tests/test_math.py", Line: 7, TestName: "tests/test_math.py::test_sub", Message: "assert 0 == 2",
+				RawOutput: "def test_sub():\n>       assert 1 - 1 == 2\nE       assert 0 == 2\n\ntests/test_math.py:7: AssertionError"},
+			{Type: "test_skip", File: "tests/test_math.py", TestName: "tests/test_math.py::TestDiv::test_zero"},
+			{Type: "test_pass", File: "tests/test_math.py", TestName: "tests/test_math.py::test_param[1-2]"},
+			{Type: "test_fail", File: "tests/test_db.py", TestName: "tests/test_db.py::test_query",
+				RawOutput: "file /src/tests/test_db.py, line 4\n  def test_query(db):\nE       fixture 'db' not found\n/src/tests/test_db.py:4"},
+		}, lines)
+
+		s := Summarize(lines)
+		assert.Equal(t, 2, s.TestsPassed)
+		assert.Equal(t, 2, s.TestsFailed)
+		assert.Equal(t, []string{"tests/test_math.py::test_sub", "tests/test_db.py::test_query"}, s.FailedTests)
+	})
+
+	t.Run("quiet", func(t *testing.T) {
+		output := `..sF                                                                     [100%]
+=================================== FAILURES ===================================
+_________________________________ TestApi.test_get _______________________________
+
+    def test_get(self):
+>       assert resp.status == 200
+E       assert 500 == 200
+
+tests/test_api.py:21: AssertionError
+=========================== short test summary info ============================
+SKIPPED [2] tests/test_api.py:30: needs network
+FAILED tests/test_api.py::TestApi::test_get - assert 500 == 200
+ERROR tests/test_broken.py - ModuleNotFoundError: No module named 'requests'
+=================== 1 failed, 2 passed, 2 skipped in 0.12s ====================
+`
+		lines := parser.Parse(output)
+		assert.Equal(t, []ParsedLine{
+			{Type: "test_fail", File: "tests/test_api.py", Line: 21, TestName: "tests/test_api.py::TestApi::test_get", Message: "assert 500 == 200",
+				RawOutput: "def test_get(self):\n>       assert resp.status == 200\nE       assert 500 == 200\n\ntests/test_api.py:21: AssertionError"},
+			{Type: "error", File: "tests/test_broken.py", Message: "ModuleNotFoundError: No module named 'requests'"},
+			{Type: "test_skip", File: "tests/test_api.py", Line: 30, TestName: "tests/test_api.py:30", Message: "needs network"},
+			{Type: "test_skip", File: "tests/test_api.py", Line: 30, TestName: "tests/test_api.py:30", Message: "needs network"},
+		}, lines)
+	})
+}
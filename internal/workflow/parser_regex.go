@@ -0,0 +1,122 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegexRule maps the output lines matching Pattern to parsed lines of Type.
+// The pattern's named groups file, line, col, message, test and package
+// fill in the parsed line; without a message group, the message is the
+// whole line.
+type RegexRule struct {
+	Pattern string
+	Type    string // "error", "warning", "test_pass", "test_fail" or "test_skip"
+}
+
+// RegexParser is an output parser declared in the config: each line is
+// parsed by the first of its rules that matches.
+type RegexParser struct {
+	name  string
+	rules []regexRule
+}
+
+type regexRule struct {
+	re  *regexp.Regexp
+	typ string
+}
+
+// regexParserGroups are the named groups a rule's pattern may use.
+var regexParserGroups = map[string]bool{
+	"file":    true,
+	"line":    true,
+	"col":     true,
+	"message": true,
+	"test":    true,
+	"package": true,
+}
+
+// regexParserTypes are the types of parsed lines a rule may produce.
+var regexParserTypes = map[string]bool{
+	"error":     true,
+	"warning":   true,
+	"test_pass": true,
+	"test_fail": true,
+	"test_skip": true,
+}
+
+// NewRegexParser creates a parser named name from rules.
+func NewRegexParser(name string, rules []RegexRule) (*RegexParser, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("parser %s has no rules", name)
+	}
+	p := &RegexParser{name: name}
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parser %s rule %d: %w", name, i, err)
+		}
+		for _, group := range re.SubexpNames() {
+			if group != "" && !regexParserGroups[group] {
+				return nil, fmt.Errorf("parser %s rule %d: unknown group %q", name, i, group)
+			}
+		}
+		if !regexParserTypes[rule.Type] {
+			return nil, fmt.Errorf("parser %s rule %d: invalid type %q", name, i, rule.Type)
+		}
+		p.rules = append(p.rules, regexRule{re: re, typ: rule.Type})
+	}
+	return p, nil
+}
+
+func (p *RegexParser) Name() string {
+	return p.name
+}
+
+// Parse parses output line by line.
+func (p *RegexParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	for _, line := range strings.Split(output, "\n") {
+		if parsed := p.ParseLine(line); parsed != nil {
+			result = append(result, *parsed)
+		}
+	}
+	return result
+}
+
+// ParseLine parses a line with the first rule that matches it.
+func (p *RegexParser) ParseLine(line string) *ParsedLine {
+	line = strings.TrimRight(ansiPattern.ReplaceAllString(line, ""), " \r")
+	if line == "" {
+		return nil
+	}
+	for _, rule := range p.rules {
+		m := rule.re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		parsed := &ParsedLine{Type: rule.typ, Message: strings.TrimSpace(line)}
+		for i, group := range rule.re.SubexpNames() {
+			switch group {
+			case "file":
+				parsed.File = m[i]
+			case "line":
+				parsed.Line = atoi(m[i])
+			case "col":
+				parsed.Column = atoi(m[i])
+			case "message":
+				parsed.Message = strings.TrimSpace(m[i])
+			case "test":
+				parsed.TestName = m[i]
+			case "package":
+				parsed.Package = m[i]
+			}
+		}
+		return parsed
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexParser_Parse(t *testing.T) {
+	parser, err := NewRegexParser("phpunit", []RegexRule{
+		{Pattern: `^(?P<file>\S+\.php):(?P<line>\d+):(?P<col>\d+) (?P<message>.*) \[error\]$`, Type: "error"},
+		{Pattern: `^(?P<file>\S+\.php):(?P<line>\d+) .*\[warn\]$`, Type: "warning"},
+		{Pattern: `^✔ (?P<package>\w+)::(?P<test>\w+)`, Type: "test_pass"},
+		{Pattern: `^✘ (?P<package>\w+)::(?P<test>\w+)(?: - (?P<message>.*))?`, Type: "test_fail"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "phpunit", parser.Name())
+
+	output := `src/App.php:12:5 Undefined variable $x [error]
+src/Db.php:40 Deprecated call [warn]
+✔ AppTest::testIndex
+✘ AppTest::testShow - expected 200, got 500
+Time: 00:00.012
+`
+	assert.Equal(t, []ParsedLine{
+		{Type: "error", File: "src/App.php", Line: 12, Column: 5, Message: "Undefined variable $x"},
+		{Type: "warning", File: "src/Db.php", Line: 40, Message: "src/Db.php:40 Deprecated call [warn]"},
+		{Type: "test_pass", Package: "AppTest", TestName: "testIndex", Message: "✔ AppTest::testIndex"},
+		{Type: "test_fail", Package: "AppTest", TestName: "testShow", Message: "expected 200, got 500"},
+	}, parser.Parse(output))
+}
+
+func TestNewRegexParser_Errors(t *testing.T) {
+	tests := []struct {
+		rules []RegexRule
+		err   string
+	}{
+		{nil, "has no rules"},
+		{[]RegexRule{{Pattern: "(", Type: "error"}}, "rule 0"},
+		{[]RegexRule{{Pattern: "x", Type: "error"}, {Pattern: "(?P<path>.*)", Type: "error"}}, `rule 1: unknown group "path"`},
+		{[]RegexRule{{Pattern: "x", Type: "fatal"}}, `invalid type "fatal"`},
+	}
+	for _, tt := range tests {
+		_, err := NewRegexParser("custom", tt.rules)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tt.err)
+	}
+}
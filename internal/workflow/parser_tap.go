@@ -0,0 +1,107 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TAPParser parses Test Anything Protocol output, as written by node --test,
+// prove, bats and tape. Subtests are indented under their parent; only the
+// innermost tests are reported, so a parent doesn't count twice.
+type TAPParser struct{}
+
+func (p *TAPParser) Name() string {
+	return "tap"
+}
+
+// tapTestPattern matches a test point: "ok 1 - description # SKIP reason".
+// The number, description and directive are optional.
+var tapTestPattern = regexp.MustCompile(`^(ok|not ok)\b(?:\s+\d+)?(?:\s*-)?\s*(.*?)(?:\s+#\s*((?i:skip|todo))\S*\s*(.*))?$`)
+
+// tapLocationPattern matches a location in a YAML diagnostic block, e.g.
+// "location: '/src/app.test.js:12:3'" or "at: app.test.js:12:3".
+var tapLocationPattern = regexp.MustCompile(`^(?:location|at):\s*['"]?(.+?):(\d+)(?::(\d+))?['"]?\)?$`)
+
+// Parse parses TAP output.
+func (p *TAPParser) Parse(output string) []ParsedLine {
+	var result []ParsedLine
+	parent := make(map[int]bool) // Indents with a test point deeper since their last
+	var last *ParsedLine         // Last failed test, collecting its diagnostics
+	lastIndent := 0
+	inYAML := false
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		// YAML diagnostics follow a test point, indented by two more spaces
+		if last != nil && indent > lastIndent && (inYAML || trimmed == "---") {
+			switch trimmed {
+			case "---":
+				inYAML = true
+			case "...":
+				inYAML, last = false, nil
+			default:
+				last.RawOutput += trimmed + "\n"
+				if m := tapLocationPattern.FindStringSubmatch(trimmed); m != nil && last.File == "" {
+					last.File = m[1]
+					last.Line = atoi(m[2])
+					last.Column = atoi(m[3])
+				}
+				if msg, ok := strings.CutPrefix(trimmed, "message:"); ok && last.Message == "" {
+					last.Message = strings.Trim(strings.TrimSpace(msg), `'"`)
+				}
+			}
+			continue
+		}
+		inYAML, last = false, nil
+
+		if strings.HasPrefix(trimmed, "Bail out!") {
+			result = append(result, ParsedLine{Type: "error", Message: trimmed})
+			continue
+		}
+		m := tapTestPattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+
+		isParent := parent[indent]
+		for i := range parent {
+			if i >= indent {
+				delete(parent, i)
+			}
+		}
+		for i := 0; i < indent; i++ {
+			parent[i] = true
+		}
+		if isParent {
+			continue
+		}
+
+		parsed := ParsedLine{Type: "test_pass", TestName: m[2]}
+		switch {
+		case m[3] != "":
+			// Skipped tests and failing TODO tests don't fail the run
+			if m[1] == "not ok" || strings.EqualFold(m[3], "SKIP") {
+				parsed.Type = "test_skip"
+				parsed.Message = m[4]
+			}
+		case m[1] == "not ok":
+			parsed.Type = "test_fail"
+		}
+		result = append(result, parsed)
+		if parsed.Type == "test_fail" {
+			last = &result[len(result)-1]
+			lastIndent = indent
+		}
+	}
+	return result
+}
+
+// ParseLine is not applicable for TAP as subtests need context.
+func (p *TAPParser) ParseLine(line string) *ParsedLine {
+	return nil
+}
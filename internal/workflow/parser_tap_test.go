@@ -0,0 +1,88 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTAPParser_Parse(t *testing.T) {
+	parser := &TAPParser{}
+	assert.Equal(t, "tap", parser.Name())
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []ParsedLine
+	}{
+		{
+			name: "flat",
+			output: `TAP version 13
+1..5
+ok 1 - adds numbers
+not ok 2 - subtracts numbers
+  ---
+  message: 'expected 3 to equal 2'
+  at: test/math.js:12:5
+  ...
+ok 3 - divides # SKIP no division yet
+not ok 4 - multiplies # TODO later
+ok 5
+`,
+			expected: []ParsedLine{
+				{Type: "test_pass", TestName: "adds numbers"},
+				{Type: "test_fail", TestName: "subtracts numbers", File: "test/math.js", Line: 12, Column: 5, Message: "expected 3 to equal 2",
+					RawOutput: "message: 'expected 3 to equal 2'\nat: test/math.js:12:5\n"},
+				{Type: "test_skip", TestName: "divides", Message: "no division yet"},
+				{Type: "test_skip", TestName: "multiplies", Message: "later"},
+				{Type: "test_pass", TestName: ""},
+			},
+		},
+		{
+			name: "node subtests",
+			output: `TAP version 13
+# Subtest: math
+    # Subtest: adds
+    ok 1 - adds
+      ---
+      duration_ms: 0.5
+      ...
+    # Subtest: subtracts
+    not ok 2 - subtracts
+      ---
+      duration_ms: 1.2
+      location: '/src/test/math.test.js:8:3'
+      failureType: 'testCodeFailure'
+      ...
+    1..2
+not ok 1 - math
+  ---
+  duration_ms: 3.1
+  ...
+# Subtest: standalone
+ok 2 - standalone
+1..2
+# tests 3
+`,
+			expected: []ParsedLine{
+				{Type: "test_pass", TestName: "adds"},
+				{Type: "test_fail", TestName: "subtracts", File: "/src/test/math.test.js", Line: 8, Column: 3,
+					RawOutput: "duration_ms: 1.2\nlocation: '/src/test/math.test.js:8:3'\nfailureType: 'testCodeFailure'\n"},
+				{Type: "test_pass", TestName: "standalone"},
+			},
+		},
+		{
+			name:     "bail out",
+			output:   "1..3\nok 1 - first\nBail out! database unavailable\n",
+			expected: []ParsedLine{{Type: "test_pass", TestName: "first"}, {Type: "error", Message: "Bail out! database unavailable"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parser.Parse(tt.output))
+		})
+	}
+}
@@ -277,6 +277,12 @@ func TestParserRegistry_Get(t *testing.T) {
 		{"generic", "generic"},
 		{"none", "none"},
 		{"html", "html"},
+		{"junit", "junit"},
+		{"tap", "tap"},
+		{"eslint", "eslint"},
+		{"tsc", "tsc"},
+		{"pytest", "pytest"},
+		{"cargo", "cargo"},
 		{"unknown", "none"}, // Falls back to none
 		{"", "none"},        // Empty falls back to none
 	}
@@ -396,6 +402,35 @@ func TestFormatTestResults(t *testing.T) {
 	assert.Contains(t, html, "openFileAtLine")
 }
 
+func TestFormatParsedOutput(t *testing.T) {
+	parsedLines := []ParsedLine{
+		{Type: "error", File: "src/app.ts", Line: 10, Column: 5, Message: "TS2322: <bad> type"},
+		{Type: "warning", Message: "deprecated option"},
+		{Type: "test_pass", TestName: "tests/test_app.py::test_ok"},
+		{Type: "test_fail", TestName: "tests/test_app.py::test_bad", File: "tests/test_app.py", Line: 7, Message: "assert 0 == 2"},
+		{Type: "test_skip", TestName: "tests/test_app.py::test_slow", Message: "too slow"},
+	}
+	html := FormatOutput("raw <output>\n", parsedLines, "pytest")
+
+	// Problems, linked to their files and escaped
+	assert.Contains(t, html, "<strong>Problems</strong>")
+	assert.Contains(t, html, "openFileAtLine('src/app.ts', 10, 5); return false;\">src/app.ts:10:5</a>: TS2322: &lt;bad&gt; type")
+	assert.Contains(t, html, "deprecated option")
+
+	// Test results, without an empty package
+	assert.Contains(t, html, "1/3 tests passed")
+	assert.Contains(t, html, "✗</span> tests/test_app.py::test_bad <a href=\"#\" class=\"text-danger\" onclick=\"openFileAtLine('tests/test_app.py', 7, 0)")
+	assert.Contains(t, html, " - assert 0 == 2")
+	assert.Contains(t, html, "tests/test_app.py::test_slow - <span class=\"text-muted\">too slow</span>")
+
+	// The output, collapsed and escaped
+	assert.Contains(t, html, "<details><summary style=\"cursor: pointer;\"><strong>Output</strong>")
+	assert.Contains(t, html, "raw &lt;output&gt;")
+
+	// Nothing parsed yet, as while streaming
+	assert.Equal(t, "raw &lt;output&gt;<br>", FormatOutput("raw <output>\n", nil, "pytest"))
+}
+
 func TestExtractSkipReason(t *testing.T) {
 	tests := []struct {
 		name     string
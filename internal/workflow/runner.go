@@ -9,8 +9,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -18,6 +20,7 @@ import (
 	"time"
 
 	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // Built-in workflow IDs
@@ -32,6 +35,13 @@ const (
 // How long to keep completed run states for polling
 const completedRunTTL = 60 * time.Second
 
+// maxOutputSize limits the output kept of a run.
+const maxOutputSize = 10 * 1024 * 1024 // 10MB
+
+// formatThrottle limits how often running output is formatted as HTML, to
+// avoid O(n²) formatting of large outputs.
+const formatThrottle = 100 * time.Millisecond
+
 // RealRunner implements the Runner interface.
 type RealRunner struct {
 	mu          sync.RWMutex
@@ -40,6 +50,8 @@ type RealRunner struct {
 	svc         ServiceController
 	parsers     *ParserRegistry
 	workingDir  string
+	secrets     *secrets.Manager
+	history     *History
 	currentRuns map[string]*runState
 	cancelFuncs map[string]context.CancelFunc
 	done        chan struct{} // signals shutdown to background goroutines
@@ -174,6 +186,46 @@ func (r *RealRunner) UpdateConfig(workflows []WorkflowConfig, workingDir string)
 	}
 }
 
+// SetSecrets sets the manager that resolves secrets in workflow
+// environments and redacts them from workflow output.
+func (r *RealRunner) SetSecrets(m *secrets.Manager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secrets = m
+}
+
+// SetHistory sets the store completed runs are recorded in.
+func (r *RealRunner) SetHistory(h *History) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = h
+}
+
+// SetParsers sets the output parsers declared in the config, in addition to
+// the built-in ones. Runs in flight keep the parsers they started with.
+func (r *RealRunner) SetParsers(parsers []OutputParser) {
+	registry := NewParserRegistry()
+	for _, p := range parsers {
+		registry.Register(p)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.parsers = registry
+}
+
+// record stores a completed run in the history, if there is one.
+func (r *RealRunner) record(status *WorkflowStatus) {
+	r.mu.RLock()
+	h := r.history
+	r.mu.RUnlock()
+	if h == nil {
+		return
+	}
+	if err := h.Record(status); err != nil {
+		log.Printf("Warning: failed to record workflow run %s: %v", status.ID, err)
+	}
+}
+
 func (r *RealRunner) registerBuiltins() {
 	r.workflows[BuiltinStartAll] = WorkflowConfig{
 		ID:   BuiltinStartAll,
@@ -250,11 +302,14 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 	// Create run state
 	runID := fmt.Sprintf("%s-%d", id, time.Now().UnixNano())
 	status := &WorkflowStatus{
-		ID:        runID,
-		Name:      wf.Name,
-		Worktree:  opts.Worktree,
-		State:     StateRunning,
-		StartedAt: time.Now(),
+		ID:         runID,
+		WorkflowID: id,
+		Name:       wf.Name,
+		Worktree:   opts.Worktree,
+		Inputs:     opts.Inputs,
+		State:      StateRunning,
+		StartedAt:  time.Now(),
+		Trigger:    opts.Trigger,
 	}
 
 	state := &runState{
@@ -281,10 +336,15 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 	}()
 
 	// Emit started event
-	r.emitEvent(ctx, "workflow.started", map[string]interface{}{
+	started := map[string]interface{}{
 		"workflow_id": runID,
 		"name":        wf.Name,
-	})
+	}
+	if opts.Trigger != nil {
+		started["trigger"] = string(opts.Trigger.Type)
+		started["trigger_detail"] = opts.Trigger.Detail
+	}
+	r.emitEvent(ctx, "workflow.started", started)
 
 	// Run asynchronously
 	go func() {
@@ -300,6 +360,35 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 			r.mu.Unlock()
 		}()
 
+		// Note the code the run is of, for the history
+		workDir := opts.WorkingDir
+		if workDir == "" {
+			r.mu.RLock()
+			workDir = r.workingDir
+			r.mu.RUnlock()
+		}
+		commit, dirty := headState(runCtx, workDir)
+		state.mu.Lock()
+		status.Commit = commit
+		status.Dirty = dirty
+		state.mu.Unlock()
+
+		r.mu.RLock()
+		h := r.history
+		r.mu.RUnlock()
+
+		// Restore the results of a recorded run with the same cache key
+		// instead of running again
+		if wf.CacheKey != nil && h != nil && r.restoreRun(h, wf, state, opts.Inputs, workDir) {
+			state.mu.RLock()
+			statusCopy := status.clone()
+			state.mu.RUnlock()
+			r.emitFinished(runCtx, statusCopy, wf)
+			r.record(statusCopy)
+			state.notifyComplete(runID, statusCopy)
+			return
+		}
+
 		// Stop required services
 		if len(wf.RequiresStopped) > 0 && r.svc != nil {
 			if err := r.svc.StopServices(runCtx, wf.RequiresStopped); err != nil {
@@ -308,16 +397,20 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 				status.Error = fmt.Sprintf("failed to stop services: %v", err)
 				status.FinishedAt = time.Now()
 				status.Duration = status.FinishedAt.Sub(status.StartedAt)
-				statusCopy := *status
+				statusCopy := status.clone()
 				state.mu.Unlock()
-				r.emitFinished(runCtx, &statusCopy, wf)
-				state.notifyComplete(runID, &statusCopy)
+				r.emitFinished(runCtx, statusCopy, wf)
+				r.record(statusCopy)
+				state.notifyComplete(runID, statusCopy)
 				return
 			}
 		}
 
 		// Execute the workflow with streaming output
 		r.executeStreaming(runCtx, wf, state, opts)
+		if h != nil {
+			r.keepArtifacts(h, state, wf.Artifacts, workDir)
+		}
 
 		// Restart services if configured and successful
 		state.mu.RLock()
@@ -337,10 +430,11 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 
 		// Emit finished event and notify subscribers
 		state.mu.RLock()
-		statusCopy := *status
+		statusCopy := status.clone()
 		state.mu.RUnlock()
-		r.emitFinished(runCtx, &statusCopy, wf)
-		state.notifyComplete(runID, &statusCopy)
+		r.emitFinished(runCtx, statusCopy, wf)
+		r.record(statusCopy)
+		state.notifyComplete(runID, statusCopy)
 	}()
 
 	// Return immediately with the initial status
@@ -348,6 +442,10 @@ func (r *RealRunner) RunWithOptions(ctx context.Context, id string, opts RunOpti
 }
 
 func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, state *runState, opts RunOptions) {
+	if len(wf.Steps) > 0 {
+		r.executeSteps(ctx, wf, state, opts)
+		return
+	}
 	status := state.status
 
 	// Apply timeout if configured
@@ -386,13 +484,36 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 	}
 
 	// Set working directory
+	r.mu.RLock()
 	workDir := r.workingDir
+	sm := r.secrets
+	parsers := r.parsers
+	r.mu.RUnlock()
 	if opts.WorkingDir != "" {
 		workDir = opts.WorkingDir
 	}
 
+	// Build the environment once for all commands: the workflow's env file
+	// and env with secrets resolved, then the run's own variables
+	var env []string
+	if wf.EnvFile != "" || len(wf.Env) > 0 || len(opts.Env) > 0 {
+		var err error
+		env, err = sm.Environ(execCtx, wf.EnvFile, workDir, wf.Env)
+		if err != nil {
+			state.mu.Lock()
+			status.State = StateFailed
+			status.Error = sm.Redact(fmt.Sprintf("failed to build environment: %v", err))
+			status.FinishedAt = time.Now()
+			status.Duration = status.FinishedAt.Sub(status.StartedAt)
+			state.mu.Unlock()
+			return
+		}
+		for k, v := range opts.Env {
+			env = append(env, k+"="+v)
+		}
+	}
+
 	// Output tracking across all commands
-	const maxOutputSize = 10 * 1024 * 1024 // 10MB limit
 	var outputBuilder strings.Builder
 	outputTruncated := false
 
@@ -427,30 +548,7 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 			state.mu.Unlock()
 		}
 
-		cmd := exec.CommandContext(execCtx, cmdArgs[0], cmdArgs[1:]...)
-		if workDir != "" {
-			cmd.Dir = workDir
-		}
-
-		// Run in its own process group so cancel/timeout kills descendants
-		// (make/go test/script children) too, not just the direct child.
-		// Mirrors the service process path.
-		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-		cmd.Cancel = func() error {
-			err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-			if err == syscall.ESRCH {
-				return os.ErrProcessDone
-			}
-			return err
-		}
-
-		// Apply environment variables from options
-		if len(opts.Env) > 0 {
-			cmd.Env = os.Environ()
-			for k, v := range opts.Env {
-				cmd.Env = append(cmd.Env, k+"="+v)
-			}
-		}
+		cmd := commandFor(execCtx, cmdArgs, workDir, env)
 
 		// Create pipes for streaming output
 		stdout, err := cmd.StdoutPipe()
@@ -492,7 +590,6 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 
 		// Throttle HTML formatting to avoid O(n²) on large outputs
 		var lastFormatTime time.Time
-		const formatThrottle = 100 * time.Millisecond
 
 		streamOutput := func(reader io.Reader) {
 			defer wg.Done()
@@ -501,7 +598,7 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 			scanner.Buffer(buf, 1024*1024)
 
 			for scanner.Scan() {
-				line := scanner.Text() + "\n"
+				line := sm.Redact(scanner.Text()) + "\n"
 				state.mu.Lock()
 				if !outputTruncated {
 					if outputBuilder.Len()+len(line) > maxOutputSize {
@@ -577,8 +674,7 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 			}
 			// Parse and format output even on failure
 			if wf.OutputParser != "" {
-				parser := r.parsers.Get(wf.OutputParser)
-				status.ParsedLines = parser.Parse(status.Output)
+				status.ParsedLines = parseOutput(parsers.Get(wf.OutputParser), status.Output, wf.Results, workDir, status.StartedAt)
 				status.Summary = Summarize(status.ParsedLines)
 			}
 			status.OutputHTML = FormatOutput(status.Output, status.ParsedLines, wf.OutputParser)
@@ -608,8 +704,7 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 
 	// Parse output if parser configured
 	if wf.OutputParser != "" {
-		parser := r.parsers.Get(wf.OutputParser)
-		status.ParsedLines = parser.Parse(status.Output)
+		status.ParsedLines = parseOutput(parsers.Get(wf.OutputParser), status.Output, wf.Results, workDir, status.StartedAt)
 		status.Summary = Summarize(status.ParsedLines)
 	}
 
@@ -618,6 +713,144 @@ func (r *RealRunner) executeStreaming(ctx context.Context, wf WorkflowConfig, st
 	state.mu.Unlock()
 }
 
+// restoreRun restores the results and artifacts of the newest successful
+// run recorded with the same cache key as this one, reporting whether
+// there was one. The run's key is set either way, so a run that isn't
+// restored can be restored from later.
+func (r *RealRunner) restoreRun(h *History, wf WorkflowConfig, state *runState, inputs map[string]any, workDir string) bool {
+	status := state.status
+	state.mu.RLock()
+	commit, dirty := status.Commit, status.Dirty
+	state.mu.RUnlock()
+
+	key, err := cacheKey(wf.CacheKey, wf, inputs, workDir, commit, dirty)
+	if err != nil {
+		log.Printf("Warning: failed to compute cache key of workflow %s: %v", wf.ID, err)
+		return false
+	}
+	state.mu.Lock()
+	status.CacheKey = key
+	state.mu.Unlock()
+
+	cached, _ := h.Cached(wf.ID, "", key)
+	if cached == nil {
+		return false
+	}
+	restored, err := h.RestoreArtifacts(cached.ID, status.ID, "", workDir, cached.Artifacts)
+	if err != nil {
+		log.Printf("Warning: failed to restore workflow run %s: %v", cached.ID, err)
+		return false
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	status.CachedFrom = cached.ID
+	status.Output = cached.Output
+	status.OutputHTML = cached.OutputHTML
+	status.ParsedLines = cached.ParsedLines
+	status.Summary = cached.Summary
+	status.Steps = cloneSteps(cached.Steps)
+	status.Artifacts = restored
+	status.Coverage = cached.Coverage
+	status.State = StateSuccess
+	status.Success = true
+	status.FinishedAt = time.Now()
+	status.Duration = status.FinishedAt.Sub(status.StartedAt)
+	return true
+}
+
+// keepArtifacts keeps the files matching a workflow's artifacts globs with
+// its run, then summarizes the coverage files among everything the run and
+// its steps kept.
+func (r *RealRunner) keepArtifacts(h *History, state *runState, patterns []string, workDir string) {
+	status := state.status
+	artifacts, err := h.CollectArtifacts(status.ID, workDir, patterns, status.StartedAt, "")
+	if err != nil {
+		log.Printf("Warning: workflow run %s: %v", status.ID, err)
+	}
+
+	state.mu.Lock()
+	status.Artifacts = addArtifacts(status.Artifacts, artifacts)
+	all := append([]Artifact(nil), status.Artifacts...)
+	state.mu.Unlock()
+
+	coverage := SummarizeCoverage(all, h.artifactsDir(status.ID), workDir)
+	state.mu.Lock()
+	status.Coverage = coverage
+	state.mu.Unlock()
+}
+
+// parseOutput parses a run's output with parser. With results, the parser
+// reads the files in dir matching those globs that were written since the
+// run started instead, so reports left by earlier runs are ignored.
+func parseOutput(parser OutputParser, output string, results []string, dir string, since time.Time) []ParsedLine {
+	if len(results) == 0 {
+		return parser.Parse(output)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range results {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, _ := filepath.Glob(pattern)
+		for _, file := range matches {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	var errs []ParsedLine
+	read := 0
+	for _, file := range files {
+		info, err := os.Stat(file)
+		// Modification times may be truncated to the second
+		if err != nil || info.IsDir() || info.ModTime().Before(since.Truncate(time.Second)) {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, ParsedLine{Type: "error", File: file, Message: fmt.Sprintf("failed to read results: %v", err)})
+			continue
+		}
+		read++
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	if read == 0 && len(errs) == 0 {
+		return []ParsedLine{{Type: "error", Message: fmt.Sprintf("no results written to %s", strings.Join(results, ", "))}}
+	}
+	return append(errs, parser.Parse(sb.String())...)
+}
+
+// commandFor returns a command running args in dir with env, or with
+// Trellis's environment if env is nil.
+func commandFor(ctx context.Context, args []string, dir string, env []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+
+	// Run in its own process group so cancel/timeout kills descendants
+	// (make/go test/script children) too, not just the direct child.
+	// Mirrors the service process path.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if err == syscall.ESRCH {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+
+	cmd.Env = env
+	return cmd
+}
+
 func (r *RealRunner) runBuiltin(ctx context.Context, wf WorkflowConfig) (*WorkflowStatus, error) {
 	status := &WorkflowStatus{
 		ID:        wf.ID,
@@ -681,8 +914,7 @@ func (r *RealRunner) Status(runID string) (*WorkflowStatus, bool) {
 	defer state.mu.RUnlock()
 
 	// Return a copy
-	statusCopy := *state.status
-	return &statusCopy, true
+	return state.status.clone(), true
 }
 
 // LatestRun returns the most recently started run for a worktree.
@@ -709,8 +941,7 @@ func (r *RealRunner) LatestRun(worktree string) (*WorkflowStatus, bool) {
 	defer latest.mu.RUnlock()
 
 	// Return a copy
-	statusCopy := *latest.status
-	return &statusCopy, true
+	return latest.status.clone(), true
 }
 
 // Cancel cancels a running workflow. id may be a run ID or a workflow ID;
@@ -787,23 +1018,21 @@ func (r *RealRunner) emitFinished(ctx context.Context, status *WorkflowStatus, w
 	if status.Error != "" {
 		payload["error"] = status.Error
 	}
-	// Add test counts for go_test_json parser
-	if wf.OutputParser == "go_test_json" && len(status.ParsedLines) > 0 {
-		var passed, failed, skipped int
-		for _, line := range status.ParsedLines {
-			switch line.Type {
-			case "test_pass":
-				passed++
-			case "test_fail":
-				failed++
-			case "test_skip":
-				skipped++
-			}
-		}
-		payload["tests_passed"] = passed
-		payload["tests_failed"] = failed
-		payload["tests_skipped"] = skipped
-		payload["tests_total"] = passed + failed + skipped
+	if status.CachedFrom != "" {
+		payload["cached_from"] = status.CachedFrom
+	}
+	if len(status.Artifacts) > 0 {
+		payload["artifacts"] = len(status.Artifacts)
+	}
+	if status.Coverage != nil {
+		payload["coverage"] = status.Coverage.Percent()
+	}
+	// Add test counts for parsers that report tests
+	if s := status.Summary; s != nil && s.TestsPassed+s.TestsFailed+s.TestsSkipped > 0 {
+		payload["tests_passed"] = s.TestsPassed
+		payload["tests_failed"] = s.TestsFailed
+		payload["tests_skipped"] = s.TestsSkipped
+		payload["tests_total"] = s.TestsPassed + s.TestsFailed + s.TestsSkipped
 	}
 	r.emitEvent(ctx, "workflow.finished", payload)
 }
@@ -825,10 +1054,10 @@ func (r *RealRunner) Subscribe(runID string, ch chan<- OutputUpdate) error {
 	// If already completed, send final status immediately
 	state.mu.RLock()
 	if state.completed {
-		statusCopy := *state.status
+		statusCopy := state.status.clone()
 		state.mu.RUnlock()
 		select {
-		case ch <- OutputUpdate{RunID: runID, Done: true, Status: &statusCopy}:
+		case ch <- OutputUpdate{RunID: runID, Done: true, Status: statusCopy}:
 		default:
 		}
 	} else {
@@ -907,6 +1136,32 @@ func (state *runState) notifyComplete(runID string, status *WorkflowStatus) {
 	}
 }
 
+// headState returns the commit checked out in dir and whether dir has
+// uncommitted changes to tracked files. The commit is empty outside a git
+// repository.
+func headState(ctx context.Context, dir string) (commit string, dirty bool) {
+	if dir == "" {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	commit = strings.TrimSpace(string(out))
+
+	cmd = exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=no")
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		dirty = len(bytes.TrimSpace(out)) > 0
+	}
+	return commit, dirty
+}
+
 // inputTemplateData is the data structure passed to templates for input expansion.
 type inputTemplateData struct {
 	Inputs map[string]any
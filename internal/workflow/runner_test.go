@@ -5,6 +5,9 @@ package workflow
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -13,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // waitForCompletion polls the runner status until the workflow completes or times out
@@ -374,6 +378,77 @@ func TestRunner_Run_WithParser(t *testing.T) {
 	assert.Equal(t, 10, status.ParsedLines[0].Line)
 }
 
+func TestRunner_Run_WithResults(t *testing.T) {
+	dir := t.TempDir()
+	reports := filepath.Join(dir, "reports")
+	require.NoError(t, os.MkdirAll(reports, 0755))
+
+	// A report left by an earlier run is ignored
+	stale := filepath.Join(reports, "old.xml")
+	require.NoError(t, os.WriteFile(stale, []byte(`<testsuite><testcase classname="old" name="stale"/></testsuite>`), 0644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(stale, old, old))
+
+	workflows := []WorkflowConfig{
+		{
+			ID:           "test",
+			Name:         "Test",
+			Command:      []string{"sh", "-c", `echo 'running tests'; echo '<testsuite><testcase classname="app" name="works"/><testcase classname="app" name="breaks"><failure message="boom"/></testcase></testsuite>' > reports/new.xml`},
+			OutputParser: "junit",
+			Results:      []string{"reports/*.xml"},
+		},
+		{
+			ID:           "missing",
+			Name:         "Missing",
+			Command:      []string{"true"},
+			OutputParser: "junit",
+			Results:      []string{"build/*.xml"},
+		},
+	}
+	runner := NewRunner(workflows, nil, nil, dir)
+	defer runner.Close()
+
+	initial, err := runner.Run(context.Background(), "test")
+	require.NoError(t, err)
+	status := waitForCompletion(t, runner, initial.ID, 5*time.Second)
+	assert.Equal(t, "running tests\n", status.Output)
+	require.NotNil(t, status.Summary)
+	assert.Equal(t, 1, status.Summary.TestsPassed)
+	assert.Equal(t, []string{"app.breaks"}, status.Summary.FailedTests)
+	assert.Contains(t, status.OutputHTML, "app/breaks")
+
+	initial, err = runner.Run(context.Background(), "missing")
+	require.NoError(t, err)
+	status = waitForCompletion(t, runner, initial.ID, 5*time.Second)
+	require.NotNil(t, status.Summary)
+	assert.Equal(t, "no results written to build/*.xml", status.Summary.FirstError)
+}
+
+func TestRunner_SetParsers(t *testing.T) {
+	workflows := []WorkflowConfig{
+		{ID: "lint", Name: "Lint", Command: []string{"echo", "app.php:3: missing semicolon"}, OutputParser: "php"},
+	}
+	runner := NewRunner(workflows, nil, nil, "")
+	defer runner.Close()
+
+	parser, err := NewRegexParser("php", []RegexRule{{Pattern: `^(?P<file>\S+):(?P<line>\d+): (?P<message>.*)$`, Type: "error"}})
+	require.NoError(t, err)
+	runner.SetParsers([]OutputParser{parser})
+
+	initial, err := runner.Run(context.Background(), "lint")
+	require.NoError(t, err)
+	status := waitForCompletion(t, runner, initial.ID, 5*time.Second)
+	assert.Equal(t, []ParsedLine{{Type: "error", File: "app.php", Line: 3, Message: "missing semicolon"}}, status.ParsedLines)
+	assert.Contains(t, status.OutputHTML, "openFileAtLine('app.php', 3, 0)")
+
+	// Parsers no longer declared fall back to none
+	runner.SetParsers(nil)
+	initial, err = runner.Run(context.Background(), "lint")
+	require.NoError(t, err)
+	status = waitForCompletion(t, runner, initial.ID, 5*time.Second)
+	assert.Empty(t, status.ParsedLines)
+}
+
 func TestRunner_Run_WorkingDir(t *testing.T) {
 	bus := newTestBus()
 	defer bus.Close()
@@ -420,6 +495,50 @@ func TestRunner_Run_OverrideWorkingDir(t *testing.T) {
 	assert.Contains(t, status.Output, tmpDir)
 }
 
+func TestRunner_Run_EnvAndSecrets(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "deploy.env"), []byte("REGION=eu\n"), 0644))
+
+	sm := secrets.NewManager(secrets.NewStore(t.TempDir(), "test"), nil)
+	require.NoError(t, sm.Set("deploy_token", "tok_0123456789"))
+
+	workflows := []WorkflowConfig{
+		{
+			ID:      "deploy",
+			Name:    "Deploy",
+			Command: []string{"sh", "-c", "echo $REGION $TOKEN $EXTRA"},
+			EnvFile: "deploy.env",
+			Env:     map[string]string{"TOKEN": `{{ secret "deploy_token" }}`},
+		},
+		{
+			ID:      "broken",
+			Name:    "Broken",
+			Command: []string{"true"},
+			Env:     map[string]string{"TOKEN": `{{ secret "missing" }}`},
+		},
+	}
+
+	runner := NewRunner(workflows, bus, nil, tmpDir)
+	runner.SetSecrets(sm)
+
+	initialStatus, err := runner.RunWithOptions(context.Background(), "deploy", RunOptions{
+		Env: map[string]string{"EXTRA": "x"},
+	})
+	require.NoError(t, err)
+	status := waitForCompletion(t, runner, initialStatus.ID, 5*time.Second)
+	assert.True(t, status.Success)
+	assert.Equal(t, "eu [secret:deploy_token] x\n", status.Output)
+
+	initialStatus, err = runner.Run(context.Background(), "broken")
+	require.NoError(t, err)
+	status = waitForCompletion(t, runner, initialStatus.ID, 5*time.Second)
+	assert.Equal(t, StateFailed, status.State)
+	assert.Contains(t, status.Error, "missing")
+}
+
 func TestRunner_Status(t *testing.T) {
 	bus := newTestBus()
 	defer bus.Close()
@@ -1047,3 +1166,114 @@ func TestRunner_Subscribe_ReplaysOutput(t *testing.T) {
 		t.Fatal("did not receive replayed output")
 	}
 }
+
+func TestRunner_Run_RecordsHistory(t *testing.T) {
+	bus := newTestBus()
+	defer bus.Close()
+
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		require.NoError(t, cmd.Run())
+	}
+	out, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+	head := strings.TrimSpace(string(out))
+
+	history, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	workflows := []WorkflowConfig{
+		{ID: "echo", Name: "Echo", Command: []string{"echo", "hello"}},
+	}
+	runner := NewRunner(workflows, bus, nil, repo)
+	defer runner.Close()
+	runner.SetHistory(history)
+
+	initialStatus, err := runner.RunWithOptions(context.Background(), "echo", RunOptions{Worktree: "main"})
+	require.NoError(t, err)
+	waitForCompletion(t, runner, initialStatus.ID, 5*time.Second)
+
+	require.Eventually(t, func() bool {
+		return len(history.Runs("echo", "", 0)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	run, err := history.Run(initialStatus.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "echo", run.WorkflowID)
+	assert.Equal(t, "main", run.Worktree)
+	assert.Equal(t, head, run.Commit)
+	assert.False(t, run.Dirty)
+	assert.Equal(t, StateSuccess, run.State)
+	assert.Contains(t, run.Output, "hello")
+}
+
+// runRecorded runs workflow id and returns the run once it's recorded in
+// history.
+func runRecorded(t *testing.T, runner Runner, history *History, id string) *WorkflowStatus {
+	t.Helper()
+	initial, err := runner.Run(context.Background(), id)
+	require.NoError(t, err)
+	var run *WorkflowStatus
+	require.Eventually(t, func() bool {
+		run, err = history.Run(initial.ID)
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	return run
+}
+
+func TestRunner_Run_ArtifactsAndCacheKey(t *testing.T) {
+	work := t.TempDir()
+	writeFile(t, work, "in.txt", "v1")
+	history, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	workflows := []WorkflowConfig{{
+		ID:        "build",
+		Name:      "Build",
+		Command:   []string{"sh", "-c", "date +%s%N > out.bin && printf 'mode: set\\na.go:1.1,2.2 3 1\\nb.go:1.1,2.2 1 0\\n' > cover.out && echo built"},
+		Artifacts: []string{"out.bin", "cover.out"},
+		CacheKey:  &CacheKey{Files: []string{"in.txt"}},
+	}}
+	runner := NewRunner(workflows, nil, nil, work)
+	defer runner.Close()
+	runner.SetHistory(history)
+
+	first := runRecorded(t, runner, history, "build")
+	assert.Equal(t, StateSuccess, first.State)
+	assert.Empty(t, first.CachedFrom)
+	assert.NotEmpty(t, first.CacheKey)
+	require.Len(t, first.Artifacts, 2)
+	assert.Equal(t, "cover.out", first.Artifacts[0].Path)
+	assert.Equal(t, "out.bin", first.Artifacts[1].Path)
+	require.NotNil(t, first.Coverage)
+	assert.Equal(t, 3, first.Coverage.Covered)
+	assert.Equal(t, 4, first.Coverage.Total)
+	built, err := os.ReadFile(filepath.Join(work, "out.bin"))
+	require.NoError(t, err)
+
+	// Nothing changed, so the run is restored instead of run
+	require.NoError(t, os.Remove(filepath.Join(work, "out.bin")))
+	second := runRecorded(t, runner, history, "build")
+	assert.Equal(t, StateSuccess, second.State)
+	assert.Equal(t, first.ID, second.CachedFrom)
+	assert.Equal(t, first.CacheKey, second.CacheKey)
+	assert.Contains(t, second.Output, "built")
+	assert.Len(t, second.Artifacts, 2)
+	assert.NotNil(t, second.Coverage)
+	restored, err := os.ReadFile(filepath.Join(work, "out.bin"))
+	require.NoError(t, err)
+	assert.Equal(t, built, restored)
+	_, err = history.ArtifactFile(second.ID, "out.bin")
+	assert.NoError(t, err)
+
+	// A changed input file runs it again
+	writeFile(t, work, "in.txt", "v2")
+	third := runRecorded(t, runner, history, "build")
+	assert.Empty(t, third.CachedFrom)
+	assert.NotEqual(t, first.CacheKey, third.CacheKey)
+}
@@ -0,0 +1,187 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule: five fields (minute, hour, day of
+// month, month, day of week), each "*", a value, a range "a-b", a list of
+// those separated by commas, or any of them stepped with "/n". Months and
+// days of the week may be given by their three-letter English names, and
+// Sunday is 0 or 7. When both day fields are restricted, a day matches
+// either, as in cron. The descriptors @yearly, @monthly, @weekly, @daily,
+// @hourly and "@every <duration>" are also accepted.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bit i is set if value i matches
+	domAny, dowAny                bool   // Day field was "*"
+	every                         time.Duration
+}
+
+// scheduleField is the range and names of a cron field.
+type scheduleField struct {
+	name     string
+	min, max int
+	names    []string // Names of the values from min, if any
+}
+
+var (
+	minuteField = scheduleField{name: "minute", min: 0, max: 59}
+	hourField   = scheduleField{name: "hour", min: 0, max: 23}
+	domField    = scheduleField{name: "day of month", min: 1, max: 31}
+	monthField  = scheduleField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dowField    = scheduleField{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a cron schedule.
+func ParseSchedule(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every duration: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("@every duration must be positive")
+		}
+		return &Schedule{every: d}, nil
+	}
+	if expanded, ok := scheduleDescriptors[spec]; ok {
+		spec = expanded
+	} else if strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("unknown descriptor %q", spec)
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+	s := &Schedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parse returns the bitset of the values a field matches.
+func (f scheduleField) parse(text string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(text, ",") {
+		rangeText, stepText, stepped := strings.Cut(part, "/")
+		step := 1
+		if stepped {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, stepText)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangeText != "*" {
+			loText, hiText, isRange := strings.Cut(rangeText, "-")
+			var err error
+			if lo, err = f.value(loText); err != nil {
+				return 0, err
+			}
+			switch {
+			case isRange:
+				if hi, err = f.value(hiText); err != nil {
+					return 0, err
+				}
+				if hi < lo {
+					return 0, fmt.Errorf("invalid %s range %q", f.name, rangeText)
+				}
+			case !stepped:
+				hi = lo
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single value of a field, by number or name.
+func (f scheduleField) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, must be %d-%d", f.name, text, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after t the schedule matches, in t's
+// location, or the zero time if it matches none in the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether t's day matches the day fields.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
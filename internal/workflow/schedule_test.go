@@ -0,0 +1,72 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon-fri", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches
+		{"0 0 15 * fri", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"5,45 10 * * *", time.Date(2026, 3, 4, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2026, 3, 4, 10, 19, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, s.Next(from), tt.spec)
+	}
+
+	// Never matches
+	s, err := ParseSchedule("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(from).IsZero())
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@often",
+		"@every soon",
+		"@every -1m",
+	} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,754 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/secrets"
+)
+
+// maxStepDepth bounds how deeply workflows reused as steps may nest, in
+// case a reference cycle got past validation.
+const maxStepDepth = 8
+
+// stepRun executes one run of a workflow composed of steps.
+type stepRun struct {
+	r       *RealRunner
+	state   *runState
+	sm      *secrets.Manager
+	parsers *ParserRegistry
+	history *History // Keeps the steps' artifacts and caches their results; nil if there's no history
+	workDir string
+	env     map[string]string // Variables of the run itself, added to every step's
+	parser  string            // Output parser of the run's workflow, for live output
+
+	// Guarded by state.mu
+	output     outputBuffer // Every step's lines, prefixed with the step, as they arrive
+	lastFormat time.Time
+}
+
+// outputBuffer accumulates output up to maxOutputSize.
+type outputBuffer struct {
+	b         strings.Builder
+	truncated bool
+}
+
+// write appends s, unless the buffer is full.
+func (o *outputBuffer) write(s string) {
+	if o.truncated {
+		return
+	}
+	if o.b.Len()+len(s) > maxOutputSize {
+		o.b.WriteString("\n... output truncated (exceeded 10MB) ...\n")
+		o.truncated = true
+		return
+	}
+	o.b.WriteString(s)
+}
+
+func (o *outputBuffer) String() string {
+	return o.b.String()
+}
+
+// exitCodeError reports a command that exited with a non-zero code.
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exited with code %d", e.code)
+}
+
+// executeSteps runs a workflow composed of steps, each step starting once
+// the steps it needs have finished.
+func (r *RealRunner) executeSteps(ctx context.Context, wf WorkflowConfig, state *runState, opts RunOptions) {
+	status := state.status
+
+	execCtx := ctx
+	if wf.Timeout > 0 {
+		var cancel context.CancelFunc
+		execCtx, cancel = context.WithTimeout(ctx, wf.Timeout)
+		defer cancel()
+	}
+
+	r.mu.RLock()
+	workDir := r.workingDir
+	sm := r.secrets
+	parsers := r.parsers
+	history := r.history
+	r.mu.RUnlock()
+	if opts.WorkingDir != "" {
+		workDir = opts.WorkingDir
+	}
+
+	run := &stepRun{
+		r:       r,
+		state:   state,
+		sm:      sm,
+		parsers: parsers,
+		history: history,
+		workDir: workDir,
+		env:     opts.Env,
+		parser:  wf.OutputParser,
+	}
+	steps := newStepStatuses(wf.Steps)
+	state.mu.Lock()
+	status.Steps = steps
+	state.mu.Unlock()
+
+	run.runSteps(execCtx, wf, steps, opts.Inputs, "", 0)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	status.FinishedAt = time.Now()
+	status.Duration = status.FinishedAt.Sub(status.StartedAt)
+	status.Output = stepSections(status.Steps)
+	status.ParsedLines = stepParsedLines(status.Steps)
+	status.Summary = Summarize(status.ParsedLines)
+	status.OutputHTML = FormatStepsHTML(status.Steps)
+
+	failed := failedSteps(wf.Steps, status.Steps)
+	switch {
+	case execCtx.Err() == context.Canceled:
+		status.State = StateCanceled
+		status.Error = "canceled"
+	case execCtx.Err() == context.DeadlineExceeded:
+		status.State = StateFailed
+		status.Error = "timeout exceeded"
+	case len(failed) > 0:
+		status.State = StateFailed
+		status.Error = stepsError(failed).Error()
+		status.ExitCode = failed[0].ExitCode
+	default:
+		status.State = StateSuccess
+		status.Success = true
+	}
+}
+
+// newStepStatuses returns the initial status of each step.
+func newStepStatuses(steps []WorkflowStep) []StepStatus {
+	statuses := make([]StepStatus, len(steps))
+	for i, step := range steps {
+		name := step.Name
+		if name == "" {
+			name = step.ID
+		}
+		statuses[i] = StepStatus{ID: step.ID, Name: name, State: StatePending}
+	}
+	return statuses
+}
+
+// runSteps runs wf's steps, recording their progress in statuses, and
+// returns when every step has finished or been skipped. path prefixes the
+// step IDs in the run's output.
+func (run *stepRun) runSteps(ctx context.Context, wf WorkflowConfig, statuses []StepStatus, inputs map[string]any, path string, depth int) {
+	steps := wf.Steps
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		index[step.ID] = i
+	}
+
+	started := make([]bool, len(steps))
+	done := make([]bool, len(steps))
+	finished := make(chan int)
+	running := 0
+	for {
+		// Deciding not to run a step can make others ready, so go around
+		// until nothing changes
+		for progress := true; progress; {
+			progress = false
+			for i, step := range steps {
+				if started[i] || !needsDone(step, index, done) {
+					continue
+				}
+				started[i] = true
+				progress = true
+				if !run.start(ctx, steps, statuses, i, inputs) {
+					done[i] = true
+					continue
+				}
+				running++
+				go func(i int) {
+					run.executeStep(ctx, wf, steps[i], &statuses[i], inputs, path+steps[i].ID, depth)
+					finished <- i
+				}(i)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		done[<-finished] = true
+		running--
+	}
+
+	// Steps still not started need each other
+	run.state.mu.Lock()
+	defer run.state.mu.Unlock()
+	for i := range steps {
+		if !started[i] {
+			statuses[i].State = StateFailed
+			statuses[i].Error = "dependency cycle"
+		}
+	}
+}
+
+// needsDone reports whether every step that step needs has finished.
+func needsDone(step WorkflowStep, index map[string]int, done []bool) bool {
+	for _, need := range step.Needs {
+		if i, ok := index[need]; ok && !done[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// start decides whether step i runs now that its needs have finished,
+// marking it running if it does and skipped or canceled if it doesn't.
+func (run *stepRun) start(ctx context.Context, steps []WorkflowStep, statuses []StepStatus, i int, inputs map[string]any) bool {
+	run.state.mu.Lock()
+	defer run.state.mu.Unlock()
+
+	st := &statuses[i]
+	if ctx.Err() != nil {
+		st.State = StateCanceled
+		st.Error = contextError(ctx.Err())
+		return false
+	}
+	ok, err := evalCondition(steps[i], steps, statuses, inputs)
+	if err != nil {
+		st.State = StateFailed
+		st.Error = fmt.Sprintf("invalid if: %v", err)
+		return false
+	}
+	if !ok {
+		st.State = StateSkipped
+		return false
+	}
+	st.State = StateRunning
+	st.StartedAt = time.Now()
+	return true
+}
+
+// contextError describes why a context ended, as run errors do.
+func contextError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout exceeded"
+	}
+	return "canceled"
+}
+
+// stepTemplateData is the data step conditions are evaluated with.
+type stepTemplateData struct {
+	Inputs map[string]any
+	Steps  map[string]StepStatus
+}
+
+// evalCondition reports whether step runs. Without an If, a step runs when
+// every step it needs succeeded or failed with continue_on_error. If is a
+// template over the run's .Inputs and its .Steps by ID, with functions
+// success (every need succeeded), failure (a need failed) and always; a
+// bare expression such as `failure` is wrapped in {{ }}. The step runs
+// unless the template renders empty, "false", "0" or "<no value>".
+func evalCondition(step WorkflowStep, steps []WorkflowStep, statuses []StepStatus, inputs map[string]any) (bool, error) {
+	success, failure := true, false
+	for _, need := range step.Needs {
+		for j := range steps {
+			if steps[j].ID != need {
+				continue
+			}
+			switch statuses[j].State {
+			case StateSuccess:
+			case StateFailed:
+				if !steps[j].ContinueOnError {
+					success = false
+					failure = true
+				}
+			default:
+				success = false
+			}
+		}
+	}
+	if step.If == "" {
+		return success, nil
+	}
+
+	tmpl, err := template.New("if").Funcs(template.FuncMap{
+		"success": func() bool { return success },
+		"failure": func() bool { return failure },
+		"always":  func() bool { return true },
+	}).Parse(ConditionTemplate(step.If))
+	if err != nil {
+		return false, err
+	}
+	data := stepTemplateData{Inputs: inputs, Steps: make(map[string]StepStatus, len(statuses))}
+	for _, st := range statuses {
+		data.Steps[st.ID] = st
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return false, err
+	}
+	switch strings.TrimSpace(buf.String()) {
+	case "", "false", "0", "<no value>":
+		return false, nil
+	}
+	return true, nil
+}
+
+// ConditionTemplate returns the template of a step's if, wrapping a bare
+// expression in {{ }}.
+func ConditionTemplate(cond string) string {
+	if strings.Contains(cond, "{{") {
+		return cond
+	}
+	return "{{" + cond + "}}"
+}
+
+// executeStep runs a started step and records how it finished.
+func (run *stepRun) executeStep(ctx context.Context, wf WorkflowConfig, step WorkflowStep, st *StepStatus, inputs map[string]any, path string, depth int) {
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	var err error
+	restored := run.restoreStep(wf, step, st, inputs, path)
+	switch {
+	case restored:
+	case step.Workflow != "":
+		err = run.runWorkflowStep(ctx, step, st, inputs, path, depth)
+	default:
+		err = run.runCommandStep(ctx, wf, step, st, inputs, path)
+	}
+	if !restored {
+		run.keepArtifacts(step, st, path)
+	}
+
+	run.state.mu.Lock()
+	defer run.state.mu.Unlock()
+	st.FinishedAt = time.Now()
+	st.Duration = st.FinishedAt.Sub(st.StartedAt)
+	if st.Steps == nil && st.parser != "" {
+		st.parsed = parseOutput(run.parsers.Get(st.parser), st.Output, st.results, run.workDir, st.StartedAt)
+	}
+	st.Summary = Summarize(st.parsed)
+
+	var exitErr *exitCodeError
+	switch {
+	case err == nil:
+		st.State = StateSuccess
+	case ctx.Err() != nil:
+		st.State = StateFailed
+		st.Error = contextError(ctx.Err())
+		if st.Error == "canceled" {
+			st.State = StateCanceled
+		}
+	case errors.Is(err, context.Canceled):
+		st.State = StateCanceled
+		st.Error = "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		st.State = StateFailed
+		st.Error = "timeout exceeded"
+	default:
+		st.State = StateFailed
+		st.Error = run.sm.Redact(err.Error())
+		if errors.As(err, &exitErr) {
+			st.ExitCode = exitErr.code
+		}
+	}
+}
+
+// runCommandStep runs a step's command with the workflow's environment and
+// the step's own variables.
+func (run *stepRun) runCommandStep(ctx context.Context, wf WorkflowConfig, step WorkflowStep, st *StepStatus, inputs map[string]any, path string) error {
+	parser, results := run.parsing(wf, step)
+	run.state.mu.Lock()
+	st.parser = parser
+	st.results = results
+	run.state.mu.Unlock()
+
+	if len(step.Command) == 0 {
+		return errors.New("no command specified")
+	}
+	commands, err := expandCommandsWithInputs([][]string{step.Command}, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to expand inputs: %w", err)
+	}
+	env, err := run.environ(ctx, wf.EnvFile, mergeEnv(wf.Env, step.Env))
+	if err != nil {
+		return err
+	}
+	var out outputBuffer
+	return run.runCommand(ctx, commands[0], env, &out, st, path, parser)
+}
+
+// parsing returns the output parser of a step and the files it reads
+// instead of the step's output, if any.
+func (run *stepRun) parsing(wf WorkflowConfig, step WorkflowStep) (string, []string) {
+	if step.Workflow != "" {
+		if called, ok := run.r.Get(step.Workflow); ok {
+			wf = called
+		}
+	}
+	parser, results := step.OutputParser, step.Results
+	if parser == "" {
+		parser = wf.OutputParser
+	}
+	if len(results) == 0 && step.Workflow != "" {
+		results = wf.Results
+	}
+	return parser, results
+}
+
+// restoreStep restores a step's output and artifacts from the newest
+// recorded run of the workflow in which the step succeeded with the same
+// cache key, reporting whether there was one.
+func (run *stepRun) restoreStep(wf WorkflowConfig, step WorkflowStep, st *StepStatus, inputs map[string]any, path string) bool {
+	if step.CacheKey == nil || run.history == nil {
+		return false
+	}
+	status := run.state.status
+	run.state.mu.RLock()
+	commit, dirty := status.Commit, status.Dirty
+	run.state.mu.RUnlock()
+
+	// The key covers the workflow the step runs, as well as the step
+	config := []any{step}
+	if step.Workflow != "" {
+		called, _ := run.r.Get(step.Workflow)
+		config = append(config, called)
+	}
+	key, err := cacheKey(step.CacheKey, config, inputs, run.workDir, commit, dirty)
+	if err != nil {
+		log.Printf("Warning: failed to compute cache key of step %s: %v", path, err)
+		return false
+	}
+	run.state.mu.Lock()
+	st.CacheKey = key
+	run.state.mu.Unlock()
+
+	cached, cachedStep := run.history.Cached(status.WorkflowID, path, key)
+	if cachedStep == nil {
+		return false
+	}
+	restored, err := run.history.RestoreArtifacts(cached.ID, status.ID, path, run.workDir, cached.Artifacts)
+	if err != nil {
+		log.Printf("Warning: failed to restore step %s from workflow run %s: %v", path, cached.ID, err)
+		return false
+	}
+
+	var out outputBuffer
+	line := run.writeLine(&out, st, path, fmt.Sprintf("restored from run %s\n", cached.ID))
+	run.state.notifySubscribers(status.ID, line)
+
+	parser, results := run.parsing(wf, step)
+	run.state.mu.Lock()
+	defer run.state.mu.Unlock()
+	st.CachedFrom = cached.ID
+	st.Output = cachedStep.Output
+	st.Steps = cloneSteps(cachedStep.Steps)
+	st.parser = parser
+	st.results = results
+	status.Artifacts = addArtifacts(status.Artifacts, restored)
+	return true
+}
+
+// keepArtifacts keeps the files a step wrote matching its artifacts globs,
+// and those of the workflow it runs, with the run.
+func (run *stepRun) keepArtifacts(step WorkflowStep, st *StepStatus, path string) {
+	patterns := step.Artifacts
+	if step.Workflow != "" {
+		if called, ok := run.r.Get(step.Workflow); ok {
+			patterns = append(append([]string(nil), patterns...), called.Artifacts...)
+		}
+	}
+	if run.history == nil || len(patterns) == 0 {
+		return
+	}
+	status := run.state.status
+	artifacts, err := run.history.CollectArtifacts(status.ID, run.workDir, patterns, st.StartedAt, path)
+	if err != nil {
+		log.Printf("Warning: workflow run %s: step %s: %v", status.ID, path, err)
+	}
+	run.state.mu.Lock()
+	status.Artifacts = addArtifacts(status.Artifacts, artifacts)
+	run.state.mu.Unlock()
+}
+
+// runWorkflowStep runs another workflow as a step: its steps, recorded as
+// the step's own, or its commands in sequence.
+func (run *stepRun) runWorkflowStep(ctx context.Context, step WorkflowStep, st *StepStatus, inputs map[string]any, path string, depth int) error {
+	if depth >= maxStepDepth {
+		return fmt.Errorf("workflows nested more than %d deep", maxStepDepth)
+	}
+	wf, ok := run.r.Get(step.Workflow)
+	if !ok || strings.HasPrefix(step.Workflow, "_") {
+		return fmt.Errorf("workflow %q not found", step.Workflow)
+	}
+	stepInputs, err := expandStepInputs(step.Inputs, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to expand inputs: %w", err)
+	}
+	if err := ValidateInputs(wf.Inputs, stepInputs); err != nil {
+		return err
+	}
+	if wf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wf.Timeout)
+		defer cancel()
+	}
+	wf.Env = mergeEnv(wf.Env, step.Env)
+	if step.OutputParser != "" {
+		wf.OutputParser = step.OutputParser
+	}
+	if len(step.Results) > 0 {
+		wf.Results = step.Results
+	}
+
+	if len(wf.Steps) > 0 {
+		children := newStepStatuses(wf.Steps)
+		run.state.mu.Lock()
+		st.Steps = children
+		run.state.mu.Unlock()
+
+		run.runSteps(ctx, wf, children, stepInputs, path+"/", depth+1)
+
+		run.state.mu.Lock()
+		st.Output = stepSections(children)
+		st.parsed = stepParsedLines(children)
+		run.state.mu.Unlock()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if failed := failedSteps(wf.Steps, children); len(failed) > 0 {
+			return stepsError(failed)
+		}
+		return nil
+	}
+
+	run.state.mu.Lock()
+	st.parser = wf.OutputParser
+	st.results = wf.Results
+	run.state.mu.Unlock()
+
+	commands := wf.GetCommands()
+	if len(commands) == 0 {
+		return errors.New("no command specified")
+	}
+	commands, err = expandCommandsWithInputs(commands, stepInputs)
+	if err != nil {
+		return fmt.Errorf("failed to expand inputs: %w", err)
+	}
+	env, err := run.environ(ctx, wf.EnvFile, wf.Env)
+	if err != nil {
+		return err
+	}
+	var out outputBuffer
+	for i, args := range commands {
+		if len(commands) > 1 {
+			if i > 0 {
+				run.writeLine(&out, st, path, "\n")
+			}
+			run.writeLine(&out, st, path, fmt.Sprintf("=== Command %d/%d: %s ===\n", i+1, len(commands), strings.Join(args, " ")))
+		}
+		if err := run.runCommand(ctx, args, env, &out, st, path, wf.OutputParser); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if len(commands) > 1 {
+				return fmt.Errorf("command %d %w", i+1, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// expandStepInputs expands the templates in the string inputs a step
+// passes to the workflow it runs, using the run's inputs.
+func expandStepInputs(stepInputs, inputs map[string]any) (map[string]any, error) {
+	expanded := make(map[string]any, len(stepInputs))
+	for name, value := range stepInputs {
+		if s, ok := value.(string); ok {
+			v, err := expandTemplate(s, inputTemplateData{Inputs: inputs})
+			if err != nil {
+				return nil, fmt.Errorf("input %q: %w", name, err)
+			}
+			value = v
+		}
+		expanded[name] = value
+	}
+	return expanded, nil
+}
+
+// mergeEnv returns env with extra's variables added, extra winning.
+func mergeEnv(env, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return env
+	}
+	merged := make(map[string]string, len(env)+len(extra))
+	for k, v := range env {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
+}
+
+// environ builds a step's environment: the env file and env with secrets
+// resolved, then the run's own variables. It's nil, inheriting Trellis's
+// environment, when there are none.
+func (run *stepRun) environ(ctx context.Context, envFile string, env map[string]string) ([]string, error) {
+	if envFile == "" && len(env) == 0 && len(run.env) == 0 {
+		return nil, nil
+	}
+	environ, err := run.sm.Environ(ctx, envFile, run.workDir, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build environment: %w", err)
+	}
+	for k, v := range run.env {
+		environ = append(environ, k+"="+v)
+	}
+	return environ, nil
+}
+
+// runCommand runs args, adding its output to out and the run's output.
+func (run *stepRun) runCommand(ctx context.Context, args []string, env []string, out *outputBuffer, st *StepStatus, path, parser string) error {
+	cmd := commandFor(ctx, args, run.workDir, env)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	stream := func(reader io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := run.writeLine(out, st, path, run.sm.Redact(scanner.Text())+"\n")
+			// Raw go test JSON isn't useful to watch
+			if parser != "go_test_json" {
+				run.state.notifySubscribers(run.state.status.ID, line)
+			}
+		}
+		if scanner.Err() != nil {
+			run.writeLine(out, st, path, "\n... output line exceeded 1MB limit, some output discarded ...\n")
+			// Drain remaining output to prevent pipe deadlock
+			io.Copy(io.Discard, reader)
+		}
+	}
+	go stream(stdout)
+	go stream(stderr)
+	wg.Wait()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &exitCodeError{code: exitErr.ExitCode()}
+	}
+	return err
+}
+
+// writeLine adds a line of a step's output to out and, prefixed with the
+// step's path, to the run's output, returning the prefixed line.
+func (run *stepRun) writeLine(out *outputBuffer, st *StepStatus, path, line string) string {
+	prefixed := "[" + path + "] " + line
+
+	run.state.mu.Lock()
+	defer run.state.mu.Unlock()
+	out.write(line)
+	st.Output = out.String()
+
+	status := run.state.status
+	run.output.write(prefixed)
+	status.Output = run.output.String()
+	// Throttle HTML formatting to avoid O(n²) for large outputs
+	if now := time.Now(); now.Sub(run.lastFormat) >= formatThrottle {
+		status.OutputHTML = FormatOutput(status.Output, nil, run.parser)
+		run.lastFormat = now
+	}
+	return prefixed
+}
+
+// failedSteps returns the steps that failed without continue_on_error.
+func failedSteps(steps []WorkflowStep, statuses []StepStatus) []StepStatus {
+	var failed []StepStatus
+	for i, st := range statuses {
+		if st.State == StateFailed && !steps[i].ContinueOnError {
+			failed = append(failed, st)
+		}
+	}
+	return failed
+}
+
+// stepsError describes the failed steps of a run.
+func stepsError(failed []StepStatus) error {
+	if len(failed) == 1 {
+		return fmt.Errorf("step %s failed: %s", failed[0].ID, failed[0].Error)
+	}
+	ids := make([]string, len(failed))
+	for i, st := range failed {
+		ids[i] = st.ID
+	}
+	return fmt.Errorf("steps %s failed", strings.Join(ids, ", "))
+}
+
+// stepSections returns the output of steps as one text, under a header per
+// step.
+func stepSections(steps []StepStatus) string {
+	var sb strings.Builder
+	for i, st := range steps {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "=== Step %s: %s", st.ID, st.State)
+		if !st.StartedAt.IsZero() {
+			fmt.Fprintf(&sb, " (%s)", st.Duration.Round(time.Millisecond))
+		}
+		if st.CachedFrom != "" {
+			fmt.Fprintf(&sb, ", restored from %s", st.CachedFrom)
+		}
+		if st.Error != "" {
+			fmt.Fprintf(&sb, ": %s", st.Error)
+		}
+		sb.WriteString(" ===\n")
+		sb.WriteString(st.Output)
+		if st.Output != "" && !strings.HasSuffix(st.Output, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// stepParsedLines returns the parsed output of steps, in step order.
+func stepParsedLines(steps []StepStatus) []ParsedLine {
+	var lines []ParsedLine
+	for _, st := range steps {
+		lines = append(lines, st.parsed...)
+	}
+	return lines
+}
@@ -0,0 +1,303 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSteps runs workflow id of workflows to completion.
+func runSteps(t *testing.T, workflows []WorkflowConfig, id string, inputs map[string]any) *WorkflowStatus {
+	t.Helper()
+	runner := NewRunner(workflows, nil, nil, t.TempDir())
+	t.Cleanup(func() { runner.Close() })
+	initial, err := runner.RunWithOptions(context.Background(), id, RunOptions{Inputs: inputs})
+	require.NoError(t, err)
+	return waitForCompletion(t, runner, initial.ID, 10*time.Second)
+}
+
+// stepByID returns the status of a step.
+func stepByID(t *testing.T, steps []StepStatus, id string) StepStatus {
+	t.Helper()
+	for _, st := range steps {
+		if st.ID == id {
+			return st
+		}
+	}
+	t.Fatalf("step %s not found", id)
+	return StepStatus{}
+}
+
+func TestRunner_Steps_Parallel(t *testing.T) {
+	workflows := []WorkflowConfig{{
+		ID:   "check",
+		Name: "Check",
+		Steps: []WorkflowStep{
+			{ID: "lint", Command: []string{"sh", "-c", "sleep 1; echo linted"}},
+			{ID: "test", Name: "Unit tests", Command: []string{"sh", "-c", "sleep 1; echo tested"}},
+			{ID: "build", Command: []string{"echo", "built"}, Needs: []string{"lint", "test"}},
+		},
+	}}
+
+	start := time.Now()
+	status := runSteps(t, workflows, "check", nil)
+	elapsed := time.Since(start)
+
+	assert.Equal(t, StateSuccess, status.State)
+	assert.True(t, status.Success)
+	assert.Less(t, elapsed, 1900*time.Millisecond, "lint and test should run in parallel")
+
+	require.Len(t, status.Steps, 3)
+	lint, test, build := status.Steps[0], status.Steps[1], status.Steps[2]
+	assert.Equal(t, "Unit tests", test.Name)
+	assert.Equal(t, "lint", lint.Name)
+	for _, st := range status.Steps {
+		assert.Equal(t, StateSuccess, st.State, st.ID)
+	}
+	assert.False(t, build.StartedAt.Before(lint.FinishedAt))
+	assert.False(t, build.StartedAt.Before(test.FinishedAt))
+	assert.Equal(t, "built\n", build.Output)
+	assert.Contains(t, status.Output, "=== Step lint: success")
+	assert.Contains(t, status.Output, "linted")
+	assert.Contains(t, status.OutputHTML, "Unit tests")
+}
+
+func TestRunner_Steps_FailureAndConditions(t *testing.T) {
+	workflows := []WorkflowConfig{{
+		ID:   "pre-push",
+		Name: "Pre-push",
+		Steps: []WorkflowStep{
+			{ID: "lint", Command: []string{"sh", "-c", "echo style; exit 3"}, ContinueOnError: true},
+			{ID: "test", Command: []string{"sh", "-c", "exit 1"}},
+			{ID: "vet", Command: []string{"true"}},
+			{ID: "build", Command: []string{"true"}, Needs: []string{"test"}},
+			{ID: "after-lint", Command: []string{"true"}, Needs: []string{"lint"}},
+			{ID: "report", Command: []string{"echo", "reporting"}, Needs: []string{"test"}, If: "failure"},
+			{ID: "cleanup", Command: []string{"true"}, Needs: []string{"build"}, If: "always"},
+			{ID: "deploy", Command: []string{"true"}, If: `eq .Inputs.env "prod"`},
+			{ID: "lint-failed", Command: []string{"true"}, Needs: []string{"lint"}, If: `{{ eq (index .Steps "lint").State "failed" }}`},
+		},
+	}}
+
+	status := runSteps(t, workflows, "pre-push", map[string]any{"env": "dev"})
+
+	assert.Equal(t, StateFailed, status.State)
+	assert.False(t, status.Success)
+	assert.Equal(t, "step test failed: exited with code 1", status.Error)
+	assert.Equal(t, 1, status.ExitCode)
+
+	lint := stepByID(t, status.Steps, "lint")
+	assert.Equal(t, StateFailed, lint.State)
+	assert.Equal(t, 3, lint.ExitCode)
+
+	want := map[string]WorkflowState{
+		"test":        StateFailed,
+		"vet":         StateSuccess,
+		"build":       StateSkipped,
+		"after-lint":  StateSuccess,
+		"report":      StateSuccess,
+		"cleanup":     StateSuccess,
+		"deploy":      StateSkipped,
+		"lint-failed": StateSuccess,
+	}
+	for id, state := range want {
+		assert.Equal(t, state, stepByID(t, status.Steps, id).State, id)
+	}
+}
+
+func TestRunner_Steps_TimeoutAndEnv(t *testing.T) {
+	workflows := []WorkflowConfig{{
+		ID:   "env",
+		Name: "Env",
+		Env:  map[string]string{"REGION": "us", "TIER": "dev"},
+		Steps: []WorkflowStep{
+			{ID: "show", Command: []string{"sh", "-c", "echo $REGION-$TIER"}, Env: map[string]string{"TIER": "prod"}},
+			{ID: "slow", Command: []string{"sleep", "10"}, Timeout: 100 * time.Millisecond},
+		},
+	}}
+
+	status := runSteps(t, workflows, "env", nil)
+
+	assert.Equal(t, StateFailed, status.State)
+	assert.Equal(t, "us-prod\n", stepByID(t, status.Steps, "show").Output)
+	slow := stepByID(t, status.Steps, "slow")
+	assert.Equal(t, StateFailed, slow.State)
+	assert.Equal(t, "timeout exceeded", slow.Error)
+	assert.Less(t, slow.Duration, 5*time.Second)
+}
+
+func TestRunner_Steps_Workflow(t *testing.T) {
+	workflows := []WorkflowConfig{
+		{
+			ID:       "greet",
+			Name:     "Greet",
+			Commands: [][]string{{"echo", "hello {{.Inputs.name}}"}, {"echo", "bye"}},
+			Inputs:   []WorkflowInput{{Name: "name", Required: true}},
+		},
+		{
+			ID:   "checks",
+			Name: "Checks",
+			Steps: []WorkflowStep{
+				{ID: "one", Command: []string{"echo", "one"}},
+				{ID: "two", Command: []string{"sh", "-c", "exit 2"}, Needs: []string{"one"}},
+			},
+		},
+		{
+			ID:   "all",
+			Name: "All",
+			Steps: []WorkflowStep{
+				{ID: "greet", Workflow: "greet", Inputs: map[string]any{"name": "{{.Inputs.who}}"}},
+				{ID: "checks", Workflow: "checks", Needs: []string{"greet"}},
+				{ID: "missing", Workflow: "greet"},
+			},
+		},
+	}
+
+	status := runSteps(t, workflows, "all", map[string]any{"who": "trellis"})
+
+	greet := stepByID(t, status.Steps, "greet")
+	assert.Equal(t, StateSuccess, greet.State)
+	assert.Contains(t, greet.Output, "hello trellis\n")
+	assert.Contains(t, greet.Output, "=== Command 2/2: echo bye ===")
+
+	checks := stepByID(t, status.Steps, "checks")
+	assert.Equal(t, StateFailed, checks.State)
+	assert.Equal(t, "step two failed: exited with code 2", checks.Error)
+	require.Len(t, checks.Steps, 2)
+	assert.Equal(t, StateSuccess, checks.Steps[0].State)
+	assert.Equal(t, 2, checks.Steps[1].ExitCode)
+	assert.Contains(t, status.OutputHTML, "<details")
+
+	missing := stepByID(t, status.Steps, "missing")
+	assert.Equal(t, StateFailed, missing.State)
+	assert.Contains(t, missing.Error, "required")
+
+	assert.Equal(t, "steps checks, missing failed", status.Error)
+}
+
+func TestRunner_Steps_Cancel(t *testing.T) {
+	workflows := []WorkflowConfig{{
+		ID:   "long",
+		Name: "Long",
+		Steps: []WorkflowStep{
+			{ID: "wait", Command: []string{"sleep", "10"}},
+			{ID: "after", Command: []string{"true"}, Needs: []string{"wait"}, If: "always"},
+		},
+	}}
+	runner := NewRunner(workflows, nil, nil, "")
+	defer runner.Close()
+
+	initial, err := runner.Run(context.Background(), "long")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		status, _ := runner.Status(initial.ID)
+		return len(status.Steps) > 0 && status.Steps[0].State == StateRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, runner.Cancel(initial.ID))
+
+	status := waitForCompletion(t, runner, initial.ID, 5*time.Second)
+	assert.Equal(t, StateCanceled, status.State)
+	assert.Equal(t, StateCanceled, status.Steps[0].State)
+	assert.Equal(t, StateCanceled, status.Steps[1].State)
+}
+
+func TestRunner_Steps_GoTestParser(t *testing.T) {
+	json := `{"Action":"pass","Package":"app","Test":"TestA"}
+{"Action":"fail","Package":"app","Test":"TestB"}
+`
+	workflows := []WorkflowConfig{{
+		ID:           "test",
+		Name:         "Test",
+		OutputParser: "go_test_json",
+		Steps: []WorkflowStep{
+			{ID: "unit", Command: []string{"printf", "%s", json}},
+			{ID: "lint", Command: []string{"echo", "main.go:1:1: oops"}, OutputParser: "none"},
+		},
+	}}
+
+	status := runSteps(t, workflows, "test", nil)
+
+	require.NotNil(t, status.Summary)
+	assert.Equal(t, 1, status.Summary.TestsPassed)
+	assert.Equal(t, []string{"app.TestB"}, status.Summary.FailedTests)
+	assert.Equal(t, []string{"app.TestB"}, stepByID(t, status.Steps, "unit").Summary.FailedTests)
+	assert.Nil(t, stepByID(t, status.Steps, "lint").Summary)
+}
+
+func TestRunner_Steps_Results(t *testing.T) {
+	report := `<testsuite><testcase classname="ui" name="renders"/></testsuite>`
+	workflows := []WorkflowConfig{{
+		ID:           "test",
+		Name:         "Test",
+		OutputParser: "junit",
+		Steps: []WorkflowStep{
+			{ID: "unit", Command: []string{"sh", "-c", "echo '" + report + "' > unit.xml"}, Results: []string{"unit.xml"}},
+			{ID: "lint", Command: []string{"echo", "src/a.ts(1,2): error TS1005: ';' expected."}, OutputParser: "tsc"},
+		},
+	}}
+
+	status := runSteps(t, workflows, "test", nil)
+
+	require.NotNil(t, status.Summary)
+	assert.Equal(t, 1, status.Summary.TestsPassed)
+	assert.Equal(t, 1, status.Summary.Errors)
+	assert.Equal(t, 1, stepByID(t, status.Steps, "unit").Summary.TestsPassed)
+	assert.Equal(t, "TS1005: ';' expected.", stepByID(t, status.Steps, "lint").Summary.FirstError)
+}
+
+func TestRunner_Steps_CacheKey(t *testing.T) {
+	work := t.TempDir()
+	writeFile(t, work, "src/main.c", "int main() {}")
+	history, err := NewHistory(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+
+	workflows := []WorkflowConfig{{
+		ID:   "ci",
+		Name: "CI",
+		Steps: []WorkflowStep{
+			{
+				ID:        "build",
+				Command:   []string{"sh", "-c", "mkdir -p bin && date +%s%N > bin/app && echo compiled"},
+				Artifacts: []string{"bin"},
+				CacheKey:  &CacheKey{Files: []string{"src/**"}},
+			},
+			{ID: "test", Command: []string{"sh", "-c", "cat bin/app >/dev/null && echo tested"}, Needs: []string{"build"}},
+		},
+	}}
+	runner := NewRunner(workflows, nil, nil, work)
+	defer runner.Close()
+	runner.SetHistory(history)
+
+	first := runRecorded(t, runner, history, "ci")
+	require.Equal(t, StateSuccess, first.State, first.Error)
+	assert.Empty(t, stepByID(t, first.Steps, "build").CachedFrom)
+	require.Len(t, first.Artifacts, 1)
+	assert.Equal(t, Artifact{Path: "bin/app", Size: first.Artifacts[0].Size, Step: "build"}, first.Artifacts[0])
+
+	// The build is restored, binary and all; the test runs again
+	require.NoError(t, os.RemoveAll(filepath.Join(work, "bin")))
+	second := runRecorded(t, runner, history, "ci")
+	require.Equal(t, StateSuccess, second.State, second.Error)
+	build := stepByID(t, second.Steps, "build")
+	assert.Equal(t, first.ID, build.CachedFrom)
+	assert.Equal(t, StateSuccess, build.State)
+	assert.Contains(t, build.Output, "compiled")
+	assert.Contains(t, second.Output, "restored from "+first.ID)
+	assert.Empty(t, stepByID(t, second.Steps, "test").CachedFrom)
+	assert.Contains(t, stepByID(t, second.Steps, "test").Output, "tested")
+	assert.Equal(t, first.Artifacts, second.Artifacts)
+	assert.FileExists(t, filepath.Join(work, "bin/app"))
+
+	// Changing a source runs the build again
+	writeFile(t, work, "src/lib/util.c", "int util() {}")
+	third := runRecorded(t, runner, history, "ci")
+	assert.Empty(t, stepByID(t, third.Steps, "build").CachedFrom)
+}
@@ -0,0 +1,401 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wingedpig/trellis/internal/events"
+	"github.com/wingedpig/trellis/internal/watcher"
+)
+
+// defaultTriggerDebounce is how long file changes and events must settle
+// before they start a run.
+const defaultTriggerDebounce = 500 * time.Millisecond
+
+// maxTriggerItems caps the files or events listed in a run's trigger.
+const maxTriggerItems = 10
+
+// Triggers starts workflows when files matching their trigger globs change
+// in the active worktree, when events matching their trigger patterns are
+// published, and on their schedules. Each workflow has at most one
+// triggered run in flight; triggers arriving meanwhile are handled by the
+// workflow's policy, and those queued are merged into a single run.
+type Triggers struct {
+	runner  Runner
+	bus     events.EventBus
+	matcher *events.PatternMatcher
+
+	mu       sync.Mutex
+	active   map[string]*triggered // Workflows with triggers, by ID
+	flights  map[string]*flight    // Triggered runs, by workflow ID
+	worktree string
+	sub      events.SubscriptionID // Subscription to all events, while any workflow has event triggers
+	closed   bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// triggered is a workflow with triggers and what watches for them.
+type triggered struct {
+	wf        WorkflowConfig
+	watcher   *watcher.TreeWatcher
+	debouncer *watcher.Debouncer // Debounces events
+	events    pendingTrigger     // Events waiting out the debounce; guarded by Triggers.mu
+	schedule  *Schedule
+	stop      chan struct{} // Stops the schedule
+}
+
+// flight tracks the triggered run of a workflow in flight.
+type flight struct {
+	runID  string          // Empty when no triggered run is in flight
+	queued *pendingTrigger // Triggers of the run to start when runID finishes
+}
+
+// pendingTrigger accumulates triggers of one type. A trigger of another
+// type replaces them.
+type pendingTrigger struct {
+	typ   TriggerType
+	items []string // Changed files, event types or schedule
+}
+
+func (p *pendingTrigger) add(typ TriggerType, items []string) {
+	if p.typ != typ {
+		p.typ, p.items = typ, nil
+	}
+	for _, item := range items {
+		found := false
+		for _, have := range p.items {
+			if have == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.items = append(p.items, item)
+		}
+	}
+}
+
+// trigger returns the record of the run the triggers start.
+func (p *pendingTrigger) trigger() *RunTrigger {
+	items := p.items
+	detail := strings.Join(items, ", ")
+	if len(items) > maxTriggerItems {
+		detail = fmt.Sprintf("%s and %d more", strings.Join(items[:maxTriggerItems], ", "), len(items)-maxTriggerItems)
+	}
+	return &RunTrigger{Type: p.typ, Detail: detail}
+}
+
+// NewTriggers creates the triggers of runner's workflows. Nothing is
+// triggered until Update is called with the workflows.
+func NewTriggers(runner Runner, bus events.EventBus) *Triggers {
+	return &Triggers{
+		runner:  runner,
+		bus:     bus,
+		matcher: events.NewPatternMatcher(),
+		active:  make(map[string]*triggered),
+		flights: make(map[string]*flight),
+		done:    make(chan struct{}),
+	}
+}
+
+// Update replaces the watched workflows. File triggers watch the tree
+// under workDir, and triggered runs are for worktree. Runs in flight carry
+// on. Workflows whose triggers can't be set up are reported in the error;
+// the others are watched regardless.
+func (t *Triggers) Update(workflows []WorkflowConfig, workDir, worktree string) error {
+	var errs []error
+	active := make(map[string]*triggered)
+	hasEvents := false
+	for _, wf := range workflows {
+		if wf.Triggers == nil {
+			continue
+		}
+		id := wf.ID
+		tw := &triggered{wf: wf, stop: make(chan struct{})}
+		debounce := wf.Triggers.Debounce
+		if debounce <= 0 {
+			debounce = defaultTriggerDebounce
+		}
+		if len(wf.Triggers.Files) > 0 && workDir != "" {
+			w, err := watcher.NewTreeWatcher(workDir, debounce, fileFilter(wf.Triggers), func(files []string) {
+				t.fire(id, TriggerFiles, files)
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("workflow %s: %w", id, err))
+			} else {
+				tw.watcher = w
+			}
+		}
+		if len(wf.Triggers.Events) > 0 {
+			tw.debouncer = watcher.NewDebouncer(debounce)
+			hasEvents = true
+		}
+		if wf.Triggers.Schedule != "" {
+			schedule, err := ParseSchedule(wf.Triggers.Schedule)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("workflow %s: invalid schedule: %w", id, err))
+			} else {
+				tw.schedule = schedule
+			}
+		}
+		active[id] = tw
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		for _, tw := range active {
+			tw.close()
+		}
+		return nil
+	}
+	old := t.active
+	t.active = active
+	t.worktree = worktree
+	for id, tw := range active {
+		if tw.schedule != nil {
+			t.wg.Add(1)
+			go t.runSchedule(id, tw)
+		}
+	}
+	if hasEvents && t.sub == "" && t.bus != nil {
+		sub, err := t.bus.SubscribeAsync("*", t.handleEvent, 256)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to subscribe to events: %w", err))
+		} else {
+			t.sub = sub
+		}
+	} else if !hasEvents && t.sub != "" {
+		t.bus.Unsubscribe(t.sub)
+		t.sub = ""
+	}
+	t.mu.Unlock()
+
+	// Outside the lock: stopping waits for callbacks, which take it
+	for _, tw := range old {
+		tw.close()
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops all triggers. Runs in flight carry on, but queued runs don't
+// start.
+func (t *Triggers) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	old := t.active
+	t.active = nil
+	if t.sub != "" {
+		t.bus.Unsubscribe(t.sub)
+		t.sub = ""
+	}
+	t.mu.Unlock()
+
+	for _, tw := range old {
+		tw.close()
+	}
+	t.wg.Wait()
+	return nil
+}
+
+func (tw *triggered) close() {
+	close(tw.stop)
+	if tw.watcher != nil {
+		tw.watcher.Close()
+	}
+	if tw.debouncer != nil {
+		tw.debouncer.Stop()
+	}
+}
+
+// fileFilter returns the TreeWatcher filter of a workflow's file triggers.
+// The .git and .trellis directories are never watched, so Trellis's own
+// state doesn't trigger runs.
+func fileFilter(tr *WorkflowTriggers) func(rel string, dir bool) bool {
+	return func(rel string, dir bool) bool {
+		if base := path.Base(rel); base == ".git" || base == ".trellis" {
+			return true
+		}
+		for _, pattern := range tr.Ignore {
+			if watcher.MatchGlob(pattern, rel) {
+				return true
+			}
+		}
+		if dir {
+			return false
+		}
+		for _, pattern := range tr.Files {
+			if watcher.MatchGlob(pattern, rel) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// handleEvent debounces events matching the trigger patterns of workflows.
+// Events about a workflow's own runs never trigger it.
+func (t *Triggers) handleEvent(_ context.Context, event events.Event) error {
+	runID, _ := event.Payload["workflow_id"].(string)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, tw := range t.active {
+		if tw.debouncer == nil || isRunOf(runID, id) {
+			continue
+		}
+		for _, pattern := range tw.wf.Triggers.Events {
+			if t.matcher.Match(event.Type, pattern) {
+				tw.events.add(TriggerEvent, []string{event.Type})
+				tw.debouncer.Debounce(id, func() { t.fireEvents(id, tw) })
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// fireEvents triggers a workflow with the events that settled.
+func (t *Triggers) fireEvents(id string, tw *triggered) {
+	t.mu.Lock()
+	items := tw.events.items
+	tw.events = pendingTrigger{}
+	t.mu.Unlock()
+	if len(items) > 0 {
+		t.fire(id, TriggerEvent, items)
+	}
+}
+
+// runSchedule triggers a workflow on its schedule until it is stopped.
+func (t *Triggers) runSchedule(id string, tw *triggered) {
+	defer t.wg.Done()
+	for {
+		next := tw.schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-tw.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			t.fire(id, TriggerSchedule, []string{tw.wf.Triggers.Schedule})
+		}
+	}
+}
+
+// fire starts a triggered run of workflow id, or applies its policy if a
+// triggered run is in flight.
+func (t *Triggers) fire(id string, typ TriggerType, items []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tw, ok := t.active[id]
+	if t.closed || !ok {
+		return
+	}
+	f := t.flights[id]
+	if f == nil {
+		f = &flight{}
+		t.flights[id] = f
+	}
+	if f.runID == "" {
+		p := pendingTrigger{}
+		p.add(typ, items)
+		t.start(id, tw.wf, f, p.trigger())
+		return
+	}
+
+	switch tw.wf.Triggers.Policy {
+	case PolicySkip:
+		log.Printf("Workflow %s: %s trigger skipped, run %s in flight", id, typ, f.runID)
+		return
+	case PolicyCancel:
+		if f.queued == nil {
+			if err := t.runner.Cancel(f.runID); err != nil {
+				log.Printf("Warning: failed to cancel workflow run %s: %v", f.runID, err)
+			}
+		}
+	}
+	if f.queued == nil {
+		f.queued = &pendingTrigger{}
+	}
+	f.queued.add(typ, items)
+}
+
+// start starts a triggered run and watches for it to finish. Caller must
+// hold t.mu.
+func (t *Triggers) start(id string, wf WorkflowConfig, f *flight, trigger *RunTrigger) {
+	// Triggered runs have no one to ask for inputs
+	var inputs map[string]any
+	for _, input := range wf.Inputs {
+		if input.Default != nil {
+			if inputs == nil {
+				inputs = make(map[string]any)
+			}
+			inputs[input.Name] = input.Default
+		}
+	}
+
+	status, err := t.runner.RunWithOptions(context.Background(), id, RunOptions{
+		Worktree: t.worktree,
+		Inputs:   inputs,
+		Trigger:  trigger,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to start workflow %s on %s trigger: %v", id, trigger.Type, err)
+		return
+	}
+	log.Printf("Workflow %s triggered by %s: %s", id, trigger.Type, trigger.Detail)
+
+	ch := make(chan OutputUpdate, 64)
+	if err := t.runner.Subscribe(status.ID, ch); err != nil {
+		return
+	}
+	f.runID = status.ID
+	t.wg.Add(1)
+	go t.await(id, status.ID, ch)
+}
+
+// await waits for a triggered run to finish, then starts the queued run,
+// if any.
+func (t *Triggers) await(id, runID string, ch chan OutputUpdate) {
+	defer t.wg.Done()
+	defer t.runner.Unsubscribe(runID, ch)
+	for done := false; !done; {
+		select {
+		case <-t.done:
+			return
+		case update := <-ch:
+			done = update.Done
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f := t.flights[id]
+	f.runID = ""
+	queued := f.queued
+	f.queued = nil
+	tw, ok := t.active[id]
+	if queued == nil || t.closed || !ok {
+		return
+	}
+	t.start(id, tw.wf, f, queued.trigger())
+}
@@ -0,0 +1,175 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wingedpig/trellis/internal/events"
+)
+
+// newTriggers returns triggers for a workflow that appends a line to a
+// file in dir on each run, after sleeping for the given duration.
+func newTriggers(t *testing.T, dir string, sleep string, triggers *WorkflowTriggers, bus events.EventBus) (*Triggers, Runner) {
+	t.Helper()
+	wf := WorkflowConfig{
+		ID:       "check",
+		Name:     "Check",
+		Command:  []string{"sh", "-c", "sleep " + sleep + "; echo run >> runs.log"},
+		Triggers: triggers,
+	}
+	runner := NewRunner([]WorkflowConfig{wf}, bus, nil, dir)
+	tr := NewTriggers(runner, bus)
+	require.NoError(t, tr.Update([]WorkflowConfig{wf}, dir, "main"))
+	t.Cleanup(func() {
+		tr.Close()
+		runner.Close()
+	})
+	return tr, runner
+}
+
+// runCount returns the number of finished runs of the newTriggers workflow.
+func runCount(dir string) int {
+	data, _ := os.ReadFile(filepath.Join(dir, "runs.log"))
+	return strings.Count(string(data), "run\n")
+}
+
+// latestTrigger waits for the latest run in worktree main to finish and
+// returns its trigger.
+func latestTrigger(t *testing.T, runner Runner) *RunTrigger {
+	t.Helper()
+	var status *WorkflowStatus
+	require.Eventually(t, func() bool {
+		s, ok := runner.LatestRun("main")
+		if !ok || s.State == StateRunning {
+			return false
+		}
+		status = s
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return status.Trigger
+}
+
+func TestTriggers_Files(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".trellis"), 0755))
+	_, runner := newTriggers(t, dir, "0", &WorkflowTriggers{
+		Files:    []string{"*.go"},
+		Ignore:   []string{"vendor"},
+		Debounce: 50 * time.Millisecond,
+	}, nil)
+
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644)
+	os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg"), 0644)
+	trigger := latestTrigger(t, runner)
+	require.NotNil(t, trigger)
+	assert.Equal(t, TriggerFiles, trigger.Type)
+	assert.Equal(t, "main.go, pkg/a.go", trigger.Detail)
+
+	// Other files, ignored directories and Trellis's state don't trigger
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("x"), 0644)
+	os.MkdirAll(filepath.Join(dir, "vendor"), 0755)
+	os.WriteFile(filepath.Join(dir, "vendor", "v.go"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, ".trellis", "state.go"), []byte("x"), 0644)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, runCount(dir))
+}
+
+func TestTriggers_Events(t *testing.T) {
+	dir := t.TempDir()
+	bus := events.NewMemoryEventBus(events.MemoryBusConfig{})
+	defer bus.Close()
+	_, runner := newTriggers(t, dir, "0", &WorkflowTriggers{
+		Events:   []string{"service.*", "workflow.finished"},
+		Debounce: 50 * time.Millisecond,
+	}, bus)
+
+	ctx := context.Background()
+	bus.Publish(ctx, events.Event{Type: events.EventServiceCrashed})
+	bus.Publish(ctx, events.Event{Type: events.EventServiceStarted})
+	bus.Publish(ctx, events.Event{Type: events.EventServiceCrashed})
+	bus.Publish(ctx, events.Event{Type: events.EventBinaryChanged})
+	trigger := latestTrigger(t, runner)
+	require.NotNil(t, trigger)
+	assert.Equal(t, TriggerEvent, trigger.Type)
+	assert.Equal(t, "service.crashed, service.started", trigger.Detail)
+
+	// The run's own workflow.finished doesn't trigger it again, but another
+	// workflow's does
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, runCount(dir))
+	bus.Publish(ctx, events.Event{Type: events.EventWorkflowFinished, Payload: map[string]interface{}{"workflow_id": "build-1"}})
+	require.Eventually(t, func() bool { return runCount(dir) == 2 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTriggers_Policy(t *testing.T) {
+	tests := []struct {
+		policy TriggerPolicy
+		runs   int
+		detail string
+	}{
+		{PolicyQueue, 2, "b.go, c.go"},
+		{PolicyCancel, 1, "b.go, c.go"},
+		{PolicySkip, 1, "a.go"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dir := t.TempDir()
+			tr, runner := newTriggers(t, dir, "0.3", &WorkflowTriggers{Schedule: "@yearly", Policy: tt.policy}, nil)
+
+			tr.fire("check", TriggerFiles, []string{"a.go"})
+			tr.fire("check", TriggerFiles, []string{"b.go"})
+			tr.fire("check", TriggerFiles, []string{"c.go", "b.go"})
+
+			require.Eventually(t, func() bool { return runCount(dir) == tt.runs }, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.detail, latestTrigger(t, runner).Detail)
+			time.Sleep(500 * time.Millisecond)
+			assert.Equal(t, tt.runs, runCount(dir))
+		})
+	}
+}
+
+func TestTriggers_Schedule(t *testing.T) {
+	dir := t.TempDir()
+	tr, runner := newTriggers(t, dir, "0", &WorkflowTriggers{Schedule: "@every 100ms"}, nil)
+
+	require.Eventually(t, func() bool { return runCount(dir) >= 2 }, 5*time.Second, 10*time.Millisecond)
+	trigger := latestTrigger(t, runner)
+	assert.Equal(t, &RunTrigger{Type: TriggerSchedule, Detail: "@every 100ms"}, trigger)
+
+	// Removing the trigger stops the schedule
+	require.NoError(t, tr.Update(nil, dir, "main"))
+	time.Sleep(50 * time.Millisecond)
+	n := runCount(dir)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, n, runCount(dir))
+}
+
+func TestTriggers_UpdateErrors(t *testing.T) {
+	runner := NewRunner(nil, nil, nil, "")
+	defer runner.Close()
+	tr := NewTriggers(runner, nil)
+	defer tr.Close()
+
+	err := tr.Update([]WorkflowConfig{
+		{ID: "bad", Triggers: &WorkflowTriggers{Schedule: "every day"}},
+		{ID: "missing", Triggers: &WorkflowTriggers{Files: []string{"*.go"}}},
+	}, filepath.Join(t.TempDir(), "missing"), "main")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow bad: invalid schedule")
+	assert.Contains(t, err.Error(), "workflow missing: failed to watch")
+}
@@ -6,6 +6,8 @@ package workflow
 import (
 	"context"
 	"time"
+
+	"github.com/wingedpig/trellis/internal/secrets"
 )
 
 // WorkflowInput defines a parameter that prompts the user before execution.
@@ -31,11 +33,103 @@ type WorkflowConfig struct {
 	Commands        [][]string     // Multiple commands to run in sequence
 	Timeout         time.Duration
 	OutputParser    string
+	Results         []string // Globs of files the output parser reads after the run instead of the output
 	Confirm         bool
 	ConfirmMessage  string
 	RequiresStopped []string
 	RestartServices bool
 	Inputs          []WorkflowInput // Input parameters to prompt user for
+	Env             map[string]string
+	EnvFile         string            // dotenv file, relative to the working directory
+	Steps           []WorkflowStep    // Named steps run as their needs allow, instead of Commands
+	Triggers        *WorkflowTriggers // Starts runs on file changes, events and schedules
+	Artifacts       []string          // Globs of files, relative to the working directory, kept with each run
+	CacheKey        *CacheKey         // Skips the run when a recorded run had the same key
+}
+
+// CacheKey decides when a run or step can be skipped, its results restored
+// from a recorded one, because nothing it depends on changed. The key
+// always covers the workflow or step's config and the run's inputs.
+type CacheKey struct {
+	Files  []string // Globs of files, relative to the working directory, whose contents the key covers; matched as the triggers' files are
+	Commit bool     // The key covers the commit checked out; runs with uncommitted changes are never cached
+}
+
+// WorkflowTriggers start runs of a workflow without anyone asking for them.
+// Triggered runs are in the active worktree.
+type WorkflowTriggers struct {
+	Files    []string      // Globs of files, relative to the worktree root, whose changes start a run
+	Ignore   []string      // Globs of files and directories not to watch
+	Events   []string      // Event type patterns that start a run
+	Schedule string        // Cron schedule; see ParseSchedule
+	Debounce time.Duration // Quiet period after file changes and events before a run starts
+	Policy   TriggerPolicy // What to do when triggered while a triggered run is in flight
+}
+
+// TriggerPolicy decides what happens when a workflow is triggered while a
+// run it triggered earlier is still in flight.
+type TriggerPolicy string
+
+const (
+	PolicyQueue  TriggerPolicy = "queue"  // Run again once the run in flight finishes (default)
+	PolicyCancel TriggerPolicy = "cancel" // Cancel the run in flight, then run again
+	PolicySkip   TriggerPolicy = "skip"   // Ignore the trigger
+)
+
+// RunTrigger records what started a triggered run.
+type RunTrigger struct {
+	Type   TriggerType
+	Detail string // Changed files, event types or schedule
+}
+
+// TriggerType is the kind of trigger that started a run.
+type TriggerType string
+
+const (
+	TriggerFiles    TriggerType = "files"
+	TriggerEvent    TriggerType = "event"
+	TriggerSchedule TriggerType = "schedule"
+)
+
+// WorkflowStep is a named step of a workflow composed of steps. Steps run
+// as soon as the steps they need have finished, in parallel with any other
+// step that is ready.
+type WorkflowStep struct {
+	ID              string
+	Name            string
+	Command         []string
+	Workflow        string         // ID of a workflow to run as this step, instead of Command
+	Inputs          map[string]any // Inputs of Workflow; string values are templates over the run's inputs
+	Needs           []string       // IDs of the steps that must finish first
+	If              string         // Condition deciding whether the step runs; see evalCondition
+	ContinueOnError bool           // A failure neither fails the run nor skips the steps needing this one
+	Timeout         time.Duration
+	Env             map[string]string // Added to the workflow's env
+	OutputParser    string            // Defaults to the workflow's
+	Results         []string          // Globs of files the output parser reads after the step instead of its output
+	Artifacts       []string          // Globs of files kept after the step
+	CacheKey        *CacheKey         // Skips the step when a recorded run of the workflow had the same key for it
+}
+
+// StepStatus represents the status of a step of a run.
+type StepStatus struct {
+	ID         string
+	Name       string
+	State      WorkflowState
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Duration   time.Duration
+	ExitCode   int
+	Output     string
+	Summary    *WorkflowSummary // Rollup of the step's parsed output; nil without an output parser
+	Error      string
+	Steps      []StepStatus // Steps of a reused workflow composed of steps
+	CacheKey   string       // Key the step's results are cached under; empty without a cache_key
+	CachedFrom string       // ID of the run the step's results were restored from
+
+	parser  string       // Output parser of the step
+	results []string     // Files the output parser reads instead of the output
+	parsed  []ParsedLine // Parsed output, merged into the run's ParsedLines
 }
 
 // GetCommands returns the commands to execute, preferring Commands over Command.
@@ -52,8 +146,12 @@ func (w *WorkflowConfig) GetCommands() [][]string {
 // WorkflowStatus represents the current status of a workflow.
 type WorkflowStatus struct {
 	ID          string
+	WorkflowID  string // ID of the workflow this is a run of
 	Name        string
-	Worktree    string // Worktree the run was started for (empty if none specified)
+	Worktree    string         // Worktree the run was started for (empty if none specified)
+	Commit      string         // Commit checked out in the working directory when the run started
+	Dirty       bool           // Working directory had uncommitted changes to tracked files
+	Inputs      map[string]any // User-supplied input values
 	State       WorkflowState
 	StartedAt   time.Time
 	FinishedAt  time.Time
@@ -65,6 +163,33 @@ type WorkflowStatus struct {
 	ParsedLines []ParsedLine
 	Summary     *WorkflowSummary // Rollup of ParsedLines; nil when no output parser is configured
 	Error       string
+	Steps       []StepStatus // Status of each step, in config order, for workflows composed of steps
+	Trigger     *RunTrigger  // What started the run; nil when it was started by hand
+	Artifacts   []Artifact   // Files kept from the run and its steps
+	Coverage    *Coverage    // Summary of the coverage files among Artifacts; nil if there are none
+	CacheKey    string       // Key the run's results are cached under; empty without a cache_key
+	CachedFrom  string       // ID of the run the results were restored from
+}
+
+// clone returns a copy of status that shares no steps with it, so the copy
+// can be read while the run updates its steps.
+func (s *WorkflowStatus) clone() *WorkflowStatus {
+	c := *s
+	c.Steps = cloneSteps(s.Steps)
+	c.Artifacts = append([]Artifact(nil), s.Artifacts...)
+	return &c
+}
+
+func cloneSteps(steps []StepStatus) []StepStatus {
+	if steps == nil {
+		return nil
+	}
+	c := make([]StepStatus, len(steps))
+	for i, step := range steps {
+		c[i] = step
+		c[i].Steps = cloneSteps(step.Steps)
+	}
+	return c
 }
 
 // WorkflowSummary is a structured rollup of ParsedLines so programmatic
@@ -127,6 +252,7 @@ const (
 	StateSuccess  WorkflowState = "success"
 	StateFailed   WorkflowState = "failed"
 	StateCanceled WorkflowState = "canceled"
+	StateSkipped  WorkflowState = "skipped" // Steps only: a need failed or the step's condition was false
 )
 
 // ParsedLine represents a parsed line of output (e.g., error, test result).
@@ -174,6 +300,14 @@ type Runner interface {
 	// UpdateConfig updates the workflow configs and working directory.
 	// Called when worktree is activated to use new paths.
 	UpdateConfig(workflows []WorkflowConfig, workingDir string)
+	// SetSecrets sets the manager that resolves secrets in workflow
+	// environments and redacts them from workflow output.
+	SetSecrets(m *secrets.Manager)
+	// SetHistory sets the store completed runs are recorded in.
+	SetHistory(h *History)
+	// SetParsers sets the output parsers declared in the config, in
+	// addition to the built-in ones.
+	SetParsers(parsers []OutputParser)
 	// Close shuts down the runner and stops background goroutines.
 	Close() error
 }
@@ -191,6 +325,8 @@ type RunOptions struct {
 	Env map[string]string
 	// Inputs provides user-supplied input values for template expansion.
 	Inputs map[string]any
+	// Trigger records what started the run, for triggered runs.
+	Trigger *RunTrigger
 }
 
 // OutputParser parses workflow output into structured lines.
@@ -219,6 +355,12 @@ func NewParserRegistry() *ParserRegistry {
 	r.Register(&GenericParser{})
 	r.Register(&NoOpParser{})
 	r.Register(&HTMLParser{})
+	r.Register(&JUnitParser{})
+	r.Register(&TAPParser{})
+	r.Register(&ESLintParser{})
+	r.Register(&TSCParser{})
+	r.Register(&PytestParser{})
+	r.Register(&CargoParser{})
 	return r
 }
 
@@ -0,0 +1,157 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package worktree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// PortAllocator gives each worktree its own ports, so the services of
+// several worktrees can run side by side. A port is allocated the first
+// time a worktree asks for a name and stays the same, across restarts when
+// the allocator has a file, until the worktree's ports are released.
+type PortAllocator struct {
+	mu    sync.Mutex
+	path  string // "" = in memory only
+	start int
+	end   int
+	ports map[string]map[string]int // Worktree -> name -> port
+}
+
+// NewPortAllocator creates an allocator handing out ports from start to
+// end inclusive, keeping its allocations in the file at path if path isn't
+// empty.
+func NewPortAllocator(path string, start, end int) (*PortAllocator, error) {
+	a := &PortAllocator{
+		path:  path,
+		start: start,
+		end:   end,
+		ports: make(map[string]map[string]int),
+	}
+	if path == "" {
+		return a, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &a.ports); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if a.ports == nil {
+		a.ports = make(map[string]map[string]int)
+	}
+	return a, nil
+}
+
+// Port returns the port allocated to worktree for name, allocating the
+// lowest port in the range that no worktree has and nothing is listening
+// on if there isn't one yet.
+func (a *PortAllocator) Port(worktree, name string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if port, ok := a.ports[worktree][name]; ok {
+		return port, nil
+	}
+
+	taken := make(map[int]bool)
+	for _, names := range a.ports {
+		for _, port := range names {
+			taken[port] = true
+		}
+	}
+	for port := a.start; port <= a.end; port++ {
+		if taken[port] || !portFree(port) {
+			continue
+		}
+		if a.ports[worktree] == nil {
+			a.ports[worktree] = make(map[string]int)
+		}
+		a.ports[worktree][name] = port
+		if err := a.save(); err != nil {
+			return 0, err
+		}
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free ports left in %d-%d for %s %q", a.start, a.end, worktree, name)
+}
+
+// Ports returns the ports allocated to worktree by name.
+func (a *PortAllocator) Ports(worktree string) map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ports := make(map[string]int, len(a.ports[worktree]))
+	for name, port := range a.ports[worktree] {
+		ports[name] = port
+	}
+	return ports
+}
+
+// Release frees the ports allocated to worktree, as when it's removed.
+func (a *PortAllocator) Release(worktree string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.ports[worktree]; !ok {
+		return nil
+	}
+	delete(a.ports, worktree)
+	return a.save()
+}
+
+// Worktrees returns the names of the worktrees with allocated ports.
+func (a *PortAllocator) Worktrees() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	names := make([]string, 0, len(a.ports))
+	for name := range a.ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// save writes the allocations to the allocator's file. Caller must hold
+// a.mu.
+func (a *PortAllocator) save() error {
+	if a.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(a.ports, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return err
+	}
+	tmp := a.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, a.path)
+}
+
+// portFree reports whether nothing is listening on port locally.
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
@@ -0,0 +1,85 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package worktree
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freeRange returns the first port of n consecutive ports nothing is
+// listening on.
+func freeRange(t *testing.T, n int) int {
+	t.Helper()
+	for start := 41000; start < 60000; start += n {
+		free := true
+		for p := start; p < start+n; p++ {
+			if !portFree(p) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return start
+		}
+	}
+	t.Fatal("no free port range")
+	return 0
+}
+
+func TestPortAllocator(t *testing.T) {
+	start := freeRange(t, 4)
+	path := filepath.Join(t.TempDir(), "ports.json")
+	a, err := NewPortAllocator(path, start, start+3)
+	require.NoError(t, err)
+
+	api, err := a.Port("main", "api")
+	require.NoError(t, err)
+	assert.Equal(t, start, api)
+	again, err := a.Port("main", "api")
+	require.NoError(t, err)
+	assert.Equal(t, api, again)
+
+	// Other worktrees and names get ports of their own
+	featureAPI, err := a.Port("feature", "api")
+	require.NoError(t, err)
+	assert.Equal(t, start+1, featureAPI)
+
+	// Ports something is listening on are skipped
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(start+2)))
+	require.NoError(t, err)
+	defer ln.Close()
+	db, err := a.Port("main", "db")
+	require.NoError(t, err)
+	assert.Equal(t, start+3, db)
+
+	_, err = a.Port("main", "cache")
+	assert.Error(t, err, "the range is used up")
+
+	// Allocations survive a restart
+	reloaded, err := NewPortAllocator(path, start, start+3)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"api": start, "db": start + 3}, reloaded.Ports("main"))
+	assert.Equal(t, []string{"feature", "main"}, reloaded.Worktrees())
+
+	// Released ports go to the next worktree that asks
+	require.NoError(t, reloaded.Release("feature"))
+	assert.Empty(t, reloaded.Ports("feature"))
+	port, err := reloaded.Port("other", "api")
+	require.NoError(t, err)
+	assert.Equal(t, start+1, port)
+}
+
+func TestPortAllocator_BadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ports.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+	_, err := NewPortAllocator(path, 20000, 20999)
+	assert.Error(t, err)
+}
@@ -120,6 +120,18 @@ type Client struct {
 	// Crashes provides access to crash history operations.
 	// Crashes store context from service crashes for debugging.
 	Crashes *CrashClient
+
+	// Proxy provides access to traffic captured by proxy listeners.
+	// Captured requests can be inspected and replayed.
+	Proxy *ProxyClient
+
+	// Config provides access to the server's layered configuration.
+	// Effective values show which file set each setting.
+	Config *ConfigClient
+
+	// Secrets manages the secrets referenced from service and workflow
+	// environments. Values are write-only.
+	Secrets *SecretClient
 }
 
 // Option configures a [Client]. Options are passed to [New] to customize
@@ -158,6 +170,9 @@ func New(baseURL string, opts ...Option) *Client {
 	c.Trace = &TraceClient{c: c}
 	c.Notify = &NotifyClient{c: c}
 	c.Crashes = &CrashClient{c: c}
+	c.Proxy = &ProxyClient{c: c}
+	c.Config = &ConfigClient{c: c}
+	c.Secrets = &SecretClient{c: c}
 
 	return c
 }
@@ -257,6 +272,15 @@ func (c *Client) postJSON(ctx context.Context, path string, body interface{}) (j
 	return c.do(ctx, http.MethodPost, path, bytes.NewReader(data))
 }
 
+// putJSON performs a PUT request with a JSON body.
+func (c *Client) putJSON(ctx context.Context, path string, body interface{}) (json.RawMessage, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.do(ctx, http.MethodPut, path, bytes.NewReader(data))
+}
+
 // delete performs a DELETE request to the given path.
 func (c *Client) delete(ctx context.Context, path string) (json.RawMessage, error) {
 	return c.do(ctx, http.MethodDelete, path, nil)
@@ -6,6 +6,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -389,6 +390,27 @@ func TestServiceClient_Error(t *testing.T) {
 	}
 }
 
+func TestServiceClient_Worktree(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/services/backend/logs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("worktree") != "feature" {
+			t.Errorf("worktree param = %q, want %q", r.URL.Query().Get("worktree"), "feature")
+		}
+		if r.URL.Query().Get("lines") != "50" {
+			t.Errorf("lines param = %q, want 50", r.URL.Query().Get("lines"))
+		}
+		apiHandler(map[string]interface{}{"service": "backend", "lines": []string{}}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	if _, err := c.Services.Worktree("feature").Logs(context.Background(), "backend", 50); err != nil {
+		t.Fatalf("Logs() error = %v", err)
+	}
+}
+
 func TestWorktreeClient_List(t *testing.T) {
 	worktrees := []Worktree{
 		{
@@ -536,6 +558,41 @@ func TestWorktreeClient_Remove(t *testing.T) {
 	})
 }
 
+func TestWorktreeClient_StartServices(t *testing.T) {
+	worktree := Worktree{
+		Path:     "/home/user/project-feature",
+		Branch:   "feature",
+		Services: true,
+		Ports:    map[string]int{"api": 20000},
+	}
+
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Method = %s, want POST", r.Method)
+		}
+		if r.URL.Path != "/api/v1/worktrees/feature/services/start" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		apiHandler(map[string]interface{}{"worktree": worktree}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	result, err := c.Worktrees.StartServices(context.Background(), "feature")
+
+	if err != nil {
+		t.Fatalf("StartServices() error = %v", err)
+	}
+
+	if !result.Services {
+		t.Error("result.Services = false, want true")
+	}
+
+	if result.Ports["api"] != 20000 {
+		t.Errorf("result.Ports[api] = %d, want 20000", result.Ports["api"])
+	}
+}
+
 func TestWorktree_Name(t *testing.T) {
 	wt := Worktree{Path: "/home/user/project-feature"}
 	if wt.Name() != "project-feature" {
@@ -651,6 +708,78 @@ func TestWorkflowClient_Run(t *testing.T) {
 	})
 }
 
+func TestWorkflowClient_History(t *testing.T) {
+	runs := []WorkflowStatus{
+		{ID: "test-2", WorkflowID: "test", Worktree: "feature", Commit: "abc", State: WorkflowStateFailed},
+	}
+
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/workflows/test/runs":
+			if got := r.URL.Query().Get("worktree"); got != "feature" {
+				t.Errorf("worktree = %q, want feature", got)
+			}
+			if got := r.URL.Query().Get("limit"); got != "5" {
+				t.Errorf("limit = %q, want 5", got)
+			}
+			apiHandler(runs, http.StatusOK)(w, r)
+		case "/api/v1/workflows/test/runs/test-2":
+			run := runs[0]
+			run.Output = "FAIL"
+			apiHandler(run, http.StatusOK)(w, r)
+		case "/api/v1/workflows/test/flaky":
+			apiHandler([]FlakyTest{{Test: "pkg.TestA", Commits: []string{"abc"}, Passes: 1, Failures: 2}}, http.StatusOK)(w, r)
+		case "/api/v1/workflows/test/runs/test-2/artifacts/dist/app v2.js":
+			w.Write([]byte("app"))
+		case "/api/v1/workflows/test/runs/test-2/artifacts/missing":
+			apiErrorHandler("NOT_FOUND", "artifact not found", http.StatusNotFound)(w, r)
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	ctx := context.Background()
+
+	got, err := c.Workflows.History(ctx, "test", &HistoryOptions{Worktree: "feature", Limit: 5})
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Commit != "abc" {
+		t.Errorf("History() = %+v, want run of commit abc", got)
+	}
+
+	run, err := c.Workflows.HistoryRun(ctx, "test", "test-2")
+	if err != nil {
+		t.Fatalf("HistoryRun() error = %v", err)
+	}
+	if run.Output != "FAIL" {
+		t.Errorf("Output = %q, want FAIL", run.Output)
+	}
+
+	flaky, err := c.Workflows.Flaky(ctx, "test")
+	if err != nil {
+		t.Fatalf("Flaky() error = %v", err)
+	}
+	if len(flaky) != 1 || flaky[0].Failures != 2 {
+		t.Errorf("Flaky() = %+v, want pkg.TestA with 2 failures", flaky)
+	}
+
+	body, err := c.Workflows.Artifact(ctx, "test", "test-2", "dist/app v2.js")
+	if err != nil {
+		t.Fatalf("Artifact() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != "app" {
+		t.Errorf("Artifact() = %q, %v, want app", data, err)
+	}
+	if _, err := c.Workflows.Artifact(ctx, "test", "test-2", "missing"); err == nil {
+		t.Error("Artifact() of a missing artifact succeeded")
+	}
+}
+
 func TestWorkflowClient_Status(t *testing.T) {
 	status := WorkflowStatus{
 		ID:       "build-123",
@@ -685,6 +814,40 @@ func TestWorkflowClient_Status(t *testing.T) {
 	}
 }
 
+func TestWorkflowClient_StatusSteps(t *testing.T) {
+	status := WorkflowStatus{
+		ID:    "check-1",
+		State: WorkflowStateFailed,
+		Error: "step test failed: exited with code 1",
+		Steps: []StepStatus{
+			{ID: "lint", State: WorkflowStateSuccess, Duration: time.Second},
+			{ID: "test", State: WorkflowStateFailed, ExitCode: 1, Error: "exited with code 1"},
+			{ID: "all", State: WorkflowStateFailed, Steps: []StepStatus{
+				{ID: "deploy", State: WorkflowStateSkipped},
+			}},
+		},
+	}
+
+	server := mockServer(t, apiHandler(status, http.StatusOK))
+	defer server.Close()
+
+	c := New(server.URL)
+	result, err := c.Workflows.Status(context.Background(), "check-1")
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if len(result.Steps) != 3 {
+		t.Fatalf("len(Steps) = %d, want 3", len(result.Steps))
+	}
+	if got := result.Steps[1]; got.State != WorkflowStateFailed || got.ExitCode != 1 {
+		t.Errorf("Steps[1] = %+v, want failed with exit code 1", got)
+	}
+	if got := result.Steps[2].Steps; len(got) != 1 || got[0].State != WorkflowStateSkipped {
+		t.Errorf("Steps[2].Steps = %+v, want skipped deploy", got)
+	}
+}
+
 func TestEventClient_List(t *testing.T) {
 	events := []Event{
 		{
@@ -741,6 +904,29 @@ func TestEventClient_List(t *testing.T) {
 			t.Fatalf("List() error = %v", err)
 		}
 	})
+
+	t.Run("with search and cursor", func(t *testing.T) {
+		server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			if got := r.URL.Query().Get("q"); got != "backend crashed" {
+				t.Errorf("q = %q, want %q", got, "backend crashed")
+			}
+			if got := r.URL.Query().Get("before"); got != "evt-42" {
+				t.Errorf("before = %q, want %q", got, "evt-42")
+			}
+			apiHandler(events, http.StatusOK)(w, r)
+		})
+		defer server.Close()
+
+		c := New(server.URL)
+		_, err := c.Events.List(context.Background(), &ListOptions{
+			Search: "backend crashed",
+			Before: "evt-42",
+		})
+
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+	})
 }
 
 func TestLogClient_List(t *testing.T) {
@@ -804,6 +990,48 @@ func TestLogClient_GetEntries(t *testing.T) {
 	}
 }
 
+func TestLogClient_Stats(t *testing.T) {
+	stats := LogStats{
+		Total:   3,
+		GroupBy: "path",
+		Groups:  []LogStatsGroup{{Key: "/api", Count: 3, Values: &LogValueStats{Count: 3, P95: 120}}},
+	}
+
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/logs/nginx/stats" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("source") != "history" || q.Get("group_by") != "path" || q.Get("field") != "duration" || q.Get("bucket") != "1m" || q.Get("top") != "5" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		if q.Get("start") == "" || q.Get("end") == "" {
+			t.Errorf("missing time range: %s", r.URL.RawQuery)
+		}
+		apiHandler(stats, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	result, err := c.Logs.Stats(context.Background(), "nginx", &LogStatsOptions{
+		History: true,
+		GroupBy: "path",
+		Field:   "duration",
+		Top:     5,
+		Bucket:  "1m",
+		Since:   time.Now().Add(-time.Hour),
+		Until:   time.Now(),
+	})
+
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if result.Total != 3 || len(result.Groups) != 1 || result.Groups[0].Values.P95 != 120 {
+		t.Errorf("Stats() = %+v", result)
+	}
+}
+
 func TestLogClient_GetHistoryEntries(t *testing.T) {
 	entries := []LogEntry{
 		{
@@ -1444,3 +1672,150 @@ func TestEventClient_ListWithAllOptions(t *testing.T) {
 		t.Fatalf("List() error = %v", err)
 	}
 }
+
+func TestProxyClient_List(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/proxy/requests" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("status") != "5xx" || query.Get("method") != "POST" || query.Get("min_duration") != "500ms" || query.Get("limit") != "20" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		apiHandler([]ProxyRequestSummary{{ID: 7, Method: "POST", URL: "/api/orders", Status: 502}}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	requests, err := c.Proxy.List(context.Background(), &ProxyRequestsOptions{
+		Method:      "POST",
+		Status:      "5xx",
+		MinDuration: 500 * time.Millisecond,
+		Limit:       20,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].ID != 7 || requests[0].Status != 502 {
+		t.Errorf("List() = %+v", requests)
+	}
+}
+
+func TestProxyClient_Replay(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/proxy/requests/7/replay" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var opts ReplayOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		if opts.Method != "PUT" || opts.Body == nil || *opts.Body != "{}" || opts.Worktree != "feature-x" {
+			t.Errorf("unexpected options: %+v", opts)
+		}
+		apiHandler(ProxyRequest{
+			ProxyRequestSummary: ProxyRequestSummary{ID: 8, ReplayOf: 7, Status: 200, Worktree: "feature-x"},
+			ResponseBody:        ProxyBody{Data: "AAE=", Base64: true, Size: 2},
+		}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	body := "{}"
+	req, err := c.Proxy.Replay(context.Background(), 7, &ReplayOptions{Method: "PUT", Body: &body, Worktree: "feature-x"})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if req.ID != 8 || req.ReplayOf != 7 || req.Worktree != "feature-x" {
+		t.Errorf("Replay() = %+v", req)
+	}
+	data, err := req.ResponseBody.Bytes()
+	if err != nil || len(data) != 2 || data[1] != 1 {
+		t.Errorf("ResponseBody.Bytes() = %v, %v", data, err)
+	}
+}
+
+func TestProxyClient_SetFaults(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/proxy/faults" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Listener string      `json:"listener"`
+			Route    int         `json:"route"`
+			Faults   ProxyFaults `json:"faults"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Listener != ":8443" || body.Route != 1 || !body.Faults.Enabled || body.Faults.ErrorRate != 5 {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		apiHandler([]ProxyRouteFaults{{Listener: ":8443", Route: 1, Name: "*", Faults: body.Faults}}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	faults, err := c.Proxy.SetFaults(context.Background(), ":8443", 1, ProxyFaults{Enabled: true, ErrorRate: 5})
+	if err != nil {
+		t.Fatalf("SetFaults() error = %v", err)
+	}
+	if len(faults) != 1 || faults[0].Name != "*" || faults[0].Faults.ErrorRate != 5 {
+		t.Errorf("SetFaults() = %+v", faults)
+	}
+}
+
+func TestConfigClient_Show(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/config" || r.URL.Query().Get("effective") != "1" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		apiHandler(Config{
+			Layers: []ConfigLayer{{Kind: ConfigLayerBase, Path: "/repo/trellis.hjson"}},
+			Values: []ConfigValue{{Path: "server.port", Value: 1234, Origin: ConfigLayer{Kind: ConfigLayerDefault}}},
+		}, http.StatusOK)(w, r)
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	cfg, err := c.Config.Show(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Show() error = %v", err)
+	}
+	if len(cfg.Layers) != 1 || len(cfg.Values) != 1 || cfg.Values[0].Origin.Kind != ConfigLayerDefault {
+		t.Errorf("Show() = %+v", cfg)
+	}
+}
+
+func TestSecretClient(t *testing.T) {
+	server := mockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/secrets":
+			apiHandler([]Secret{{Name: "stripe_key", Source: SecretSourceStore}}, http.StatusOK)(w, r)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/secrets":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["name"] != "stripe_key" || body["value"] != "sk_test_1" {
+				t.Errorf("Set body = %v", body)
+			}
+			apiHandler(Secret{Name: "stripe_key", Source: SecretSourceStore}, http.StatusOK)(w, r)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/secrets/aws/key":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	})
+	defer server.Close()
+
+	c := New(server.URL)
+	ctx := context.Background()
+	if err := c.Secrets.Set(ctx, "stripe_key", "sk_test_1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	list, err := c.Secrets.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "stripe_key" {
+		t.Errorf("List() = %+v", list)
+	}
+	if err := c.Secrets.Delete(ctx, "aws/key"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ConfigClient provides access to the configuration the server is running
+// with, assembled from layered files: includes, the config file itself,
+// per-user and local overrides, and the active worktree's overlay.
+type ConfigClient struct {
+	c *Client
+}
+
+// Config layer kinds, from lowest to highest precedence.
+const (
+	ConfigLayerDefault  = "default"  // Filled in by Trellis, not set in any file
+	ConfigLayerInclude  = "include"  // A file named by another file's include
+	ConfigLayerBase     = "base"     // The config file itself
+	ConfigLayerUser     = "user"     // Per-user overrides in the user config directory
+	ConfigLayerLocal    = "local"    // Git-ignored overrides next to the config file
+	ConfigLayerWorktree = "worktree" // The active worktree's overlay file
+)
+
+// ConfigLayer is a file that contributes settings to the config.
+type ConfigLayer struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"` // Empty for defaults
+}
+
+// ConfigValue is one setting of the effective config and the layer it came from.
+type ConfigValue struct {
+	Path   string      `json:"path"` // e.g. "server.port" or "services[api].env.DEBUG"
+	Value  interface{} `json:"value"`
+	Origin ConfigLayer `json:"origin"`
+}
+
+// Config describes how the server's config was assembled.
+type Config struct {
+	Layers []ConfigLayer `json:"layers"`           // Lowest precedence first
+	Values []ConfigValue `json:"values,omitempty"` // Set when effective values were requested
+}
+
+// Show returns the files the config was assembled from. With effective set,
+// every setting of the merged config is included with the layer that set it.
+func (cc *ConfigClient) Show(ctx context.Context, effective bool) (*Config, error) {
+	path := "/api/v1/config"
+	if effective {
+		path += "?effective=1"
+	}
+
+	data, err := cc.c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -39,6 +39,15 @@ type ListOptions struct {
 
 	// Until filters to events before this time.
 	Until time.Time
+
+	// Search restricts results to events whose type, worktree, or payload
+	// contain every whitespace-separated term (case-insensitive).
+	Search string
+
+	// Before is a pagination cursor: only events older than the event with
+	// this ID are returned. Pass the ID of the oldest event from the previous
+	// page to fetch the next (older) page.
+	Before string
 }
 
 // List returns recent events from the event log.
@@ -64,6 +73,12 @@ func (e *EventClient) List(ctx context.Context, opts *ListOptions) ([]Event, err
 		if !opts.Until.IsZero() {
 			params.Set("until", opts.Until.Format(time.RFC3339))
 		}
+		if opts.Search != "" {
+			params.Set("q", opts.Search)
+		}
+		if opts.Before != "" {
+			params.Set("before", opts.Before)
+		}
 		if len(params) > 0 {
 			path += "?" + params.Encode()
 		}
@@ -200,6 +200,65 @@ func (l *LogClient) GetHistoryEntries(ctx context.Context, name string, opts *Lo
 	return entries, nil
 }
 
+// Stats aggregates a log viewer's entries: counts and numeric summaries
+// grouped by a field, plus an optional time-bucketed histogram.
+//
+//	stats, err := client.Logs.Stats(ctx, "nginx", &LogStatsOptions{
+//		GroupBy: "path",
+//		Field:   "duration",
+//		Bucket:  "1m",
+//	})
+func (l *LogClient) Stats(ctx context.Context, name string, opts *LogStatsOptions) (*LogStats, error) {
+	params := url.Values{}
+
+	if opts != nil {
+		if opts.History {
+			params.Set("source", "history")
+		}
+		if opts.Filter != "" {
+			params.Set("filter", opts.Filter)
+		}
+		if opts.GroupBy != "" {
+			params.Set("group_by", opts.GroupBy)
+		}
+		if opts.Field != "" {
+			params.Set("field", opts.Field)
+		}
+		if opts.Top > 0 {
+			params.Set("top", fmt.Sprintf("%d", opts.Top))
+		}
+		if opts.Bucket != "" {
+			params.Set("bucket", opts.Bucket)
+		}
+		if !opts.Since.IsZero() {
+			params.Set("start", opts.Since.Format(time.RFC3339))
+		}
+		if !opts.Until.IsZero() {
+			params.Set("end", opts.Until.Format(time.RFC3339))
+		}
+		if opts.Grep != "" {
+			params.Set("grep", opts.Grep)
+		}
+	}
+
+	path := "/api/v1/logs/" + url.PathEscape(name) + "/stats"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	data, err := l.c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats LogStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse log stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
 // GetHistory returns historical log entries from a log viewer's history files.
 //
 // Unlike [LogClient.GetHistoryEntries] which returns parsed entries via options,
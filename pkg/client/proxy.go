@@ -0,0 +1,237 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ProxyClient provides access to traffic captured by proxy listeners.
+type ProxyClient struct {
+	c *Client
+}
+
+// ProxyRequestSummary describes a captured request without its headers and bodies.
+type ProxyRequestSummary struct {
+	ID           uint64    `json:"id"`
+	Listener     string    `json:"listener"`
+	Time         time.Time `json:"time"`
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`
+	Host         string    `json:"host"`
+	RemoteAddr   string    `json:"remote_addr"`
+	Route        string    `json:"route"`
+	Upstream     string    `json:"upstream,omitempty"`
+	Worktree     string    `json:"worktree,omitempty"` // Set on listeners that route by worktree
+	Status       int       `json:"status"`
+	DurationMS   float64   `json:"duration_ms"`
+	RequestSize  int64     `json:"request_size"`
+	ResponseSize int64     `json:"response_size"`
+	Error        string    `json:"error,omitempty"`
+	Fault        string    `json:"fault,omitempty"` // Faults injected into the request, e.g. "latency=250ms, error=503"
+	Mock         string    `json:"mock,omitempty"`  // Stub or fixture that answered, e.g. "stub 0", or "recorded <fixture>"
+	ReplayOf     uint64    `json:"replay_of,omitempty"`
+}
+
+// ProxyBody is a captured request or response body.
+type ProxyBody struct {
+	Data      string `json:"data,omitempty"`
+	Base64    bool   `json:"base64,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+	Size      int64  `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Bytes returns the body data, decoding base64 for binary bodies.
+func (b ProxyBody) Bytes() ([]byte, error) {
+	if b.Base64 {
+		return base64.StdEncoding.DecodeString(b.Data)
+	}
+	return []byte(b.Data), nil
+}
+
+// ProxyRequest is a captured request/response pair.
+type ProxyRequest struct {
+	ProxyRequestSummary
+	RequestHeaders  map[string][]string `json:"request_headers"`
+	RequestBody     ProxyBody           `json:"request_body"`
+	ResponseHeaders map[string][]string `json:"response_headers"`
+	ResponseBody    ProxyBody           `json:"response_body"`
+}
+
+// ProxyRequestsOptions filters captured requests.
+type ProxyRequestsOptions struct {
+	Listener    string        // Listener address (e.g., ":443")
+	Method      string        // HTTP method
+	Status      string        // Exact code ("404") or class ("5xx")
+	Path        string        // Substring of the path and query
+	Upstream    string        // Substring of the upstream host
+	MinDuration time.Duration // Only requests at least this slow
+	Limit       int           // Maximum requests to return, newest first
+}
+
+// ReplayOptions edits a captured request before it is replayed. Zero fields
+// keep the captured value.
+type ReplayOptions struct {
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url,omitempty"`     // Path and query
+	Headers map[string]string `json:"headers,omitempty"` // Headers to set; an empty value removes the header
+	Body    *string           `json:"body,omitempty"`    // Replacement request body
+
+	// Worktree sends the replay to this worktree's upstreams, on listeners
+	// that route by worktree
+	Worktree string `json:"worktree,omitempty"`
+}
+
+// List returns captured requests, newest first.
+func (p *ProxyClient) List(ctx context.Context, opts *ProxyRequestsOptions) ([]ProxyRequestSummary, error) {
+	path := "/api/v1/proxy/requests"
+
+	if opts != nil {
+		params := url.Values{}
+		if opts.Listener != "" {
+			params.Set("listener", opts.Listener)
+		}
+		if opts.Method != "" {
+			params.Set("method", opts.Method)
+		}
+		if opts.Status != "" {
+			params.Set("status", opts.Status)
+		}
+		if opts.Path != "" {
+			params.Set("path", opts.Path)
+		}
+		if opts.Upstream != "" {
+			params.Set("upstream", opts.Upstream)
+		}
+		if opts.MinDuration > 0 {
+			params.Set("min_duration", opts.MinDuration.String())
+		}
+		if opts.Limit > 0 {
+			params.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	data, err := p.c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []ProxyRequestSummary
+	if err := json.Unmarshal(data, &requests); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy requests: %w", err)
+	}
+	return requests, nil
+}
+
+// Get returns a captured request with its headers and bodies.
+func (p *ProxyClient) Get(ctx context.Context, id uint64) (*ProxyRequest, error) {
+	data, err := p.c.get(ctx, "/api/v1/proxy/requests/"+strconv.FormatUint(id, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	var req ProxyRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy request: %w", err)
+	}
+	return &req, nil
+}
+
+// Replay resends a captured request through its listener's current routes
+// and returns the newly captured exchange. opts may be nil.
+func (p *ProxyClient) Replay(ctx context.Context, id uint64, opts *ReplayOptions) (*ProxyRequest, error) {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+	data, err := p.c.postJSON(ctx, "/api/v1/proxy/requests/"+strconv.FormatUint(id, 10)+"/replay", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var req ProxyRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy request: %w", err)
+	}
+	return &req, nil
+}
+
+// Clear drops every captured request.
+func (p *ProxyClient) Clear(ctx context.Context) error {
+	_, err := p.c.delete(ctx, "/api/v1/proxy/requests")
+	return err
+}
+
+// ProxyFaults are the fault injection settings of a route. Durations use Go
+// syntax ("250ms") and rates are percentages.
+type ProxyFaults struct {
+	Enabled            bool    `json:"enabled"`
+	Latency            string  `json:"latency,omitempty"`
+	LatencyMax         string  `json:"latency_max,omitempty"` // Latency is uniform between Latency and LatencyMax
+	ErrorRate          float64 `json:"error_rate,omitempty"`
+	ErrorStatus        int     `json:"error_status,omitempty"` // Default 503
+	AbortRate          float64 `json:"abort_rate,omitempty"`
+	Bandwidth          int     `json:"bandwidth,omitempty"` // Response bytes per second
+	WebSocketDropRate  float64 `json:"websocket_drop_rate,omitempty"`
+	WebSocketDropAfter string  `json:"websocket_drop_after,omitempty"` // Default 10s
+}
+
+// ProxyRouteFaults describes the fault injection settings of one route.
+type ProxyRouteFaults struct {
+	Listener string      `json:"listener"`
+	Route    int         `json:"route"` // Index in the listener's routes
+	Name     string      `json:"name"`  // Route matchers summary
+	Faults   ProxyFaults `json:"faults"`
+}
+
+// Faults returns the fault injection settings of every route.
+func (p *ProxyClient) Faults(ctx context.Context) ([]ProxyRouteFaults, error) {
+	data, err := p.c.get(ctx, "/api/v1/proxy/faults")
+	if err != nil {
+		return nil, err
+	}
+	return parseRouteFaults(data)
+}
+
+// SetFaults replaces the fault injection settings of a listener's route
+// until trellis restarts, and returns the settings of every route.
+func (p *ProxyClient) SetFaults(ctx context.Context, listener string, route int, faults ProxyFaults) ([]ProxyRouteFaults, error) {
+	body := struct {
+		Listener string      `json:"listener"`
+		Route    int         `json:"route"`
+		Faults   ProxyFaults `json:"faults"`
+	}{listener, route, faults}
+	data, err := p.c.putJSON(ctx, "/api/v1/proxy/faults", body)
+	if err != nil {
+		return nil, err
+	}
+	return parseRouteFaults(data)
+}
+
+// ClearFaults turns fault injection off on every route, keeping the
+// settings, and returns the settings of every route.
+func (p *ProxyClient) ClearFaults(ctx context.Context) ([]ProxyRouteFaults, error) {
+	data, err := p.c.delete(ctx, "/api/v1/proxy/faults")
+	if err != nil {
+		return nil, err
+	}
+	return parseRouteFaults(data)
+}
+
+func parseRouteFaults(data []byte) ([]ProxyRouteFaults, error) {
+	var faults []ProxyRouteFaults
+	if err := json.Unmarshal(data, &faults); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy faults: %w", err)
+	}
+	return faults, nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2026 Groups.io, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// SecretClient manages the secrets that service and workflow environments
+// reference with {{ secret "name" }}. Values can be set but are never
+// returned by the server.
+type SecretClient struct {
+	c *Client
+}
+
+// Secret sources.
+const (
+	SecretSourceStore   = "store"   // The project's local encrypted store
+	SecretSourceCommand = "command" // Read from the configured secrets command
+)
+
+// Secret describes a secret without its value.
+type Secret struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// List returns the secrets in the local store and those read from the
+// secrets command so far.
+func (s *SecretClient) List(ctx context.Context) ([]Secret, error) {
+	data, err := s.c.get(ctx, "/api/v1/secrets")
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Secret
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	return list, nil
+}
+
+// Set stores a secret in the local store, replacing any previous value.
+func (s *SecretClient) Set(ctx context.Context, name, value string) error {
+	_, err := s.c.postJSON(ctx, "/api/v1/secrets", map[string]string{"name": name, "value": value})
+	return err
+}
+
+// Delete removes a secret from the local store.
+func (s *SecretClient) Delete(ctx context.Context, name string) error {
+	_, err := s.c.delete(ctx, "/api/v1/secrets/"+url.PathEscape(name))
+	return err
+}
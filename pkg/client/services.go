@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 // ServiceClient provides access to service management operations.
@@ -19,8 +20,35 @@ import (
 // Access this client through [Client.Services]:
 //
 //	services, err := client.Services.List(ctx)
+//
+// The client addresses the active worktree's services; use [ServiceClient.Worktree]
+// for the services another worktree runs alongside them.
 type ServiceClient struct {
-	c *Client
+	c        *Client
+	worktree string // "" = the active worktree
+}
+
+// Worktree returns a client for the services of the named worktree, which
+// run alongside the active worktree's when worktree.concurrent is set. The
+// active worktree's name addresses its services like the unscoped client.
+//
+// Example:
+//
+//	services, err := client.Services.Worktree("feature-x").List(ctx)
+func (s *ServiceClient) Worktree(name string) *ServiceClient {
+	return &ServiceClient{c: s.c, worktree: name}
+}
+
+// path returns the API path of p addressed to the client's worktree.
+func (s *ServiceClient) path(p string) string {
+	if s.worktree == "" {
+		return p
+	}
+	sep := "?"
+	if strings.Contains(p, "?") {
+		sep = "&"
+	}
+	return p + sep + "worktree=" + url.QueryEscape(s.worktree)
 }
 
 // List returns all configured services and their current status.
@@ -32,7 +60,7 @@ type ServiceClient struct {
 //	    fmt.Printf("%s: %s\n", svc.Name, svc.Status.State)
 //	}
 func (s *ServiceClient) List(ctx context.Context) ([]Service, error) {
-	data, err := s.c.get(ctx, "/api/v1/services")
+	data, err := s.c.get(ctx, s.path("/api/v1/services"))
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +77,7 @@ func (s *ServiceClient) List(ctx context.Context) ([]Service, error) {
 //
 // Returns an error if the service does not exist.
 func (s *ServiceClient) Get(ctx context.Context, name string) (*Service, error) {
-	data, err := s.c.get(ctx, "/api/v1/services/"+url.PathEscape(name))
+	data, err := s.c.get(ctx, s.path("/api/v1/services/"+url.PathEscape(name)))
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +95,7 @@ func (s *ServiceClient) Get(ctx context.Context, name string) (*Service, error)
 // Returns the service with its updated state. If the service is already
 // running, this is a no-op and returns the current state.
 func (s *ServiceClient) Start(ctx context.Context, name string) (*Service, error) {
-	data, err := s.c.post(ctx, "/api/v1/services/"+url.PathEscape(name)+"/start")
+	data, err := s.c.post(ctx, s.path("/api/v1/services/"+url.PathEscape(name)+"/start"))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +113,7 @@ func (s *ServiceClient) Start(ctx context.Context, name string) (*Service, error
 // The service process receives a SIGTERM signal and is given time to shut down
 // gracefully. Returns the service with its updated state.
 func (s *ServiceClient) Stop(ctx context.Context, name string) (*Service, error) {
-	data, err := s.c.post(ctx, "/api/v1/services/"+url.PathEscape(name)+"/stop")
+	data, err := s.c.post(ctx, s.path("/api/v1/services/"+url.PathEscape(name)+"/stop"))
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +131,7 @@ func (s *ServiceClient) Stop(ctx context.Context, name string) (*Service, error)
 // If the service is running, it is stopped first, then started. If the service
 // is already stopped, it is simply started. Returns the service with its updated state.
 func (s *ServiceClient) Restart(ctx context.Context, name string) (*Service, error) {
-	data, err := s.c.post(ctx, "/api/v1/services/"+url.PathEscape(name)+"/restart")
+	data, err := s.c.post(ctx, s.path("/api/v1/services/"+url.PathEscape(name)+"/restart"))
 	if err != nil {
 		return nil, err
 	}
@@ -124,13 +152,13 @@ func (s *ServiceClient) Restart(ctx context.Context, name string) (*Service, err
 // For structured log access, consider using the log viewer APIs instead.
 func (s *ServiceClient) Logs(ctx context.Context, name string, lines int) ([]byte, error) {
 	path := fmt.Sprintf("/api/v1/services/%s/logs?lines=%d", url.PathEscape(name), lines)
-	return s.c.get(ctx, path)
+	return s.c.get(ctx, s.path(path))
 }
 
 // ClearLogs clears the in-memory log buffer for a service.
 //
 // This removes all buffered log lines. It does not affect log files on disk.
 func (s *ServiceClient) ClearLogs(ctx context.Context, name string) error {
-	_, err := s.c.delete(ctx, "/api/v1/services/"+url.PathEscape(name)+"/logs")
+	_, err := s.c.delete(ctx, s.path("/api/v1/services/"+url.PathEscape(name)+"/logs"))
 	return err
 }
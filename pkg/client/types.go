@@ -119,6 +119,14 @@ type Worktree struct {
 
 	// Active is true if this is the currently active worktree.
 	Active bool `json:"Active"`
+
+	// Services is true if the worktree's services are running alongside
+	// the active worktree's (worktree.concurrent).
+	Services bool `json:"Services"`
+
+	// Ports are the ports allocated to the worktree by name, as used by
+	// {{.Worktree.Port "name"}} in the config.
+	Ports map[string]int `json:"Ports,omitempty"`
 }
 
 // Name returns the worktree name, which is the last component of the path.
@@ -171,6 +179,70 @@ type Workflow struct {
 
 	// Inputs defines the input parameters for this workflow.
 	Inputs []WorkflowInput `json:"Inputs"`
+
+	// Steps are the named steps of a workflow run as a dependency graph
+	// (mutually exclusive with Command and Commands).
+	Steps []WorkflowStep `json:"Steps"`
+
+	// Triggers start runs on file changes, events and schedules. It is nil
+	// for workflows that only run on request.
+	Triggers *WorkflowTriggers `json:"Triggers"`
+}
+
+// WorkflowTriggers define what starts a workflow without anyone asking.
+type WorkflowTriggers struct {
+	// Files are globs of files, relative to the worktree root, whose
+	// changes start a run.
+	Files []string `json:"Files"`
+
+	// Ignore are globs of files and directories not to watch.
+	Ignore []string `json:"Ignore"`
+
+	// Events are event type patterns that start a run.
+	Events []string `json:"Events"`
+
+	// Schedule is a cron schedule.
+	Schedule string `json:"Schedule"`
+
+	// Debounce is how long file changes and events must settle before a
+	// run starts.
+	Debounce time.Duration `json:"Debounce"`
+
+	// Policy is what happens when the workflow is triggered while a
+	// triggered run is in flight: "queue", "cancel" or "skip". Empty means
+	// "queue".
+	Policy string `json:"Policy"`
+}
+
+// WorkflowStep is a named step of a workflow.
+type WorkflowStep struct {
+	// ID identifies the step in Needs and conditions.
+	ID string `json:"ID"`
+
+	// Name is the display name, defaulting to ID.
+	Name string `json:"Name"`
+
+	// Command is the command the step runs (mutually exclusive with Workflow).
+	Command []string `json:"Command"`
+
+	// Workflow is the ID of another workflow the step runs.
+	Workflow string `json:"Workflow"`
+
+	// Inputs are the inputs passed to Workflow.
+	Inputs map[string]any `json:"Inputs"`
+
+	// Needs lists the steps that must finish before this step starts.
+	Needs []string `json:"Needs"`
+
+	// If is the condition under which the step runs, such as "failure" or
+	// "always". Empty means all needed steps succeeded.
+	If string `json:"If"`
+
+	// ContinueOnError keeps a failure of the step from failing the run.
+	ContinueOnError bool `json:"ContinueOnError"`
+
+	// Timeout is the step's time limit; zero means no limit.
+	Timeout time.Duration `json:"Timeout"`
 }
 
 // WorkflowInput defines a parameter that can be passed when running a workflow.
@@ -211,9 +283,26 @@ type WorkflowStatus struct {
 	// ID is the workflow identifier.
 	ID string `json:"ID"`
 
+	// WorkflowID is the ID of the workflow this is a run of.
+	WorkflowID string `json:"WorkflowID"`
+
 	// Name is the workflow display name.
 	Name string `json:"Name"`
 
+	// Worktree is the worktree the run was started for, if one was given.
+	Worktree string `json:"Worktree"`
+
+	// Commit is the commit checked out in the working directory when the
+	// run started, empty outside a git repository.
+	Commit string `json:"Commit"`
+
+	// Dirty is true when the working directory had uncommitted changes to
+	// tracked files.
+	Dirty bool `json:"Dirty"`
+
+	// Inputs holds the input values the run was started with.
+	Inputs map[string]any `json:"Inputs"`
+
 	// State is the current execution state.
 	// See WorkflowState* constants for possible values.
 	State string `json:"State"`
@@ -245,6 +334,136 @@ type WorkflowStatus struct {
 
 	// Error contains the error message if the workflow failed.
 	Error string `json:"Error"`
+
+	// Steps reports each step of a workflow composed of steps, in
+	// configuration order. It is empty for command workflows.
+	Steps []StepStatus `json:"Steps"`
+
+	// Trigger records what started the run. It is nil for runs started on
+	// request.
+	Trigger *RunTrigger `json:"Trigger"`
+
+	// Artifacts lists the files kept from the run, which can be downloaded
+	// with [WorkflowClient.Artifact].
+	Artifacts []Artifact `json:"Artifacts"`
+
+	// Coverage summarizes the coverage files among the artifacts. It is nil
+	// when there are none.
+	Coverage *Coverage `json:"Coverage"`
+
+	// CacheKey is the key the run's results are cached under, for
+	// workflows with a cache key.
+	CacheKey string `json:"CacheKey"`
+
+	// CachedFrom is the ID of the run whose results were restored instead
+	// of running the workflow, if any.
+	CachedFrom string `json:"CachedFrom"`
+}
+
+// Artifact is a file kept from a workflow run.
+type Artifact struct {
+	// Path is the file's path relative to the working directory.
+	Path string `json:"Path"`
+
+	// Size is the file's size in bytes.
+	Size int64 `json:"Size"`
+
+	// Step is the path of the step that produced the file, such as
+	// "build" or "ci/lint". It is empty for the workflow's own artifacts.
+	Step string `json:"Step"`
+}
+
+// Coverage summarizes the coverage files kept from a workflow run.
+type Coverage struct {
+	// Covered and Total count the statements (Go) or lines covered and
+	// instrumented across all files.
+	Covered int `json:"Covered"`
+	Total   int `json:"Total"`
+
+	// Files reports each source file, sorted by path.
+	Files []FileCoverage `json:"Files"`
+}
+
+// Percent returns the percentage of statements or lines covered.
+func (c Coverage) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return 100 * float64(c.Covered) / float64(c.Total)
+}
+
+// FileCoverage is the coverage of one source file.
+type FileCoverage struct {
+	// File is the source file, relative to the working directory when
+	// it's under it.
+	File string `json:"File"`
+
+	// Covered and Total count the file's statements or lines.
+	Covered int `json:"Covered"`
+	Total   int `json:"Total"`
+}
+
+// Percent returns the percentage of the file's statements or lines covered.
+func (f FileCoverage) Percent() float64 {
+	if f.Total == 0 {
+		return 0
+	}
+	return 100 * float64(f.Covered) / float64(f.Total)
+}
+
+// RunTrigger records what started a triggered workflow run.
+type RunTrigger struct {
+	// Type is "files", "event" or "schedule".
+	Type string `json:"Type"`
+
+	// Detail lists the changed files or event types, or gives the schedule.
+	Detail string `json:"Detail"`
+}
+
+// StepStatus is the status of one step of a workflow run.
+type StepStatus struct {
+	// ID is the step identifier.
+	ID string `json:"ID"`
+
+	// Name is the step display name.
+	Name string `json:"Name"`
+
+	// State is the step's execution state: one of the WorkflowState*
+	// constants or WorkflowStateSkipped.
+	State string `json:"State"`
+
+	// StartedAt is when the step started; zero if it never ran.
+	StartedAt time.Time `json:"StartedAt"`
+
+	// FinishedAt is when the step finished.
+	FinishedAt time.Time `json:"FinishedAt"`
+
+	// Duration is how long the step ran.
+	Duration time.Duration `json:"Duration"`
+
+	// ExitCode is the exit code of the step's failing command.
+	ExitCode int `json:"ExitCode"`
+
+	// Output contains the step's combined stdout/stderr output.
+	Output string `json:"Output"`
+
+	// Summary aggregates the step's parsed output. It is nil for steps
+	// without an output parser.
+	Summary *WorkflowSummary `json:"Summary"`
+
+	// Error describes why the step failed or was canceled.
+	Error string `json:"Error"`
+
+	// Steps are the steps of a reused workflow composed of steps.
+	Steps []StepStatus `json:"Steps"`
+
+	// CacheKey is the key the step's results are cached under, for steps
+	// with a cache key.
+	CacheKey string `json:"CacheKey"`
+
+	// CachedFrom is the ID of the run whose results of this step were
+	// restored instead of running it, if any.
+	CachedFrom string `json:"CachedFrom"`
 }
 
 // WorkflowSummary is a structured rollup of a workflow run's parsed output.
@@ -268,6 +487,54 @@ type WorkflowSummary struct {
 	FirstError string `json:"FirstError"`
 }
 
+// TestResult is a test's result in one workflow run: "pass", "fail", or
+// "unknown" when the run failed more tests than it lists.
+type TestResult string
+
+// TestTimeline is the pass/fail history of the tests that failed in a
+// workflow's recent runs.
+type TestTimeline struct {
+	// Runs are the runs that reported test results, oldest first, without
+	// their output.
+	Runs []WorkflowStatus `json:"Runs"`
+
+	// Tests are the tests that failed at least once, most failures first.
+	Tests []TestHistory `json:"Tests"`
+}
+
+// TestHistory is one test's results across the runs of a [TestTimeline].
+type TestHistory struct {
+	// Test is "package.TestName", as in [WorkflowSummary.FailedTests].
+	Test string `json:"Test"`
+
+	// Results holds the test's result in each run of the timeline.
+	Results []TestResult `json:"Results"`
+
+	// Failures is the number of runs the test failed in.
+	Failures int `json:"Failures"`
+
+	// Flips counts changes between passing and failing from one run to
+	// the next.
+	Flips int `json:"Flips"`
+}
+
+// FlakyTest is a test that both passed and failed on the same commit.
+type FlakyTest struct {
+	// Test is "package.TestName", as in [WorkflowSummary.FailedTests].
+	Test string `json:"Test"`
+
+	// Commits are the commits the test both passed and failed on.
+	Commits []string `json:"Commits"`
+
+	// Passes and Failures count the test's passing and failing runs on
+	// those commits.
+	Passes   int `json:"Passes"`
+	Failures int `json:"Failures"`
+
+	// LastFailure is the ID of the last run the test failed in.
+	LastFailure string `json:"LastFailure"`
+}
+
 // WorkflowState constants define the possible states of a workflow execution.
 const (
 	// WorkflowStateIdle indicates the workflow is not running.
@@ -287,6 +554,10 @@ const (
 
 	// WorkflowStateCanceled indicates the workflow was canceled.
 	WorkflowStateCanceled = "canceled"
+
+	// WorkflowStateSkipped indicates a workflow step did not run because a
+	// step it needs failed or its condition was false.
+	WorkflowStateSkipped = "skipped"
 )
 
 // Event represents a Trellis event from the event log.
@@ -334,6 +605,10 @@ type TraceRequest struct {
 	// ExpandByID enables two-pass searching: first find matching entries,
 	// then expand the time window to find related entries.
 	ExpandByID bool `json:"expand_by_id"`
+
+	// Filter is an optional log filter expression (the same syntax as the
+	// log viewer filter box) that matched entries must also satisfy.
+	Filter string `json:"filter,omitempty"`
 }
 
 // TraceResult is the initial response when starting a trace query.
@@ -570,3 +845,93 @@ type LogEntriesOptions struct {
 	// After specifies how many context lines to include after each grep match.
 	After int
 }
+
+// LogStatsOptions specifies an aggregation over a log viewer's entries.
+type LogStatsOptions struct {
+	// History scans rotated history files instead of the live buffer.
+	// Since and Until are required when History is set.
+	History bool
+
+	// Filter is a filter expression selecting the entries to aggregate.
+	Filter string
+
+	// GroupBy is the field to group entries by (e.g., "path").
+	GroupBy string
+
+	// Field is a numeric field to summarize (e.g., "duration").
+	Field string
+
+	// Top limits the number of groups returned. The server default is 10.
+	Top int
+
+	// Bucket is the histogram bucket width (e.g., "1m"), or "auto".
+	// Empty means no histogram.
+	Bucket string
+
+	// Since limits the aggregation to entries after this time.
+	Since time.Time
+
+	// Until limits the aggregation to entries before this time.
+	Until time.Time
+
+	// Grep filters history entries by pattern before aggregation.
+	Grep string
+}
+
+// LogValueStats summarizes the numeric values of a log field.
+type LogValueStats struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	P50   float64 `json:"p50"`
+	P95   float64 `json:"p95"`
+	P99   float64 `json:"p99"`
+}
+
+// LogStatsGroup is the aggregate for one group-by value.
+type LogStatsGroup struct {
+	Key    string         `json:"key"`
+	Count  int            `json:"count"`
+	Values *LogValueStats `json:"values,omitempty"`
+}
+
+// LogHistogramBucket counts entries in one time bucket, by level.
+type LogHistogramBucket struct {
+	Start  time.Time      `json:"start"`
+	Count  int            `json:"count"`
+	Levels map[string]int `json:"levels,omitempty"`
+}
+
+// LogStats is the result of a log aggregation.
+type LogStats struct {
+	// Total is the number of entries aggregated.
+	Total int `json:"total"`
+
+	// Oldest and Newest are the timestamps of the first and last entries.
+	Oldest time.Time `json:"oldest"`
+	Newest time.Time `json:"newest"`
+
+	// GroupBy and Field echo the request.
+	GroupBy string `json:"group_by,omitempty"`
+	Field   string `json:"field,omitempty"`
+
+	// Groups are ordered by count, largest first.
+	Groups []LogStatsGroup `json:"groups,omitempty"`
+
+	// Ungrouped counts entries without the group-by field.
+	Ungrouped int `json:"ungrouped,omitempty"`
+
+	// OtherGroups is the number of groups beyond the Top limit.
+	OtherGroups int `json:"other_groups,omitempty"`
+
+	// Values summarizes Field across all entries.
+	Values *LogValueStats `json:"values,omitempty"`
+
+	// Bucket is the histogram bucket width.
+	Bucket string `json:"bucket,omitempty"`
+
+	// Histogram is ordered oldest first.
+	Histogram []LogHistogramBucket `json:"histogram,omitempty"`
+}
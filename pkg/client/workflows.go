@@ -7,7 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 // WorkflowClient provides access to workflow execution.
@@ -139,3 +142,116 @@ func (w *WorkflowClient) Status(ctx context.Context, id string) (*WorkflowStatus
 
 	return &status, nil
 }
+
+// HistoryOptions filters the runs returned by [WorkflowClient.History].
+type HistoryOptions struct {
+	// Worktree limits the runs to those for a worktree.
+	Worktree string
+
+	// Limit caps the number of runs returned. Zero returns all of them.
+	Limit int
+}
+
+// History returns a workflow's completed runs, newest first, without their
+// output. Use [WorkflowClient.HistoryRun] to get a run with its output.
+//
+// Runs are kept on disk, so they survive restarts, until they age out of
+// the configured retention.
+func (w *WorkflowClient) History(ctx context.Context, id string, opts *HistoryOptions) ([]WorkflowStatus, error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.Worktree != "" {
+			query.Set("worktree", opts.Worktree)
+		}
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+	}
+	path := "/api/v1/workflows/" + url.PathEscape(id) + "/runs"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := w.c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []WorkflowStatus
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// HistoryRun returns a completed run of a workflow with its output.
+func (w *WorkflowClient) HistoryRun(ctx context.Context, id, runID string) (*WorkflowStatus, error) {
+	data, err := w.c.get(ctx, "/api/v1/workflows/"+url.PathEscape(id)+"/runs/"+url.PathEscape(runID))
+	if err != nil {
+		return nil, err
+	}
+
+	var status WorkflowStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow run: %w", err)
+	}
+
+	return &status, nil
+}
+
+// Artifact downloads a file kept from a workflow run, named by its
+// [Artifact.Path]. The caller must close the returned reader.
+func (w *WorkflowClient) Artifact(ctx context.Context, id, runID, path string) (io.ReadCloser, error) {
+	var escaped []string
+	for _, part := range strings.Split(path, "/") {
+		escaped = append(escaped, url.PathEscape(part))
+	}
+	resp, err := w.c.stream(ctx, "/api/v1/workflows/"+url.PathEscape(id)+"/runs/"+url.PathEscape(runID)+"/artifacts/"+strings.Join(escaped, "/"), "")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Tests returns the pass/fail timeline of the tests that failed in a
+// workflow's last limit runs reporting test results, or in all of its
+// recorded runs if limit is zero.
+//
+// Results come from [WorkflowSummary.FailedTests], so a test that didn't
+// fail in a run is taken to have passed.
+func (w *WorkflowClient) Tests(ctx context.Context, id string, limit int) (*TestTimeline, error) {
+	path := "/api/v1/workflows/" + url.PathEscape(id) + "/tests"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+
+	data, err := w.c.get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeline TestTimeline
+	if err := json.Unmarshal(data, &timeline); err != nil {
+		return nil, fmt.Errorf("failed to parse test timeline: %w", err)
+	}
+
+	return &timeline, nil
+}
+
+// Flaky returns the tests of a workflow that both passed and failed on the
+// same commit with the same inputs. Runs with uncommitted changes don't
+// count.
+func (w *WorkflowClient) Flaky(ctx context.Context, id string) ([]FlakyTest, error) {
+	data, err := w.c.get(ctx, "/api/v1/workflows/"+url.PathEscape(id)+"/flaky")
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []FlakyTest
+	if err := json.Unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("failed to parse flaky tests: %w", err)
+	}
+
+	return tests, nil
+}
@@ -83,6 +83,37 @@ func (w *WorktreeClient) Activate(ctx context.Context, name string) (*ActivateRe
 	return &result, nil
 }
 
+// StartServices starts the services of a worktree other than the active one,
+// alongside the active worktree's, with the worktree's own config and ports.
+// The server must have worktree.concurrent set.
+//
+// Address the running services with [ServiceClient.Worktree].
+func (w *WorktreeClient) StartServices(ctx context.Context, name string) (*Worktree, error) {
+	return w.services(ctx, name, "start")
+}
+
+// StopServices stops the services a worktree runs alongside the active
+// worktree's.
+func (w *WorktreeClient) StopServices(ctx context.Context, name string) (*Worktree, error) {
+	return w.services(ctx, name, "stop")
+}
+
+func (w *WorktreeClient) services(ctx context.Context, name, action string) (*Worktree, error) {
+	data, err := w.c.post(ctx, "/api/v1/worktrees/"+url.PathEscape(name)+"/services/"+action)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Worktree Worktree `json:"worktree"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse worktree: %w", err)
+	}
+
+	return &resp.Worktree, nil
+}
+
 // RemoveOptions configures worktree removal behavior.
 type RemoveOptions struct {
 	// DeleteBranch also deletes the git branch when removing the worktree.
@@ -1,7 +1,8 @@
 // Trellis Session Inbox — popup window client.
 //
 // Lists all active claude+codex sessions across worktrees with a real-time
-// state badge (running / needs-you). Clicking a row sends a "navigate"
+// state badge (running / needs-you), plus any firing log alerts. Clicking a
+// row sends a "navigate"
 // command over the WebSocket; the server forwards it to all main-window
 // trellis tabs, which then navigate themselves.
 
@@ -9,11 +10,14 @@
     "use strict";
 
     var INBOX_API = "/api/v1/inbox/sessions";
+    var ALERTS_API = "/api/v1/alerts";
     var WS_URL = (location.protocol === "https:" ? "wss:" : "ws:") +
         "//" + location.host + "/api/v1/inbox/ws?role=inbox";
 
     // sessionId -> row data
     var rows = new Map();
+    // rule name -> firing alert
+    var alerts = new Map();
     var ws = null;
     var reconnectTimer = null;
 
@@ -161,6 +165,60 @@
 
         renderSection("needs-you-list", "needs-you-empty", needsYou, "needs-you");
         renderSection("running-list", "running-empty", running, "running");
+        renderAlerts();
+    }
+
+    // renderAlerts lists firing log alerts, newest first. The section is
+    // hidden entirely when nothing is firing.
+    function renderAlerts() {
+        var section = document.getElementById("alerts-section");
+        var list = document.getElementById("alerts-list");
+        if (!section || !list) return;
+        list.textContent = "";
+        if (alerts.size === 0) {
+            section.style.display = "none";
+            return;
+        }
+        section.style.display = "";
+
+        var items = Array.from(alerts.values());
+        items.sort(function(a, b) {
+            return (Date.parse(b.fired_at) || 0) - (Date.parse(a.fired_at) || 0);
+        });
+        items.forEach(function(al) {
+            var a = document.createElement("a");
+            a.className = "inbox-row alert severity-" + (al.severity || "warning");
+            a.href = "/alerts";
+            a.title = al.rule + " — " + al.source;
+
+            var icon = document.createElement("i");
+            icon.className = "fa-solid fa-bell inbox-icon alert";
+            a.appendChild(icon);
+
+            var text = document.createElement("div");
+            text.className = "inbox-text";
+            var name = document.createElement("div");
+            name.className = "inbox-name";
+            name.textContent = al.rule;
+            var sub = document.createElement("div");
+            sub.className = "inbox-sub";
+            sub.textContent = al.message || al.source;
+            text.appendChild(name);
+            text.appendChild(sub);
+            a.appendChild(text);
+
+            var time = document.createElement("span");
+            time.className = "inbox-time";
+            time.textContent = formatAgo(al.fired_at);
+            a.appendChild(time);
+
+            a.addEventListener("click", function(e) {
+                e.preventDefault();
+                sendNavigate(a.href);
+            });
+
+            list.appendChild(a);
+        });
     }
 
     function renderSection(listID, emptyID, items, cls) {
@@ -273,6 +331,47 @@
         }).catch(function(err) {
             console.warn("inbox: initial load failed", err);
         });
+
+        fetch(ALERTS_API).then(function(r) {
+            return r.json();
+        }).then(function(data) {
+            alerts.clear();
+            var list = data.data || data;
+            if (Array.isArray(list)) {
+                list.forEach(function(rule) {
+                    if (rule.state !== "firing") return;
+                    alerts.set(rule.name, {
+                        rule: rule.name,
+                        severity: rule.severity,
+                        source: rule.source,
+                        message: rule.message,
+                        fired_at: rule.fired_at
+                    });
+                });
+            }
+            renderAlerts();
+        }).catch(function(err) {
+            console.warn("inbox: alert load failed", err);
+        });
+    }
+
+    // applyAlert adds a firing log alert or removes a resolved one.
+    function applyAlert(ev) {
+        if (!ev || !ev.payload) return;
+        var p = ev.payload;
+        if (!p.rule) return;
+        if (p.state === "firing") {
+            alerts.set(p.rule, {
+                rule: p.rule,
+                severity: p.severity,
+                source: p.source,
+                message: p.message,
+                fired_at: ev.timestamp || new Date().toISOString()
+            });
+        } else {
+            alerts.delete(p.rule);
+        }
+        renderAlerts();
     }
 
     function applyEvent(ev) {
@@ -353,6 +452,8 @@
                 applyEvent(msg.event);
             } else if (msg.type === "activity") {
                 applyActivity(msg.event);
+            } else if (msg.type === "alert") {
+                applyAlert(msg.event);
             } else if (msg.type === "navigate_failed") {
                 // No main window connected — open one directly.
                 window.open(msg.path, "trellis-main");
@@ -378,6 +479,7 @@
             var el = document.querySelector(rowSelector(r.id) + " .inbox-time");
             if (el) el.textContent = formatAgo(r.last_state_change_at);
         });
+        if (alerts.size > 0) renderAlerts();
     }, 30000);
 
     if (document.readyState === "loading") {
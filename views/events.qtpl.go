@@ -27,22 +27,36 @@ var (
 //line views/events.qtpl:7
 type EventsPage struct {
 	BasePage
-	Events []events.Event
+	Events            []events.Event
+	Search            string
+	OlderCursor       string
+	WebhooksEnabled   bool
+	WebhookDeliveries []events.WebhookDelivery
 }
 
-//line views/events.qtpl:13
+//line views/events.qtpl:17
 func (p *EventsPage) StreamRender(qw422016 *qt422016.Writer) {
-//line views/events.qtpl:13
+//line views/events.qtpl:17
 	qw422016.N().S(`
 `)
-//line views/events.qtpl:14
+//line views/events.qtpl:18
 	p.StreamHeader(qw422016)
-//line views/events.qtpl:14
+//line views/events.qtpl:18
 	qw422016.N().S(`
 
 <div class="d-flex justify-content-between align-items-center mb-4">
     <h2><i class="fa-solid fa-clock-rotate-left"></i> Events</h2>
-    <div>
+    <div class="d-flex gap-2">
+        <form class="d-flex" method="get" action="/events">
+            <input type="search" class="form-control me-2" name="q" value="`)
+//line views/events.qtpl:24
+	qw422016.E().S(p.Search)
+//line views/events.qtpl:24
+	qw422016.N().S(`" placeholder="Search events">
+            <button class="btn btn-outline-secondary" type="submit">
+                <i class="fa-solid fa-magnifying-glass"></i>
+            </button>
+        </form>
         <button class="btn btn-outline-secondary" onclick="location.reload()">
             <i class="fa-solid fa-rotate"></i> Refresh
         </button>
@@ -53,16 +67,37 @@ func (p *EventsPage) StreamRender(qw422016 *qt422016.Writer) {
     <div class="card-header">
         <i class="fa-solid fa-stream"></i> Recent Events
         <span class="badge bg-secondary ms-2">`)
-//line views/events.qtpl:28
+//line views/events.qtpl:38
 	qw422016.N().D(len(p.Events))
-//line views/events.qtpl:28
+//line views/events.qtpl:38
 	qw422016.N().S(`</span>
+        `)
+//line views/events.qtpl:39
+	if p.OlderCursor != "" {
+//line views/events.qtpl:39
+		qw422016.N().S(`
+        <a class="btn btn-sm btn-outline-secondary float-end" href="/events?q=`)
+//line views/events.qtpl:40
+		qw422016.N().U(p.Search)
+//line views/events.qtpl:40
+		qw422016.N().S(`&before=`)
+//line views/events.qtpl:40
+		qw422016.N().U(p.OlderCursor)
+//line views/events.qtpl:40
+		qw422016.N().S(`">
+            <i class="fa-solid fa-angles-up"></i> Older
+        </a>
+        `)
+//line views/events.qtpl:43
+	}
+//line views/events.qtpl:43
+	qw422016.N().S(`
     </div>
     <div class="card-body p-0">
         `)
-//line views/events.qtpl:31
+//line views/events.qtpl:46
 	if len(p.Events) > 0 {
-//line views/events.qtpl:31
+//line views/events.qtpl:46
 		qw422016.N().S(`
         <div class="table-responsive">
             <table class="table table-dark table-hover mb-0">
@@ -76,106 +111,272 @@ func (p *EventsPage) StreamRender(qw422016 *qt422016.Writer) {
                 </thead>
                 <tbody id="events-table">
                     `)
-//line views/events.qtpl:43
+//line views/events.qtpl:58
 		for _, evt := range p.Events {
-//line views/events.qtpl:43
+//line views/events.qtpl:58
 			qw422016.N().S(`
                     <tr>
                         <td>
                             <small class="text-muted">`)
-//line views/events.qtpl:46
+//line views/events.qtpl:61
 			qw422016.E().S(evt.Timestamp.Format("2006-01-02 15:04:05"))
-//line views/events.qtpl:46
+//line views/events.qtpl:61
 			qw422016.N().S(`</small>
                         </td>
                         <td>
                             `)
-//line views/events.qtpl:50
+//line views/events.qtpl:65
 			badgeClass := "bg-secondary"
-			if evt.Type == "service.crashed" {
+			if evt.Type == "service.crashed" || evt.Type == "service.unhealthy" || evt.Type == "config.reload_failed" {
 				badgeClass = "bg-danger"
-			} else if evt.Type == "service.started" || evt.Type == "service.restarted" {
+			} else if evt.Type == "service.started" || evt.Type == "service.restarted" || evt.Type == "service.ready" {
 				badgeClass = "bg-success"
 			} else if evt.Type == "workflow.finished" {
 				badgeClass = "bg-info"
-			} else if evt.Type == "worktree.activated" {
+			} else if evt.Type == "worktree.activated" || evt.Type == "config.reloaded" {
 				badgeClass = "bg-primary"
 			}
 
-//line views/events.qtpl:60
+//line views/events.qtpl:75
 			qw422016.N().S(`
                             <span class="badge `)
-//line views/events.qtpl:61
+//line views/events.qtpl:76
 			qw422016.E().S(badgeClass)
-//line views/events.qtpl:61
+//line views/events.qtpl:76
 			qw422016.N().S(`">`)
-//line views/events.qtpl:61
+//line views/events.qtpl:76
 			qw422016.E().S(evt.Type)
-//line views/events.qtpl:61
+//line views/events.qtpl:76
 			qw422016.N().S(`</span>
                         </td>
                         <td>`)
-//line views/events.qtpl:63
+//line views/events.qtpl:78
 			qw422016.E().S(evt.Worktree)
-//line views/events.qtpl:63
+//line views/events.qtpl:78
 			qw422016.N().S(`</td>
                         <td>
                             `)
-//line views/events.qtpl:65
+//line views/events.qtpl:80
 			if evt.Payload != nil {
-//line views/events.qtpl:65
+//line views/events.qtpl:80
 				qw422016.N().S(`
                                 `)
-//line views/events.qtpl:66
+//line views/events.qtpl:81
 				for key, val := range evt.Payload {
-//line views/events.qtpl:66
+//line views/events.qtpl:81
 					qw422016.N().S(`
                                     <small class="me-2"><strong>`)
-//line views/events.qtpl:67
+//line views/events.qtpl:82
 					qw422016.E().S(key)
-//line views/events.qtpl:67
+//line views/events.qtpl:82
 					qw422016.N().S(`:</strong> `)
-//line views/events.qtpl:67
+//line views/events.qtpl:82
 					qw422016.E().V(val)
-//line views/events.qtpl:67
+//line views/events.qtpl:82
 					qw422016.N().S(`</small>
                                 `)
-//line views/events.qtpl:68
+//line views/events.qtpl:83
 				}
-//line views/events.qtpl:68
+//line views/events.qtpl:83
 				qw422016.N().S(`
                             `)
-//line views/events.qtpl:69
+//line views/events.qtpl:84
 			}
-//line views/events.qtpl:69
+//line views/events.qtpl:84
 			qw422016.N().S(`
                         </td>
                     </tr>
                     `)
-//line views/events.qtpl:72
+//line views/events.qtpl:87
 		}
-//line views/events.qtpl:72
+//line views/events.qtpl:87
 		qw422016.N().S(`
                 </tbody>
             </table>
         </div>
         `)
-//line views/events.qtpl:76
+//line views/events.qtpl:91
 	} else {
-//line views/events.qtpl:76
+//line views/events.qtpl:91
 		qw422016.N().S(`
         <div class="p-4 text-center text-muted">
             <i class="fa-solid fa-inbox fa-3x mb-3"></i>
+            `)
+//line views/events.qtpl:94
+		if p.Search != "" {
+//line views/events.qtpl:94
+			qw422016.N().S(`
+            <p>No events match "`)
+//line views/events.qtpl:95
+			qw422016.E().S(p.Search)
+//line views/events.qtpl:95
+			qw422016.N().S(`".</p>
+            `)
+//line views/events.qtpl:96
+		} else {
+//line views/events.qtpl:96
+			qw422016.N().S(`
             <p>No events recorded yet.</p>
+            `)
+//line views/events.qtpl:98
+		}
+//line views/events.qtpl:98
+		qw422016.N().S(`
         </div>
         `)
-//line views/events.qtpl:81
+//line views/events.qtpl:100
 	}
-//line views/events.qtpl:81
+//line views/events.qtpl:100
 	qw422016.N().S(`
     </div>
 </div>
 
+`)
+//line views/events.qtpl:104
+	if p.WebhooksEnabled {
+//line views/events.qtpl:104
+		qw422016.N().S(`
+<div class="card mt-4">
+    <div class="card-header">
+        <i class="fa-solid fa-paper-plane"></i> Webhook Deliveries
+        <span class="badge bg-secondary ms-2">`)
+//line views/events.qtpl:108
+		qw422016.N().D(len(p.WebhookDeliveries))
+//line views/events.qtpl:108
+		qw422016.N().S(`</span>
+    </div>
+    <div class="card-body p-0">
+        `)
+//line views/events.qtpl:111
+		if len(p.WebhookDeliveries) > 0 {
+//line views/events.qtpl:111
+			qw422016.N().S(`
+        <div class="table-responsive">
+            <table class="table table-dark table-hover mb-0">
+                <thead>
+                    <tr>
+                        <th style="width: 180px;">Time</th>
+                        <th style="width: 150px;">Webhook</th>
+                        <th style="width: 200px;">Event</th>
+                        <th style="width: 100px;">Status</th>
+                        <th style="width: 90px;">Attempts</th>
+                        <th>Details</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    `)
+//line views/events.qtpl:125
+			for _, d := range p.WebhookDeliveries {
+//line views/events.qtpl:125
+				qw422016.N().S(`
+                    <tr>
+                        <td>
+                            <small class="text-muted">`)
+//line views/events.qtpl:128
+				qw422016.E().S(d.Timestamp.Format("2006-01-02 15:04:05"))
+//line views/events.qtpl:128
+				qw422016.N().S(`</small>
+                        </td>
+                        <td>`)
+//line views/events.qtpl:130
+				qw422016.E().S(d.WebhookID)
+//line views/events.qtpl:130
+				qw422016.N().S(`</td>
+                        <td>`)
+//line views/events.qtpl:131
+				qw422016.E().S(d.EventType)
+//line views/events.qtpl:131
+				qw422016.N().S(`</td>
+                        <td>
+                            `)
+//line views/events.qtpl:133
+				if d.Success {
+//line views/events.qtpl:133
+					qw422016.N().S(`
+                            <span class="badge bg-success">`)
+//line views/events.qtpl:134
+					qw422016.N().D(d.StatusCode)
+//line views/events.qtpl:134
+					qw422016.N().S(`</span>
+                            `)
+//line views/events.qtpl:135
+				} else if d.StatusCode > 0 {
+//line views/events.qtpl:135
+					qw422016.N().S(`
+                            <span class="badge bg-danger">`)
+//line views/events.qtpl:136
+					qw422016.N().D(d.StatusCode)
+//line views/events.qtpl:136
+					qw422016.N().S(`</span>
+                            `)
+//line views/events.qtpl:137
+				} else {
+//line views/events.qtpl:137
+					qw422016.N().S(`
+                            <span class="badge bg-danger">failed</span>
+                            `)
+//line views/events.qtpl:139
+				}
+//line views/events.qtpl:139
+				qw422016.N().S(`
+                        </td>
+                        <td>`)
+//line views/events.qtpl:141
+				qw422016.N().D(d.Attempts)
+//line views/events.qtpl:141
+				qw422016.N().S(`</td>
+                        <td>
+                            <small class="text-muted">`)
+//line views/events.qtpl:143
+				qw422016.N().DL(d.DurationMs)
+//line views/events.qtpl:143
+				qw422016.N().S(`ms</small>
+                            `)
+//line views/events.qtpl:144
+				if d.Error != "" {
+//line views/events.qtpl:144
+					qw422016.N().S(`
+                            <small class="text-danger ms-2">`)
+//line views/events.qtpl:145
+					qw422016.E().S(d.Error)
+//line views/events.qtpl:145
+					qw422016.N().S(`</small>
+                            `)
+//line views/events.qtpl:146
+				}
+//line views/events.qtpl:146
+				qw422016.N().S(`
+                        </td>
+                    </tr>
+                    `)
+//line views/events.qtpl:149
+			}
+//line views/events.qtpl:149
+			qw422016.N().S(`
+                </tbody>
+            </table>
+        </div>
+        `)
+//line views/events.qtpl:153
+		} else {
+//line views/events.qtpl:153
+			qw422016.N().S(`
+        <div class="p-4 text-center text-muted">
+            <p class="mb-0">No webhook deliveries yet.</p>
+        </div>
+        `)
+//line views/events.qtpl:157
+		}
+//line views/events.qtpl:157
+		qw422016.N().S(`
+    </div>
+</div>
+`)
+//line views/events.qtpl:160
+	}
+//line views/events.qtpl:160
+	qw422016.N().S(`
+
 <script>
 // Scroll to bottom to show most recent events
 (function() {
@@ -184,36 +385,36 @@ func (p *EventsPage) StreamRender(qw422016 *qt422016.Writer) {
 </script>
 
 `)
-//line views/events.qtpl:92
+//line views/events.qtpl:169
 	p.StreamFooter(qw422016)
-//line views/events.qtpl:92
+//line views/events.qtpl:169
 	qw422016.N().S(`
 `)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 }
 
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 func (p *EventsPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	p.StreamRender(qw422016)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	qt422016.ReleaseWriter(qw422016)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 }
 
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 func (p *EventsPage) Render() string {
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	p.WriteRender(qb422016)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	qs422016 := string(qb422016.B)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 	return qs422016
-//line views/events.qtpl:93
+//line views/events.qtpl:170
 }
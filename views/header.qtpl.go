@@ -185,6 +185,9 @@ window.TrellisNav = (function() {
         { value: '/trace', text: '/Trace', icon: 'magnifying-glass-location' },
         { value: '/crashes', text: '/Crashes', icon: 'skull-crossbones' },
         { value: '/events', text: '/Events', icon: 'clock-rotate-left' },
+        { value: '/workflows', text: '/Workflows', icon: 'bolt' },
+        { value: '/alerts', text: '/Alerts', icon: 'bell' },
+        { value: '/proxy', text: '/Proxy', icon: 'right-left' },
         { value: '/usage', text: '/Usage', icon: 'coins' }
     ];
 
@@ -683,11 +686,12 @@ window.TrellisNav = (function() {
                     if (data.data.services) {
                         for (const svc of data.data.services) {
                             const opt = document.createElement('option');
-                            opt.value = '/terminal/service/' + svc.name;
+                            opt.value = '/terminal/service/' + svc.name +
+                                (svc.worktree ? '?worktree=' + encodeURIComponent(svc.worktree) : '');
                             opt.dataset.isService = 'true';
                             opt.dataset.serviceName = svc.name;
                             opt.dataset.serviceStatus = svc.status;
-                            opt.textContent = '#' + svc.name + ' - service';
+                            opt.textContent = '#' + svc.name + (svc.worktree ? '@' + svc.worktree : '') + ' - service';
                             select.appendChild(opt);
                         }
                     }
@@ -876,6 +880,14 @@ window.TrellisNav = (function() {
             const payload = event.payload || {};
             title = 'Trellis: Trace Failed';
             body = 'Trace "' + (payload.name || 'unknown') + '" failed: ' + (payload.error || 'unknown error');
+        } else if (eventType === 'log.alert') {
+            const payload = event.payload || {};
+            if (notificationSettings.failures_only && payload.state !== 'firing') return;
+            title = payload.state === 'firing' ? 'Trellis: Alert ' + (payload.rule || 'unknown') : 'Trellis: Resolved ' + (payload.rule || 'unknown');
+            body = payload.message || '';
+            if (payload.state === 'firing' && payload.sample) {
+                body += '\n' + payload.sample;
+            }
         } else {
             title = 'Trellis: ' + eventType;
             body = JSON.stringify(event.payload || {});
@@ -947,33 +959,33 @@ window.TrellisNav = (function() {
 function toggleTheme() { TrellisNav.toggleTheme(); }
 </script>
 `)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 }
 
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 func WriteNavScript(qq422016 qtio422016.Writer, sessionID, shortcutsJSON, mode string) {
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	StreamNavScript(qw422016, sessionID, shortcutsJSON, mode)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	qt422016.ReleaseWriter(qw422016)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 }
 
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 func NavScript(sessionID, shortcutsJSON, mode string) string {
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	WriteNavScript(qb422016, sessionID, shortcutsJSON, mode)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	qs422016 := string(qb422016.B)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 	return qs422016
-//line views/header.qtpl:906
+//line views/header.qtpl:918
 }
 
 // NavbarRightControls renders the right-hand navbar control group shared by the
@@ -983,51 +995,51 @@ func NavScript(sessionID, shortcutsJSON, mode string) string {
 // usage badge appears (page header only). Keeping this in one place avoids the
 // drift that previously left the terminal navbar showing a stale worktree label.
 
-//line views/header.qtpl:914
+//line views/header.qtpl:926
 func StreamNavbarRightControls(qw422016 *qt422016.Writer, p *BasePage, btnClass, helpOnClick, helpTitle string) {
-//line views/header.qtpl:914
+//line views/header.qtpl:926
 	qw422016.N().S(`
 <div class="d-flex align-items-center gap-3 ms-auto">
     `)
-//line views/header.qtpl:916
+//line views/header.qtpl:928
 	if p.Worktree != nil {
-//line views/header.qtpl:916
+//line views/header.qtpl:928
 		qw422016.N().S(`
     <a class="navbar-text text-decoration-none" href="/worktree/`)
-//line views/header.qtpl:917
+//line views/header.qtpl:929
 		qw422016.E().S(p.WorktreeLabel())
-//line views/header.qtpl:917
+//line views/header.qtpl:929
 		qw422016.N().S(`" title="Go to worktree home">
         <i class="fa-solid fa-code-branch text-accent"></i> `)
-//line views/header.qtpl:918
+//line views/header.qtpl:930
 		qw422016.E().S(p.WorktreeLabel())
-//line views/header.qtpl:918
+//line views/header.qtpl:930
 		qw422016.N().S(`
     </a>
     `)
-//line views/header.qtpl:920
+//line views/header.qtpl:932
 	}
-//line views/header.qtpl:920
+//line views/header.qtpl:932
 	qw422016.N().S(`
     <button class="`)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.E().S(btnClass)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.N().S(`" onclick="`)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.E().S(helpOnClick)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.N().S(`" title="`)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.E().S(helpTitle)
-//line views/header.qtpl:921
+//line views/header.qtpl:933
 	qw422016.N().S(`">
         <i class="fa-solid fa-keyboard"></i>
     </button>
     <button class="`)
-//line views/header.qtpl:924
+//line views/header.qtpl:936
 	qw422016.E().S(btnClass)
-//line views/header.qtpl:924
+//line views/header.qtpl:936
 	qw422016.N().S(`" onclick="window.open('/inbox', 'trellis-inbox', 'popup=yes,width=420,height=720')" title="Open session inbox (Cmd/Ctrl + I)">
         <i class="fa-solid fa-inbox"></i>
     </button>
@@ -1037,38 +1049,38 @@ func StreamNavbarRightControls(qw422016 *qt422016.Writer, p *BasePage, btnClass,
     </button>
 </div>
 `)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 }
 
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 func WriteNavbarRightControls(qq422016 qtio422016.Writer, p *BasePage, btnClass, helpOnClick, helpTitle string) {
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	StreamNavbarRightControls(qw422016, p, btnClass, helpOnClick, helpTitle)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	qt422016.ReleaseWriter(qw422016)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 }
 
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 func NavbarRightControls(p *BasePage, btnClass, helpOnClick, helpTitle string) string {
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	WriteNavbarRightControls(qb422016, p, btnClass, helpOnClick, helpTitle)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	qs422016 := string(qb422016.B)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 	return qs422016
-//line views/header.qtpl:932
+//line views/header.qtpl:944
 }
 
-//line views/header.qtpl:934
+//line views/header.qtpl:946
 func (p *BasePage) StreamHeader(qw422016 *qt422016.Writer) {
-//line views/header.qtpl:934
+//line views/header.qtpl:946
 	qw422016.N().S(`
 <!DOCTYPE html>
 <html lang="en">
@@ -1076,9 +1088,9 @@ func (p *BasePage) StreamHeader(qw422016 *qt422016.Writer) {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>`)
-//line views/header.qtpl:940
+//line views/header.qtpl:952
 	qw422016.E().S(p.Title)
-//line views/header.qtpl:940
+//line views/header.qtpl:952
 	qw422016.N().S(` - Trellis</title>
     <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
     <link href="https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.5.1/css/all.min.css" rel="stylesheet">
@@ -1150,9 +1162,9 @@ func (p *BasePage) StreamHeader(qw422016 *qt422016.Writer) {
             </div>
 
             `)
-//line views/header.qtpl:1010
+//line views/header.qtpl:1022
 	StreamNavbarRightControls(qw422016, p, "btn btn-sm btn-link text-muted", "showShortcutHelp()", "Keyboard Shortcuts (Cmd/Ctrl+H)")
-//line views/header.qtpl:1010
+//line views/header.qtpl:1022
 	qw422016.N().S(`
         </div>
     </div>
@@ -1170,46 +1182,46 @@ func (p *BasePage) StreamHeader(qw422016 *qt422016.Writer) {
 <script src="/static/js/command_palette.js"></script>
 <script src="/static/js/shortcut_help.js"></script>
 `)
-//line views/header.qtpl:1026
+//line views/header.qtpl:1038
 	StreamNavScript(qw422016, p.SessionID(), p.ShortcutsJSON(), "page")
-//line views/header.qtpl:1026
+//line views/header.qtpl:1038
 	qw422016.N().S(`
 <script src="/static/js/inbox_main_ws.js"></script>
 <main>
 <div class="page-container container-fluid mt-4">
 `)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 }
 
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 func (p *BasePage) WriteHeader(qq422016 qtio422016.Writer) {
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	p.StreamHeader(qw422016)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	qt422016.ReleaseWriter(qw422016)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 }
 
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 func (p *BasePage) Header() string {
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	p.WriteHeader(qb422016)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	qs422016 := string(qb422016.B)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 	return qs422016
-//line views/header.qtpl:1030
+//line views/header.qtpl:1042
 }
 
-//line views/header.qtpl:1032
+//line views/header.qtpl:1044
 func (p *BasePage) StreamFooter(qw422016 *qt422016.Writer) {
-//line views/header.qtpl:1032
+//line views/header.qtpl:1044
 	qw422016.N().S(`
 </div>
 </main>
@@ -1217,31 +1229,31 @@ func (p *BasePage) StreamFooter(qw422016 *qt422016.Writer) {
 </body>
 </html>
 `)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 }
 
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 func (p *BasePage) WriteFooter(qq422016 qtio422016.Writer) {
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	p.StreamFooter(qw422016)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	qt422016.ReleaseWriter(qw422016)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 }
 
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 func (p *BasePage) Footer() string {
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	p.WriteFooter(qb422016)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	qs422016 := string(qb422016.B)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 	return qs422016
-//line views/header.qtpl:1038
+//line views/header.qtpl:1050
 }
@@ -196,6 +196,12 @@ func (p *InboxPage) StreamRender(qw422016 *qt422016.Writer) {
         }
         .inbox-row .inbox-name { font-weight: 400; }
         .inbox-row.unread .inbox-name { font-weight: 600; }
+        .inbox-row.alert { border-left-color: var(--bs-danger, #dc3545); }
+        .inbox-row.alert.severity-info { border-left-color: var(--bs-info, #0dcaf0); }
+        .inbox-row.alert.severity-warning { border-left-color: var(--bs-warning, #ffc107); }
+        .inbox-icon.alert { color: var(--bs-danger, #dc3545); }
+        .inbox-row.severity-info .inbox-icon.alert { color: var(--bs-info, #0dcaf0); }
+        .inbox-row.severity-warning .inbox-icon.alert { color: var(--bs-warning, #ffc107); }
         .inbox-empty {
             padding: 1rem 0.75rem;
             color: var(--trellis-text-muted, #6c757d);
@@ -211,6 +217,10 @@ func (p *InboxPage) StreamRender(qw422016 *qt422016.Writer) {
         <span id="inbox-status" class="inbox-status">connecting…</span>
     </div>
     <div class="inbox-list">
+        <div id="alerts-section" style="display: none;">
+            <div class="inbox-section-label">Log alerts</div>
+            <div id="alerts-list"></div>
+        </div>
         <div class="inbox-section-label">Needs you</div>
         <div id="needs-you-list"></div>
         <div id="needs-you-empty" class="inbox-empty">No sessions waiting.</div>
@@ -223,31 +233,31 @@ func (p *InboxPage) StreamRender(qw422016 *qt422016.Writer) {
 </body>
 </html>
 `)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 }
 
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 func (p *InboxPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	p.StreamRender(qw422016)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	qt422016.ReleaseWriter(qw422016)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 }
 
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 func (p *InboxPage) Render() string {
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	p.WriteRender(qb422016)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	qs422016 := string(qb422016.B)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 	return qs422016
-//line views/inbox.qtpl:203
+//line views/inbox.qtpl:213
 }
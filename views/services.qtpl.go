@@ -57,7 +57,7 @@ func (p *ServiceDetailPage) StreamRender(qw422016 *qt422016.Writer) {
 	qw422016.N().S(`
         `)
 //line views/services.qtpl:22
-	if state == "running" {
+	if state == "running" || state == "starting" {
 //line views/services.qtpl:22
 		qw422016.N().S(`
         <button class="btn btn-outline-secondary" onclick="serviceAction('stop')">
@@ -27,17 +27,19 @@ var (
 //line views/status.qtpl:7
 type StatusPage struct {
 	BasePage
-	Services []service.ServiceInfo
+	Services         []service.ServiceInfo
+	ServiceWorktree  string   // Worktree whose services are shown ("" = active)
+	ServiceWorktrees []string // Worktrees running services alongside the active one
 }
 
-//line views/status.qtpl:13
+//line views/status.qtpl:15
 func (p *StatusPage) StreamRender(qw422016 *qt422016.Writer) {
-//line views/status.qtpl:13
+//line views/status.qtpl:15
 	qw422016.N().S(`
 `)
-//line views/status.qtpl:14
+//line views/status.qtpl:16
 	p.StreamHeader(qw422016)
-//line views/status.qtpl:14
+//line views/status.qtpl:16
 	qw422016.N().S(`
 
 <h2 class="mb-4"><i class="fa-solid fa-server"></i> Service Status</h2>
@@ -46,6 +48,66 @@ func (p *StatusPage) StreamRender(qw422016 *qt422016.Writer) {
     <div class="card-header d-flex justify-content-between align-items-center">
         <span>Services</span>
         <div class="d-flex gap-2">
+            `)
+//line views/status.qtpl:24
+	if len(p.ServiceWorktrees) > 0 || p.ServiceWorktree != "" {
+//line views/status.qtpl:24
+		qw422016.N().S(`
+            <select class="form-select form-select-sm" style="width: auto;" onchange="selectWorktree(this.value)" title="Worktree">
+                <option value=""`)
+//line views/status.qtpl:26
+		if p.ServiceWorktree == "" {
+//line views/status.qtpl:26
+			qw422016.N().S(` selected`)
+//line views/status.qtpl:26
+		}
+//line views/status.qtpl:26
+		qw422016.N().S(`>`)
+//line views/status.qtpl:26
+		if p.Worktree != nil {
+//line views/status.qtpl:26
+			qw422016.E().S(p.Worktree.Name())
+//line views/status.qtpl:26
+		} else {
+//line views/status.qtpl:26
+			qw422016.N().S(`active`)
+//line views/status.qtpl:26
+		}
+//line views/status.qtpl:26
+		qw422016.N().S(` (active)</option>
+                `)
+//line views/status.qtpl:27
+		for _, name := range p.ServiceWorktrees {
+//line views/status.qtpl:27
+			qw422016.N().S(`
+                <option value="`)
+//line views/status.qtpl:28
+			qw422016.E().S(name)
+//line views/status.qtpl:28
+			qw422016.N().S(`"`)
+//line views/status.qtpl:28
+			if p.ServiceWorktree == name {
+//line views/status.qtpl:28
+				qw422016.N().S(` selected`)
+//line views/status.qtpl:28
+			}
+//line views/status.qtpl:28
+			qw422016.N().S(`>`)
+//line views/status.qtpl:28
+			qw422016.E().S(name)
+//line views/status.qtpl:28
+			qw422016.N().S(`</option>
+                `)
+//line views/status.qtpl:29
+		}
+//line views/status.qtpl:29
+		qw422016.N().S(`
+            </select>
+            `)
+//line views/status.qtpl:31
+	}
+//line views/status.qtpl:31
+	qw422016.N().S(`
             <button class="btn btn-sm btn-primary" onclick="startAll()" title="Start all services">
                 <i class="fa-solid fa-play"></i> Start All
             </button>
@@ -98,6 +160,24 @@ func (p *StatusPage) StreamRender(qw422016 *qt422016.Writer) {
 </div>
 
 <script>
+const serviceWorktree = '`)
+//line views/status.qtpl:84
+	qw422016.E().S(JSAttr(p.ServiceWorktree))
+//line views/status.qtpl:84
+	qw422016.N().S(`';
+
+// withWorktree addresses a URL to the worktree whose services are shown.
+function withWorktree(url) {
+    if (!serviceWorktree) {
+        return url;
+    }
+    return url + (url.includes('?') ? '&' : '?') + 'worktree=' + encodeURIComponent(serviceWorktree);
+}
+
+function selectWorktree(name) {
+    window.location.href = '/status' + (name ? '?worktree=' + encodeURIComponent(name) : '');
+}
+
 function escapeHtml(text) {
     const div = document.createElement('div');
     div.textContent = text;
@@ -109,7 +189,7 @@ function escapeJsAttr(s) {
 }
 
 function loadStatusData() {
-    fetch('/api/v1/services')
+    fetch(withWorktree('/api/v1/services'))
         .then(r => r.json())
         .then(data => {
             const services = data.data || [];
@@ -125,25 +205,34 @@ function loadStatusData() {
 
             services.forEach(svc => {
                 const state = svc.Status?.State || 'unknown';
-                const isRunning = state === 'running';
+                const isRunning = state === 'running' || state === 'starting';
                 const name = svc.Name || '';
                 const tr = document.createElement('tr');
                 tr.innerHTML = `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(`
                     <td>
-                        <a href="/terminal/service/${encodeURIComponent(name)}" class="text-accent text-decoration-none">
+                        <a href="${withWorktree('/terminal/service/' + encodeURIComponent(name))}" class="text-accent text-decoration-none">
                             ${escapeHtml(name)}
                         </a>
+                        ${svc.Status?.Error ? `)
+//line views/status.qtpl:84
+	qw422016.N().S("`")
+//line views/status.qtpl:84
+	qw422016.N().S(`<div class="small text-danger">${escapeHtml(svc.Status.Error)}</div>`)
+//line views/status.qtpl:84
+	qw422016.N().S("`")
+//line views/status.qtpl:84
+	qw422016.N().S(` : ''}
                     </td>
                     <td><span class="badge badge-${state}">${state}</span></td>
                     <td class="text-end">
                         ${isRunning ? `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(`
                             <button class="btn btn-sm btn-outline-secondary" onclick="serviceAction('${escapeJsAttr(name)}', 'stop')" title="Stop">
                                 <i class="fa-solid fa-stop"></i>
@@ -152,27 +241,27 @@ function loadStatusData() {
                                 <i class="fa-solid fa-rotate"></i>
                             </button>
                         `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(` : `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(`
                             <button class="btn btn-sm btn-primary" onclick="serviceAction('${escapeJsAttr(name)}', 'start')" title="Start">
                                 <i class="fa-solid fa-play"></i>
                             </button>
                         `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(`}
                     </td>
                 `)
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S("`")
-//line views/status.qtpl:14
+//line views/status.qtpl:84
 	qw422016.N().S(`;
 
                 if (isRunning) {
@@ -211,19 +300,22 @@ function loadStatusData() {
 }
 
 function serviceAction(name, action) {
-    fetch('/api/v1/services/' + encodeURIComponent(name) + '/' + action, { method: 'POST' })
+    fetch(withWorktree('/api/v1/services/' + encodeURIComponent(name) + '/' + action), { method: 'POST' })
         .then(() => loadStatusData())
         .catch(err => console.error('Service action failed:', err));
 }
 
 function startAll() {
-    fetch('/api/v1/workflows/_start_all/run', { method: 'POST' })
+    // Another worktree's services have no workflows; start them directly
+    const url = serviceWorktree ? withWorktree('/api/v1/services/start-all') : '/api/v1/workflows/_start_all/run';
+    fetch(url, { method: 'POST' })
         .then(() => setTimeout(loadStatusData, 500))
         .catch(err => console.error('Start all failed:', err));
 }
 
 function stopAll() {
-    fetch('/api/v1/workflows/_stop_all/run', { method: 'POST' })
+    const url = serviceWorktree ? withWorktree('/api/v1/services/stop-all') : '/api/v1/workflows/_stop_all/run';
+    fetch(url, { method: 'POST' })
         .then(() => setTimeout(loadStatusData, 500))
         .catch(err => console.error('Stop all failed:', err));
 }
@@ -240,36 +332,36 @@ setInterval(loadStatusData, 5000);
 </script>
 
 `)
-//line views/status.qtpl:191
+//line views/status.qtpl:219
 	p.StreamFooter(qw422016)
-//line views/status.qtpl:191
+//line views/status.qtpl:219
 	qw422016.N().S(`
 `)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 }
 
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 func (p *StatusPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	p.StreamRender(qw422016)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	qt422016.ReleaseWriter(qw422016)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 }
 
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 func (p *StatusPage) Render() string {
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	p.WriteRender(qb422016)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	qs422016 := string(qb422016.B)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 	return qs422016
-//line views/status.qtpl:192
+//line views/status.qtpl:220
 }
@@ -66,6 +66,7 @@ type TerminalPage struct {
 type ServiceInfo struct {
 	Name           string         `json:"name"`
 	Status         string         `json:"status"`
+	Worktree       string         `json:"worktree,omitempty"`        // Worktree running the service alongside the active one
 	ParserType     string         `json:"parser_type,omitempty"`     // "json", "logfmt", "regex", "none"
 	Columns        []string       `json:"columns,omitempty"`         // Deprecated: use Layout
 	ColumnWidths   map[string]int `json:"column_widths,omitempty"`   // Deprecated: use Layout
@@ -114,75 +115,76 @@ type LogViewerInfo struct {
 
 type TerminalWindowPage struct {
 	BasePage
-	Session       string
-	Window        string
-	IsRemote      bool
-	ViewType      string // "local", "remote", "service", "editor", "logviewer"
-	ServiceName   string // Only for ViewType="service"
-	LogViewerName string // Only for ViewType="logviewer"
-	WorktreeName  string // Current worktree name (e.g., "main", "feature-branch")
-	ProjectName   string
-	Shortcuts     []ShortcutInfo
-	Notifications NotificationSettings
-	Services      []ServiceInfo
-	Links         []LinkInfo
-	LogViewers    []LogViewerInfo
+	Session         string
+	Window          string
+	IsRemote        bool
+	ViewType        string // "local", "remote", "service", "editor", "logviewer"
+	ServiceName     string // Only for ViewType="service"
+	ServiceWorktree string // Only for ViewType="service": worktree running the service alongside the active one
+	LogViewerName   string // Only for ViewType="logviewer"
+	WorktreeName    string // Current worktree name (e.g., "main", "feature-branch")
+	ProjectName     string
+	Shortcuts       []ShortcutInfo
+	Notifications   NotificationSettings
+	Services        []ServiceInfo
+	Links           []LinkInfo
+	LogViewers      []LogViewerInfo
 }
 
 // ShortcutsJSON streams the shortcuts as JSON for JavaScript (raw, unescaped).
 
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 func (p *TerminalWindowPage) StreamShortcutsJSON(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	if len(p.Shortcuts) == 0 {
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 		qw422016.N().S(`[]`)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	} else {
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 		b, _ := json.Marshal(p.Shortcuts)
 
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 		qw422016.N().Z(b)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	}
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 }
 
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 func (p *TerminalWindowPage) WriteShortcutsJSON(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	p.StreamShortcutsJSON(qw422016)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 }
 
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 func (p *TerminalWindowPage) ShortcutsJSON() string {
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	p.WriteShortcutsJSON(qb422016)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 	return qs422016
-//line views/terminal.qtpl:110
+//line views/terminal.qtpl:112
 }
 
 // shortcutKeyDisplay formats a shortcut key like "cmd+l" as "<kbd>Cmd</kbd> + <kbd>L</kbd>".
 
-//line views/terminal.qtpl:113
+//line views/terminal.qtpl:115
 func streamshortcutKeyDisplay(qw422016 *qt422016.Writer, key string) {
-//line views/terminal.qtpl:113
+//line views/terminal.qtpl:115
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:115
+//line views/terminal.qtpl:117
 	parts := strings.Split(key, "+")
 	for i, part := range parts {
 		part = strings.TrimSpace(part)
@@ -205,212 +207,212 @@ func streamshortcutKeyDisplay(qw422016 *qt422016.Writer, key string) {
 		}
 	}
 
-//line views/terminal.qtpl:136
+//line views/terminal.qtpl:138
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 	for i, part := range parts {
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 		if i > 0 {
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 			qw422016.N().S(` + `)
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 		}
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 		qw422016.N().S(`<kbd>`)
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 		qw422016.E().S(part)
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 		qw422016.N().S(`</kbd>`)
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 	}
-//line views/terminal.qtpl:137
+//line views/terminal.qtpl:139
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 }
 
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 func writeshortcutKeyDisplay(qq422016 qtio422016.Writer, key string) {
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	streamshortcutKeyDisplay(qw422016, key)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 }
 
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 func shortcutKeyDisplay(key string) string {
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	writeshortcutKeyDisplay(qb422016, key)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 	return qs422016
-//line views/terminal.qtpl:138
+//line views/terminal.qtpl:140
 }
 
 // NotificationsJSON streams the notification settings as JSON for JavaScript.
 
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 func (p *TerminalWindowPage) StreamNotificationsJSON(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	b, _ := json.Marshal(p.Notifications)
 
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qw422016.N().Z(b)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 }
 
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 func (p *TerminalWindowPage) WriteNotificationsJSON(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	p.StreamNotificationsJSON(qw422016)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 }
 
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 func (p *TerminalWindowPage) NotificationsJSON() string {
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	p.WriteNotificationsJSON(qb422016)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 	return qs422016
-//line views/terminal.qtpl:141
+//line views/terminal.qtpl:143
 }
 
 // ServicesJSON streams the services list as JSON for JavaScript.
 
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 func (p *TerminalWindowPage) StreamServicesJSON(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	b, _ := json.Marshal(p.Services)
 
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qw422016.N().Z(b)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 }
 
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 func (p *TerminalWindowPage) WriteServicesJSON(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	p.StreamServicesJSON(qw422016)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 }
 
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 func (p *TerminalWindowPage) ServicesJSON() string {
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	p.WriteServicesJSON(qb422016)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 	return qs422016
-//line views/terminal.qtpl:144
+//line views/terminal.qtpl:146
 }
 
 // LinksJSON streams the links list as JSON for JavaScript.
 
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 func (p *TerminalWindowPage) StreamLinksJSON(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	b, _ := json.Marshal(p.Links)
 
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qw422016.N().Z(b)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 }
 
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 func (p *TerminalWindowPage) WriteLinksJSON(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	p.StreamLinksJSON(qw422016)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 }
 
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 func (p *TerminalWindowPage) LinksJSON() string {
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	p.WriteLinksJSON(qb422016)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 	return qs422016
-//line views/terminal.qtpl:147
+//line views/terminal.qtpl:149
 }
 
 // LogViewersJSON streams the log viewers list as JSON for JavaScript.
 
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 func (p *TerminalWindowPage) StreamLogViewersJSON(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	b, _ := json.Marshal(p.LogViewers)
 
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qw422016.N().Z(b)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 }
 
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 func (p *TerminalWindowPage) WriteLogViewersJSON(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	p.StreamLogViewersJSON(qw422016)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 }
 
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 func (p *TerminalWindowPage) LogViewersJSON() string {
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	p.WriteLogViewersJSON(qb422016)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 	return qs422016
-//line views/terminal.qtpl:150
+//line views/terminal.qtpl:152
 }
 
 // SessionDisplayName returns the branch name for display (e.g., "main", "demovideos")
 //
-//line views/terminal.qtpl:154
+//line views/terminal.qtpl:156
 func (p *TerminalWindowPage) SessionDisplayName() string {
 	if p.ProjectName == "" {
 		return p.Session
@@ -427,14 +429,14 @@ func (p *TerminalWindowPage) SessionDisplayName() string {
 	return p.Session
 }
 
-//line views/terminal.qtpl:172
+//line views/terminal.qtpl:174
 func (p *TerminalPage) StreamRender(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:172
+//line views/terminal.qtpl:174
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:173
+//line views/terminal.qtpl:175
 	p.StreamHeader(qw422016)
-//line views/terminal.qtpl:173
+//line views/terminal.qtpl:175
 	qw422016.N().S(`
 <script>
 // Terminal pages aren't SPA-migrated yet: opt out so links cause full reload
@@ -455,9 +457,9 @@ func (p *TerminalPage) StreamRender(qw422016 *qt422016.Writer) {
             </div>
             <div class="card-body p-0">
                 `)
-//line views/terminal.qtpl:192
+//line views/terminal.qtpl:194
 	if len(p.Terminals) > 0 {
-//line views/terminal.qtpl:192
+//line views/terminal.qtpl:194
 		qw422016.N().S(`
                 <table class="table table-dark table-hover mb-0">
                     <thead>
@@ -469,12 +471,12 @@ func (p *TerminalPage) StreamRender(qw422016 *qt422016.Writer) {
                     </thead>
                     <tbody>
                         `)
-//line views/terminal.qtpl:202
+//line views/terminal.qtpl:204
 		for _, term := range p.Terminals {
-//line views/terminal.qtpl:202
+//line views/terminal.qtpl:204
 			qw422016.N().S(`
                         `)
-//line views/terminal.qtpl:204
+//line views/terminal.qtpl:206
 			var termURL string
 			if term.IsRemote {
 				termURL = "/terminal/remote/" + term.Window
@@ -482,78 +484,78 @@ func (p *TerminalPage) StreamRender(qw422016 *qt422016.Writer) {
 				termURL = "/terminal/local/" + term.Worktree + "/" + term.Window
 			}
 
-//line views/terminal.qtpl:210
+//line views/terminal.qtpl:212
 			qw422016.N().S(`
                         <tr>
                             <td>
                                 <a href="`)
-//line views/terminal.qtpl:213
+//line views/terminal.qtpl:215
 			qw422016.E().S(termURL)
-//line views/terminal.qtpl:213
+//line views/terminal.qtpl:215
 			qw422016.N().S(`" class="text-decoration-none text-accent">
                                     `)
-//line views/terminal.qtpl:214
+//line views/terminal.qtpl:216
 			qw422016.E().S(term.Window)
-//line views/terminal.qtpl:214
+//line views/terminal.qtpl:216
 			qw422016.N().S(`
                                 </a>
                             </td>
                             <td>
                                 <small class="text-muted">
                                     `)
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 			if term.IsRemote {
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 				qw422016.N().S(`!`)
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 			} else {
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 				qw422016.N().S(`@`)
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 			}
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 			qw422016.E().S(term.Worktree)
-//line views/terminal.qtpl:219
+//line views/terminal.qtpl:221
 			qw422016.N().S(`
                                 </small>
                             </td>
                             <td>
                                 `)
-//line views/terminal.qtpl:223
+//line views/terminal.qtpl:225
 			if term.IsRemote {
-//line views/terminal.qtpl:223
+//line views/terminal.qtpl:225
 				qw422016.N().S(`
                                 <span class="badge bg-warning text-dark"><i class="fa-solid fa-globe"></i> Remote</span>
                                 `)
-//line views/terminal.qtpl:225
+//line views/terminal.qtpl:227
 			} else {
-//line views/terminal.qtpl:225
+//line views/terminal.qtpl:227
 				qw422016.N().S(`
                                 <span class="badge bg-secondary"><i class="fa-solid fa-terminal"></i> Local</span>
                                 `)
-//line views/terminal.qtpl:227
+//line views/terminal.qtpl:229
 			}
-//line views/terminal.qtpl:227
+//line views/terminal.qtpl:229
 			qw422016.N().S(`
                             </td>
                         </tr>
                         `)
-//line views/terminal.qtpl:230
+//line views/terminal.qtpl:232
 		}
-//line views/terminal.qtpl:230
+//line views/terminal.qtpl:232
 		qw422016.N().S(`
                     </tbody>
                 </table>
                 `)
-//line views/terminal.qtpl:233
+//line views/terminal.qtpl:235
 	} else {
-//line views/terminal.qtpl:233
+//line views/terminal.qtpl:235
 		qw422016.N().S(`
                 <div class="p-3 text-muted">No terminal sessions available</div>
                 `)
-//line views/terminal.qtpl:235
+//line views/terminal.qtpl:237
 	}
-//line views/terminal.qtpl:235
+//line views/terminal.qtpl:237
 	qw422016.N().S(`
             </div>
         </div>
@@ -597,43 +599,43 @@ func (p *TerminalPage) StreamRender(qw422016 *qt422016.Writer) {
 </div>
 
 `)
-//line views/terminal.qtpl:277
+//line views/terminal.qtpl:279
 	p.StreamFooter(qw422016)
-//line views/terminal.qtpl:277
+//line views/terminal.qtpl:279
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 }
 
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 func (p *TerminalPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	p.StreamRender(qw422016)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 }
 
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 func (p *TerminalPage) Render() string {
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	p.WriteRender(qb422016)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 	return qs422016
-//line views/terminal.qtpl:278
+//line views/terminal.qtpl:280
 }
 
-//line views/terminal.qtpl:280
+//line views/terminal.qtpl:282
 func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
-//line views/terminal.qtpl:280
+//line views/terminal.qtpl:282
 	qw422016.N().S(`
 <!DOCTYPE html>
 <html lang="en">
@@ -641,9 +643,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>`)
-//line views/terminal.qtpl:286
+//line views/terminal.qtpl:288
 	qw422016.E().S(p.Title)
-//line views/terminal.qtpl:286
+//line views/terminal.qtpl:288
 	qw422016.N().S(` - Trellis</title>
     <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
     <link href="https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.5.1/css/all.min.css" rel="stylesheet">
@@ -675,32 +677,32 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             <div class="d-flex align-items-center gap-2 ms-3">
                 <select id="navSelect" class="form-select form-select-sm">
                     <option value="`)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.E().S(p.Session)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.N().S(`/`)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.E().S(p.Window)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.N().S(`" selected>@`)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.E().S(p.SessionDisplayName())
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.N().S(` - `)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.E().S(p.Window)
-//line views/terminal.qtpl:316
+//line views/terminal.qtpl:318
 	qw422016.N().S(`</option>
                 </select>
 
                 <div class="btn-group" role="group"`)
-//line views/terminal.qtpl:319
+//line views/terminal.qtpl:321
 	if p.ViewType != "local" {
-//line views/terminal.qtpl:319
+//line views/terminal.qtpl:321
 		qw422016.N().S(` style="display:none"`)
-//line views/terminal.qtpl:319
+//line views/terminal.qtpl:321
 	}
-//line views/terminal.qtpl:319
+//line views/terminal.qtpl:321
 	qw422016.N().S(`>
                     <button id="showTerminalBtn" class="btn btn-sm btn-terminal active" onclick="showTerminal()">
                         <i class="fa-solid fa-terminal"></i>
@@ -711,22 +713,22 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                 </div>
 
                 <select id="workflowSelect" class="form-select form-select-sm" style="width: 160px;`)
-//line views/terminal.qtpl:328
+//line views/terminal.qtpl:330
 	if p.ViewType != "local" && p.ViewType != "output" {
-//line views/terminal.qtpl:328
+//line views/terminal.qtpl:330
 		qw422016.N().S(` display:none;`)
-//line views/terminal.qtpl:328
+//line views/terminal.qtpl:330
 	}
-//line views/terminal.qtpl:328
+//line views/terminal.qtpl:330
 	qw422016.N().S(`">
                     <option value="">Workflow...</option>
                 </select>
             </div>
 
             `)
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	StreamNavbarRightControls(qw422016, &p.BasePage, "btn btn-sm btn-terminal", "showHelp()", "Keyboard Shortcuts (Cmd/Ctrl+?)")
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S(`
         </div>
     </div>
@@ -917,21 +919,21 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         overflow: auto;
         /* Prevent iOS rubber-band bounce when scrolling past edges.
            `)
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S("`")
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S(`none`)
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S("`")
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S(` disables the element's own bounce; `)
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S("`")
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S(`contain`)
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S("`")
-//line views/terminal.qtpl:333
+//line views/terminal.qtpl:335
 	qw422016.N().S(` only blocks
            the chain to the parent. */
         overscroll-behavior: none;
@@ -1043,6 +1045,33 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         font-size: 12px;
         flex-shrink: 0;
     }
+    .logviewer-histogram {
+        display: flex;
+        align-items: stretch;
+        gap: 1px;
+        height: 56px;
+        padding: 4px 12px;
+        border-bottom: 1px solid var(--trellis-input-border);
+        background: var(--trellis-pre-bg);
+        flex-shrink: 0;
+    }
+    .logviewer-histogram-bar {
+        flex: 1;
+        min-width: 1px;
+        display: flex;
+        flex-direction: column;
+        justify-content: flex-end;
+    }
+    .logviewer-histogram-bar .seg-error { background: #ef4444; }
+    .logviewer-histogram-bar .seg-warn { background: #f59e0b; }
+    .logviewer-histogram-bar .seg-other { background: #3b82f6; opacity: 0.6; }
+    .logviewer-histogram-bar:hover .seg-other { opacity: 1; }
+    .logviewer-histogram-message {
+        margin: auto;
+        color: var(--bs-secondary-color);
+        font-size: 12px;
+        font-style: italic;
+    }
     .logviewer-gap-notice {
         padding: 4px 12px;
         color: var(--bs-secondary-color);
@@ -1326,6 +1355,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                 <span id="logviewer-newlines-count">+0 new lines</span>
                 <i class="fa-solid fa-arrow-down"></i>
             </button>
+            <button class="btn btn-sm btn-outline-secondary" id="logviewer-histogram-btn" onclick="toggleLogViewerHistogram()" title="Toggle histogram">
+                <i class="fa-solid fa-chart-column"></i>
+            </button>
             <button class="btn btn-sm btn-outline-secondary" id="logviewer-history-btn" onclick="openHistorySearchModal()" title="Search history">
                 <i class="fa-solid fa-clock-rotate-left"></i>
             </button>
@@ -1341,6 +1373,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         </div>
     </div>
     <div id="logviewer-banner" class="logviewer-banner" style="display: none;"></div>
+    <div id="logviewer-histogram" class="logviewer-histogram" style="display: none;"></div>
     <div id="logviewer-log" class="logviewer-log"></div>
     <div id="logviewer-expanded" class="logviewer-expanded" style="display: none;">
         <div class="logviewer-expanded-header">
@@ -1489,9 +1522,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 <script src="https://cdn.jsdelivr.net/npm/jquery@3.7.1/dist/jquery.min.js"></script>
 <script src="https://cdn.jsdelivr.net/npm/select2@4.1.0-rc.0/dist/js/select2.min.js"></script>
 `)
-//line views/terminal.qtpl:1078
+//line views/terminal.qtpl:1111
 	StreamNavScript(qw422016, p.SessionID(), p.ShortcutsJSON(), "terminal")
-//line views/terminal.qtpl:1078
+//line views/terminal.qtpl:1111
 	qw422016.N().S(`
 <script src="https://cdn.jsdelivr.net/npm/xterm@5.3.0/lib/xterm.min.js"></script>
 <script src="https://cdn.jsdelivr.net/npm/xterm-addon-fit@0.8.0/lib/xterm-addon-fit.min.js"></script>
@@ -1500,69 +1533,74 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 <script src="/static/js/shortcut_help.js"></script>
 <script>
     const initialSession = '`)
-//line views/terminal.qtpl:1085
+//line views/terminal.qtpl:1118
 	qw422016.E().S(JSAttr(p.Session))
-//line views/terminal.qtpl:1085
+//line views/terminal.qtpl:1118
 	qw422016.N().S(`';
     const initialWindow = '`)
-//line views/terminal.qtpl:1086
+//line views/terminal.qtpl:1119
 	qw422016.E().S(JSAttr(p.Window))
-//line views/terminal.qtpl:1086
+//line views/terminal.qtpl:1119
 	qw422016.N().S(`';
     const initialIsRemote = `)
-//line views/terminal.qtpl:1087
+//line views/terminal.qtpl:1120
 	qw422016.E().V(p.IsRemote)
-//line views/terminal.qtpl:1087
+//line views/terminal.qtpl:1120
 	qw422016.N().S(`;
     const initialViewType = '`)
-//line views/terminal.qtpl:1088
+//line views/terminal.qtpl:1121
 	qw422016.E().S(JSAttr(p.ViewType))
-//line views/terminal.qtpl:1088
+//line views/terminal.qtpl:1121
 	qw422016.N().S(`';
     const initialServiceName = '`)
-//line views/terminal.qtpl:1089
+//line views/terminal.qtpl:1122
 	qw422016.E().S(JSAttr(p.ServiceName))
-//line views/terminal.qtpl:1089
+//line views/terminal.qtpl:1122
+	qw422016.N().S(`';
+    const initialServiceWorktree = '`)
+//line views/terminal.qtpl:1123
+	qw422016.E().S(JSAttr(p.ServiceWorktree))
+//line views/terminal.qtpl:1123
 	qw422016.N().S(`';
     const initialLogViewerName = '`)
-//line views/terminal.qtpl:1090
+//line views/terminal.qtpl:1124
 	qw422016.E().S(JSAttr(p.LogViewerName))
-//line views/terminal.qtpl:1090
+//line views/terminal.qtpl:1124
 	qw422016.N().S(`';
     const initialWorktree = '`)
-//line views/terminal.qtpl:1091
+//line views/terminal.qtpl:1125
 	qw422016.E().S(JSAttr(p.WorktreeName))
-//line views/terminal.qtpl:1091
+//line views/terminal.qtpl:1125
 	qw422016.N().S(`';
     const projectName = '`)
-//line views/terminal.qtpl:1092
+//line views/terminal.qtpl:1126
 	qw422016.E().S(JSAttr(p.ProjectName))
-//line views/terminal.qtpl:1092
+//line views/terminal.qtpl:1126
 	qw422016.N().S(`';
     const customShortcuts = `)
-//line views/terminal.qtpl:1093
+//line views/terminal.qtpl:1127
 	p.StreamShortcutsJSON(qw422016)
-//line views/terminal.qtpl:1093
+//line views/terminal.qtpl:1127
 	qw422016.N().S(`;
     const notificationSettings = `)
-//line views/terminal.qtpl:1094
+//line views/terminal.qtpl:1128
 	p.StreamNotificationsJSON(qw422016)
-//line views/terminal.qtpl:1094
+//line views/terminal.qtpl:1128
 	qw422016.N().S(`;
     const initialServices = `)
-//line views/terminal.qtpl:1095
+//line views/terminal.qtpl:1129
 	p.StreamServicesJSON(qw422016)
-//line views/terminal.qtpl:1095
+//line views/terminal.qtpl:1129
 	qw422016.N().S(`;
     const initialLinks = `)
-//line views/terminal.qtpl:1096
+//line views/terminal.qtpl:1130
 	p.StreamLinksJSON(qw422016)
-//line views/terminal.qtpl:1096
+//line views/terminal.qtpl:1130
 	qw422016.N().S(`;
     const initialLogViewers = `)
-//line views/terminal.qtpl:1097
+//line views/terminal.qtpl:1131
 	p.StreamLogViewersJSON(qw422016)
-//line views/terminal.qtpl:1097
+//line views/terminal.qtpl:1131
 	qw422016.N().S(`;
 
     // Map of terminalKey -> {term, fitAddon, ws, container, isRemote}
@@ -1581,9 +1619,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
     // Clear history if server was restarted (session ID changed)
     const currentSessionID = '`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.E().S(JSAttr(p.SessionID()))
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`';
     const storedSessionID = sessionStorage.getItem('trellis-session-id');
     if (storedSessionID !== currentSessionID) {
@@ -1662,7 +1700,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             // Push current terminal URL to history before navigating away
             // This allows cmd-backspace to return to this terminal from the page
             if (currentTerminalKey) {
-                const url = window.location.pathname;
+                const url = window.location.pathname + window.location.search;
                 pushToScreenHistory(url);
             }
             // Navigate to page URL
@@ -1674,7 +1712,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             return;
         } else if (isService) {
             const serviceName = selectedOption.dataset.serviceName;
-            showService(serviceName);
+            showService(serviceName, selectedOption.dataset.serviceWorktree);
         } else if (isLogViewer) {
             const logViewerName = selectedOption.dataset.logViewerName;
             showLogViewer(logViewerName);
@@ -1741,10 +1779,12 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
     // Service log state
     let currentServiceName = null;
+    let currentServiceWorktree = ''; // Worktree running the service alongside the active one ('' = active)
     let serviceLogPollTimer = null;
     let serviceLogFetching = false;   // Guard against overlapping log fetches
     let serviceStatusFetching = false; // Guard against overlapping status fetches
     let lastLogLength = 0;
+    let lastServiceLogRaw = '';         // Raw text of the newest parsed entry
     // Structured service log state (when parser is configured)
     let serviceLogConfig = null;        // Current service's logging config
     let serviceLogEntries = [];         // All parsed entries
@@ -1790,6 +1830,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     let logViewerAutoPaused = false;    // Following was auto-paused because the volume crossed the threshold
     let logViewerAutoPauseRate = 30;    // Lines/sec above which following auto-pauses (0 = disabled; from config)
     let logViewerRateWindow = [];       // Recent [ms, count] samples of rendered entries, for auto-pause detection
+    let logViewerHistogramVisible = false; // Histogram of entry counts shown above the log
+    let logViewerHistogramTimer = null;    // Periodic histogram refresh while visible
+    let logViewerHistogramRequest = 0;     // Incremented per fetch so stale responses are dropped
 
     // Browser notifications
     let notificationsEnabled = false;
@@ -1892,6 +1935,14 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             const payload = event.payload || {};
             title = 'Trellis: Trace Failed';
             body = 'Trace "' + (payload.name || 'unknown') + '" failed: ' + (payload.error || 'unknown error');
+        } else if (eventType === 'log.alert') {
+            const payload = event.payload || {};
+            if (notificationSettings.failures_only && payload.state !== 'firing') return;
+            title = payload.state === 'firing' ? 'Trellis: Alert ' + (payload.rule || 'unknown') : 'Trellis: Resolved ' + (payload.rule || 'unknown');
+            body = payload.message || '';
+            if (payload.state === 'firing' && payload.sample) {
+                body += '\n' + payload.sample;
+            }
         } else if (eventType === 'notify.done') {
             const payload = event.payload || {};
             title = 'Trellis: Done';
@@ -2107,11 +2158,12 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                 if (initialServices && initialServices.length > 0) {
                     for (const svc of initialServices) {
                         const opt = document.createElement('option');
-                        opt.value = '/terminal/service/' + svc.name;
+                        opt.value = servicePageURL(svc.name, svc.worktree);
                         opt.dataset.isService = true;
                         opt.dataset.serviceName = svc.name;
+                        opt.dataset.serviceWorktree = svc.worktree || '';
                         opt.dataset.serviceStatus = svc.status;
-                        opt.textContent = '#' + svc.name + ' - service';
+                        opt.textContent = serviceKey(svc.name, svc.worktree) + ' - service';
                         select.appendChild(opt);
                     }
                 }
@@ -2191,6 +2243,17 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         if (!serviceName) return;
 
+        // Events from the active worktree's services carry its name; match
+        // them to the options of services without a worktree
+        const select = document.getElementById('navSelect');
+        const backgroundWorktrees = new Set();
+        for (const opt of select.options) {
+            if (opt.dataset.serviceWorktree) {
+                backgroundWorktrees.add(opt.dataset.serviceWorktree);
+            }
+        }
+        const serviceWorktree = backgroundWorktrees.has(event.worktree) ? event.worktree : '';
+
         let newStatus = null;
         if (eventType === 'service.started' || eventType === 'service.restarted') {
             newStatus = 'running';
@@ -2202,9 +2265,8 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         if (newStatus) {
             // Update the picker option
-            const select = document.getElementById('navSelect');
             for (const opt of select.options) {
-                if (opt.dataset.serviceName === serviceName) {
+                if (opt.dataset.serviceName === serviceName && (opt.dataset.serviceWorktree || '') === serviceWorktree) {
                     opt.dataset.serviceStatus = newStatus;
                     break;
                 }
@@ -2224,7 +2286,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             refreshingPicker = false;
 
             // Also update the service wrapper if currently viewing this service
-            if (currentServiceName === serviceName) {
+            if (currentServiceName === serviceName && currentServiceWorktree === serviceWorktree) {
                 const statusEl = document.getElementById('service-status');
                 if (statusEl) {
                     statusEl.textContent = newStatus;
@@ -2551,6 +2613,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             serviceLogPollTimer = null;
         }
         currentServiceName = null;
+        currentServiceWorktree = '';
         currentLinkUrl = null; // Clear link state when switching terminals
         wasInCodeWrapper = false; // Clear code view state when switching terminals
 
@@ -2592,7 +2655,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         // Save to navigation history (use URL, not terminal key)
         if (currentTerminalKey && currentTerminalKey !== terminalKey) {
-            pushToScreenHistory(window.location.pathname);
+            pushToScreenHistory(window.location.pathname + window.location.search);
         }
 
         // Hide current terminal
@@ -2820,11 +2883,12 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             if (initialServices && initialServices.length > 0) {
                 for (const svc of initialServices) {
                     const opt = document.createElement('option');
-                    opt.value = '/terminal/service/' + svc.name;
+                    opt.value = servicePageURL(svc.name, svc.worktree);
                     opt.dataset.isService = true;
                     opt.dataset.serviceName = svc.name;
+                    opt.dataset.serviceWorktree = svc.worktree || '';
                     opt.dataset.serviceStatus = svc.status;
-                    opt.textContent = '#' + svc.name + ' - service';
+                    opt.textContent = serviceKey(svc.name, svc.worktree) + ' - service';
                     select.appendChild(opt);
                 }
             }
@@ -2892,7 +2956,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                 setTimeout(() => {
                     // Initialize based on view type
                     if (initialViewType === 'service' && initialServiceName) {
-                        showService(initialServiceName);
+                        showService(initialServiceName, initialServiceWorktree);
                     } else if (initialViewType === 'logviewer' && initialLogViewerName) {
                         var backBtn = document.getElementById('logviewer-back-btn');
                         if (backBtn) backBtn.style.display = 'none';
@@ -2917,7 +2981,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     window.addEventListener('popstate', (event) => {
         if (event.state) {
             if (event.state.type === 'service' && event.state.service) {
-                showService(event.state.service);
+                showService(event.state.service, event.state.worktree);
             } else if (event.state.type === 'logviewer' && event.state.name) {
                 showLogViewer(event.state.name);
             } else if (event.state.type === 'editor' && event.state.worktree) {
@@ -3222,10 +3286,9 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                     $('#navSelect').val(windowSpec).trigger('change.select2');
                     return true;
                 } else if (windowSpec.startsWith('#')) {
-                    // Service shortcut: #name
-                    const serviceName = windowSpec.substring(1);
-                    showService(serviceName);
-                    $('#navSelect').val(windowSpec).trigger('change.select2');
+                    // Service shortcut: #name or #name@worktree
+                    const svc = parseServiceKey(windowSpec);
+                    showService(svc.name, svc.worktree);
                     return true;
                 } else if (windowSpec.startsWith('@')) {
                     // Local terminal shortcut: @worktree - window
@@ -3333,6 +3396,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             serviceLogPollTimer = null;
         }
         currentServiceName = null;
+        currentServiceWorktree = '';
 
         // Close log viewer websocket if active
         closeLogViewerWs();
@@ -3376,8 +3440,8 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
             // If previous view was a service, go back to it
             if (restoredKey.startsWith('#')) {
-                const serviceName = restoredKey.substring(1);
-                showService(serviceName);
+                const svc = parseServiceKey(restoredKey);
+                showService(svc.name, svc.worktree);
                 return;
             }
 
@@ -3443,7 +3507,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         // Save to navigation history (use URL) before switching
         const editorKey = 'editor:' + currentWorktree;
         if (currentTerminalKey && currentTerminalKey !== editorKey) {
-            pushToScreenHistory(window.location.pathname);
+            pushToScreenHistory(window.location.pathname + window.location.search);
             // Save the terminal key so we can restore it when exiting editor
             terminalKeyBeforeEditor = currentTerminalKey;
         }
@@ -3650,7 +3714,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         // Save to navigation history before switching to output (use URL)
         if (currentTerminalKey && !currentTerminalKey.startsWith('output:')) {
-            pushToScreenHistory(window.location.pathname);
+            pushToScreenHistory(window.location.pathname + window.location.search);
         }
 
         document.getElementById('terminal-wrapper').style.display = 'none';
@@ -3693,7 +3757,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         // Save to navigation history before switching to output (use URL)
         if (currentTerminalKey && !currentTerminalKey.startsWith('output:')) {
-            pushToScreenHistory(window.location.pathname);
+            pushToScreenHistory(window.location.pathname + window.location.search);
         }
 
         // Hide current terminal container
@@ -4098,13 +4162,13 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         // Once the server has sent a terminal message (done/error) we stop
         // treating subsequent socket events as failures. iOS Safari fires
         // onerror when the socket is closed right after a normal `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`done`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`
         // — desktop browsers don't — which used to surface as a spurious
         // "WebSocket error" appended after a successful "✓ SUCCESS" render.
@@ -4177,7 +4241,18 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         const title = workflowId + ' - ' + result;
 
         // Use server-formatted HTML output if available, otherwise fall back to plain text
-        const header = result + ' (took ' + duration + ')<br><br>';
+        let header = result + ' (took ' + duration + ')';
+        if (status.CachedFrom) {
+            header += ', restored from ' + status.CachedFrom;
+        }
+        if (status.Coverage && status.Coverage.Total > 0) {
+            header += ', coverage ' + (100 * status.Coverage.Covered / status.Coverage.Total).toFixed(1) + '%';
+        }
+        if (status.Artifacts && status.Artifacts.length > 0) {
+            header += ', <a href="/workflows/' + encodeURIComponent(status.WorkflowID) + '/history">' +
+                status.Artifacts.length + ' artifact' + (status.Artifacts.length === 1 ? '' : 's') + '</a>';
+        }
+        header += '<br><br>';
         let outputHtml;
         if (status.OutputHTML) {
             outputHtml = header + status.OutputHTML;
@@ -4244,10 +4319,42 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     }
 
     // Service log functions
-    function showService(serviceName) {
+
+    // serviceKey returns the picker key of a service: #name for the active
+    // worktree's services, #name@worktree for another worktree's.
+    function serviceKey(serviceName, worktree) {
+        return '#' + serviceName + (worktree ? '@' + worktree : '');
+    }
+
+    function parseServiceKey(key) {
+        const spec = key.substring(1);
+        const at = spec.indexOf('@');
+        if (at < 0) {
+            return { name: spec, worktree: '' };
+        }
+        return { name: spec.substring(0, at), worktree: spec.substring(at + 1) };
+    }
+
+    function servicePageURL(serviceName, worktree) {
+        return '/terminal/service/' + encodeURIComponent(serviceName) +
+            (worktree ? '?worktree=' + encodeURIComponent(worktree) : '');
+    }
+
+    // serviceAPIURL returns the API URL of the current service plus suffix,
+    // addressing the worktree running it.
+    function serviceAPIURL(suffix) {
+        let url = '/api/v1/services/' + encodeURIComponent(currentServiceName) + (suffix || '');
+        if (currentServiceWorktree) {
+            url += (url.includes('?') ? '&' : '?') + 'worktree=' + encodeURIComponent(currentServiceWorktree);
+        }
+        return url;
+    }
+
+    function showService(serviceName, worktree) {
+        worktree = worktree || '';
         // Save to navigation history (use URL)
-        if (currentTerminalKey && currentTerminalKey !== '#' + serviceName) {
-            pushToScreenHistory(window.location.pathname);
+        if (currentTerminalKey && currentTerminalKey !== serviceKey(serviceName, worktree)) {
+            pushToScreenHistory(window.location.pathname + window.location.search);
         }
 
         // Hide current terminal container
@@ -4283,13 +4390,15 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         }
 
         currentServiceName = serviceName;
-        currentTerminalKey = '#' + serviceName;
+        currentServiceWorktree = worktree;
+        currentTerminalKey = serviceKey(serviceName, worktree);
 
-        document.getElementById('service-name').textContent = serviceName;
-        document.title = 'Service: ' + serviceName;
+        const displayName = worktree ? serviceName + '@' + worktree : serviceName;
+        document.getElementById('service-name').textContent = displayName;
+        document.title = 'Service: ' + displayName;
 
         // Look up service config for structured logging
-        serviceLogConfig = initialServices.find(s => s.name === serviceName);
+        serviceLogConfig = initialServices.find(s => s.name === serviceName && (s.worktree || '') === worktree);
         const isStructured = serviceLogConfig && serviceLogConfig.parser_type;
 
         if (isStructured) {
@@ -4376,8 +4485,8 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         }, 1000);
 
         // Update URL without navigating (for bookmarking/refresh)
-        const serviceUrl = '/terminal/service/' + encodeURIComponent(serviceName);
-        history.pushState({ type: 'service', service: serviceName }, '', serviceUrl);
+        const serviceUrl = servicePageURL(serviceName, worktree);
+        history.pushState({ type: 'service', service: serviceName, worktree: worktree }, '', serviceUrl);
 
         // Update picker to match (use URL)
         $('#navSelect').val(serviceUrl).trigger('change.select2');
@@ -4387,7 +4496,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         if (!currentServiceName || serviceStatusFetching) return;
         serviceStatusFetching = true;
 
-        fetch('/api/v1/services/' + encodeURIComponent(currentServiceName))
+        fetch(serviceAPIURL())
             .then(r => r.json())
             .then(data => {
                 if (data.data) {
@@ -4420,7 +4529,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         if (!currentServiceName || serviceLogFetching) return;
         serviceLogFetching = true;
 
-        fetch('/api/v1/services/' + encodeURIComponent(currentServiceName) + '/logs?lines=1000')
+        fetch(serviceAPIURL('/logs?lines=1000'))
             .then(r => r.json())
             .then(data => {
                 // API returns either:
@@ -4437,8 +4546,11 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
                     if (entries && entries.length > 0) {
                         // Server-parsed entries - use directly
                         const newLineCount = entries.length;
+                        // Multiline folding grows the last entry without adding one
+                        const lastRaw = entries[entries.length - 1].raw || '';
 
-                        if (newLineCount !== lastLogLength) {
+                        if (newLineCount !== lastLogLength || lastRaw !== lastServiceLogRaw) {
+                            lastServiceLogRaw = lastRaw;
                             // Convert server entries to frontend format
                             serviceLogEntries = entries.map(e => ({
                                 timestamp: e.timestamp,
@@ -4799,7 +4911,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     function stopService() {
         if (!currentServiceName) return;
 
-        fetch('/api/v1/services/' + encodeURIComponent(currentServiceName) + '/stop', { method: 'POST' })
+        fetch(serviceAPIURL('/stop'), { method: 'POST' })
             .then(r => r.json())
             .then(data => {
                 if (data.error) {
@@ -4813,7 +4925,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     function restartService() {
         if (!currentServiceName) return;
 
-        fetch('/api/v1/services/' + encodeURIComponent(currentServiceName) + '/restart', { method: 'POST' })
+        fetch(serviceAPIURL('/restart'), { method: 'POST' })
             .then(r => r.json())
             .then(data => {
                 if (data.error) {
@@ -4827,7 +4939,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
     function clearServiceLog() {
         if (!currentServiceName) return;
 
-        fetch('/api/v1/services/' + encodeURIComponent(currentServiceName) + '/logs', { method: 'DELETE' })
+        fetch(serviceAPIURL('/logs'), { method: 'DELETE' })
             .then(r => r.json())
             .then(data => {
                 if (data.error) {
@@ -4856,7 +4968,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
         // Save to navigation history (use URL)
         if (currentTerminalKey && currentTerminalKey !== '~' + logViewerName) {
-            pushToScreenHistory(window.location.pathname);
+            pushToScreenHistory(window.location.pathname + window.location.search);
         }
 
         // Hide current terminal container
@@ -4870,6 +4982,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             serviceLogPollTimer = null;
         }
         currentServiceName = null;
+        currentServiceWorktree = '';
 
         // Close existing log viewer websocket if different log viewer
         if (logViewerWs && currentLogViewerName !== logViewerName) {
@@ -5000,6 +5113,11 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         document.getElementById('logviewer-expanded').style.display = 'none';
         updateLogViewerModeUI();
 
+        // Restore histogram visibility and load it for this viewer
+        logViewerHistogramVisible = localStorage.getItem('trellis-logviewer-histogram') === '1';
+        document.getElementById('logviewer-histogram').innerHTML = '';
+        updateLogViewerHistogramUI();
+
         // Set up scroll listener for pause detection
         const logEl = document.getElementById('logviewer-log');
         logEl.onscroll = handleLogViewerScroll;
@@ -5692,6 +5810,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         // Refilter entries
         logViewerFilteredEntries = logViewerEntries.filter(e => matchesLogViewerFilter(e, logViewerFilter));
         rerenderLogViewer();
+        refreshLogViewerHistogram();
 
         // If filter changed, also update WebSocket filter
         if (logViewerWs && currentLogViewerName) {
@@ -5707,6 +5826,7 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         logViewerFilter = '';
         logViewerFilteredEntries = [...logViewerEntries];
         rerenderLogViewer();
+        refreshLogViewerHistogram();
 
         if (logViewerWs) {
             logViewerWs.send(JSON.stringify({
@@ -5738,6 +5858,129 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         }
     }
 
+    function toggleLogViewerHistogram() {
+        logViewerHistogramVisible = !logViewerHistogramVisible;
+        localStorage.setItem('trellis-logviewer-histogram', logViewerHistogramVisible ? '1' : '0');
+        updateLogViewerHistogramUI();
+    }
+
+    function updateLogViewerHistogramUI() {
+        document.getElementById('logviewer-histogram').style.display = logViewerHistogramVisible ? 'flex' : 'none';
+        document.getElementById('logviewer-histogram-btn').classList.toggle('active', logViewerHistogramVisible);
+
+        if (logViewerHistogramTimer) {
+            clearInterval(logViewerHistogramTimer);
+            logViewerHistogramTimer = null;
+        }
+        if (logViewerHistogramVisible && currentLogViewerName) {
+            refreshLogViewerHistogram();
+            logViewerHistogramTimer = setInterval(refreshLogViewerHistogram, 10000);
+        }
+    }
+
+    // refreshLogViewerHistogram fetches time-bucketed counts for the viewer's
+    // buffer, using the current filter, from the stats API.
+    async function refreshLogViewerHistogram() {
+        if (!logViewerHistogramVisible || !currentLogViewerName) return;
+
+        // Explore viewers don't tail until "Go live"; the stats request
+        // would start the tail behind the user's back.
+        if (logViewerExplore && !logViewerWentLive) {
+            renderLogViewerHistogram(null, 'Histogram is available once the viewer is live');
+            return;
+        }
+
+        const name = currentLogViewerName;
+        const request = ++logViewerHistogramRequest;
+        let url = `)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(`/api/v1/logs/${encodeURIComponent(name)}/stats?bucket=auto`)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(`;
+        if (logViewerFilter) {
+            url += '&filter=' + encodeURIComponent(logViewerFilter);
+        }
+
+        try {
+            const resp = await fetch(url);
+            const body = await resp.json();
+            if (request !== logViewerHistogramRequest || name !== currentLogViewerName) return;
+            if (!resp.ok) {
+                renderLogViewerHistogram(null, body.error ? body.error.message : 'Failed to load histogram');
+                return;
+            }
+            renderLogViewerHistogram(body.data);
+        } catch (err) {
+            if (request !== logViewerHistogramRequest) return;
+            renderLogViewerHistogram(null, 'Failed to load histogram');
+        }
+    }
+
+    function renderLogViewerHistogram(stats, message) {
+        const el = document.getElementById('logviewer-histogram');
+        el.innerHTML = '';
+
+        const buckets = stats && stats.histogram ? stats.histogram : [];
+        if (!stats || buckets.length === 0) {
+            const msg = document.createElement('span');
+            msg.className = 'logviewer-histogram-message';
+            msg.textContent = message || 'No entries';
+            el.appendChild(msg);
+            return;
+        }
+
+        const max = Math.max(1, ...buckets.map(b => b.count));
+        const fragment = document.createDocumentFragment();
+        for (const b of buckets) {
+            const levels = b.levels || {};
+            const errors = (levels.error || 0) + (levels.fatal || 0);
+            const warns = levels.warn || 0;
+            const other = b.count - errors - warns;
+
+            const bar = document.createElement('div');
+            bar.className = 'logviewer-histogram-bar';
+            bar.title = `)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(`${new Date(b.start).toLocaleString()} (${stats.bucket}): ${b.count} entries`)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(` +
+                (errors ? `)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(`, ${errors} errors`)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(` : '') + (warns ? `)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(`, ${warns} warnings`)
+//line views/terminal.qtpl:1148
+	qw422016.N().S("`")
+//line views/terminal.qtpl:1148
+	qw422016.N().S(` : '');
+            for (const [cls, count] of [['seg-error', errors], ['seg-warn', warns], ['seg-other', other]]) {
+                if (count <= 0) continue;
+                const seg = document.createElement('div');
+                seg.className = cls;
+                seg.style.height = (count / max * 100) + '%';
+                bar.appendChild(seg);
+            }
+            fragment.appendChild(bar);
+        }
+        el.appendChild(fragment);
+    }
+
     function toggleLogViewerTimestamp() {
         logViewerTimestampAbsolute = !logViewerTimestampAbsolute;
 
@@ -5873,6 +6116,10 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             clearInterval(logViewerRelativeTimeInterval);
             logViewerRelativeTimeInterval = null;
         }
+        if (logViewerHistogramTimer) {
+            clearInterval(logViewerHistogramTimer);
+            logViewerHistogramTimer = null;
+        }
         currentLogViewerName = null;
         logViewerEntries = [];
         logViewerFilteredEntries = [];
@@ -5990,13 +6237,13 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         }
 
         throw new Error(`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`Invalid time format: ${input}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`);
     }
 
@@ -6032,63 +6279,63 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
         try {
             // Build query URL
             let url = `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`/api/v1/logs/${encodeURIComponent(currentLogViewerName)}/history`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             url += `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`?start=${encodeURIComponent(startTime)}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             url += `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`&end=${encodeURIComponent(endTime)}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             if (grep) {
                 url += `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`&grep=${encodeURIComponent(grep)}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             }
             if (before > 0) {
                 url += `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`&before=${before}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             }
             if (after > 0) {
                 url += `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`&after=${after}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             }
 
@@ -6096,13 +6343,13 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             if (!response.ok) {
                 const text = await response.text();
                 throw new Error(text || `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`HTTP ${response.status}`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`);
             }
 
@@ -6139,13 +6386,13 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
             // Update connection status
             const statusEl = document.getElementById('logviewer-status');
             statusEl.textContent = `)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`${data.entries?.length || 0} results`)
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S("`")
-//line views/terminal.qtpl:1114
+//line views/terminal.qtpl:1148
 	qw422016.N().S(`;
             statusEl.className = 'logviewer-connection-status text-info';
 
@@ -6186,36 +6433,36 @@ func (p *TerminalWindowPage) StreamRender(qw422016 *qt422016.Writer) {
 
 <script src="/static/js/inbox_main_ws.js"></script>
 `)
-//line views/terminal.qtpl:5635
+//line views/terminal.qtpl:5846
 	p.StreamFooter(qw422016)
-//line views/terminal.qtpl:5635
+//line views/terminal.qtpl:5846
 	qw422016.N().S(`
 `)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 }
 
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 func (p *TerminalWindowPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	p.StreamRender(qw422016)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	qt422016.ReleaseWriter(qw422016)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 }
 
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 func (p *TerminalWindowPage) Render() string {
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	p.WriteRender(qb422016)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	qs422016 := string(qb422016.B)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 	return qs422016
-//line views/terminal.qtpl:5636
+//line views/terminal.qtpl:5847
 }
@@ -42,19 +42,23 @@ func (p *TraceReportPage) EntriesJSON() string {
 	return string(data)
 }
 
+func (p *TraceReportPage) Waterfall() []trace.WaterfallSpan {
+	return trace.BuildWaterfall(p.Report.Entries)
+}
+
 func (p *TraceReportPage) LogViewersJSON() string {
 	data, _ := json.Marshal(p.LogViewers)
 	return string(data)
 }
 
-//line views/trace_report.qtpl:26
+//line views/trace_report.qtpl:30
 func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
-//line views/trace_report.qtpl:26
+//line views/trace_report.qtpl:30
 	qw422016.N().S(`
 `)
-//line views/trace_report.qtpl:27
+//line views/trace_report.qtpl:31
 	p.StreamHeader(qw422016)
-//line views/trace_report.qtpl:27
+//line views/trace_report.qtpl:31
 	qw422016.N().S(`
 <!-- Uses shared CSS from /static/css/logviewer.css -->
 
@@ -62,18 +66,18 @@ func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
     <ol class="breadcrumb">
         <li class="breadcrumb-item"><a href="/trace">Distributed Trace</a></li>
         <li class="breadcrumb-item active" aria-current="page">`)
-//line views/trace_report.qtpl:33
+//line views/trace_report.qtpl:37
 	qw422016.E().S(p.Report.Name)
-//line views/trace_report.qtpl:33
+//line views/trace_report.qtpl:37
 	qw422016.N().S(`</li>
     </ol>
 </nav>
 
 <h2 class="mb-4">
     <i class="fa-solid fa-file-lines"></i> `)
-//line views/trace_report.qtpl:38
+//line views/trace_report.qtpl:42
 	qw422016.E().S(p.Report.Name)
-//line views/trace_report.qtpl:38
+//line views/trace_report.qtpl:42
 	qw422016.N().S(`
 </h2>
 
@@ -86,55 +90,55 @@ func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
             <tr>
                 <th>Trace ID</th>
                 <td><code>`)
-//line views/trace_report.qtpl:49
+//line views/trace_report.qtpl:53
 	qw422016.E().S(p.Report.TraceID)
-//line views/trace_report.qtpl:49
+//line views/trace_report.qtpl:53
 	qw422016.N().S(`</code></td>
             </tr>
             <tr>
                 <th>Group</th>
                 <td>`)
-//line views/trace_report.qtpl:53
+//line views/trace_report.qtpl:57
 	qw422016.E().S(p.Report.Group)
-//line views/trace_report.qtpl:53
+//line views/trace_report.qtpl:57
 	qw422016.N().S(`</td>
             </tr>
             <tr>
                 <th>Created</th>
                 <td class="created-time" data-time="`)
-//line views/trace_report.qtpl:57
+//line views/trace_report.qtpl:61
 	qw422016.E().S(p.Report.CreatedAt.Format(time.RFC3339))
-//line views/trace_report.qtpl:57
+//line views/trace_report.qtpl:61
 	qw422016.N().S(`"></td>
             </tr>
             <tr>
                 <th>Time Range</th>
                 <td class="time-range"
                     data-start="`)
-//line views/trace_report.qtpl:62
+//line views/trace_report.qtpl:66
 	qw422016.E().S(p.Report.TimeRange.Start.Format(time.RFC3339))
-//line views/trace_report.qtpl:62
+//line views/trace_report.qtpl:66
 	qw422016.N().S(`"
                     data-end="`)
-//line views/trace_report.qtpl:63
+//line views/trace_report.qtpl:67
 	qw422016.E().S(p.Report.TimeRange.End.Format(time.RFC3339))
-//line views/trace_report.qtpl:63
+//line views/trace_report.qtpl:67
 	qw422016.N().S(`"></td>
             </tr>
             <tr>
                 <th>Total Entries</th>
                 <td>`)
-//line views/trace_report.qtpl:67
+//line views/trace_report.qtpl:71
 	qw422016.N().D(p.Report.Summary.TotalEntries)
-//line views/trace_report.qtpl:67
+//line views/trace_report.qtpl:71
 	qw422016.N().S(`</td>
             </tr>
             <tr>
                 <th>Duration</th>
                 <td>`)
-//line views/trace_report.qtpl:71
+//line views/trace_report.qtpl:75
 	qw422016.N().D(int(p.Report.Summary.DurationMS))
-//line views/trace_report.qtpl:71
+//line views/trace_report.qtpl:75
 	qw422016.N().S(`ms</td>
             </tr>
         </table>
@@ -155,28 +159,149 @@ func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
     </div>
 </div>
 
+`)
+//line views/trace_report.qtpl:95
+	spans := p.Waterfall()
+
+//line views/trace_report.qtpl:95
+	qw422016.N().S(`
+`)
+//line views/trace_report.qtpl:96
+	if len(spans) > 0 {
+//line views/trace_report.qtpl:96
+		qw422016.N().S(`
+<!-- Span Waterfall -->
+<div class="card mb-3">
+    <div class="card-header">
+        <i class="fa-solid fa-bars-staggered"></i> Spans (`)
+//line views/trace_report.qtpl:100
+		qw422016.N().D(len(spans))
+//line views/trace_report.qtpl:100
+		qw422016.N().S(`)
+    </div>
+    <div class="card-body p-0">
+        <table class="table table-sm mb-0 trace-waterfall">
+            <tbody>
+            `)
+//line views/trace_report.qtpl:105
+		for _, span := range spans {
+//line views/trace_report.qtpl:105
+			qw422016.N().S(`
+            <tr title="`)
+//line views/trace_report.qtpl:106
+			qw422016.E().S(span.Service)
+//line views/trace_report.qtpl:106
+			qw422016.N().S(` `)
+//line views/trace_report.qtpl:106
+			qw422016.E().S(span.Kind)
+//line views/trace_report.qtpl:106
+			qw422016.N().S(` span `)
+//line views/trace_report.qtpl:106
+			qw422016.E().S(span.SpanID)
+//line views/trace_report.qtpl:106
+			qw422016.N().S(`">
+                <td class="text-nowrap" style="width: 35%; padding-left: `)
+//line views/trace_report.qtpl:107
+			qw422016.N().D(8 + span.Depth*16)
+//line views/trace_report.qtpl:107
+			qw422016.N().S(`px;">
+                    `)
+//line views/trace_report.qtpl:108
+			if span.HasProblem {
+//line views/trace_report.qtpl:108
+				qw422016.N().S(`<i class="fa-solid fa-circle-exclamation text-danger"></i>`)
+//line views/trace_report.qtpl:108
+			}
+//line views/trace_report.qtpl:108
+			qw422016.N().S(`
+                    `)
+//line views/trace_report.qtpl:109
+			if span.Service != "" {
+//line views/trace_report.qtpl:109
+				qw422016.N().S(`<span class="text-muted">`)
+//line views/trace_report.qtpl:109
+				qw422016.E().S(span.Service)
+//line views/trace_report.qtpl:109
+				qw422016.N().S(`</span>`)
+//line views/trace_report.qtpl:109
+			}
+//line views/trace_report.qtpl:109
+			qw422016.N().S(`
+                    `)
+//line views/trace_report.qtpl:110
+			qw422016.E().S(span.Name)
+//line views/trace_report.qtpl:110
+			qw422016.N().S(`
+                </td>
+                <td>
+                    <div style="position: relative; height: 14px;">
+                        <div class="`)
+//line views/trace_report.qtpl:114
+			if span.HasProblem {
+//line views/trace_report.qtpl:114
+				qw422016.N().S(`bg-danger`)
+//line views/trace_report.qtpl:114
+			} else {
+//line views/trace_report.qtpl:114
+				qw422016.N().S(`bg-primary`)
+//line views/trace_report.qtpl:114
+			}
+//line views/trace_report.qtpl:114
+			qw422016.N().S(`"
+                             style="position: absolute; top: 2px; height: 10px; min-width: 2px; border-radius: 2px; left: `)
+//line views/trace_report.qtpl:115
+			qw422016.N().FPrec(span.OffsetPct, 3)
+//line views/trace_report.qtpl:115
+			qw422016.N().S(`%; width: `)
+//line views/trace_report.qtpl:115
+			qw422016.N().FPrec(span.WidthPct, 3)
+//line views/trace_report.qtpl:115
+			qw422016.N().S(`%;"></div>
+                    </div>
+                </td>
+                <td class="text-nowrap text-end text-muted" style="width: 8em;">`)
+//line views/trace_report.qtpl:118
+			qw422016.E().S(span.Duration.String())
+//line views/trace_report.qtpl:118
+			qw422016.N().S(`</td>
+            </tr>
+            `)
+//line views/trace_report.qtpl:120
+		}
+//line views/trace_report.qtpl:120
+		qw422016.N().S(`
+            </tbody>
+        </table>
+    </div>
+</div>
+`)
+//line views/trace_report.qtpl:125
+	}
+//line views/trace_report.qtpl:125
+	qw422016.N().S(`
+
 <!-- Log Entries -->
 <div class="card mb-3">
     <div class="card-header d-flex justify-content-between align-items-center">
         <span><i class="fa-solid fa-list"></i> Log Entries (`)
-//line views/trace_report.qtpl:94
+//line views/trace_report.qtpl:130
 	qw422016.N().D(len(p.Report.Entries))
-//line views/trace_report.qtpl:94
+//line views/trace_report.qtpl:130
 	qw422016.N().S(`)</span>
     </div>
     <div class="card-body p-0">
         `)
-//line views/trace_report.qtpl:97
+//line views/trace_report.qtpl:133
 	if len(p.Report.Entries) == 0 {
-//line views/trace_report.qtpl:97
+//line views/trace_report.qtpl:133
 		qw422016.N().S(`
         <div class="alert alert-info m-3">
             <i class="fa-solid fa-info-circle"></i> No log entries found for this trace.
         </div>
         `)
-//line views/trace_report.qtpl:101
+//line views/trace_report.qtpl:137
 	} else {
-//line views/trace_report.qtpl:101
+//line views/trace_report.qtpl:137
 		qw422016.N().S(`
         <div class="trace-filter-bar">
             <i class="fa-solid fa-search text-muted"></i>
@@ -200,9 +325,9 @@ func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
             </div>
         </div>
         `)
-//line views/trace_report.qtpl:123
+//line views/trace_report.qtpl:159
 	}
-//line views/trace_report.qtpl:123
+//line views/trace_report.qtpl:159
 	qw422016.N().S(`
     </div>
 </div>
@@ -210,14 +335,14 @@ func (p *TraceReportPage) StreamRender(qw422016 *qt422016.Writer) {
 <script>
 // Uses shared functions from /static/js/logviewer.js
 var allEntries = `)
-//line views/trace_report.qtpl:129
+//line views/trace_report.qtpl:165
 	qw422016.N().S(p.EntriesJSON())
-//line views/trace_report.qtpl:129
+//line views/trace_report.qtpl:165
 	qw422016.N().S(`;
 var logViewers = `)
-//line views/trace_report.qtpl:130
+//line views/trace_report.qtpl:166
 	qw422016.N().S(p.LogViewersJSON())
-//line views/trace_report.qtpl:130
+//line views/trace_report.qtpl:166
 	qw422016.N().S(`;
 var filteredEntries = [];
 var selectedEntry = null;
@@ -353,9 +478,9 @@ function toggleTimestampFormat() {
 function deleteAndGoBack() {
     if (!confirm('Delete this trace report?')) return;
     fetch('/api/v1/trace/reports/`)
-//line views/trace_report.qtpl:264
+//line views/trace_report.qtpl:300
 	qw422016.E().S(p.Report.Name)
-//line views/trace_report.qtpl:264
+//line views/trace_report.qtpl:300
 	qw422016.N().S(`', { method: 'DELETE' })
         .then(function(r) { return r.json(); })
         .then(function(data) {
@@ -469,9 +594,9 @@ function formatTimeRange(startISO, endISO) {
 
 <script>
 var TRACE_REPORT_NAME = '`)
-//line views/trace_report.qtpl:376
+//line views/trace_report.qtpl:412
 	qw422016.E().S(JSAttr(p.Report.Name))
-//line views/trace_report.qtpl:376
+//line views/trace_report.qtpl:412
 	qw422016.N().S(`';
 
 function showSaveToCase() {
@@ -579,41 +704,41 @@ function doLinkTrace(worktree, caseId) {
 </script>
 
 `)
-//line views/trace_report.qtpl:482
+//line views/trace_report.qtpl:518
 	p.StreamFooter(qw422016)
-//line views/trace_report.qtpl:482
+//line views/trace_report.qtpl:518
 	qw422016.N().S(`
 `)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 }
 
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 func (p *TraceReportPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	p.StreamRender(qw422016)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	qt422016.ReleaseWriter(qw422016)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 }
 
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 func (p *TraceReportPage) Render() string {
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	p.WriteRender(qb422016)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	qs422016 := string(qb422016.B)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 	return qs422016
-//line views/trace_report.qtpl:483
+//line views/trace_report.qtpl:519
 }
 
-//line views/trace_report.qtpl:486
+//line views/trace_report.qtpl:522
 func levelBadgeClass(level string) string {
 	switch level {
 	case "ERROR", "error", "ERR":
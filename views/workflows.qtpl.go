@@ -153,25 +153,32 @@ func (p *WorkflowsPage) StreamRender(qw422016 *qt422016.Writer) {
 			qw422016.N().S(`')" title="Run workflow">
                                     <i class="fa-solid fa-play"></i>
                                 </button>
+                                <a class="btn btn-sm btn-outline-secondary" href="/workflows/`)
+//line views/workflows.qtpl:65
+			qw422016.E().S(wf.ID)
+//line views/workflows.qtpl:65
+			qw422016.N().S(`/history" title="Run history">
+                                    <i class="fa-solid fa-clock-rotate-left"></i>
+                                </a>
                             </td>
                         </tr>
                         `)
-//line views/workflows.qtpl:67
+//line views/workflows.qtpl:70
 		}
-//line views/workflows.qtpl:67
+//line views/workflows.qtpl:70
 		qw422016.N().S(`
                     </tbody>
                 </table>
                 `)
-//line views/workflows.qtpl:70
+//line views/workflows.qtpl:73
 	} else {
-//line views/workflows.qtpl:70
+//line views/workflows.qtpl:73
 		qw422016.N().S(`
                 <div class="p-3 text-muted">No workflows configured. Add workflows to your <code>trellis.hjson</code> config file.</div>
                 `)
-//line views/workflows.qtpl:72
+//line views/workflows.qtpl:75
 	}
-//line views/workflows.qtpl:72
+//line views/workflows.qtpl:75
 	qw422016.N().S(`
             </div>
         </div>
@@ -327,36 +334,36 @@ function pollStatus(runID, output) {
 </script>
 
 `)
-//line views/workflows.qtpl:226
+//line views/workflows.qtpl:229
 	p.StreamFooter(qw422016)
-//line views/workflows.qtpl:226
+//line views/workflows.qtpl:229
 	qw422016.N().S(`
 `)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 }
 
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 func (p *WorkflowsPage) WriteRender(qq422016 qtio422016.Writer) {
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	p.StreamRender(qw422016)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	qt422016.ReleaseWriter(qw422016)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 }
 
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 func (p *WorkflowsPage) Render() string {
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	qb422016 := qt422016.AcquireByteBuffer()
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	p.WriteRender(qb422016)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	qs422016 := string(qb422016.B)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	qt422016.ReleaseByteBuffer(qb422016)
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 	return qs422016
-//line views/workflows.qtpl:227
+//line views/workflows.qtpl:230
 }